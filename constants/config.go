@@ -6,10 +6,30 @@ import "time"
 const (
 	// Time-related constants.
 	DefaultTestDuration         = 2 * time.Minute
+	SmokeTestDuration           = time.Minute // Run length of --smoke, unless --duration is given
 	DefaultStatusReportInterval = 15 * time.Second
 	DefaultPeerScoreFreq        = 5 * time.Second
 	DefaultReportInterval       = 2 * time.Minute
 	DefaultLibp2pPeerscoreFreq  = 30 * time.Second
+	DefaultPublishTimeout       = 30 * time.Second
+	DefaultAlertTimeout         = 30 * time.Second
+	DefaultReachabilityTimeout  = 30 * time.Second
+	DefaultBeaconPeersTimeout   = 30 * time.Second
+	DefaultBeaconPeersInterval  = 10 * time.Minute
+	DefaultClockSkewTimeout     = 10 * time.Second
+	DefaultBeaconSyncTimeout    = 10 * time.Second
+	DefaultBeaconSyncInterval   = 30 * time.Second
+	DefaultHandshakeRetryWindow = 30 * time.Second
+	DefaultEventBucketWidth     = time.Minute
+	DefaultLateEventGrace       = 10 * time.Second
+	DefaultShutdownTimeout      = 10 * time.Second
+	DefaultCheckpointInterval   = time.Minute
+	DefaultExperimentPhase      = 30 * time.Minute
+	DefaultMaxPeersRampStep     = 30 * time.Minute
+	DefaultProbeTimeout         = 10 * time.Second
+	ReportProgressInterval      = 5 * time.Second
+	ShortSessionDuration        = 30 * time.Second
+	ConnectionDedupWindow       = 500 * time.Millisecond // CONNECTED events this close on an open session without a key are duplicates
 
 	// Network and connection constants.
 	DefaultPrysmHTTPPort   = 443
@@ -17,15 +37,164 @@ const (
 	DefaultMaxPeers        = 80
 	DefaultDialConcurrency = 16
 	DefaultDialTimeout     = 5 * time.Second
+	DefaultAgentVersion    = "hermes"
 
 	// PubSub and messaging constants.
 	DefaultPubSubLimit     = 200
 	DefaultPubSubQueueSize = 200
 
 	// File and data constants.
-	DefaultFilePermissions = 0644
-	ShortPeerIDLength      = 12
-	MaxDisconnectReasons   = 5
+	DefaultFilePermissions   = 0644
+	DefaultDirPermissions    = 0755
+	ShortPeerIDLength        = 12
+	MaxDisconnectReasons     = 5
+	DefaultShardSize         = 500
+	DefaultDataFileBudgetMB  = 64
+	DecodeErrorOffenderLimit = 10
+	ReqRespAbuserLimit       = 10
+	GossipLeechLimit         = 10
+	NegotiationRemoteLimit   = 10
+	UnknownAgentStringLimit  = 50
+	EventBurstLimit          = 20
+	AIReferencePeerLimit     = 25
+
+	// Unhandled trace event capture, bounded so a chatty new event type cannot grow the report.
+	UnhandledEventSamples     = 3
+	UnhandledEventTypeLimit   = 50
+	UnhandledEventSampleBytes = 2048
+
+	// Event burst detection, events of one type from one peer in one bucket.
+	DefaultEventBurstThreshold = 100
+
+	// Gossip leeches, message IDs a peer must request from us through IWANT, without delivering a
+	// single message, before it is flagged.
+	GossipLeechMinRequests = 10
+
+	// Detail sampling, the share of peers captured in full as a random baseline (1 captures every peer).
+	DefaultDetailSampleRate = 1.0
+
+	// Score percentile bands, the run is split into at most this many buckets of at least a minute.
+	ScoreBandBuckets = 60
+
+	// Gossipsub peer score thresholds Hermes configures. Below them a peer is no longer
+	// gossiped to, no longer published to, and finally has all its RPCs ignored.
+	GossipScoreThreshold   = -4000.0
+	PublishScoreThreshold  = -8000.0
+	GraylistScoreThreshold = -16000.0
+
+	// Our own MaxPeers pressure. The node is at capacity from this share of MaxPeers, and the
+	// report warns when sessions ended by our limit reach this share of disconnects.
+	DefaultCapacityRatio        = 0.95
+	PeerPressureDistortionShare = 0.1
+
+	// Topics whose invalid message deliveries span at least this many peers are reported as anomalies.
+	InvalidDeliveryMinPeers = 2
+
+	// Topic health, the most points a topic's mesh size over the run is reported in.
+	TopicHealthMeshPoints = 24
+
+	// Independent validation's beacon data fetches. Hermes refreshes its beacon state every 30s,
+	// a slower fetch leaves validation on a stale state. Latency percentiles cover the most recent
+	// samples per endpoint, and the fetches are unhealthy once this share of them failed.
+	BeaconFetchSlowThreshold    = 30 * time.Second
+	BeaconFetchLatencySamples   = 1024
+	BeaconFetchUnhealthyFailure = 0.5
+
+	// libp2p resource manager, how often its blocked resources are sampled, which bounds how
+	// closely a block is matched to the sessions ending around it, and the most peers listed.
+	ResourceSampleInterval = 10 * time.Second
+	ResourceLimitedPeers   = 20
+
+	// Recommended configuration: the share of completed sessions with fewer than two score
+	// snapshots from which a shorter snapshot interval is suggested, and the shortest one
+	// suggested; how long reaching capacity may take before more dial concurrency is
+	// suggested, and the most suggested; the share of a topic's subnets that must be unhealthy
+	// before subscribing to the others only is suggested.
+	RecommendSparseScoreShare   = 0.25
+	RecommendMinScoreInterval   = 10 * time.Second
+	RecommendCapacityDelay      = 10 * time.Minute
+	RecommendMaxDialConcurrency = 64
+	RecommendUnhealthySubnets   = 0.5
+
+	// Clock skew, the default offset from the beacon node's clock that is flagged (gossip's
+	// MAXIMUM_GOSSIP_CLOCK_DISPARITY), and the median drift of the peers' head slots from our
+	// current slot that is. Synced peers' heads trail the current slot by up to one slot.
+	DefaultClockSkewThreshold = 500 * time.Millisecond
+	ClockSkewPeerSlotsAhead   = 1.0
+	ClockSkewPeerSlotsBehind  = 2.0
+	ClockSkewMinPeers         = 3
+
+	// Delegated validation's beacon node, how far its head may trail the wall clock before it
+	// counts as not synced, and how long a failure keeps it degraded. Score snapshots within
+	// the grace are not attributed to the peers.
+	BeaconSyncMaxDistance = 4
+	BeaconSyncGrace       = time.Minute
+
+	// Canary peers, the user agent they announce followed by their behaviour, and how often
+	// they redial the primary host and publish what their behaviour calls for.
+	CanaryAgentPrefix = "hermes-peer-score-canary/"
+	CanaryInterval    = 12 * time.Second
+
+	// Event starvation, how long a run may go without any event before the node is considered wedged.
+	DefaultStarvationTimeout = 5 * time.Minute
+
+	// Logging, the most status intervals an unchanged status report line is held back for, and
+	// one in how many per-event log lines is written at info level.
+	StatusLogMaxBackoff = 8
+	DefaultLogSample    = 100
+
+	// Memory watchdog, how often resident memory is checked against --spill-rss-mb, and the
+	// name pattern of the temporary file completed sessions' events are spilled to.
+	MemoryWatchdogInterval = 10 * time.Second
+	SpillFilePattern       = "peer-score-spill-*.zst"
+
+	// Time slices, the width headline statistics are also computed per and the goodbye reasons kept per slice.
+	TimeSliceWidth       = 10 * time.Minute
+	TimeSliceReasonLimit = 3
+
+	// Run health, the share of out-of-order events above which the run's data quality is flagged.
+	HealthOutOfOrderRatio = 0.01
+
+	// Peers whose status reports the same head slot for this long are reported as stalled.
+	StatusStallThreshold = 10 * time.Minute
+
+	// Sessions where this many of our status requests failed before the peer said goodbye are
+	// flagged, repeated identify failures often point at an incompatibility.
+	IdentifyRepeatedFailures = 2
+
+	// Swimlane view, the peers drawn by default and the score fall between snapshots that is marked.
+	DefaultSwimlanePeers = 50
+	SwimlaneScoreDrop    = 10.0
+
+	// Head divergence, the slots a peer's head may trail ours by default, the window after an
+	// episode its score drops and goodbyes are attributed in, the score fall that counts and the
+	// episodes listed.
+	DefaultHeadDivergenceSlots = 32
+	HeadDivergenceFollow       = 5 * time.Minute
+	HeadDivergenceScoreDrop    = 10.0
+	HeadDivergenceEpisodeLimit = 50
+
+	// Lite report, the goodbye reasons kept and the size it must stay under for chat bots and CI comments.
+	LiteReportReasonLimit = 10
+	LiteReportMaxBytes    = 50 << 10
+
+	// Markdown run summary, the clients and worst scored peers listed.
+	MarkdownSummaryClientLimit = 10
+	MarkdownSummaryTopicLimit  = 10
+	WorstScoredPeerLimit       = 10
+
+	// Score feed shared with other monitoring nodes: how long a published snapshot counts, the
+	// largest snapshot a feed server accepts, the composite score below which a peer counts as
+	// bad and the peers listed in the consensus view.
+	ScoreFeedMaxAge       = 24 * time.Hour
+	ScoreFeedMaxBodyBytes = 32 << 20
+	ConsensusBadScore     = 0.0
+	ConsensusPeerLimit    = 50
+
+	// Gossipsub mesh degree Hermes runs with by default, the target and its low and high watermarks.
+	DefaultGossipD   = 8
+	DefaultGossipDlo = 6
+	DefaultGossipDhi = 12
 
 	// Default hosts and addresses.
 	DefaultDevp2pHost = "0.0.0.0"
@@ -48,8 +217,94 @@ const (
 	DefaultJSONReportFile = "peer-score-report.json"
 	DefaultHTMLReportFile = "peer-score-report.html"
 	DefaultDataJSFile     = "peer-score-report-data.js"
+	DefaultCheckpointFile = "peer-score-checkpoint.json"
+	DefaultExperimentDir  = "validation-experiment"
+	ExperimentResultFile  = "validation-experiment.json"
+	DefaultSweepDir       = "param-sweep"
+	SweepResultFile       = "param-sweep.json"
+	DefaultConfigFile     = "config.yaml"
+
+	DefaultHermesRegressionFile = "hermes-regression-report.html"
+	DefaultSwimlanesFile        = "peer-swimlanes.html"
+	DefaultLiteReportFile       = "peer-score-report-lite.json"
+	DefaultMarkdownSummaryFile  = "peer-score-summary.md"
+	DefaultGrafanaFile          = "grafana.json" // Not timestamped, so dashboards read the latest run at a fixed URL
+	DefaultOpenMetricsFile      = "peer-score-metrics.prom"
+	DefaultManifestFile         = "peer-score-manifest.json"
+	DefaultErrorJournalFile     = "peer-score-errors.ndjson"
+	DefaultPeerTraceFile        = "peer-score-trace.ndjson"
+
+	// PartialReportSuffix is appended to report files whose generation was cancelled part way.
+	PartialReportSuffix = ".partial"
+)
+
+// Report signing.
+const (
+	SignatureSuffix       = ".sig"           // Appended to an artifact for its ed25519 signature
+	SigstoreBundleSuffix  = ".sigstore.json" // Appended to an artifact for its Sigstore bundle
+	DefaultCosignBinary   = "cosign"
+	DefaultSigningTimeout = 2 * time.Minute // Keyless signing waits on the OIDC token and the transparency log
+
+	// GitHubActionsOIDCIssuer issues the identity tokens keyless signing uses in GitHub Actions.
+	GitHubActionsOIDCIssuer = "https://token.actions.githubusercontent.com"
+)
+
+// Report retention.
+const (
+	RemoteRemoveTimeout   = time.Minute // Each remote removal command, e.g. an object store CLI
+	RemotePathPlaceholder = "{}"        // Replaced with an artifact's path in remote removal commands
+)
+
+// Custom analyzers.
+const (
+	AnalyzerPluginSymbol   = "Analyze"       // Function a Go plugin analyzer exports
+	DefaultAnalyzerTimeout = 2 * time.Minute // Longest a single analyzer may run
+)
+
+// AI API defaults, OpenRouter unless an OpenAI-compatible gateway is configured.
+const (
+	DefaultAIBaseURL        = "https://openrouter.ai/api/v1"
+	DefaultAIRequestTimeout = 5 * time.Minute // Reasoning models take minutes over a full report
 )
 
+// AI analysis queue, shared by the report generations using the same lock directory.
+const (
+	DefaultAIConcurrency  = 1
+	DefaultAIQueueTimeout = 10 * time.Minute // Longest a run waits for a slot before deferring its analysis
+	AIQueuePollInterval   = 2 * time.Second
+)
+
+// Regression alerting defaults, as relative changes from the baseline run.
+const (
+	DefaultHandshakeRegressionThreshold   = 0.20
+	DefaultUniquePeersRegressionThreshold = 0.30
+	DefaultConnectionsRegressionThreshold = 0.30
+
+	// Hermes sensitive metrics, only compared when the baseline used a different Hermes version
+	DefaultMeshAdoptionRegressionThreshold     = 0.20
+	DefaultTimeToFirstScoreRegressionThreshold = 0.50
+	DefaultPruneRateRegressionThreshold        = 0.50
+
+	DefaultRegressionIssueLabel = "regression"
+	GitHubTokenEnv              = "GITHUB_TOKEN"
+)
+
+// Quickstart port suggestions, the first free port from the start is suggested.
+const (
+	QuickstartLibp2pPort   = 9000
+	QuickstartPortAttempts = 100
+)
+
+// EnvPrefix starts the environment variable of every flag, PEER_SCORE_PRYSM_HOST sets --prysm-host.
+const EnvPrefix = "PEER_SCORE_"
+
+// PrivateKeyEnv holds a hex-encoded libp2p private key, so separate runs can share one identity.
+const PrivateKeyEnv = "HERMES_PEER_SCORE_PRIVATE_KEY"
+
+// PruneHashSaltEnv holds the secret salt hashed report fields are keyed with, kept out of the
+// flags so it does not show in process listings.
+const PruneHashSaltEnv = "HERMES_PEER_SCORE_HASH_SALT"
+
 // Data stream types.
 const (
 	DefaultDataStreamType = "callback"
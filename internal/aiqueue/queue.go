@@ -0,0 +1,119 @@
+// Package aiqueue limits how many report generations call the AI API at once, across every
+// process sharing a lock directory, so CI runs finishing together do not hit rate limits.
+package aiqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// ErrTimeout is returned when no slot freed up within the wait timeout.
+var ErrTimeout = errors.New("timed out waiting for an AI analysis slot")
+
+// Queue hands out a fixed number of slots, each an exclusive lock on a file in the lock
+// directory (flock, or LockFileEx on Windows). The OS releases a slot when its process exits,
+// so a crashed run never holds one for good.
+type Queue struct {
+	dir   string
+	slots int
+	poll  time.Duration
+}
+
+// New creates a queue with slots concurrent AI requests over the lock files in dir.
+func New(dir string, slots int) (*Queue, error) {
+	if slots < 1 {
+		return nil, fmt.Errorf("AI concurrency must be at least 1, got %d", slots)
+	}
+
+	if err := os.MkdirAll(dir, constants.DefaultDirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create AI queue directory: %w", err)
+	}
+
+	return &Queue{dir: dir, slots: slots, poll: constants.AIQueuePollInterval}, nil
+}
+
+// Slot is a held AI request slot.
+type Slot struct {
+	file *os.File
+}
+
+// Acquire waits up to timeout for a free slot, returning ErrTimeout when none freed up in
+// time and the context's error when it was cancelled first.
+func (q *Queue) Acquire(ctx context.Context, timeout time.Duration) (*Slot, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		slot, err := q.tryAcquire()
+		if err != nil || slot != nil {
+			return slot, err
+		}
+
+		wait := min(q.poll, time.Until(deadline))
+		if wait <= 0 {
+			return nil, ErrTimeout
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// tryAcquire takes the first free slot, nil when all are held.
+func (q *Queue) tryAcquire() (*Slot, error) {
+	for i := 0; i < q.slots; i++ {
+		path := filepath.Join(q.dir, fmt.Sprintf("ai-slot-%d.lock", i))
+
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, constants.DefaultFilePermissions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open AI queue slot: %w", err)
+		}
+
+		locked, err := lockFile(file)
+		if err != nil {
+			file.Close()
+
+			return nil, fmt.Errorf("failed to lock AI queue slot: %w", err)
+		}
+
+		if !locked {
+			file.Close()
+
+			continue
+		}
+
+		// Note the holder, for whoever wonders which run is holding the slot
+		if err := file.Truncate(0); err == nil {
+			fmt.Fprintf(file, "pid %d since %s\n", os.Getpid(), time.Now().UTC().Format(time.RFC3339))
+		}
+
+		return &Slot{file: file}, nil
+	}
+
+	return nil, nil
+}
+
+// Release frees the slot for the next waiting run.
+func (s *Slot) Release() error {
+	if s == nil || s.file == nil {
+		return nil
+	}
+
+	defer func() { s.file = nil }()
+
+	if err := unlockFile(s.file); err != nil {
+		s.file.Close()
+
+		return fmt.Errorf("failed to unlock AI queue slot: %w", err)
+	}
+
+	return s.file.Close()
+}
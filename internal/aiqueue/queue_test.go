@@ -0,0 +1,80 @@
+package aiqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueueSlots(t *testing.T) {
+	queue, err := New(t.TempDir(), 2)
+	if err != nil {
+		t.Fatalf("Expected no error creating queue, got %v", err)
+	}
+
+	queue.poll = 10 * time.Millisecond
+	ctx := context.Background()
+
+	first, err := queue.Acquire(ctx, time.Second)
+	if err != nil {
+		t.Fatalf("Expected the first slot, got %v", err)
+	}
+
+	second, err := queue.Acquire(ctx, time.Second)
+	if err != nil {
+		t.Fatalf("Expected the second slot, got %v", err)
+	}
+
+	if _, err := queue.Acquire(ctx, 50*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Expected a timeout with both slots held, got %v", err)
+	}
+
+	// A slot released while waiting is handed to the waiting run
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = first.Release()
+	}()
+
+	third, err := queue.Acquire(ctx, time.Second)
+	if err != nil {
+		t.Fatalf("Expected the released slot, got %v", err)
+	}
+
+	for _, slot := range []*Slot{second, third} {
+		if err := slot.Release(); err != nil {
+			t.Errorf("Expected no error releasing a slot, got %v", err)
+		}
+	}
+
+	// Releasing twice is harmless
+	if err := third.Release(); err != nil {
+		t.Errorf("Expected no error releasing a slot twice, got %v", err)
+	}
+}
+
+func TestQueueCancelled(t *testing.T) {
+	queue, err := New(t.TempDir(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error creating queue, got %v", err)
+	}
+
+	held, err := queue.Acquire(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("Expected a slot, got %v", err)
+	}
+	defer held.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := queue.Acquire(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the wait to be cancelled, got %v", err)
+	}
+}
+
+func TestNewRejectsNoSlots(t *testing.T) {
+	if _, err := New(t.TempDir(), 0); err == nil {
+		t.Error("Expected an error for a queue without slots")
+	}
+}
@@ -0,0 +1,96 @@
+package alerting
+
+// metricCheck is a metric compared between the baseline and current run.
+type metricCheck struct {
+	metric    string
+	baseline  float64
+	current   float64
+	threshold float64
+}
+
+// worsening returns the relative change from the baseline in the direction that is worse.
+func (c metricCheck) worsening() float64 {
+	if HigherIsWorse(c.metric) {
+		return (c.current - c.baseline) / c.baseline
+	}
+
+	return (c.baseline - c.current) / c.baseline
+}
+
+// Compare compares a run against a baseline run and collects the metrics that regressed.
+// When the runs used different Hermes versions the Hermes sensitive metrics are compared too.
+// Runs in different validation modes are not compared at all, the modes alone move every
+// metric, so the comparison is only flagged.
+func Compare(baseline, current RunSummary, thresholds Thresholds) *Comparison {
+	comparison := &Comparison{
+		Baseline:    baseline,
+		Current:     current,
+		Regressions: make([]Regression, 0),
+	}
+
+	// Baselines saved before the mode was recorded are compared as before
+	if baseline.ValidationMode != "" && current.ValidationMode != "" && baseline.ValidationMode != current.ValidationMode {
+		comparison.ModeChanged = true
+
+		return comparison
+	}
+
+	checks := []metricCheck{
+		{metric: MetricHandshakeSuccessRate, baseline: baseline.HandshakeSuccessRate, current: current.HandshakeSuccessRate, threshold: thresholds.HandshakeSuccessDrop},
+		{metric: MetricUniquePeers, baseline: float64(baseline.UniquePeers), current: float64(current.UniquePeers), threshold: thresholds.UniquePeersDrop},
+		{metric: MetricTotalConnections, baseline: float64(baseline.TotalConnections), current: float64(current.TotalConnections), threshold: thresholds.ConnectionsDrop},
+	}
+
+	// Baselines saved before versions were recorded cannot tell a Hermes bump apart
+	comparison.HermesChanged = baseline.HermesVersion != "" && current.HermesVersion != "" &&
+		baseline.HermesVersion != current.HermesVersion
+
+	var hermesChecks []metricCheck
+
+	if comparison.HermesChanged {
+		hermesChecks = []metricCheck{
+			{metric: MetricMeshAdoption, baseline: baseline.Hermes.MeshAdoption, current: current.Hermes.MeshAdoption, threshold: thresholds.MeshAdoptionDrop},
+			{metric: MetricTimeToFirstScore, baseline: baseline.Hermes.MedianTimeToFirstScore, current: current.Hermes.MedianTimeToFirstScore, threshold: thresholds.TimeToFirstScoreRise},
+			{metric: MetricPruneRate, baseline: baseline.Hermes.PruneRate, current: current.Hermes.PruneRate, threshold: thresholds.PruneRateRise},
+		}
+		comparison.HermesDeltas = make([]MetricDelta, 0, len(hermesChecks))
+	}
+
+	for i, check := range append(checks, hermesChecks...) {
+		hermes := i >= len(checks)
+
+		// A zero baseline cannot regress, a disabled threshold is still shown for Hermes metrics
+		if check.baseline <= 0 {
+			if hermes {
+				comparison.HermesDeltas = append(comparison.HermesDeltas, MetricDelta{Metric: check.metric, Current: check.current})
+			}
+
+			continue
+		}
+
+		change := check.worsening()
+		regressed := check.threshold > 0 && change > check.threshold
+
+		if hermes {
+			comparison.HermesDeltas = append(comparison.HermesDeltas, MetricDelta{
+				Metric:    check.metric,
+				Baseline:  check.baseline,
+				Current:   check.current,
+				Change:    change,
+				Regressed: regressed,
+			})
+		}
+
+		if regressed {
+			comparison.Regressions = append(comparison.Regressions, Regression{
+				Metric:    check.metric,
+				Baseline:  check.baseline,
+				Current:   check.current,
+				Drop:      change,
+				Threshold: check.threshold,
+			})
+		}
+	}
+
+	return comparison
+}
@@ -0,0 +1,216 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := RunSummary{TotalConnections: 100, SuccessfulHandshakes: 80, HandshakeSuccessRate: 0.8, UniquePeers: 50}
+	thresholds := Thresholds{HandshakeSuccessDrop: 0.20, UniquePeersDrop: 0.30, ConnectionsDrop: 0.30}
+
+	tests := []struct {
+		name            string
+		current         RunSummary
+		expectedMetrics []string
+	}{
+		{
+			name:            "no regression",
+			current:         RunSummary{TotalConnections: 95, SuccessfulHandshakes: 72, HandshakeSuccessRate: 0.72, UniquePeers: 48},
+			expectedMetrics: []string{},
+		},
+		{
+			name:            "handshake success drop",
+			current:         RunSummary{TotalConnections: 100, SuccessfulHandshakes: 50, HandshakeSuccessRate: 0.5, UniquePeers: 50},
+			expectedMetrics: []string{MetricHandshakeSuccessRate},
+		},
+		{
+			name:            "peers and connections drop",
+			current:         RunSummary{TotalConnections: 50, SuccessfulHandshakes: 40, HandshakeSuccessRate: 0.8, UniquePeers: 20},
+			expectedMetrics: []string{MetricUniquePeers, MetricTotalConnections},
+		},
+		{
+			name:            "empty run",
+			current:         RunSummary{},
+			expectedMetrics: []string{MetricHandshakeSuccessRate, MetricUniquePeers, MetricTotalConnections},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison := Compare(baseline, tt.current, thresholds)
+
+			if len(comparison.Regressions) != len(tt.expectedMetrics) {
+				t.Fatalf("expected %d regressions, got %+v", len(tt.expectedMetrics), comparison.Regressions)
+			}
+
+			for i, metric := range tt.expectedMetrics {
+				if comparison.Regressions[i].Metric != metric {
+					t.Errorf("expected regression %d to be %s, got %s", i, metric, comparison.Regressions[i].Metric)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareHermesVersions(t *testing.T) {
+	metrics := peer.HermesMetrics{MeshAdoption: 0.8, MedianTimeToFirstScore: 10, PruneRate: 0.2}
+	thresholds := Thresholds{MeshAdoptionDrop: 0.20, TimeToFirstScoreRise: 0.50, PruneRateRise: 0.50}
+
+	tests := []struct {
+		name            string
+		baseline        string
+		current         string
+		hermes          peer.HermesMetrics
+		expectedChanged bool
+		expectedMetrics []string
+	}{
+		{
+			name:            "same version skips Hermes metrics",
+			baseline:        "v1",
+			current:         "v1",
+			hermes:          peer.HermesMetrics{MeshAdoption: 0.1, MedianTimeToFirstScore: 60, PruneRate: 1},
+			expectedMetrics: []string{},
+		},
+		{
+			name:            "baseline without a version skips Hermes metrics",
+			current:         "v2",
+			hermes:          peer.HermesMetrics{MeshAdoption: 0.1},
+			expectedMetrics: []string{},
+		},
+		{
+			name:            "version bump within thresholds",
+			baseline:        "v1",
+			current:         "v2",
+			hermes:          peer.HermesMetrics{MeshAdoption: 0.7, MedianTimeToFirstScore: 12, PruneRate: 0.25},
+			expectedChanged: true,
+			expectedMetrics: []string{},
+		},
+		{
+			name:            "version bump regresses every Hermes metric",
+			baseline:        "v1",
+			current:         "v2",
+			hermes:          peer.HermesMetrics{MeshAdoption: 0.4, MedianTimeToFirstScore: 30, PruneRate: 0.5},
+			expectedChanged: true,
+			expectedMetrics: []string{MetricMeshAdoption, MetricTimeToFirstScore, MetricPruneRate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison := Compare(
+				RunSummary{HermesVersion: tt.baseline, Hermes: metrics},
+				RunSummary{HermesVersion: tt.current, Hermes: tt.hermes},
+				thresholds,
+			)
+
+			if comparison.HermesChanged != tt.expectedChanged {
+				t.Fatalf("expected HermesChanged %v, got %v", tt.expectedChanged, comparison.HermesChanged)
+			}
+
+			if tt.expectedChanged && len(comparison.HermesDeltas) != 3 {
+				t.Errorf("expected 3 Hermes deltas, got %+v", comparison.HermesDeltas)
+			}
+
+			if len(comparison.Regressions) != len(tt.expectedMetrics) {
+				t.Fatalf("expected %d regressions, got %+v", len(tt.expectedMetrics), comparison.Regressions)
+			}
+
+			for i, metric := range tt.expectedMetrics {
+				if comparison.Regressions[i].Metric != metric {
+					t.Errorf("expected regression %d to be %s, got %s", i, metric, comparison.Regressions[i].Metric)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareValidationModes(t *testing.T) {
+	baseline := RunSummary{ValidationMode: "delegated", TotalConnections: 100, HandshakeSuccessRate: 0.8, UniquePeers: 50}
+	thresholds := Thresholds{HandshakeSuccessDrop: 0.20, UniquePeersDrop: 0.30, ConnectionsDrop: 0.30}
+
+	tests := []struct {
+		name            string
+		mode            string
+		expectedChanged bool
+		expectedCount   int
+	}{
+		{name: "same mode", mode: "delegated", expectedCount: 3},
+		{name: "current without a mode", expectedCount: 3},
+		{name: "other mode is not compared", mode: "independent", expectedChanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison := Compare(baseline, RunSummary{ValidationMode: tt.mode}, thresholds)
+
+			if comparison.ModeChanged != tt.expectedChanged {
+				t.Fatalf("expected ModeChanged %v, got %v", tt.expectedChanged, comparison.ModeChanged)
+			}
+
+			if len(comparison.Regressions) != tt.expectedCount {
+				t.Errorf("expected %d regressions, got %+v", tt.expectedCount, comparison.Regressions)
+			}
+		})
+	}
+}
+
+func TestGitHubIssueReporter(t *testing.T) {
+	var received issueRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/ethpandaops/hermes-peer-score/issues" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing token")
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode issue: %v", err)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":1,"html_url":"https://github.com/ethpandaops/hermes-peer-score/issues/1"}`))
+	}))
+	defer server.Close()
+
+	reporter := NewGitHubIssueReporter("ethpandaops/hermes-peer-score", "secret", []string{"regression"}, time.Second, logrus.New())
+	reporter.SetAPIURL(server.URL)
+
+	comparison := Compare(
+		RunSummary{ValidationMode: "delegated", TotalConnections: 100, SuccessfulHandshakes: 80, HandshakeSuccessRate: 0.8},
+		RunSummary{ValidationMode: "delegated", TotalConnections: 100, SuccessfulHandshakes: 40, HandshakeSuccessRate: 0.4},
+		Thresholds{HandshakeSuccessDrop: 0.20},
+	)
+
+	err := reporter.Report(context.Background(), comparison, Artifacts{
+		HTMLReport: "out/peer-score-report-delegated-2025.html",
+		BaseURL:    "https://ethpandaops.github.io/hermes-peer-score/2025-06-01/",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(received.Title, MetricHandshakeSuccessRate) {
+		t.Errorf("expected title to mention the regressed metric, got %q", received.Title)
+	}
+
+	if !strings.Contains(received.Body, "https://ethpandaops.github.io/hermes-peer-score/2025-06-01/peer-score-report-delegated-2025.html") {
+		t.Errorf("expected body to link the HTML report, got %q", received.Body)
+	}
+
+	if len(received.Labels) != 1 || received.Labels[0] != "regression" {
+		t.Errorf("unexpected labels %v", received.Labels)
+	}
+}
@@ -0,0 +1,219 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGitHubAPIURL is the GitHub REST API base URL.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubIssueReporter opens a GitHub issue describing the regressions of a run.
+type GitHubIssueReporter struct {
+	apiURL     string
+	repo       string // owner/name
+	token      string
+	labels     []string
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+}
+
+// NewGitHubIssueReporter creates a reporter filing issues in the given owner/name repository.
+func NewGitHubIssueReporter(repo, token string, labels []string, timeout time.Duration, logger logrus.FieldLogger) *GitHubIssueReporter {
+	return &GitHubIssueReporter{
+		apiURL: DefaultGitHubAPIURL,
+		repo:   repo,
+		token:  token,
+		labels: labels,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger: logger.WithField("component", "github_issue_reporter"),
+	}
+}
+
+// SetAPIURL overrides the GitHub API base URL, e.g. for GitHub Enterprise.
+func (r *GitHubIssueReporter) SetAPIURL(apiURL string) {
+	r.apiURL = strings.TrimSuffix(apiURL, "/")
+}
+
+// issueRequest is the body of the GitHub create issue API call.
+type issueRequest struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// issueResponse is the subset of the GitHub create issue response used for logging.
+type issueResponse struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Report opens an issue for the comparison if it contains regressions.
+func (r *GitHubIssueReporter) Report(ctx context.Context, comparison *Comparison, artifacts Artifacts) error {
+	if !comparison.HasRegressions() {
+		return nil
+	}
+
+	body, err := json.Marshal(issueRequest{
+		Title:  IssueTitle(comparison),
+		Body:   IssueBody(comparison, artifacts),
+		Labels: r.labels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/issues", r.apiURL, r.repo)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create issue request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to create issue: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var issue issueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return fmt.Errorf("failed to decode issue response: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"repo":   r.repo,
+		"number": issue.Number,
+		"url":    issue.HTMLURL,
+	}).Info("Opened regression issue")
+
+	return nil
+}
+
+// IssueTitle summarises the regressed metrics in a single line.
+func IssueTitle(comparison *Comparison) string {
+	metrics := make([]string, 0, len(comparison.Regressions))
+	for _, regression := range comparison.Regressions {
+		metrics = append(metrics, fmt.Sprintf("%s %s", regression.Metric, FormatChange(regression.Metric, regression.Drop)))
+	}
+
+	title := "Peer score regression"
+	if comparison.HermesChanged {
+		title = "Hermes regression"
+	}
+
+	return fmt.Sprintf("%s (%s): %s", title, comparison.Current.ValidationMode, strings.Join(metrics, ", "))
+}
+
+// IssueBody renders the comparison summary and artifact links as Markdown.
+func IssueBody(comparison *Comparison, artifacts Artifacts) string {
+	var b strings.Builder
+
+	b.WriteString("The latest peer score run regressed against the baseline run.\n\n")
+
+	if comparison.HermesChanged {
+		fmt.Fprintf(&b, "Hermes changed from `%s` to `%s` between the runs, so the Hermes sensitive metrics were compared too.\n\n",
+			comparison.Baseline.HermesVersion, comparison.Current.HermesVersion)
+	}
+
+	b.WriteString("| Metric | Baseline | Current | Change | Threshold |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, regression := range comparison.Regressions {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %.1f%% |\n",
+			regression.Metric,
+			FormatMetric(regression.Metric, regression.Baseline),
+			FormatMetric(regression.Metric, regression.Current),
+			FormatChange(regression.Metric, regression.Drop),
+			regression.Threshold*100,
+		)
+	}
+
+	b.WriteString("\n### Summary\n\n")
+	b.WriteString("| | Baseline | Current |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| Started | %s | %s |\n", formatTime(comparison.Baseline.StartTime), formatTime(comparison.Current.StartTime))
+	fmt.Fprintf(&b, "| Total connections | %d | %d |\n", comparison.Baseline.TotalConnections, comparison.Current.TotalConnections)
+	fmt.Fprintf(&b, "| Successful handshakes | %d | %d |\n", comparison.Baseline.SuccessfulHandshakes, comparison.Current.SuccessfulHandshakes)
+	fmt.Fprintf(&b, "| Failed handshakes | %d | %d |\n", comparison.Baseline.FailedHandshakes, comparison.Current.FailedHandshakes)
+	fmt.Fprintf(&b, "| Handshake success rate | %.1f%% | %.1f%% |\n", comparison.Baseline.HandshakeSuccessRate*100, comparison.Current.HandshakeSuccessRate*100)
+	fmt.Fprintf(&b, "| Unique peers | %d | %d |\n", comparison.Baseline.UniquePeers, comparison.Current.UniquePeers)
+
+	if comparison.HermesChanged {
+		fmt.Fprintf(&b, "| Hermes version | `%s` | `%s` |\n", comparison.Baseline.HermesVersion, comparison.Current.HermesVersion)
+
+		for _, delta := range comparison.HermesDeltas {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", delta.Metric, FormatMetric(delta.Metric, delta.Baseline), FormatMetric(delta.Metric, delta.Current))
+		}
+	}
+
+	b.WriteString("\n### Artifacts\n\n")
+
+	for _, file := range []string{artifacts.HTMLReport, artifacts.HermesReport, artifacts.JSONReport} {
+		if file == "" {
+			continue
+		}
+
+		if artifacts.BaseURL != "" {
+			fmt.Fprintf(&b, "- [%s](%s/%s)\n", filepath.Base(file), strings.TrimSuffix(artifacts.BaseURL, "/"), filepath.Base(file))
+		} else {
+			fmt.Fprintf(&b, "- `%s`\n", filepath.Base(file))
+		}
+	}
+
+	return b.String()
+}
+
+// FormatMetric formats a metric value for display.
+func FormatMetric(metric string, value float64) string {
+	switch metric {
+	case MetricHandshakeSuccessRate, MetricMeshAdoption:
+		return fmt.Sprintf("%.1f%%", value*100)
+	case MetricTimeToFirstScore:
+		return fmt.Sprintf("%.1fs", value)
+	case MetricPruneRate:
+		return fmt.Sprintf("%.2f", value)
+	default:
+		return fmt.Sprintf("%.0f", value)
+	}
+}
+
+// FormatChange formats a relative worsening with the sign of the metric's movement,
+// so a 25% drop reads -25.0% and a 25% rise in a metric where higher is worse reads +25.0%.
+func FormatChange(metric string, worsening float64) string {
+	if !HigherIsWorse(metric) {
+		worsening = -worsening
+	}
+
+	return fmt.Sprintf("%+.1f%%", worsening*100)
+}
+
+// formatTime formats a run start time, tolerating baselines without one.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
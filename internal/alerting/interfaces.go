@@ -0,0 +1,8 @@
+package alerting
+
+import "context"
+
+// Reporter defines the interface for raising regressions found by a baseline comparison.
+type Reporter interface {
+	Report(ctx context.Context, comparison *Comparison, artifacts Artifacts) error
+}
@@ -0,0 +1,87 @@
+package alerting
+
+import (
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// Metric names used in regressions.
+const (
+	MetricHandshakeSuccessRate = "handshake_success_rate"
+	MetricUniquePeers          = "unique_peers"
+	MetricTotalConnections     = "total_connections"
+	MetricMeshAdoption         = "mesh_adoption"
+	MetricTimeToFirstScore     = "time_to_first_score"
+	MetricPruneRate            = "prune_rate"
+)
+
+// HigherIsWorse reports whether a rise in the metric, rather than a drop, is a regression.
+func HigherIsWorse(metric string) bool {
+	return metric == MetricTimeToFirstScore || metric == MetricPruneRate
+}
+
+// RunSummary holds the headline statistics of a run that are compared against a baseline.
+type RunSummary struct {
+	ValidationMode       string             `json:"validation_mode"`
+	StartTime            time.Time          `json:"start_time"`
+	TotalConnections     int                `json:"total_connections"`
+	SuccessfulHandshakes int                `json:"successful_handshakes"`
+	FailedHandshakes     int                `json:"failed_handshakes"`
+	HandshakeSuccessRate float64            `json:"handshake_success_rate"` // Of the handshakes attempted, as the report headline shows it
+	UniquePeers          int                `json:"unique_peers"`
+	HermesVersion        string             `json:"hermes_version,omitempty"`
+	Hermes               peer.HermesMetrics `json:"hermes"`
+}
+
+// Thresholds holds the relative changes, as fractions of the baseline value, that count as regressions.
+// The Hermes sensitive thresholds only apply when the runs used different Hermes versions.
+type Thresholds struct {
+	HandshakeSuccessDrop float64
+	UniquePeersDrop      float64
+	ConnectionsDrop      float64
+	MeshAdoptionDrop     float64
+	TimeToFirstScoreRise float64
+	PruneRateRise        float64
+}
+
+// Regression describes a metric that moved the wrong way beyond its threshold.
+type Regression struct {
+	Metric    string  `json:"metric"`
+	Baseline  float64 `json:"baseline"`
+	Current   float64 `json:"current"`
+	Drop      float64 `json:"drop"`      // Relative worsening from the baseline, 0.25 = 25%
+	Threshold float64 `json:"threshold"` // Relative worsening that triggers a regression
+}
+
+// Comparison is the result of comparing a run against a baseline run.
+type Comparison struct {
+	Baseline      RunSummary    `json:"baseline"`
+	Current       RunSummary    `json:"current"`
+	ModeChanged   bool          `json:"mode_changed"`   // The runs used different validation modes and were not compared
+	HermesChanged bool          `json:"hermes_changed"` // The runs used different Hermes versions
+	Regressions   []Regression  `json:"regressions"`
+	HermesDeltas  []MetricDelta `json:"hermes_deltas,omitempty"` // Set when the Hermes version changed
+}
+
+// MetricDelta shows how a Hermes sensitive metric moved between the runs.
+type MetricDelta struct {
+	Metric    string  `json:"metric"`
+	Baseline  float64 `json:"baseline"`
+	Current   float64 `json:"current"`
+	Change    float64 `json:"change"` // Relative worsening from the baseline, negative when it improved
+	Regressed bool    `json:"regressed"`
+}
+
+// HasRegressions reports whether any metric regressed.
+func (c *Comparison) HasRegressions() bool {
+	return len(c.Regressions) > 0
+}
+
+// Artifacts links to the files produced by the run.
+type Artifacts struct {
+	JSONReport   string
+	HTMLReport   string
+	HermesReport string // Hermes regression report, set when the Hermes version changed
+	BaseURL      string // Optional public location the reports are published under
+}
@@ -0,0 +1,109 @@
+// Package analyzers runs custom analyses of the final report. Analyzers are compiled in
+// through the registry, loaded from Go plugins or run as external processes. Each adds a named
+// JSON section to the report and, optionally, an HTML fragment to the HTML report.
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Analyzer sources, as recorded with each section.
+const (
+	SourceBuiltin = "builtin"
+	SourcePlugin  = "plugin"
+	SourceExec    = "exec"
+)
+
+// Analyzer is a custom analysis of the final report. It is given the JSON report, exactly as
+// written to disk, and returns its findings.
+type Analyzer interface {
+	Name() string
+	Analyze(ctx context.Context, report []byte) (*Output, error)
+}
+
+// Output is what an analyzer found. Data is kept in the JSON report under the analyzer's
+// name, HTML is inserted into the HTML report as is.
+type Output struct {
+	Data json.RawMessage `json:"data,omitempty"`
+	HTML string          `json:"html,omitempty"`
+}
+
+// Section is an analyzer's output as kept in the report.
+type Section struct {
+	Name       string          `json:"name"`
+	Source     string          `json:"source"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	HTML       string          `json:"html,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DurationMs float64         `json:"duration_ms"`
+}
+
+// validName matches analyzer names, which key their sections in the report.
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateName checks that an analyzer name can key a report section.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid analyzer name %q: use lower case letters, digits, - and _", name)
+	}
+
+	return nil
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Analyzer)
+)
+
+// Register adds a compiled-in analyzer, typically from the init function of a package
+// imported for its side effects. Every registered analyzer runs on every report. Register
+// panics on an invalid or duplicate name, like database/sql drivers.
+func Register(analyzer Analyzer) {
+	if analyzer == nil {
+		panic("analyzers: Register analyzer is nil")
+	}
+
+	name := analyzer.Name()
+	if err := ValidateName(name); err != nil {
+		panic("analyzers: " + err.Error())
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic("analyzers: Register called twice for analyzer " + name)
+	}
+
+	registry[name] = analyzer
+}
+
+// Registered returns the compiled-in analyzers, sorted by name.
+func Registered() []Analyzer {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registered := make([]Analyzer, 0, len(registry))
+	for _, analyzer := range registry {
+		registered = append(registered, analyzer)
+	}
+
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Name() < registered[j].Name()
+	})
+
+	return registered
+}
+
+// unregister removes a compiled-in analyzer, for tests.
+func unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, name)
+}
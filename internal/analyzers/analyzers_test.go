@@ -0,0 +1,173 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// funcAnalyzer is an analyzer backed by a function.
+type funcAnalyzer struct {
+	name string
+	fn   func(ctx context.Context, report []byte) (*Output, error)
+}
+
+func (f *funcAnalyzer) Name() string { return f.name }
+
+func (f *funcAnalyzer) Analyze(ctx context.Context, report []byte) (*Output, error) {
+	return f.fn(ctx, report)
+}
+
+func TestRegister(t *testing.T) {
+	analyzer := &funcAnalyzer{name: "mev-relays"}
+
+	Register(analyzer)
+	defer unregister("mev-relays")
+
+	registered := Registered()
+	if len(registered) != 1 || registered[0] != analyzer {
+		t.Fatalf("Expected the registered analyzer, got %v", registered)
+	}
+
+	for name, analyzer := range map[string]Analyzer{
+		"duplicate": &funcAnalyzer{name: "mev-relays"},
+		"invalid":   &funcAnalyzer{name: "MEV Relays"},
+		"nil":       nil,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected Register to panic for a %s analyzer", name)
+				}
+			}()
+
+			Register(analyzer)
+		}()
+	}
+}
+
+func TestRunner(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	runner := NewRunner(logger)
+
+	analyzers := []Analyzer{
+		&funcAnalyzer{name: "peers", fn: func(_ context.Context, report []byte) (*Output, error) {
+			var decoded struct {
+				Peers map[string]json.RawMessage `json:"peers"`
+			}
+
+			if err := json.Unmarshal(report, &decoded); err != nil {
+				return nil, err
+			}
+
+			data, err := json.Marshal(map[string]int{"peers": len(decoded.Peers)})
+
+			return &Output{Data: data, HTML: "<p>2 peers</p>"}, err
+		}},
+		&funcAnalyzer{name: "failing", fn: func(context.Context, []byte) (*Output, error) {
+			return nil, errors.New("relay list unavailable")
+		}},
+		&funcAnalyzer{name: "panicking", fn: func(context.Context, []byte) (*Output, error) {
+			panic("nil map")
+		}},
+		&funcAnalyzer{name: "invalid-json", fn: func(context.Context, []byte) (*Output, error) {
+			return &Output{Data: json.RawMessage("{")}, nil
+		}},
+		&funcAnalyzer{name: "empty", fn: func(context.Context, []byte) (*Output, error) {
+			return nil, nil
+		}},
+	}
+
+	for _, analyzer := range analyzers {
+		if err := runner.Add(analyzer, SourceBuiltin); err != nil {
+			t.Fatalf("Expected no error adding %s, got %v", analyzer.Name(), err)
+		}
+	}
+
+	if err := runner.Add(&funcAnalyzer{name: "peers"}, SourceExec); err == nil {
+		t.Error("Expected an error adding a duplicate analyzer")
+	}
+
+	sections := runner.Run(context.Background(), []byte(`{"peers": {"a": {}, "b": {}}}`))
+	if len(sections) != len(analyzers) {
+		t.Fatalf("Expected a section per analyzer, got %d", len(sections))
+	}
+
+	if peers := sections[0]; peers.Name != "peers" || peers.Source != SourceBuiltin ||
+		string(peers.Data) != `{"peers":2}` || peers.HTML != "<p>2 peers</p>" || peers.Error != "" {
+		t.Errorf("Unexpected section %+v", peers)
+	}
+
+	for i, want := range map[int]string{1: "relay list unavailable", 2: "analyzer panicked: nil map", 3: "invalid JSON"} {
+		if !strings.Contains(sections[i].Error, want) || sections[i].Data != nil {
+			t.Errorf("Expected section %s to fail with %q, got %+v", sections[i].Name, want, sections[i])
+		}
+	}
+
+	if empty := sections[4]; empty.Error != "" || empty.Data != nil {
+		t.Errorf("Expected an empty section without error, got %+v", empty)
+	}
+}
+
+func TestExecAnalyzer(t *testing.T) {
+	program := filepath.Join(t.TempDir(), "mev-relays.py")
+	if err := os.WriteFile(program, []byte("#!/bin/sh\n"), 0o700); err != nil {
+		t.Fatalf("Failed to write program: %v", err)
+	}
+
+	analyzer, err := NewExecAnalyzer(program)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if analyzer.Name() != "mev-relays" {
+		t.Errorf("Expected the analyzer to be named after the file, got %s", analyzer.Name())
+	}
+
+	var stdin []byte
+
+	analyzer.run = func(_ context.Context, path string, input []byte) ([]byte, error) {
+		stdin = input
+
+		return []byte(`{"data": {"relays": 3}, "html": "<b>3 relays</b>"}`), nil
+	}
+
+	output, err := analyzer.Analyze(context.Background(), []byte(`{"peers": {}}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(stdin) != `{"peers": {}}` || string(output.Data) != `{"relays": 3}` || output.HTML != "<b>3 relays</b>" {
+		t.Errorf("Unexpected output %+v for input %s", output, stdin)
+	}
+
+	analyzer.run = func(context.Context, string, []byte) ([]byte, error) {
+		return []byte("Traceback"), nil
+	}
+
+	if _, err := analyzer.Analyze(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "invalid output") {
+		t.Errorf("Expected invalid output to fail, got %v", err)
+	}
+
+	if _, err := NewExecAnalyzer(filepath.Join(t.TempDir(), "MEV Relays")); err == nil {
+		t.Error("Expected an error for a program name that cannot key a section")
+	}
+
+	if _, err := NewExecAnalyzer(filepath.Join(t.TempDir(), "missing.py")); err == nil {
+		t.Error("Expected an error for a missing program")
+	}
+}
+
+func TestLoadPlugin(t *testing.T) {
+	if _, err := LoadPlugin(filepath.Join(t.TempDir(), "mev-relays.so")); err == nil {
+		t.Error("Expected an error for a missing plugin")
+	}
+}
@@ -0,0 +1,75 @@
+package analyzers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// commandRunner runs a command with the given standard input and returns its standard output.
+type commandRunner func(ctx context.Context, path string, stdin []byte) ([]byte, error)
+
+// runCommand runs a command with os/exec, adding its standard error to a failure.
+func runCommand(ctx context.Context, path string, stdin []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// ExecAnalyzer runs an external program as an analyzer, so analyses can be written in any
+// language. The program reads the JSON report on standard input and writes its output as a
+// JSON object with optional "data" and "html" fields to standard output.
+type ExecAnalyzer struct {
+	name string
+	path string
+	run  commandRunner
+}
+
+// NewExecAnalyzer creates an analyzer running the program at path, named after the file
+// without its extension, e.g. mev-relays.py is mev-relays. The program must be executable.
+func NewExecAnalyzer(path string) (*ExecAnalyzer, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer program %s: %w", path, err)
+	}
+
+	return &ExecAnalyzer{name: name, path: resolved, run: runCommand}, nil
+}
+
+// Name returns the name of the program file.
+func (e *ExecAnalyzer) Name() string {
+	return e.name
+}
+
+// Analyze runs the program on the report and parses its output.
+func (e *ExecAnalyzer) Analyze(ctx context.Context, report []byte) (*Output, error) {
+	stdout, err := e.run(ctx, e.path, report)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer program %s failed: %w", e.path, err)
+	}
+
+	var output Output
+	if err := json.Unmarshal(stdout, &output); err != nil {
+		return nil, fmt.Errorf("analyzer program %s wrote invalid output: %w", e.path, err)
+	}
+
+	return &output, nil
+}
@@ -0,0 +1,64 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"strings"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// PluginFunc is the function a Go plugin analyzer exports as Analyze. It uses standard library
+// types only, so plugins can be built outside this module. It returns the JSON data and HTML
+// fragment of its section.
+type PluginFunc = func(ctx context.Context, report []byte) (data []byte, html string, err error)
+
+// pluginAnalyzer is an analyzer loaded from a Go plugin.
+type pluginAnalyzer struct {
+	name string
+	fn   PluginFunc
+}
+
+// LoadPlugin loads an analyzer from a Go plugin (.so) built with go build -buildmode=plugin.
+// The analyzer is named after the file, e.g. mev-relays.so is mev-relays. Plugins must be
+// built with the same Go version and dependency versions as the tool.
+func LoadPlugin(path string) (Analyzer, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	loaded, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open analyzer plugin: %w", err)
+	}
+
+	symbol, err := loaded.Lookup(constants.AnalyzerPluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer plugin %s: %w", path, err)
+	}
+
+	fn, ok := symbol.(PluginFunc)
+	if !ok {
+		return nil, fmt.Errorf("analyzer plugin %s: %s is a %T, not a %T", path, constants.AnalyzerPluginSymbol, symbol, PluginFunc(nil))
+	}
+
+	return &pluginAnalyzer{name: name, fn: fn}, nil
+}
+
+// Name returns the name of the plugin file.
+func (p *pluginAnalyzer) Name() string {
+	return p.name
+}
+
+// Analyze calls the plugin's Analyze function.
+func (p *pluginAnalyzer) Analyze(ctx context.Context, report []byte) (*Output, error) {
+	data, html, err := p.fn(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Output{Data: data, HTML: html}, nil
+}
@@ -0,0 +1,117 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// entry is an analyzer added to a runner and where it came from.
+type entry struct {
+	analyzer Analyzer
+	source   string
+}
+
+// Runner runs analyzers on the final report, one after the other in the order they were added.
+// An analyzer that fails, panics or runs out of time is recorded in its section and never
+// stops the others or the report.
+type Runner struct {
+	entries []entry
+	names   map[string]bool
+	timeout time.Duration
+	logger  logrus.FieldLogger
+}
+
+// NewRunner creates a runner without analyzers.
+func NewRunner(logger logrus.FieldLogger) *Runner {
+	return &Runner{
+		names:   make(map[string]bool),
+		timeout: constants.DefaultAnalyzerTimeout,
+		logger:  logger.WithField("component", "analyzers"),
+	}
+}
+
+// Add adds an analyzer from the given source. Names key the report sections, so each may be
+// added once.
+func (r *Runner) Add(analyzer Analyzer, source string) error {
+	name := analyzer.Name()
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	if r.names[name] {
+		return fmt.Errorf("duplicate analyzer %s", name)
+	}
+
+	r.names[name] = true
+	r.entries = append(r.entries, entry{analyzer: analyzer, source: source})
+
+	return nil
+}
+
+// Len returns the number of analyzers added.
+func (r *Runner) Len() int {
+	return len(r.entries)
+}
+
+// Run runs every analyzer on the JSON report and returns their sections, failed ones included.
+func (r *Runner) Run(ctx context.Context, report []byte) []Section {
+	sections := make([]Section, 0, len(r.entries))
+
+	for _, added := range r.entries {
+		section := Section{Name: added.analyzer.Name(), Source: added.source}
+
+		started := time.Now()
+		output, err := r.analyze(ctx, added.analyzer, report)
+		section.DurationMs = float64(time.Since(started)) / float64(time.Millisecond)
+
+		if err == nil && len(output.Data) > 0 && !json.Valid(output.Data) {
+			err = errors.New("analyzer returned invalid JSON data")
+		}
+
+		logger := r.logger.WithFields(logrus.Fields{
+			"analyzer": section.Name,
+			"source":   section.Source,
+		})
+
+		if err != nil {
+			section.Error = err.Error()
+
+			logger.WithError(err).Warn("Custom analyzer failed")
+		} else {
+			section.Data = output.Data
+			section.HTML = output.HTML
+
+			logger.WithField("duration_ms", section.DurationMs).Debug("Custom analyzer finished")
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// analyze runs one analyzer within the analyzer timeout, turning a panic into an error.
+func (r *Runner) analyze(ctx context.Context, analyzer Analyzer, report []byte) (output *Output, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			output, err = nil, fmt.Errorf("analyzer panicked: %v", recovered)
+		}
+	}()
+
+	output, err = analyzer.Analyze(ctx, report)
+	if err == nil && output == nil {
+		output = &Output{}
+	}
+
+	return output, err
+}
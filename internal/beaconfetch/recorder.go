@@ -0,0 +1,292 @@
+package beaconfetch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+)
+
+// kindOrder is the order endpoints are listed in, the fetches validation depends on first.
+var kindOrder = []string{KindBeaconState, KindSpec, KindBeacon, KindOther}
+
+// Recorder is an http.RoundTripper that times the requests made to the beacon node's API and
+// passes every other request through untouched. Hermes builds its independent validation's
+// beacon API client on the default transport, so installing the recorder there observes the
+// fetches without changes to Hermes.
+type Recorder struct {
+	host     string // host:port of the beacon API
+	endpoint string
+	base     http.RoundTripper
+	previous http.RoundTripper // Default transport before Install, put back by Restore
+
+	mu    sync.Mutex
+	kinds map[string]*kindStats
+}
+
+// kindStats accumulates the fetches of one kind of request.
+type kindStats struct {
+	requests  int
+	failures  int
+	slow      int
+	total     time.Duration
+	max       time.Duration
+	recent    []time.Duration // Ring of the most recent fetch latencies
+	next      int
+	lastError string
+}
+
+// NewRecorder creates a recorder for the beacon API of a Prysm host connection string, which
+// may carry user:password@ credentials, sending requests on through base.
+func NewRecorder(host string, port int, useTLS bool, base http.RoundTripper) *Recorder {
+	// Requests are labelled by the endpoint without its credentials
+	endpoint := common.ParseBeaconEndpoint(host, port, useTLS)
+	endpoint.User = nil
+
+	return &Recorder{
+		host:     endpoint.Host,
+		endpoint: endpoint.String(),
+		base:     base,
+		kinds:    make(map[string]*kindStats),
+	}
+}
+
+// Install creates a recorder and routes the default HTTP transport through it. Call Restore
+// once the run is done.
+func Install(host string, port int, useTLS bool) *Recorder {
+	recorder := NewRecorder(host, port, useTLS, http.DefaultTransport)
+	recorder.previous = http.DefaultTransport
+	http.DefaultTransport = recorder
+
+	return recorder
+}
+
+// Restore puts back the default transport the recorder was installed over.
+func (r *Recorder) Restore() {
+	if r.previous != nil && http.DefaultTransport == r {
+		http.DefaultTransport = r.previous
+	}
+}
+
+// RoundTrip implements http.RoundTripper. A fetch is timed until its body is read or closed,
+// as the beacon state's download is most of it.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != r.host || ignored(req.URL.Path) {
+		return r.base.RoundTrip(req)
+	}
+
+	kind := Classify(req.URL.Path)
+	start := time.Now()
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		r.record(kind, time.Since(start), err)
+
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		r.record(kind, time.Since(start), fmt.Errorf("HTTP %d", resp.StatusCode))
+
+		return resp, nil
+	}
+
+	resp.Body = &timedBody{ReadCloser: resp.Body, done: func(err error) {
+		r.record(kind, time.Since(start), err)
+	}}
+
+	return resp, nil
+}
+
+// ignored reports whether a request is left out of the fetches: the node endpoints are
+// liveness probes and the tool's own cross-checks, not beacon data validation runs on.
+func ignored(path string) bool {
+	return strings.HasPrefix(path, "/eth/v1/node/")
+}
+
+// Classify returns the kind of beacon API request a path is.
+func Classify(path string) string {
+	switch {
+	case strings.Contains(path, "/debug/beacon/states/"):
+		return KindBeaconState
+	case strings.HasPrefix(path, "/eth/v1/config/"):
+		return KindSpec
+	case strings.HasPrefix(path, "/eth/v1/beacon/"):
+		return KindBeacon
+	default:
+		return KindOther
+	}
+}
+
+// record adds one fetch, failed when err is set.
+func (r *Recorder) record(kind string, took time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.kinds[kind]
+	if !ok {
+		stats = &kindStats{}
+		r.kinds[kind] = stats
+	}
+
+	stats.requests++
+	stats.total += took
+	stats.max = max(stats.max, took)
+
+	if took > constants.BeaconFetchSlowThreshold {
+		stats.slow++
+	}
+
+	if err != nil {
+		stats.failures++
+		stats.lastError = err.Error()
+	}
+
+	if len(stats.recent) < constants.BeaconFetchLatencySamples {
+		stats.recent = append(stats.recent, took)
+	} else {
+		stats.recent[stats.next] = took
+		stats.next = (stats.next + 1) % len(stats.recent)
+	}
+}
+
+// Summary returns the fetches recorded so far and judges their health.
+func (r *Recorder) Summary() *Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := &Summary{
+		Endpoint:             r.endpoint,
+		Observed:             true,
+		SlowThresholdSeconds: constants.BeaconFetchSlowThreshold.Seconds(),
+		Endpoints:            make([]EndpointStats, 0, len(r.kinds)),
+	}
+
+	for _, kind := range kindOrder {
+		stats, ok := r.kinds[kind]
+		if !ok {
+			continue
+		}
+
+		summary.Requests += stats.requests
+		summary.Failures += stats.failures
+		summary.Slow += stats.slow
+
+		summary.Endpoints = append(summary.Endpoints, EndpointStats{
+			Kind:       kind,
+			Requests:   stats.requests,
+			Failures:   stats.failures,
+			Slow:       stats.slow,
+			MeanMillis: millis(stats.total) / float64(stats.requests),
+			P95Millis:  millis(percentile(stats.recent, 0.95)),
+			MaxMillis:  millis(stats.max),
+			LastError:  stats.lastError,
+		})
+	}
+
+	judge(summary)
+
+	return summary
+}
+
+// Unobserved returns the summary of a run whose fetches could not be instrumented.
+func Unobserved(host string, port int, useTLS bool, note string) *Summary {
+	return &Summary{
+		Endpoint:             NewRecorder(host, port, useTLS, nil).endpoint,
+		Note:                 note,
+		Health:               HealthUnknown,
+		SlowThresholdSeconds: constants.BeaconFetchSlowThreshold.Seconds(),
+		Endpoints:            make([]EndpointStats, 0),
+	}
+}
+
+// judge sets the health of the fetches. Validation without a beacon state cannot work, and
+// failing or slow fetches leave it on a stale one.
+func judge(summary *Summary) {
+	summary.Health = HealthHealthy
+
+	if summary.Requests == 0 {
+		summary.Health = HealthUnhealthy
+		summary.Reasons = append(summary.Reasons, "no beacon data was fetched")
+
+		return
+	}
+
+	if summary.Failures > 0 {
+		summary.Health = HealthDegraded
+		if float64(summary.Failures) >= constants.BeaconFetchUnhealthyFailure*float64(summary.Requests) {
+			summary.Health = HealthUnhealthy
+		}
+
+		summary.Reasons = append(summary.Reasons, fmt.Sprintf("%d of %d fetches failed", summary.Failures, summary.Requests))
+	}
+
+	if summary.Slow > 0 {
+		if summary.Health == HealthHealthy {
+			summary.Health = HealthDegraded
+		}
+
+		summary.Reasons = append(summary.Reasons, fmt.Sprintf("%d fetches took longer than %s", summary.Slow, constants.BeaconFetchSlowThreshold))
+	}
+}
+
+// percentile returns the q quantile of the latencies, by the nearest rank.
+func percentile(latencies []time.Duration, q float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+
+	return sorted[max(rank, 0)]
+}
+
+// millis returns a duration in milliseconds.
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// timedBody reports when a response body has been read to the end, failed or been closed.
+type timedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func(error)
+}
+
+// Read implements io.Reader.
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		b.finish(nil)
+	case err != nil:
+		b.finish(err)
+	}
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *timedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish(nil)
+
+	return err
+}
+
+// finish records the fetch once.
+func (b *timedBody) finish(err error) {
+	b.once.Do(func() { b.done(err) })
+}
@@ -0,0 +1,103 @@
+package beaconfetch
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRecorderRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eth/v1/config/spec" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+
+	recorder := NewRecorder("user:secret@"+serverURL.Hostname(), port, false, http.DefaultTransport)
+	client := &http.Client{Transport: recorder}
+
+	for _, path := range []string{"/eth/v2/debug/beacon/states/head", "/eth/v2/debug/beacon/states/head", "/eth/v1/config/spec", "/eth/v1/node/syncing"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	summary := recorder.Summary()
+
+	if summary.Endpoint != "http://"+serverURL.Host || !summary.Observed {
+		t.Errorf("Expected the redacted endpoint observed, got %+v", summary)
+	}
+
+	if summary.Requests != 3 || summary.Failures != 1 || len(summary.Endpoints) != 2 {
+		t.Fatalf("Expected 3 fetches, 1 failed, over 2 kinds, got %+v", summary)
+	}
+
+	if state := summary.Endpoints[0]; state.Kind != KindBeaconState || state.Requests != 2 || state.Failures != 0 {
+		t.Errorf("Expected 2 beacon state fetches first, got %+v", state)
+	}
+
+	if spec := summary.Endpoints[1]; spec.Kind != KindSpec || spec.LastError != "HTTP 503" {
+		t.Errorf("Expected the failed spec fetch, got %+v", spec)
+	}
+
+	if summary.Health != HealthDegraded {
+		t.Errorf("Expected degraded health for 1 failure in 3, got %s", summary.Health)
+	}
+}
+
+func TestRecorderHealth(t *testing.T) {
+	recorder := NewRecorder("localhost", 3500, false, nil)
+
+	if summary := recorder.Summary(); summary.Health != HealthUnhealthy {
+		t.Errorf("Expected unhealthy without any fetch, got %+v", summary)
+	}
+
+	recorder.record(KindBeaconState, 2*time.Second, nil)
+	recorder.record(KindBeaconState, 4*time.Second, nil)
+
+	summary := recorder.Summary()
+	if summary.Health != HealthHealthy || summary.Endpoints[0].MeanMillis != 3000 || summary.Endpoints[0].P95Millis != 4000 {
+		t.Errorf("Expected healthy fetches averaging 3s, got %+v", summary)
+	}
+
+	recorder.record(KindBeaconState, time.Minute, nil)
+
+	if summary := recorder.Summary(); summary.Health != HealthDegraded || summary.Slow != 1 {
+		t.Errorf("Expected a slow fetch to degrade health, got %+v", summary)
+	}
+
+	recorder.record(KindBeacon, time.Second, io.ErrUnexpectedEOF)
+	recorder.record(KindBeacon, time.Second, io.ErrUnexpectedEOF)
+	recorder.record(KindBeacon, time.Second, io.ErrUnexpectedEOF)
+
+	if summary := recorder.Summary(); summary.Health != HealthUnhealthy || len(summary.Reasons) != 2 {
+		t.Errorf("Expected half the fetches failing to be unhealthy, got %+v", summary)
+	}
+
+	if kind := Classify("/eth/v1/beacon/states/head/committees"); kind != KindBeacon {
+		t.Errorf("Expected committees to classify as beacon, got %s", kind)
+	}
+}
@@ -0,0 +1,46 @@
+package beaconfetch
+
+// Health of the beacon data fetches recorded in the report.
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"  // Some fetches failed or were slow
+	HealthUnhealthy = "unhealthy" // Most fetches failed, or none were made
+	HealthUnknown   = "unknown"   // The fetches could not be observed
+)
+
+// Kinds of beacon API request independent validation makes.
+const (
+	KindBeaconState = "beacon_state" // /eth/v2/debug/beacon/states, the state validation runs against
+	KindSpec        = "spec"         // /eth/v1/config, the chain spec fetched with each state
+	KindBeacon      = "beacon"       // Other /eth/v1/beacon endpoints, e.g. committees and genesis
+	KindOther       = "other"
+)
+
+// EndpointStats summarises the fetches of one kind of request.
+type EndpointStats struct {
+	Kind       string  `json:"kind"`
+	Requests   int     `json:"requests"`
+	Failures   int     `json:"failures"` // Transport errors, error statuses and broken bodies
+	Slow       int     `json:"slow"`     // Fetches taking longer than the slow threshold
+	MeanMillis float64 `json:"mean_ms"`
+	P95Millis  float64 `json:"p95_ms"` // Over the most recent fetches
+	MaxMillis  float64 `json:"max_ms"`
+	LastError  string  `json:"last_error,omitempty"`
+}
+
+// Summary is the health of the beacon data fetches independent validation relies on. Slow or
+// failing fetches leave validation on stale state, which silently changes how we validate and
+// forward gossip and so how peers score us.
+type Summary struct {
+	Endpoint             string          `json:"endpoint"` // Beacon API, with credentials redacted
+	Observed             bool            `json:"observed"` // Fetches could be instrumented, see Note otherwise
+	Note                 string          `json:"note,omitempty"`
+	Health               string          `json:"health"`
+	Reasons              []string        `json:"reasons,omitempty"`
+	Requests             int             `json:"requests"`
+	Failures             int             `json:"failures"`
+	Slow                 int             `json:"slow"`
+	SlowThresholdSeconds float64         `json:"slow_threshold_seconds"`
+	Endpoints            []EndpointStats `json:"endpoints"`
+	CacheSize            int             `json:"cache_size,omitempty"` // From the validation-cache-size override
+}
@@ -0,0 +1,205 @@
+package beaconpeers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+// peersPath is the standard beacon API endpoint listing the node's peers.
+const peersPath = "/eth/v1/node/peers"
+
+// Checker cross-references Hermes' peers with the peers the beacon node knows about.
+type Checker struct {
+	endpoint   *url.URL
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+}
+
+// NewChecker creates a checker for the beacon API of a Prysm host connection string,
+// which may carry user:password@ credentials.
+func NewChecker(host string, port int, useTLS bool, timeout time.Duration, logger logrus.FieldLogger) *Checker {
+	return &Checker{
+		endpoint: common.ParseBeaconEndpoint(host, port, useTLS),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger: logger.WithField("component", "beacon_peers"),
+	}
+}
+
+// Check fetches the beacon node's peers and compares them with Hermes' view. A failed
+// fetch is recorded in the result rather than returned, so the report is still written.
+func (c *Checker) Check(ctx context.Context, hermes map[string]HermesPeer) *Result {
+	beacon, err := c.fetchPeers(ctx)
+	if err != nil {
+		c.logger.WithError(err).Warn("Beacon node peer cross-check failed")
+
+		return &Result{
+			Endpoint:       redact.URL(c.endpoint.String()),
+			CheckedAt:      time.Now(),
+			Reconciliation: make([]StateCount, 0),
+			OneSided:       make([]OneSidedPeer, 0),
+			Snapshot:       make([]BeaconPeer, 0),
+			Error:          err.Error(),
+		}
+	}
+
+	result := Compare(beacon, hermes)
+	result.Endpoint = redact.URL(c.endpoint.String())
+	result.CheckedAt = time.Now()
+
+	c.logger.WithFields(logrus.Fields{
+		"beacon_peers": result.BeaconPeers,
+		"overlap":      result.Overlap,
+		"beacon_only":  result.BeaconOnly,
+		"hermes_only":  result.HermesOnly,
+	}).Info("Beacon node peer cross-check complete")
+
+	return result
+}
+
+// fetchPeers lists the beacon node's peers.
+func (c *Checker) fetchPeers(ctx context.Context) ([]BeaconPeer, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint.JoinPath(peersPath).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create beacon peers request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach beacon API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return nil, fmt.Errorf("beacon API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var peers peersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&peers); err != nil {
+		return nil, fmt.Errorf("failed to decode beacon peers: %w", err)
+	}
+
+	return peers.Data, nil
+}
+
+// Compare reports the overlap between the beacon node's peers and Hermes' view: the peers
+// both know about and those only one side saw, listing the ones connected on their side.
+// The two are independent hosts, so a peer's state or direction on one says nothing about
+// the other and is not compared. Every peer is reconciled by state, and the beacon node's
+// list is kept as the snapshot.
+func Compare(beacon []BeaconPeer, hermes map[string]HermesPeer) *Result {
+	result := &Result{
+		BeaconPeers: len(beacon),
+		OneSided:    make([]OneSidedPeer, 0),
+		Snapshot:    append(make([]BeaconPeer, 0, len(beacon)), beacon...),
+	}
+
+	states := make(map[string]*StateCount)
+	count := func(state string) *StateCount {
+		if states[state] == nil {
+			states[state] = &StateCount{State: state}
+		}
+
+		return states[state]
+	}
+
+	listed := make(map[string]bool, len(beacon))
+
+	for _, beaconPeer := range beacon {
+		listed[beaconPeer.PeerID] = true
+	}
+
+	for peerID, view := range hermes {
+		if view.Connected {
+			result.HermesPeers++
+		}
+
+		if !listed[peerID] {
+			result.HermesOnly++
+			count(hermesState(view)).HermesOnly++
+
+			if view.Connected {
+				result.OneSided = append(result.OneSided, OneSidedPeer{PeerID: peerID, Side: SideHermes})
+			}
+		}
+	}
+
+	for _, beaconPeer := range beacon {
+		if _, known := hermes[beaconPeer.PeerID]; known {
+			result.Overlap++
+			count(beaconPeer.State).Both++
+
+			continue
+		}
+
+		result.BeaconOnly++
+		count(beaconPeer.State).BeaconOnly++
+
+		if beaconPeer.State == StateConnected {
+			result.OneSided = append(result.OneSided, OneSidedPeer{
+				PeerID:             beaconPeer.PeerID,
+				Side:               SideBeacon,
+				LastSeenP2PAddress: beaconPeer.LastSeenP2PAddress,
+			})
+		}
+	}
+
+	sort.Slice(result.OneSided, func(i, j int) bool {
+		if result.OneSided[i].Side != result.OneSided[j].Side {
+			return result.OneSided[i].Side == SideBeacon
+		}
+
+		return result.OneSided[i].PeerID < result.OneSided[j].PeerID
+	})
+
+	sort.Slice(result.Snapshot, func(i, j int) bool {
+		return result.Snapshot[i].PeerID < result.Snapshot[j].PeerID
+	})
+
+	result.Reconciliation = make([]StateCount, 0, len(states))
+	for _, state := range []string{StateConnected, StateConnecting, StateDisconnecting, StateDisconnected} {
+		if counted, ok := states[state]; ok {
+			result.Reconciliation = append(result.Reconciliation, *counted)
+			delete(states, state)
+		}
+	}
+
+	// States outside the beacon API's, in case a node reports its own
+	others := make([]string, 0, len(states))
+	for state := range states {
+		others = append(others, state)
+	}
+
+	sort.Strings(others)
+
+	for _, state := range others {
+		result.Reconciliation = append(result.Reconciliation, *states[state])
+	}
+
+	return result
+}
+
+// hermesState describes Hermes' view of a peer in beacon API terms.
+func hermesState(view HermesPeer) string {
+	if view.Connected {
+		return StateConnected
+	}
+
+	return StateDisconnected
+}
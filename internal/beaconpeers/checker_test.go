@@ -0,0 +1,134 @@
+package beaconpeers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCompare(t *testing.T) {
+	beacon := []BeaconPeer{
+		{PeerID: "both-connected", State: StateConnected, Direction: "outbound"},
+		{PeerID: "both-differing", State: StateDisconnected, LastSeenP2PAddress: "/ip4/1.2.3.4/tcp/9000"},
+		{PeerID: "leaving", State: StateDisconnecting, Direction: "outbound"},
+		{PeerID: "beacon-only", State: StateConnected, Direction: "inbound", LastSeenP2PAddress: "/ip4/5.6.7.8/tcp/9000"},
+		{PeerID: "beacon-only-gone", State: StateDisconnected},
+	}
+
+	hermes := map[string]HermesPeer{
+		"both-connected":   {Connected: true},
+		"both-differing":   {Connected: true},
+		"leaving":          {Connected: true},
+		"hermes-only":      {Connected: true},
+		"hermes-only-gone": {Connected: false},
+	}
+
+	result := Compare(beacon, hermes)
+
+	if result.BeaconPeers != 5 || result.HermesPeers != 4 || result.Overlap != 3 || result.BeaconOnly != 2 || result.HermesOnly != 2 {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+
+	if len(result.Snapshot) != 5 || result.Snapshot[0].PeerID != "beacon-only" {
+		t.Errorf("unexpected snapshot: %+v", result.Snapshot)
+	}
+
+	reconciliation := []StateCount{
+		{State: StateConnected, Both: 1, BeaconOnly: 1, HermesOnly: 1},
+		{State: StateDisconnecting, Both: 1},
+		{State: StateDisconnected, Both: 1, BeaconOnly: 1, HermesOnly: 1},
+	}
+
+	if len(result.Reconciliation) != len(reconciliation) {
+		t.Fatalf("expected %d reconciliation rows, got %+v", len(reconciliation), result.Reconciliation)
+	}
+
+	for i, want := range reconciliation {
+		if result.Reconciliation[i] != want {
+			t.Errorf("reconciliation row %d = %+v, want %+v", i, result.Reconciliation[i], want)
+		}
+	}
+
+	// Peers known to both are never flagged, whatever their state on either side
+	oneSided := []OneSidedPeer{
+		{PeerID: "beacon-only", Side: SideBeacon, LastSeenP2PAddress: "/ip4/5.6.7.8/tcp/9000"},
+		{PeerID: "hermes-only", Side: SideHermes},
+	}
+
+	if len(result.OneSided) != len(oneSided) {
+		t.Fatalf("expected %d one-sided peers, got %+v", len(oneSided), result.OneSided)
+	}
+
+	for i, want := range oneSided {
+		if result.OneSided[i] != want {
+			t.Errorf("one-sided peer %d = %+v, want %+v", i, result.OneSided[i], want)
+		}
+	}
+}
+
+func TestCheckerCheck(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/node/peers" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":[{"peer_id":"a","state":"disconnected","direction":"inbound","last_seen_p2p_address":"/ip4/1.2.3.4/tcp/9000"}]}`))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		host        string
+		wantError   bool
+		wantOverlap int
+	}{
+		{name: "credentials from host", host: "user:secret@" + serverURL.Hostname(), wantOverlap: 1},
+		{name: "missing credentials", host: serverURL.Hostname(), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(tt.host, port, false, time.Second, logger)
+			result := checker.Check(context.Background(), map[string]HermesPeer{"a": {Connected: true}})
+
+			if (result.Error != "") != tt.wantError {
+				t.Fatalf("Error = %q, want error %v", result.Error, tt.wantError)
+			}
+
+			if result.Overlap != tt.wantOverlap {
+				t.Errorf("expected overlap %d, got %+v", tt.wantOverlap, result)
+			}
+
+			if result.Endpoint == "" || (tt.host != serverURL.Hostname() && result.Endpoint == "http://"+tt.host+":"+serverURL.Port()) {
+				t.Errorf("expected endpoint with redacted credentials, got %q", result.Endpoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package beaconpeers
+
+import "time"
+
+// Beacon API peer states.
+const (
+	StateConnected     = "connected"
+	StateConnecting    = "connecting"
+	StateDisconnected  = "disconnected"
+	StateDisconnecting = "disconnecting"
+)
+
+// Sides of the cross-check a one-sided peer was seen by.
+const (
+	SideBeacon = "beacon"
+	SideHermes = "hermes"
+)
+
+// BeaconPeer is a peer as listed by the beacon node's /eth/v1/node/peers endpoint.
+type BeaconPeer struct {
+	PeerID             string `json:"peer_id"`
+	ENR                string `json:"enr,omitempty"`
+	LastSeenP2PAddress string `json:"last_seen_p2p_address"`
+	State              string `json:"state"`
+	Direction          string `json:"direction"`
+}
+
+// HermesPeer is Hermes' view of a peer at the time of the check.
+type HermesPeer struct {
+	Connected bool
+}
+
+// OneSidedPeer is a peer connected on one side of the cross-check that the other side never
+// saw. Hermes and the beacon node are independent hosts with their own connections, so only
+// whether a peer is known at all is compared, not its state or direction.
+type OneSidedPeer struct {
+	PeerID             string `json:"peer_id"`
+	Side               string `json:"side"` // beacon or hermes
+	LastSeenP2PAddress string `json:"last_seen_p2p_address,omitempty"`
+}
+
+// StateCount reconciles the peers in one state: those known to both sides, in the beacon
+// node's state, and those only one side knows, each in its own state.
+type StateCount struct {
+	State      string `json:"state"`
+	Both       int    `json:"both"`
+	BeaconOnly int    `json:"beacon_only"`
+	HermesOnly int    `json:"hermes_only"`
+}
+
+// Summary is the counts of one check, kept for the checks taken during the run.
+type Summary struct {
+	CheckedAt   time.Time `json:"checked_at"`
+	BeaconPeers int       `json:"beacon_peers"`
+	HermesPeers int       `json:"hermes_peers"`
+	Overlap     int       `json:"overlap"`
+	BeaconOnly  int       `json:"beacon_only"`
+	HermesOnly  int       `json:"hermes_only"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Result is the outcome of cross-checking Hermes' peers against the beacon node's.
+type Result struct {
+	Endpoint       string         `json:"endpoint"` // Beacon API, with credentials redacted
+	CheckedAt      time.Time      `json:"checked_at"`
+	BeaconPeers    int            `json:"beacon_peers"` // Peers listed by the beacon node, in any state
+	HermesPeers    int            `json:"hermes_peers"` // Peers Hermes had connected at the check
+	Overlap        int            `json:"overlap"`      // Peers known to both
+	BeaconOnly     int            `json:"beacon_only"`  // Peers the beacon node lists that Hermes never saw
+	HermesOnly     int            `json:"hermes_only"`  // Peers Hermes saw that the beacon node does not list
+	Reconciliation []StateCount   `json:"reconciliation"`
+	OneSided       []OneSidedPeer `json:"one_sided"`          // Peers connected on one side only, beacon node first
+	Snapshot       []BeaconPeer   `json:"snapshot"`           // The beacon node's peer list as fetched, by peer ID
+	Periodic       []Summary      `json:"periodic,omitempty"` // Checks taken during the run, oldest first
+	Error          string         `json:"error,omitempty"`
+}
+
+// Summary returns the counts of the check.
+func (r *Result) Summary() Summary {
+	return Summary{
+		CheckedAt:   r.CheckedAt,
+		BeaconPeers: r.BeaconPeers,
+		HermesPeers: r.HermesPeers,
+		Overlap:     r.Overlap,
+		BeaconOnly:  r.BeaconOnly,
+		HermesOnly:  r.HermesOnly,
+		Error:       r.Error,
+	}
+}
+
+// peersResponse is the body of the beacon node's /eth/v1/node/peers endpoint.
+type peersResponse struct {
+	Data []BeaconPeer `json:"data"`
+}
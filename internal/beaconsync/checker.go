@@ -0,0 +1,102 @@
+package beaconsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+)
+
+// syncingPath is the standard beacon API endpoint reporting the node's sync status.
+const syncingPath = "/eth/v1/node/syncing"
+
+// syncingResponse is the body of the syncing endpoint.
+type syncingResponse struct {
+	Data struct {
+		HeadSlot     string `json:"head_slot"`
+		SyncDistance string `json:"sync_distance"`
+		IsSyncing    bool   `json:"is_syncing"`
+		IsOptimistic bool   `json:"is_optimistic"`
+		ELOffline    bool   `json:"el_offline"`
+	} `json:"data"`
+}
+
+// Checker reads the beacon node's sync status from its beacon API.
+type Checker struct {
+	endpoint   *url.URL
+	httpClient *http.Client
+}
+
+// NewChecker creates a checker for the beacon API of a Prysm host connection string, which may
+// carry user:password@ credentials.
+func NewChecker(host string, port int, useTLS bool, timeout time.Duration) *Checker {
+	return &Checker{
+		endpoint: common.ParseBeaconEndpoint(host, port, useTLS),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Check reads the beacon node's sync status once. A failed check is recorded in the status
+// rather than returned, a beacon node that cannot be asked is unavailable to validation too.
+func (c *Checker) Check(ctx context.Context) SyncStatus {
+	status, err := c.fetch(ctx)
+	if err != nil {
+		return SyncStatus{CheckedAt: time.Now(), Error: err.Error()}
+	}
+
+	return status
+}
+
+// fetch requests and decodes the sync status.
+func (c *Checker) fetch(ctx context.Context) (SyncStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint.JoinPath(syncingPath).String(), nil)
+	if err != nil {
+		return SyncStatus{}, fmt.Errorf("failed to create beacon syncing request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return SyncStatus{}, fmt.Errorf("failed to reach beacon API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return SyncStatus{}, fmt.Errorf("beacon API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var syncing syncingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&syncing); err != nil {
+		return SyncStatus{}, fmt.Errorf("failed to decode beacon syncing status: %w", err)
+	}
+
+	headSlot, err := strconv.ParseUint(syncing.Data.HeadSlot, 10, 64)
+	if err != nil {
+		return SyncStatus{}, fmt.Errorf("invalid beacon head slot %q: %w", syncing.Data.HeadSlot, err)
+	}
+
+	distance, err := strconv.ParseUint(syncing.Data.SyncDistance, 10, 64)
+	if err != nil {
+		return SyncStatus{}, fmt.Errorf("invalid beacon sync distance %q: %w", syncing.Data.SyncDistance, err)
+	}
+
+	return SyncStatus{
+		CheckedAt:    time.Now(),
+		HeadSlot:     headSlot,
+		SyncDistance: distance,
+		IsSyncing:    syncing.Data.IsSyncing,
+		IsOptimistic: syncing.Data.IsOptimistic,
+		ELOffline:    syncing.Data.ELOffline,
+	}, nil
+}
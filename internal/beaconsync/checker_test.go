@@ -0,0 +1,50 @@
+package beaconsync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	status := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != syncingPath {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"data":{"head_slot":"1000","sync_distance":"12","is_syncing":true,"is_optimistic":false,"el_offline":true}}`))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+
+	checker := NewChecker("user:pass@"+serverURL.Hostname(), port, false, time.Second)
+
+	got := checker.Check(context.Background())
+	if got.Error != "" || got.HeadSlot != 1000 || got.SyncDistance != 12 || !got.IsSyncing || !got.ELOffline {
+		t.Errorf("Unexpected sync status %+v", got)
+	}
+
+	status = http.StatusServiceUnavailable
+
+	if got := checker.Check(context.Background()); got.Error == "" {
+		t.Error("Expected a failed check to record its error")
+	}
+}
@@ -0,0 +1,269 @@
+// Package beaconsync guards delegated validation against a beacon node that is not synced or
+// has pruned the history peers ask for. Its failures are our own, so the periods the beacon
+// node was degraded are recorded and the peers are not scored for them.
+package beaconsync
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// failureKey identifies a failure count by its kind and protocol.
+type failureKey struct {
+	kind     string
+	protocol string
+}
+
+// Tracker records the beacon node's failures and the windows it was degraded in. Every failure
+// keeps the beacon node degraded for the grace after it, so failures close together form one
+// window. It is safe for concurrent use.
+type Tracker struct {
+	grace  time.Duration
+	logger logrus.FieldLogger
+
+	mu          sync.Mutex
+	windows     []Window // In the order they started, never overlapping
+	failures    map[failureKey]*FailureCount
+	checks      int
+	notSynced   int
+	maxDistance uint64
+	checkErr    string
+}
+
+// NewTracker creates a tracker keeping the beacon node degraded for grace after each failure.
+func NewTracker(grace time.Duration, logger logrus.FieldLogger) *Tracker {
+	return &Tracker{
+		grace:    grace,
+		logger:   logger.WithField("component", "beacon_sync"),
+		failures: make(map[failureKey]*FailureCount),
+	}
+}
+
+// Classify returns the kind of beacon node failure the error Hermes traced for a request is,
+// empty when the request failed for another reason. Hermes forwards block and blob requests to
+// the beacon node, failing to open the stream to it when it is unreachable.
+func Classify(reason string) string {
+	lower := strings.ToLower(reason)
+
+	switch {
+	case lower == "":
+		return ""
+	case strings.Contains(lower, "syncing"), strings.Contains(lower, "not synced"), strings.Contains(lower, "optimistic"):
+		return FailureNotSynced
+	case strings.Contains(lower, "resource unavailable"), strings.Contains(lower, "pruned"), strings.Contains(lower, "no longer available"):
+		return FailureMissingHistory
+	case strings.Contains(lower, "downstream"):
+		return FailureUnavailable
+	default:
+		return ""
+	}
+}
+
+// RecordRequest records a request Hermes failed to handle, from its trace event type and
+// error. It returns the kind of beacon node failure, empty when the beacon node was not to
+// blame.
+func (t *Tracker) RecordRequest(protocol, reason string, at time.Time) string {
+	kind := Classify(reason)
+	if kind == "" {
+		return ""
+	}
+
+	t.fail(kind, protocol, reason, at)
+
+	return kind
+}
+
+// RecordSync records a check of the beacon node's sync status. A failed check counts the
+// beacon node unavailable, one finding it syncing, optimistic, without its execution client or
+// too far behind counts it not synced.
+func (t *Tracker) RecordSync(status SyncStatus) {
+	t.mu.Lock()
+	t.checks++
+	t.maxDistance = max(t.maxDistance, status.SyncDistance)
+
+	if status.Error != "" {
+		t.checkErr = status.Error
+	}
+
+	notSynced := status.Error == "" && (status.IsSyncing || status.IsOptimistic || status.ELOffline ||
+		status.SyncDistance > constants.BeaconSyncMaxDistance)
+	if notSynced {
+		t.notSynced++
+	}
+	t.mu.Unlock()
+
+	switch {
+	case status.Error != "":
+		t.fail(FailureUnavailable, ProtocolSyncCheck, status.Error, status.CheckedAt)
+	case notSynced:
+		t.fail(FailureNotSynced, ProtocolSyncCheck, syncReason(status), status.CheckedAt)
+	}
+}
+
+// syncReason describes why a sync status counts as not synced.
+func syncReason(status SyncStatus) string {
+	reasons := make([]string, 0, 3)
+
+	if status.IsSyncing {
+		reasons = append(reasons, "syncing")
+	}
+
+	if status.IsOptimistic {
+		reasons = append(reasons, "optimistic")
+	}
+
+	if status.ELOffline {
+		reasons = append(reasons, "execution client offline")
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "behind")
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+// fail counts a failure and degrades the beacon node from at until the grace after it.
+func (t *Tracker) fail(kind, protocol, reason string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := failureKey{kind: kind, protocol: protocol}
+
+	count, ok := t.failures[key]
+	if !ok {
+		count = &FailureCount{Kind: kind, Protocol: protocol}
+		t.failures[key] = count
+	}
+
+	count.Count++
+	count.LastError = reason
+
+	window := Window{From: at, To: at.Add(t.grace), Kinds: []string{kind}, Failures: 1}
+
+	// Fold the failure into the windows it overlaps, failures may arrive slightly out of order
+	merged := make([]Window, 0, len(t.windows)+1)
+
+	for _, existing := range t.windows {
+		if existing.To.Before(window.From) || window.To.Before(existing.From) {
+			merged = append(merged, existing)
+
+			continue
+		}
+
+		if existing.From.Before(window.From) {
+			window.From = existing.From
+		}
+
+		if existing.To.After(window.To) {
+			window.To = existing.To
+		}
+
+		window.Failures += existing.Failures
+		window.Kinds = mergeKinds(existing.Kinds, window.Kinds)
+	}
+
+	opened := window.Failures == 1
+
+	merged = append(merged, window)
+	sort.Slice(merged, func(i, j int) bool { return merged[i].From.Before(merged[j].From) })
+	t.windows = merged
+
+	if opened {
+		t.logger.WithFields(logrus.Fields{
+			"kind":     kind,
+			"protocol": protocol,
+			"error":    reason,
+		}).Warn("Beacon node behind delegated validation is degraded, peer scores are not attributed until it recovers")
+	}
+}
+
+// mergeKinds returns the kinds of both lists, sorted and without repeats.
+func mergeKinds(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	kinds := make([]string, 0, len(a)+len(b))
+
+	for _, kind := range append(append([]string(nil), a...), b...) {
+		if !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+// Degraded reports whether the beacon node was degraded at the given time.
+func (t *Tracker) Degraded(at time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, window := range t.windows {
+		if !at.Before(window.From) && !at.After(window.To) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Summary returns the failures recorded so far and the degraded windows, cut off at the end
+// of the run.
+func (t *Tracker) Summary(end time.Time) *Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summary := &Summary{
+		Health:           HealthHealthy,
+		ByKind:           make([]FailureCount, 0, len(t.failures)),
+		SyncChecks:       t.checks,
+		NotSynced:        t.notSynced,
+		MaxSyncDistance:  t.maxDistance,
+		GraceSeconds:     t.grace.Seconds(),
+		Windows:          make([]Window, 0, len(t.windows)),
+		LastSyncCheckErr: t.checkErr,
+	}
+
+	for _, count := range t.failures {
+		summary.Failures += count.Count
+		summary.ByKind = append(summary.ByKind, *count)
+	}
+
+	sort.Slice(summary.ByKind, func(i, j int) bool {
+		a, b := summary.ByKind[i], summary.ByKind[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+
+		return a.Protocol < b.Protocol
+	})
+
+	for _, window := range t.windows {
+		window.Resolved = !window.To.After(end)
+		if !window.Resolved {
+			window.To = end
+		}
+
+		window.Seconds = max(window.To.Sub(window.From).Seconds(), 0)
+		summary.DegradedSeconds += window.Seconds
+		summary.Windows = append(summary.Windows, window)
+	}
+
+	if len(summary.Windows) > 0 {
+		summary.Health = HealthDegraded
+	}
+
+	return summary
+}
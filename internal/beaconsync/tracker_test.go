@@ -0,0 +1,93 @@
+package beaconsync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   string
+	}{
+		{"", ""},
+		{"new stream to downstream host: failed to dial", FailureUnavailable},
+		{"node is currently syncing", FailureNotSynced},
+		{"head is optimistic", FailureNotSynced},
+		{"resource unavailable: blobs pruned", FailureMissingHistory},
+		{"read request data: snappy: corrupt input", ""},
+		{"stream reset", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Classify(tt.reason); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestTrackerWindows(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(time.Minute, logger)
+
+	if kind := tracker.RecordRequest("HANDLE_STATUS", "stream reset", start); kind != "" {
+		t.Fatalf("Expected a peer's failure not to count, got %q", kind)
+	}
+
+	// Two failures within the grace form one window, a later one opens another
+	tracker.RecordRequest("HANDLE_BEACON_BLOCKS_BY_RANGE", "new stream to downstream host: refused", start)
+	tracker.RecordRequest("HANDLE_BLOB_SIDECARS_BY_RANGE", "resource unavailable", start.Add(30*time.Second))
+	tracker.RecordSync(SyncStatus{CheckedAt: start.Add(5 * time.Minute), SyncDistance: 40})
+
+	if !tracker.Degraded(start.Add(80*time.Second)) || tracker.Degraded(start.Add(2*time.Minute)) {
+		t.Error("Expected the beacon node degraded until the grace after the last failure of the first window only")
+	}
+
+	summary := tracker.Summary(start.Add(5*time.Minute + 20*time.Second))
+
+	if summary.Health != HealthDegraded || summary.Failures != 3 || summary.SyncChecks != 1 || summary.NotSynced != 1 || summary.MaxSyncDistance != 40 {
+		t.Fatalf("Unexpected summary %+v", summary)
+	}
+
+	if len(summary.Windows) != 2 {
+		t.Fatalf("Expected 2 windows, got %+v", summary.Windows)
+	}
+
+	first, last := summary.Windows[0], summary.Windows[1]
+
+	if first.Failures != 2 || first.Seconds != 90 || !first.Resolved || len(first.Kinds) != 2 {
+		t.Errorf("Unexpected first window %+v", first)
+	}
+
+	if last.Failures != 1 || last.Seconds != 20 || last.Resolved || last.Kinds[0] != FailureNotSynced {
+		t.Errorf("Expected the last window cut off unresolved at the end of the run, got %+v", last)
+	}
+
+	if summary.DegradedSeconds != 110 {
+		t.Errorf("Expected 110 degraded seconds, got %v", summary.DegradedSeconds)
+	}
+}
+
+func TestTrackerHealthy(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	tracker := NewTracker(time.Minute, logger)
+	tracker.RecordSync(SyncStatus{CheckedAt: time.Now(), SyncDistance: 1})
+
+	summary := tracker.Summary(time.Now())
+	if summary.Health != HealthHealthy || summary.Failures != 0 || len(summary.Windows) != 0 {
+		t.Errorf("Expected a synced beacon node healthy, got %+v", summary)
+	}
+
+	tracker.RecordSync(SyncStatus{CheckedAt: time.Now(), Error: "connection refused"})
+
+	if summary := tracker.Summary(time.Now()); summary.ByKind[0].Kind != FailureUnavailable || summary.LastSyncCheckErr != "connection refused" {
+		t.Errorf("Expected a failed check counted unavailable, got %+v", summary)
+	}
+}
@@ -0,0 +1,66 @@
+package beaconsync
+
+import "time"
+
+// Health of the beacon node behind delegated validation.
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded" // The beacon node failed requests or was not synced for a while
+)
+
+// Kinds of beacon node failure.
+const (
+	FailureNotSynced      = "not_synced"      // The beacon node was syncing, optimistic or without its execution client
+	FailureMissingHistory = "missing_history" // The beacon node had pruned the data asked for
+	FailureUnavailable    = "unavailable"     // The beacon node could not be reached
+)
+
+// ProtocolSyncCheck names the tool's own sync status checks among the failed requests.
+const ProtocolSyncCheck = "SYNC_CHECK"
+
+// SyncStatus is one check of the beacon node's sync status.
+type SyncStatus struct {
+	CheckedAt    time.Time `json:"checked_at"`
+	HeadSlot     uint64    `json:"head_slot"`
+	SyncDistance uint64    `json:"sync_distance"`
+	IsSyncing    bool      `json:"is_syncing"`
+	IsOptimistic bool      `json:"is_optimistic"`
+	ELOffline    bool      `json:"el_offline"`
+	Error        string    `json:"error,omitempty"` // The check failed, the beacon node counts as unavailable
+}
+
+// Window is a period the beacon node was degraded. Score snapshots taken within it are not
+// attributed to the peers, whose gossip our validation may have misjudged.
+type Window struct {
+	From     time.Time `json:"from"` // First failure
+	To       time.Time `json:"to"`   // Last failure and the grace after it, or the end of the run
+	Seconds  float64   `json:"seconds"`
+	Kinds    []string  `json:"kinds"` // Failure kinds seen within the window
+	Failures int       `json:"failures"`
+	Resolved bool      `json:"resolved"` // The beacon node recovered before the run ended
+}
+
+// FailureCount counts the failures of one kind on one protocol.
+type FailureCount struct {
+	Kind      string `json:"kind"`
+	Protocol  string `json:"protocol"` // Hermes's trace event type, or SYNC_CHECK
+	Count     int    `json:"count"`
+	LastError string `json:"last_error"`
+}
+
+// Summary is the health of the beacon node delegated validation relies on. A beacon node that
+// is not synced or has pruned history fails the requests Hermes forwards and misjudges gossip,
+// which peers would otherwise be penalised for in this report.
+type Summary struct {
+	Health           string         `json:"health"`
+	Failures         int            `json:"failures"`
+	ByKind           []FailureCount `json:"by_kind"` // Most frequent first
+	SyncChecks       int            `json:"sync_checks"`
+	NotSynced        int            `json:"not_synced"` // Sync checks finding the beacon node not synced
+	MaxSyncDistance  uint64         `json:"max_sync_distance"`
+	GraceSeconds     float64        `json:"grace_seconds"` // How long a failure keeps the beacon node degraded
+	Windows          []Window       `json:"windows"`
+	DegradedSeconds  float64        `json:"degraded_seconds"`
+	PausedSnapshots  int            `json:"paused_snapshots"` // Score snapshots left out of the peers' score summaries
+	LastSyncCheckErr string         `json:"last_sync_check_error,omitempty"`
+}
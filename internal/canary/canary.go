@@ -0,0 +1,215 @@
+// Package canary stages peers with known gossip behaviour against the primary host, so the
+// scores it records for them can check the measurement pipeline end to end. A canary is an
+// in-process libp2p host that answers Hermes's status handshake, subscribes to one gossip topic
+// and behaves as its behaviour says there.
+package canary
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p"
+	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p/encoder"
+	ethpb "github.com/OffchainLabs/prysm/v6/proto/prysm/v1alpha1"
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Canary is one in-process peer staging a behaviour towards the primary host.
+type Canary struct {
+	behaviour string
+	target    func() *peer.AddrInfo
+	host      host.Host
+	topic     *pubsub.Topic
+	sub       *pubsub.Subscription
+	logger    logrus.FieldLogger
+
+	published atomic.Int64
+}
+
+// New creates a canary staging behaviour on the gossip topic towards the primary host, whose
+// address target returns, nil while it has none. The address is asked for on every redial, as
+// a restarted node may have a new identity. The canary does not listen, it dials the primary
+// host once Run is called. Close releases its host.
+func New(ctx context.Context, behaviour string, target func() *peer.AddrInfo, topic string, logger logrus.FieldLogger) (*Canary, error) {
+	if behaviour != BehaviourHonest && behaviour != BehaviourInvalid {
+		return nil, fmt.Errorf("unknown canary behaviour %q", behaviour)
+	}
+
+	h, err := libp2p.New(
+		libp2p.NoListenAddrs,
+		libp2p.UserAgent(constants.CanaryAgentPrefix+behaviour),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create canary host: %w", err)
+	}
+
+	c := &Canary{
+		behaviour: behaviour,
+		target:    target,
+		host:      h,
+		logger: logger.WithFields(logrus.Fields{
+			"component": "canary",
+			"behaviour": behaviour,
+			"canary_id": h.ID().String(),
+		}),
+	}
+
+	// Hermes drops peers that do not answer its status request
+	h.SetStreamHandler(protocol.ID(p2p.RPCStatusTopicV1+encoder.SszNetworkEncoder{}.ProtocolSuffix()), c.handleStatus)
+
+	// Hermes's gossip is unsigned and without authors, so messages are told apart by content
+	ps, err := pubsub.NewGossipSub(ctx, h,
+		pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign),
+		pubsub.WithNoAuthor(),
+		pubsub.WithMessageIdFn(messageID),
+	)
+	if err != nil {
+		_ = h.Close()
+
+		return nil, fmt.Errorf("failed to create canary gossipsub: %w", err)
+	}
+
+	if c.topic, err = ps.Join(topic); err != nil {
+		_ = h.Close()
+
+		return nil, fmt.Errorf("failed to join %s: %w", topic, err)
+	}
+
+	if c.sub, err = c.topic.Subscribe(); err != nil {
+		_ = h.Close()
+
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	return c, nil
+}
+
+// Staged describes the canary for Verify.
+func (c *Canary) Staged() Staged {
+	return Staged{
+		Behaviour: c.behaviour,
+		PeerID:    c.host.ID().String(),
+		Published: int(c.published.Load()),
+	}
+}
+
+// Run keeps the canary connected to the primary host and stages its behaviour until ctx is done.
+func (c *Canary) Run(ctx context.Context) {
+	go c.drain(ctx)
+
+	ticker := time.NewTicker(constants.CanaryInterval)
+	defer ticker.Stop()
+
+	for {
+		if target := c.target(); target == nil {
+			c.logger.Debug("Primary host has no address yet")
+		} else if c.host.Network().Connectedness(target.ID) != network.Connected {
+			c.connect(ctx, *target)
+		} else if c.behaviour == BehaviourInvalid {
+			c.publishInvalid(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close shuts the canary's host down.
+func (c *Canary) Close() error {
+	c.sub.Cancel()
+
+	return c.host.Close()
+}
+
+// connect dials the primary host, which may not be listening yet early in the run.
+func (c *Canary) connect(ctx context.Context, target peer.AddrInfo) {
+	dialCtx, cancel := context.WithTimeout(ctx, constants.DefaultDialTimeout)
+	defer cancel()
+
+	if err := c.host.Connect(dialCtx, target); err != nil {
+		c.logger.WithError(err).Debug("Canary could not connect to the primary host, retrying")
+
+		return
+	}
+
+	c.logger.Info("Canary connected to the primary host")
+}
+
+// publishInvalid publishes one message that does not decode as the topic's type, which the
+// primary host's validation must reject and count against the canary.
+func (c *Canary) publishInvalid(ctx context.Context) {
+	// The primary host has to have told us it is on the topic, or there is no one to publish to
+	publishCtx, cancel := context.WithTimeout(ctx, constants.CanaryInterval)
+	defer cancel()
+
+	count := c.published.Load() + 1
+	payload := fmt.Appendf(nil, "hermes-peer-score canary invalid message %d at %d", count, time.Now().UnixNano())
+
+	if err := c.topic.Publish(publishCtx, payload, pubsub.WithReadiness(pubsub.MinTopicSize(1))); err != nil {
+		c.logger.WithError(err).Debug("Canary could not publish")
+
+		return
+	}
+
+	c.published.Store(count)
+}
+
+// drain reads and discards the messages the primary host forwards, so the subscription's
+// buffer never fills.
+func (c *Canary) drain(ctx context.Context) {
+	for {
+		if _, err := c.sub.Next(ctx); err != nil {
+			return
+		}
+	}
+}
+
+// handleStatus answers Hermes's status request with its own status, so the canary looks like
+// a peer on the same chain with the same head.
+func (c *Canary) handleStatus(stream network.Stream) {
+	defer stream.Close()
+
+	sszEncoder := encoder.SszNetworkEncoder{}
+	status := &ethpb.Status{}
+
+	if err := sszEncoder.DecodeWithMaxLength(stream, status); err != nil {
+		c.logger.WithError(err).Debug("Canary could not read the status request")
+		_ = stream.Reset()
+
+		return
+	}
+
+	// A zero result code, then the response
+	if _, err := stream.Write([]byte{0}); err != nil {
+		_ = stream.Reset()
+
+		return
+	}
+
+	if _, err := sszEncoder.EncodeWithMaxLength(stream, status); err != nil {
+		c.logger.WithError(err).Debug("Canary could not answer the status request")
+		_ = stream.Reset()
+	}
+}
+
+// messageID identifies a message by its topic and content.
+func messageID(msg *pubsubpb.Message) string {
+	sum := sha256.Sum256(append([]byte(msg.GetTopic()), msg.GetData()...))
+
+	return string(sum[:20])
+}
@@ -0,0 +1,77 @@
+package canary
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p"
+	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p/encoder"
+	ethpb "github.com/OffchainLabs/prysm/v6/proto/prysm/v1alpha1"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCanaryAnswersStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	// Stands in for Hermes, which asks every new peer for its status
+	primary, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	if err != nil {
+		t.Fatalf("Expected no error creating the primary host, got %v", err)
+	}
+	defer primary.Close()
+
+	target := &peer.AddrInfo{ID: primary.ID(), Addrs: primary.Addrs()}
+
+	c, err := New(ctx, BehaviourHonest, func() *peer.AddrInfo { return target }, "/eth2/4a26c58b/beacon_block/ssz_snappy", logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating the canary, got %v", err)
+	}
+	defer c.Close()
+
+	c.connect(ctx, *target)
+
+	stream, err := primary.NewStream(ctx, c.host.ID(), protocol.ID(p2p.RPCStatusTopicV1+encoder.SszNetworkEncoder{}.ProtocolSuffix()))
+	if err != nil {
+		t.Fatalf("Expected the canary to accept a status stream, got %v", err)
+	}
+	defer stream.Close()
+
+	sent := &ethpb.Status{
+		ForkDigest:     []byte{0x4a, 0x26, 0xc5, 0x8b},
+		FinalizedRoot:  bytes.Repeat([]byte{1}, 32),
+		FinalizedEpoch: 368748,
+		HeadRoot:       bytes.Repeat([]byte{2}, 32),
+		HeadSlot:       11800000,
+	}
+
+	if _, err := (encoder.SszNetworkEncoder{}).EncodeWithMaxLength(stream, sent); err != nil {
+		t.Fatalf("Expected no error writing the status request, got %v", err)
+	}
+
+	if err := stream.CloseWrite(); err != nil {
+		t.Fatalf("Expected no error closing the request, got %v", err)
+	}
+
+	code := make([]byte, 1)
+	if _, err := io.ReadFull(stream, code); err != nil || code[0] != 0 {
+		t.Fatalf("Expected a zero result code, got %v (%v)", code, err)
+	}
+
+	received := &ethpb.Status{}
+	if err := (encoder.SszNetworkEncoder{}).DecodeWithMaxLength(stream, received); err != nil {
+		t.Fatalf("Expected no error reading the status response, got %v", err)
+	}
+
+	if received.HeadSlot != sent.HeadSlot || !bytes.Equal(received.ForkDigest, sent.ForkDigest) {
+		t.Errorf("Expected the canary to echo the status, got head slot %d and fork digest %x", received.HeadSlot, received.ForkDigest)
+	}
+}
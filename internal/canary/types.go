@@ -0,0 +1,53 @@
+package canary
+
+// Behaviours a canary stages towards the primary host.
+const (
+	BehaviourHonest  = "honest"  // Subscribes to the topic and never sends anything invalid
+	BehaviourInvalid = "invalid" // Publishes messages on the topic that do not decode
+)
+
+// Outcomes of the self-test.
+const (
+	StatusPassed = "passed"
+	StatusFailed = "failed" // The primary host did not score at least one canary as expected
+)
+
+// Staged is a canary that ran against the primary host, as handed to Verify.
+type Staged struct {
+	Behaviour string `json:"behaviour"`
+	PeerID    string `json:"peer_id"`
+	Published int    `json:"published"` // Messages the canary published
+}
+
+// Check is one expectation of the primary host's scoring of a canary.
+type Check struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Observed string `json:"observed"`
+	Passed   bool   `json:"passed"`
+}
+
+// Outcome is how the primary host scored one canary.
+type Outcome struct {
+	Staged
+
+	Sessions          int     `json:"sessions"`
+	Snapshots         int     `json:"snapshots"`                   // Score snapshots the primary host took of the canary
+	FinalScore        float64 `json:"final_score"`                 // In the last snapshot
+	LowestScore       float64 `json:"lowest_score"`                // Across all snapshots
+	InvalidDeliveries float64 `json:"invalid_deliveries"`          // Most counted in one snapshot, summed over topics
+	BehaviourPenalty  float64 `json:"behaviour_penalty"`           // Most counted in one snapshot
+	CorruptSnapshots  int     `json:"corrupt_snapshots,omitempty"` // Snapshots whose topic scores could not be read, left out of InvalidDeliveries
+	Checks            []Check `json:"checks"`
+	Passed            bool    `json:"passed"`
+}
+
+// Result is the self-test of the measurement pipeline. Canaries with known behaviour connect
+// to the primary host, and the scores it recorded for them are checked against what that
+// behaviour must earn, so a report whose own canaries were misjudged is not trusted blindly.
+type Result struct {
+	Status   string    `json:"status"`
+	Topic    string    `json:"topic"` // The gossip topic the canaries subscribed and published to
+	Canaries []Outcome `json:"canaries"`
+	Failed   int       `json:"failed"` // Canaries with at least one failed check
+}
@@ -0,0 +1,148 @@
+package canary
+
+import (
+	"fmt"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// Verify checks the scores the primary host recorded for each staged canary against its
+// behaviour. A canary the primary host never took a score snapshot of fails, as the pipeline
+// did not see a peer it certainly had.
+func Verify(topic string, staged []Staged, peers map[string]*peer.Stats) *Result {
+	result := &Result{
+		Status:   StatusPassed,
+		Topic:    topic,
+		Canaries: make([]Outcome, 0, len(staged)),
+	}
+
+	for _, canary := range staged {
+		outcome := observe(canary, peers[canary.PeerID])
+		outcome.Checks = expectations(outcome)
+
+		outcome.Passed = true
+		for _, check := range outcome.Checks {
+			outcome.Passed = outcome.Passed && check.Passed
+		}
+
+		if !outcome.Passed {
+			result.Failed++
+			result.Status = StatusFailed
+		}
+
+		result.Canaries = append(result.Canaries, outcome)
+	}
+
+	return result
+}
+
+// observe collects the primary host's score snapshots of a canary.
+func observe(canary Staged, stats *peer.Stats) Outcome {
+	outcome := Outcome{Staged: canary}
+	if stats == nil {
+		return outcome
+	}
+
+	outcome.Sessions = len(stats.ConnectionSessions)
+
+	for _, session := range stats.ConnectionSessions {
+		for i := range session.PeerScores {
+			snapshot := &session.PeerScores[i]
+
+			if outcome.Snapshots == 0 || snapshot.Score < outcome.LowestScore {
+				outcome.LowestScore = snapshot.Score
+			}
+
+			outcome.Snapshots++
+			outcome.FinalScore = snapshot.Score
+			outcome.BehaviourPenalty = max(outcome.BehaviourPenalty, snapshot.BehaviourPenalty)
+
+			topics, err := snapshot.TopicScores()
+			if err != nil {
+				outcome.CorruptSnapshots++
+
+				continue
+			}
+
+			invalid := 0.0
+			for _, topic := range topics {
+				invalid += topic.InvalidMessageDeliveries
+			}
+
+			outcome.InvalidDeliveries = max(outcome.InvalidDeliveries, invalid)
+		}
+	}
+
+	return outcome
+}
+
+// expectations returns the checks of a canary's scores for its behaviour.
+func expectations(outcome Outcome) []Check {
+	checks := []Check{{
+		Name:     "scored",
+		Expected: "at least one score snapshot",
+		Observed: fmt.Sprintf("%d snapshots in %d sessions", outcome.Snapshots, outcome.Sessions),
+		Passed:   outcome.Snapshots > 0,
+	}}
+
+	if outcome.Snapshots == 0 {
+		return checks
+	}
+
+	// Invalid deliveries are read from the topic scores, so unreadable ones could hide them
+	if outcome.CorruptSnapshots > 0 {
+		checks = append(checks, Check{
+			Name:     "topic scores readable",
+			Expected: "every snapshot",
+			Observed: fmt.Sprintf("%d of %d unreadable", outcome.CorruptSnapshots, outcome.Snapshots),
+		})
+	}
+
+	switch outcome.Behaviour {
+	case BehaviourHonest:
+		checks = append(checks,
+			Check{
+				Name:     "no invalid deliveries",
+				Expected: "0",
+				Observed: fmt.Sprintf("%.2f", outcome.InvalidDeliveries),
+				Passed:   outcome.InvalidDeliveries == 0,
+			},
+			Check{
+				Name:     "no behaviour penalty",
+				Expected: "0",
+				Observed: fmt.Sprintf("%.2f", outcome.BehaviourPenalty),
+				Passed:   outcome.BehaviourPenalty == 0,
+			},
+			Check{
+				Name:     "score not negative",
+				Expected: ">= 0",
+				Observed: fmt.Sprintf("lowest %.2f", outcome.LowestScore),
+				Passed:   outcome.LowestScore >= 0,
+			},
+		)
+	case BehaviourInvalid:
+		// Nothing published means the canary never found the primary host on its topic
+		checks = append(checks,
+			Check{
+				Name:     "published",
+				Expected: "at least one message",
+				Observed: fmt.Sprintf("%d", outcome.Published),
+				Passed:   outcome.Published > 0,
+			},
+			Check{
+				Name:     "invalid deliveries counted",
+				Expected: "> 0",
+				Observed: fmt.Sprintf("%.2f", outcome.InvalidDeliveries),
+				Passed:   outcome.InvalidDeliveries > 0,
+			},
+			Check{
+				Name:     "score negative",
+				Expected: "< 0",
+				Observed: fmt.Sprintf("lowest %.2f", outcome.LowestScore),
+				Passed:   outcome.LowestScore < 0,
+			},
+		)
+	}
+
+	return checks
+}
@@ -0,0 +1,125 @@
+package canary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func scored(scores []float64, invalid float64, penalty float64) *peer.Stats {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshots := make([]peer.PeerScoreSnapshot, 0, len(scores))
+
+	for i, score := range scores {
+		snapshots = append(snapshots, peer.PeerScoreSnapshot{
+			Timestamp:        start.Add(time.Duration(i) * 5 * time.Second),
+			Score:            score,
+			BehaviourPenalty: penalty,
+			Topics: []peer.TopicScore{
+				{Topic: "/eth2/4a26c58b/beacon_block/ssz_snappy", InvalidMessageDeliveries: invalid},
+				{Topic: "/eth2/4a26c58b/voluntary_exit/ssz_snappy"},
+			},
+		})
+	}
+
+	return &peer.Stats{ConnectionSessions: []peer.ConnectionSession{{PeerScores: snapshots}}}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		staged   Staged
+		stats    *peer.Stats
+		passed   bool
+		failures []string
+	}{
+		{
+			name:   "honest canary scored well",
+			staged: Staged{Behaviour: BehaviourHonest, PeerID: "canary"},
+			stats:  scored([]float64{0, 0.5, 1}, 0, 0),
+			passed: true,
+		},
+		{
+			name:     "honest canary penalised",
+			staged:   Staged{Behaviour: BehaviourHonest, PeerID: "canary"},
+			stats:    scored([]float64{0, -10, -20}, 2, 0),
+			failures: []string{"no invalid deliveries", "score not negative"},
+		},
+		{
+			name:     "honest canary with behaviour penalty",
+			staged:   Staged{Behaviour: BehaviourHonest, PeerID: "canary"},
+			stats:    scored([]float64{0, 0.5}, 0, 1),
+			failures: []string{"no behaviour penalty"},
+		},
+		{
+			name:   "invalid canary penalised",
+			staged: Staged{Behaviour: BehaviourInvalid, PeerID: "canary", Published: 5},
+			stats:  scored([]float64{0, -140, -280}, 2, 0),
+			passed: true,
+		},
+		{
+			name:     "invalid canary not penalised",
+			staged:   Staged{Behaviour: BehaviourInvalid, PeerID: "canary", Published: 5},
+			stats:    scored([]float64{0, 0.5}, 0, 0),
+			failures: []string{"invalid deliveries counted", "score negative"},
+		},
+		{
+			name:     "invalid canary never published",
+			staged:   Staged{Behaviour: BehaviourInvalid, PeerID: "canary"},
+			stats:    scored([]float64{0}, 0, 0),
+			failures: []string{"published", "invalid deliveries counted", "score negative"},
+		},
+		{
+			name:     "canary never scored",
+			staged:   Staged{Behaviour: BehaviourHonest, PeerID: "canary"},
+			failures: []string{"scored"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peers := map[string]*peer.Stats{}
+			if tt.stats != nil {
+				peers[tt.staged.PeerID] = tt.stats
+			}
+
+			result := Verify("/eth2/4a26c58b/beacon_block/ssz_snappy", []Staged{tt.staged}, peers)
+
+			if len(result.Canaries) != 1 {
+				t.Fatalf("Expected 1 canary, got %d", len(result.Canaries))
+			}
+
+			outcome := result.Canaries[0]
+			if outcome.Passed != tt.passed {
+				t.Errorf("Expected passed %v, got %v", tt.passed, outcome.Passed)
+			}
+
+			wantStatus, wantFailed := StatusPassed, 0
+			if !tt.passed {
+				wantStatus, wantFailed = StatusFailed, 1
+			}
+
+			if result.Status != wantStatus || result.Failed != wantFailed {
+				t.Errorf("Expected status %s with %d failed, got %s with %d", wantStatus, wantFailed, result.Status, result.Failed)
+			}
+
+			failures := make([]string, 0)
+			for _, check := range outcome.Checks {
+				if !check.Passed {
+					failures = append(failures, check.Name)
+				}
+			}
+
+			if len(failures) != len(tt.failures) {
+				t.Fatalf("Expected failed checks %v, got %v", tt.failures, failures)
+			}
+
+			for i := range failures {
+				if failures[i] != tt.failures[i] {
+					t.Errorf("Expected failed checks %v, got %v", tt.failures, failures)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// FormatVersion is bumped whenever the checkpoint layout changes incompatibly.
+const FormatVersion = 1
+
+// Checkpoint is the collector state periodically written to disk, so a run that crashes
+// or is stopped by a host reboot can be resumed rather than restarted.
+type Checkpoint struct {
+	Version        int                       `json:"version"`
+	SavedAt        time.Time                 `json:"saved_at"`
+	ValidationMode string                    `json:"validation_mode"`
+	Network        string                    `json:"network"`
+	StartTime      time.Time                 `json:"start_time"`
+	Phases         *peer.RunPhases           `json:"phases"`
+	Gaps           []peer.RunGap             `json:"gaps,omitempty"`
+	Peers          map[string]*peer.Stats    `json:"peers"`
+	EventCounts    map[string]map[string]int `json:"event_counts"`
+	EventTimeline  *peer.EventTimeline       `json:"event_timeline,omitempty"`
+}
+
+// Save writes the checkpoint atomically, so a crash mid-write leaves the previous one intact.
+func Save(path string, cp *Checkpoint) error {
+	cp.Version = FormatVersion
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	// Flush to disk before the rename, a reboot must not leave an empty checkpoint behind
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to sync checkpoint: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close checkpoint: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), constants.DefaultFilePermissions); err != nil {
+		return fmt.Errorf("failed to set checkpoint permissions: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a checkpoint written by Save.
+func Load(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	if cp.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d, expected %d", cp.Version, FormatVersion)
+	}
+
+	if cp.Phases == nil {
+		return nil, fmt.Errorf("checkpoint has no run phases")
+	}
+
+	return &cp, nil
+}
+
+// Resume prepares a loaded checkpoint to continue at the given time. The time since the
+// checkpoint was saved is recorded as a gap, and sessions open at the checkpoint are
+// closed at it, since their disconnects were never observed.
+func (cp *Checkpoint) Resume(at time.Time) peer.RunGap {
+	gap := peer.NewRunGap(cp.SavedAt, at)
+	cp.Gaps = append(cp.Gaps, gap)
+
+	for _, stats := range cp.Peers {
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			if session.Disconnected {
+				continue
+			}
+
+			closedAt := cp.SavedAt
+			session.Disconnected = true
+			session.DisconnectedAt = &closedAt
+			session.EndedByGap = true
+
+			if session.ConnectedAt != nil {
+				duration := closedAt.Sub(*session.ConnectedAt)
+				session.Duration = &duration
+			}
+		}
+	}
+
+	return gap
+}
@@ -0,0 +1,155 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestSaveLoad(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	connected := start.Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		content string // Written instead of a saved checkpoint when set
+		wantErr string
+	}{
+		{
+			name: "roundtrip",
+		},
+		{
+			name:    "unsupported version",
+			content: `{"version": 99, "phases": {}}`,
+			wantErr: "unsupported checkpoint version",
+		},
+		{
+			name:    "missing phases",
+			content: `{"version": 1}`,
+			wantErr: "no run phases",
+		},
+		{
+			name:    "corrupt file",
+			content: `{"version": 1,`,
+			wantErr: "failed to parse checkpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatalf("failed to write checkpoint: %v", err)
+				}
+			} else {
+				err := Save(path, &Checkpoint{
+					SavedAt:        start.Add(5 * time.Minute),
+					ValidationMode: "delegated",
+					Network:        "mainnet",
+					StartTime:      start,
+					Phases:         peer.NewRunPhases(start, 0, time.Hour, 0),
+					Peers: map[string]*peer.Stats{
+						"a": {PeerID: "a", ConnectionSessions: []peer.ConnectionSession{{ConnectedAt: &connected}}},
+					},
+					EventCounts: map[string]map[string]int{"a": {"CONNECTED": 1}},
+				})
+				if err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+			}
+
+			cp, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if cp.Version != FormatVersion || cp.Network != "mainnet" || !cp.StartTime.Equal(start) {
+				t.Errorf("Load() = version %d, network %q, start %v", cp.Version, cp.Network, cp.StartTime)
+			}
+
+			if got := cp.EventCounts["a"]["CONNECTED"]; got != 1 {
+				t.Errorf("event count = %d, want 1", got)
+			}
+
+			if len(cp.Peers["a"].ConnectionSessions) != 1 {
+				t.Errorf("sessions = %d, want 1", len(cp.Peers["a"].ConnectionSessions))
+			}
+		})
+	}
+}
+
+func TestResume(t *testing.T) {
+	saved := time.Date(2025, 6, 1, 12, 10, 0, 0, time.UTC)
+	connected := saved.Add(-4 * time.Minute)
+	disconnected := saved.Add(-2 * time.Minute)
+
+	cp := &Checkpoint{
+		SavedAt: saved,
+		Gaps:    []peer.RunGap{peer.NewRunGap(saved.Add(-time.Hour), saved.Add(-50*time.Minute))},
+		Peers: map[string]*peer.Stats{
+			"a": {ConnectionSessions: []peer.ConnectionSession{
+				{ConnectedAt: &connected, DisconnectedAt: &disconnected, Disconnected: true},
+				{ConnectedAt: &connected},
+			}},
+		},
+	}
+
+	gap := cp.Resume(saved.Add(3 * time.Minute))
+
+	if gap.Seconds != 180 || !gap.From.Equal(saved) {
+		t.Errorf("Resume() gap = %+v, want 180s from %v", gap, saved)
+	}
+
+	if len(cp.Gaps) != 2 {
+		t.Fatalf("gaps = %d, want 2", len(cp.Gaps))
+	}
+
+	tests := []struct {
+		name           string
+		session        peer.ConnectionSession
+		wantEndedByGap bool
+		wantEnd        time.Time
+	}{
+		{
+			name:    "closed session is untouched",
+			session: cp.Peers["a"].ConnectionSessions[0],
+			wantEnd: disconnected,
+		},
+		{
+			name:           "open session is closed at the checkpoint",
+			session:        cp.Peers["a"].ConnectionSessions[1],
+			wantEndedByGap: true,
+			wantEnd:        saved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.session.Disconnected || tt.session.DisconnectedAt == nil {
+				t.Fatalf("session not disconnected: %+v", tt.session)
+			}
+
+			if tt.session.EndedByGap != tt.wantEndedByGap {
+				t.Errorf("EndedByGap = %v, want %v", tt.session.EndedByGap, tt.wantEndedByGap)
+			}
+
+			if !tt.session.DisconnectedAt.Equal(tt.wantEnd) {
+				t.Errorf("DisconnectedAt = %v, want %v", tt.session.DisconnectedAt, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -2,17 +2,32 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/aiqueue"
 	"github.com/ethpandaops/hermes-peer-score/internal/build"
 	"github.com/ethpandaops/hermes-peer-score/internal/config"
 	"github.com/ethpandaops/hermes-peer-score/internal/core"
+	"github.com/ethpandaops/hermes-peer-score/internal/experiment"
+	"github.com/ethpandaops/hermes-peer-score/internal/journal"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/peertrace"
+	"github.com/ethpandaops/hermes-peer-score/internal/progress"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+	"github.com/ethpandaops/hermes-peer-score/internal/reachability"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
 	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+	"github.com/ethpandaops/hermes-peer-score/internal/scorefeed"
 )
 
 // Handler manages CLI operations and command routing.
@@ -39,6 +54,14 @@ func (h *Handler) Run(cfg *config.DefaultConfig) error {
 		return h.handleGoModUpdate(cfg)
 	case cfg.IsValidateGoMod():
 		return h.handleGoModValidation(cfg)
+	case cfg.GetReachabilityListenAddr() != "":
+		return h.handleReachabilityServe(cfg)
+	case cfg.GetScoreFeedListenAddr() != "":
+		return h.handleScoreFeedServe(cfg)
+	case cfg.GetParamSweep() != nil:
+		return h.handleParamSweep(cfg)
+	case cfg.GetExperimentPhases() > 0:
+		return h.handleValidationExperiment(cfg)
 	default:
 		return h.handlePeerScoreTest(cfg)
 	}
@@ -72,21 +95,58 @@ func (h *Handler) handleHTMLOnlyMode(cfg *config.DefaultConfig) error {
 		return fmt.Errorf("failed to create report generator: %w", err)
 	}
 
+	reportGen.SetSplitReport(cfg.IsSplitReport(), cfg.GetShardSize())
+	reportGen.SetDataFile(cfg.IsPrettyDataFile(), cfg.GetDataFileBudgetMB()<<20)
+	reportGen.SetRedactor(redact.New(cfg.Secrets()...))
+
+	if err := reportGen.SetTemplateDir(cfg.GetTemplateDir()); err != nil {
+		return fmt.Errorf("failed to load template overrides: %w", err)
+	}
+
+	prunePolicy, err := prune.New(cfg.GetPruneFields(), cfg.GetHashFields(), cfg.GetPruneHashSalt())
+	if err != nil {
+		return fmt.Errorf("invalid field pruning policy: %w", err)
+	}
+
+	reportGen.SetPrunePolicy(prunePolicy)
+
+	if err := reportGen.SetAIClient(reports.AIClientOptions{
+		BaseURL:  cfg.GetAIBaseURL(),
+		ProxyURL: cfg.GetAIProxy(),
+		CABundle: cfg.GetAICABundle(),
+		Timeout:  cfg.GetAITimeout(),
+	}); err != nil {
+		return fmt.Errorf("failed to configure AI client: %w", err)
+	}
+
+	if dir := cfg.GetAIQueueDir(); dir != "" {
+		queue, err := aiqueue.New(dir, cfg.GetAIConcurrency())
+		if err != nil {
+			return fmt.Errorf("failed to create AI queue: %w", err)
+		}
+
+		reportGen.SetAIQueue(queue, cfg.GetAIQueueTimeout())
+	}
+
 	// Get API key for AI analysis
 	apiKey := cfg.GetClaudeAPIKey()
 	if apiKey == "" {
 		apiKey = os.Getenv("OPENROUTER_API_KEY")
 	}
 
+	// A signal cancels generation, leaving the files written so far marked partial
+	ctx, cancel := h.setupGracefulShutdown()
+	defer cancel()
+
 	// Generate HTML report
 	if apiKey != "" && !cfg.IsSkipAI() {
 		h.logger.Info("Including AI analysis in HTML report")
 
-		err = reportGen.GenerateHTMLFromJSONWithAI(inputFile, outputFile, apiKey)
+		err = reportGen.GenerateHTMLFromJSONWithAI(ctx, inputFile, outputFile, apiKey)
 	} else {
 		h.logger.Info("Generating HTML report without AI analysis")
 
-		err = reportGen.GenerateHTMLFromJSON(inputFile, outputFile)
+		err = reportGen.GenerateHTMLFromJSON(ctx, inputFile, outputFile)
 	}
 
 	if err != nil {
@@ -136,6 +196,205 @@ func (h *Handler) handleGoModValidation(cfg *config.DefaultConfig) error {
 	return nil
 }
 
+// handleReachabilityServe serves as a dial-back vantage until interrupted.
+func (h *Handler) handleReachabilityServe(cfg *config.DefaultConfig) error {
+	addr := cfg.GetReachabilityListenAddr()
+	h.logger.WithField("address", addr).Info("Serving reachability dial-back vantage")
+
+	ctx, cancel := h.setupGracefulShutdown()
+	defer cancel()
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           reachability.NewServer(constants.DefaultDialTimeout, h.logger),
+		ReadHeaderTimeout: constants.DefaultDialTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		if err := server.Close(); err != nil {
+			h.logger.WithError(err).Error("Error stopping reachability server")
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("reachability server failed: %w", err)
+	}
+
+	return nil
+}
+
+// handleScoreFeedServe serves a score feed for other instances until interrupted.
+func (h *Handler) handleScoreFeedServe(cfg *config.DefaultConfig) error {
+	addr := cfg.GetScoreFeedListenAddr()
+	h.logger.WithField("address", addr).Info("Serving score feed")
+
+	ctx, cancel := h.setupGracefulShutdown()
+	defer cancel()
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           scorefeed.NewServer(constants.ScoreFeedMaxAge, h.logger),
+		ReadHeaderTimeout: constants.DefaultDialTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		if err := server.Close(); err != nil {
+			h.logger.WithError(err).Error("Error stopping score feed server")
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("score feed server failed: %w", err)
+	}
+
+	return nil
+}
+
+// handleValidationExperiment alternates validation modes over sequential sub-runs and
+// compares the peers seen in both modes.
+func (h *Handler) handleValidationExperiment(cfg *config.DefaultConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	ctx, cancel := h.setupGracefulShutdown()
+	defer cancel()
+
+	runner := experiment.NewRunner(cfg.GetExperimentBinaries(), cfg.GetExperimentArgs(), cfg.GetExperimentPhaseDuration(), cfg.GetExperimentDir(), h.logger)
+
+	phases, err := runner.Run(ctx, experiment.PlanPhases(cfg.GetExperimentPhases()))
+	if err != nil {
+		return fmt.Errorf("validation experiment failed: %w", err)
+	}
+
+	// Compare whatever phases produced a report, a failed phase only narrows the comparison
+	results := make([]experiment.PhasePeers, 0, len(phases))
+
+	for _, phase := range phases {
+		peers, err := runner.LoadPeers(phase)
+		if err != nil {
+			h.logger.WithError(err).WithField("phase", phase.Index+1).Warn("Skipping experiment phase without a report")
+
+			continue
+		}
+
+		results = append(results, experiment.PhasePeers{Phase: phase, Peers: peers})
+	}
+
+	result := experiment.Compare(results)
+	result.PhaseDuration = cfg.GetExperimentPhaseDuration()
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal experiment result: %w", err)
+	}
+
+	if err := os.MkdirAll(cfg.GetExperimentDir(), constants.DefaultDirPermissions); err != nil {
+		return fmt.Errorf("failed to create experiment directory: %w", err)
+	}
+
+	resultFile := filepath.Join(cfg.GetExperimentDir(), constants.ExperimentResultFile)
+	if err := os.WriteFile(resultFile, data, constants.DefaultFilePermissions); err != nil {
+		return fmt.Errorf("failed to write experiment result: %w", err)
+	}
+
+	for mode, summary := range result.Modes {
+		h.logger.WithFields(logrus.Fields{
+			"mode":         mode,
+			"phases":       summary.Phases,
+			"mean_score":   fmt.Sprintf("%.3f", summary.MeanScore),
+			"goodbye_rate": fmt.Sprintf("%.3f", summary.GoodbyeRate),
+		}).Info("Validation mode summary")
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"aligned_peers":    result.AlignedPeers,
+		"only_delegated":   result.OnlyDelegated,
+		"only_independent": result.OnlyIndependent,
+		"result_file":      resultFile,
+	}).Info("Validation experiment completed")
+
+	return nil
+}
+
+// handleParamSweep runs a sub-test of this binary for each value of a mesh degree parameter
+// and summarises how peers treated us at each.
+func (h *Handler) handleParamSweep(cfg *config.DefaultConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate peer score binary: %w", err)
+	}
+
+	ctx, cancel := h.setupGracefulShutdown()
+	defer cancel()
+
+	sweep := cfg.GetParamSweep()
+	mode := cfg.GetValidationMode()
+	binaries := map[config.ValidationMode]string{mode: binary}
+	runner := experiment.NewRunner(binaries, cfg.GetExperimentArgs(), cfg.GetTestDuration(), cfg.GetSweepDir(), h.logger)
+
+	phases, err := runner.Run(ctx, experiment.PlanSweep(mode, sweep))
+	if err != nil {
+		return fmt.Errorf("parameter sweep failed: %w", err)
+	}
+
+	results := make(map[int]map[string]*peer.Stats, len(phases))
+
+	for _, phase := range phases {
+		peers, err := runner.LoadPeers(phase)
+		if err != nil {
+			h.logger.WithError(err).WithField("step", phase.Index+1).Warn("Parameter sweep step has no report")
+
+			continue
+		}
+
+		results[phase.Index] = peers
+	}
+
+	result := experiment.SummarizeSweep(sweep, cfg.GetMeshDegree(), phases, results)
+	result.PhaseDuration = cfg.GetTestDuration()
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sweep result: %w", err)
+	}
+
+	if err := os.MkdirAll(cfg.GetSweepDir(), constants.DefaultDirPermissions); err != nil {
+		return fmt.Errorf("failed to create sweep directory: %w", err)
+	}
+
+	resultFile := filepath.Join(cfg.GetSweepDir(), constants.SweepResultFile)
+	if err := os.WriteFile(resultFile, data, constants.DefaultFilePermissions); err != nil {
+		return fmt.Errorf("failed to write sweep result: %w", err)
+	}
+
+	for _, step := range result.Steps {
+		h.logger.WithFields(logrus.Fields{
+			"mesh":         step.MeshDegree.String(),
+			"peers":        step.Peers,
+			"mean_score":   fmt.Sprintf("%.3f", step.MeanScore),
+			"goodbye_rate": fmt.Sprintf("%.3f", step.GoodbyeRate),
+			"prune_rate":   fmt.Sprintf("%.3f", step.PruneRate),
+		}).Info("Parameter sweep step summary")
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"param":       sweep.Param,
+		"steps":       len(result.Steps),
+		"result_file": resultFile,
+	}).Info("Parameter sweep completed")
+
+	return nil
+}
+
 // handlePeerScoreTest runs the main peer scoring test.
 func (h *Handler) handlePeerScoreTest(cfg *config.DefaultConfig) error {
 	h.logger.WithField("validation_mode", cfg.GetValidationMode()).Info("Starting peer score test")
@@ -145,6 +404,68 @@ func (h *Handler) handlePeerScoreTest(cfg *config.DefaultConfig) error {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	// Progress events let wrapper automation follow the run without parsing the logs
+	var emitter *progress.Emitter
+
+	if target := cfg.GetProgressJSON(); target != "" {
+		var err error
+
+		emitter, err = progress.Open(target)
+		if err != nil {
+			return fmt.Errorf("failed to open progress output: %w", err)
+		}
+
+		defer func() {
+			if err := emitter.Close(); err != nil {
+				h.logger.WithError(err).Warn("Progress events were not all written")
+			}
+		}()
+	}
+
+	// Warnings and errors are journaled next to the reports, so debugging a report needs no re-run
+	if path := cfg.GetErrorJournal(); path != "" {
+		errorJournal, err := journal.Open(path, cfg.IsResume())
+		if err != nil {
+			return err
+		}
+
+		prunePolicy, err := prune.New(cfg.GetPruneFields(), cfg.GetHashFields(), cfg.GetPruneHashSalt())
+		if err != nil {
+			return fmt.Errorf("invalid field pruning policy: %w", err)
+		}
+
+		errorJournal.SetPrunePolicy(prunePolicy)
+
+		if entry, ok := h.logger.(*logrus.Entry); ok {
+			entry.Logger.AddHook(errorJournal)
+		}
+
+		defer func() {
+			h.logger.WithFields(logrus.Fields{
+				"path":    path,
+				"entries": errorJournal.Entries(),
+			}).Info("Error journal written")
+
+			if err := errorJournal.Close(); err != nil {
+				h.logger.WithError(err).Warn("Error journal was not fully written")
+			}
+		}()
+	}
+
+	err := h.runPeerScoreTest(cfg, emitter)
+	if err != nil {
+		emitter.Emit(progress.Event{Event: progress.EventFailed, Error: err.Error()})
+
+		return err
+	}
+
+	emitter.Emit(progress.Event{Event: progress.EventCompleted})
+
+	return nil
+}
+
+// runPeerScoreTest runs the test and saves its reports, emitting progress events along the way.
+func (h *Handler) runPeerScoreTest(cfg *config.DefaultConfig, emitter *progress.Emitter) error {
 	// Set up graceful shutdown
 	ctx, cancel := h.setupGracefulShutdown()
 	defer cancel()
@@ -155,6 +476,41 @@ func (h *Handler) handlePeerScoreTest(cfg *config.DefaultConfig) error {
 		return fmt.Errorf("failed to create peer score tool: %w", err)
 	}
 
+	tool.SetProgress(emitter)
+
+	// One peer is traced at full detail into a file of its own, the rest keeps its log level
+	if peerID := cfg.GetTracePeer(); peerID != "" {
+		prunePolicy, err := prune.New(cfg.GetPruneFields(), cfg.GetHashFields(), cfg.GetPruneHashSalt())
+		if err != nil {
+			return fmt.Errorf("invalid field pruning policy: %w", err)
+		}
+
+		trace, err := peertrace.Open(cfg.GetTracePeerFile(), peerID, cfg.IsResume())
+		if err != nil {
+			return err
+		}
+
+		trace.SetPrunePolicy(prunePolicy)
+
+		if entry, ok := h.logger.(*logrus.Entry); ok {
+			peertrace.Verbose(entry.Logger)
+			entry.Logger.AddHook(trace)
+		}
+
+		tool.SetPeerTrace(trace)
+
+		defer func() {
+			h.logger.WithFields(logrus.Fields{
+				"path":    trace.Path(),
+				"records": trace.Records(),
+			}).Info("Peer trace written")
+
+			if err := trace.Close(); err != nil {
+				h.logger.WithError(err).Warn("Peer trace was not fully written")
+			}
+		}()
+	}
+
 	// Log connection settings
 	h.logConnectionSettings(cfg)
 
@@ -170,8 +526,13 @@ func (h *Handler) handlePeerScoreTest(cfg *config.DefaultConfig) error {
 		}
 	}()
 
+	// The first signal ended the test, another one cancels report generation, leaving the
+	// files written so far marked partial
+	reportCtx, cancelReports := h.setupGracefulShutdown()
+	defer cancelReports()
+
 	// Save reports
-	if err := tool.SaveReports(); err != nil {
+	if err := tool.SaveReports(reportCtx); err != nil {
 		return fmt.Errorf("failed to save reports: %w", err)
 	}
 
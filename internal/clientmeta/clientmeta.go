@@ -0,0 +1,67 @@
+// Package clientmeta bundles client display names and logos into the binary, so reports
+// render them without reaching a third-party CDN, however long after the run they are opened.
+package clientmeta
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+//go:generate go run gen.go
+
+// SourceURL is where the ethpandaops cartographoor service publishes client metadata.
+const SourceURL = "https://ethpandaops-platform-production-cartographoor.ams3.cdn.digitaloceanspaces.com/networks.json"
+
+//go:embed clients.json
+var bundled []byte
+
+// Client is the metadata of one client, keyed by its lowercase name.
+type Client struct {
+	DisplayName string `json:"displayName"`
+	WebsiteURL  string `json:"websiteUrl,omitempty"`
+	Logo        string `json:"logo,omitempty"` // Data URI, empty when no logo was fetched
+}
+
+// Metadata is the bundled client metadata and where it came from.
+type Metadata struct {
+	Source    string            `json:"source"`
+	FetchedAt *time.Time        `json:"fetched_at,omitempty"` // Nil until the bundle is refreshed
+	Clients   map[string]Client `json:"clients"`
+}
+
+// Load returns the client metadata bundled at build time.
+func Load() (*Metadata, error) {
+	var metadata Metadata
+	if err := json.Unmarshal(bundled, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode bundled client metadata: %w", err)
+	}
+
+	if metadata.Clients == nil {
+		metadata.Clients = make(map[string]Client)
+	}
+
+	return &metadata, nil
+}
+
+// LogoFallback returns the URL reports fetch the logos missing from the bundle from when they
+// are opened, empty once every bundled client has a logo. A report opened offline or after the
+// source has moved on shows the initials of those clients instead.
+func (m *Metadata) LogoFallback() string {
+	if m.Source == "" {
+		return ""
+	}
+
+	if len(m.Clients) == 0 {
+		return m.Source
+	}
+
+	for _, client := range m.Clients {
+		if client.Logo == "" {
+			return m.Source
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,144 @@
+package clientmeta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pngHeader is enough of a PNG for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestLoad(t *testing.T) {
+	metadata, err := Load()
+	if err != nil {
+		t.Fatalf("Expected bundled metadata to load, got %v", err)
+	}
+
+	if metadata.Source != SourceURL {
+		t.Errorf("Expected source %q, got %q", SourceURL, metadata.Source)
+	}
+
+	for _, name := range []string{"lighthouse", "prysm", "teku", "nimbus", "lodestar", "grandine"} {
+		if metadata.Clients[name].DisplayName == "" {
+			t.Errorf("Expected bundled display name for %s", name)
+		}
+	}
+}
+
+func TestLogoFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+		want     string
+	}{
+		{name: "every logo bundled", metadata: Metadata{Source: SourceURL, Clients: map[string]Client{"teku": {Logo: "data:image/png;base64,"}}}, want: ""},
+		{name: "logo missing", metadata: Metadata{Source: SourceURL, Clients: map[string]Client{"teku": {Logo: "data:image/png;base64,"}, "nimbus": {DisplayName: "Nimbus"}}}, want: SourceURL},
+		{name: "no clients", metadata: Metadata{Source: SourceURL}, want: SourceURL},
+		{name: "no source", metadata: Metadata{Clients: map[string]Client{"nimbus": {}}}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metadata.LogoFallback(); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks string
+		logo     []byte
+		logoType string
+		want     map[string]Client
+		wantErr  bool
+	}{
+		{
+			name:     "logo with content type",
+			networks: `{"clients": {"Lighthouse": {"displayName": "Lighthouse", "websiteUrl": "https://example.com", "logo": "LOGO"}}}`,
+			logo:     []byte("<svg></svg>"),
+			logoType: "image/svg+xml",
+			want: map[string]Client{
+				"lighthouse": {DisplayName: "Lighthouse", WebsiteURL: "https://example.com", Logo: "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4="},
+			},
+		},
+		{
+			name:     "logo type sniffed",
+			networks: `{"clients": {"teku": {"logo": "LOGO"}}}`,
+			logo:     pngHeader,
+			logoType: "application/octet-stream",
+			want: map[string]Client{
+				"teku": {DisplayName: "teku", Logo: "data:image/png;base64,iVBORw0KGgoAAAANSUhEUg=="},
+			},
+		},
+		{
+			name:     "client without logo",
+			networks: `{"clients": {"nimbus": {"displayName": "Nimbus"}}}`,
+			want: map[string]Client{
+				"nimbus": {DisplayName: "Nimbus"},
+			},
+		},
+		{
+			name:     "logo is not an image",
+			networks: `{"clients": {"prysm": {"logo": "LOGO"}}}`,
+			logo:     []byte("<html>not found</html>"),
+			logoType: "text/html",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid document",
+			networks: `not json`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/logo" {
+					w.Header().Set("Content-Type", tt.logoType)
+					_, _ = w.Write(tt.logo)
+
+					return
+				}
+
+				_, _ = w.Write([]byte(strings.ReplaceAll(tt.networks, "LOGO", server.URL+"/logo")))
+			}))
+			defer server.Close()
+
+			metadata, err := Refresh(context.Background(), server.Client(), server.URL+"/networks.json")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if metadata.FetchedAt == nil || metadata.Source != server.URL+"/networks.json" {
+				t.Errorf("Expected source and fetch time to be recorded, got %+v", metadata)
+			}
+
+			if len(metadata.Clients) != len(tt.want) {
+				t.Fatalf("Expected %d clients, got %d", len(tt.want), len(metadata.Clients))
+			}
+
+			for name, want := range tt.want {
+				if got := metadata.Clients[name]; got != want {
+					t.Errorf("Client %s = %+v, want %+v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+//go:build ignore
+
+// Refreshes clients.json from the cartographoor client metadata. Run with
+// `go generate ./internal/clientmeta` or `make client-metadata`.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/clientmeta"
+)
+
+func main() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	metadata, err := clientmeta.Refresh(ctx, &http.Client{Timeout: 30 * time.Second}, clientmeta.SourceURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile("clients.json", append(data, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Bundled metadata for %d clients", len(metadata.Clients))
+}
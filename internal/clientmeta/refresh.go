@@ -0,0 +1,134 @@
+package clientmeta
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxLogoBytes caps the size of a logo, since every report inlines all of them.
+const maxLogoBytes = 256 * 1024
+
+// networksResponse is the part of the cartographoor networks document describing clients.
+type networksResponse struct {
+	Clients map[string]struct {
+		DisplayName string `json:"displayName"`
+		WebsiteURL  string `json:"websiteUrl"`
+		Logo        string `json:"logo"`
+	} `json:"clients"`
+}
+
+// Refresh fetches the client metadata from source and downloads each client's logo into a
+// data URI. Any failed download fails the refresh, so a flaky CDN never drops a logo from
+// the bundle.
+func Refresh(ctx context.Context, httpClient *http.Client, source string) (*Metadata, error) {
+	var networks networksResponse
+	if err := getJSON(ctx, httpClient, source, &networks); err != nil {
+		return nil, fmt.Errorf("failed to fetch client metadata: %w", err)
+	}
+
+	fetchedAt := time.Now().UTC()
+	metadata := &Metadata{
+		Source:    source,
+		FetchedAt: &fetchedAt,
+		Clients:   make(map[string]Client, len(networks.Clients)),
+	}
+
+	for name, info := range networks.Clients {
+		client := Client{
+			DisplayName: info.DisplayName,
+			WebsiteURL:  info.WebsiteURL,
+		}
+
+		if client.DisplayName == "" {
+			client.DisplayName = name
+		}
+
+		if info.Logo != "" {
+			logo, err := fetchLogo(ctx, httpClient, info.Logo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s logo: %w", name, err)
+			}
+
+			client.Logo = logo
+		}
+
+		metadata.Clients[strings.ToLower(name)] = client
+	}
+
+	return metadata, nil
+}
+
+// getJSON fetches url and decodes its JSON body into v.
+func getJSON(ctx context.Context, httpClient *http.Client, url string, v interface{}) error {
+	body, _, err := get(ctx, httpClient, url, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+
+	return nil
+}
+
+// fetchLogo downloads a logo and encodes it as a data URI.
+func fetchLogo(ctx context.Context, httpClient *http.Client, url string) (string, error) {
+	body, contentType, err := get(ctx, httpClient, url, maxLogoBytes)
+	if err != nil {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		mediaType = http.DetectContentType(body)
+	}
+
+	if !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("%s is not an image: %s", url, mediaType)
+	}
+
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(body), nil
+}
+
+// get fetches url, returning its body and content type. A limit above zero fails bodies
+// larger than it.
+func get(ctx context.Context, httpClient *http.Client, url string, limit int64) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+
+	reader := io.Reader(resp.Body)
+	if limit > 0 {
+		reader = io.LimitReader(resp.Body, limit+1)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", url, err)
+	}
+
+	if limit > 0 && int64(len(body)) > limit {
+		return nil, "", fmt.Errorf("%s is larger than %d bytes", url, limit)
+	}
+
+	return body, resp.Header.Get("Content-Type"), nil
+}
@@ -0,0 +1,180 @@
+package clockskew
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+// syncingPath is the standard beacon API endpoint reporting the node's head slot.
+const syncingPath = "/eth/v1/node/syncing"
+
+// dateResolution is the resolution of the HTTP Date header the beacon node's time is read from.
+const dateResolution = time.Second
+
+// Checker compares our clock with the beacon node's, from the Date header and head slot of
+// its syncing endpoint.
+type Checker struct {
+	endpoint   *url.URL
+	httpClient *http.Client
+	threshold  time.Duration
+	logger     logrus.FieldLogger
+}
+
+// NewChecker creates a checker for the beacon API of a Prysm host connection string, which may
+// carry user:password@ credentials. Offsets beyond threshold count as skew.
+func NewChecker(host string, port int, useTLS bool, threshold, timeout time.Duration, logger logrus.FieldLogger) *Checker {
+	return &Checker{
+		endpoint: common.ParseBeaconEndpoint(host, port, useTLS),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		threshold: threshold,
+		logger:    logger.WithField("component", "clock_skew"),
+	}
+}
+
+// Check compares our clock with the beacon node's once. The slot clock may be nil before
+// Hermes started, the head slot is then not compared. A failed check is recorded in the
+// sample rather than returned, so the report is still written.
+func (c *Checker) Check(ctx context.Context, slots *peer.SlotClock) BeaconSample {
+	sample := BeaconSample{Endpoint: redact.URL(c.endpoint.String())}
+
+	if err := c.measure(ctx, slots, &sample); err != nil {
+		c.logger.WithError(err).Warn("Clock skew check against the beacon node failed")
+
+		sample.CheckedAt = time.Now()
+		sample.Error = err.Error()
+
+		return sample
+	}
+
+	fields := logrus.Fields{
+		"offset_ms":      math.Round(sample.OffsetMs),
+		"uncertainty_ms": math.Round(sample.UncertaintyMs),
+		"head_slot":      sample.HeadSlot,
+		"local_slot":     sample.LocalSlot,
+	}
+
+	if sample.Skewed {
+		c.logger.WithFields(fields).Warn("Local clock is skewed from the beacon node, timeliness-sensitive peer scores will be wrong")
+	} else {
+		c.logger.WithFields(fields).Info("Clock skew check against the beacon node complete")
+	}
+
+	return sample
+}
+
+// measure reads the beacon node's time and head slot into the sample. The node's time is
+// taken at the midpoint of the request, the Date header is truncated to whole seconds.
+func (c *Checker) measure(ctx context.Context, slots *peer.SlotClock, sample *BeaconSample) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint.JoinPath(syncingPath).String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create beacon syncing request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	sent := time.Now()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach beacon API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	received := time.Now()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("beacon API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return fmt.Errorf("beacon API response has no valid Date header: %w", err)
+	}
+
+	var syncing syncingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&syncing); err != nil {
+		return fmt.Errorf("failed to decode beacon syncing status: %w", err)
+	}
+
+	headSlot, err := strconv.ParseUint(syncing.Data.HeadSlot, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid beacon head slot %q: %w", syncing.Data.HeadSlot, err)
+	}
+
+	roundTrip := received.Sub(sent)
+	midpoint := sent.Add(roundTrip / 2)
+	uncertainty := dateResolution/2 + roundTrip/2
+
+	sample.CheckedAt = midpoint
+	sample.RoundTripMs = float64(roundTrip) / float64(time.Millisecond)
+	sample.OffsetMs = float64(date.Add(dateResolution/2).Sub(midpoint)) / float64(time.Millisecond)
+	sample.UncertaintyMs = float64(uncertainty) / float64(time.Millisecond)
+	sample.HeadSlot = headSlot
+	sample.Syncing = syncing.Data.IsSyncing
+
+	// Only an offset beyond what the measurement cannot tell apart counts
+	sample.Skewed = math.Abs(sample.OffsetMs)-sample.UncertaintyMs > float64(c.threshold)/float64(time.Millisecond)
+
+	// A synced node's head is the current slot or just behind it, whatever the Date header says
+	if slots != nil {
+		sample.LocalSlot = slots.SlotAt(midpoint)
+
+		drift := float64(headSlot) - float64(sample.LocalSlot)
+		if !sample.Syncing && (drift >= constants.ClockSkewPeerSlotsAhead || drift <= -constants.ClockSkewPeerSlotsBehind) {
+			sample.Skewed = true
+		}
+	}
+
+	return nil
+}
+
+// Summarize combines the beacon node samples and the peers' slot drift into the run's result,
+// or returns nil when there is neither.
+func Summarize(threshold time.Duration, beacon []BeaconSample, peers *peer.SlotDrift) *Result {
+	if peers != nil && peers.Statuses == 0 {
+		peers = nil
+	}
+
+	if len(beacon) == 0 && peers == nil {
+		return nil
+	}
+
+	result := &Result{
+		ThresholdMs: float64(threshold) / float64(time.Millisecond),
+		Beacon:      beacon,
+		Peers:       peers,
+		Skewed:      peers != nil && peers.Skewed,
+	}
+
+	for _, sample := range beacon {
+		if sample.Error != "" {
+			continue
+		}
+
+		if math.Abs(sample.OffsetMs) > math.Abs(result.MaxOffsetMs) {
+			result.MaxOffsetMs = sample.OffsetMs
+		}
+
+		result.Skewed = result.Skewed || sample.Skewed
+	}
+
+	return result
+}
@@ -0,0 +1,123 @@
+package clockskew
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	var (
+		offset   time.Duration
+		headSlot string
+		syncing  bool
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/node/syncing" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"data":{"head_slot":"` + headSlot + `","sync_distance":"0","is_syncing":` + strconv.FormatBool(syncing) + `}}`))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+
+	// Genesis a little over 1000 slots ago, so the current slot is 1000
+	genesis := time.Now().Add(-1000*12*time.Second - 6*time.Second)
+	slots := peer.NewSlotClock(genesis, 12*time.Second, 32)
+
+	tests := []struct {
+		name       string
+		offset     time.Duration
+		headSlot   string
+		syncing    bool
+		slots      *peer.SlotClock
+		wantSkewed bool
+		wantError  bool
+	}{
+		{name: "clocks agree", headSlot: "1000", slots: slots},
+		{name: "beacon node clock ahead", offset: 5 * time.Second, headSlot: "1000", slots: slots, wantSkewed: true},
+		{name: "beacon node clock behind", offset: -5 * time.Second, headSlot: "1000", wantSkewed: true},
+		{name: "head slot in our future", headSlot: "1003", slots: slots, wantSkewed: true},
+		{name: "syncing node head slot is ignored", headSlot: "400", syncing: true, slots: slots},
+		{name: "invalid head slot", headSlot: "head", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, headSlot, syncing = tt.offset, tt.headSlot, tt.syncing
+
+			checker := NewChecker(serverURL.Hostname(), port, false, 500*time.Millisecond, time.Second, logger)
+			sample := checker.Check(context.Background(), tt.slots)
+
+			if (sample.Error != "") != tt.wantError {
+				t.Fatalf("Error = %q, want error %v", sample.Error, tt.wantError)
+			}
+
+			if sample.Skewed != tt.wantSkewed {
+				t.Errorf("Skewed = %v, want %v (offset %.0fms, uncertainty %.0fms, head %d, local %d)",
+					sample.Skewed, tt.wantSkewed, sample.OffsetMs, sample.UncertaintyMs, sample.HeadSlot, sample.LocalSlot)
+			}
+
+			if tt.wantError {
+				return
+			}
+
+			// The Date header is truncated to whole seconds, the offset must fall within the uncertainty
+			if diff := sample.OffsetMs - float64(tt.offset/time.Millisecond); diff > sample.UncertaintyMs || -diff > sample.UncertaintyMs {
+				t.Errorf("OffsetMs = %.0f, want %d within %.0f", sample.OffsetMs, tt.offset.Milliseconds(), sample.UncertaintyMs)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	if result := Summarize(time.Second, nil, &peer.SlotDrift{}); result != nil {
+		t.Errorf("Expected no result without samples or statuses, got %+v", result)
+	}
+
+	beacon := []BeaconSample{
+		{OffsetMs: 120},
+		{Error: "beacon API returned status 503"},
+		{OffsetMs: -2400, Skewed: true},
+	}
+
+	result := Summarize(500*time.Millisecond, beacon, &peer.SlotDrift{Peers: 5, Statuses: 9})
+	if result == nil {
+		t.Fatal("Expected a result")
+	}
+
+	if result.ThresholdMs != 500 || result.MaxOffsetMs != -2400 || !result.Skewed || result.Peers == nil {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	// Peers alone can flag skew
+	result = Summarize(500*time.Millisecond, nil, &peer.SlotDrift{Peers: 5, Statuses: 9, MedianSlotDrift: 2, Skewed: true})
+	if result == nil || !result.Skewed || result.MaxOffsetMs != 0 {
+		t.Errorf("Expected skew from the peers alone, got %+v", result)
+	}
+}
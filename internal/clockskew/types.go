@@ -0,0 +1,39 @@
+package clockskew
+
+import (
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// BeaconSample is one comparison of our clock with the beacon node's.
+type BeaconSample struct {
+	Endpoint      string    `json:"endpoint"` // Beacon API, with credentials redacted
+	CheckedAt     time.Time `json:"checked_at"`
+	OffsetMs      float64   `json:"offset_ms"`      // Beacon node clock minus ours, positive when ours is behind
+	UncertaintyMs float64   `json:"uncertainty_ms"` // Half the round trip plus the Date header's one second resolution
+	RoundTripMs   float64   `json:"round_trip_ms"`
+	HeadSlot      uint64    `json:"head_slot"`
+	LocalSlot     uint64    `json:"local_slot"` // Current slot by our clock when the beacon node answered
+	Syncing       bool      `json:"syncing"`    // The head slot says nothing about the clock while syncing
+	Skewed        bool      `json:"skewed"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Result summarises how far our clock was from the beacon node's and the peers' over the run.
+// Skew silently wrecks timeliness-sensitive peer scores, so a skewed run is flagged.
+type Result struct {
+	ThresholdMs float64         `json:"threshold_ms"`
+	Beacon      []BeaconSample  `json:"beacon,omitempty"` // In the order they were taken
+	Peers       *peer.SlotDrift `json:"peers,omitempty"`  // Head slots in the peers' statuses
+	MaxOffsetMs float64         `json:"max_offset_ms"`    // Largest beacon offset by magnitude, with its sign
+	Skewed      bool            `json:"skewed"`
+}
+
+// syncingResponse is the body of the beacon node's /eth/v1/node/syncing endpoint.
+type syncingResponse struct {
+	Data struct {
+		HeadSlot  string `json:"head_slot"`
+		IsSyncing bool   `json:"is_syncing"`
+	} `json:"data"`
+}
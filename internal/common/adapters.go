@@ -0,0 +1,162 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/probe-lab/hermes/eth"
+	"github.com/probe-lab/hermes/host"
+)
+
+// connectionPayload is the anonymous struct Hermes traces CONNECTED and DISCONNECTED events
+// with. It must match the one in (*host.Host).Serve field for field, or connection events fall
+// back to reflection.
+type connectionPayload = struct {
+	RemotePeer   string
+	RemoteMaddrs ma.Multiaddr
+	AgentVersion string
+	Direction    string
+	Opened       time.Time
+	Limited      bool
+}
+
+// payloadPeerKeys are the keys map payloads carry the remote peer under, in order of preference.
+// Hermes uses PeerID and RemotePeer, the others appear in replayed and hand-written event logs.
+var payloadPeerKeys = []string{"PeerID", "RemotePeer", "peer_id", "remote_peer"}
+
+// payloadPeerID returns the remote peer ID of a known Hermes trace payload type, and whether
+// the type is known. Every payload type Hermes emits is listed here, new ones are added when a
+// Hermes bump shows up in the reflection fallback counts.
+func payloadPeerID(payload any) (string, bool) {
+	switch p := payload.(type) {
+	// Gossipsub, req/resp and peer score traces, and replayed event logs
+	case map[string]any:
+		return mapPeerID(p), true
+	case map[string]string:
+		for _, key := range payloadPeerKeys {
+			if peerID := p[key]; peerID != "" {
+				return peerID, true
+			}
+		}
+
+		return "", true
+
+	// Connection traces
+	case connectionPayload:
+		return p.RemotePeer, true
+	case *connectionPayload:
+		return p.RemotePeer, true
+
+	// RPC traces
+	case *host.RpcMeta:
+		return p.PeerID.String(), true
+	case host.RpcMeta:
+		return p.PeerID.String(), true
+	case *host.TraceEventPeerScore:
+		return p.PeerID, true
+	case host.TraceEventPeerScore:
+		return p.PeerID, true
+
+	// Gossip messages rendered in full
+	case *host.TraceEventPayloadMetaData:
+		return p.PeerID, true
+	case host.TraceEventPayloadMetaData:
+		return p.PeerID, true
+	case *eth.TraceEventPhase0Block:
+		return p.PeerID, true
+	case *eth.TraceEventAltairBlock:
+		return p.PeerID, true
+	case *eth.TraceEventBellatrixBlock:
+		return p.PeerID, true
+	case *eth.TraceEventCapellaBlock:
+		return p.PeerID, true
+	case *eth.TraceEventDenebBlock:
+		return p.PeerID, true
+	case *eth.TraceEventElectraBlock:
+		return p.PeerID, true
+	case *eth.TraceEventAttestation:
+		return p.PeerID, true
+	case *eth.TraceEventAttestationElectra:
+		return p.PeerID, true
+	case *eth.TraceEventSingleAttestation:
+		return p.PeerID, true
+	case *eth.TraceEventSignedAggregateAttestationAndProof:
+		return p.PeerID, true
+	case *eth.TraceEventSignedAggregateAttestationAndProofElectra:
+		return p.PeerID, true
+	case *eth.TraceEventSignedContributionAndProof:
+		return p.PeerID, true
+	case *eth.TraceEventVoluntaryExit:
+		return p.PeerID, true
+	case *eth.TraceEventSyncCommitteeMessage:
+		return p.PeerID, true
+	case *eth.TraceEventBLSToExecutionChange:
+		return p.PeerID, true
+	case *eth.TraceEventBlobSidecar:
+		return p.PeerID, true
+	case *eth.TraceEventProposerSlashing:
+		return p.PeerID, true
+	case *eth.TraceEventAttesterSlashing:
+		return p.PeerID, true
+	default:
+		return "", false
+	}
+}
+
+// mapPeerID returns the remote peer ID of a map payload. Hermes stores libp2p peer IDs in
+// their binary form, they are encoded the way libp2p prints them.
+func mapPeerID(payload map[string]any) string {
+	for _, key := range payloadPeerKeys {
+		switch value := payload[key].(type) {
+		case nil:
+			continue
+		case string:
+			if value != "" {
+				return value
+			}
+		case peer.ID:
+			if value != "" {
+				return value.String()
+			}
+		case fmt.Stringer:
+			if peerID := value.String(); peerID != "" {
+				return peerID
+			}
+		}
+	}
+
+	return ""
+}
+
+// reflectionFallbacks counts the payloads peer IDs were extracted from by reflection, by type.
+var reflectionFallbacks = struct {
+	mu     sync.Mutex
+	byType map[string]int
+}{byType: make(map[string]int)}
+
+// recordReflectionFallback counts a payload whose peer ID had to be found by reflection.
+func recordReflectionFallback(payload any) {
+	payloadType := strings.TrimPrefix(fmt.Sprintf("%T", payload), "*")
+
+	reflectionFallbacks.mu.Lock()
+	reflectionFallbacks.byType[payloadType]++
+	reflectionFallbacks.mu.Unlock()
+}
+
+// PeerIDReflectionFallbacks returns how often a peer ID was extracted by reflection, by payload
+// type, across every host of the process. Any count means a payload type is missing an adapter.
+func PeerIDReflectionFallbacks() map[string]int {
+	reflectionFallbacks.mu.Lock()
+	defer reflectionFallbacks.mu.Unlock()
+
+	counts := make(map[string]int, len(reflectionFallbacks.byType))
+	for payloadType, count := range reflectionFallbacks.byType {
+		counts[payloadType] = count
+	}
+
+	return counts
+}
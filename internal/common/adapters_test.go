@@ -0,0 +1,113 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/probe-lab/hermes/eth"
+	"github.com/probe-lab/hermes/host"
+)
+
+const testPeerID = "12D3KooWD3eckifWpRn9wQpMG9R9hX3sD158z7EqHWmweQAJU5SA"
+
+func TestGetPeerID(t *testing.T) {
+	pid, err := peer.Decode(testPeerID)
+	if err != nil {
+		t.Fatalf("decode peer ID: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		event    *host.TraceEvent
+		expected string
+	}{
+		{
+			name:     "nil event",
+			event:    nil,
+			expected: unknown,
+		},
+		{
+			name:     "string PeerID in payload map",
+			event:    &host.TraceEvent{Payload: map[string]any{"PeerID": "test-peer-123"}},
+			expected: "test-peer-123",
+		},
+		{
+			name:     "binary PeerID in payload map",
+			event:    &host.TraceEvent{Payload: map[string]any{"PeerID": pid}},
+			expected: testPeerID,
+		},
+		{
+			name:     "RemotePeer in payload map",
+			event:    &host.TraceEvent{Payload: map[string]any{"RemotePeer": testPeerID}},
+			expected: testPeerID,
+		},
+		{
+			name:     "no peer ID in payload map",
+			event:    &host.TraceEvent{Payload: map[string]any{"other": "value"}},
+			expected: unknown,
+		},
+		{
+			name:     "req/resp request payload",
+			event:    &host.TraceEvent{Payload: map[string]string{"PeerID": testPeerID}},
+			expected: testPeerID,
+		},
+		{
+			name:     "connection payload",
+			event:    &host.TraceEvent{Payload: connectionPayload{RemotePeer: testPeerID, Direction: "inbound"}},
+			expected: testPeerID,
+		},
+		{
+			name:     "rpc payload",
+			event:    &host.TraceEvent{Payload: &host.RpcMeta{PeerID: pid}},
+			expected: testPeerID,
+		},
+		{
+			name:     "peer score payload",
+			event:    &host.TraceEvent{Payload: &host.TraceEventPeerScore{PeerID: testPeerID}},
+			expected: testPeerID,
+		},
+		{
+			name: "full gossip payload",
+			event: &host.TraceEvent{Payload: &eth.TraceEventBlobSidecar{
+				TraceEventPayloadMetaData: host.TraceEventPayloadMetaData{PeerID: testPeerID},
+			}},
+			expected: testPeerID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := GetPeerID(tt.event); result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetPeerIDReflectionFallback(t *testing.T) {
+	type unlistedPayload struct {
+		Inner struct {
+			RemotePeer string
+		}
+	}
+
+	payload := unlistedPayload{}
+	payload.Inner.RemotePeer = testPeerID
+
+	before := PeerIDReflectionFallbacks()["common.unlistedPayload"]
+
+	if result := GetPeerID(&host.TraceEvent{Payload: &payload}); result != testPeerID {
+		t.Errorf("Expected %s, got %s", testPeerID, result)
+	}
+
+	if after := PeerIDReflectionFallbacks()["common.unlistedPayload"]; after != before+1 {
+		t.Errorf("Expected %d reflection fallbacks, got %d", before+1, after)
+	}
+
+	// Known payload types are never counted, even without a peer ID
+	GetPeerID(&host.TraceEvent{Payload: map[string]any{}})
+
+	if count := PeerIDReflectionFallbacks()["map[string]interface {}"]; count != 0 {
+		t.Errorf("Expected no fallbacks for map payloads, got %d", count)
+	}
+}
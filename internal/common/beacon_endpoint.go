@@ -0,0 +1,29 @@
+package common
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseBeaconEndpoint returns the beacon API URL of a Prysm host connection string, which may
+// carry user:password@ credentials, with the port the API listens on. The URL is https when
+// useTLS is set and http otherwise.
+func ParseBeaconEndpoint(host string, port int, useTLS bool) *url.URL {
+	endpoint := &url.URL{Scheme: "http"}
+	if useTLS {
+		endpoint.Scheme = "https"
+	}
+
+	if at := strings.LastIndex(host, "@"); at > 0 {
+		username, password, _ := strings.Cut(host[:at], ":")
+		endpoint.User = url.UserPassword(username, password)
+		host = host[at+1:]
+	}
+
+	// IPv6 hosts may come bracketed or bare, JoinHostPort brackets them once
+	endpoint.Host = net.JoinHostPort(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"), strconv.Itoa(port))
+
+	return endpoint
+}
@@ -0,0 +1,42 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+)
+
+// EventFingerprint returns a short hash of an event's type and payload, which tells whether
+// errors logged at different times were caused by the same payload. Payloads that cannot be
+// marshalled are hashed in their printed form.
+func EventFingerprint(event *host.TraceEvent) string {
+	if event == nil {
+		return ""
+	}
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		payload = []byte(fmt.Sprintf("%+v", event.Payload))
+	}
+
+	sum := sha256.Sum256(append([]byte(event.Type+"\x00"), payload...))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// EventFields returns the log fields identifying the event an error or warning is about. The
+// payload is hashed, so this belongs on failure paths rather than on every event.
+func EventFields(event *host.TraceEvent) logrus.Fields {
+	if event == nil {
+		return logrus.Fields{}
+	}
+
+	return logrus.Fields{
+		"event_type":        event.Type,
+		"event_fingerprint": EventFingerprint(event),
+	}
+}
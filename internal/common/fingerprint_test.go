@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/probe-lab/hermes/host"
+)
+
+func TestEventFingerprint(t *testing.T) {
+	goodbye := &host.TraceEvent{Type: "HANDLE_GOODBYE", Payload: map[string]any{"PeerID": "16Uiu2HAm", "Code": 3}}
+	same := &host.TraceEvent{Type: "HANDLE_GOODBYE", Payload: map[string]any{"Code": 3, "PeerID": "16Uiu2HAm"}}
+	other := &host.TraceEvent{Type: "HANDLE_GOODBYE", Payload: map[string]any{"PeerID": "16Uiu2HAm", "Code": 1}}
+
+	fingerprint := EventFingerprint(goodbye)
+	if len(fingerprint) != 16 {
+		t.Fatalf("Expected a 16 character fingerprint, got %q", fingerprint)
+	}
+
+	if EventFingerprint(same) != fingerprint {
+		t.Error("Expected equal payloads to share a fingerprint")
+	}
+
+	if EventFingerprint(other) == fingerprint {
+		t.Error("Expected different payloads to differ")
+	}
+
+	// Payloads that cannot be marshalled are still fingerprinted
+	if EventFingerprint(&host.TraceEvent{Type: "X", Payload: make(chan int)}) == "" {
+		t.Error("Expected a fingerprint for a payload that cannot be marshalled")
+	}
+
+	if fields := EventFields(goodbye); fields["event_type"] != "HANDLE_GOODBYE" || fields["event_fingerprint"] != fingerprint {
+		t.Errorf("Unexpected event fields %v", fields)
+	}
+}
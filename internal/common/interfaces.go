@@ -1,6 +1,8 @@
 package common
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -13,4 +15,8 @@ type ToolInterface interface {
 	GetLogger() logrus.FieldLogger
 	IncrementEventCount(peerID, eventType string)
 	IncrementMessageCount(peerID string)
+	GetLateEventGrace() time.Duration
+	GetEventLogLevel() logrus.Level
+	IsBackendDegraded(at time.Time) bool
+	GetEventSampleRate(eventType string) int
 }
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogSampler picks the level of log lines written for every event of a kind: one in every N
+// is written at info level and the rest at debug level, so busy runs show a trickle of them
+// without flooding the log. What the lines say is recorded in the report either way.
+type LogSampler struct {
+	every uint64
+	count atomic.Uint64
+}
+
+// NewLogSampler creates a sampler writing one in every lines at info level, 0 writes none.
+func NewLogSampler(every int) *LogSampler {
+	return &LogSampler{every: uint64(max(every, 0))} //nolint:gosec // ok.
+}
+
+// Level returns the level the next line is written at. It is safe for concurrent use.
+func (s *LogSampler) Level() logrus.Level {
+	if s == nil || s.every == 0 {
+		return logrus.DebugLevel
+	}
+
+	if (s.count.Add(1)-1)%s.every == 0 {
+		return logrus.InfoLevel
+	}
+
+	return logrus.DebugLevel
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogSampler(t *testing.T) {
+	sampler := NewLogSampler(3)
+
+	want := []logrus.Level{logrus.InfoLevel, logrus.DebugLevel, logrus.DebugLevel, logrus.InfoLevel}
+	for i, level := range want {
+		if got := sampler.Level(); got != level {
+			t.Errorf("Line %d: expected %s, got %s", i, level, got)
+		}
+	}
+
+	if NewLogSampler(1).Level() != logrus.InfoLevel {
+		t.Error("Expected every line at info level when sampling one in one")
+	}
+
+	var disabled *LogSampler
+	if NewLogSampler(0).Level() != logrus.DebugLevel || disabled.Level() != logrus.DebugLevel {
+		t.Error("Expected every line at debug level when sampling is off")
+	}
+}
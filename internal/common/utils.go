@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/probe-lab/hermes/host"
 
@@ -12,38 +13,37 @@ import (
 
 const unknown = constants.Unknown
 
-// GetPeerID extracts the peer ID from a trace event.
+// GetPeerID extracts the peer ID from a trace event. Known Hermes payload types are read
+// through their adapter, anything else falls back to reflection, which is counted.
 func GetPeerID(event *host.TraceEvent) string {
 	if event == nil || event.Payload == nil {
 		return unknown
 	}
 
-	peerID := extractPeerIDFromStruct(event.Payload)
+	peerID, known := payloadPeerID(event.Payload)
+	if !known {
+		recordReflectionFallback(event.Payload)
+
+		peerID = reflectPeerID(event.Payload)
+	}
+
 	if peerID == "" {
 		return unknown
 	}
 
-	// Debug: log both the raw peerID and whether it looks like binary data
-	if len(peerID) > 20 && strings.Contains(peerID, "\u0000") {
-		// This looks like binary data, try to extract it properly using the old method
-		if payload, ok := event.Payload.(map[string]any); ok {
-			if remotePeerID, found := payload["PeerID"]; found {
-				converted := fmt.Sprintf("%v", remotePeerID)
-				if converted != peerID && len(converted) > 20 {
-					return converted
-				}
-			}
+	return peerID
+}
 
-			if remotePeerID, found := payload["RemotePeer"]; found {
-				converted := fmt.Sprintf("%v", remotePeerID)
-				if converted != peerID && len(converted) > 20 {
-					return converted
-				}
-			}
+// reflectPeerID extracts the peer ID from a payload of an unknown type by reflection. Payloads
+// it cannot walk yield no peer ID rather than a panic on the event path.
+func reflectPeerID(payload interface{}) (peerID string) {
+	defer func() {
+		if recover() != nil {
+			peerID = ""
 		}
-	}
+	}()
 
-	return peerID
+	return extractPeerIDFromStruct(payload)
 }
 
 // extractPeerIDFromStruct extracts peer ID from various payload structures using reflection.
@@ -79,6 +79,11 @@ func extractFromStruct(val reflect.Value) string {
 		field := val.Field(i)
 		fieldName := typ.Field(i).Name
 
+		// Unexported fields cannot be read
+		if !typ.Field(i).IsExported() {
+			continue
+		}
+
 		// Check common peer ID field names
 		if isPeerIDField(fieldName) {
 			if peerID := extractPeerIDValue(field); peerID != "" {
@@ -138,6 +143,13 @@ func extractPeerIDValue(field reflect.Value) string {
 		return ""
 	}
 
+	// Types like peer.ID keep a binary form and print the encoded one
+	if field.CanInterface() {
+		if stringer, ok := field.Interface().(fmt.Stringer); ok && field.Kind() != reflect.Ptr {
+			return stringer.String()
+		}
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		return field.String()
@@ -198,6 +210,61 @@ func NormalizeClientType(clientAgent string) string {
 	}
 }
 
+// GetPayloadString extracts a top-level string field from a trace event payload, which
+// may be a struct or a map. Fields holding a fmt.Stringer, such as multiaddrs, are
+// formatted. Missing or other fields return an empty string.
+func GetPayloadString(event *host.TraceEvent, field string) string {
+	if event == nil || event.Payload == nil {
+		return ""
+	}
+
+	val := reflect.ValueOf(event.Payload)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return ""
+		}
+
+		val = val.Elem()
+	}
+
+	var fieldVal reflect.Value
+
+	switch val.Kind() {
+	case reflect.Struct:
+		fieldVal = val.FieldByName(field)
+	case reflect.Map:
+		if val.Type().Key().Kind() == reflect.String {
+			fieldVal = val.MapIndex(reflect.ValueOf(field).Convert(val.Type().Key()))
+		}
+	}
+
+	if fieldVal.IsValid() && fieldVal.Kind() == reflect.Interface && !fieldVal.IsNil() {
+		fieldVal = fieldVal.Elem()
+	}
+
+	if fieldVal.IsValid() && fieldVal.CanInterface() && (fieldVal.Kind() != reflect.Ptr || !fieldVal.IsNil()) {
+		if stringer, ok := fieldVal.Interface().(fmt.Stringer); ok {
+			return stringer.String()
+		}
+	}
+
+	if !fieldVal.IsValid() || fieldVal.Kind() != reflect.String {
+		return ""
+	}
+
+	return fieldVal.String()
+}
+
+// GetEventTime returns the Hermes trace timestamp of an event, falling back to the
+// current time for events that were emitted without one.
+func GetEventTime(event *host.TraceEvent) time.Time {
+	if event == nil || event.Timestamp.IsZero() {
+		return time.Now()
+	}
+
+	return event.Timestamp
+}
+
 // FormatShortPeerID returns a shortened version of the peer ID for logging.
 func FormatShortPeerID(peerID string) string {
 	if len(peerID) <= 12 {
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/analyzers"
+)
+
+// AnalyzerSpec is a custom analyzer loaded at run time: a Go plugin or an external program.
+type AnalyzerSpec struct {
+	Source string `json:"source"` // analyzers.SourcePlugin or analyzers.SourceExec
+	Path   string `json:"path"`
+}
+
+// ParseAnalyzerSpecs parses a comma-separated list of custom analyzers, each a Go plugin or an
+// external program, e.g. "plugin:./mev-relays.so,exec:./scripts/client-versions.py".
+func ParseAnalyzerSpecs(spec string) ([]AnalyzerSpec, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	specs := make([]AnalyzerSpec, 0)
+
+	for _, entry := range strings.Split(spec, ",") {
+		source, path, found := strings.Cut(strings.TrimSpace(entry), ":")
+		path = strings.TrimSpace(path)
+
+		switch {
+		case !found || path == "":
+			return nil, fmt.Errorf("invalid analyzer %q: use plugin:<file.so> or exec:<program>", entry)
+		case source != analyzers.SourcePlugin && source != analyzers.SourceExec:
+			return nil, fmt.Errorf("unknown analyzer source %q: use plugin or exec", source)
+		}
+
+		specs = append(specs, AnalyzerSpec{Source: source, Path: path})
+	}
+
+	return specs, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAnalyzerSpecs(t *testing.T) {
+	specs, err := ParseAnalyzerSpecs("")
+	if err != nil || specs != nil {
+		t.Fatalf("Expected no analyzers for an empty spec, got %v, %v", specs, err)
+	}
+
+	specs, err = ParseAnalyzerSpecs("plugin:./mev-relays.so, exec:/opt/analyzers/client-versions.py")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []AnalyzerSpec{
+		{Source: "plugin", Path: "./mev-relays.so"},
+		{Source: "exec", Path: "/opt/analyzers/client-versions.py"},
+	}
+
+	if !reflect.DeepEqual(specs, want) {
+		t.Errorf("Expected %v, got %v", want, specs)
+	}
+
+	for _, invalid := range []string{"./mev-relays.so", "plugin:", "wasm:./mev.wasm", "exec:./a,,exec:./b"} {
+		if _, err := ParseAnalyzerSpecs(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
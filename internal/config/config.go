@@ -2,21 +2,40 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/probe-lab/hermes/eth"
 	"github.com/probe-lab/hermes/host"
 
 	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/progress"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+	"github.com/ethpandaops/hermes-peer-score/internal/signing"
 )
 
 // DefaultConfig implements the Config interface.
 type DefaultConfig struct {
 	// Tool configuration
-	validationMode ValidationMode
-	testDuration   time.Duration
-	reportInterval time.Duration
+	validationMode   ValidationMode
+	testDuration     time.Duration
+	warmupDuration   time.Duration
+	cooldownDuration time.Duration
+	reportInterval   time.Duration
+	retryWindow      time.Duration
+	eventBucketWidth time.Duration
+	burstThreshold   int
+	sampleRate       float64
+	sampleSeed       int64
+	eventRates       map[string]int // One in N events recorded in full, by event type
+	lateEventGrace   time.Duration
+	shutdownTimeout  time.Duration
+	includeTeardown  bool // Count the shutdown's disconnects and goodbyes in the statistics
+	smoke            bool // Short pipeline check, writing only the lite artifacts
 
 	// Connection settings
 	prysmHost       string
@@ -34,37 +53,186 @@ type DefaultConfig struct {
 	libp2pHost      string
 	libp2pPort      int
 	maxPeers        int
+	capacityRatio   float64
 	dialConcurrency int
+	agentVersion    string
+	hosts           []HostSpec
+	staticPeers     []StaticPeer
+	bootnodes       []string
+	meshDegree      MeshDegree
 
 	// Data stream settings
 	dataStreamType string
 	subnets        map[string]*eth.SubnetConfig
+	topicWhitelist []string
 
 	// Report settings
-	htmlOnly      bool
-	inputJSON     string
-	claudeAPIKey  string
-	skipAI        bool
-	updateGoMod   bool
-	validateGoMod bool
+	htmlOnly        bool
+	inputJSON       string
+	claudeAPIKey    string
+	skipAI          bool
+	updateGoMod     bool
+	validateGoMod   bool
+	splitReport     bool
+	shardSize       int
+	prettyData      bool
+	dataBudgetMB    int
+	swimlanePeers   int
+	headDivergence  uint64
+	previousReports []string
+	analyzers       []AnalyzerSpec
+
+	// AI analysis queue shared with concurrent runs, an empty directory disables it
+	aiQueueDir     string
+	aiConcurrency  int
+	aiQueueTimeout time.Duration
+
+	// How AI analysis reaches its API, OpenRouter directly unless set
+	aiBaseURL  string
+	aiProxy    string
+	aiCABundle string
+	aiTimeout  time.Duration
+
+	// Directory of templates replacing the embedded ones, for customised reports
+	templateDir string
+
+	// Fields dropped from or hashed in every artifact, for deployments that cannot store them
+	pruneFields   []string
+	hashFields    []string
+	pruneHashSalt string
+
+	// Output settings
+	publishURL string
+
+	// Score feed shared with other monitoring nodes, an empty URL disables it
+	scoreFeedURL        string
+	scoreFeedInstance   string
+	scoreFeedListenAddr string
+
+	// Validation mode experiment settings
+	experimentPhases        int
+	experimentPhaseDuration time.Duration
+	experimentBinaries      map[ValidationMode]string
+	experimentDir           string
+	experimentArgs          []string
+
+	// Mesh degree parameter sweep settings
+	paramSweep *ParamSweep
+	sweepDir   string
+
+	// MaxPeers ramp experiment settings
+	maxPeersRamp     []int
+	maxPeersRampStep time.Duration
+
+	// Checkpoint settings
+	checkpointFile     string
+	checkpointInterval time.Duration
+	resume             bool
+
+	// Reachability settings
+	reachabilityCheckURL   string
+	reachabilityListenAddr string
+	checkBeaconPeers       bool
+	beaconPeersInterval    time.Duration
+	clockSkewThreshold     time.Duration
+	beaconSyncInterval     time.Duration
+
+	// Canary peers staging known behaviour against the primary host
+	canary bool
+
+	// Connection negotiation failures read from the process-wide libp2p and Hermes logs
+	negotiationLogs bool
+
+	// Event starvation watchdog settings
+	starvationTimeout   time.Duration
+	restartOnStarvation bool
+
+	// Memory watchdog settings, a zero threshold disables spilling to disk
+	spillRSSMB int
+	spillDir   string
+
+	// Alerting settings
+	baselineJSON        string
+	regressionThreshold float64
+	alertGitHubRepo     string
+	artifactBaseURL     string
+
+	// Report signing settings
+	signScheme     string
+	signingKeyFile string
+
+	// Machine-readable progress output for wrapper automation
+	progressJSON string
+
+	// Journal of the run's warnings and errors, empty disables it
+	errorJournal string
+
+	// One peer traced at full detail into a file of its own, empty disables it
+	tracePeer     string
+	tracePeerFile string
+
+	// Logging settings, one in logSample per-event log lines is written at info level
+	statusInterval time.Duration
+	logSample      int
+	quiet          bool
 }
 
 // NewDefaultConfig creates a new configuration with default values.
 func NewDefaultConfig() *DefaultConfig {
 	cfg := &DefaultConfig{
-		validationMode:  ValidationModeDelegated,
-		testDuration:    constants.DefaultTestDuration,
-		reportInterval:  constants.DefaultReportInterval,
-		prysmHTTPPort:   constants.DefaultPrysmHTTPPort,
-		prysmGRPCPort:   constants.DefaultPrysmGRPCPort,
-		network:         "mainnet",
-		dialTimeout:     constants.DefaultDialTimeout,
-		devp2pHost:      constants.DefaultDevp2pHost,
-		libp2pHost:      constants.DefaultLibp2pHost,
-		maxPeers:        constants.DefaultMaxPeers,
-		dialConcurrency: constants.DefaultDialConcurrency,
-		dataStreamType:  constants.DefaultDataStreamType,
-		subnets:         make(map[string]*eth.SubnetConfig),
+		validationMode:   ValidationModeDelegated,
+		testDuration:     constants.DefaultTestDuration,
+		reportInterval:   constants.DefaultReportInterval,
+		retryWindow:      constants.DefaultHandshakeRetryWindow,
+		eventBucketWidth: constants.DefaultEventBucketWidth,
+		burstThreshold:   constants.DefaultEventBurstThreshold,
+		sampleRate:       constants.DefaultDetailSampleRate,
+		lateEventGrace:   constants.DefaultLateEventGrace,
+		shutdownTimeout:  constants.DefaultShutdownTimeout,
+		prysmHTTPPort:    constants.DefaultPrysmHTTPPort,
+		prysmGRPCPort:    constants.DefaultPrysmGRPCPort,
+		network:          "mainnet",
+		dialTimeout:      constants.DefaultDialTimeout,
+		devp2pHost:       constants.DefaultDevp2pHost,
+		libp2pHost:       constants.DefaultLibp2pHost,
+		maxPeers:         constants.DefaultMaxPeers,
+		maxPeersRampStep: constants.DefaultMaxPeersRampStep,
+		capacityRatio:    constants.DefaultCapacityRatio,
+		dialConcurrency:  constants.DefaultDialConcurrency,
+		agentVersion:     constants.DefaultAgentVersion,
+		meshDegree:       DefaultMeshDegree(),
+		dataStreamType:   constants.DefaultDataStreamType,
+		subnets:          make(map[string]*eth.SubnetConfig),
+		shardSize:        constants.DefaultShardSize,
+		dataBudgetMB:     constants.DefaultDataFileBudgetMB,
+		swimlanePeers:    constants.DefaultSwimlanePeers,
+		headDivergence:   constants.DefaultHeadDivergenceSlots,
+
+		aiConcurrency:  constants.DefaultAIConcurrency,
+		aiQueueTimeout: constants.DefaultAIQueueTimeout,
+		aiBaseURL:      constants.DefaultAIBaseURL,
+		aiTimeout:      constants.DefaultAIRequestTimeout,
+
+		checkpointFile:     constants.DefaultCheckpointFile,
+		checkpointInterval: constants.DefaultCheckpointInterval,
+
+		beaconPeersInterval: constants.DefaultBeaconPeersInterval,
+		beaconSyncInterval:  constants.DefaultBeaconSyncInterval,
+
+		errorJournal:  constants.DefaultErrorJournalFile,
+		tracePeerFile: constants.DefaultPeerTraceFile,
+
+		statusInterval: constants.DefaultStatusReportInterval,
+		logSample:      constants.DefaultLogSample,
+
+		experimentPhaseDuration: constants.DefaultExperimentPhase,
+		experimentDir:           constants.DefaultExperimentDir,
+
+		sweepDir: constants.DefaultSweepDir,
+
+		clockSkewThreshold:  constants.DefaultClockSkewThreshold,
+		starvationTimeout:   constants.DefaultStarvationTimeout,
+		regressionThreshold: constants.DefaultHandshakeRegressionThreshold,
 	}
 
 	return cfg
@@ -80,6 +248,66 @@ func (c *DefaultConfig) GetTestDuration() time.Duration {
 	return c.testDuration
 }
 
+// GetWarmupDuration returns how long the run warms up before measurement starts.
+func (c *DefaultConfig) GetWarmupDuration() time.Duration {
+	return c.warmupDuration
+}
+
+// GetCooldownDuration returns how long the run continues after measurement ends.
+func (c *DefaultConfig) GetCooldownDuration() time.Duration {
+	return c.cooldownDuration
+}
+
+// GetHandshakeRetryWindow returns how soon a reconnect must follow a failed handshake to count as a retry.
+func (c *DefaultConfig) GetHandshakeRetryWindow() time.Duration {
+	return c.retryWindow
+}
+
+// GetEventBucketWidth returns the width of the time buckets peer events are counted in.
+func (c *DefaultConfig) GetEventBucketWidth() time.Duration {
+	return c.eventBucketWidth
+}
+
+// GetEventBurstThreshold returns the events of one type in one bucket that count as a burst.
+func (c *DefaultConfig) GetEventBurstThreshold() int {
+	return c.burstThreshold
+}
+
+// GetLateEventGrace returns how long after a disconnect events still belong to the session that ended.
+func (c *DefaultConfig) GetLateEventGrace() time.Duration {
+	return c.lateEventGrace
+}
+
+// GetShutdownTimeout returns how long the shutdown phase waits for Hermes to stop after the run.
+func (c *DefaultConfig) GetShutdownTimeout() time.Duration {
+	return c.shutdownTimeout
+}
+
+// IsIncludeTeardown returns whether disconnects and goodbyes during the shutdown phase are counted in the statistics.
+func (c *DefaultConfig) IsIncludeTeardown() bool {
+	return c.includeTeardown
+}
+
+// GetDetailSampleRate returns the share of peers whose full detail is captured as a random baseline.
+func (c *DefaultConfig) GetDetailSampleRate() float64 {
+	return c.sampleRate
+}
+
+// GetDetailSampleSeed returns the seed the detail sample is drawn with.
+func (c *DefaultConfig) GetDetailSampleSeed() int64 {
+	return c.sampleSeed
+}
+
+// IsDetailSampled returns true if only a sample of peers has its full detail captured.
+func (c *DefaultConfig) IsDetailSampled() bool {
+	return c.sampleRate < 1
+}
+
+// GetEventSampleRates returns the event types recorded one in N, with N.
+func (c *DefaultConfig) GetEventSampleRates() map[string]int {
+	return c.eventRates
+}
+
 // GetReportInterval returns the report interval.
 func (c *DefaultConfig) GetReportInterval() time.Duration {
 	return c.reportInterval
@@ -120,11 +348,37 @@ func (c *DefaultConfig) GetMaxPeers() int {
 	return c.maxPeers
 }
 
+// GetMaxPeersRamp returns the MaxPeers values the run steps through, nil keeps MaxPeers fixed.
+func (c *DefaultConfig) GetMaxPeersRamp() []int {
+	return c.maxPeersRamp
+}
+
+// GetMaxPeersRampStep returns how long each MaxPeers ramp step lasts.
+func (c *DefaultConfig) GetMaxPeersRampStep() time.Duration {
+	return c.maxPeersRampStep
+}
+
+// GetCapacityRatio returns the share of the maximum number of peers at which the node
+// counts as at capacity.
+func (c *DefaultConfig) GetCapacityRatio() float64 {
+	return c.capacityRatio
+}
+
 // GetDialConcurrency returns the dial concurrency.
 func (c *DefaultConfig) GetDialConcurrency() int {
 	return c.dialConcurrency
 }
 
+// GetAgentVersion returns the agent version string advertised to peers.
+func (c *DefaultConfig) GetAgentVersion() string {
+	return c.agentVersion
+}
+
+// GetMeshDegree returns the gossipsub mesh degree Hermes runs with.
+func (c *DefaultConfig) GetMeshDegree() MeshDegree {
+	return c.meshDegree
+}
+
 // GetPrivateKeyStr returns the private key string.
 func (c *DefaultConfig) GetPrivateKeyStr() string {
 	return c.privateKeyStr
@@ -155,6 +409,35 @@ func (c *DefaultConfig) GetLibp2pPort() int {
 	return c.libp2pPort
 }
 
+// GetPrimaryLibp2pPort returns the libp2p port of the primary host, or 0 when Hermes picks one.
+func (c *DefaultConfig) GetPrimaryLibp2pPort() int {
+	if len(c.hosts) > 0 && c.hosts[0].Libp2pPort != 0 {
+		return c.hosts[0].Libp2pPort
+	}
+
+	return c.libp2pPort
+}
+
+// GetHosts returns the parallel Hermes hosts to run, or nil for a single host.
+func (c *DefaultConfig) GetHosts() []HostSpec {
+	return c.hosts
+}
+
+// GetStaticPeers returns the peers the node is pointed at explicitly, or nil for none.
+func (c *DefaultConfig) GetStaticPeers() []StaticPeer {
+	return c.staticPeers
+}
+
+// GetBootnodes returns the boot node ENRs replacing the network's, or nil to keep the network's.
+func (c *DefaultConfig) GetBootnodes() []string {
+	return c.bootnodes
+}
+
+// GetTopicWhitelist returns the gossip topic names collection is restricted to, or nil for all topics.
+func (c *DefaultConfig) GetTopicWhitelist() []string {
+	return c.topicWhitelist
+}
+
 // GetDataStreamType returns the data stream type.
 func (c *DefaultConfig) GetDataStreamType() string {
 	return c.dataStreamType
@@ -180,11 +463,71 @@ func (c *DefaultConfig) GetClaudeAPIKey() string {
 	return c.claudeAPIKey
 }
 
+// IsSmoke returns whether the run is a smoke test, checking only that the pipeline works.
+func (c *DefaultConfig) IsSmoke() bool {
+	return c.smoke
+}
+
 // IsSkipAI returns whether AI analysis should be skipped.
 func (c *DefaultConfig) IsSkipAI() bool {
 	return c.skipAI
 }
 
+// GetAIQueueDir returns the lock directory AI requests queue in with concurrent runs, empty when disabled.
+func (c *DefaultConfig) GetAIQueueDir() string {
+	return c.aiQueueDir
+}
+
+// GetAIConcurrency returns how many runs sharing the AI queue may call the AI API at once.
+func (c *DefaultConfig) GetAIConcurrency() int {
+	return c.aiConcurrency
+}
+
+// GetAIQueueTimeout returns how long a run waits in the AI queue before deferring its analysis.
+func (c *DefaultConfig) GetAIQueueTimeout() time.Duration {
+	return c.aiQueueTimeout
+}
+
+// GetAIBaseURL returns the OpenAI-compatible API base URL AI analysis is requested from.
+func (c *DefaultConfig) GetAIBaseURL() string {
+	return c.aiBaseURL
+}
+
+// GetAIProxy returns the HTTP(S) proxy AI requests go through, empty to use the environment's.
+func (c *DefaultConfig) GetAIProxy() string {
+	return c.aiProxy
+}
+
+// GetTemplateDir returns the directory of templates replacing the embedded ones, empty when none.
+func (c *DefaultConfig) GetTemplateDir() string {
+	return c.templateDir
+}
+
+// GetAICABundle returns the PEM file of extra CAs trusted for AI requests, empty when none.
+func (c *DefaultConfig) GetAICABundle() string {
+	return c.aiCABundle
+}
+
+// GetAITimeout returns how long an AI request may take.
+func (c *DefaultConfig) GetAITimeout() time.Duration {
+	return c.aiTimeout
+}
+
+// GetPruneFields returns the JSON paths dropped from every artifact.
+func (c *DefaultConfig) GetPruneFields() []string {
+	return c.pruneFields
+}
+
+// GetHashFields returns the JSON paths hashed in every artifact.
+func (c *DefaultConfig) GetHashFields() []string {
+	return c.hashFields
+}
+
+// GetPruneHashSalt returns the secret salt hashed fields are keyed with, empty for plain hashes.
+func (c *DefaultConfig) GetPruneHashSalt() string {
+	return c.pruneHashSalt
+}
+
 // IsUpdateGoMod returns whether go.mod should be updated.
 func (c *DefaultConfig) IsUpdateGoMod() bool {
 	return c.updateGoMod
@@ -195,6 +538,251 @@ func (c *DefaultConfig) IsValidateGoMod() bool {
 	return c.validateGoMod
 }
 
+// IsSplitReport returns whether the HTML report data should be split into index shards.
+func (c *DefaultConfig) IsSplitReport() bool {
+	return c.splitReport
+}
+
+// GetShardSize returns the number of peers per report shard.
+func (c *DefaultConfig) GetShardSize() int {
+	return c.shardSize
+}
+
+// IsPrettyDataFile returns whether the HTML report data file is indented.
+func (c *DefaultConfig) IsPrettyDataFile() bool {
+	return c.prettyData
+}
+
+// GetDataFileBudgetMB returns the memory budget, in MiB, for peers encoded at once while writing the data file.
+func (c *DefaultConfig) GetDataFileBudgetMB() int {
+	return c.dataBudgetMB
+}
+
+// GetSwimlanePeers returns the number of peers drawn in the swimlane view, 0 disables it.
+func (c *DefaultConfig) GetSwimlanePeers() int {
+	return c.swimlanePeers
+}
+
+// GetHeadDivergenceSlots returns how many slots a peer's head may trail ours before it is
+// reported as diverging.
+func (c *DefaultConfig) GetHeadDivergenceSlots() uint64 {
+	return c.headDivergence
+}
+
+// GetPreviousReports returns the earlier JSON reports, or glob patterns matching them, whose
+// peer sets the run is compared with.
+func (c *DefaultConfig) GetPreviousReports() []string {
+	return c.previousReports
+}
+
+// GetAnalyzers returns the custom analyzers loaded from Go plugins and external programs.
+func (c *DefaultConfig) GetAnalyzers() []AnalyzerSpec {
+	return c.analyzers
+}
+
+// GetPublishURL returns the HTTP ingest endpoint summary metrics are published to.
+func (c *DefaultConfig) GetPublishURL() string {
+	return c.publishURL
+}
+
+// GetScoreFeedURL returns the score feed peer scores are shared through with other monitoring nodes.
+func (c *DefaultConfig) GetScoreFeedURL() string {
+	return c.scoreFeedURL
+}
+
+// GetScoreFeedInstance returns the name this instance publishes its scores under, empty for the host name.
+func (c *DefaultConfig) GetScoreFeedInstance() string {
+	return c.scoreFeedInstance
+}
+
+// GetScoreFeedListenAddr returns the address a score feed server is run on instead of a test.
+func (c *DefaultConfig) GetScoreFeedListenAddr() string {
+	return c.scoreFeedListenAddr
+}
+
+// GetExperimentPhases returns the number of alternating validation mode sub-runs, 0 disables the experiment.
+func (c *DefaultConfig) GetExperimentPhases() int {
+	return c.experimentPhases
+}
+
+// GetExperimentPhaseDuration returns the duration of each validation mode sub-run.
+func (c *DefaultConfig) GetExperimentPhaseDuration() time.Duration {
+	return c.experimentPhaseDuration
+}
+
+// GetExperimentBinaries returns the peer score binary built for each validation mode.
+func (c *DefaultConfig) GetExperimentBinaries() map[ValidationMode]string {
+	return c.experimentBinaries
+}
+
+// GetExperimentDir returns the directory experiment sub-runs write their reports to.
+func (c *DefaultConfig) GetExperimentDir() string {
+	return c.experimentDir
+}
+
+// GetExperimentArgs returns the command-line arguments passed on to experiment sub-runs.
+func (c *DefaultConfig) GetExperimentArgs() []string {
+	return c.experimentArgs
+}
+
+// GetParamSweep returns the mesh degree parameter sweep to run, nil runs a single test.
+func (c *DefaultConfig) GetParamSweep() *ParamSweep {
+	return c.paramSweep
+}
+
+// GetSweepDir returns the directory parameter sweep sub-tests write their reports to.
+func (c *DefaultConfig) GetSweepDir() string {
+	return c.sweepDir
+}
+
+// GetCheckpointFile returns the file collector state is checkpointed to.
+func (c *DefaultConfig) GetCheckpointFile() string {
+	return c.checkpointFile
+}
+
+// GetCheckpointInterval returns how often collector state is checkpointed, 0 disables checkpoints.
+func (c *DefaultConfig) GetCheckpointInterval() time.Duration {
+	return c.checkpointInterval
+}
+
+// IsResume returns whether the run resumes from its checkpoint.
+func (c *DefaultConfig) IsResume() bool {
+	return c.resume
+}
+
+// GetReachabilityCheckURL returns the dial-back vantage used to check our libp2p port is reachable.
+func (c *DefaultConfig) GetReachabilityCheckURL() string {
+	return c.reachabilityCheckURL
+}
+
+// GetReachabilityListenAddr returns the address to serve as a dial-back vantage on.
+func (c *DefaultConfig) GetReachabilityListenAddr() string {
+	return c.reachabilityListenAddr
+}
+
+// IsCheckBeaconPeers returns whether Hermes' peers are cross-checked against the beacon node's at the end of the run.
+func (c *DefaultConfig) IsCheckBeaconPeers() bool {
+	return c.checkBeaconPeers
+}
+
+// GetBeaconPeersInterval returns how often the beacon node's peers are snapshotted during the
+// run when cross-checking, 0 checks at the end of the run only.
+func (c *DefaultConfig) GetBeaconPeersInterval() time.Duration {
+	return c.beaconPeersInterval
+}
+
+// GetClockSkewThreshold returns the offset from the beacon node's clock that is flagged as skew, 0 disables the check.
+func (c *DefaultConfig) GetClockSkewThreshold() time.Duration {
+	return c.clockSkewThreshold
+}
+
+// GetBeaconSyncInterval returns how often delegated validation's beacon node is checked for
+// being synced, 0 disables the checks.
+func (c *DefaultConfig) GetBeaconSyncInterval() time.Duration {
+	return c.beaconSyncInterval
+}
+
+// IsCanary returns whether canary peers with known behaviour connect to the primary host to
+// check its scoring of them.
+func (c *DefaultConfig) IsCanary() bool {
+	return c.canary
+}
+
+// IsNegotiationLogs returns whether connection negotiation failures are read from the libp2p
+// and Hermes logs, which takes over the process-wide slog default and go-log output for the run.
+func (c *DefaultConfig) IsNegotiationLogs() bool {
+	return c.negotiationLogs
+}
+
+// GetStarvationTimeout returns how long the run may go without any event before the node is
+// considered wedged, 0 disables the watchdog.
+func (c *DefaultConfig) GetStarvationTimeout() time.Duration {
+	return c.starvationTimeout
+}
+
+// IsRestartOnStarvation returns whether Hermes is restarted when its events stop arriving.
+func (c *DefaultConfig) IsRestartOnStarvation() bool {
+	return c.restartOnStarvation
+}
+
+// GetSpillRSSMB returns the resident memory, in MiB, above which completed sessions' events are
+// spilled to disk, 0 disables spilling.
+func (c *DefaultConfig) GetSpillRSSMB() int {
+	return c.spillRSSMB
+}
+
+// GetSpillDir returns the directory spilled events are written to, empty for the system
+// temporary directory.
+func (c *DefaultConfig) GetSpillDir() string {
+	return c.spillDir
+}
+
+// GetBaselineJSON returns the previous JSON report the run is compared against.
+func (c *DefaultConfig) GetBaselineJSON() string {
+	return c.baselineJSON
+}
+
+// GetRegressionThreshold returns the relative handshake success drop that counts as a regression.
+func (c *DefaultConfig) GetRegressionThreshold() float64 {
+	return c.regressionThreshold
+}
+
+// GetAlertGitHubRepo returns the owner/name repository regression issues are filed in.
+func (c *DefaultConfig) GetAlertGitHubRepo() string {
+	return c.alertGitHubRepo
+}
+
+// GetArtifactBaseURL returns the public URL the run's reports are published under.
+func (c *DefaultConfig) GetArtifactBaseURL() string {
+	return c.artifactBaseURL
+}
+
+// GetSignScheme returns the scheme the JSON report and manifest are signed with, empty when unsigned.
+func (c *DefaultConfig) GetSignScheme() string {
+	return c.signScheme
+}
+
+// GetSigningKeyFile returns the PEM private key file ed25519 signatures are made with.
+func (c *DefaultConfig) GetSigningKeyFile() string {
+	return c.signingKeyFile
+}
+
+// GetProgressJSON returns where JSON progress events are written, empty when disabled.
+func (c *DefaultConfig) GetProgressJSON() string {
+	return c.progressJSON
+}
+
+// GetErrorJournal returns the file the run's warnings and errors are journaled to, empty when disabled.
+func (c *DefaultConfig) GetErrorJournal() string {
+	return c.errorJournal
+}
+
+// GetTracePeer returns the peer traced at full detail, empty when none is.
+func (c *DefaultConfig) GetTracePeer() string {
+	return c.tracePeer
+}
+
+// GetTracePeerFile returns the file the traced peer's records are written to.
+func (c *DefaultConfig) GetTracePeerFile() string {
+	return c.tracePeerFile
+}
+
+// GetStatusInterval returns how often the run's status is reported, 0 disables status reports.
+func (c *DefaultConfig) GetStatusInterval() time.Duration {
+	return c.statusInterval
+}
+
+// GetLogSample returns N where one in N per-event log lines is written at info level and the
+// rest at debug level, 0 writes them all at debug level.
+func (c *DefaultConfig) GetLogSample() int {
+	return c.logSample
+}
+
+// IsQuiet returns whether only warnings and errors are logged.
+func (c *DefaultConfig) IsQuiet() bool {
+	return c.quiet
+}
+
 // SetValidationMode sets the validation mode.
 func (c *DefaultConfig) SetValidationMode(mode ValidationMode) {
 	c.validationMode = mode
@@ -205,6 +793,61 @@ func (c *DefaultConfig) SetTestDuration(duration time.Duration) {
 	c.testDuration = duration
 }
 
+// SetWarmupDuration sets how long the run warms up before measurement starts.
+func (c *DefaultConfig) SetWarmupDuration(duration time.Duration) {
+	c.warmupDuration = duration
+}
+
+// SetCooldownDuration sets how long the run continues after measurement ends.
+func (c *DefaultConfig) SetCooldownDuration(duration time.Duration) {
+	c.cooldownDuration = duration
+}
+
+// SetHandshakeRetryWindow sets how soon a reconnect must follow a failed handshake to count as a retry.
+func (c *DefaultConfig) SetHandshakeRetryWindow(window time.Duration) {
+	c.retryWindow = window
+}
+
+// SetEventBucketWidth sets the width of the time buckets peer events are counted in.
+func (c *DefaultConfig) SetEventBucketWidth(width time.Duration) {
+	c.eventBucketWidth = width
+}
+
+// SetEventBurstThreshold sets the events of one type in one bucket that count as a burst.
+func (c *DefaultConfig) SetEventBurstThreshold(threshold int) {
+	c.burstThreshold = threshold
+}
+
+// SetLateEventGrace sets how long after a disconnect events still belong to the session that ended.
+func (c *DefaultConfig) SetLateEventGrace(grace time.Duration) {
+	c.lateEventGrace = grace
+}
+
+// SetShutdownTimeout sets how long the shutdown phase waits for Hermes to stop after the run.
+func (c *DefaultConfig) SetShutdownTimeout(timeout time.Duration) {
+	c.shutdownTimeout = timeout
+}
+
+// SetIncludeTeardown sets whether disconnects and goodbyes during the shutdown phase are counted in the statistics.
+func (c *DefaultConfig) SetIncludeTeardown(include bool) {
+	c.includeTeardown = include
+}
+
+// SetDetailSampleRate sets the share of peers whose full detail is captured as a random baseline.
+func (c *DefaultConfig) SetDetailSampleRate(rate float64) {
+	c.sampleRate = rate
+}
+
+// SetDetailSampleSeed sets the seed the detail sample is drawn with.
+func (c *DefaultConfig) SetDetailSampleSeed(seed int64) {
+	c.sampleSeed = seed
+}
+
+// SetEventSampleRates sets the event types recorded one in N, with N.
+func (c *DefaultConfig) SetEventSampleRates(rates map[string]int) {
+	c.eventRates = rates
+}
+
 // SetPrysmHost sets the Prysm host.
 func (c *DefaultConfig) SetPrysmHost(host string) {
 	c.prysmHost = host
@@ -235,6 +878,62 @@ func (c *DefaultConfig) SetDevnetApacheURL(url string) {
 	c.devnetApacheURL = url
 }
 
+// SetMaxPeers sets the maximum number of peers.
+func (c *DefaultConfig) SetMaxPeers(maxPeers int) {
+	c.maxPeers = maxPeers
+}
+
+// SetMaxPeersRamp sets the MaxPeers values the run steps through.
+func (c *DefaultConfig) SetMaxPeersRamp(ramp []int) {
+	c.maxPeersRamp = ramp
+}
+
+// SetMaxPeersRampStep sets how long each MaxPeers ramp step lasts.
+func (c *DefaultConfig) SetMaxPeersRampStep(step time.Duration) {
+	c.maxPeersRampStep = step
+}
+
+// SetCapacityRatio sets the share of the maximum number of peers at which the node counts
+// as at capacity.
+func (c *DefaultConfig) SetCapacityRatio(ratio float64) {
+	c.capacityRatio = ratio
+}
+
+// SetAgentVersion sets the agent version string advertised to peers.
+func (c *DefaultConfig) SetAgentVersion(agentVersion string) {
+	c.agentVersion = agentVersion
+}
+
+// SetLibp2pPort sets the libp2p listen port, 0 lets Hermes pick one.
+func (c *DefaultConfig) SetLibp2pPort(port int) {
+	c.libp2pPort = port
+}
+
+// SetPrivateKeyStr sets the hex-encoded libp2p private key, empty generates a new identity.
+func (c *DefaultConfig) SetPrivateKeyStr(privateKey string) {
+	c.privateKeyStr = privateKey
+}
+
+// SetHosts sets the parallel Hermes hosts to run.
+func (c *DefaultConfig) SetHosts(hosts []HostSpec) {
+	c.hosts = hosts
+}
+
+// SetStaticPeers sets the peers the node is pointed at explicitly.
+func (c *DefaultConfig) SetStaticPeers(peers []StaticPeer) {
+	c.staticPeers = peers
+}
+
+// SetBootnodes sets the boot node ENRs replacing the network's, nil to keep the network's.
+func (c *DefaultConfig) SetBootnodes(records []string) {
+	c.bootnodes = records
+}
+
+// SetTopicWhitelist sets the gossip topic names collection is restricted to, nil for all topics.
+func (c *DefaultConfig) SetTopicWhitelist(topics []string) {
+	c.topicWhitelist = topics
+}
+
 // SetHTMLOnly sets HTML-only mode.
 func (c *DefaultConfig) SetHTMLOnly(htmlOnly bool) {
 	c.htmlOnly = htmlOnly
@@ -250,11 +949,71 @@ func (c *DefaultConfig) SetClaudeAPIKey(apiKey string) {
 	c.claudeAPIKey = apiKey
 }
 
+// SetSmoke sets whether the run is a smoke test, checking only that the pipeline works.
+func (c *DefaultConfig) SetSmoke(smoke bool) {
+	c.smoke = smoke
+}
+
 // SetSkipAI sets whether to skip AI analysis.
 func (c *DefaultConfig) SetSkipAI(skipAI bool) {
 	c.skipAI = skipAI
 }
 
+// SetAIQueueDir sets the lock directory AI requests queue in with concurrent runs, empty disables the queue.
+func (c *DefaultConfig) SetAIQueueDir(dir string) {
+	c.aiQueueDir = dir
+}
+
+// SetAIConcurrency sets how many runs sharing the AI queue may call the AI API at once.
+func (c *DefaultConfig) SetAIConcurrency(concurrency int) {
+	c.aiConcurrency = concurrency
+}
+
+// SetAIQueueTimeout sets how long a run waits in the AI queue before deferring its analysis.
+func (c *DefaultConfig) SetAIQueueTimeout(timeout time.Duration) {
+	c.aiQueueTimeout = timeout
+}
+
+// SetAIBaseURL sets the OpenAI-compatible API base URL AI analysis is requested from.
+func (c *DefaultConfig) SetAIBaseURL(baseURL string) {
+	c.aiBaseURL = baseURL
+}
+
+// SetAIProxy sets the HTTP(S) proxy AI requests go through, empty to use the environment's.
+func (c *DefaultConfig) SetAIProxy(proxy string) {
+	c.aiProxy = proxy
+}
+
+// SetAICABundle sets the PEM file of extra CAs trusted for AI requests.
+func (c *DefaultConfig) SetAICABundle(file string) {
+	c.aiCABundle = file
+}
+
+// SetTemplateDir sets the directory of templates replacing the embedded ones.
+func (c *DefaultConfig) SetTemplateDir(dir string) {
+	c.templateDir = dir
+}
+
+// SetAITimeout sets how long an AI request may take.
+func (c *DefaultConfig) SetAITimeout(timeout time.Duration) {
+	c.aiTimeout = timeout
+}
+
+// SetPruneFields sets the JSON paths dropped from every artifact.
+func (c *DefaultConfig) SetPruneFields(paths []string) {
+	c.pruneFields = paths
+}
+
+// SetHashFields sets the JSON paths hashed in every artifact.
+func (c *DefaultConfig) SetHashFields(paths []string) {
+	c.hashFields = paths
+}
+
+// SetPruneHashSalt sets the secret salt hashed fields are keyed with.
+func (c *DefaultConfig) SetPruneHashSalt(salt string) {
+	c.pruneHashSalt = salt
+}
+
 // SetUpdateGoMod sets whether to update go.mod.
 func (c *DefaultConfig) SetUpdateGoMod(update bool) {
 	c.updateGoMod = update
@@ -265,6 +1024,248 @@ func (c *DefaultConfig) SetValidateGoMod(validate bool) {
 	c.validateGoMod = validate
 }
 
+// SetSplitReport sets whether the HTML report data should be split into index shards.
+func (c *DefaultConfig) SetSplitReport(splitReport bool) {
+	c.splitReport = splitReport
+}
+
+// SetShardSize sets the number of peers per report shard.
+func (c *DefaultConfig) SetShardSize(shardSize int) {
+	c.shardSize = shardSize
+}
+
+// SetPrettyDataFile sets whether the HTML report data file is indented.
+func (c *DefaultConfig) SetPrettyDataFile(pretty bool) {
+	c.prettyData = pretty
+}
+
+// SetDataFileBudgetMB sets the memory budget, in MiB, for peers encoded at once while writing the data file.
+func (c *DefaultConfig) SetDataFileBudgetMB(budget int) {
+	c.dataBudgetMB = budget
+}
+
+// SetSwimlanePeers sets the number of peers drawn in the swimlane view, 0 disables it.
+func (c *DefaultConfig) SetSwimlanePeers(peers int) {
+	c.swimlanePeers = peers
+}
+
+// SetHeadDivergenceSlots sets how many slots a peer's head may trail ours before it is
+// reported as diverging.
+func (c *DefaultConfig) SetHeadDivergenceSlots(slots uint64) {
+	c.headDivergence = slots
+}
+
+// SetPreviousReports sets the earlier JSON reports, or glob patterns matching them, whose
+// peer sets the run is compared with.
+func (c *DefaultConfig) SetPreviousReports(reports []string) {
+	c.previousReports = reports
+}
+
+// SetAnalyzers sets the custom analyzers loaded from Go plugins and external programs.
+func (c *DefaultConfig) SetAnalyzers(specs []AnalyzerSpec) {
+	c.analyzers = specs
+}
+
+// SetPublishURL sets the HTTP ingest endpoint summary metrics are published to.
+func (c *DefaultConfig) SetPublishURL(publishURL string) {
+	c.publishURL = publishURL
+}
+
+// SetScoreFeedURL sets the score feed peer scores are shared through with other monitoring nodes.
+func (c *DefaultConfig) SetScoreFeedURL(feedURL string) {
+	c.scoreFeedURL = feedURL
+}
+
+// SetScoreFeedInstance sets the name this instance publishes its scores under.
+func (c *DefaultConfig) SetScoreFeedInstance(instance string) {
+	c.scoreFeedInstance = instance
+}
+
+// SetScoreFeedListenAddr sets the address a score feed server is run on instead of a test.
+func (c *DefaultConfig) SetScoreFeedListenAddr(addr string) {
+	c.scoreFeedListenAddr = addr
+}
+
+// SetExperimentPhases sets the number of alternating validation mode sub-runs, 0 disables the experiment.
+func (c *DefaultConfig) SetExperimentPhases(phases int) {
+	c.experimentPhases = phases
+}
+
+// SetExperimentPhaseDuration sets the duration of each validation mode sub-run.
+func (c *DefaultConfig) SetExperimentPhaseDuration(duration time.Duration) {
+	c.experimentPhaseDuration = duration
+}
+
+// SetExperimentBinaries sets the peer score binary built for each validation mode.
+func (c *DefaultConfig) SetExperimentBinaries(binaries map[ValidationMode]string) {
+	c.experimentBinaries = binaries
+}
+
+// SetExperimentDir sets the directory experiment sub-runs write their reports to.
+func (c *DefaultConfig) SetExperimentDir(dir string) {
+	c.experimentDir = dir
+}
+
+// SetExperimentArgs sets the command-line arguments passed on to experiment sub-runs.
+func (c *DefaultConfig) SetExperimentArgs(args []string) {
+	c.experimentArgs = args
+}
+
+// SetMeshDegree sets the gossipsub mesh degree Hermes runs with.
+func (c *DefaultConfig) SetMeshDegree(degree MeshDegree) {
+	c.meshDegree = degree
+}
+
+// SetParamSweep sets the mesh degree parameter sweep to run.
+func (c *DefaultConfig) SetParamSweep(sweep *ParamSweep) {
+	c.paramSweep = sweep
+}
+
+// SetSweepDir sets the directory parameter sweep sub-tests write their reports to.
+func (c *DefaultConfig) SetSweepDir(dir string) {
+	c.sweepDir = dir
+}
+
+// SetCheckpointFile sets the file collector state is checkpointed to.
+func (c *DefaultConfig) SetCheckpointFile(path string) {
+	c.checkpointFile = path
+}
+
+// SetCheckpointInterval sets how often collector state is checkpointed, 0 disables checkpoints.
+func (c *DefaultConfig) SetCheckpointInterval(interval time.Duration) {
+	c.checkpointInterval = interval
+}
+
+// SetResume sets whether the run resumes from its checkpoint.
+func (c *DefaultConfig) SetResume(resume bool) {
+	c.resume = resume
+}
+
+// SetReachabilityCheckURL sets the dial-back vantage used to check our libp2p port is reachable.
+func (c *DefaultConfig) SetReachabilityCheckURL(checkURL string) {
+	c.reachabilityCheckURL = checkURL
+}
+
+// SetReachabilityListenAddr sets the address to serve as a dial-back vantage on.
+func (c *DefaultConfig) SetReachabilityListenAddr(addr string) {
+	c.reachabilityListenAddr = addr
+}
+
+// SetCheckBeaconPeers sets whether Hermes' peers are cross-checked against the beacon node's at the end of the run.
+func (c *DefaultConfig) SetCheckBeaconPeers(check bool) {
+	c.checkBeaconPeers = check
+}
+
+// SetBeaconPeersInterval sets how often the beacon node's peers are snapshotted during the run.
+func (c *DefaultConfig) SetBeaconPeersInterval(interval time.Duration) {
+	c.beaconPeersInterval = interval
+}
+
+// SetClockSkewThreshold sets the offset from the beacon node's clock that is flagged as skew.
+func (c *DefaultConfig) SetClockSkewThreshold(threshold time.Duration) {
+	c.clockSkewThreshold = threshold
+}
+
+// SetBeaconSyncInterval sets how often delegated validation's beacon node is checked for being synced.
+func (c *DefaultConfig) SetBeaconSyncInterval(interval time.Duration) {
+	c.beaconSyncInterval = interval
+}
+
+// SetCanary sets whether canary peers with known behaviour connect to the primary host.
+func (c *DefaultConfig) SetCanary(canary bool) {
+	c.canary = canary
+}
+
+// SetNegotiationLogs sets whether connection negotiation failures are read from the libp2p and Hermes logs.
+func (c *DefaultConfig) SetNegotiationLogs(enabled bool) {
+	c.negotiationLogs = enabled
+}
+
+// SetStarvationTimeout sets how long the run may go without any event before the node is considered wedged.
+func (c *DefaultConfig) SetStarvationTimeout(timeout time.Duration) {
+	c.starvationTimeout = timeout
+}
+
+// SetRestartOnStarvation sets whether Hermes is restarted when its events stop arriving.
+func (c *DefaultConfig) SetRestartOnStarvation(restart bool) {
+	c.restartOnStarvation = restart
+}
+
+// SetSpillRSSMB sets the resident memory, in MiB, above which completed sessions' events are spilled to disk.
+func (c *DefaultConfig) SetSpillRSSMB(threshold int) {
+	c.spillRSSMB = threshold
+}
+
+// SetSpillDir sets the directory spilled events are written to.
+func (c *DefaultConfig) SetSpillDir(dir string) {
+	c.spillDir = dir
+}
+
+// SetBaselineJSON sets the previous JSON report the run is compared against.
+func (c *DefaultConfig) SetBaselineJSON(path string) {
+	c.baselineJSON = path
+}
+
+// SetRegressionThreshold sets the relative handshake success drop that counts as a regression.
+func (c *DefaultConfig) SetRegressionThreshold(threshold float64) {
+	c.regressionThreshold = threshold
+}
+
+// SetAlertGitHubRepo sets the owner/name repository regression issues are filed in.
+func (c *DefaultConfig) SetAlertGitHubRepo(repo string) {
+	c.alertGitHubRepo = repo
+}
+
+// SetArtifactBaseURL sets the public URL the run's reports are published under.
+func (c *DefaultConfig) SetArtifactBaseURL(baseURL string) {
+	c.artifactBaseURL = baseURL
+}
+
+// SetSignScheme sets the scheme the JSON report and manifest are signed with, empty for none.
+func (c *DefaultConfig) SetSignScheme(scheme string) {
+	c.signScheme = scheme
+}
+
+// SetSigningKeyFile sets the PEM private key file ed25519 signatures are made with.
+func (c *DefaultConfig) SetSigningKeyFile(keyFile string) {
+	c.signingKeyFile = keyFile
+}
+
+// SetProgressJSON sets where JSON progress events are written: stdout, stderr, fd:N or a file path.
+func (c *DefaultConfig) SetProgressJSON(target string) {
+	c.progressJSON = target
+}
+
+// SetErrorJournal sets the file the run's warnings and errors are journaled to, empty disables it.
+func (c *DefaultConfig) SetErrorJournal(path string) {
+	c.errorJournal = path
+}
+
+// SetTracePeer sets the peer traced at full detail, empty disables tracing.
+func (c *DefaultConfig) SetTracePeer(peerID string) {
+	c.tracePeer = strings.TrimSpace(peerID)
+}
+
+// SetTracePeerFile sets the file the traced peer's records are written to.
+func (c *DefaultConfig) SetTracePeerFile(path string) {
+	c.tracePeerFile = path
+}
+
+// SetStatusInterval sets how often the run's status is reported, 0 disables status reports.
+func (c *DefaultConfig) SetStatusInterval(interval time.Duration) {
+	c.statusInterval = interval
+}
+
+// SetLogSample sets N where one in N per-event log lines is written at info level.
+func (c *DefaultConfig) SetLogSample(every int) {
+	c.logSample = every
+}
+
+// SetQuiet sets whether only warnings and errors are logged.
+func (c *DefaultConfig) SetQuiet(quiet bool) {
+	c.quiet = quiet
+}
+
 // Validate validates the configuration.
 func (c *DefaultConfig) Validate() error {
 	// Validation mode-specific validation
@@ -285,6 +1286,85 @@ func (c *DefaultConfig) Validate() error {
 		return fmt.Errorf("test duration must be positive")
 	}
 
+	// Warmup and cooldown are optional but cannot be negative
+	if c.warmupDuration < 0 || c.cooldownDuration < 0 {
+		return fmt.Errorf("warmup and cooldown durations must not be negative")
+	}
+
+	if c.retryWindow < 0 {
+		return fmt.Errorf("handshake retry window must not be negative")
+	}
+
+	if c.clockSkewThreshold < 0 {
+		return fmt.Errorf("clock skew threshold must not be negative")
+	}
+
+	if c.starvationTimeout < 0 {
+		return fmt.Errorf("starvation timeout must not be negative")
+	}
+
+	if c.spillRSSMB < 0 {
+		return fmt.Errorf("spill memory threshold must not be negative")
+	}
+
+	if c.statusInterval < 0 {
+		return fmt.Errorf("status interval must not be negative")
+	}
+
+	if c.logSample < 0 {
+		return fmt.Errorf("log sample must not be negative")
+	}
+
+	if c.lateEventGrace < 0 {
+		return fmt.Errorf("late event grace window must not be negative")
+	}
+
+	if c.shutdownTimeout < 0 {
+		return fmt.Errorf("shutdown timeout must not be negative")
+	}
+
+	// Event buckets need a width, a zero burst threshold disables burst detection
+	if c.eventBucketWidth <= 0 {
+		return fmt.Errorf("event bucket width must be positive")
+	}
+
+	if c.burstThreshold < 0 {
+		return fmt.Errorf("event burst threshold must not be negative")
+	}
+
+	if c.sampleRate <= 0 || c.sampleRate > 1 {
+		return fmt.Errorf("detail sample rate must be greater than 0 and at most 1")
+	}
+
+	if c.maxPeers <= 0 {
+		return fmt.Errorf("max peers must be positive")
+	}
+
+	if len(c.maxPeersRamp) > 0 && c.maxPeersRampStep <= 0 {
+		return fmt.Errorf("max peers ramp step must be positive")
+	}
+
+	if c.capacityRatio <= 0 || c.capacityRatio > 1 {
+		return fmt.Errorf("capacity ratio must be greater than 0 and at most 1")
+	}
+
+	// Checkpoints need a file to write to, and resuming needs one to read from
+	if c.checkpointInterval < 0 {
+		return fmt.Errorf("checkpoint interval must not be negative")
+	}
+
+	if (c.checkpointInterval > 0 || c.resume) && c.checkpointFile == "" {
+		return fmt.Errorf("checkpoint file must be set when checkpointing or resuming")
+	}
+
+	if c.beaconPeersInterval < 0 {
+		return fmt.Errorf("beacon peers interval must not be negative")
+	}
+
+	if c.beaconSyncInterval < 0 {
+		return fmt.Errorf("beacon sync interval must not be negative")
+	}
+
 	// Ports should be valid
 	if c.prysmHTTPPort <= 0 || c.prysmHTTPPort > 65535 {
 		return fmt.Errorf("prysm HTTP port must be between 1 and 65535")
@@ -294,6 +1374,202 @@ func (c *DefaultConfig) Validate() error {
 		return fmt.Errorf("prysm gRPC port must be between 1 and 65535")
 	}
 
+	// Split reports need a usable shard size
+	if c.splitReport && c.shardSize <= 0 {
+		return fmt.Errorf("shard size must be positive when split reports are enabled")
+	}
+
+	// The AI queue needs at least one slot to hand out
+	if c.aiQueueDir != "" && c.aiConcurrency < 1 {
+		return fmt.Errorf("AI concurrency must be at least 1")
+	}
+
+	if c.aiQueueTimeout < 0 {
+		return fmt.Errorf("AI queue timeout must not be negative")
+	}
+
+	// AI requests go to an OpenAI-compatible API, possibly through a proxy
+	if parsed, err := url.Parse(c.aiBaseURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("AI base URL must be an absolute http or https URL")
+	}
+
+	if c.aiProxy != "" {
+		parsed, err := url.Parse(c.aiProxy)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("AI proxy must be an absolute http or https URL")
+		}
+	}
+
+	if c.aiTimeout <= 0 {
+		return fmt.Errorf("AI timeout must be positive")
+	}
+
+	if _, err := prune.New(c.pruneFields, c.hashFields, c.pruneHashSalt); err != nil {
+		return fmt.Errorf("invalid field pruning policy: %w", err)
+	}
+
+	if c.dataBudgetMB <= 0 {
+		return fmt.Errorf("data file memory budget must be positive")
+	}
+
+	if c.swimlanePeers < 0 {
+		return fmt.Errorf("swimlane peers cannot be negative")
+	}
+
+	if c.headDivergence == 0 {
+		return fmt.Errorf("head divergence slots must be positive")
+	}
+
+	// Publish endpoint must be an absolute HTTP(S) URL
+	if c.publishURL != "" {
+		parsed, err := url.Parse(c.publishURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("publish URL must be an absolute http or https URL")
+		}
+	}
+
+	// Score feeds are served over HTTP, NATS is not supported yet
+	if c.scoreFeedURL != "" {
+		parsed, err := url.Parse(c.scoreFeedURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("score feed URL must be an absolute http or https URL")
+		}
+	}
+
+	if c.libp2pPort < 0 || c.libp2pPort > 65535 {
+		return fmt.Errorf("libp2p port must be between 0 and 65535")
+	}
+
+	// A dial-back needs to know which port to dial
+	if c.reachabilityCheckURL != "" {
+		parsed, err := url.Parse(c.reachabilityCheckURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("reachability check URL must be an absolute http or https URL")
+		}
+
+		if c.GetPrimaryLibp2pPort() == 0 {
+			return fmt.Errorf("reachability check requires a fixed libp2p port (--libp2p-port or a primary host port)")
+		}
+	}
+
+	// Canaries dial the primary host, so they need to know its port
+	if c.canary && c.GetPrimaryLibp2pPort() == 0 {
+		return fmt.Errorf("canary peers require a fixed libp2p port (--libp2p-port or a primary host port)")
+	}
+
+	// Regression thresholds are relative drops
+	if c.regressionThreshold <= 0 || c.regressionThreshold >= 1 {
+		return fmt.Errorf("regression threshold must be between 0 and 1")
+	}
+
+	// Issues can only be filed with a baseline to compare against
+	if c.alertGitHubRepo != "" {
+		if c.baselineJSON == "" {
+			return fmt.Errorf("--alert-github-repo requires --baseline-json")
+		}
+
+		if parts := strings.Split(c.alertGitHubRepo, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("alert GitHub repository must be in owner/name form")
+		}
+	}
+
+	if !signing.ValidScheme(c.signScheme) {
+		return fmt.Errorf("signing scheme must be %s or %s", signing.SchemeEd25519, signing.SchemeSigstore)
+	}
+
+	if c.signScheme == signing.SchemeEd25519 && c.signingKeyFile == "" {
+		return fmt.Errorf("--sign=%s requires --signing-key", signing.SchemeEd25519)
+	}
+
+	if c.progressJSON != "" {
+		if err := progress.ValidateTarget(c.progressJSON); err != nil {
+			return err
+		}
+	}
+
+	// A traced peer is matched by its ID, a typo would trace nothing
+	if c.tracePeer != "" {
+		if _, err := peer.Decode(c.tracePeer); err != nil {
+			return fmt.Errorf("trace peer is not a valid libp2p peer ID: %w", err)
+		}
+
+		if c.tracePeerFile == "" {
+			return fmt.Errorf("--trace-peer requires --trace-peer-file")
+		}
+	}
+
+	// The experiment alternates both validation modes, each from its own build
+	if c.experimentPhases < 0 {
+		return fmt.Errorf("experiment phases must not be negative")
+	}
+
+	if c.experimentPhases > 0 {
+		if c.experimentPhases < 2 {
+			return fmt.Errorf("validation experiment needs at least 2 phases to compare both modes")
+		}
+
+		if c.experimentPhaseDuration <= 0 {
+			return fmt.Errorf("experiment phase duration must be positive")
+		}
+
+		for _, mode := range []ValidationMode{ValidationModeDelegated, ValidationModeIndependent} {
+			if c.experimentBinaries[mode] == "" {
+				return fmt.Errorf("validation experiment requires a binary for %s mode (--experiment-binaries)", mode)
+			}
+		}
+	}
+
+	if err := c.meshDegree.Validate(); err != nil {
+		return err
+	}
+
+	// A sweep runs sub-tests of this binary, so it cannot also alternate validation mode builds
+	if c.paramSweep != nil {
+		if c.experimentPhases > 0 {
+			return fmt.Errorf("parameter sweep cannot be combined with a validation experiment")
+		}
+
+		if err := c.paramSweep.Validate(c.meshDegree); err != nil {
+			return fmt.Errorf("invalid parameter sweep: %w", err)
+		}
+	}
+
+	// A smoke test checks one run's pipeline, sub-runs would each write only lite artifacts
+	if c.smoke && (c.experimentPhases > 0 || c.paramSweep != nil) {
+		return fmt.Errorf("smoke test cannot be combined with a validation experiment or parameter sweep")
+	}
+
+	// Parallel hosts share the process, so their labels and ports must be distinct
+	if err := validateHostSpecs(c.hosts, c.meshDegree); err != nil {
+		return fmt.Errorf("invalid hosts: %w", err)
+	}
+
+	// The ramp and the sweep set the primary host's MaxPeers and mesh degree themselves
+	if len(c.hosts) > 0 {
+		if c.hosts[0].MaxPeers > 0 && len(c.maxPeersRamp) > 0 {
+			return fmt.Errorf("primary host %s cannot override max-peers during a MaxPeers ramp", c.hosts[0].Label)
+		}
+
+		if len(c.hosts[0].Mesh) > 0 && c.paramSweep != nil {
+			return fmt.Errorf("primary host %s cannot override the mesh degree during a parameter sweep", c.hosts[0].Label)
+		}
+	}
+
+	// Checkpoints hold the primary host's collector state only
+	if c.resume && len(c.hosts) > 1 {
+		return fmt.Errorf("a run with parallel hosts cannot resume, checkpoints only hold the primary host's state")
+	}
+
+	if err := validateStaticPeers(c.staticPeers); err != nil {
+		return fmt.Errorf("invalid static peers: %w", err)
+	}
+
+	// The pinned Hermes version hardcodes its libp2p user agent, so any other value would be
+	// recorded in the report without peers ever seeing it
+	if c.agentVersion != constants.DefaultAgentVersion {
+		return fmt.Errorf("agent version %q is not supported, the pinned Hermes version always advertises %q", c.agentVersion, constants.DefaultAgentVersion)
+	}
+
 	return nil
 }
 
@@ -315,34 +1591,148 @@ func (c *DefaultConfig) AsHermesConfig() *eth.NodeConfig {
 		DialConcurrency:             c.dialConcurrency,
 		DataStreamType:              host.DataStreamtypeFromStr(c.dataStreamType),
 		SubnetConfigs:               c.subnets,
+		GossipSubConfig:             c.meshDegree.hermesConfig(),
 	}
 }
 
 // HostWithRedactedSecrets redacts passwords from connection strings for secure logging.
 func (c *DefaultConfig) HostWithRedactedSecrets() string {
-	return redactConnectionString(c.prysmHost)
-}
-
-// redactConnectionString redacts passwords from connection strings.
-func redactConnectionString(connStr string) string {
-	// Handle user:pass@host format
-	if len(connStr) > 0 && connStr[0] != '@' {
-		parts := strings.Split(connStr, "@")
-		if len(parts) == 2 {
-			userParts := strings.Split(parts[0], ":")
-			if len(userParts) == 2 {
-				return userParts[0] + ":****@" + parts[1]
-			}
-		}
+	return redact.URL(c.prysmHost)
+}
+
+// Redacted returns the run settings recorded in reports, with credentials redacted.
+func (c *DefaultConfig) Redacted() map[string]interface{} {
+	return map[string]interface{}{
+		"validation_mode":        string(c.validationMode),
+		"network":                c.network,
+		"test_duration":          c.testDuration.String(),
+		"warmup":                 c.warmupDuration.String(),
+		"cooldown":               c.cooldownDuration.String(),
+		"handshake_retry_window": c.retryWindow.String(),
+		"late_event_grace":       c.lateEventGrace.String(),
+		"shutdown_timeout":       c.shutdownTimeout.String(),
+		"include_teardown":       c.includeTeardown,
+		"smoke":                  c.smoke,
+		"event_bucket_width":     c.eventBucketWidth.String(),
+		"event_burst_threshold":  c.burstThreshold,
+		"detail_sample_rate":     c.sampleRate,
+		"detail_sample_seed":     c.sampleSeed,
+		"event_sample_rates":     c.eventRates,
+		"prysm_host":             redact.URL(c.prysmHost),
+		"prysm_http_port":        c.prysmHTTPPort,
+		"prysm_grpc_port":        c.prysmGRPCPort,
+		"use_tls":                c.useTLS,
+		"devnet_apache_url":      redact.URL(c.devnetApacheURL),
+		"libp2p_port":            c.libp2pPort,
+		"max_peers":              c.maxPeers,
+		"capacity_ratio":         c.capacityRatio,
+		"max_peers_ramp":         c.maxPeersRamp,
+		"max_peers_ramp_step":    c.maxPeersRampStep.String(),
+		"dial_concurrency":       c.dialConcurrency,
+		"agent_version":          c.agentVersion,
+		"gossipsub_mesh":         c.meshDegree,
+		"hosts":                  c.hosts,
+		"static_peers":           c.staticPeers,
+		"bootnodes":              c.bootnodes,
+		"topic_whitelist":        c.topicWhitelist,
+		"previous_reports":       c.previousReports,
+		"analyzers":              c.analyzers,
+		"ai_queue_dir":           c.aiQueueDir,
+		"ai_concurrency":         c.aiConcurrency,
+		"ai_queue_timeout":       c.aiQueueTimeout.String(),
+		"ai_base_url":            redact.URL(c.aiBaseURL),
+		"ai_proxy":               redact.URL(c.aiProxy),
+		"ai_ca_bundle":           c.aiCABundle,
+		"ai_timeout":             c.aiTimeout.String(),
+		"template_dir":           c.templateDir,
+		"prune_fields":           c.pruneFields,
+		"hash_fields":            c.hashFields,
+		"prune_hash_salt_set":    c.pruneHashSalt != "",
+		"publish_url":            redact.URL(c.publishURL),
+		"score_feed_url":         redact.URL(c.scoreFeedURL),
+		"score_feed_instance":    c.scoreFeedInstance,
+		"reachability_check_url": redact.URL(c.reachabilityCheckURL),
+		"check_beacon_peers":     c.checkBeaconPeers,
+		"beacon_peers_interval":  c.beaconPeersInterval.String(),
+		"clock_skew_threshold":   c.clockSkewThreshold.String(),
+		"beacon_sync_interval":   c.beaconSyncInterval.String(),
+		"canary":                 c.canary,
+		"negotiation_logs":       c.negotiationLogs,
+		"head_divergence_slots":  c.headDivergence,
+		"starvation_timeout":     c.starvationTimeout.String(),
+		"restart_on_starvation":  c.restartOnStarvation,
+		"spill_rss_mb":           c.spillRSSMB,
+		"spill_dir":              c.spillDir,
+		"checkpoint_interval":    c.checkpointInterval.String(),
+		"resumed":                c.resume,
+		"alert_github_repo":      c.alertGitHubRepo,
+		"artifact_base_url":      redact.URL(c.artifactBaseURL),
+		"sign":                   c.signScheme,
+		"progress_json":          c.progressJSON,
+		"error_journal":          c.errorJournal,
+		"trace_peer":             c.tracePeer,
+		"trace_peer_file":        c.tracePeerFile,
+		"status_interval":        c.statusInterval.String(),
+		"log_sample":             c.logSample,
+		"quiet":                  c.quiet,
+		"openrouter_api_key_set": c.claudeAPIKey != "",
 	}
+}
+
+// Secrets returns the secret values configured for the run, so they can be scrubbed from
+// any output that might echo them.
+func (c *DefaultConfig) Secrets() []string {
+	secrets := []string{c.claudeAPIKey, c.privateKeyStr, c.pruneHashSalt, os.Getenv(constants.GitHubTokenEnv)}
 
-	return connStr
+	for _, endpoint := range []string{c.prysmHost, c.devnetApacheURL, c.publishURL, c.scoreFeedURL, c.reachabilityCheckURL, c.artifactBaseURL, c.aiBaseURL, c.aiProxy} {
+		secrets = append(secrets, endpointPassword(endpoint))
+	}
+
+	return secrets
+}
+
+// endpointPassword extracts the password from a URL or user:password@host connection string.
+func endpointPassword(endpoint string) string {
+	if parsed, err := url.Parse(endpoint); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		password, _ := parsed.User.Password()
+
+		return password
+	}
+
+	at := strings.LastIndex(endpoint, "@")
+	if at <= 0 {
+		return ""
+	}
+
+	if _, password, found := strings.Cut(endpoint[:at], ":"); found {
+		return password
+	}
+
+	return ""
 }
 
 // Clone creates a deep copy of the configuration.
 func (c *DefaultConfig) Clone() *DefaultConfig {
 	clone := *c
 
+	clone.hosts = append([]HostSpec(nil), c.hosts...)
+	clone.staticPeers = append([]StaticPeer(nil), c.staticPeers...)
+	clone.bootnodes = append([]string(nil), c.bootnodes...)
+	clone.topicWhitelist = append([]string(nil), c.topicWhitelist...)
+	clone.previousReports = append([]string(nil), c.previousReports...)
+	clone.analyzers = append([]AnalyzerSpec(nil), c.analyzers...)
+	clone.pruneFields = append([]string(nil), c.pruneFields...)
+	clone.hashFields = append([]string(nil), c.hashFields...)
+	clone.maxPeersRamp = append([]int(nil), c.maxPeersRamp...)
+	clone.experimentArgs = append([]string(nil), c.experimentArgs...)
+
+	if c.experimentBinaries != nil {
+		clone.experimentBinaries = make(map[ValidationMode]string, len(c.experimentBinaries))
+		for mode, binary := range c.experimentBinaries {
+			clone.experimentBinaries[mode] = binary
+		}
+	}
+
 	// Deep copy subnets map
 	clone.subnets = make(map[string]*eth.SubnetConfig)
 	for k, v := range c.subnets {
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// EnvName returns the environment variable that sets the named flag, e.g. PEER_SCORE_PRYSM_HOST
+// for --prysm-host.
+func EnvName(flagName string) string {
+	return constants.EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// ApplyEnv sets flags from their PEER_SCORE_ environment variables. Flags given on the
+// command line take precedence over the environment. Apply it before ApplyFile, which then
+// skips the flags the environment set, so the environment in turn wins over the file.
+// Variables that match no flag are ignored, as Kubernetes injects its own PEER_SCORE_
+// service variables when the service shares the name.
+func ApplyEnv(flags *flag.FlagSet) error {
+	explicit := make(map[string]bool)
+
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	var err error
+
+	flags.VisitAll(func(f *flag.Flag) {
+		if err != nil || explicit[f.Name] {
+			return
+		}
+
+		name := EnvName(f.Name)
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid environment variable %s: %w", name, setErr)
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{flag: "prysm-host", want: "PEER_SCORE_PRYSM_HOST"},
+		{flag: "duration", want: "PEER_SCORE_DURATION"},
+		{flag: "data-file-budget-mb", want: "PEER_SCORE_DATA_FILE_BUDGET_MB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			if got := EnvName(tt.flag); got != tt.want {
+				t.Errorf("EnvName(%q) = %q, want %q", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		file     string
+		args     []string
+		wantErr  string
+		duration time.Duration
+		host     string
+		secure   bool
+	}{
+		{
+			name:     "environment sets flags",
+			env:      map[string]string{"PEER_SCORE_DURATION": "30m", "PEER_SCORE_PRYSM_HOST": "beacon.example", "PEER_SCORE_SECURE_PRYSM": "true"},
+			duration: 30 * time.Minute,
+			host:     "beacon.example",
+			secure:   true,
+		},
+		{
+			name:     "command line wins",
+			env:      map[string]string{"PEER_SCORE_PRYSM_HOST": "beacon.example"},
+			args:     []string{"--prysm-host=other.example"},
+			duration: time.Minute,
+			host:     "other.example",
+		},
+		{
+			name:     "environment wins over file",
+			env:      map[string]string{"PEER_SCORE_PRYSM_HOST": "beacon.example"},
+			file:     "duration: 30m\nprysm-host: file.example\n",
+			duration: 30 * time.Minute,
+			host:     "beacon.example",
+		},
+		{
+			name:     "unknown variables are ignored",
+			env:      map[string]string{"PEER_SCORE_SERVICE_HOST": "10.0.0.1"},
+			duration: time.Minute,
+		},
+		{
+			name:    "invalid value",
+			env:     map[string]string{"PEER_SCORE_DURATION": "soon"},
+			wantErr: "invalid environment variable PEER_SCORE_DURATION",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			duration := flags.Duration("duration", time.Minute, "")
+			host := flags.String("prysm-host", "", "")
+			secure := flags.Bool("secure-prysm", false, "")
+
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatalf("failed to parse args: %v", err)
+			}
+
+			err := ApplyEnv(flags)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyEnv() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ApplyEnv() error = %v", err)
+			}
+
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("failed to write config file: %v", err)
+				}
+
+				if err := ApplyFile(path, flags); err != nil {
+					t.Fatalf("ApplyFile() error = %v", err)
+				}
+			}
+
+			if *duration != tt.duration || *host != tt.host || *secure != tt.secure {
+				t.Errorf("got duration=%s host=%s secure=%v, want %s %s %v", *duration, *host, *secure, tt.duration, tt.host, tt.secure)
+			}
+		})
+	}
+}
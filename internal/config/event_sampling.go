@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SampledEventTypes are the event types whose detail can be recorded one in N, the chattiest
+// ones Hermes traces.
+var SampledEventTypes = []string{"GRAFT", "PRUNE", "PEERSCORE"}
+
+// ParseEventSampleRates parses a comma-separated list of per-event-type sampling rates in the
+// form TYPE=N, e.g. "PRUNE=10,PEERSCORE=5", recording one in N events of the type in full.
+// Event types are case-insensitive, and a rate of 1 records every event.
+func ParseEventSampleRates(spec string) (map[string]int, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	rates := make(map[string]int)
+
+	for _, entry := range strings.Split(spec, ",") {
+		eventType, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid event sample rate %q, expected TYPE=N", entry)
+		}
+
+		eventType = strings.ToUpper(strings.TrimSpace(eventType))
+
+		supported := false
+		for _, sampled := range SampledEventTypes {
+			supported = supported || sampled == eventType
+		}
+
+		if !supported {
+			return nil, fmt.Errorf("event type %q cannot be sampled, expected one of %s", eventType, strings.Join(SampledEventTypes, ", "))
+		}
+
+		rate, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || rate < 1 {
+			return nil, fmt.Errorf("invalid sample rate %q for %s, expected a positive integer", value, eventType)
+		}
+
+		if _, exists := rates[eventType]; exists {
+			return nil, fmt.Errorf("duplicate sample rate for %s", eventType)
+		}
+
+		rates[eventType] = rate
+	}
+
+	return rates, nil
+}
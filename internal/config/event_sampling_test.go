@@ -0,0 +1,67 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEventSampleRates(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    map[string]int
+		expectError bool
+	}{
+		{
+			name:     "empty spec",
+			spec:     "",
+			expected: nil,
+		},
+		{
+			name:     "several types",
+			spec:     "PRUNE=10, graft=10,PEERSCORE = 5",
+			expected: map[string]int{"PRUNE": 10, "GRAFT": 10, "PEERSCORE": 5},
+		},
+		{
+			name:        "unsupported type",
+			spec:        "CONNECTED=10",
+			expectError: true,
+		},
+		{
+			name:        "missing rate",
+			spec:        "PRUNE",
+			expectError: true,
+		},
+		{
+			name:        "zero rate",
+			spec:        "PRUNE=0",
+			expectError: true,
+		},
+		{
+			name:        "duplicate type",
+			spec:        "PRUNE=10,prune=5",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := ParseEventSampleRates(tt.spec)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(rates, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, rates)
+			}
+		})
+	}
+}
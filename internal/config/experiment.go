@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseExperimentBinaries parses a comma-separated list of per-mode binaries in the form
+// mode=path, e.g. "delegated=./peer-score-delegated,independent=./peer-score-independent".
+// Each validation mode needs its own build, since the Hermes version is pinned in go.mod.
+func ParseExperimentBinaries(spec string) (map[ValidationMode]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	binaries := make(map[ValidationMode]string)
+
+	for _, entry := range strings.Split(spec, ",") {
+		mode, path, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || path == "" {
+			return nil, fmt.Errorf("invalid experiment binary %q, expected mode=path", entry)
+		}
+
+		switch ValidationMode(mode) {
+		case ValidationModeDelegated, ValidationModeIndependent:
+		default:
+			return nil, fmt.Errorf("invalid validation mode %q for experiment binary", mode)
+		}
+
+		if _, exists := binaries[ValidationMode(mode)]; exists {
+			return nil, fmt.Errorf("duplicate experiment binary for %s mode", mode)
+		}
+
+		binaries[ValidationMode(mode)] = path
+	}
+
+	return binaries, nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExperimentBinaries(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    map[ValidationMode]string
+		expectError bool
+	}{
+		{
+			name:     "empty spec",
+			spec:     "",
+			expected: nil,
+		},
+		{
+			name: "both modes",
+			spec: "delegated=./bin/delegated, independent=/opt/independent",
+			expected: map[ValidationMode]string{
+				ValidationModeDelegated:   "./bin/delegated",
+				ValidationModeIndependent: "/opt/independent",
+			},
+		},
+		{
+			name:        "unknown mode",
+			spec:        "optimistic=./bin/optimistic",
+			expectError: true,
+		},
+		{
+			name:        "missing path",
+			spec:        "delegated=",
+			expectError: true,
+		},
+		{
+			name:        "duplicate mode",
+			spec:        "delegated=a,delegated=b",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binaries, err := ParseExperimentBinaries(tt.spec)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(binaries, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, binaries)
+			}
+		})
+	}
+}
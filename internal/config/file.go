@@ -0,0 +1,52 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyFile sets flags from a YAML config file whose keys are flag names, as written by
+// the init command. Flags given on the command line take precedence over the file.
+func ApplyFile(path string, flags *flag.FlagSet) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	explicit := make(map[string]bool)
+
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for name, value := range values {
+		if flags.Lookup(name) == nil {
+			return fmt.Errorf("unknown setting %q in config file %s", name, path)
+		}
+
+		if explicit[name] {
+			continue
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return fmt.Errorf("setting %q in config file %s must be a single value", name, path)
+		case nil:
+			continue
+		}
+
+		if err := flags.Set(name, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("invalid setting %q in config file %s: %w", name, path, err)
+		}
+	}
+
+	return nil
+}
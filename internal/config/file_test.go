@@ -0,0 +1,87 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		args     []string
+		wantErr  string
+		duration time.Duration
+		host     string
+		secure   bool
+	}{
+		{
+			name:     "file sets flags",
+			content:  "duration: 30m\nprysm-host: beacon.example\nsecure-prysm: true\n",
+			duration: 30 * time.Minute,
+			host:     "beacon.example",
+			secure:   true,
+		},
+		{
+			name:     "command line wins",
+			content:  "duration: 30m\nprysm-host: beacon.example\n",
+			args:     []string{"--prysm-host=other.example"},
+			duration: 30 * time.Minute,
+			host:     "other.example",
+		},
+		{
+			name:    "unknown setting",
+			content: "prysm-hots: beacon.example\n",
+			wantErr: `unknown setting "prysm-hots"`,
+		},
+		{
+			name:    "invalid value",
+			content: "duration: soon\n",
+			wantErr: `invalid setting "duration"`,
+		},
+		{
+			name:    "nested value",
+			content: "prysm-host:\n  name: beacon.example\n",
+			wantErr: "must be a single value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write config file: %v", err)
+			}
+
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			duration := flags.Duration("duration", time.Minute, "")
+			host := flags.String("prysm-host", "", "")
+			secure := flags.Bool("secure-prysm", false, "")
+
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatalf("failed to parse args: %v", err)
+			}
+
+			err := ApplyFile(path, flags)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyFile() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ApplyFile() error = %v", err)
+			}
+
+			if *duration != tt.duration || *host != tt.host || *secure != tt.secure {
+				t.Errorf("got duration=%s host=%s secure=%v, want %s %s %v", *duration, *host, *secure, tt.duration, tt.host, tt.secure)
+			}
+		})
+	}
+}
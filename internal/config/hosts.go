@@ -0,0 +1,278 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/probe-lab/hermes/eth"
+)
+
+// Settings a parallel host can run with instead of the run's own, given after its ports as
+// ;name=value pairs. The mesh degree parameters are named as in --param-sweep.
+const (
+	HostMaxPeers             = "max-peers"
+	HostDialConcurrency      = "dial-concurrency"
+	HostAttestationSubnets   = "attestation-subnets"
+	HostSyncCommitteeSubnets = "sync-committee-subnets"
+)
+
+// hostSubnetTopics maps the subnet settings to the gossip topics Hermes keys them by.
+var hostSubnetTopics = map[string]string{
+	HostAttestationSubnets:   "beacon_attestation",
+	HostSyncCommitteeSubnets: "sync_committee",
+}
+
+// HostSpec describes one Hermes host run in parallel with others in the same process.
+// Each host gets its own libp2p identity; a zero port lets the OS pick one. A host runs with
+// the run's settings, except for those it overrides.
+type HostSpec struct {
+	Label      string `json:"label"`
+	Libp2pPort int    `json:"libp2p_port"`
+	Devp2pPort int    `json:"devp2p_port"`
+
+	MaxPeers        int                          `json:"max_peers,omitempty"`
+	DialConcurrency int                          `json:"dial_concurrency,omitempty"`
+	Mesh            map[string]int               `json:"mesh,omitempty"`    // Mesh degree parameters by name
+	Subnets         map[string]*eth.SubnetConfig `json:"subnets,omitempty"` // By gossip topic
+}
+
+// ParseHostSpecs parses a comma-separated list of hosts in the form
+// label[:libp2p-port[:devp2p-port]][;name=value...], e.g.
+// "baseline:9000:9001,experiment:9100:9101;max-peers=50;attestation-subnets=0-15".
+func ParseHostSpecs(spec string) ([]HostSpec, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	hosts := make([]HostSpec, 0)
+
+	for _, entry := range strings.Split(spec, ",") {
+		settings := strings.Split(strings.TrimSpace(entry), ";")
+
+		parts := strings.Split(settings[0], ":")
+		if len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid host %q, expected label[:libp2p-port[:devp2p-port]][;name=value...]", entry)
+		}
+
+		host := HostSpec{Label: parts[0]}
+
+		ports := []*int{&host.Libp2pPort, &host.Devp2pPort}
+		for i, value := range parts[1:] {
+			port, err := strconv.Atoi(value)
+			if err != nil || port < 0 || port > 65535 {
+				return nil, fmt.Errorf("invalid port %q for host %s", value, host.Label)
+			}
+
+			*ports[i] = port
+		}
+
+		for _, setting := range settings[1:] {
+			if err := host.set(setting); err != nil {
+				return nil, fmt.Errorf("invalid setting %q for host %s: %w", setting, host.Label, err)
+			}
+		}
+
+		hosts = append(hosts, host)
+	}
+
+	return hosts, nil
+}
+
+// set applies one name=value setting to the host.
+func (h *HostSpec) set(setting string) error {
+	name, value, ok := strings.Cut(strings.TrimSpace(setting), "=")
+	if !ok {
+		return fmt.Errorf("expected name=value")
+	}
+
+	if topic, ok := hostSubnetTopics[name]; ok {
+		subnets, err := parseSubnetSelection(value)
+		if err != nil {
+			return err
+		}
+
+		if h.Subnets == nil {
+			h.Subnets = make(map[string]*eth.SubnetConfig)
+		}
+
+		h.Subnets[topic] = subnets
+
+		return nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return fmt.Errorf("expected a positive number")
+	}
+
+	switch name {
+	case HostMaxPeers:
+		h.MaxPeers = number
+	case HostDialConcurrency:
+		h.DialConcurrency = number
+	case MeshParamD, MeshParamDlo, MeshParamDhi:
+		if h.Mesh == nil {
+			h.Mesh = make(map[string]int)
+		}
+
+		h.Mesh[name] = number
+	default:
+		return fmt.Errorf("unknown setting %s", name)
+	}
+
+	return nil
+}
+
+// parseSubnetSelection parses the subnets a host subscribes to: all, a count of random subnets
+// or an inclusive range such as 0-15.
+func parseSubnetSelection(value string) (*eth.SubnetConfig, error) {
+	if value == "all" {
+		return &eth.SubnetConfig{Type: eth.SubnetAll}, nil
+	}
+
+	if first, last, ok := strings.Cut(value, "-"); ok {
+		start, startErr := strconv.ParseUint(first, 10, 64)
+		end, endErr := strconv.ParseUint(last, 10, 64)
+
+		if startErr != nil || endErr != nil || end < start {
+			return nil, fmt.Errorf("invalid subnet range %s", value)
+		}
+
+		return &eth.SubnetConfig{Type: eth.SubnetStaticRange, Start: start, End: end + 1}, nil
+	}
+
+	count, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || count == 0 {
+		return nil, fmt.Errorf("expected all, a subnet count or a range such as 0-15")
+	}
+
+	return &eth.SubnetConfig{Type: eth.SubnetRandom, Count: count}, nil
+}
+
+// MeshDegree returns the mesh degree the host runs with, given the run's.
+func (h HostSpec) MeshDegree(run MeshDegree) MeshDegree {
+	for _, param := range []string{MeshParamD, MeshParamDlo, MeshParamDhi} {
+		if value, ok := h.Mesh[param]; ok {
+			run = run.With(param, value)
+		}
+	}
+
+	return run
+}
+
+// SubnetConfigs returns the subnets the host subscribes to per topic, given the run's.
+func (h HostSpec) SubnetConfigs(run map[string]*eth.SubnetConfig) map[string]*eth.SubnetConfig {
+	if len(h.Subnets) == 0 {
+		return run
+	}
+
+	subnets := maps.Clone(run)
+	if subnets == nil {
+		subnets = make(map[string]*eth.SubnetConfig, len(h.Subnets))
+	}
+
+	maps.Copy(subnets, h.Subnets)
+
+	return subnets
+}
+
+// Apply sets the host's overrides on a Hermes node configuration built from the run's
+// settings, whose mesh degree is mesh.
+func (h HostSpec) Apply(cfg *eth.NodeConfig, mesh MeshDegree) {
+	if h.MaxPeers > 0 {
+		cfg.MaxPeers = h.MaxPeers
+	}
+
+	if h.DialConcurrency > 0 {
+		cfg.DialConcurrency = h.DialConcurrency
+	}
+
+	if len(h.Mesh) > 0 {
+		cfg.GossipSubConfig = h.MeshDegree(mesh).hermesConfig()
+	}
+
+	cfg.SubnetConfigs = h.SubnetConfigs(cfg.SubnetConfigs)
+}
+
+// Settings returns the host's overrides as name=value pairs in the --hosts syntax, empty when
+// it runs with the run's settings.
+func (h HostSpec) Settings() string {
+	settings := make([]string, 0)
+
+	if h.MaxPeers > 0 {
+		settings = append(settings, fmt.Sprintf("%s=%d", HostMaxPeers, h.MaxPeers))
+	}
+
+	if h.DialConcurrency > 0 {
+		settings = append(settings, fmt.Sprintf("%s=%d", HostDialConcurrency, h.DialConcurrency))
+	}
+
+	for _, param := range []string{MeshParamD, MeshParamDlo, MeshParamDhi} {
+		if value, ok := h.Mesh[param]; ok {
+			settings = append(settings, fmt.Sprintf("%s=%d", param, value))
+		}
+	}
+
+	names := make([]string, 0, len(hostSubnetTopics))
+	for name := range hostSubnetTopics {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if subnets := h.Subnets[hostSubnetTopics[name]]; subnets != nil {
+			settings = append(settings, name+"="+formatSubnetSelection(subnets))
+		}
+	}
+
+	return strings.Join(settings, ";")
+}
+
+// formatSubnetSelection formats a subnet selection the way parseSubnetSelection reads it.
+func formatSubnetSelection(subnets *eth.SubnetConfig) string {
+	switch subnets.Type {
+	case eth.SubnetRandom:
+		return strconv.FormatUint(subnets.Count, 10)
+	case eth.SubnetStaticRange:
+		return fmt.Sprintf("%d-%d", subnets.Start, subnets.End-1)
+	default:
+		return string(subnets.Type)
+	}
+}
+
+// validateHostSpecs checks that host labels and fixed ports do not collide and that each
+// host's mesh degree, the run's with its overrides, is one Hermes accepts.
+func validateHostSpecs(hosts []HostSpec, mesh MeshDegree) error {
+	labels := make(map[string]bool, len(hosts))
+	ports := make(map[int]string, len(hosts)*2)
+
+	for _, host := range hosts {
+		if labels[host.Label] {
+			return fmt.Errorf("duplicate host label %s", host.Label)
+		}
+
+		labels[host.Label] = true
+
+		for _, port := range []int{host.Libp2pPort, host.Devp2pPort} {
+			if port == 0 {
+				continue
+			}
+
+			if other, exists := ports[port]; exists {
+				return fmt.Errorf("hosts %s and %s both use port %d", other, host.Label, port)
+			}
+
+			ports[port] = host.Label
+		}
+
+		if err := host.MeshDegree(mesh).Validate(); err != nil {
+			return fmt.Errorf("host %s: %w", host.Label, err)
+		}
+	}
+
+	return nil
+}
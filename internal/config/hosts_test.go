@@ -0,0 +1,191 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/probe-lab/hermes/eth"
+)
+
+func TestParseHostSpecs(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    []HostSpec
+		expectError bool
+	}{
+		{
+			name:     "empty spec",
+			spec:     "",
+			expected: nil,
+		},
+		{
+			name: "labels only",
+			spec: "baseline,experiment",
+			expected: []HostSpec{
+				{Label: "baseline"},
+				{Label: "experiment"},
+			},
+		},
+		{
+			name: "labels with ports",
+			spec: "baseline:9000:9001, experiment:9100",
+			expected: []HostSpec{
+				{Label: "baseline", Libp2pPort: 9000, Devp2pPort: 9001},
+				{Label: "experiment", Libp2pPort: 9100},
+			},
+		},
+		{
+			name: "settings",
+			spec: "baseline:9000,experiment:9100:9101;max-peers=50;dlo=4;attestation-subnets=0-15;sync-committee-subnets=2",
+			expected: []HostSpec{
+				{Label: "baseline", Libp2pPort: 9000},
+				{
+					Label:      "experiment",
+					Libp2pPort: 9100,
+					Devp2pPort: 9101,
+					MaxPeers:   50,
+					Mesh:       map[string]int{MeshParamDlo: 4},
+					Subnets: map[string]*eth.SubnetConfig{
+						"beacon_attestation": {Type: eth.SubnetStaticRange, Start: 0, End: 16},
+						"sync_committee":     {Type: eth.SubnetRandom, Count: 2},
+					},
+				},
+			},
+		},
+		{
+			name:     "all subnets",
+			spec:     "experiment;attestation-subnets=all",
+			expected: []HostSpec{{Label: "experiment", Subnets: map[string]*eth.SubnetConfig{"beacon_attestation": {Type: eth.SubnetAll}}}},
+		},
+		{
+			name:        "unknown setting",
+			spec:        "experiment;peers=50",
+			expectError: true,
+		},
+		{
+			name:        "invalid setting value",
+			spec:        "experiment;max-peers=0",
+			expectError: true,
+		},
+		{
+			name:        "reversed subnet range",
+			spec:        "experiment;attestation-subnets=15-0",
+			expectError: true,
+		},
+		{
+			name:        "missing label",
+			spec:        ":9000",
+			expectError: true,
+		},
+		{
+			name:        "invalid port",
+			spec:        "baseline:http",
+			expectError: true,
+		},
+		{
+			name:        "too many parts",
+			spec:        "baseline:9000:9001:9002",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hosts, err := ParseHostSpecs(tt.spec)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(hosts, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, hosts)
+			}
+		})
+	}
+}
+
+func TestValidateHostSpecs(t *testing.T) {
+	tests := []struct {
+		name        string
+		hosts       []HostSpec
+		expectError bool
+	}{
+		{
+			name:  "distinct hosts",
+			hosts: []HostSpec{{Label: "a", Libp2pPort: 9000}, {Label: "b", Libp2pPort: 9100}},
+		},
+		{
+			name:  "automatic ports",
+			hosts: []HostSpec{{Label: "a"}, {Label: "b"}},
+		},
+		{
+			name:        "duplicate label",
+			hosts:       []HostSpec{{Label: "a"}, {Label: "a"}},
+			expectError: true,
+		},
+		{
+			name:        "invalid mesh override",
+			hosts:       []HostSpec{{Label: "a"}, {Label: "b", Mesh: map[string]int{MeshParamDlo: 20}}},
+			expectError: true,
+		},
+		{
+			name:        "shared port",
+			hosts:       []HostSpec{{Label: "a", Libp2pPort: 9000}, {Label: "b", Devp2pPort: 9000}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHostSpecs(tt.hosts, DefaultMeshDegree())
+			if tt.expectError && err == nil {
+				t.Error("expected error, got nil")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHostSpecApply(t *testing.T) {
+	hosts, err := ParseHostSpecs("experiment;max-peers=50;dial-concurrency=8;d=6;attestation-subnets=4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	host := hosts[0]
+	run := map[string]*eth.SubnetConfig{"sync_committee": {Type: eth.SubnetAll}}
+	cfg := &eth.NodeConfig{MaxPeers: 80, DialConcurrency: 16, SubnetConfigs: run}
+
+	host.Apply(cfg, DefaultMeshDegree())
+
+	if cfg.MaxPeers != 50 || cfg.DialConcurrency != 8 {
+		t.Errorf("expected max peers 50 and dial concurrency 8, got %d and %d", cfg.MaxPeers, cfg.DialConcurrency)
+	}
+
+	if cfg.GossipSubConfig == nil || cfg.GossipSubConfig.D != 6 || cfg.GossipSubConfig.DLow != DefaultMeshDegree().Dlo {
+		t.Errorf("expected D overridden and Dlo kept, got %+v", cfg.GossipSubConfig)
+	}
+
+	if len(cfg.SubnetConfigs) != 2 || cfg.SubnetConfigs["beacon_attestation"].Count != 4 {
+		t.Errorf("expected the run's subnets with the host's attestation subnets, got %+v", cfg.SubnetConfigs)
+	}
+
+	if len(run) != 1 {
+		t.Errorf("expected the run's subnets left unchanged, got %+v", run)
+	}
+
+	if settings := host.Settings(); settings != "max-peers=50;dial-concurrency=8;d=6;attestation-subnets=4" {
+		t.Errorf("expected settings in the --hosts syntax, got %q", settings)
+	}
+}
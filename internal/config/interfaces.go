@@ -18,7 +18,19 @@ const (
 type Config interface {
 	GetValidationMode() ValidationMode
 	GetTestDuration() time.Duration
+	GetWarmupDuration() time.Duration
+	GetCooldownDuration() time.Duration
+	GetHandshakeRetryWindow() time.Duration
+	GetLateEventGrace() time.Duration
+	GetShutdownTimeout() time.Duration
+	IsIncludeTeardown() bool
 	GetReportInterval() time.Duration
+	GetEventBucketWidth() time.Duration
+	GetEventBurstThreshold() int
+	GetDetailSampleRate() float64
+	GetDetailSampleSeed() int64
+	IsDetailSampled() bool
+	GetEventSampleRates() map[string]int
 	GetPrysmHost() string
 	GetPrysmHTTPPort() int
 	GetPrysmGRPCPort() int
@@ -26,18 +38,101 @@ type Config interface {
 	GetNetwork() string
 	GetDevnetApacheURL() string
 	GetMaxPeers() int
+	GetMaxPeersRamp() []int
+	GetMaxPeersRampStep() time.Duration
+	GetCapacityRatio() float64
 	GetDialConcurrency() int
+	GetAgentVersion() string
+	GetMeshDegree() MeshDegree
+	GetHosts() []HostSpec
+	GetStaticPeers() []StaticPeer
+	GetBootnodes() []string
+	GetPrimaryLibp2pPort() int
+	GetSubnets() map[string]*eth.SubnetConfig
+	GetTopicWhitelist() []string
 	AsHermesConfig() *eth.NodeConfig
 	Validate() error
 	HostWithRedactedSecrets() string
+	Redacted() map[string]interface{}
+	Secrets() []string
 
 	// Report configuration
 	IsHTMLOnly() bool
 	GetInputJSON() string
 	GetClaudeAPIKey() string
 	IsSkipAI() bool
+	IsSmoke() bool
+	GetAIQueueDir() string
+	GetAIConcurrency() int
+	GetAIQueueTimeout() time.Duration
+	GetAIBaseURL() string
+	GetAIProxy() string
+	GetAICABundle() string
+	GetAITimeout() time.Duration
+	GetTemplateDir() string
+	GetPruneFields() []string
+	GetHashFields() []string
+	GetPruneHashSalt() string
 	IsUpdateGoMod() bool
 	IsValidateGoMod() bool
+	IsSplitReport() bool
+	GetShardSize() int
+	IsPrettyDataFile() bool
+	GetDataFileBudgetMB() int
+	GetSwimlanePeers() int
+	GetHeadDivergenceSlots() uint64
+	GetPreviousReports() []string
+	GetAnalyzers() []AnalyzerSpec
+
+	// Output configuration
+	GetPublishURL() string
+	GetScoreFeedURL() string
+	GetScoreFeedInstance() string
+	GetScoreFeedListenAddr() string
+
+	// Checkpoint configuration
+	GetCheckpointFile() string
+	GetCheckpointInterval() time.Duration
+	IsResume() bool
+
+	// Reachability configuration
+	GetReachabilityCheckURL() string
+	GetReachabilityListenAddr() string
+	IsCheckBeaconPeers() bool
+	GetBeaconPeersInterval() time.Duration
+	GetClockSkewThreshold() time.Duration
+	GetBeaconSyncInterval() time.Duration
+	IsCanary() bool
+	IsNegotiationLogs() bool
+
+	// Event starvation watchdog configuration
+	GetStarvationTimeout() time.Duration
+	IsRestartOnStarvation() bool
+
+	// Memory watchdog configuration
+	GetSpillRSSMB() int
+	GetSpillDir() string
+
+	// Alerting configuration
+	GetBaselineJSON() string
+	GetRegressionThreshold() float64
+	GetAlertGitHubRepo() string
+	GetArtifactBaseURL() string
+
+	// Report signing configuration
+	GetSignScheme() string
+	GetSigningKeyFile() string
+
+	// Progress output configuration
+	GetProgressJSON() string
+	GetErrorJournal() string
+	GetTracePeer() string
+	GetTracePeerFile() string
+
+	// Logging configuration
+	GetStatusInterval() time.Duration
+	GetLogSample() int
+	IsQuiet() bool
 }
 
 // Validator defines the interface for configuration validation.
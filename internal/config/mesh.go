@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/probe-lab/hermes/eth"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Mesh degree parameters, as named in --param-sweep.
+const (
+	MeshParamD   = "d"
+	MeshParamDlo = "dlo"
+	MeshParamDhi = "dhi"
+)
+
+// MeshDegree is the gossipsub mesh degree Hermes runs with: the number of mesh peers it keeps
+// per topic, grafting below Dlo and pruning above Dhi. It changes how often we graft and prune
+// peers, and so how they score us.
+type MeshDegree struct {
+	D   int `json:"d"`
+	Dlo int `json:"dlo"`
+	Dhi int `json:"dhi"`
+}
+
+// DefaultMeshDegree returns the mesh degree Hermes runs with unless configured.
+func DefaultMeshDegree() MeshDegree {
+	return MeshDegree{D: constants.DefaultGossipD, Dlo: constants.DefaultGossipDlo, Dhi: constants.DefaultGossipDhi}
+}
+
+// Validate checks the watermarks bracket the target, Hermes needs the high one above it.
+func (m MeshDegree) Validate() error {
+	if m.Dlo <= 0 {
+		return fmt.Errorf("gossipsub Dlo must be positive")
+	}
+
+	if m.Dlo > m.D || m.D >= m.Dhi {
+		return fmt.Errorf("gossipsub mesh degree must satisfy Dlo <= D < Dhi, got %s", m)
+	}
+
+	return nil
+}
+
+// String returns the mesh degree as it is logged.
+func (m MeshDegree) String() string {
+	return fmt.Sprintf("D=%d Dlo=%d Dhi=%d", m.D, m.Dlo, m.Dhi)
+}
+
+// With returns the mesh degree with one parameter set.
+func (m MeshDegree) With(param string, value int) MeshDegree {
+	switch param {
+	case MeshParamD:
+		m.D = value
+	case MeshParamDlo:
+		m.Dlo = value
+	case MeshParamDhi:
+		m.Dhi = value
+	}
+
+	return m
+}
+
+// hermesConfig returns the Hermes gossipsub settings for the mesh degree, with the other
+// parameters at Hermes' defaults. The outbound quota must stay below Dlo and at most D/2.
+func (m MeshDegree) hermesConfig() *eth.GossipSubConfig {
+	return &eth.GossipSubConfig{
+		D:                     m.D,
+		DLow:                  m.Dlo,
+		DHigh:                 m.Dhi,
+		DLazy:                 6,
+		DScore:                min(5, m.Dhi),
+		DOut:                  min(3, m.Dlo-1, m.D/2),
+		FanoutTTL:             60 * time.Second,
+		SeenMessagesTTL:       780 * time.Second,
+		Advertise:             3,
+		FloodPublishThreshold: 16384,
+	}
+}
+
+// ParamSweep runs sequential sub-tests, one for each value of a mesh degree parameter.
+type ParamSweep struct {
+	Param  string `json:"param"`
+	Values []int  `json:"values"` // In the order the sub-tests run
+}
+
+// ParseParamSweep parses a sweep in the form param=value,value,..., e.g. "d=6,8,10".
+func ParseParamSweep(spec string) (*ParamSweep, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	param, list, found := strings.Cut(strings.TrimSpace(spec), "=")
+	if !found || list == "" {
+		return nil, fmt.Errorf("invalid parameter sweep %q, expected param=value,value", spec)
+	}
+
+	sweep := &ParamSweep{Param: strings.ToLower(strings.TrimSpace(param))}
+
+	switch sweep.Param {
+	case MeshParamD, MeshParamDlo, MeshParamDhi:
+	default:
+		return nil, fmt.Errorf("invalid sweep parameter %q, expected one of d, dlo or dhi", param)
+	}
+
+	seen := make(map[int]bool)
+
+	for _, entry := range strings.Split(list, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q in parameter sweep", sweep.Param, entry)
+		}
+
+		if seen[value] {
+			return nil, fmt.Errorf("duplicate %s value %d in parameter sweep", sweep.Param, value)
+		}
+
+		seen[value] = true
+		sweep.Values = append(sweep.Values, value)
+	}
+
+	if len(sweep.Values) < 2 {
+		return nil, fmt.Errorf("parameter sweep needs at least 2 values to compare")
+	}
+
+	return sweep, nil
+}
+
+// Flag returns the command line flag that sets the swept parameter.
+func (s *ParamSweep) Flag() string {
+	return "gossip-" + s.Param
+}
+
+// Validate checks every step of the sweep is a valid mesh degree when applied to base.
+func (s *ParamSweep) Validate(base MeshDegree) error {
+	for _, value := range s.Values {
+		if err := base.With(s.Param, value).Validate(); err != nil {
+			return fmt.Errorf("sweep step %s=%d: %w", s.Param, value, err)
+		}
+	}
+
+	return nil
+}
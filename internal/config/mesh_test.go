@@ -0,0 +1,129 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseParamSweep(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    *ParamSweep
+		expectError bool
+	}{
+		{
+			name: "empty spec",
+			spec: "",
+		},
+		{
+			name:     "mesh degree values in order",
+			spec:     "d=10, 6,8",
+			expected: &ParamSweep{Param: MeshParamD, Values: []int{10, 6, 8}},
+		},
+		{
+			name:     "parameter is case insensitive",
+			spec:     "Dhi=12,16",
+			expected: &ParamSweep{Param: MeshParamDhi, Values: []int{12, 16}},
+		},
+		{
+			name:        "unknown parameter",
+			spec:        "dlazy=6,8",
+			expectError: true,
+		},
+		{
+			name:        "missing values",
+			spec:        "d=",
+			expectError: true,
+		},
+		{
+			name:        "single value",
+			spec:        "d=8",
+			expectError: true,
+		},
+		{
+			name:        "duplicate value",
+			spec:        "d=8,8",
+			expectError: true,
+		},
+		{
+			name:        "invalid value",
+			spec:        "d=8,ten",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sweep, err := ParseParamSweep(tt.spec)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(sweep, tt.expected) {
+				t.Errorf("ParseParamSweep(%q) = %+v, want %+v", tt.spec, sweep, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMeshDegreeValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		degree      MeshDegree
+		expectError bool
+	}{
+		{name: "defaults", degree: DefaultMeshDegree()},
+		{name: "low watermark equal to target", degree: MeshDegree{D: 6, Dlo: 6, Dhi: 12}},
+		{name: "low watermark above target", degree: MeshDegree{D: 6, Dlo: 8, Dhi: 12}, expectError: true},
+		{name: "high watermark equal to target", degree: MeshDegree{D: 12, Dlo: 6, Dhi: 12}, expectError: true},
+		{name: "zero low watermark", degree: MeshDegree{D: 8, Dlo: 0, Dhi: 12}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.degree.Validate()
+			if tt.expectError && err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParamSweepValidate(t *testing.T) {
+	sweep := &ParamSweep{Param: MeshParamD, Values: []int{6, 10}}
+	if err := sweep.Validate(DefaultMeshDegree()); err != nil {
+		t.Errorf("unexpected error for d=6,10: %v", err)
+	}
+
+	// D=12 reaches the default high watermark
+	sweep.Values = append(sweep.Values, 12)
+	if err := sweep.Validate(DefaultMeshDegree()); err == nil {
+		t.Error("expected error for a step with D equal to Dhi")
+	}
+}
+
+func TestMeshDegreeHermesConfig(t *testing.T) {
+	cfg := MeshDegree{D: 4, Dlo: 2, Dhi: 6}.hermesConfig()
+
+	if cfg.D != 4 || cfg.DLow != 2 || cfg.DHigh != 6 {
+		t.Errorf("mesh degree = %d/%d/%d, want 4/2/6", cfg.D, cfg.DLow, cfg.DHigh)
+	}
+
+	// Gossipsub rejects an outbound quota at or above Dlo
+	if cfg.DOut >= cfg.DLow || cfg.DOut > cfg.D/2 {
+		t.Errorf("DOut = %d, want below Dlo and at most D/2", cfg.DOut)
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsePreviousReports parses a comma-separated list of earlier JSON reports, e.g.
+// "reports/run-1.json,reports/archive/*.json". Entries may be glob patterns, which are expanded
+// when the report is generated. Duplicates are dropped.
+func ParsePreviousReports(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	reports := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(spec, ",") {
+		path := strings.TrimSpace(entry)
+
+		switch {
+		case path == "":
+			return nil, fmt.Errorf("empty report path in %q", spec)
+		case seen[path]:
+			continue
+		}
+
+		seen[path] = true
+		reports = append(reports, path)
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePreviousReports(t *testing.T) {
+	reports, err := ParsePreviousReports(" ")
+	if err != nil || reports != nil {
+		t.Fatalf("Expected no reports for an empty spec, got %v, %v", reports, err)
+	}
+
+	reports, err = ParsePreviousReports("run-1.json, archive/*.json,run-1.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(reports, ",") != "run-1.json,archive/*.json" {
+		t.Errorf("Expected both reports in order without the duplicate, got %v", reports)
+	}
+
+	if _, err := ParsePreviousReports("run-1.json,,run-2.json"); err == nil {
+		t.Error("Expected an error for an empty report path")
+	}
+}
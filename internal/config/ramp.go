@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseMaxPeersRamp parses a comma-separated list of MaxPeers values the run steps through,
+// e.g. "50,100,200". Each value holds for one ramp step, the last until the run ends.
+func ParseMaxPeersRamp(spec string) ([]int, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	ramp := make([]int, 0)
+
+	for _, entry := range strings.Split(spec, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(entry))
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid max peers value %q in ramp, expected a positive number", entry)
+		}
+
+		if len(ramp) > 0 && ramp[len(ramp)-1] == value {
+			return nil, fmt.Errorf("max peers ramp repeats %d in consecutive steps", value)
+		}
+
+		ramp = append(ramp, value)
+	}
+
+	if len(ramp) < 2 {
+		return nil, fmt.Errorf("max peers ramp needs at least 2 steps to compare")
+	}
+
+	return ramp, nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMaxPeersRamp(t *testing.T) {
+	ramp, err := ParseMaxPeersRamp("")
+	if err != nil || ramp != nil {
+		t.Fatalf("Expected no ramp for an empty spec, got %v, %v", ramp, err)
+	}
+
+	ramp, err = ParseMaxPeersRamp("50, 100,200,100")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(ramp, []int{50, 100, 200, 100}) {
+		t.Errorf("Expected the steps in order, got %v", ramp)
+	}
+
+	for _, spec := range []string{"50", "50,0", "50,abc", "50,50,100"} {
+		if _, err := ParseMaxPeersRamp(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
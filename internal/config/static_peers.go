@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/probe-lab/hermes/eth"
+)
+
+// StaticPeer is a peer the node is pointed at explicitly, by its ENR.
+type StaticPeer struct {
+	ENR    string `json:"enr"`
+	PeerID string `json:"peer_id"` // libp2p peer ID derived from the ENR's public key
+}
+
+// ParseStaticPeers parses a comma-separated list of ENRs, e.g. "enr:-Ly4Q...,enr:-KK4Q...".
+func ParseStaticPeers(spec string) ([]StaticPeer, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	peers := make([]StaticPeer, 0)
+
+	for _, entry := range strings.Split(spec, ",") {
+		record := strings.TrimSpace(entry)
+
+		peerID, err := PeerIDFromENR(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid static peer %q: %w", record, err)
+		}
+
+		peers = append(peers, StaticPeer{ENR: record, PeerID: peerID})
+	}
+
+	return peers, nil
+}
+
+// ParseBootnodes parses a comma-separated list of boot node ENRs, checking each is a valid
+// record and none is listed twice.
+func ParseBootnodes(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	records := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(spec, ",") {
+		record := strings.TrimSpace(entry)
+
+		peerID, err := PeerIDFromENR(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boot node %q: %w", record, err)
+		}
+
+		if seen[peerID] {
+			return nil, fmt.Errorf("duplicate boot node %s", peerID)
+		}
+
+		seen[peerID] = true
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// PeerIDFromENR returns the libp2p peer ID of the node an ENR describes.
+func PeerIDFromENR(record string) (string, error) {
+	peerID, _, err := DescribeENR(record)
+
+	return peerID, err
+}
+
+// DescribeENR returns the libp2p peer ID of the node an ENR describes and the IP and TCP port
+// it advertises, empty when the record carries no TCP endpoint.
+func DescribeENR(record string) (peerID, address string, err error) {
+	node, err := enode.Parse(enode.ValidSchemes, record)
+	if err != nil {
+		return "", "", fmt.Errorf("parse enr: %w", err)
+	}
+
+	discovered, err := eth.NewDiscoveredPeer(node)
+	if err != nil {
+		return "", "", err
+	}
+
+	if node.IP() != nil && node.TCP() != 0 {
+		address = net.JoinHostPort(node.IP().String(), strconv.Itoa(node.TCP()))
+	}
+
+	return discovered.AddrInfo.ID.String(), address, nil
+}
+
+// validateStaticPeers checks that no static peer is listed twice.
+func validateStaticPeers(peers []StaticPeer) error {
+	seen := make(map[string]bool, len(peers))
+
+	for _, staticPeer := range peers {
+		if seen[staticPeer.PeerID] {
+			return fmt.Errorf("duplicate static peer %s", staticPeer.PeerID)
+		}
+
+		seen[staticPeer.PeerID] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// Two mainnet boot node ENRs.
+const (
+	testENR      = "enr:-Ly4QDhEjlkf8fwO5uWAadexy88GXZneTuUCIPHhv98v8ZfXMtC0S1S_8soiT0CMEgoeLe9Db01dtkFQUnA9YcnYC_8Bh2F0dG5ldHOIAAAAAAAAAACEZXRoMpCCS-QxAgAAZP__________gmlkgnY0gmlwhEFtZ5WJc2VjcDI1NmsxoQMRSho89q2GKx_l2FZhR1RmnSiQr6o_9hfXfQUuW6bjMohzeW5jbmV0cwCDdGNwgiMog3VkcIIjKA"
+	otherTestENR = "enr:-Ly4QLKgv5M2D4DYJgo6s4NG_K4zu4sk5HOLCfGCdtgoezsbfRbfGpQ4iSd31M88ec3DHA5FWVbkgIas9EaJeXia0nwBh2F0dG5ldHOIAAAAAAAAAACEZXRoMpCCS-QxAgAAZP__________gmlkgnY0gmlwhI1eYRaJc2VjcDI1NmsxoQLpK_A47iNBkVjka9Mde1F-Kie-R0sq97MCNKCxt2HwOIhzeW5jbmV0cwCDdGNwgiMog3VkcIIjKA"
+)
+
+func TestParseStaticPeers(t *testing.T) {
+	peers, err := ParseStaticPeers("")
+	if err != nil || peers != nil {
+		t.Fatalf("Expected no static peers for an empty spec, got %v, %v", peers, err)
+	}
+
+	peers, err = ParseStaticPeers(testENR + ", " + otherTestENR)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(peers) != 2 || peers[0].ENR != testENR || peers[1].ENR != otherTestENR {
+		t.Fatalf("Expected both ENRs in order, got %+v", peers)
+	}
+
+	for _, staticPeer := range peers {
+		if !strings.HasPrefix(staticPeer.PeerID, "16Uiu2HAm") {
+			t.Errorf("Expected a secp256k1 libp2p peer ID, got %q", staticPeer.PeerID)
+		}
+	}
+
+	if peers[0].PeerID == peers[1].PeerID {
+		t.Error("Expected distinct peer IDs for distinct ENRs")
+	}
+
+	if _, err := ParseStaticPeers("enr:not-a-record"); err == nil {
+		t.Error("Expected an error for an invalid ENR")
+	}
+}
+
+func TestValidateStaticPeers(t *testing.T) {
+	peers, err := ParseStaticPeers(testENR + "," + otherTestENR)
+	if err != nil {
+		t.Fatalf("Expected no error parsing, got %v", err)
+	}
+
+	if err := validateStaticPeers(peers); err != nil {
+		t.Errorf("Expected distinct static peers to be valid, got %v", err)
+	}
+
+	if err := validateStaticPeers(append(peers, peers[0])); err == nil || !strings.Contains(err.Error(), "duplicate static peer") {
+		t.Errorf("Expected a duplicate static peer error, got %v", err)
+	}
+}
+
+func TestParseBootnodes(t *testing.T) {
+	records, err := ParseBootnodes("")
+	if err != nil || records != nil {
+		t.Fatalf("Expected no boot nodes for an empty spec, got %v, %v", records, err)
+	}
+
+	records, err = ParseBootnodes(testENR + ", " + otherTestENR)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(records) != 2 || records[0] != testENR || records[1] != otherTestENR {
+		t.Fatalf("Expected both ENRs in order, got %v", records)
+	}
+
+	if _, err := ParseBootnodes(testENR + "," + testENR); err == nil || !strings.Contains(err.Error(), "duplicate boot node") {
+		t.Errorf("Expected a duplicate boot node error, got %v", err)
+	}
+
+	if _, err := ParseBootnodes("enr:not-a-record"); err == nil {
+		t.Error("Expected an error for an invalid ENR")
+	}
+
+	_, address, err := DescribeENR(testENR)
+	if err != nil || address != "65.109.103.149:9000" {
+		t.Errorf("Expected the ENR's TCP endpoint, got %q (%v)", address, err)
+	}
+}
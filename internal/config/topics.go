@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseTopicWhitelist parses a comma-separated list of gossip topic names, e.g.
+// "beacon_block,beacon_aggregate_and_proof". Names are given without the fork digest and
+// encoding, and without a subnet suffix to match every subnet of a topic. Duplicates are dropped.
+func ParseTopicWhitelist(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	topics := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(spec, ",") {
+		name := strings.TrimSpace(entry)
+
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("empty topic name in %q", spec)
+		case strings.Contains(name, "/"):
+			return nil, fmt.Errorf("invalid topic name %q: use the name alone, e.g. beacon_block", name)
+		case seen[name]:
+			continue
+		}
+
+		seen[name] = true
+		topics = append(topics, name)
+	}
+
+	return topics, nil
+}
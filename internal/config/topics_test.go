@@ -0,0 +1,30 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTopicWhitelist(t *testing.T) {
+	topics, err := ParseTopicWhitelist("")
+	if err != nil || topics != nil {
+		t.Fatalf("Expected no whitelist for an empty spec, got %v, %v", topics, err)
+	}
+
+	topics, err = ParseTopicWhitelist("beacon_block, beacon_aggregate_and_proof,beacon_block")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(topics, ",") != "beacon_block,beacon_aggregate_and_proof" {
+		t.Errorf("Expected both topics in order without the duplicate, got %v", topics)
+	}
+
+	if _, err := ParseTopicWhitelist("beacon_block,,voluntary_exit"); err == nil {
+		t.Error("Expected an error for an empty topic name")
+	}
+
+	if _, err := ParseTopicWhitelist("/eth2/4a26c58b/beacon_block/ssz_snappy"); err == nil {
+		t.Error("Expected an error for a full topic string")
+	}
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/analyzers"
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+)
+
+// loadAnalyzers collects the compiled-in analyzers and loads the configured plugins and
+// programs, in that order. Returns nil when there are none.
+func loadAnalyzers(cfg Config, logger logrus.FieldLogger) (*analyzers.Runner, error) {
+	registered := analyzers.Registered()
+	specs := cfg.GetAnalyzers()
+
+	if len(registered) == 0 && len(specs) == 0 {
+		return nil, nil
+	}
+
+	runner := analyzers.NewRunner(logger)
+
+	for _, analyzer := range registered {
+		if err := runner.Add(analyzer, analyzers.SourceBuiltin); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, spec := range specs {
+		analyzer, err := loadAnalyzer(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := runner.Add(analyzer, spec.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.WithField("analyzers", runner.Len()).Info("Loaded custom analyzers")
+
+	return runner, nil
+}
+
+// loadAnalyzer loads one configured analyzer.
+func loadAnalyzer(spec config.AnalyzerSpec) (analyzers.Analyzer, error) {
+	switch spec.Source {
+	case analyzers.SourcePlugin:
+		return analyzers.LoadPlugin(spec.Path)
+	case analyzers.SourceExec:
+		return analyzers.NewExecAnalyzer(spec.Path)
+	default:
+		return nil, fmt.Errorf("unknown analyzer source %q", spec.Source)
+	}
+}
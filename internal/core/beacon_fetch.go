@@ -0,0 +1,61 @@
+package core
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconfetch"
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+)
+
+// startBeaconFetchRecorder times independent validation's beacon data fetches. Hermes makes
+// them over the default HTTP transport, unless it talks TLS to the beacon node, when it builds
+// a transport of its own that cannot be observed.
+func (t *DefaultTool) startBeaconFetchRecorder() {
+	if t.config.GetValidationMode() != config.ValidationModeIndependent || t.config.GetUseTLS() {
+		return
+	}
+
+	t.beaconFetches = beaconfetch.Install(t.config.GetPrysmHost(), t.config.GetPrysmHTTPPort(), false)
+}
+
+// stopBeaconFetchRecorder puts the default HTTP transport back. The fetches recorded stay
+// available for the report.
+func (t *DefaultTool) stopBeaconFetchRecorder() {
+	if t.beaconFetches != nil {
+		t.beaconFetches.Restore()
+	}
+}
+
+// beaconFetchSummary returns the health of independent validation's beacon data fetches, nil
+// in delegated mode where Prysm validates with its own state.
+func (t *DefaultTool) beaconFetchSummary() *beaconfetch.Summary {
+	if t.config.GetValidationMode() != config.ValidationModeIndependent {
+		return nil
+	}
+
+	var summary *beaconfetch.Summary
+
+	if t.beaconFetches != nil {
+		summary = t.beaconFetches.Summary()
+	} else {
+		summary = beaconfetch.Unobserved(t.config.GetPrysmHost(), t.config.GetPrysmHTTPPort(), t.config.GetUseTLS(),
+			"Hermes builds its own transport for TLS connections to the beacon node, so the fetches could not be timed")
+	}
+
+	// Hermes keeps its cache hit counts to itself, only the configured size is known
+	overrides := config.GetValidationConfigs()[config.ValidationModeIndependent].ConfigOverrides
+	if size, ok := overrides["validation-cache-size"].(int); ok {
+		summary.CacheSize = size
+	}
+
+	if summary.Health == beaconfetch.HealthDegraded || summary.Health == beaconfetch.HealthUnhealthy {
+		t.logger.WithFields(logrus.Fields{
+			"health":   summary.Health,
+			"requests": summary.Requests,
+			"failures": summary.Failures,
+			"slow":     summary.Slow,
+		}).Warn("Beacon data fetches for independent validation were degraded, validation may have run on stale state")
+	}
+
+	return summary
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// startBeaconSyncTracker watches the beacon node delegated validation forwards gossip and
+// requests to. Every host delegates to the same beacon node, so they share the tracker. A
+// failure keeps the beacon node degraded at least until the next sync check could clear it.
+func (t *DefaultTool) startBeaconSyncTracker() {
+	if t.config.GetValidationMode() != config.ValidationModeDelegated {
+		return
+	}
+
+	grace := max(constants.BeaconSyncGrace, t.config.GetBeaconSyncInterval())
+	t.beaconSync = beaconsync.NewTracker(grace, t.logger)
+	t.eventMgr.SetBeaconSync(t.beaconSync)
+
+	for _, collector := range t.extraHosts {
+		collector.setBeaconSync(t.beaconSync)
+	}
+}
+
+// runBeaconSyncChecks periodically checks the beacon node's sync status until the run ends.
+func (t *DefaultTool) runBeaconSyncChecks(ctx context.Context, interval time.Duration) {
+	checker := beaconsync.NewChecker(t.config.GetPrysmHost(), t.config.GetPrysmHTTPPort(), t.config.GetUseTLS(), constants.DefaultBeaconSyncTimeout)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			status := checker.Check(ctx)
+
+			// A check cut short by the end of the run says nothing about the beacon node
+			if ctx.Err() != nil {
+				return
+			}
+
+			t.beaconSync.RecordSync(status)
+		}
+	}
+}
+
+// beaconSyncSummary returns the health of delegated validation's beacon node and how many of
+// the peers' score snapshots were left out while it was degraded, nil in independent mode.
+func (t *DefaultTool) beaconSyncSummary(peers map[string]*peer.Stats, end time.Time) *beaconsync.Summary {
+	if t.beaconSync == nil {
+		return nil
+	}
+
+	summary := t.beaconSync.Summary(end)
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for _, session := range stats.ConnectionSessions {
+			for _, snapshot := range session.PeerScores {
+				if snapshot.BeaconDegraded {
+					summary.PausedSnapshots++
+				}
+			}
+		}
+	}
+
+	if summary.Health == beaconsync.HealthDegraded {
+		t.logger.WithFields(logrus.Fields{
+			"failures":         summary.Failures,
+			"windows":          len(summary.Windows),
+			"degraded_seconds": summary.DegradedSeconds,
+			"paused_snapshots": summary.PausedSnapshots,
+		}).Warn("Beacon node behind delegated validation was degraded, peer scores in those windows were not attributed")
+	}
+
+	return summary
+}
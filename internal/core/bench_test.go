@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+)
+
+// benchPeers are the peer counts of the synthetic datasets.
+var benchPeers = []int{1000, 10000}
+
+// benchTopics are the topics each synthetic peer is grafted to and scored on.
+var benchTopics = []string{
+	"/eth2/4a26c58b/beacon_block/ssz_snappy",
+	"/eth2/4a26c58b/beacon_aggregate_and_proof/ssz_snappy",
+	"/eth2/4a26c58b/beacon_attestation_3/ssz_snappy",
+}
+
+// benchAgents are the clients the synthetic peers run, in turn.
+var benchAgents = []string{
+	"Lighthouse/v7.0.1-e42406d/x86_64-linux",
+	"Prysm/v6.0.3/9dfeb9a7e0c3b4ae0d6f8d0e5b1c0f7a2f1d8c4b",
+	"teku/v25.6.0/linux-x86_64/-eclipseadoptium-openjdk64bitservervm-java-21",
+	"nimbus",
+}
+
+// syntheticEvents generates the trace events of a run with the given number of peers. The
+// peers connect over the first ten minutes, are identified, grafted, scored and sent a
+// message, and every other peer says goodbye and disconnects two minutes in.
+func syntheticEvents(peers int) []*host.TraceEvent {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	spacing := 10 * time.Minute / time.Duration(peers)
+	events := make([]*host.TraceEvent, 0, peers*12+len(benchTopics))
+
+	add := func(eventType string, at time.Time, topic string, data map[string]interface{}) {
+		events = append(events, &host.TraceEvent{Type: eventType, Topic: topic, Timestamp: at, Payload: data})
+	}
+
+	for _, topic := range benchTopics {
+		add("JOIN", start, "", map[string]interface{}{"Topic": topic})
+	}
+
+	for i := 0; i < peers; i++ {
+		id := fmt.Sprintf("16Uiu2HAm%044d", i)
+		agent := benchAgents[i%len(benchAgents)]
+		at := start.Add(time.Second + time.Duration(i)*spacing)
+
+		connection := map[string]interface{}{
+			"RemotePeer":   id,
+			"RemoteMaddrs": fmt.Sprintf("/ip4/198.51.%d.%d/tcp/9000", i/256%256, i%256),
+			"AgentVersion": agent,
+			"Direction":    []string{"Outbound", "Inbound"}[i%2],
+			"Opened":       at.Format(time.RFC3339Nano),
+			"Limited":      false,
+		}
+
+		add("CONNECTED", at, "", connection)
+		add("REQUEST_STATUS", at.Add(400*time.Millisecond), "", map[string]interface{}{
+			"PeerID": id, "AgentVersion": agent, "ForkDigest": "4a26c58b", "HeadSlot": int64(11800000), "FinalizedEpoch": int64(368748),
+		})
+		add("HANDLE_STATUS", at.Add(600*time.Millisecond), "", map[string]interface{}{
+			"PeerID": id, "ProtocolID": "/eth2/beacon_chain/req/status/1/ssz_snappy", "LatencyS": 0.012,
+			"Request": map[string]interface{}{"ForkDigest": "4a26c58b", "HeadSlot": int64(11799990), "FinalizedEpoch": int64(368747)},
+		})
+
+		for _, topic := range benchTopics {
+			add("GRAFT", at.Add(10*time.Second), topic, map[string]interface{}{"PeerID": id, "Topic": topic})
+		}
+
+		add("DELIVER_MESSAGE", at.Add(20*time.Second), benchTopics[0], map[string]interface{}{
+			"PeerID": id, "Topic": benchTopics[0], "MsgID": fmt.Sprintf("%x", i), "Local": false,
+		})
+
+		for snapshot := 1; snapshot <= 3; snapshot++ {
+			topics := make([]interface{}, 0, len(benchTopics))
+			for _, topic := range benchTopics {
+				topics = append(topics, map[string]interface{}{
+					"Topic": topic, "TimeInMesh": int64(time.Duration(snapshot) * 20 * time.Second),
+					"FirstMessageDeliveries": float64(snapshot), "MeshMessageDeliveries": 2.5, "InvalidMessageDeliveries": 0.0,
+				})
+			}
+
+			add("PEERSCORE", at.Add(time.Duration(snapshot)*30*time.Second), "", map[string]interface{}{
+				"PeerID": id, "Score": float64(i%40) - 10, "AppSpecificScore": 0.0, "IPColocationFactor": 0.0, "BehaviourPenalty": 0.0, "Topics": topics,
+			})
+		}
+
+		if i%2 == 1 {
+			add("HANDLE_GOODBYE", at.Add(2*time.Minute), "", map[string]interface{}{"PeerID": id, "Code": int64(129), "Reason": "too many peers"})
+			add("DISCONNECTED", at.Add(2*time.Minute+time.Second), "", connection)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return events
+}
+
+// BenchmarkHandleEvent measures the event pipeline, from a Hermes trace event to the peer's
+// sessions, over whole runs.
+func BenchmarkHandleEvent(b *testing.B) {
+	for _, peers := range benchPeers {
+		events := syntheticEvents(peers)
+
+		b.Run(fmt.Sprintf("peers=%d", peers), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				b.StopTimer()
+				tool, _, _ := newReplayTool(b, events)
+				b.StartTimer()
+
+				for _, event := range events {
+					if err := tool.handleEvent(context.Background(), event); err != nil {
+						b.Fatalf("Expected no error handling %s, got %v", event.Type, err)
+					}
+				}
+			}
+
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(events)), "ns/event")
+		})
+	}
+}
+
+// BenchmarkGenerateReports measures saving every report of a run.
+func BenchmarkGenerateReports(b *testing.B) {
+	b.Chdir(b.TempDir())
+	b.Setenv("OPENROUTER_API_KEY", "")
+
+	for _, peers := range benchPeers {
+		events := syntheticEvents(peers)
+
+		b.Run(fmt.Sprintf("peers=%d", peers), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				b.StopTimer()
+				tool, _, end := newReplayTool(b, events)
+
+				for _, event := range events {
+					if err := tool.handleEvent(context.Background(), event); err != nil {
+						b.Fatalf("Expected no error handling %s, got %v", event.Type, err)
+					}
+				}
+
+				end()
+				b.StartTimer()
+
+				if err := tool.SaveReports(context.Background()); err != nil {
+					b.Fatalf("Expected no error saving reports, got %v", err)
+				}
+			}
+		})
+	}
+}
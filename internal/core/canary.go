@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/canary"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// startCanaries connects one canary peer of each behaviour to the primary host, staging it on
+// the beacon block topic until ctx is done. Each block is validated however the run validates
+// gossip, so an undecodable one is rejected in either mode. The canaries are closed once they
+// stop, under wg.
+func (t *DefaultTool) startCanaries(ctx context.Context, wg *sync.WaitGroup) {
+	topics := t.hermesCtrl.GetTopicExpectations()
+	if topics == nil {
+		t.logger.Warn("Gossip topics are unknown, canary peers not started")
+
+		return
+	}
+
+	t.canaryTopic = fmt.Sprintf("/eth2/%s/%s/ssz_snappy", topics.ForkDigest, p2p.GossipBlockMessage)
+
+	for _, behaviour := range []string{canary.BehaviourHonest, canary.BehaviourInvalid} {
+		c, err := canary.New(ctx, behaviour, t.hermesCtrl.GetListenAddr, t.canaryTopic, t.logger)
+		if err != nil {
+			t.logger.WithError(err).WithField("behaviour", behaviour).Warn("Failed to start canary peer")
+
+			continue
+		}
+
+		t.canaries = append(t.canaries, c)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			c.Run(ctx)
+
+			if err := c.Close(); err != nil {
+				t.logger.WithError(err).WithField("behaviour", behaviour).Debug("Failed to close canary peer")
+			}
+		}()
+	}
+}
+
+// canaryResult checks the primary host scored the canaries as their behaviour calls for, nil
+// when none ran. The canaries are tagged with their origin, which keeps them out of the
+// statistics of the network's peers.
+func (t *DefaultTool) canaryResult(peers map[string]*peer.Stats) *canary.Result {
+	if len(t.canaries) == 0 {
+		return nil
+	}
+
+	staged := make([]canary.Staged, 0, len(t.canaries))
+
+	for _, c := range t.canaries {
+		current := c.Staged()
+		staged = append(staged, current)
+
+		if stats := peers[current.PeerID]; stats != nil {
+			stats.Origin = peer.OriginCanary
+		}
+	}
+
+	result := canary.Verify(t.canaryTopic, staged, peers)
+
+	for _, outcome := range result.Canaries {
+		fields := logrus.Fields{
+			"behaviour":    outcome.Behaviour,
+			"canary_id":    outcome.PeerID,
+			"snapshots":    outcome.Snapshots,
+			"lowest_score": outcome.LowestScore,
+			"invalid":      outcome.InvalidDeliveries,
+		}
+
+		if !outcome.Passed {
+			t.logger.WithFields(fields).Warn("Primary host did not score a canary peer as expected, the measurement pipeline may be broken")
+
+			continue
+		}
+
+		t.logger.WithFields(fields).Info("Primary host scored a canary peer as expected")
+	}
+
+	return result
+}
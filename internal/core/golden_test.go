@@ -0,0 +1,298 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden report files from the fixture event logs")
+
+// goldenEvents is the fixture event log in each golden scenario directory, every other file
+// there is a golden report file.
+const goldenEvents = "events.ndjson"
+
+// goldenSummary holds the run health summary printed once the reports are saved.
+const goldenSummary = "summary.txt"
+
+// goldenDuration is how long the replayed runs last from their first event.
+const goldenDuration = 15 * time.Minute
+
+// TestGoldenReports replays each scenario's event log through the event pipeline and checks
+// every report file written for it byte for byte. Run `make update-golden` to accept changes.
+func TestGoldenReports(t *testing.T) {
+	scenarios, err := filepath.Glob(filepath.Join("testdata", "golden", "*", goldenEvents))
+	if err != nil {
+		t.Fatalf("Expected no error listing scenarios, got %v", err)
+	}
+
+	if len(scenarios) == 0 {
+		t.Fatal("Expected golden scenarios in testdata/golden")
+	}
+
+	for _, eventsFile := range scenarios {
+		dir, err := filepath.Abs(filepath.Dir(eventsFile))
+		if err != nil {
+			t.Fatalf("Expected no error resolving %s, got %v", eventsFile, err)
+		}
+
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			events, err := loadEvents(filepath.Join(dir, goldenEvents))
+			if err != nil {
+				t.Fatalf("Expected no error loading events, got %v", err)
+			}
+
+			got := replayEvents(t, events)
+
+			if *updateGolden {
+				writeGolden(t, dir, got)
+
+				return
+			}
+
+			want := readGolden(t, dir)
+
+			for _, name := range sortedNames(want) {
+				if _, ok := got[name]; !ok {
+					t.Errorf("Expected %s to be written", name)
+				}
+			}
+
+			for _, name := range sortedNames(got) {
+				expected, ok := want[name]
+				if !ok {
+					t.Errorf("Unexpected report file %s, run make update-golden if it is new", name)
+
+					continue
+				}
+
+				if !bytes.Equal(got[name], expected) {
+					t.Errorf("%s differs from the golden file at byte %d, run make update-golden if the change is intended",
+						name, firstDifference(got[name], expected))
+				}
+			}
+		})
+	}
+}
+
+// loadEvents reads a log of Hermes trace events, one JSON event per line. Integral numbers
+// are decoded as int64 like the integer fields of Hermes payloads, the others as float64.
+func loadEvents(path string) ([]*host.TraceEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := make([]*host.TraceEvent, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+
+		event := &host.TraceEvent{}
+		if err := decoder.Decode(event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		event.Payload = fixtureNumbers(event.Payload)
+		events = append(events, event)
+	}
+
+	return events, scanner.Err()
+}
+
+// fixtureNumbers converts the JSON numbers in a decoded payload to int64 or float64.
+func fixtureNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		f, _ := v.Float64()
+
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = fixtureNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = fixtureNumbers(item)
+		}
+	}
+
+	return value
+}
+
+// replayEvents runs the events through a tool whose clock starts at the first event, writes
+// the reports once the run's duration has passed and returns the files written, and the
+// printed summary, by name.
+func replayEvents(t *testing.T, events []*host.TraceEvent) map[string][]byte {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	t.Setenv("OPENROUTER_API_KEY", "")
+
+	tool, summary, end := newReplayTool(t, events)
+
+	for _, event := range events {
+		if err := tool.handleEvent(context.Background(), event); err != nil {
+			t.Fatalf("Expected no error handling %s at %s, got %v", event.Type, event.Timestamp, err)
+		}
+	}
+
+	end()
+
+	if err := tool.SaveReports(context.Background()); err != nil {
+		t.Fatalf("Expected no error saving reports, got %v", err)
+	}
+
+	files := readFiles(t, ".", "")
+	files[goldenSummary] = summary.Bytes()
+
+	return files
+}
+
+// newReplayTool creates a tool whose clock starts at the first event, with the buffer its run
+// health summary is printed to. Calling end moves the clock past the run's cooldown.
+func newReplayTool(tb testing.TB, events []*host.TraceEvent) (*DefaultTool, *bytes.Buffer, func()) {
+	tb.Helper()
+
+	if len(events) == 0 {
+		tb.Fatal("Expected at least one event")
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := config.NewDefaultConfig()
+	cfg.SetTestDuration(goldenDuration)
+
+	now := events[0].Timestamp
+
+	tool, err := newTool(cfg, logger, func() time.Time { return now })
+	if err != nil {
+		tb.Fatalf("Expected no error creating tool, got %v", err)
+	}
+
+	summary := &bytes.Buffer{}
+
+	tool.summaryOut = summary
+	tool.startTime = now
+	tool.phases = peer.NewRunPhases(now, cfg.GetWarmupDuration(), cfg.GetTestDuration(), cfg.GetCooldownDuration())
+
+	return tool, summary, func() { now = tool.phases.CooldownEnd }
+}
+
+// readGolden returns the golden report files of a scenario by name.
+func readGolden(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+
+	files := readFiles(t, dir, "")
+	delete(files, goldenEvents)
+
+	return files
+}
+
+// writeGolden replaces a scenario's golden report files with the ones written by the replay.
+func writeGolden(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+
+	for name := range readGolden(t, dir) {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("Expected no error removing %s, got %v", name, err)
+		}
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Expected no error creating %s, got %v", filepath.Dir(path), err)
+		}
+
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatalf("Expected no error writing %s, got %v", path, err)
+		}
+	}
+
+	t.Logf("Updated %d golden files in %s", len(files), dir)
+}
+
+// readFiles reads every file below dir, keyed by its slash separated path relative to dir.
+func readFiles(t *testing.T, dir, prefix string) map[string][]byte {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(dir, prefix))
+	if err != nil {
+		t.Fatalf("Expected no error reading %s, got %v", dir, err)
+	}
+
+	files := make(map[string][]byte)
+
+	for _, entry := range entries {
+		name := filepath.ToSlash(filepath.Join(prefix, entry.Name()))
+
+		if entry.IsDir() {
+			for nested, content := range readFiles(t, dir, name) {
+				files[nested] = content
+			}
+
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Expected no error reading %s, got %v", name, err)
+		}
+
+		files[name] = content
+	}
+
+	return files
+}
+
+// sortedNames returns the file names in order, so failures are reported deterministically.
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// firstDifference returns the offset of the first byte that differs between a and b.
+func firstDifference(a, b []byte) int {
+	for i := range min(len(a), len(b)) {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+
+	return min(len(a), len(b))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -13,12 +14,17 @@ import (
 	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p/encoder"
 	"github.com/OffchainLabs/prysm/v6/config/params"
 	"github.com/OffchainLabs/prysm/v6/time/slots"
+	libp2ppeer "github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
 	"github.com/probe-lab/hermes/eth"
 	"github.com/probe-lab/hermes/host"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
 	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
 )
 
 // DefaultHermesController implements the HermesController interface.
@@ -29,6 +35,22 @@ type DefaultHermesController struct {
 	callback      func(ctx context.Context, event interface{}) error
 	networkConfig *params.NetworkConfig
 	beaconConfig  *params.BeaconChainConfig
+	slotClock     *peer.SlotClock
+	topics        *peer.TopicExpectations
+	origins       map[string]string
+	bootstrap     []peer.BootstrapNode
+	listenAddr    *libp2ppeer.AddrInfo
+
+	// Cancels the node's context, and is closed once the node has returned
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	// Optional per-host overrides when running several hosts in one process
+	host          *config.HostSpec
+	freshIdentity bool
+
+	// MaxPeers the next node starts with, overriding the configured one when set
+	maxPeers int
 }
 
 // NewHermesController creates a new Hermes controller.
@@ -39,6 +61,17 @@ func NewHermesController(cfg config.Config, logger logrus.FieldLogger) *DefaultH
 	}
 }
 
+// NewHostHermesController creates a Hermes controller for one of several parallel hosts.
+// Hosts other than the primary get a freshly generated libp2p identity.
+func NewHostHermesController(cfg config.Config, host config.HostSpec, primary bool, logger logrus.FieldLogger) *DefaultHermesController {
+	return &DefaultHermesController{
+		config:        cfg,
+		logger:        logger.WithFields(logrus.Fields{"component": "hermes_controller", "host": host.Label}),
+		host:          &host,
+		freshIdentity: !primary,
+	}
+}
+
 // Start initializes and starts the Hermes node.
 func (hc *DefaultHermesController) Start(ctx context.Context) error {
 	hc.logger.Info("Starting Hermes node")
@@ -80,12 +113,34 @@ func (hc *DefaultHermesController) Start(ctx context.Context) error {
 		}
 	}
 
+	// Hermes cannot be told to dial a peer, static peers are handed to discv5 as extra
+	// bootstrap nodes so they are found and dialed like any other discovered peer
+	// Configured boot nodes replace the network's, devnets and private networks may have none
+	bootnodes := c.Network.BootstrapNodes
+	if configured := hc.config.GetBootnodes(); len(configured) > 0 {
+		bootnodes = configured
+	}
+
+	if len(bootnodes) == 0 && len(hc.config.GetStaticPeers()) == 0 {
+		return fmt.Errorf("network %s has no boot nodes, set them with --bootnodes or --static-peers", network)
+	}
+
+	hc.bootstrap = hc.bootstrapNodes(bootnodes)
+	hc.origins = bootstrapOrigins(hc.bootstrap)
+	c.Network.BootstrapNodes = withStaticPeers(bootnodes, hc.config.GetStaticPeers())
+
 	hc.networkConfig = c.Network
 	hc.beaconConfig = c.Beacon
 
 	genesisRoot := c.Genesis.GenesisValidatorRoot
 	genesisTime := c.Genesis.GenesisTime
 
+	hc.slotClock = peer.NewSlotClock(
+		genesisTime,
+		time.Duration(hc.beaconConfig.SecondsPerSlot)*time.Second,
+		uint64(hc.beaconConfig.SlotsPerEpoch),
+	)
+
 	// Compute fork version and fork digest
 	currentSlot := slots.Since(genesisTime)
 	currentEpoch := slots.ToEpoch(currentSlot)
@@ -100,6 +155,9 @@ func (hc *DefaultHermesController) Start(ctx context.Context) error {
 		return fmt.Errorf("create fork digest (%s, %x): %w", genesisTime, genesisRoot, err)
 	}
 
+	// Gossip topics the node should subscribe to for the current fork
+	hc.topics = expectedTopics(hc.beaconConfig, currentEpoch, forkDigest, hc.subnets())
+
 	// Override global configuration
 	params.OverrideBeaconConfig(hc.beaconConfig)
 	params.OverrideBeaconNetworkConfig(hc.networkConfig)
@@ -107,11 +165,17 @@ func (hc *DefaultHermesController) Start(ctx context.Context) error {
 	// Create Hermes configuration
 	hermesConfig := hc.createHermesConfig(forkDigest, currentForkVersion)
 	hermesConfig.GenesisConfig = c.Genesis
+	hermesConfig.BeaconConfig = hermesBeaconConfig(hc.beaconConfig, currentEpoch)
 
 	if err = hermesConfig.Validate(); err != nil {
 		return fmt.Errorf("invalid Hermes node config: %w", err)
 	}
 
+	// A node that cannot follow the fork's topics would only show up in the report
+	if err = checkTopics(hc.topics, hermesTopics(hermesConfig)); err != nil {
+		return fmt.Errorf("invalid gossip topic configuration: %w", err)
+	}
+
 	// Create the node
 	node, err := eth.NewNode(hermesConfig)
 	if err != nil {
@@ -123,24 +187,38 @@ func (hc *DefaultHermesController) Start(ctx context.Context) error {
 	}
 
 	hc.node = node
+	hc.listenAddr = localAddr(hermesConfig)
 
 	// Register event callback
 	hc.node.OnEvent(func(ctx context.Context, event *host.TraceEvent) {
 		if hc.callback != nil {
 			if err := hc.callback(ctx, event); err != nil {
-				hc.logger.WithError(err).Error("Event callback failed")
+				hc.logger.WithError(err).WithFields(common.EventFields(event)).Error("Event callback failed")
 			}
 		}
 	})
 
-	// Start the node in a goroutine
+	// Start the node in a goroutine, under its own context so Stop can tear it down
+	nodeCtx, cancel := context.WithCancel(ctx)
+	hc.cancel = cancel
+	hc.stopped = make(chan struct{})
+
 	go func() {
+		defer close(hc.stopped)
+
 		// Blackhole hermes logs by redirecting the default logger
 		originalOutput := log.Writer()
 		log.SetOutput(io.Discard)
 		defer log.SetOutput(originalOutput)
 
-		if err := hc.node.Start(ctx); err != nil {
+		if err := hc.node.Start(nodeCtx); err != nil {
+			// Hermes returns the context error when it is stopped
+			if nodeCtx.Err() != nil {
+				hc.logger.WithError(err).Debug("Hermes node stopped")
+
+				return
+			}
+
 			hc.logger.WithError(err).Fatal("Failed to start hermes")
 		}
 	}()
@@ -150,18 +228,34 @@ func (hc *DefaultHermesController) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop gracefully shuts down the Hermes node.
+// Stop shuts down the Hermes node and waits up to the shutdown timeout for it to close its
+// connections. Hermes has no explicit stop method, so the node's context is cancelled.
 func (hc *DefaultHermesController) Stop() error {
+	if hc.cancel == nil {
+		return nil
+	}
+
 	hc.logger.Info("Stopping Hermes node")
+	hc.cancel()
+
+	timeout := hc.config.GetShutdownTimeout()
 
-	if hc.node != nil {
-		// Hermes doesn't have an explicit stop method, so we rely on context cancellation
-		hc.logger.Info("Hermes node shutdown initiated")
+	select {
+	case <-hc.stopped:
+		hc.logger.Info("Hermes node stopped")
+	case <-time.After(timeout):
+		return fmt.Errorf("hermes node did not stop within %s", timeout)
 	}
 
 	return nil
 }
 
+// SetMaxPeers sets the MaxPeers the node starts with from its next start. Hermes reads it
+// once at startup, so a running node must be restarted for it to take effect.
+func (hc *DefaultHermesController) SetMaxPeers(maxPeers int) {
+	hc.maxPeers = maxPeers
+}
+
 // RegisterEventCallback sets the callback function for processing events.
 func (hc *DefaultHermesController) RegisterEventCallback(callback func(ctx context.Context, event interface{}) error) {
 	hc.callback = callback
@@ -173,6 +267,126 @@ func (hc *DefaultHermesController) GetNode() interface{} {
 	return hc.node
 }
 
+// GetSlotClock returns the slot clock for the configured network, or nil if Hermes has not been started.
+func (hc *DefaultHermesController) GetSlotClock() *peer.SlotClock {
+	return hc.slotClock
+}
+
+// GetTopicExpectations returns the gossip topics expected for the fork the node started in,
+// or nil if Hermes has not been started.
+func (hc *DefaultHermesController) GetTopicExpectations() *peer.TopicExpectations {
+	return hc.topics
+}
+
+// GetPeerOrigins returns the origin of the boot nodes and static peers by peer ID, or nil if
+// Hermes has not been started.
+func (hc *DefaultHermesController) GetPeerOrigins() map[string]string {
+	return hc.origins
+}
+
+// GetBootstrapNodes returns the boot nodes and static peers handed to discv5, or nil if
+// Hermes has not been started.
+func (hc *DefaultHermesController) GetBootstrapNodes() []peer.BootstrapNode {
+	return hc.bootstrap
+}
+
+// GetListenAddr returns the address peers in this process dial the node on, or nil if Hermes
+// has not been started or picks its libp2p port itself.
+func (hc *DefaultHermesController) GetListenAddr() *libp2ppeer.AddrInfo {
+	return hc.listenAddr
+}
+
+// localAddr returns the address the node's libp2p host is dialed on from this process, or nil
+// without a fixed port. The node was created, so its private key is parsed and cached.
+func localAddr(cfg *eth.NodeConfig) *libp2ppeer.AddrInfo {
+	if cfg.Libp2pPort == 0 {
+		return nil
+	}
+
+	key, err := cfg.PrivateKey()
+	if err != nil {
+		return nil
+	}
+
+	id, err := libp2ppeer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil
+	}
+
+	// Wildcard listeners are reachable on the loopback address
+	ip := net.ParseIP(cfg.Libp2pHost)
+	if ip == nil || ip.IsUnspecified() {
+		ip = net.IPv4(127, 0, 0, 1)
+	}
+
+	addr, err := manet.FromNetAddr(&net.TCPAddr{IP: ip, Port: cfg.Libp2pPort})
+	if err != nil {
+		return nil
+	}
+
+	return &libp2ppeer.AddrInfo{ID: id, Addrs: []ma.Multiaddr{addr}}
+}
+
+// bootstrapNodes describes the boot node ENRs and the static peers, boot nodes first.
+func (hc *DefaultHermesController) bootstrapNodes(bootnodes []string) []peer.BootstrapNode {
+	nodes := make([]peer.BootstrapNode, 0, len(bootnodes)+len(hc.config.GetStaticPeers()))
+	index := make(map[string]int, cap(nodes))
+
+	add := func(record, origin string) {
+		peerID, address, err := config.DescribeENR(record)
+		if err != nil {
+			hc.logger.WithError(err).Debug("Skipping boot node without a libp2p identity")
+
+			return
+		}
+
+		node := peer.BootstrapNode{PeerID: peerID, ENR: record, Address: address, Origin: origin}
+
+		// A boot node also listed as a static peer is what the operator asked for
+		if i, ok := index[peerID]; ok {
+			nodes[i] = node
+
+			return
+		}
+
+		index[peerID] = len(nodes)
+		nodes = append(nodes, node)
+	}
+
+	for _, record := range bootnodes {
+		add(record, peer.OriginBootnode)
+	}
+
+	for _, staticPeer := range hc.config.GetStaticPeers() {
+		add(staticPeer.ENR, peer.OriginStatic)
+	}
+
+	return nodes
+}
+
+// bootstrapOrigins maps the peer IDs of the bootstrap nodes to their origin.
+func bootstrapOrigins(nodes []peer.BootstrapNode) map[string]string {
+	origins := make(map[string]string, len(nodes))
+
+	for _, node := range nodes {
+		origins[node.PeerID] = node.Origin
+	}
+
+	return origins
+}
+
+// withStaticPeers returns the bootstrap ENRs with the static peers' ENRs appended.
+func withStaticPeers(bootnodes []string, statics []config.StaticPeer) []string {
+	records := make([]string, 0, len(bootnodes)+len(statics))
+	records = append(records, bootnodes...)
+
+	for _, staticPeer := range statics {
+		records = append(records, staticPeer.ENR)
+	}
+
+	return records
+}
+
 // createHermesConfig creates the Hermes node configuration.
 func (hc *DefaultHermesController) createHermesConfig(forkDigest [4]byte, currentForkVersion [4]byte) *eth.NodeConfig {
 	cfg := hc.config.AsHermesConfig()
@@ -189,12 +403,55 @@ func (hc *DefaultHermesController) createHermesConfig(forkDigest [4]byte, curren
 	cfg.Tracer = otel.GetTracerProvider().Tracer("hermes")
 	cfg.Meter = otel.GetMeterProvider().Meter("hermes")
 
+	if hc.maxPeers > 0 {
+		cfg.MaxPeers = hc.maxPeers
+	}
+
+	// Apply per-host overrides so parallel hosts do not collide
+	if hc.host != nil {
+		hc.applyHostConfig(cfg)
+	}
+
 	// Apply validation-specific configuration overrides
 	hc.applyValidationConfig(cfg)
 
 	return cfg
 }
 
+// applyHostConfig applies the listen ports, identity and setting overrides of a parallel host.
+func (hc *DefaultHermesController) applyHostConfig(cfg *eth.NodeConfig) {
+	if hc.host.Libp2pPort != 0 || hc.freshIdentity {
+		cfg.Libp2pPort = hc.host.Libp2pPort
+	}
+
+	if hc.host.Devp2pPort != 0 || hc.freshIdentity {
+		cfg.Devp2pPort = hc.host.Devp2pPort
+	}
+
+	// An empty key makes Hermes generate a new one
+	if hc.freshIdentity {
+		cfg.PrivateKeyStr = ""
+	}
+
+	hc.host.Apply(cfg, hc.config.GetMeshDegree())
+
+	hc.logger.WithFields(logrus.Fields{
+		"libp2p_port": cfg.Libp2pPort,
+		"devp2p_port": cfg.Devp2pPort,
+		"settings":    hc.host.Settings(),
+	}).Info("Applying host configuration")
+}
+
+// subnets returns the subnets the node subscribes to per topic, the host's where it
+// overrides the run's.
+func (hc *DefaultHermesController) subnets() map[string]*eth.SubnetConfig {
+	if hc.host == nil {
+		return hc.config.GetSubnets()
+	}
+
+	return hc.host.SubnetConfigs(hc.config.GetSubnets())
+}
+
 // applyValidationConfig applies validation-specific configuration overrides.
 func (hc *DefaultHermesController) applyValidationConfig(cfg *eth.NodeConfig) {
 	validationMode := hc.config.GetValidationMode()
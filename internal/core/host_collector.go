@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/events"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// hostCollector collects peer data for an additional Hermes host running alongside
+// the primary one. Each host keeps its own peer state so sessions are never mixed.
+type hostCollector struct {
+	spec       config.HostSpec
+	lateGrace  time.Duration
+	sampler    *common.LogSampler
+	eventRates map[string]int
+	logger     logrus.FieldLogger
+
+	peerRepo   peer.Repository
+	sessionMgr peer.SessionManager
+	eventMgr   *events.DefaultManager
+	hermesCtrl HermesController
+	beaconSync *beaconsync.Tracker // Shared with the primary host, all hosts delegate to the same beacon node
+}
+
+// newHostCollector creates the components for an additional Hermes host.
+func newHostCollector(cfg config.Config, spec config.HostSpec, logger logrus.FieldLogger) (*hostCollector, error) {
+	hc := &hostCollector{
+		spec:       spec,
+		lateGrace:  cfg.GetLateEventGrace(),
+		eventRates: cfg.GetEventSampleRates(),
+		sampler:    common.NewLogSampler(cfg.GetLogSample()),
+		logger:     logger.WithField("host", spec.Label),
+	}
+
+	hc.peerRepo = peer.NewInMemoryRepository(hc.logger)
+	hc.sessionMgr = peer.NewSessionManager(hc.peerRepo, hc.logger)
+	hc.eventMgr = events.NewManager(hc, hc.logger)
+
+	if err := hc.eventMgr.RegisterDefaultHandlers(); err != nil {
+		return nil, fmt.Errorf("failed to register event handlers for host %s: %w", spec.Label, err)
+	}
+
+	if whitelist := cfg.GetTopicWhitelist(); len(whitelist) > 0 {
+		hc.eventMgr.SetTopicWhitelist(whitelist)
+	}
+
+	hc.hermesCtrl = NewHostHermesController(cfg, spec, false, logger)
+
+	return hc, nil
+}
+
+// start starts the host's Hermes node and routes its events to the host's handlers.
+func (hc *hostCollector) start(ctx context.Context) error {
+	if err := hc.hermesCtrl.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start Hermes host %s: %w", hc.spec.Label, err)
+	}
+
+	hc.hermesCtrl.RegisterEventCallback(hc.handleEvent)
+
+	return nil
+}
+
+// handleEvent processes events from the host's Hermes node.
+func (hc *hostCollector) handleEvent(ctx context.Context, event interface{}) error {
+	if hermesEvent, ok := event.(*host.TraceEvent); ok {
+		return hc.eventMgr.HandleEvent(ctx, hermesEvent)
+	}
+
+	return fmt.Errorf("unsupported event type: %T", event)
+}
+
+// hostPeers returns the peers observed by this host.
+func (hc *hostCollector) hostPeers() peer.HostPeers {
+	return peer.HostPeers{
+		Label:      hc.spec.Label,
+		Libp2pPort: hc.spec.Libp2pPort,
+		Devp2pPort: hc.spec.Devp2pPort,
+		Settings:   hc.spec.Settings(),
+		Peers:      hc.peerRepo.GetAllPeers(),
+	}
+}
+
+func (hc *hostCollector) GetPeer(peerID string) (interface{}, bool) {
+	peer, exists := hc.peerRepo.GetPeer(peerID)
+
+	return peer, exists
+}
+
+func (hc *hostCollector) CreatePeer(peerID string) interface{} {
+	return hc.peerRepo.CreatePeer(peerID)
+}
+
+func (hc *hostCollector) UpdatePeer(peerID string, updateFn func(interface{})) {
+	hc.peerRepo.UpdatePeer(peerID, func(peer *peer.Stats) {
+		updateFn(peer)
+	})
+}
+
+func (hc *hostCollector) UpdateOrCreatePeer(peerID string, updateFn func(interface{})) {
+	hc.peerRepo.UpdateOrCreatePeer(peerID, func(peer *peer.Stats) {
+		updateFn(peer)
+	})
+}
+
+func (hc *hostCollector) GetLogger() logrus.FieldLogger {
+	return hc.logger
+}
+
+func (hc *hostCollector) IncrementEventCount(peerID, eventType string) {
+	hc.peerRepo.IncrementEventCount(peerID, eventType)
+}
+
+func (hc *hostCollector) IncrementMessageCount(peerID string) {
+	if err := hc.sessionMgr.IncrementMessageCount(peerID); err != nil {
+		hc.logger.WithError(err).WithField("peer_id", peerID).Debug("Failed to increment message count")
+	}
+}
+
+func (hc *hostCollector) GetLateEventGrace() time.Duration {
+	return hc.lateGrace
+}
+
+func (hc *hostCollector) GetEventLogLevel() logrus.Level {
+	return hc.sampler.Level()
+}
+
+func (hc *hostCollector) IsBackendDegraded(at time.Time) bool {
+	return hc.beaconSync != nil && hc.beaconSync.Degraded(at)
+}
+
+func (hc *hostCollector) GetEventSampleRate(eventType string) int {
+	return hc.eventRates[eventType]
+}
+
+// setBeaconSync shares the primary host's beacon node tracker with this host.
+func (hc *hostCollector) setBeaconSync(tracker *beaconsync.Tracker) {
+	hc.beaconSync = tracker
+	hc.eventMgr.SetBeaconSync(tracker)
+}
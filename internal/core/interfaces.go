@@ -4,20 +4,39 @@ import (
 	"context"
 	"time"
 
+	libp2ppeer "github.com/libp2p/go-libp2p/core/peer"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconfetch"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconpeers"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
+	"github.com/ethpandaops/hermes-peer-score/internal/canary"
+	"github.com/ethpandaops/hermes-peer-score/internal/clockskew"
 	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/events"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/reachability"
+	"github.com/ethpandaops/hermes-peer-score/internal/recommend"
+	"github.com/ethpandaops/hermes-peer-score/internal/resources"
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
 )
 
 // Tool defines the interface for the main peer score tool.
 type Tool interface {
 	Start(ctx context.Context) error
 	Stop() error
-	GenerateReport() (*Report, error)
+	GenerateReport(ctx context.Context) (*Report, error)
 	GetLogger() logrus.FieldLogger
 	GetConfig() Config
+	OnEvent(eventType string, handler EventHook) (string, error)
 }
 
+// EventHook is a custom handler attached to the tool with OnEvent.
+type EventHook = events.HookFunc
+
+// AllEvents is the event type to attach a hook to every trace event with.
+const AllEvents = events.AllEvents
+
 // Config is an alias for the config package interface.
 type Config = config.Config
 
@@ -49,20 +68,62 @@ type HermesController interface {
 	Start(ctx context.Context) error
 	Stop() error
 	RegisterEventCallback(callback func(ctx context.Context, event interface{}) error)
+	SetMaxPeers(maxPeers int)
 	GetNode() interface{}
+	GetSlotClock() *peer.SlotClock
+	GetTopicExpectations() *peer.TopicExpectations
+	GetPeerOrigins() map[string]string
+	GetBootstrapNodes() []peer.BootstrapNode
+	GetListenAddr() *libp2ppeer.AddrInfo
 }
 
 // Report represents the main report structure.
 type Report struct {
-	Config               Config                    `json:"config"`
-	ValidationMode       string                    `json:"validation_mode"`
-	Timestamp            time.Time                 `json:"timestamp"`
-	StartTime            time.Time                 `json:"start_time"`
-	EndTime              time.Time                 `json:"end_time"`
-	Duration             time.Duration             `json:"duration"`
-	TotalConnections     int                       `json:"total_connections"`
-	SuccessfulHandshakes int                       `json:"successful_handshakes"`
-	FailedHandshakes     int                       `json:"failed_handshakes"`
-	Peers                map[string]interface{}    `json:"peers"`
-	PeerEventCounts      map[string]map[string]int `json:"peer_event_counts"`
+	Config               Config                         `json:"config"`
+	ValidationMode       string                         `json:"validation_mode"`
+	AgentVersion         string                         `json:"agent_version"`
+	MeshDegree           config.MeshDegree              `json:"mesh_degree"`
+	Timestamp            time.Time                      `json:"timestamp"`
+	StartTime            time.Time                      `json:"start_time"`
+	EndTime              time.Time                      `json:"end_time"`
+	Duration             time.Duration                  `json:"duration"`
+	TotalConnections     int                            `json:"total_connections"`
+	SuccessfulHandshakes int                            `json:"successful_handshakes"`
+	FailedHandshakes     int                            `json:"failed_handshakes"`
+	ReconciledHandshakes *peer.ReconciledHandshakeStats `json:"reconciled_handshakes,omitempty"`
+	DataQuality          *peer.DataQualityStats         `json:"data_quality,omitempty"`
+	Reachability         *reachability.Result           `json:"reachability,omitempty"`
+	Subscriptions        *peer.SubscriptionReport       `json:"subscriptions,omitempty"`
+	BeaconPeers          *beaconpeers.Result            `json:"beacon_peers,omitempty"`
+	BeaconFetches        *beaconfetch.Summary           `json:"beacon_fetches,omitempty"`
+	BeaconSync           *beaconsync.Summary            `json:"beacon_sync,omitempty"`
+	Canary               *canary.Result                 `json:"canary,omitempty"`
+	ScoreConsensus       *peer.ConsensusView            `json:"score_consensus,omitempty"`
+	ClockSkew            *clockskew.Result              `json:"clock_skew,omitempty"`
+	Sampling             *peer.SamplingSummary          `json:"sampling,omitempty"`
+	EventSampling        *peer.EventSampling            `json:"event_sampling,omitempty"`
+	PeerPressure         *peer.PeerPressure             `json:"peer_pressure,omitempty"`
+	Resources            *resources.Summary             `json:"resources,omitempty"`
+	Shutdown             *peer.ShutdownTeardown         `json:"shutdown,omitempty"`
+	SessionSurvival      *peer.SessionSurvival          `json:"session_survival,omitempty"`
+	TopicHealth          *peer.TopicHealthSummary       `json:"topic_health,omitempty"`
+	InvalidDeliveries    *peer.InvalidDeliveries        `json:"invalid_deliveries,omitempty"`
+	RouterMetrics        *peer.RouterMetrics            `json:"router_metrics,omitempty"`
+	StatusTracking       *peer.StatusTracking           `json:"status_tracking,omitempty"`
+	HeadDivergence       *peer.HeadDivergence           `json:"head_divergence,omitempty"`
+	Peers                map[string]interface{}         `json:"peers"`
+	PeerEventCounts      map[string]map[string]int      `json:"peer_event_counts"`
+	EventTimeline        *peer.EventTimeline            `json:"event_timeline,omitempty"`
+	Phases               *peer.RunPhases                `json:"phases,omitempty"`
+	Gaps                 []peer.RunGap                  `json:"gaps,omitempty"`
+	Starvation           []watchdog.Window              `json:"starvation,omitempty"`
+	TimeSlices           []peer.TimeSlice               `json:"time_slices,omitempty"`
+	Epochs               []peer.EpochSlice              `json:"epochs,omitempty"`
+	TopicWhitelist       *peer.TopicWhitelist           `json:"topic_whitelist,omitempty"`
+	PeerOverlap          *peer.PeerOverlap              `json:"peer_overlap,omitempty"`
+	NegotiationFailures  *peer.NegotiationFailures      `json:"negotiation_failures,omitempty"`
+	BootstrapNodes       []peer.BootstrapNodeStatus     `json:"bootstrap_nodes,omitempty"`
+	MaxPeersRamp         *peer.MaxPeersRamp             `json:"max_peers_ramp,omitempty"`
+	Hosts                []peer.HostSummary             `json:"hosts,omitempty"`
+	Recommendations      *recommend.Profile             `json:"recommendations,omitempty"` // Configuration suggested for the next run
 }
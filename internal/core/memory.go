@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+// startMemoryWatchdog checks the process' resident memory against --spill-rss-mb, when set,
+// until the returned function is called. Above the threshold, the oldest completed sessions'
+// detailed events are spilled to disk, so a busy run is not killed for running out of memory.
+func (t *DefaultTool) startMemoryWatchdog(ctx context.Context) func() {
+	if t.spill == nil {
+		return func() {}
+	}
+
+	threshold := uint64(t.config.GetSpillRSSMB()) << 20 //nolint:gosec // validated to be positive.
+
+	watchdogCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(constants.MemoryWatchdogInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watchdogCtx.Done():
+				return
+			case <-ticker.C:
+				t.checkMemory(threshold)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// checkMemory spills the older half of the sessions that ended outside the late event grace
+// window when resident memory exceeds threshold bytes.
+func (t *DefaultTool) checkMemory(threshold uint64) {
+	rss := residentMemory()
+	if rss <= threshold {
+		return
+	}
+
+	// Sessions still within the grace window may be assigned late events
+	spilled, err := t.spill(t.clock().Add(-t.config.GetLateEventGrace()))
+	if err != nil {
+		t.logger.WithError(err).Warn("Failed to spill session events to disk")
+		t.errBudget.Record(reports.ErrorCategorySpill)
+	}
+
+	if spilled == 0 {
+		t.logger.WithField("rss_mb", rss>>20).Debug("Resident memory above the spill threshold, but no completed sessions are left to spill")
+
+		return
+	}
+
+	// Hand the spilled events' memory back to the OS, resident memory would not drop otherwise
+	debug.FreeOSMemory()
+
+	t.logger.WithFields(logrus.Fields{
+		"sessions":      spilled,
+		"rss_mb":        rss >> 20,
+		"rss_after_mb":  residentMemory() >> 20,
+		"spilled_total": t.spiller.Stats().Sessions,
+		"threshold_mb":  threshold >> 20,
+	}).Info("Spilled completed sessions' events to disk under memory pressure")
+}
+
+// residentMemory returns the process' resident set size in bytes. Outside Linux it falls back
+// to the memory the Go runtime holds from the OS, which leaves out memory held by cgo.
+func residentMemory() uint64 {
+	if rss, err := readStatm(); err == nil {
+		return rss
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return stats.Sys - stats.HeapReleased
+}
+
+// readStatm reads the resident set size from /proc/self/statm.
+func readStatm() (uint64, error) {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected statm format %q", data)
+	}
+
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resident page count: %w", err)
+	}
+
+	return pages * uint64(os.Getpagesize()), nil //nolint:gosec // page sizes are positive.
+}
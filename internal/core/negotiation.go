@@ -0,0 +1,234 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// Hermes traces connections only once they are established, so connections that fail during
+// negotiation are visible in the libp2p and Hermes logs alone.
+const (
+	upgraderSubsystem = "upgrader"                  // go-log subsystem of the libp2p connection upgrader
+	hermesDialFailure = "Failed to connect to peer" // Debug message of Hermes's peer dialer
+)
+
+// acceptUpgradePattern splits the upgrader's inbound failure message into the error and the
+// local and remote addresses.
+var acceptUpgradePattern = regexp.MustCompile(`^accept upgrade error: (.*) \((\S+) <--> (\S+)\)$`)
+
+// negotiationTap feeds the connection negotiation failures logged by libp2p and Hermes to a
+// recorder. The logs are process wide, so with several hosts it sees the failures of all of them.
+type negotiationTap struct {
+	pipe          *logging.PipeReader
+	upgraderLevel zapcore.Level
+	previousSlog  *slog.Logger
+	done          chan struct{}
+}
+
+// startNegotiationTap starts recording negotiation failures: inbound ones from the upgrader's
+// debug log and outbound ones from Hermes's dialer.
+func startNegotiationTap(recorder *peer.NegotiationRecorder, logger logrus.FieldLogger) *negotiationTap {
+	tap := &negotiationTap{previousSlog: slog.Default()}
+
+	// Route Hermes's slog records through the recorder. SetDefault redirects the log package
+	// to the new handler as well, which would loop back, so its output is put back.
+	output, flags := log.Writer(), log.Flags()
+	slog.SetDefault(slog.New(&dialFailureHandler{recorder: recorder, next: tap.previousSlog.Handler()}))
+	log.SetOutput(output)
+	log.SetFlags(flags)
+
+	// The upgrader only logs failed upgrades at debug level. go-log cannot hand out its output
+	// core for wrapping, so it is rebuilt, which is only possible for standard output streams.
+	cfg := logging.GetConfig()
+	if cfg.File != "" || cfg.URL != "" {
+		logger.Warn("go-log writes to a file or URL, inbound negotiation failures are not recorded")
+
+		return tap
+	}
+
+	tap.upgraderLevel = zapcore.Level(cfg.Level)
+	if level, ok := cfg.SubsystemLevels[upgraderSubsystem]; ok {
+		tap.upgraderLevel = zapcore.Level(level)
+	}
+
+	// Keep the upgrader's debug messages out of the console, only the tap reads them
+	logging.SetPrimaryCore(&subsystemFilter{Core: consoleCore(cfg), name: upgraderSubsystem, level: tap.upgraderLevel})
+
+	tap.pipe = logging.NewPipeReader(logging.PipeFormat(logging.JSONOutput))
+	tap.done = make(chan struct{})
+
+	if err := logging.SetLogLevel(upgraderSubsystem, zapcore.DebugLevel.String()); err != nil {
+		logger.WithError(err).Warn("Failed to raise the libp2p upgrader log level, inbound negotiation failures are not recorded")
+	}
+
+	go tap.readUpgrader(recorder)
+
+	return tap
+}
+
+// readUpgrader records the inbound upgrade failures in the go-log pipe until it is closed.
+func (t *negotiationTap) readUpgrader(recorder *peer.NegotiationRecorder) {
+	defer close(t.done)
+
+	decoder := json.NewDecoder(t.pipe)
+
+	for {
+		var entry struct {
+			Logger string `json:"logger"`
+			Msg    string `json:"msg"`
+		}
+
+		if err := decoder.Decode(&entry); err != nil {
+			return
+		}
+
+		if entry.Logger != upgraderSubsystem {
+			continue
+		}
+
+		if match := acceptUpgradePattern.FindStringSubmatch(entry.Msg); match != nil {
+			recorder.Record(peer.NegotiationInbound, remoteIP(match[3]), match[1])
+		}
+	}
+}
+
+// Stop stops recording and restores the log configuration.
+func (t *negotiationTap) Stop() {
+	slog.SetDefault(t.previousSlog)
+
+	if t.pipe == nil {
+		return
+	}
+
+	_ = logging.SetLogLevel(upgraderSubsystem, t.upgraderLevel.String())
+	_ = t.pipe.Close()
+
+	<-t.done
+}
+
+// remoteIP returns the IP part of a multiaddr such as /ip4/1.2.3.4/tcp/9000, as inbound
+// connections come from a new port each time.
+func remoteIP(addr string) string {
+	parts := strings.SplitN(strings.TrimPrefix(addr, "/"), "/", 3)
+	if len(parts) < 2 {
+		return addr
+	}
+
+	return "/" + parts[0] + "/" + parts[1]
+}
+
+// dialFailureHandler records the dial failures Hermes logs and passes every record on.
+type dialFailureHandler struct {
+	recorder *peer.NegotiationRecorder
+	next     slog.Handler
+}
+
+// Enabled accepts every level, Hermes logs dial failures at debug level.
+func (h *dialFailureHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle records dial failures and passes the record on when the next handler takes its level.
+func (h *dialFailureHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Message == hermesDialFailure {
+		var peerID, message string
+
+		record.Attrs(func(attr slog.Attr) bool {
+			switch attr.Key {
+			case "peer":
+				peerID = attr.Value.String()
+			case "error":
+				message = attr.Value.String()
+			}
+
+			return true
+		})
+
+		h.recorder.Record(peer.NegotiationOutbound, peerID, message)
+	}
+
+	if !h.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler adding attrs to the records passed on.
+func (h *dialFailureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &dialFailureHandler{recorder: h.recorder, next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler grouping the attributes of the records passed on.
+func (h *dialFailureHandler) WithGroup(name string) slog.Handler {
+	return &dialFailureHandler{recorder: h.recorder, next: h.next.WithGroup(name)}
+}
+
+// subsystemFilter drops the entries of one go-log subsystem below a level.
+type subsystemFilter struct {
+	zapcore.Core
+	name  string
+	level zapcore.Level
+}
+
+// With adds fields, keeping the filter.
+func (f *subsystemFilter) With(fields []zapcore.Field) zapcore.Core {
+	return &subsystemFilter{Core: f.Core.With(fields), name: f.name, level: f.level}
+}
+
+// Check drops the subsystem's entries below the level.
+func (f *subsystemFilter) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.LoggerName == f.name && entry.Level < f.level {
+		return checked
+	}
+
+	return f.Core.Check(entry, checked)
+}
+
+// consoleCore builds the output core go-log builds from its configuration.
+func consoleCore(cfg logging.Config) zapcore.Core {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	var encoder zapcore.Encoder
+
+	switch cfg.Format {
+	case logging.JSONOutput:
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case logging.PlaintextOutput:
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+
+	outputs := make([]zapcore.WriteSyncer, 0, 2)
+	if cfg.Stderr {
+		outputs = append(outputs, zapcore.Lock(os.Stderr))
+	}
+
+	if cfg.Stdout {
+		outputs = append(outputs, zapcore.Lock(os.Stdout))
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(outputs...), zapcore.DebugLevel)
+
+	for key, value := range cfg.Labels {
+		core = core.With([]zapcore.Field{zap.String(key, value)})
+	}
+
+	return core
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestAcceptUpgradePattern(t *testing.T) {
+	message := "accept upgrade error: failed to negotiate security protocol: protocols not supported: [/noise] " +
+		"(/ip4/10.0.0.1/tcp/9000 <--> /ip4/1.2.3.4/tcp/53211)"
+
+	match := acceptUpgradePattern.FindStringSubmatch(message)
+	if match == nil {
+		t.Fatal("Expected the upgrade error to match")
+	}
+
+	if match[1] != "failed to negotiate security protocol: protocols not supported: [/noise]" {
+		t.Errorf("Unexpected error %q", match[1])
+	}
+
+	if remote := remoteIP(match[3]); remote != "/ip4/1.2.3.4" {
+		t.Errorf("Expected the remote IP, got %q", remote)
+	}
+}
+
+// TestNegotiationUpstreamMessages pins the log messages and error texts the negotiation tap
+// reads to the sources of the libp2p and Hermes versions in go.mod, so a bump that changes
+// them fails here instead of silently zeroing the counts.
+func TestNegotiationUpstreamMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		module  string
+		file    string
+		snippet string
+	}{
+		{
+			name:    "upgrader subsystem",
+			module:  "github.com/libp2p/go-libp2p",
+			file:    "p2p/net/upgrader/listener.go",
+			snippet: fmt.Sprintf("logging.Logger(%q)", upgraderSubsystem),
+		},
+		{
+			name:    "inbound upgrade failure",
+			module:  "github.com/libp2p/go-libp2p",
+			file:    "p2p/net/upgrader/listener.go",
+			snippet: `log.Debugf("accept upgrade error: %s (%s <--> %s)",`,
+		},
+		{
+			name:    "security negotiation failure",
+			module:  "github.com/libp2p/go-libp2p",
+			file:    "p2p/net/upgrader/upgrader.go",
+			snippet: `"failed to negotiate security protocol: %w"`,
+		},
+		{
+			name:    "muxer negotiation failure",
+			module:  "github.com/libp2p/go-libp2p",
+			file:    "p2p/net/upgrader/upgrader.go",
+			snippet: `"failed to negotiate stream multiplexer: %w"`,
+		},
+		{
+			name:    "unsupported protocols",
+			module:  "github.com/multiformats/go-multistream",
+			file:    "client.go",
+			snippet: `"protocols not supported: %v"`,
+		},
+		{
+			name:    "outbound dial failure",
+			module:  "github.com/probe-lab/hermes",
+			file:    "eth/peer_dialer.go",
+			snippet: fmt.Sprintf(`slog.Debug(%q, "peer", addrInfo.ID, "error", err)`, hermesDialFailure),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := os.ReadFile(filepath.Join(moduleDir(t, tt.module), tt.file))
+			if err != nil {
+				t.Fatalf("Expected to read %s of %s, got %v", tt.file, tt.module, err)
+			}
+
+			if !strings.Contains(string(source), tt.snippet) {
+				t.Errorf("Expected %s of %s to contain %s", tt.file, tt.module, tt.snippet)
+			}
+		})
+	}
+
+	// The pattern must take apart what the upgrader's format produces
+	message := fmt.Sprintf("accept upgrade error: %s (%s <--> %s)", "failed to negotiate security protocol: EOF", "/ip4/10.0.0.1/tcp/9000", "/ip4/1.2.3.4/tcp/53211")
+	if match := acceptUpgradePattern.FindStringSubmatch(message); match == nil || match[1] != "failed to negotiate security protocol: EOF" || match[3] != "/ip4/1.2.3.4/tcp/53211" {
+		t.Errorf("Expected the upgrader's message taken apart, got %q", match)
+	}
+}
+
+// moduleDir returns the source directory of a module in the build, replacements applied.
+func moduleDir(t *testing.T, module string) string {
+	t.Helper()
+
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", module).Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		t.Skip("go is not installed")
+	}
+
+	if err != nil {
+		t.Fatalf("Expected go list to find %s, got %v", module, err)
+	}
+
+	dir := strings.TrimSpace(string(out))
+	if dir == "" {
+		t.Fatalf("Expected %s in the module cache, run go mod download", module)
+	}
+
+	return dir
+}
+
+func TestDialFailureHandler(t *testing.T) {
+	var out bytes.Buffer
+
+	recorder := peer.NewNegotiationRecorder()
+	next := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(&dialFailureHandler{recorder: recorder, next: next})
+
+	logger.Debug(hermesDialFailure, "peer", "16Uiu2HAm", "error", "failed to dial: connection refused")
+	logger.Info("Connected")
+
+	snapshot := recorder.Snapshot()
+	if snapshot == nil || snapshot.Outbound == nil || snapshot.Outbound.ByStage[peer.NegotiationTransport] != 1 {
+		t.Fatalf("Expected one outbound transport failure, got %+v", snapshot)
+	}
+
+	if snapshot.Outbound.TopRemotes[0].Remote != "16Uiu2HAm" {
+		t.Errorf("Expected the dialed peer as remote, got %+v", snapshot.Outbound.TopRemotes)
+	}
+
+	if bytes.Contains(out.Bytes(), []byte(hermesDialFailure)) || !bytes.Contains(out.Bytes(), []byte("Connected")) {
+		t.Errorf("Expected only records at the next handler's level to pass, got %q", out.String())
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+// rampStepAt returns the MaxPeers ramp step, from 0, the run is in at a time. Steps are
+// counted from the start of the run's phases, before they are set the run is in the first.
+func (t *DefaultTool) rampStepAt(at time.Time) int {
+	ramp := t.config.GetMaxPeersRamp()
+	if t.phases == nil || !at.After(t.phases.WarmupStart) {
+		return 0
+	}
+
+	return min(int(at.Sub(t.phases.WarmupStart)/t.config.GetMaxPeersRampStep()), len(ramp)-1)
+}
+
+// startMaxPeersRamp restarts the primary Hermes node with the next MaxPeers at each ramp step
+// until the last step, the end of the run, or until the returned function is called. A failed
+// restart ends the ramp.
+func (t *DefaultTool) startMaxPeersRamp(ctx context.Context) func() {
+	ramp := t.config.GetMaxPeersRamp()
+	if len(ramp) == 0 {
+		return func() {}
+	}
+
+	rampCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		step := t.config.GetMaxPeersRampStep()
+
+		for i := t.rampStepAt(t.clock()) + 1; i < len(ramp); i++ {
+			at := t.phases.WarmupStart.Add(time.Duration(i) * step)
+			if !at.Before(t.phases.CooldownEnd) {
+				return
+			}
+
+			select {
+			case <-rampCtx.Done():
+				return
+			case <-time.After(time.Until(at)):
+			}
+
+			if !t.restartIntoRampStep(rampCtx, i+1, ramp[i]) {
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// restartIntoRampStep restarts the primary Hermes node with a step's MaxPeers and records the
+// restart, returning whether it succeeded.
+func (t *DefaultTool) restartIntoRampStep(ctx context.Context, step, maxPeers int) bool {
+	logger := t.logger.WithFields(logrus.Fields{
+		"ramp_step": step,
+		"max_peers": maxPeers,
+	})
+
+	logger.Info("Restarting Hermes into the next MaxPeers ramp step")
+
+	restart := peer.RampRestart{Step: step, MaxPeers: maxPeers, Start: t.clock()}
+
+	t.hermesCtrl.SetMaxPeers(maxPeers)
+
+	err := t.restartHermes(ctx)
+	restart.End = t.clock()
+
+	if err != nil {
+		logger.WithError(err).Error("Failed to restart Hermes into the MaxPeers ramp step, the ramp ends here")
+		t.errBudget.Record(reports.ErrorCategoryHermes)
+		restart.Error = err.Error()
+	}
+
+	t.rampMu.Lock()
+	t.rampRestarts = append(t.rampRestarts, restart)
+	t.rampMu.Unlock()
+
+	return err == nil
+}
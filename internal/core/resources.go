@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/resources"
+)
+
+// runResourceSamples samples the resources the libp2p resource manager refused until the run
+// ends, so sessions ending without a goodbye can be matched to the intervals with refusals.
+func (t *DefaultTool) runResourceSamples(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.resources.Sample(t.clock())
+		}
+	}
+}
+
+// resourceSummary returns the resource manager's limits and refusals, and tags the sessions
+// that ended while it was refusing resources. Nil when the run was not monitored.
+func (t *DefaultTool) resourceSummary(peers map[string]*peer.Stats, endTime time.Time) *resources.Summary {
+	if t.resources == nil {
+		return nil
+	}
+
+	summary := t.resources.Summary(endTime)
+	resources.AttributeSessions(peers, summary)
+
+	if summary.TotalBlocked > 0 {
+		t.logger.WithFields(logrus.Fields{
+			"blocked":          summary.TotalBlocked,
+			"limited_sessions": summary.LimitedSessions,
+		}).Warn("The libp2p resource manager hit its limits, some disconnects may be ours rather than the peers'")
+	}
+
+	return summary
+}
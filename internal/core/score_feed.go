@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+	"github.com/ethpandaops/hermes-peer-score/internal/scorefeed"
+)
+
+// shareScores publishes our peers' composite scores to the score feed, when configured, and
+// compares them with the scores other instances published, setting each shared peer's
+// consensus score. A feed that cannot be reached leaves the view with its error rather than
+// failing the run.
+func (t *DefaultTool) shareScores(ctx context.Context, peers map[string]*peer.Stats, now time.Time) *peer.ConsensusView {
+	feedURL := t.config.GetScoreFeedURL()
+	if feedURL == "" {
+		return nil
+	}
+
+	instance := t.scoreFeedInstance()
+	network := t.config.GetNetwork()
+
+	feed, err := scorefeed.New(feedURL, constants.DefaultPublishTimeout, t.logger)
+	if err != nil {
+		return t.scoreFeedFailed(err)
+	}
+
+	if err := feed.Publish(ctx, scorefeed.BuildSnapshot(instance, network, now, peers)); err != nil {
+		return t.scoreFeedFailed(err)
+	}
+
+	snapshots, err := feed.Fetch(ctx)
+	if err != nil {
+		return t.scoreFeedFailed(err)
+	}
+
+	others, instances := scorefeed.OtherScores(snapshots, instance, network, now.Add(-constants.ScoreFeedMaxAge))
+	view := peer.ApplyConsensus(peers, others, instances, constants.ConsensusPeerLimit)
+
+	t.logger.WithFields(logrus.Fields{
+		"instances":     len(instances),
+		"shared_peers":  view.SharedPeers,
+		"hostile_to_us": view.Verdicts[peer.ConsensusHostileToUs],
+		"generally_bad": view.Verdicts[peer.ConsensusGenerallyBad],
+	}).Info("Compared peer scores with other vantage points")
+
+	return view
+}
+
+// scoreFeedFailed records a score feed error, which the consensus view shows.
+func (t *DefaultTool) scoreFeedFailed(err error) *peer.ConsensusView {
+	t.logger.WithError(err).Warn("Failed to share peer scores through the score feed")
+	t.errBudget.Record(reports.ErrorCategoryScoreFeed)
+
+	return &peer.ConsensusView{Error: err.Error()}
+}
+
+// scoreFeedInstance returns the name our scores are published under, the host name unless configured.
+func (t *DefaultTool) scoreFeedInstance() string {
+	if instance := t.config.GetScoreFeedInstance(); instance != "" {
+		return instance
+	}
+
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+
+	return t.config.GetAgentVersion()
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+)
+
+// shutdownEvents connects a peer at the start of the run and tears it down during shutdown.
+const shutdownEvents = `{"Type":"CONNECTED","Timestamp":"2025-06-01T12:00:01Z","Data":{"RemotePeer":"16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6","RemoteMaddrs":"/ip4/203.0.113.10/tcp/9000","AgentVersion":"Lighthouse/v7.0.1-e42406d/x86_64-linux","Direction":"Outbound","Opened":"2025-06-01T12:00:01Z","Limited":false}}
+{"Type":"HANDLE_GOODBYE","Timestamp":"SHUTDOWN_GOODBYE","Data":{"PeerID":"16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6","Code":1,"Reason":"client shutdown"}}
+{"Type":"DISCONNECTED","Timestamp":"SHUTDOWN_DISCONNECT","Data":{"RemotePeer":"16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6","RemoteMaddrs":"/ip4/203.0.113.10/tcp/9000","AgentVersion":"Lighthouse/v7.0.1-e42406d/x86_64-linux","Direction":"Outbound","Opened":"2025-06-01T12:00:01Z","Limited":false}}
+`
+
+// TestGenerateReportShutdown checks the teardown during the shutdown phase is left out of the
+// statistics GenerateReport takes over the run, the time slices and epochs included.
+func TestGenerateReportShutdown(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "")
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tool, _, end := newReplayTool(t, []*host.TraceEvent{{Timestamp: start}})
+
+	end()
+
+	shutdownStart := tool.phases.CooldownEnd
+	goodbyeAt, disconnectAt := shutdownStart.Add(time.Second), shutdownStart.Add(2*time.Second)
+
+	replaced := strings.NewReplacer(
+		"SHUTDOWN_GOODBYE", goodbyeAt.Format(time.RFC3339),
+		"SHUTDOWN_DISCONNECT", disconnectAt.Format(time.RFC3339),
+	).Replace(shutdownEvents)
+
+	path := filepath.Join(t.TempDir(), goldenEvents)
+	if err := os.WriteFile(path, []byte(replaced), 0o644); err != nil {
+		t.Fatalf("Expected no error writing events, got %v", err)
+	}
+
+	events, err := loadEvents(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading events, got %v", err)
+	}
+
+	// The peer connects during the run, and says goodbye and disconnects during shutdown
+	if err := tool.handleEvent(context.Background(), events[0]); err != nil {
+		t.Fatalf("Expected no error handling %s, got %v", events[0].Type, err)
+	}
+
+	tool.shutdownStart = shutdownStart
+
+	for _, event := range events[1:] {
+		if err := tool.handleEvent(context.Background(), event); err != nil {
+			t.Fatalf("Expected no error handling %s, got %v", event.Type, err)
+		}
+	}
+
+	tool.shutdownEnd = shutdownStart.Add(5 * time.Second)
+	tool.shutdownCompleted = true
+	tool.clock = func() time.Time { return tool.shutdownEnd }
+
+	report, err := tool.GenerateReport(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error generating the report, got %v", err)
+	}
+
+	if report.Shutdown == nil || report.Shutdown.Goodbye != 1 {
+		t.Fatalf("Expected the peer's shutdown goodbye classified, got %+v", report.Shutdown)
+	}
+
+	if len(report.TimeSlices) == 0 {
+		t.Fatal("Expected time slices over the run")
+	}
+
+	for _, slice := range report.TimeSlices {
+		if slice.Disconnects != 0 || slice.Goodbyes != 0 {
+			t.Errorf("Expected the shutdown teardown left out of the slice from %s, got %d disconnects and %d goodbyes",
+				slice.Start, slice.Disconnects, slice.Goodbyes)
+		}
+	}
+
+	for _, epoch := range report.Epochs {
+		if epoch.Disconnects != 0 || epoch.Goodbyes != 0 {
+			t.Errorf("Expected the shutdown teardown left out of epoch %d, got %d disconnects and %d goodbyes",
+				epoch.Epoch, epoch.Disconnects, epoch.Goodbyes)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/progress"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+// saveSmokeReports writes the lite report and markdown summary of a smoke test and checks that
+// the run saw a peer and an event and that its report was written. A smoke test is too short
+// to say anything about scoring, so it neither publishes nor compares against a baseline.
+func (t *DefaultTool) saveSmokeReports(report *Report, reportsReport *reports.Report) error {
+	liteFile, err := t.reportGen.GenerateLiteJSON(reportsReport)
+	if err != nil {
+		return fmt.Errorf("failed to save lite JSON report: %w", err)
+	}
+
+	markdownFile, err := t.reportGen.GenerateMarkdownSummary(reportsReport)
+	if err != nil {
+		return fmt.Errorf("failed to save markdown summary: %w", err)
+	}
+
+	t.progress.Emit(progress.Event{Event: progress.EventReport, Stage: "json"})
+
+	t.logger.WithFields(logrus.Fields{
+		"lite_file":     liteFile,
+		"markdown_file": markdownFile,
+	}).Info("Smoke test reports saved")
+
+	t.removeCheckpoint(report)
+
+	if err := checkSmoke(report, liteFile); err != nil {
+		return err
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"peers":  len(report.Peers),
+		"events": smokeEvents(report),
+	}).Info("Smoke test passed")
+
+	return nil
+}
+
+// checkSmoke checks the relaxed assertions of a smoke test: at least one peer, at least one
+// event and a non-empty report on disk.
+func checkSmoke(report *Report, reportFile string) error {
+	problems := make([]string, 0)
+
+	if len(report.Peers) == 0 {
+		problems = append(problems, "no peers were seen")
+	}
+
+	if smokeEvents(report) == 0 {
+		problems = append(problems, "no events were received")
+	}
+
+	if info, err := os.Stat(reportFile); err != nil || info.Size() == 0 {
+		problems = append(problems, fmt.Sprintf("report %s was not written", reportFile))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("smoke test failed: %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// smokeEvents counts the events the run received, from the data quality checks or, without
+// them, the per-peer event counts.
+func smokeEvents(report *Report) int {
+	if report.DataQuality != nil && report.DataQuality.EventsChecked > 0 {
+		return report.DataQuality.EventsChecked
+	}
+
+	events := 0
+
+	for _, counts := range report.PeerEventCounts {
+		for _, count := range counts {
+			events += count
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestCheckSmoke(t *testing.T) {
+	reportFile := filepath.Join(t.TempDir(), "report.json")
+	_ = os.WriteFile(reportFile, []byte(`{"peers":{}}`), 0o600)
+
+	report := &Report{
+		Peers:           map[string]interface{}{"16Uiu2HAmPeer": map[string]interface{}{}},
+		PeerEventCounts: map[string]map[string]int{"16Uiu2HAmPeer": {"CONNECTED": 1}},
+	}
+
+	if err := checkSmoke(report, reportFile); err != nil {
+		t.Errorf("Expected the smoke test to pass, got %v", err)
+	}
+
+	report.DataQuality = &peer.DataQualityStats{EventsChecked: 5}
+	if events := smokeEvents(report); events != 5 {
+		t.Errorf("Expected the checked events counted, got %d", events)
+	}
+
+	err := checkSmoke(&Report{}, filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil {
+		t.Fatal("Expected an empty run to fail the smoke test")
+	}
+
+	for _, problem := range []string{"no peers", "no events", "not written"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected %q in %v", problem, err)
+		}
+	}
+}
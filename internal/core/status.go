@@ -0,0 +1,41 @@
+package core
+
+import (
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
+)
+
+// statusBackoff decides which periodic status reports are logged. A report whose peer counts
+// changed is always logged. While they stay the same, the reports logged grow further apart,
+// doubling up to maxWait intervals, so a settled run does not repeat itself every interval.
+type statusBackoff struct {
+	maxWait int
+	wait    int
+	skipped int
+	last    *watchdog.Diagnostics
+}
+
+// newStatusBackoff creates a backoff holding unchanged reports back for up to maxWait intervals.
+func newStatusBackoff(maxWait int) *statusBackoff {
+	return &statusBackoff{maxWait: max(maxWait, 1), wait: 1}
+}
+
+// due reports whether the status report with the given counts is logged.
+func (b *statusBackoff) due(diagnostics watchdog.Diagnostics) bool {
+	if b.last == nil || *b.last != diagnostics {
+		b.last = &diagnostics
+		b.wait = 1
+		b.skipped = 0
+
+		return true
+	}
+
+	b.skipped++
+	if b.skipped < b.wait {
+		return false
+	}
+
+	b.skipped = 0
+	b.wait = min(b.wait*2, b.maxWait)
+
+	return true
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
+)
+
+func TestStatusBackoff(t *testing.T) {
+	backoff := newStatusBackoff(4)
+	settled := watchdog.Diagnostics{KnownPeers: 10, ConnectedPeers: 5}
+
+	// Unchanged reports are logged after 1, 2, 4, then every 4 intervals
+	var logged []int
+
+	for i := range 16 {
+		if backoff.due(settled) {
+			logged = append(logged, i)
+		}
+	}
+
+	want := []int{0, 1, 3, 7, 11, 15}
+	if len(logged) != len(want) {
+		t.Fatalf("Expected reports %v logged, got %v", want, logged)
+	}
+
+	for i := range want {
+		if logged[i] != want[i] {
+			t.Fatalf("Expected reports %v logged, got %v", want, logged)
+		}
+	}
+
+	// A change is logged at once and resets the backoff
+	changed := watchdog.Diagnostics{KnownPeers: 11, ConnectedPeers: 5}
+	if !backoff.due(changed) || !backoff.due(changed) || backoff.due(changed) {
+		t.Error("Expected a change logged at once and the backoff restarted")
+	}
+}
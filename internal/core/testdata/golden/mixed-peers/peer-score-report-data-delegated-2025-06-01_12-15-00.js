@@ -0,0 +1 @@
+window.reportData = {"metadata":{"agent_version":"hermes","format_version":"1.0","phases":{"warmup_start":"2025-06-01T12:00:00Z","measure_start":"2025-06-01T12:00:00Z","measure_end":"2025-06-01T12:15:00Z","cooldown_end":"2025-06-01T12:15:00Z","ended_in_phase":"complete"},"processed_at":"2025-06-01T12:15:00Z","timeline":{"bucket_seconds":60,"buckets":15,"burst_threshold":100,"out_of_range":0,"start":"2025-06-01T12:00:00Z"},"total_peers":3},"peerEventCounts":{"16Uiu2HAkzTqGJx4Nn6T2g2xMYzU9jVbh3Jf7cC1xPA6y4zhEyWq1":{"CONNECTED":4,"DISCONNECTED":2,"DUPLICATE_MESSAGE":1,"GRAFT":2,"HANDLE_GOODBYE":2,"PEERSCORE":4,"PRUNE":2,"REQUEST_STATUS":4},"16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6":{"CONNECTED":2,"DELIVER_MESSAGE":1,"GRAFT":2,"HANDLE_STATUS":1,"PEERSCORE":4,"REQUEST_STATUS":2},"16Uiu2HAmQn8vFdVFNcCW9LUnV8mK5pYtDFjgxQ8h8XcE2Ns6i6Ar":{"CONNECTED":2,"DISCONNECTED":2,"HANDLE_STATUS":3,"PEERSCORE":4,"REJECT_MESSAGE":1,"REQUEST_STATUS":2}},"peers":[{"attempts_to_identify":1,"client_agent":"Prysm/v6.0.3/9dfeb9a7e0c3b4ae0d6f8d0e5b1c0f7a2f1d8c4b","client_type":"prysm","connection_sessions":[{"connected_at":"2025-06-01T12:00:12Z","direction":"outbound","transport":"tcp","identified_at":"2025-06-01T12:00:12.5Z","disconnected_at":"2025-06-01T12:02:31Z","connected_slot":0,"connected_epoch":0,"message_count":4,"duration":139000000000,"disconnected":true,"connection_key":"opened:2025-06-01T12:00:12Z|/ip4/192.0.2.44/tcp/13000","peer_scores":[{"timestamp":"2025-06-01T12:00:30Z","slot":0,"epoch":0,"score":-4,"app_specific_score":0,"ip_colocation_factor":0,"behaviour_penalty":2,"topics":[{"topic":"/eth2/4a26c58b/beacon_block/ssz_snappy","time_in_mesh":16000000000,"first_message_deliveries":0,"mesh_message_deliveries":0,"invalid_message_deliveries":0}]}],"score_summary":{"snapshots":1,"scored_seconds":121,"time_weighted_mean":-4,"area_below_zero":-484,"seconds_below_publish":0},"goodbye_events":[{"timestamp":"2025-06-01T12:02:30Z","slot":0,"epoch":0,"code":129,"reason":"client shutdown"}],"mesh_events":[{"timestamp":"2025-06-01T12:00:14Z","slot":0,"epoch":0,"type":"GRAFT","direction":"","topic":"/eth2/4a26c58b/beacon_block/ssz_snappy","reason":""},{"timestamp":"2025-06-01T12:02:00Z","slot":0,"epoch":0,"type":"PRUNE","direction":"","topic":"/eth2/4a26c58b/beacon_block/ssz_snappy","reason":""}],"status_updates":[{"timestamp":"2025-06-01T12:00:12.5Z","head_slot":11800001,"finalized_epoch":368748,"attempt":1,"latency_ms":500}]},{"connected_at":"2025-06-01T12:03:00Z","direction":"outbound","transport":"tcp","identified_at":"2025-06-01T12:03:00.8Z","disconnected_at":null,"connected_slot":0,"connected_epoch":0,"message_count":1,"duration":null,"disconnected":false,"censored":true,"connection_key":"opened:2025-06-01T12:03:00Z|/ip4/192.0.2.44/tcp/13000","peer_scores":[{"timestamp":"2025-06-01T12:08:00Z","slot":0,"epoch":0,"score":2.75,"app_specific_score":0,"ip_colocation_factor":0,"behaviour_penalty":0,"topics":[]}],"score_summary":{"snapshots":1,"scored_seconds":420,"time_weighted_mean":2.75,"area_below_zero":0,"seconds_below_publish":0},"goodbye_events":[],"mesh_events":[],"status_updates":[{"timestamp":"2025-06-01T12:03:00.8Z","head_slot":11800015,"finalized_epoch":368749,"attempt":1,"latency_ms":800}]}],"decode_error_count":0,"event_buckets":{"CONNECTED":[1,0,0,1],"DISCONNECTED":[0,0,1],"DUPLICATE_MESSAGE":[1],"GRAFT":[1],"HANDLE_GOODBYE":[0,0,1],"PEERSCORE":[1,0,0,0,0,0,0,0,1],"PRUNE":[0,0,1],"REQUEST_STATUS":[1,0,0,1]},"event_count":21,"failed_handshakes":0,"failed_identify_attempts":0,"first_seen_at":"2025-06-01T12:00:00Z","goodbye_count":1,"has_scores":true,"identify_attempts":2,"last_seen_at":"2025-06-01T12:03:00Z","last_session_status":"Connected","max_peer_score":2.75,"mesh_count":2,"min_peer_score":-4,"origin":"discv5","peer_id":"16Uiu2HAkzTqGJx4Nn6T2g2xMYzU9jVbh3Jf7cC1xPA6y4zhEyWq1","reqresp_abuse_count":0,"score_area_below_zero":-484,"seconds_below_publish":0,"session_attribution":[{"direction":"outbound","disconnect_initiator":"remote","initiator_reason":"The peer said goodbye (129: client shutdown)","goodbye_severities":["info"]},{"direction":"outbound","goodbye_severities":[]}],"session_count":2,"short_peer_id":"16Uiu2HAkzTq","successful_handshakes":0,"time_weighted_score":1.2402957486136783,"total_connections":2,"total_message_count":0},{"attempts_to_identify":1,"client_agent":"Lighthouse/v7.0.1-e42406d/x86_64-linux","client_type":"lighthouse","connection_sessions":[{"connected_at":"2025-06-01T12:00:01Z","direction":"outbound","transport":"tcp","identified_at":"2025-06-01T12:00:01.4Z","disconnected_at":null,"connected_slot":0,"connected_epoch":0,"message_count":3,"duration":null,"disconnected":false,"censored":true,"connection_key":"opened:2025-06-01T12:00:01Z|/ip4/203.0.113.10/tcp/9000","peer_scores":[{"timestamp":"2025-06-01T12:00:30Z","slot":0,"epoch":0,"score":12.5,"app_specific_score":0,"ip_colocation_factor":0,"behaviour_penalty":0,"topics":[{"topic":"/eth2/4a26c58b/beacon_block/ssz_snappy","time_in_mesh":20000000000,"first_message_deliveries":3,"mesh_message_deliveries":2.5,"invalid_message_deliveries":0},{"topic":"/eth2/4a26c58b/beacon_aggregate_and_proof/ssz_snappy","time_in_mesh":0,"first_message_deliveries":1,"mesh_message_deliveries":0,"invalid_message_deliveries":0}]},{"timestamp":"2025-06-01T12:08:00Z","slot":0,"epoch":0,"score":18.25,"app_specific_score":0,"ip_colocation_factor":0,"behaviour_penalty":0,"topics":[{"topic":"/eth2/4a26c58b/beacon_block/ssz_snappy","time_in_mesh":470000000000,"first_message_deliveries":9,"mesh_message_deliveries":6,"invalid_message_deliveries":0},{"topic":"/eth2/4a26c58b/beacon_aggregate_and_proof/ssz_snappy","time_in_mesh":300000000000,"first_message_deliveries":4,"mesh_message_deliveries":1.5,"invalid_message_deliveries":0}]}],"score_summary":{"snapshots":2,"scored_seconds":870,"time_weighted_mean":15.275862068965518,"area_below_zero":0,"seconds_below_publish":0},"goodbye_events":[],"mesh_events":[{"timestamp":"2025-06-01T12:00:10Z","slot":0,"epoch":0,"type":"GRAFT","direction":"","topic":"/eth2/4a26c58b/beacon_block/ssz_snappy","reason":""}],"status_updates":[{"timestamp":"2025-06-01T12:00:01.4Z","head_slot":11800000,"finalized_epoch":368748,"attempt":1,"latency_ms":400},{"timestamp":"2025-06-01T12:12:00.5Z","inbound":true,"head_slot":11800060,"finalized_epoch":368750}]}],"decode_error_count":0,"event_buckets":{"CONNECTED":[1],"DELIVER_MESSAGE":[1],"GRAFT":[1],"HANDLE_STATUS":[0,0,0,0,0,0,0,0,0,0,0,0,1],"PEERSCORE":[1,0,0,0,0,0,0,0,1],"REQUEST_STATUS":[1]},"event_count":12,"failed_handshakes":0,"failed_identify_attempts":0,"first_seen_at":"2025-06-01T12:00:00Z","goodbye_count":0,"has_scores":true,"identify_attempts":1,"last_seen_at":"2025-06-01T12:00:01Z","last_session_status":"Connected","max_peer_score":18.25,"mesh_count":1,"min_peer_score":12.5,"origin":"discv5","peer_id":"16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6","reqresp_abuse_count":0,"score_area_below_zero":0,"seconds_below_publish":0,"session_attribution":[{"direction":"outbound","goodbye_severities":[]}],"session_count":1,"short_peer_id":"16Uiu2HAm7Ux","successful_handshakes":0,"time_weighted_score":15.275862068965518,"total_connections":1,"total_message_count":0},{"attempts_to_identify":1,"client_agent":"teku/v25.6.0/linux-x86_64/-eclipseadoptium-openjdk64bitservervm-java-21","client_type":"teku","connection_sessions":[{"connected_at":"2025-06-01T12:00:05Z","direction":"inbound","transport":"quic","muxer":"quic","security":"tls","identified_at":"2025-06-01T12:00:05.6Z","disconnected_at":"2025-06-01T12:14:00Z","connected_slot":0,"connected_epoch":0,"message_count":2,"duration":835000000000,"disconnected":true,"connection_key":"opened:2025-06-01T12:00:05Z|/ip4/198.51.100.7/udp/9001/quic-v1","peer_scores":[{"timestamp":"2025-06-01T12:00:30Z","slot":0,"epoch":0,"score":1.2,"app_specific_score":0,"ip_colocation_factor":0,"behaviour_penalty":0,"topics":[{"topic":"/eth2/4a26c58b/beacon_attestation_3/ssz_snappy","time_in_mesh":0,"first_message_deliveries":0.5,"mesh_message_deliveries":0,"invalid_message_deliveries":0}]},{"timestamp":"2025-06-01T12:08:00Z","slot":0,"epoch":0,"score":-0.5,"app_specific_score":0,"ip_colocation_factor":0,"behaviour_penalty":0,"topics":[{"topic":"/eth2/4a26c58b/beacon_attestation_3/ssz_snappy","time_in_mesh":0,"first_message_deliveries":0,"mesh_message_deliveries":0,"invalid_message_deliveries":1}]}],"score_summary":{"snapshots":2,"scored_seconds":810,"time_weighted_mean":0.4444444444444444,"area_below_zero":-180,"seconds_below_publish":0},"goodbye_events":[],"mesh_events":[],"status_updates":[{"timestamp":"2025-06-01T12:00:05.2Z","inbound":true,"head_slot":11799990,"finalized_epoch":368747},{"timestamp":"2025-06-01T12:00:05.6Z","head_slot":11799990,"finalized_epoch":368747,"attempt":1,"latency_ms":600},{"timestamp":"2025-06-01T12:05:00Z","inbound":true,"head_slot":11799990,"finalized_epoch":368747},{"timestamp":"2025-06-01T12:12:00Z","inbound":true,"head_slot":11799990,"finalized_epoch":368747}]}],"decode_error_count":1,"decode_errors":{"total":1,"by_kind":{"validation_failed":1},"by_topic":{"/eth2/4a26c58b/beacon_attestation_3/ssz_snappy":1},"last_reason":"validation failed","last_seen_at":"2025-06-01T12:01:00Z"},"event_buckets":{"CONNECTED":[1],"DISCONNECTED":[0,0,0,0,0,0,0,0,0,0,0,0,0,0,1],"HANDLE_STATUS":[1,0,0,0,0,1,0,0,0,0,0,0,1],"PEERSCORE":[1,0,0,0,0,0,0,0,1],"REJECT_MESSAGE":[0,1],"REQUEST_STATUS":[1]},"event_count":14,"failed_handshakes":0,"failed_identify_attempts":0,"first_seen_at":"2025-06-01T12:00:00Z","goodbye_count":0,"has_scores":true,"identify_attempts":1,"last_seen_at":"2025-06-01T12:00:05Z","last_session_status":"Disconnected","max_peer_score":1.2,"mesh_count":0,"min_peer_score":-0.5,"origin":"incoming","peer_id":"16Uiu2HAmQn8vFdVFNcCW9LUnV8mK5pYtDFjgxQ8h8XcE2Ns6i6Ar","reqresp_abuse_count":0,"score_area_below_zero":-180,"seconds_below_publish":0,"session_attribution":[{"direction":"inbound","goodbye_severities":[]}],"session_count":1,"short_peer_id":"16Uiu2HAmQn8","successful_handshakes":0,"time_weighted_score":0.4444444444444444,"total_connections":1,"total_message_count":0}],"summary":{"DataQuality":{"events_checked":27,"missing_timestamps":0,"out_of_order_events":0,"max_lag_seconds":0,"unhandled_events":0,"late_event_grace_seconds":10,"late_events_assigned":0,"late_events_dropped":0,"duplicate_connections":0},"EndTime":"2025-06-01T12:15:00Z","FailedHandshakes":0,"ReconciledHandshakes":{"retry_window_seconds":30,"episodes":4,"successful_episodes":4,"failed_episodes":0,"recovered_episodes":0,"success_rate":100},"StartTime":"2025-06-01T12:00:00Z","SuccessfulHandshakes":4,"TestDuration":900,"TotalConnections":4,"UniquePeers":3,"client_distribution":{"lighthouse":1,"prysm":1,"teku":1},"decode_error_offenders":[{"peer_id":"16Uiu2HAmQn8vFdVFNcCW9LUnV8mK5pYtDFjgxQ8h8XcE2Ns6i6Ar","client_type":"teku","total":1,"by_kind":{"validation_failed":1},"by_topic":{"/eth2/4a26c58b/beacon_attestation_3/ssz_snappy":1}}],"event_bursts":[],"goodbye_events_summary":{"total_events":1,"reason_stats":[{"reason":"client shutdown","count":1,"codes":[129],"examples":["client shutdown"]}],"unique_reasons":1,"top_reasons":["client shutdown"],"code_frequency":{"129":1}},"goodbye_reconnects":{"by_code":[{"code":129,"reason":"client shutdown","goodbyes":1,"reconnected":1,"median_reconnect_seconds":29,"compared":1,"longer_after":1}],"by_client":[{"client":"prysm","goodbyes":1,"reconnected":1,"median_reconnect_seconds":29,"compared":1,"longer_after":1}]},"gossip_leeches":[],"gossip_leeches_by_client":{},"gossip_threshold":-4000,"graylist_threshold":-16000,"headline":{"unique_peers":3,"total_connections":4,"successful_handshakes":4,"failed_handshakes":0,"handshake_success_rate":1,"sessions":4,"disconnects":2,"goodbye_events":1,"clients":[{"client":"lighthouse","peers":1,"sessions":1,"disconnects":0,"goodbye_events":0,"successful_handshakes":0,"failed_handshakes":0,"handshake_success_rate":0,"median_duration_seconds":0,"median_score":18.25,"scored_peers":1,"reqresp_abuse":0},{"client":"prysm","peers":1,"sessions":2,"disconnects":1,"goodbye_events":1,"successful_handshakes":0,"failed_handshakes":0,"handshake_success_rate":0,"median_duration_seconds":139,"median_score":2.75,"scored_peers":1,"reqresp_abuse":0},{"client":"teku","peers":1,"sessions":1,"disconnects":1,"goodbye_events":0,"successful_handshakes":0,"failed_handshakes":0,"handshake_success_rate":0,"median_duration_seconds":835,"median_score":-0.5,"scored_peers":1,"reqresp_abuse":0}],"disconnect_reasons":[{"side":"remote","code":129,"reason":"client shutdown","count":1}],"score_bands":{"peers":3,"snapshots":6,"min":{"p10":-4,"p50":-0.5,"p90":12.5},"mean":{"p10":-0.625,"p50":0.35,"p90":15.375},"bucket_seconds":60,"buckets":[{"start":"2025-06-01T12:00:00Z","peers":3,"min":{"p10":-4,"p50":1.2,"p90":12.5},"mean":{"p10":-4,"p50":1.2,"p90":12.5}},{"start":"2025-06-01T12:08:00Z","peers":3,"min":{"p10":-0.5,"p50":2.75,"p90":18.25},"mean":{"p10":-0.5,"p50":2.75,"p90":18.25}}],"below_gossip":0,"below_publish":0,"below_graylist":0},"worst_scored":[{"peer_id":"16Uiu2HAkzTqGJx4Nn6T2g2xMYzU9jVbh3Jf7cC1xPA6y4zhEyWq1","client_type":"prysm","time_weighted_mean":1.2402957486136783,"area_below_zero":-484,"seconds_below_publish":0},{"peer_id":"16Uiu2HAmQn8vFdVFNcCW9LUnV8mK5pYtDFjgxQ8h8XcE2Ns6i6Ar","client_type":"teku","time_weighted_mean":0.4444444444444444,"area_below_zero":-180,"seconds_below_publish":0}]},"peer_origins":[{"origin":"discv5","peers":2,"sessions":3,"disconnected":1,"short_lived":0,"with_goodbye":1,"median_duration_seconds":139},{"origin":"incoming","peers":1,"sessions":1,"disconnected":1,"short_lived":0,"with_goodbye":0,"median_duration_seconds":835}],"peer_summaries":[{"attempts_to_identify":1,"client_agent":"Prysm/v6.0.3/9dfeb9a7e0c3b4ae0d6f8d0e5b1c0f7a2f1d8c4b","client_type":"prysm","decode_error_count":0,"event_count":21,"failed_handshakes":0,"failed_identify_attempts":0,"first_seen_at":"2025-06-01T12:00:00Z","goodbye_count":1,"has_scores":true,"identify_attempts":2,"last_seen_at":"2025-06-01T12:03:00Z","last_session_status":"Connected","last_session_time":"2025-06-01T12:03:00Z","max_peer_score":2.75,"mesh_count":2,"min_peer_score":-4,"origin":"discv5","peer_id":"16Uiu2HAkzTqGJx4Nn6T2g2xMYzU9jVbh3Jf7cC1xPA6y4zhEyWq1","reqresp_abuse_count":0,"score_area_below_zero":-484,"seconds_below_publish":0,"session_count":2,"short_peer_id":"16Uiu2HAkzTq","successful_handshakes":0,"time_weighted_score":1.2402957486136783,"total_connections":2,"total_message_count":0},{"attempts_to_identify":1,"client_agent":"Lighthouse/v7.0.1-e42406d/x86_64-linux","client_type":"lighthouse","decode_error_count":0,"event_count":12,"failed_handshakes":0,"failed_identify_attempts":0,"first_seen_at":"2025-06-01T12:00:00Z","goodbye_count":0,"has_scores":true,"identify_attempts":1,"last_seen_at":"2025-06-01T12:00:01Z","last_session_status":"Connected","last_session_time":"2025-06-01T12:00:01Z","max_peer_score":18.25,"mesh_count":1,"min_peer_score":12.5,"origin":"discv5","peer_id":"16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6","reqresp_abuse_count":0,"score_area_below_zero":0,"seconds_below_publish":0,"session_count":1,"short_peer_id":"16Uiu2HAm7Ux","successful_handshakes":0,"time_weighted_score":15.275862068965518,"total_connections":1,"total_message_count":0},{"attempts_to_identify":1,"client_agent":"teku/v25.6.0/linux-x86_64/-eclipseadoptium-openjdk64bitservervm-java-21","client_type":"teku","decode_error_count":1,"decode_errors":{"total":1,"by_kind":{"validation_failed":1},"by_topic":{"/eth2/4a26c58b/beacon_attestation_3/ssz_snappy":1},"last_reason":"validation failed","last_seen_at":"2025-06-01T12:01:00Z"},"event_count":14,"failed_handshakes":0,"failed_identify_attempts":0,"first_seen_at":"2025-06-01T12:00:00Z","goodbye_count":0,"has_scores":true,"identify_attempts":1,"last_seen_at":"2025-06-01T12:00:05Z","last_session_status":"Disconnected","last_session_time":"2025-06-01T12:00:05Z","max_peer_score":1.2,"mesh_count":0,"min_peer_score":-0.5,"origin":"incoming","peer_id":"16Uiu2HAmQn8vFdVFNcCW9LUnV8mK5pYtDFjgxQ8h8XcE2Ns6i6Ar","reqresp_abuse_count":0,"score_area_below_zero":-180,"seconds_below_publish":0,"session_count":1,"short_peer_id":"16Uiu2HAmQn8","successful_handshakes":0,"time_weighted_score":0.4444444444444444,"total_connections":1,"total_message_count":0}],"publish_threshold":-8000,"reqresp_abuse_by_client":{},"reqresp_abusers":[],"score_band_chart":{"Width":800,"Height":200,"MeanArea":"0.0,51.7 800.0,0.0 800.0,168.5 0.0,200.0","MeanLine":"0.0,153.3 800.0,139.3","MinArea":"0.0,51.7 800.0,0.0 800.0,168.5 0.0,200.0","MinLine":"0.0,153.3 800.0,139.3","Top":18.25,"Bottom":-4,"Thresholds":null,"ZeroY":164.04494382022472},"score_bands":{"peers":3,"snapshots":6,"min":{"p10":-4,"p50":-0.5,"p90":12.5},"mean":{"p10":-0.625,"p50":0.35,"p90":15.375},"bucket_seconds":60,"buckets":[{"start":"2025-06-01T12:00:00Z","peers":3,"min":{"p10":-4,"p50":1.2,"p90":12.5},"mean":{"p10":-4,"p50":1.2,"p90":12.5}},{"start":"2025-06-01T12:08:00Z","peers":3,"min":{"p10":-0.5,"p50":2.75,"p90":18.25},"mean":{"p10":-0.5,"p50":2.75,"p90":18.25}}],"below_gossip":0,"below_publish":0,"below_graylist":0},"transports":[{"transport":"tcp","peers":2,"sessions":3,"disconnected":1,"short_lived":0,"with_goodbye":1,"median_duration_seconds":139,"muxers":{"not reported":3},"security":{"not reported":3}},{"transport":"quic","peers":1,"sessions":1,"disconnected":1,"short_lived":0,"with_goodbye":0,"median_duration_seconds":835,"muxers":{"quic":1},"security":{"tls":1}}],"unknown_clients":{"peers":0,"sessions":0,"distinct_agents":0,"agent_strings":[],"identify":{"identified":0,"never_identified":0,"median_identify_seconds":0,"max_identify_seconds":0,"median_unidentified_life_seconds":0},"session_fates":{},"goodbye_reasons":{}}}};
\ No newline at end of file
@@ -1,25 +1,105 @@
 package core
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/probe-lab/hermes/host"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/aiqueue"
+	"github.com/ethpandaops/hermes-peer-score/internal/alerting"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconfetch"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconpeers"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
+	"github.com/ethpandaops/hermes-peer-score/internal/canary"
+	"github.com/ethpandaops/hermes-peer-score/internal/checkpoint"
+	"github.com/ethpandaops/hermes-peer-score/internal/clockskew"
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
 	"github.com/ethpandaops/hermes-peer-score/internal/config"
 	"github.com/ethpandaops/hermes-peer-score/internal/events"
 	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/peertrace"
+	"github.com/ethpandaops/hermes-peer-score/internal/progress"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+	"github.com/ethpandaops/hermes-peer-score/internal/publish"
+	"github.com/ethpandaops/hermes-peer-score/internal/reachability"
+	"github.com/ethpandaops/hermes-peer-score/internal/recommend"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
 	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+	"github.com/ethpandaops/hermes-peer-score/internal/resources"
+	"github.com/ethpandaops/hermes-peer-score/internal/signing"
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
 )
 
 // DefaultTool implements the Tool interface.
 type DefaultTool struct {
 	config    config.Config
 	logger    logrus.FieldLogger
+	clock     func() time.Time // Current time, fixed by the golden report tests
 	startTime time.Time
+	phases    *peer.RunPhases
+	timeline  *peer.TimelineRecorder
+	topics    *peer.SubscriptionRecorder
+	router    *peer.RouterRecorder
+
+	// Connections that failed before Hermes traced them, read from the libp2p and Hermes logs
+	negotiation    *peer.NegotiationRecorder
+	negotiationTap *negotiationTap
+
+	// Independent validation's beacon data fetches, nil when they are not observed
+	beaconFetches *beaconfetch.Recorder
+
+	// Delegated validation's beacon node failures, nil in independent mode
+	beaconSync *beaconsync.Tracker
+
+	// Canary peers staged against the primary host, and the topic they are on
+	canaries    []*canary.Canary
+	canaryTopic string
+
+	// Resources the libp2p resource manager refused, sampled from the start of the run
+	resources *resources.Monitor
+
+	// Periods the collector was down, recorded when a run resumes from a checkpoint
+	gaps []peer.RunGap
+
+	// Errors the run recovered from, graded in the run manifest
+	errBudget *reports.ErrorBudget
+
+	// Where the health summary is printed once the reports are saved
+	summaryOut io.Writer
+
+	// Machine-readable progress events for wrapper automation, nil when disabled
+	progress *progress.Emitter
+
+	// Event starvation watchdog for the primary host, and the windows it recorded once stopped
+	watchdog   *watchdog.Watchdog
+	starvation []watchdog.Window
+
+	// Spills the primary host's completed sessions' events to disk under memory pressure, nil
+	// when disabled. spill moves the sessions that ended before a cutoff.
+	spiller *peer.Spiller
+	spill   func(cutoff time.Time) (int, error)
+
+	// Restarts of the primary Hermes node into the steps of a MaxPeers ramp
+	rampMu       sync.Mutex
+	rampRestarts []peer.RampRestart
+
+	// Serializes restarts of the primary Hermes node by the watchdog and the MaxPeers ramp
+	restartMu sync.Mutex
+
+	// Shutdown phase, from stopping the primary Hermes node until it returned or timed out
+	shutdownStart     time.Time
+	shutdownEnd       time.Time
+	shutdownCompleted bool
 
 	// Core components
 	peerRepo   peer.Repository
@@ -28,20 +108,43 @@ type DefaultTool struct {
 	reportGen  *reports.DefaultGenerator
 	hermesCtrl HermesController
 
+	// Additional Hermes hosts run alongside the primary one for comparison
+	extraHosts []*hostCollector
+
 	// Event counting
 	peerEventCounts map[string]map[string]int
+
+	// Picks the level of per-event log lines
+	logSampler *common.LogSampler
+
+	// Reachability self-test result, set once the dial-back completes. The beacon node
+	// peer cross-check results and clock skew samples share the lock.
+	reachabilityMu     sync.Mutex
+	reachabilityResult *reachability.Result
+	beaconPeersResult  *beaconpeers.Result
+	beaconPeersChecks  []beaconpeers.Summary
+	clockSamples       []clockskew.BeaconSample
 }
 
 // NewTool creates a new peer score tool instance.
 func NewTool(ctx context.Context, cfg config.Config, logger logrus.FieldLogger) (*DefaultTool, error) {
+	_ = ctx // Context will be passed to individual methods as needed
+
+	return newTool(cfg, logger, time.Now)
+}
+
+// newTool creates a tool reading the current time from clock.
+func newTool(cfg config.Config, logger logrus.FieldLogger, clock func() time.Time) (*DefaultTool, error) {
 	tool := &DefaultTool{
 		config:          cfg,
 		logger:          logger.WithField("component", "core_tool"),
+		clock:           clock,
+		errBudget:       reports.NewErrorBudget(),
+		summaryOut:      os.Stdout,
 		peerEventCounts: make(map[string]map[string]int),
+		logSampler:      common.NewLogSampler(cfg.GetLogSample()),
 	}
 
-	_ = ctx // Context will be passed to individual methods as needed
-
 	// Initialize components
 	if err := tool.initializeComponents(); err != nil {
 		return nil, fmt.Errorf("failed to initialize components: %w", err)
@@ -50,10 +153,40 @@ func NewTool(ctx context.Context, cfg config.Config, logger logrus.FieldLogger)
 	return tool, nil
 }
 
+// SetProgress sets where progress events are emitted. Standard output then carries the events
+// alone, so the health summary moves to standard error.
+func (t *DefaultTool) SetProgress(emitter *progress.Emitter) {
+	t.progress = emitter
+
+	if emitter.WritesToStdout() {
+		t.summaryOut = os.Stderr
+	}
+}
+
+// SetPeerTrace records every event of the trace's peer in it, alongside the normal processing.
+func (t *DefaultTool) SetPeerTrace(trace *peertrace.Trace) {
+	t.eventMgr.SetPeerTrace(trace)
+}
+
 // initializeComponents sets up all the tool's dependencies.
 func (t *DefaultTool) initializeComponents() error {
-	// Initialize peer repository
-	t.peerRepo = peer.NewInMemoryRepository(t.logger)
+	// Initialize peer repository, sampling peers for detailed capture when configured
+	repo := peer.NewInMemoryRepository(t.logger)
+	repo.SetClock(t.clock)
+
+	if t.config.IsDetailSampled() {
+		sampler := peer.NewDetailSampler(t.config.GetDetailSampleRate(), t.config.GetDetailSampleSeed())
+		sampler.SetTraced(t.config.GetTracePeer())
+		repo.SetSampler(sampler)
+	}
+
+	if t.config.GetSpillRSSMB() > 0 {
+		t.spiller = peer.NewSpiller(t.config.GetSpillDir())
+		t.spill = repo.Spill
+		repo.SetSpiller(t.spiller)
+	}
+
+	t.peerRepo = repo
 
 	// Initialize session manager
 	t.sessionMgr = peer.NewSessionManager(t.peerRepo, t.logger)
@@ -66,6 +199,63 @@ func (t *DefaultTool) initializeComponents() error {
 		return fmt.Errorf("failed to create report generator: %w", err)
 	}
 
+	t.reportGen.SetSplitReport(t.config.IsSplitReport(), t.config.GetShardSize())
+	t.reportGen.SetDataFile(t.config.IsPrettyDataFile(), t.config.GetDataFileBudgetMB()<<20)
+	t.reportGen.SetSwimlanes(t.config.GetSwimlanePeers())
+	t.reportGen.SetRedactor(redact.New(t.config.Secrets()...))
+	t.reportGen.SetClock(t.clock)
+
+	// Load template overrides now, so a broken template fails the run before collecting rather than after
+	if err := t.reportGen.SetTemplateDir(t.config.GetTemplateDir()); err != nil {
+		return fmt.Errorf("failed to load template overrides: %w", err)
+	}
+
+	prunePolicy, err := prune.New(t.config.GetPruneFields(), t.config.GetHashFields(), t.config.GetPruneHashSalt())
+	if err != nil {
+		return fmt.Errorf("invalid field pruning policy: %w", err)
+	}
+
+	t.reportGen.SetPrunePolicy(prunePolicy)
+	t.reportGen.SetErrorBudget(t.errBudget)
+
+	// Load the signing key now, so a bad key fails the run before collecting rather than after
+	if scheme := t.config.GetSignScheme(); scheme != "" {
+		signer, err := signing.NewSigner(scheme, t.config.GetSigningKeyFile())
+		if err != nil {
+			return fmt.Errorf("failed to create report signer: %w", err)
+		}
+
+		t.reportGen.SetSigner(signer)
+	}
+
+	// Load custom analyzers now, so a broken plugin fails the run before collecting rather than after
+	analyzerRunner, err := loadAnalyzers(t.config, t.logger)
+	if err != nil {
+		return fmt.Errorf("failed to load custom analyzers: %w", err)
+	}
+
+	t.reportGen.SetAnalyzers(analyzerRunner)
+
+	// AI requests may have to go through a gateway or an egress proxy
+	if err := t.reportGen.SetAIClient(reports.AIClientOptions{
+		BaseURL:  t.config.GetAIBaseURL(),
+		ProxyURL: t.config.GetAIProxy(),
+		CABundle: t.config.GetAICABundle(),
+		Timeout:  t.config.GetAITimeout(),
+	}); err != nil {
+		return fmt.Errorf("failed to configure AI client: %w", err)
+	}
+
+	// Runs sharing an AI queue directory take turns calling the AI API
+	if dir := t.config.GetAIQueueDir(); dir != "" {
+		queue, err := aiqueue.New(dir, t.config.GetAIConcurrency())
+		if err != nil {
+			return fmt.Errorf("failed to create AI queue: %w", err)
+		}
+
+		t.reportGen.SetAIQueue(queue, t.config.GetAIQueueTimeout())
+	}
+
 	// Initialize event manager
 	t.eventMgr = events.NewManager(t, t.logger)
 
@@ -74,16 +264,86 @@ func (t *DefaultTool) initializeComponents() error {
 		return fmt.Errorf("failed to register event handlers: %w", err)
 	}
 
-	// Initialize Hermes controller
-	t.hermesCtrl = NewHermesController(t.config, t.logger)
+	// Bucket primary host events over time for burst detection. The window is planned from
+	// now until the phases are known, its end is moved once they are.
+	plannedEnd := t.clock().Add(t.config.GetWarmupDuration() + t.config.GetTestDuration() +
+		t.config.GetCooldownDuration() + t.config.GetShutdownTimeout() + t.config.GetEventBucketWidth())
+	t.timeline = peer.NewTimelineRecorder(t.clock(), plannedEnd, t.config.GetEventBucketWidth(), t.config.GetEventBurstThreshold())
+	t.eventMgr.SetTimeline(t.timeline)
+
+	// Record the primary host's gossip topic subscriptions
+	t.topics = peer.NewSubscriptionRecorder()
+	t.eventMgr.SetSubscriptions(t.topics)
+
+	// Sample the primary host's own gossipsub router, to read peers' reactions against
+	t.router = peer.NewRouterRecorder(t.clock(), t.config.GetEventBucketWidth())
+	t.eventMgr.SetRouter(t.router)
+
+	t.negotiation = peer.NewNegotiationRecorder()
+
+	// Restrict processing to the whitelisted gossip topics, for light-footprint monitoring
+	if whitelist := t.config.GetTopicWhitelist(); len(whitelist) > 0 {
+		t.eventMgr.SetTopicWhitelist(whitelist)
+	}
+
+	// Initialize Hermes controllers, the first configured host is the primary one
+	hosts := t.config.GetHosts()
+	if len(hosts) == 0 {
+		t.hermesCtrl = NewHermesController(t.config, t.logger)
+
+		return nil
+	}
+
+	t.hermesCtrl = NewHostHermesController(t.config, hosts[0], true, t.logger)
+
+	for _, spec := range hosts[1:] {
+		collector, err := newHostCollector(t.config, spec, t.logger)
+		if err != nil {
+			return err
+		}
+
+		t.extraHosts = append(t.extraHosts, collector)
+	}
 
 	return nil
 }
 
 // Start begins the peer scoring test.
 func (t *DefaultTool) Start(ctx context.Context) error {
-	t.startTime = time.Now()
+	t.startTime = t.clock()
 	t.logger.Info("Starting peer score tool")
+	t.progress.Emit(progress.Event{Event: progress.EventStarted})
+
+	// Restore state before any events arrive, so resumed counts continue where they left off
+	if t.config.IsResume() {
+		if err := t.resumeFromCheckpoint(); err != nil {
+			return fmt.Errorf("failed to resume from checkpoint: %w", err)
+		}
+	}
+
+	// The watchdog exists before events can arrive, it only checks for starvation once Hermes is up
+	if timeout := t.config.GetStarvationTimeout(); timeout > 0 {
+		t.watchdog = watchdog.New(timeout, t.clock(), t.clock, t.connectionDiagnostics, t.logger)
+	}
+
+	// A MaxPeers ramp starts Hermes in the step the run is in, which for resumed runs may be a later one
+	if ramp := t.config.GetMaxPeersRamp(); len(ramp) > 0 {
+		t.hermesCtrl.SetMaxPeers(ramp[t.rampStepAt(t.clock())])
+	}
+
+	// Tap the logs before the hosts listen, so the first failed connections are counted
+	if t.config.IsNegotiationLogs() {
+		t.negotiationTap = startNegotiationTap(t.negotiation, t.logger)
+	}
+
+	// Time the beacon data fetches independent validation makes from its first one
+	t.startBeaconFetchRecorder()
+
+	// Watch the beacon node delegated validation relies on, its failures pause score attribution
+	t.startBeaconSyncTracker()
+
+	// Count the resources our own resource manager refuses, which close connections without a goodbye
+	t.resources = resources.NewMonitor(t.clock())
 
 	// Start Hermes
 	if err := t.hermesCtrl.Start(ctx); err != nil {
@@ -93,23 +353,396 @@ func (t *DefaultTool) Start(ctx context.Context) error {
 	// Register event callback
 	t.hermesCtrl.RegisterEventCallback(t.handleEvent)
 
-	// Start status reporting
-	go t.startStatusReporting(ctx)
+	stopWatchdog := t.startWatchdog(ctx)
+	defer stopWatchdog()
+
+	stopMemoryWatchdog := t.startMemoryWatchdog(ctx)
+	defer stopMemoryWatchdog()
+
+	// Start additional hosts, each feeding its own peer state
+	for _, collector := range t.extraHosts {
+		if err := collector.start(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Check our libp2p port is reachable from outside while the run proceeds
+	if checkURL := t.config.GetReachabilityCheckURL(); checkURL != "" {
+		go t.checkReachability(ctx, checkURL)
+	}
+
+	// Compare our clock with the beacon node's early, skew ruins timeliness-sensitive scores
+	if t.config.GetClockSkewThreshold() > 0 {
+		go t.checkClockSkew(ctx)
+	}
 
-	// Wait for test duration or context cancellation
+	// Phase boundaries start once Hermes is up, so node startup time is not measured.
+	// Resumed runs keep the boundaries planned by the original run.
 	testDuration := t.config.GetTestDuration()
-	t.logger.WithField("duration", testDuration).Info("Running peer score test")
+	if t.phases == nil {
+		t.phases = peer.NewRunPhases(t.clock(), t.config.GetWarmupDuration(), testDuration, t.config.GetCooldownDuration())
+	}
+
+	// Events stamped after the shutdown phase, with a bucket of slack, fall outside the timeline
+	t.timeline.SetEnd(t.phases.CooldownEnd.Add(t.config.GetShutdownTimeout() + t.config.GetEventBucketWidth()))
+
+	// Start status reporting, which places its progress events in the phases
+	go t.startStatusReporting(ctx)
+
+	t.logger.WithFields(logrus.Fields{
+		"warmup":   t.config.GetWarmupDuration(),
+		"duration": testDuration,
+		"cooldown": t.config.GetCooldownDuration(),
+		"ends_at":  t.phases.CooldownEnd,
+	}).Info("Running peer score test")
+
+	// Checkpoint collector state until the run ends
+	checkpointCtx, stopCheckpoints := context.WithCancel(ctx)
+
+	var checkpoints sync.WaitGroup
+
+	if interval := t.config.GetCheckpointInterval(); interval > 0 {
+		checkpoints.Add(1)
+
+		go func() {
+			defer checkpoints.Done()
+
+			t.runCheckpoints(checkpointCtx, interval)
+		}()
+	}
+
+	// Snapshot the beacon node's peers during the run too, the final check adds the last one
+	if interval := t.config.GetBeaconPeersInterval(); t.config.IsCheckBeaconPeers() && interval > 0 {
+		checkpoints.Add(1)
+
+		go func() {
+			defer checkpoints.Done()
+
+			t.runBeaconPeerChecks(checkpointCtx, interval)
+		}()
+	}
+
+	// Check the delegated beacon node is synced, failed requests alone miss a node quietly behind
+	if interval := t.config.GetBeaconSyncInterval(); t.beaconSync != nil && interval > 0 {
+		checkpoints.Add(1)
+
+		go func() {
+			defer checkpoints.Done()
+
+			t.runBeaconSyncChecks(checkpointCtx, interval)
+		}()
+	}
+
+	checkpoints.Add(1)
+
+	go func() {
+		defer checkpoints.Done()
+
+		t.runResourceSamples(checkpointCtx, constants.ResourceSampleInterval)
+	}()
+
+	// Stage peers of known behaviour, the report checks the primary host scored them as expected
+	if t.config.IsCanary() {
+		t.startCanaries(checkpointCtx, &checkpoints)
+	}
 
-	select {
-	case <-ctx.Done():
-		t.logger.Info("Test interrupted by context cancellation")
-	case <-time.After(testDuration):
-		t.logger.Info("Test duration completed")
+	defer func() {
+		stopCheckpoints()
+		checkpoints.Wait()
+	}()
+
+	// Step MaxPeers through the ramp, if configured, on the phase boundaries' clock
+	stopRamp := t.startMaxPeersRamp(ctx)
+	defer stopRamp()
+
+	// Run each phase in turn until the run completes or the context is cancelled
+	phases := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+	}{
+		{peer.PhaseWarmup, t.phases.WarmupStart, t.phases.MeasureStart},
+		{peer.PhaseMeasure, t.phases.MeasureStart, t.phases.MeasureEnd},
+		{peer.PhaseCooldown, t.phases.MeasureEnd, t.phases.CooldownEnd},
+	}
+
+	for _, phase := range phases {
+		remaining := time.Until(phase.end)
+		if !phase.end.After(phase.start) || remaining <= 0 {
+			continue
+		}
+
+		t.logger.WithFields(logrus.Fields{
+			"phase":     phase.name,
+			"remaining": remaining,
+		}).Info("Entering run phase")
+
+		t.progress.Emit(progress.Event{
+			Event:      progress.EventPhase,
+			Phase:      phase.name,
+			ETASeconds: progress.Seconds(t.phases.CooldownEnd.Sub(t.clock())),
+		})
+
+		select {
+		case <-ctx.Done():
+			t.logger.WithField("phase", phase.name).Info("Test interrupted by context cancellation")
+
+			return nil
+		case <-time.After(remaining):
+		}
+	}
+
+	t.logger.Info("Test duration completed")
+
+	// Events stop once Hermes is shut down, which is not starvation
+	stopWatchdog()
+	stopRamp()
+
+	// Cross-check peers while Hermes and the beacon node are still connected to them
+	if t.config.IsCheckBeaconPeers() {
+		t.checkBeaconPeers(ctx)
+	}
+
+	// Check the clock again, it may have drifted over a long run
+	if t.config.GetClockSkewThreshold() > 0 {
+		t.checkClockSkew(ctx)
+	}
+
+	// Stop Hermes while its events are still processed, so the peers' teardown is recorded
+	if t.config.GetShutdownTimeout() > 0 {
+		t.runShutdown()
 	}
 
 	return nil
 }
 
+// runShutdown stops the primary Hermes node as the run's shutdown phase. Events keep being
+// handled until the node returns, so goodbyes and disconnects during teardown are recorded.
+func (t *DefaultTool) runShutdown() {
+	t.shutdownStart = t.clock()
+
+	t.logger.WithFields(logrus.Fields{
+		"phase":   peer.PhaseShutdown,
+		"timeout": t.config.GetShutdownTimeout(),
+	}).Info("Entering run phase")
+
+	t.progress.Emit(progress.Event{Event: progress.EventPhase, Phase: peer.PhaseShutdown})
+
+	if err := t.hermesCtrl.Stop(); err != nil {
+		t.logger.WithError(err).Warn("Hermes did not stop within the shutdown timeout, the remaining peers' teardown is unobserved")
+		t.errBudget.Record(reports.ErrorCategoryHermes)
+	} else {
+		t.shutdownCompleted = true
+	}
+
+	t.shutdownEnd = t.clock()
+}
+
+// startWatchdog runs the event starvation watchdog, when configured, until the returned
+// function is called. Stopping it ends any open starvation window and keeps the windows
+// for the report.
+func (t *DefaultTool) startWatchdog(ctx context.Context) func() {
+	if t.watchdog == nil {
+		return func() {}
+	}
+
+	if t.config.IsRestartOnStarvation() {
+		t.watchdog.SetRestart(func() error {
+			err := t.restartHermes(ctx)
+			if err != nil {
+				t.errBudget.Record(reports.ErrorCategoryHermes)
+			}
+
+			return err
+		})
+	}
+
+	watchdogCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		t.watchdog.Run(watchdogCtx)
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+
+			t.starvation = t.watchdog.Finish()
+		})
+	}
+}
+
+// restartHermes stops the primary Hermes node and starts a fresh one in its place.
+func (t *DefaultTool) restartHermes(ctx context.Context) error {
+	t.restartMu.Lock()
+	defer t.restartMu.Unlock()
+
+	if err := t.hermesCtrl.Stop(); err != nil {
+		return fmt.Errorf("failed to stop Hermes: %w", err)
+	}
+
+	if err := t.hermesCtrl.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start Hermes: %w", err)
+	}
+
+	t.hermesCtrl.RegisterEventCallback(t.handleEvent)
+
+	return nil
+}
+
+// resumeFromCheckpoint restores collector state from the checkpoint file and records the
+// time the collector was down as a gap.
+func (t *DefaultTool) resumeFromCheckpoint() error {
+	cp, err := checkpoint.Load(t.config.GetCheckpointFile())
+	if err != nil {
+		return err
+	}
+
+	if cp.ValidationMode != string(t.config.GetValidationMode()) || cp.Network != t.config.GetNetwork() {
+		return fmt.Errorf("checkpoint is for a %s run on %s, not %s on %s",
+			cp.ValidationMode, cp.Network, t.config.GetValidationMode(), t.config.GetNetwork())
+	}
+
+	gap := cp.Resume(t.clock())
+
+	t.peerRepo.Restore(cp.Peers, cp.EventCounts)
+	t.timeline.Restore(cp.EventTimeline)
+	t.startTime = cp.StartTime
+	t.phases = cp.Phases
+	t.gaps = cp.Gaps
+
+	t.logger.WithFields(logrus.Fields{
+		"checkpoint": t.config.GetCheckpointFile(),
+		"peers":      len(cp.Peers),
+		"gap_from":   gap.From,
+		"gap":        time.Duration(gap.Seconds * float64(time.Second)),
+	}).Info("Resumed run from checkpoint")
+
+	return nil
+}
+
+// runCheckpoints periodically writes collector state to the checkpoint file.
+func (t *DefaultTool) runCheckpoints(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.writeCheckpoint(); err != nil {
+				t.logger.WithError(err).Warn("Failed to write checkpoint")
+				t.errBudget.Record(reports.ErrorCategoryCheckpoint)
+			}
+		}
+	}
+}
+
+// writeCheckpoint saves the current collector state to the checkpoint file.
+func (t *DefaultTool) writeCheckpoint() error {
+	phases := *t.phases
+
+	// Checkpoints hold every event, spilled ones included, so a resumed run loses none
+	peers := t.peerRepo.GetAllPeers()
+	if err := t.spiller.Load(peers); err != nil {
+		return fmt.Errorf("failed to load spilled session events: %w", err)
+	}
+
+	return checkpoint.Save(t.config.GetCheckpointFile(), &checkpoint.Checkpoint{
+		SavedAt:        t.clock(),
+		ValidationMode: string(t.config.GetValidationMode()),
+		Network:        t.config.GetNetwork(),
+		StartTime:      t.startTime,
+		Phases:         &phases,
+		Gaps:           t.gaps,
+		Peers:          peers,
+		EventCounts:    t.peerRepo.GetPeerEventCounts(),
+		EventTimeline:  t.timeline.Snapshot(),
+	})
+}
+
+// checkReachability asks the dial-back vantage whether the primary host's libp2p port is reachable.
+func (t *DefaultTool) checkReachability(ctx context.Context, checkURL string) {
+	checker := reachability.NewChecker(checkURL, constants.DefaultReachabilityTimeout, t.logger)
+	result := checker.Check(ctx, t.config.GetPrimaryLibp2pPort())
+
+	if result.Status == reachability.StatusUnreachable {
+		t.logger.WithField("port", result.Port).Warn("libp2p port is not reachable from the internet, only outbound connections will succeed and peer retention will suffer")
+	}
+
+	t.reachabilityMu.Lock()
+	t.reachabilityResult = result
+	t.reachabilityMu.Unlock()
+}
+
+// runBeaconPeerChecks periodically snapshots the beacon node's peers and keeps the counts of
+// each cross-check for the report.
+func (t *DefaultTool) runBeaconPeerChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			summary := t.compareBeaconPeers(ctx).Summary()
+
+			t.reachabilityMu.Lock()
+			t.beaconPeersChecks = append(t.beaconPeersChecks, summary)
+			t.reachabilityMu.Unlock()
+		}
+	}
+}
+
+// checkBeaconPeers cross-references the primary host's peers with the beacon node's peer list
+// and keeps the result, with the checks taken during the run, for the report.
+func (t *DefaultTool) checkBeaconPeers(ctx context.Context) {
+	result := t.compareBeaconPeers(ctx)
+
+	t.reachabilityMu.Lock()
+	result.Periodic = append([]beaconpeers.Summary(nil), t.beaconPeersChecks...)
+	t.beaconPeersResult = result
+	t.reachabilityMu.Unlock()
+}
+
+// compareBeaconPeers snapshots the beacon node's peers and reports their overlap with the
+// peers of the primary host, in the state of each peer's last session.
+func (t *DefaultTool) compareBeaconPeers(ctx context.Context) *beaconpeers.Result {
+	hermes := make(map[string]beaconpeers.HermesPeer)
+
+	for peerID, stats := range t.peerRepo.GetAllPeers() {
+		if len(stats.ConnectionSessions) == 0 {
+			continue
+		}
+
+		last := stats.ConnectionSessions[len(stats.ConnectionSessions)-1]
+		hermes[peerID] = beaconpeers.HermesPeer{Connected: !last.Disconnected}
+	}
+
+	checker := beaconpeers.NewChecker(t.config.GetPrysmHost(), t.config.GetPrysmHTTPPort(), t.config.GetUseTLS(), constants.DefaultBeaconPeersTimeout, t.logger)
+
+	return checker.Check(ctx, hermes)
+}
+
+// checkClockSkew compares our clock with the beacon node's and records the sample.
+func (t *DefaultTool) checkClockSkew(ctx context.Context) {
+	checker := clockskew.NewChecker(t.config.GetPrysmHost(), t.config.GetPrysmHTTPPort(), t.config.GetUseTLS(),
+		t.config.GetClockSkewThreshold(), constants.DefaultClockSkewTimeout, t.logger)
+	sample := checker.Check(ctx, t.hermesCtrl.GetSlotClock())
+
+	t.reachabilityMu.Lock()
+	t.clockSamples = append(t.clockSamples, sample)
+	t.reachabilityMu.Unlock()
+}
+
 // Stop gracefully shuts down the tool.
 func (t *DefaultTool) Stop() error {
 	t.logger.Info("Stopping peer score tool")
@@ -120,23 +753,314 @@ func (t *DefaultTool) Stop() error {
 		}
 	}
 
+	for _, collector := range t.extraHosts {
+		if err := collector.hermesCtrl.Stop(); err != nil {
+			t.logger.WithError(err).WithField("host", collector.spec.Label).Error("Error stopping Hermes controller")
+		}
+	}
+
+	if t.negotiationTap != nil {
+		t.negotiationTap.Stop()
+		t.negotiationTap = nil
+	}
+
+	t.stopBeaconFetchRecorder()
+
+	if err := t.spiller.Close(); err != nil {
+		t.logger.WithError(err).Warn("Failed to remove the spill file")
+	}
+
 	return nil
 }
 
-// GenerateReport creates the final peer score report.
-func (t *DefaultTool) GenerateReport() (*Report, error) {
+// GenerateReport creates the final peer score report, stopping with an error when ctx is cancelled.
+func (t *DefaultTool) GenerateReport(ctx context.Context) (*Report, error) {
 	t.logger.Info("Generating peer score report")
 
-	endTime := time.Now()
+	endTime := t.clock()
 	duration := endTime.Sub(t.startTime)
 
-	// Get all peer data
+	// Get all peer data, reading events spilled under memory pressure back in
 	peers := t.peerRepo.GetAllPeers()
 	eventCounts := t.peerRepo.GetPeerEventCounts()
 
-	// Calculate statistics
+	if err := t.spiller.Load(peers); err != nil {
+		return nil, fmt.Errorf("failed to load spilled session events: %w", err)
+	}
+
+	if spilled := t.spiller.Stats(); spilled.Sessions > 0 {
+		t.logger.WithFields(logrus.Fields{
+			"spills":   spilled.Spills,
+			"sessions": spilled.Sessions,
+			"bytes":    spilled.Bytes,
+		}).Info("Read spilled session events back for the report")
+	}
+
+	// Annotate events with the beacon slot and epoch they occurred in
+	if slotClock := t.hermesCtrl.GetSlotClock(); slotClock != nil {
+		slotClock.AnnotatePeers(peers)
+	}
+
+	// Tag how each peer came to us, boot nodes and static peers are kept out of churn statistics
+	peer.TagOrigins(peers, t.hermesCtrl.GetPeerOrigins())
+
+	// Check the canaries were scored as staged, which also keeps them out of the statistics
+	canaryResult := t.canaryResult(peers)
+
+	// Summarise each session's score trajectory, open sessions are scored up to the end of the run
+	peer.SummarizeSessionScores(peers, endTime)
+
+	// Compare the peers' scores with other vantage points sharing the score feed
+	scoreConsensus := t.shareScores(ctx, peers, endTime)
+
+	// Sessions closed and goodbyes sent while Hermes shut down are the peers' teardown rather
+	// than churn. They are tagged before any statistics are taken, so every one of them leaves
+	// the teardown out unless asked for
+	var shutdown *peer.ShutdownTeardown
+	if !t.shutdownStart.IsZero() {
+		shutdown = peer.AnalyzeShutdown(peers, t.shutdownStart, t.shutdownEnd, t.shutdownCompleted, t.config.IsIncludeTeardown())
+	}
+
+	// Tag sessions with their MaxPeers ramp step first, so the restarts between steps are not counted as churn
+	var ramp *peer.MaxPeersRamp
+	if steps := t.config.GetMaxPeersRamp(); len(steps) > 0 && t.phases != nil {
+		rampEnd := endTime
+		if !t.shutdownStart.IsZero() {
+			rampEnd = t.shutdownStart
+		}
+
+		t.rampMu.Lock()
+		restarts := append([]peer.RampRestart(nil), t.rampRestarts...)
+		t.rampMu.Unlock()
+
+		ramp = peer.AnalyzeMaxPeersRamp(peers, steps, t.config.GetMaxPeersRampStep(), t.phases.WarmupStart, rampEnd, restarts)
+		for _, step := range ramp.Steps {
+			t.logger.WithFields(logrus.Fields{
+				"ramp_step":      step.Step,
+				"max_peers":      step.MaxPeers,
+				"mean_peers":     step.MeanPeers,
+				"churn_per_hour": step.ChurnPerHour,
+				"mean_score":     step.MeanScore,
+			}).Info("MaxPeers ramp step statistics")
+		}
+	}
+
+	// Calculate headline statistics from the measurement window only, so startup
+	// effects (mesh formation, discovery ramp) do not skew short runs
 	calculator := peer.NewStatsCalculator()
-	connectionStats := calculator.CalculateConnectionStats(peers)
+	measuredPeers := peer.GeneralPeers(peers)
+
+	if t.phases != nil {
+		t.phases.Finish(endTime)
+		measuredPeers = t.phases.MeasuredPeers(measuredPeers)
+	}
+
+	connectionStats := calculator.CalculateConnectionStats(measuredPeers)
+
+	// The same statistics per time slice over the whole run, so degradations partway through show
+	timeSlices := peer.CalculateTimeSlices(peer.GeneralPeers(peers), t.startTime, endTime, constants.TimeSliceWidth)
+
+	// And per beacon epoch, so incidents reported by epoch number can be cross-referenced
+	epochs := peer.CalculateEpochSlices(peer.GeneralPeers(peers), t.hermesCtrl.GetSlotClock(), t.startTime, endTime)
+
+	// Reconcile retried handshakes into one outcome per connection episode
+	reconciled := calculator.CalculateReconciledHandshakes(measuredPeers, t.config.GetHandshakeRetryWindow())
+
+	// Event ordering is checked on the primary host, whose peers the report details
+	dataQuality := t.eventMgr.DataQuality()
+	peer.CountLateEvents(peers, t.config.GetLateEventGrace(), &dataQuality)
+	peer.CountDuplicateConnections(peers, &dataQuality)
+	peer.CountCorruptTopicScores(peers, &dataQuality)
+
+	// Sessions our own MaxPeers limit ended would otherwise count as peer churn. During a ramp
+	// capacity is judged against its largest step, each step's fill is in the ramp statistics.
+	primary := t.primaryHost()
+
+	maxPeers := cmp.Or(primary.MaxPeers, t.config.GetMaxPeers())
+	if steps := t.config.GetMaxPeersRamp(); len(steps) > 0 {
+		maxPeers = slices.Max(steps)
+	}
+
+	pressure := peer.AnalyzePeerPressure(peers, maxPeers, t.config.GetCapacityRatio(), endTime)
+	if pressure.Distorted {
+		t.logger.WithFields(logrus.Fields{
+			"max_peers":            pressure.MaxPeers,
+			"ended_by_local_limit": pressure.EndedByLocalLimit,
+			"disconnects":          pressure.Disconnects,
+		}).Warn("Our own peer limit ended many sessions, churn statistics are likely distorted")
+	}
+
+	// Sessions closed without a goodbye while our resource manager refused resources are ours
+	resourceSummary := t.resourceSummary(peers, endTime)
+
+	// Sessions still open at the end or closed by the run itself are censored, not churn
+	survival := peer.AnalyzeSessionSurvival(peers, endTime)
+
+	t.reachabilityMu.Lock()
+	reachabilityResult := t.reachabilityResult
+	beaconPeersResult := t.beaconPeersResult
+	clockSamples := append([]clockskew.BeaconSample(nil), t.clockSamples...)
+	t.reachabilityMu.Unlock()
+
+	// Peers' head slots tell whether our clock is off even without the beacon node's
+	var slotDrift *peer.SlotDrift
+	if slotClock := t.hermesCtrl.GetSlotClock(); slotClock != nil {
+		slotDrift = peer.AnalyzeSlotDrift(peers, slotClock, constants.ClockSkewMinPeers,
+			constants.ClockSkewPeerSlotsAhead, constants.ClockSkewPeerSlotsBehind)
+	}
+
+	beaconFetches := t.beaconFetchSummary()
+	beaconSync := t.beaconSyncSummary(peers, endTime)
+
+	clockSkew := clockskew.Summarize(t.config.GetClockSkewThreshold(), clockSamples, slotDrift)
+	if clockSkew != nil && clockSkew.Skewed {
+		fields := logrus.Fields{
+			"max_offset_ms": clockSkew.MaxOffsetMs,
+			"threshold_ms":  clockSkew.ThresholdMs,
+		}
+
+		if clockSkew.Peers != nil {
+			fields["peer_median_slot_drift"] = clockSkew.Peers.MedianSlotDrift
+		}
+
+		t.logger.WithFields(fields).Warn("Local clock was skewed during the run, timeliness-sensitive peer scores in this report are unreliable")
+	}
+
+	// Check the subscribed topics against the set expected for the fork, a wrong set ruins scoring
+	subscriptions := t.topics.Report(t.hermesCtrl.GetTopicExpectations())
+	if subscriptions.Checked && !subscriptions.Valid {
+		t.logger.WithFields(logrus.Fields{
+			"fork":       subscriptions.Fork,
+			"missing":    subscriptions.Missing,
+			"unexpected": len(subscriptions.Unexpected),
+		}).Warn("Gossip topic subscriptions do not match the set expected for the fork")
+	}
+
+	// Invalid deliveries on one topic across many peers usually point at Hermes rather than the peers
+	invalidDeliveries := peer.DetectInvalidDeliveries(peers, constants.InvalidDeliveryMinPeers)
+	for _, anomaly := range invalidDeliveries.Anomalies {
+		t.logger.WithFields(logrus.Fields{
+			"topic":          anomaly.Topic,
+			"peers":          len(anomaly.Peers),
+			"max_deliveries": anomaly.MaxDeliveries,
+			"first_seen":     anomaly.FirstSeenAt,
+			"last_seen":      anomaly.LastSeenAt,
+		}).Error("Invalid message deliveries counted on one topic across several peers, Hermes may be propagating or misjudging invalid messages")
+	}
+
+	// Stalled nodes are likely to score us poorly and prune us
+	statusTracking := peer.AnalyzeStatusUpdates(peers, constants.StatusStallThreshold)
+	if len(statusTracking.Stalled) > 0 {
+		t.logger.WithFields(logrus.Fields{
+			"stalled":       len(statusTracking.Stalled),
+			"status_peers":  statusTracking.Peers,
+			"stall_seconds": statusTracking.StallSeconds,
+		}).Warn("Peers reported a head slot that stopped advancing")
+	}
+
+	// Peers trailing our head find our gossip useless and tend to leave
+	headDivergence := peer.AnalyzeHeadDivergence(peers, t.hermesCtrl.GetSlotClock(), t.config.GetHeadDivergenceSlots(),
+		constants.HeadDivergenceFollow, constants.HeadDivergenceScoreDrop, constants.HeadDivergenceEpisodeLimit)
+	if headDivergence != nil && headDivergence.Episodes > 0 {
+		t.logger.WithFields(logrus.Fields{
+			"diverging_peers":        headDivergence.DivergingPeers,
+			"episodes":               headDivergence.Episodes,
+			"with_goodbye":           headDivergence.WithGoodbye,
+			"diverging_goodbye_rate": headDivergence.DivergingGoodbyeRate,
+			"in_sync_goodbye_rate":   headDivergence.InSyncGoodbyeRate,
+		}).Warn("Peers' head slots trailed ours")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("report generation cancelled during analysis: %w", err)
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"stage":   "analysis",
+		"peers":   len(peers),
+		"elapsed": time.Since(endTime).Round(time.Millisecond),
+	}).Info("Report generation progress")
+
+	// Our own router behaviour, to correlate the peers' treatment of us with
+	router := t.router.Snapshot(endTime)
+	if router != nil {
+		t.logger.WithFields(logrus.Fields{
+			"topics":         len(router.Topics),
+			"duplicate_rate": router.Totals.DuplicateRate,
+			"iwant_ratio":    router.Totals.IWantRatio,
+		}).Info("Sampled local gossipsub router metrics")
+
+		// Peers count invalid messages against us, so our own rejected publishes deserve attention
+		if publishing := router.Publishing; publishing != nil && publishing.Rejected > 0 {
+			t.logger.WithFields(logrus.Fields{
+				"published": publishing.Published,
+				"rejected":  publishing.Rejected,
+			}).Warn("Our own validator rejected messages we published")
+		}
+	}
+
+	// Per-topic health across peers, whether a topic is healthy matters more than any one peer
+	topicHealth := peer.AnalyzeTopicHealth(peers, router, primary.MeshDegree(t.config.GetMeshDegree()).Dlo,
+		constants.InvalidDeliveryMinPeers, constants.TopicHealthMeshPoints)
+	if topicHealth.Unhealthy > 0 {
+		t.logger.WithFields(logrus.Fields{
+			"unhealthy": topicHealth.Unhealthy,
+			"degraded":  topicHealth.Degraded,
+			"topics":    len(topicHealth.Topics),
+		}).Warn("Gossip topics look unhealthy across peers")
+	}
+
+	// Connections that never reached CONNECTED, hostility the peer statistics cannot show
+	negotiation := t.negotiation.Snapshot()
+	if negotiation != nil && negotiation.Inbound != nil && negotiation.Inbound.Unsupported > 0 {
+		t.logger.WithFields(logrus.Fields{
+			"failures":    negotiation.Inbound.Failures,
+			"unsupported": negotiation.Inbound.Unsupported,
+			"remotes":     negotiation.Inbound.Remotes,
+		}).Warn("Inbound connections offered no security or muxer protocol we support")
+	}
+
+	// Which boot nodes and static peers were reached, a private network hinges on a few of them
+	bootstrap := peer.BootstrapNodeReport(t.hermesCtrl.GetBootstrapNodes(), peers, t.startTime)
+	if unreached := peer.UnreachedBootstrapNodes(bootstrap); unreached > 0 && unreached == len(bootstrap) {
+		t.logger.WithField("bootstrap_nodes", unreached).Warn("No connection was opened to any boot node or static peer")
+	}
+
+	// Returning versus new peers tells a turning network apart from the same peers cycling
+	overlap := t.comparePreviousRuns(peers)
+
+	// What to run with next time, from the measurements above
+	recommendations := recommend.Recommend(recommend.Inputs{
+		MaxPeers:              cmp.Or(primary.MaxPeers, t.config.GetMaxPeers()),
+		MaxPeersRamp:          t.config.GetMaxPeersRamp(),
+		DialConcurrency:       cmp.Or(primary.DialConcurrency, t.config.GetDialConcurrency()),
+		ScoreSnapshotInterval: constants.DefaultLibp2pPeerscoreFreq,
+		Subnets:               primary.SubnetConfigs(t.config.GetSubnets()),
+		Start:                 t.startTime,
+		End:                   endTime,
+		Peers:                 peer.GeneralPeers(peers),
+		Pressure:              pressure,
+		Ramp:                  ramp,
+		Resources:             resourceSummary,
+		Topics:                topicHealth,
+	})
+
+	// Keep full timelines for the sampled peers only, the counts of the others are in the event totals
+	timeline := t.timeline.Snapshot()
+
+	var sampling *peer.SamplingSummary
+
+	if t.config.IsDetailSampled() {
+		sampling = peer.SummarizeSampling(peers, t.config.GetDetailSampleRate(), t.config.GetDetailSampleSeed())
+		timeline.Retain(func(peerID string) bool {
+			stats, exists := peers[peerID]
+
+			return exists && stats.CapturesDetail()
+		})
+	}
+
+	// Counts over the recorded events of sampled types scale by each type's factor
+	eventSampling := peer.SummarizeEventSampling(peers, t.config.GetEventSampleRates())
 
 	// Convert peers to map[string]interface{} for report
 	peerData := make(map[string]interface{})
@@ -147,6 +1071,8 @@ func (t *DefaultTool) GenerateReport() (*Report, error) {
 	report := &Report{
 		Config:               t.config,
 		ValidationMode:       string(t.config.GetValidationMode()),
+		AgentVersion:         t.config.GetAgentVersion(),
+		MeshDegree:           primary.MeshDegree(t.config.GetMeshDegree()),
 		Timestamp:            endTime,
 		StartTime:            t.startTime,
 		EndTime:              endTime,
@@ -156,12 +1082,48 @@ func (t *DefaultTool) GenerateReport() (*Report, error) {
 		FailedHandshakes:     connectionStats.FailedHandshakes,
 		Peers:                peerData,
 		PeerEventCounts:      eventCounts,
+		ReconciledHandshakes: &reconciled,
+		DataQuality:          &dataQuality,
+		Reachability:         reachabilityResult,
+		Subscriptions:        subscriptions,
+		BeaconPeers:          beaconPeersResult,
+		BeaconFetches:        beaconFetches,
+		BeaconSync:           beaconSync,
+		Canary:               canaryResult,
+		ScoreConsensus:       scoreConsensus,
+		ClockSkew:            clockSkew,
+		Sampling:             sampling,
+		EventSampling:        eventSampling,
+		PeerPressure:         pressure,
+		Resources:            resourceSummary,
+		SessionSurvival:      survival,
+		Shutdown:             shutdown,
+		TopicHealth:          topicHealth,
+		InvalidDeliveries:    invalidDeliveries,
+		RouterMetrics:        router,
+		StatusTracking:       statusTracking,
+		HeadDivergence:       headDivergence,
+		EventTimeline:        timeline,
+		Phases:               t.phases,
+		Gaps:                 t.gaps,
+		Starvation:           t.starvation,
+		TimeSlices:           timeSlices,
+		Epochs:               epochs,
+		TopicWhitelist:       t.eventMgr.TopicWhitelist(),
+		PeerOverlap:          overlap,
+		NegotiationFailures:  negotiation,
+		BootstrapNodes:       bootstrap,
+		MaxPeersRamp:         ramp,
+		Hosts:                t.summarizeHosts(peers),
+		Recommendations:      recommendations,
 	}
 
 	t.logger.WithFields(logrus.Fields{
 		"total_connections":     connectionStats.TotalConnections,
 		"successful_handshakes": connectionStats.SuccessfulHandshakes,
 		"failed_handshakes":     connectionStats.FailedHandshakes,
+		"reconciled_episodes":   reconciled.Episodes,
+		"out_of_order_events":   dataQuality.OutOfOrderEvents,
 		"unique_peers":          len(peers),
 		"test_duration":         duration,
 	}).Info("Report generation complete")
@@ -169,6 +1131,93 @@ func (t *DefaultTool) GenerateReport() (*Report, error) {
 	return report, nil
 }
 
+// comparePreviousRuns sets the run's peers against those of the configured earlier reports.
+// Unreadable reports are skipped, so a missing archive never fails the run.
+func (t *DefaultTool) comparePreviousRuns(peers map[string]*peer.Stats) *peer.PeerOverlap {
+	patterns := t.config.GetPreviousReports()
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	runs := make([]*peer.PreviousRun, 0)
+	loaded := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			t.logger.WithError(err).WithField("pattern", pattern).Warn("Invalid previous report pattern")
+
+			continue
+		}
+
+		if len(files) == 0 {
+			t.logger.WithField("pattern", pattern).Warn("No previous reports matched")
+		}
+
+		for _, file := range files {
+			if loaded[file] {
+				continue
+			}
+
+			loaded[file] = true
+
+			run, err := peer.LoadPreviousRun(file)
+			if err != nil {
+				t.logger.WithError(err).WithField("file", file).Warn("Skipping previous report")
+
+				continue
+			}
+
+			runs = append(runs, run)
+		}
+	}
+
+	overlap := peer.CalculatePeerOverlap(peer.GeneralPeers(peers), runs)
+	if overlap != nil {
+		t.logger.WithFields(logrus.Fields{
+			"runs":           len(overlap.Runs),
+			"peers":          overlap.Peers,
+			"returning":      overlap.Returning,
+			"new":            overlap.New,
+			"latest_jaccard": overlap.Runs[0].Jaccard,
+		}).Info("Compared peer set with previous runs")
+	}
+
+	return overlap
+}
+
+// primaryHost returns the primary host's spec, or an empty one when a single host runs. The
+// report's statistics cover the primary host, so they are judged against its settings.
+func (t *DefaultTool) primaryHost() config.HostSpec {
+	if hosts := t.config.GetHosts(); len(hosts) > 0 {
+		return hosts[0]
+	}
+
+	return config.HostSpec{}
+}
+
+// summarizeHosts builds per-host report sections when several hosts were run.
+func (t *DefaultTool) summarizeHosts(primaryPeers map[string]*peer.Stats) []peer.HostSummary {
+	hosts := t.config.GetHosts()
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	hostPeers := []peer.HostPeers{{
+		Label:      hosts[0].Label,
+		Libp2pPort: hosts[0].Libp2pPort,
+		Devp2pPort: hosts[0].Devp2pPort,
+		Settings:   hosts[0].Settings(),
+		Peers:      primaryPeers,
+	}}
+
+	for _, collector := range t.extraHosts {
+		hostPeers = append(hostPeers, collector.hostPeers())
+	}
+
+	return peer.SummarizeHosts(hostPeers, t.phases)
+}
+
 // GetLogger returns the tool's logger.
 func (t *DefaultTool) GetLogger() logrus.FieldLogger {
 	return t.logger
@@ -179,54 +1228,114 @@ func (t *DefaultTool) GetConfig() Config {
 	return t.config
 }
 
+// OnEvent attaches a custom handler for a trace event type of the primary host, or for every
+// event with AllEvents, and returns the name its statistics are reported under. Hooks run
+// inline alongside the built-in handlers, so they should return quickly. A hook's errors and
+// panics are counted and logged, they never stop the event from being processed. Hooks should
+// be attached before Start to see every event.
+func (t *DefaultTool) OnEvent(eventType string, handler EventHook) (string, error) {
+	name, err := t.eventMgr.AddHook(eventType, handler)
+	if err != nil {
+		return "", fmt.Errorf("failed to attach event hook: %w", err)
+	}
+
+	return name, nil
+}
+
 // handleEvent processes events from Hermes.
 func (t *DefaultTool) handleEvent(ctx context.Context, event interface{}) error {
 	// This will be called by the Hermes controller when events are received
 	if hermesEvent, ok := event.(*host.TraceEvent); ok {
+		if t.watchdog != nil {
+			t.watchdog.Touch()
+		}
+
 		// Pass event to event manager for processing
-		return t.eventMgr.HandleEvent(ctx, hermesEvent)
+		err := t.eventMgr.HandleEvent(ctx, hermesEvent)
+		if err != nil {
+			t.errBudget.Record(reports.ErrorCategoryEvents)
+		}
+
+		return err
 	}
 
 	return fmt.Errorf("unsupported event type: %T", event)
 }
 
-// startStatusReporting provides periodic updates on peer connection status.
+// startStatusReporting provides periodic updates on peer connection status every
+// --status-interval. Progress events go out every interval, the log line backs off while the
+// peer counts stay the same.
 func (t *DefaultTool) startStatusReporting(ctx context.Context) {
-	ticker := time.NewTicker(15 * time.Second)
+	interval := t.config.GetStatusInterval()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	backoff := newStatusBackoff(constants.StatusLogMaxBackoff)
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			t.logCurrentStatus()
+			t.logCurrentStatus(backoff)
 		}
 	}
 }
 
-// logCurrentStatus logs the current peer connection statistics.
-func (t *DefaultTool) logCurrentStatus() {
+// logCurrentStatus logs the current peer connection statistics when backoff lets it, and
+// emits them as a progress event.
+func (t *DefaultTool) logCurrentStatus(backoff *statusBackoff) {
 	peers := t.peerRepo.GetAllPeers()
-	peerCount := len(peers)
+	diagnostics := diagnosePeers(peers)
 
-	// Count active peers manually
-	activeCount := 0
+	if backoff.due(diagnostics) {
+		t.logger.WithFields(logrus.Fields{
+			"peer_count":   diagnostics.KnownPeers,
+			"active_peers": diagnostics.ConnectedPeers,
+		}).Info("Status report")
+	}
+
+	if t.progress == nil {
+		return
+	}
+
+	now := t.clock()
+	connection := peer.NewStatsCalculator().CalculateConnectionStats(peers)
 
+	t.progress.Emit(progress.Event{
+		Event:      progress.EventStatus,
+		Phase:      t.phases.PhaseAt(now),
+		ETASeconds: progress.Seconds(t.phases.CooldownEnd.Sub(now)),
+		Peers:      &progress.Peers{Known: diagnostics.KnownPeers, Connected: diagnostics.ConnectedPeers},
+		Handshakes: &progress.Handshakes{Successful: connection.SuccessfulHandshakes, Failed: connection.FailedHandshakes},
+	})
+}
+
+// connectionDiagnostics counts the peers seen so far and those with an open session.
+func (t *DefaultTool) connectionDiagnostics() watchdog.Diagnostics {
+	return diagnosePeers(t.peerRepo.GetAllPeers())
+}
+
+// diagnosePeers counts the peers and those with an open session.
+func diagnosePeers(peers map[string]*peer.Stats) watchdog.Diagnostics {
+	diagnostics := watchdog.Diagnostics{KnownPeers: len(peers)}
+
+	// Count active peers manually
 	for _, peer := range peers {
 		for _, session := range peer.ConnectionSessions {
 			if !session.Disconnected {
-				activeCount++
+				diagnostics.ConnectedPeers++
 
 				break
 			}
 		}
 	}
 
-	t.logger.WithFields(logrus.Fields{
-		"peer_count":   peerCount,
-		"active_peers": activeCount,
-	}).Info("Status report")
+	return diagnostics
 }
 
 func (t *DefaultTool) GetPeer(peerID string) (interface{}, bool) {
@@ -261,25 +1370,48 @@ func (t *DefaultTool) IncrementMessageCount(peerID string) {
 	}
 }
 
-// SaveReports generates and saves both JSON and HTML reports.
-func (t *DefaultTool) SaveReports() error {
-	report, err := t.GenerateReport()
+func (t *DefaultTool) GetLateEventGrace() time.Duration {
+	return t.config.GetLateEventGrace()
+}
+
+func (t *DefaultTool) GetEventLogLevel() logrus.Level {
+	return t.logSampler.Level()
+}
+
+func (t *DefaultTool) IsBackendDegraded(at time.Time) bool {
+	return t.beaconSync != nil && t.beaconSync.Degraded(at)
+}
+
+func (t *DefaultTool) GetEventSampleRate(eventType string) int {
+	return t.config.GetEventSampleRates()[eventType]
+}
+
+// SaveReports generates and saves both JSON and HTML reports. Cancelling ctx stops generation
+// between stages, reports already complete are kept and incomplete ones are marked partial.
+// Once reports are being written, the run manifest and health summary follow whether or not
+// all of them could be.
+func (t *DefaultTool) SaveReports(ctx context.Context) (err error) {
+	report, err := t.GenerateReport(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to generate report: %w", err)
 	}
 
+	t.progress.Emit(progress.Event{Event: progress.EventReport, Stage: "analysis"})
+
 	// Get validation config details for the report
 	validationConfigs := config.GetValidationConfigs()
 	validationConfig := validationConfigs[t.config.GetValidationMode()]
 
 	// Convert to reports package format
 	reportsReport := &reports.Report{
-		Config:         report.Config,
+		Config:         t.config.Redacted(),
 		ValidationMode: report.ValidationMode,
 		ValidationConfig: map[string]interface{}{
 			"mode":          string(t.config.GetValidationMode()),
 			"HermesVersion": validationConfig.HermesVersion,
 		},
+		AgentVersion:         report.AgentVersion,
+		MeshDegree:           &report.MeshDegree,
 		Timestamp:            report.Timestamp,
 		StartTime:            report.StartTime,
 		EndTime:              report.EndTime,
@@ -289,6 +1421,67 @@ func (t *DefaultTool) SaveReports() error {
 		FailedHandshakes:     report.FailedHandshakes,
 		Peers:                report.Peers,
 		PeerEventCounts:      report.PeerEventCounts,
+		ReconciledHandshakes: report.ReconciledHandshakes,
+		DataQuality:          report.DataQuality,
+		Reachability:         report.Reachability,
+		Subscriptions:        report.Subscriptions,
+		BeaconPeers:          report.BeaconPeers,
+		BeaconFetches:        report.BeaconFetches,
+		BeaconSync:           report.BeaconSync,
+		Canary:               report.Canary,
+		ScoreConsensus:       report.ScoreConsensus,
+		ClockSkew:            report.ClockSkew,
+		Sampling:             report.Sampling,
+		EventSampling:        report.EventSampling,
+		PeerPressure:         report.PeerPressure,
+		Resources:            report.Resources,
+		SessionSurvival:      report.SessionSurvival,
+		Shutdown:             report.Shutdown,
+		TopicHealth:          report.TopicHealth,
+		InvalidDeliveries:    report.InvalidDeliveries,
+		RouterMetrics:        report.RouterMetrics,
+		StatusTracking:       report.StatusTracking,
+		HeadDivergence:       report.HeadDivergence,
+		EventTimeline:        report.EventTimeline,
+		Phases:               report.Phases,
+		Gaps:                 report.Gaps,
+		Starvation:           report.Starvation,
+		TimeSlices:           report.TimeSlices,
+		Epochs:               report.Epochs,
+		TopicWhitelist:       report.TopicWhitelist,
+		PeerOverlap:          report.PeerOverlap,
+		NegotiationFailures:  report.NegotiationFailures,
+		BootstrapNodes:       report.BootstrapNodes,
+		MaxPeersRamp:         report.MaxPeersRamp,
+		Hosts:                report.Hosts,
+		Recommendations:      report.Recommendations,
+	}
+
+	// The error journal is written alongside the run, list it with the reports
+	if path := t.config.GetErrorJournal(); path != "" {
+		t.reportGen.AddArtifact(reports.ArtifactErrors, path)
+	}
+
+	// So is the traced peer's record
+	if t.config.GetTracePeer() != "" {
+		t.reportGen.AddArtifact(reports.ArtifactPeerTrace, t.config.GetTracePeerFile())
+	}
+
+	// The manifest lists everything written below and grades the run, so it comes last
+	defer func() {
+		if merr := t.saveManifest(reportsReport, err); merr != nil && err == nil {
+			err = merr
+		}
+	}()
+
+	// Custom analyzers read the report as it will be written and add their sections to it
+	if err := t.reportGen.RunAnalyzers(ctx, reportsReport); err != nil {
+		return fmt.Errorf("failed to run custom analyzers: %w", err)
+	}
+
+	// A smoke test only checks that the pipeline works end to end, the lite artifacts show that
+	if t.config.IsSmoke() {
+		return t.saveSmokeReports(report, reportsReport)
 	}
 
 	// Save JSON report
@@ -297,6 +1490,36 @@ func (t *DefaultTool) SaveReports() error {
 		return fmt.Errorf("failed to save JSON report: %w", err)
 	}
 
+	// The lite report is what bots and CI read, so it is written for every run
+	liteFile, err := t.reportGen.GenerateLiteJSON(reportsReport)
+	if err != nil {
+		return fmt.Errorf("failed to save lite JSON report: %w", err)
+	}
+
+	// The markdown summary is what people read, in commit and pull request comments
+	markdownFile, err := t.reportGen.GenerateMarkdownSummary(reportsReport)
+	if err != nil {
+		return fmt.Errorf("failed to save markdown summary: %w", err)
+	}
+
+	// Grafana dashboards read the latest run's key metrics from a file at a fixed name
+	grafanaFile, err := t.reportGen.GenerateGrafanaJSON(reportsReport)
+	if err != nil {
+		return fmt.Errorf("failed to save Grafana JSON report: %w", err)
+	}
+
+	// The OpenMetrics snapshot is for a Pushgateway or the monitoring stack's textfile collector
+	metricsFile, err := t.reportGen.GenerateOpenMetrics(reportsReport)
+	if err != nil {
+		return fmt.Errorf("failed to save OpenMetrics snapshot: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("report generation cancelled after the JSON reports %s and %s: %w", jsonFile, liteFile, err)
+	}
+
+	t.progress.Emit(progress.Event{Event: progress.EventReport, Stage: "json"})
+
 	// Check for AI analysis API key
 	apiKey := t.config.GetClaudeAPIKey()
 	if apiKey == "" {
@@ -310,19 +1533,169 @@ func (t *DefaultTool) SaveReports() error {
 	if apiKey != "" && !t.config.IsSkipAI() {
 		t.logger.Info("Including AI analysis in HTML report")
 
-		htmlFile, err = t.reportGen.GenerateHTMLWithAI(reportsReport, apiKey)
+		htmlFile, err = t.reportGen.GenerateHTMLWithAI(ctx, reportsReport, apiKey)
 	} else {
-		htmlFile, err = t.reportGen.GenerateHTML(reportsReport)
+		htmlFile, err = t.reportGen.GenerateHTML(ctx, reportsReport)
 	}
 
 	if err != nil {
 		return fmt.Errorf("failed to save HTML report: %w", err)
 	}
 
+	t.progress.Emit(progress.Event{Event: progress.EventReport, Stage: "html"})
+
 	t.logger.WithFields(logrus.Fields{
-		"json_file": jsonFile,
-		"html_file": htmlFile,
+		"json_file":     jsonFile,
+		"lite_file":     liteFile,
+		"markdown_file": markdownFile,
+		"grafana_file":  grafanaFile,
+		"metrics_file":  metricsFile,
+		"html_file":     htmlFile,
 	}).Info("Reports saved successfully")
 
+	t.removeCheckpoint(report)
+
+	// Publish summary metrics, failures must not lose the reports already written
+	if publishURL := t.config.GetPublishURL(); publishURL != "" {
+		if err := t.publishSummary(publishURL, reportsReport, validationConfig.HermesVersion); err != nil {
+			t.logger.WithError(err).Warn("Failed to publish summary metrics")
+			t.errBudget.Record(reports.ErrorCategoryPublish)
+		}
+	}
+
+	// Compare against the baseline run, alerting must not fail the run either
+	if baselineJSON := t.config.GetBaselineJSON(); baselineJSON != "" {
+		if err := t.checkRegressions(baselineJSON, reportsReport, validationConfig.HermesVersion, jsonFile, htmlFile); err != nil {
+			t.logger.WithError(err).Warn("Failed to check for regressions")
+			t.errBudget.Record(reports.ErrorCategoryRegression)
+		}
+	}
+
+	return nil
+}
+
+// removeCheckpoint removes the checkpoint of a completed run, which has nothing left to
+// resume. Interrupted runs keep their checkpoint.
+func (t *DefaultTool) removeCheckpoint(report *Report) {
+	if t.config.GetCheckpointInterval() <= 0 || report.Phases == nil || report.Phases.Interrupted {
+		return
+	}
+
+	if err := os.Remove(t.config.GetCheckpointFile()); err != nil && !os.IsNotExist(err) {
+		t.logger.WithError(err).Warn("Failed to remove checkpoint")
+		t.errBudget.Record(reports.ErrorCategoryCheckpoint)
+	}
+}
+
+// saveManifest writes the run manifest, graded by the run's health and runErr, and prints the
+// health summary.
+func (t *DefaultTool) saveManifest(report *reports.Report, runErr error) error {
+	manifest, filename, err := t.reportGen.GenerateManifest(report, runErr)
+	if err != nil {
+		return fmt.Errorf("failed to save run manifest: %w", err)
+	}
+
+	if err := reports.WriteRunSummary(t.summaryOut, manifest, filename); err != nil {
+		t.logger.WithError(err).Warn("Failed to print run health summary")
+	}
+
 	return nil
 }
+
+// checkRegressions compares the run against a baseline report and, when configured,
+// files a GitHub issue for severe regressions. A baseline from another Hermes version
+// also gets the Hermes regression report, one from another validation mode is refused.
+func (t *DefaultTool) checkRegressions(baselineJSON string, report *reports.Report, hermesVersion, jsonFile, htmlFile string) error {
+	baseline, err := reports.LoadRunSummary(baselineJSON)
+	if err != nil {
+		return err
+	}
+
+	current := reports.NewRunSummary(report, hermesVersion)
+
+	comparison := alerting.Compare(baseline, current, alerting.Thresholds{
+		HandshakeSuccessDrop: t.config.GetRegressionThreshold(),
+		UniquePeersDrop:      constants.DefaultUniquePeersRegressionThreshold,
+		ConnectionsDrop:      constants.DefaultConnectionsRegressionThreshold,
+		MeshAdoptionDrop:     constants.DefaultMeshAdoptionRegressionThreshold,
+		TimeToFirstScoreRise: constants.DefaultTimeToFirstScoreRegressionThreshold,
+		PruneRateRise:        constants.DefaultPruneRateRegressionThreshold,
+	})
+
+	if comparison.ModeChanged {
+		return fmt.Errorf("baseline ran in %s validation mode and this run in %s, not comparing them", baseline.ValidationMode, current.ValidationMode)
+	}
+
+	var hermesFile string
+
+	if comparison.HermesChanged {
+		t.logger.WithFields(logrus.Fields{
+			"baseline_hermes": baseline.HermesVersion,
+			"current_hermes":  current.HermesVersion,
+		}).Info("Baseline used a different Hermes version, comparing Hermes sensitive metrics")
+
+		hermesFile, err = t.reportGen.GenerateHermesRegression(comparison, baselineJSON, report.Timestamp)
+		if err != nil {
+			t.logger.WithError(err).Warn("Failed to generate Hermes regression report")
+			t.errBudget.Record(reports.ErrorCategoryReport)
+		}
+	}
+
+	if !comparison.HasRegressions() {
+		t.logger.WithField("baseline", baselineJSON).Info("No regressions against baseline")
+
+		return nil
+	}
+
+	for _, regression := range comparison.Regressions {
+		t.logger.WithFields(logrus.Fields{
+			"metric":   regression.Metric,
+			"baseline": regression.Baseline,
+			"current":  regression.Current,
+			"change":   alerting.FormatChange(regression.Metric, regression.Drop),
+		}).Warn("Regression against baseline")
+	}
+
+	repo := t.config.GetAlertGitHubRepo()
+	if repo == "" {
+		return nil
+	}
+
+	token := os.Getenv(constants.GitHubTokenEnv)
+	if token == "" {
+		return fmt.Errorf("%s is not set, cannot open regression issue", constants.GitHubTokenEnv)
+	}
+
+	reporter := alerting.NewGitHubIssueReporter(repo, token, []string{constants.DefaultRegressionIssueLabel}, constants.DefaultAlertTimeout, t.logger)
+
+	return reporter.Report(context.Background(), comparison, alerting.Artifacts{
+		JSONReport:   jsonFile,
+		HTMLReport:   htmlFile,
+		HermesReport: hermesFile,
+		BaseURL:      t.config.GetArtifactBaseURL(),
+	})
+}
+
+// publishSummary maps the report to summary metrics and sends them to the configured endpoint.
+func (t *DefaultTool) publishSummary(publishURL string, report *reports.Report, hermesVersion string) error {
+	peers := make(map[string]*peer.Stats, len(report.Peers))
+
+	for peerID, peerData := range report.Peers {
+		if peerStats, ok := peerData.(*peer.Stats); ok {
+			peers[peerID] = peerStats
+		}
+	}
+
+	event := publish.BuildSummaryEvent(publish.RunInfo{
+		Network:        t.config.GetNetwork(),
+		ValidationMode: report.ValidationMode,
+		HermesVersion:  hermesVersion,
+		AgentVersion:   report.AgentVersion,
+		StartTime:      report.StartTime,
+		EndTime:        report.EndTime,
+	}, reports.CalculateHeadline(report), peers)
+
+	publisher := publish.NewHTTPPublisher(publishURL, constants.DefaultPublishTimeout, t.logger)
+
+	return publisher.Publish(context.Background(), event)
+}
@@ -0,0 +1,211 @@
+package core
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p"
+	"github.com/OffchainLabs/prysm/v6/config/params"
+	"github.com/OffchainLabs/prysm/v6/consensus-types/primitives"
+	"github.com/probe-lab/hermes/eth"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// gossipDataColumnSidecarMessage is the Fulu data column sidecar topic, which the pinned
+// Prysm version has no constant for.
+const gossipDataColumnSidecarMessage = "data_column_sidecar"
+
+// hermesBaseTopics are the topics Hermes subscribes to when no subscription topics are set.
+// It mirrors the unexported list in the pinned Hermes version.
+var hermesBaseTopics = []string{
+	p2p.GossipBlockMessage,
+	p2p.GossipAggregateAndProofMessage,
+	p2p.GossipAttestationMessage,
+	p2p.GossipAttesterSlashingMessage,
+	p2p.GossipProposerSlashingMessage,
+	p2p.GossipContributionAndProofMessage,
+	p2p.GossipSyncCommitteeMessage,
+	p2p.GossipBlsToExecutionChangeMessage,
+	p2p.GossipBlobSidecarMessage,
+}
+
+// hermesKnownTopics are the topics the pinned Hermes version can build a topic string for,
+// others are skipped with a warning when it subscribes.
+var hermesKnownTopics = map[string]bool{
+	p2p.GossipBlockMessage:                true,
+	p2p.GossipAggregateAndProofMessage:    true,
+	p2p.GossipAttestationMessage:          true,
+	p2p.GossipExitMessage:                 true,
+	p2p.GossipAttesterSlashingMessage:     true,
+	p2p.GossipProposerSlashingMessage:     true,
+	p2p.GossipContributionAndProofMessage: true,
+	p2p.GossipSyncCommitteeMessage:        true,
+	p2p.GossipBlsToExecutionChangeMessage: true,
+	p2p.GossipBlobSidecarMessage:          true,
+}
+
+// forkAt returns the name of the fork active at the given epoch.
+func forkAt(cfg *params.BeaconChainConfig, epoch primitives.Epoch) string {
+	switch {
+	case epoch >= cfg.FuluForkEpoch:
+		return "fulu"
+	case epoch >= cfg.ElectraForkEpoch:
+		return "electra"
+	case epoch >= cfg.DenebForkEpoch:
+		return "deneb"
+	case epoch >= cfg.CapellaForkEpoch:
+		return "capella"
+	case epoch >= cfg.BellatrixForkEpoch:
+		return "bellatrix"
+	case epoch >= cfg.AltairForkEpoch:
+		return "altair"
+	default:
+		return "phase0"
+	}
+}
+
+// expectedTopics returns the gossip topics Hermes should subscribe to in the given epoch.
+// Voluntary exits are left out on purpose, Hermes cannot validate them. Subnet counts
+// follow the active fork, so a node still on the pre-Electra blob subnet count is caught.
+func expectedTopics(cfg *params.BeaconChainConfig, epoch primitives.Epoch, forkDigest [4]byte, subnets map[string]*eth.SubnetConfig) *peer.TopicExpectations {
+	fork := forkAt(cfg, epoch)
+
+	topics := []peer.TopicExpectation{
+		{Name: p2p.GossipBlockMessage},
+		{Name: p2p.GossipAggregateAndProofMessage},
+		{Name: p2p.GossipAttestationMessage, Subnets: subnetCount(subnets[p2p.GossipAttestationMessage], cfg.AttestationSubnetCount)},
+		{Name: p2p.GossipAttesterSlashingMessage},
+		{Name: p2p.GossipProposerSlashingMessage},
+	}
+
+	if epoch >= cfg.AltairForkEpoch {
+		topics = append(topics,
+			peer.TopicExpectation{Name: p2p.GossipContributionAndProofMessage},
+			peer.TopicExpectation{Name: p2p.GossipSyncCommitteeMessage, Subnets: subnetCount(subnets[p2p.GossipSyncCommitteeMessage], cfg.SyncCommitteeSubnetCount)},
+		)
+	}
+
+	if epoch >= cfg.CapellaForkEpoch {
+		topics = append(topics, peer.TopicExpectation{Name: p2p.GossipBlsToExecutionChangeMessage})
+	}
+
+	// Blob sidecars are replaced by data columns in Fulu
+	if epoch >= cfg.DenebForkEpoch && epoch < cfg.FuluForkEpoch {
+		blobSubnets := cfg.BlobsidecarSubnetCount
+		if epoch >= cfg.ElectraForkEpoch {
+			blobSubnets = cfg.BlobsidecarSubnetCountElectra
+		}
+
+		topics = append(topics, peer.TopicExpectation{Name: p2p.GossipBlobSidecarMessage, Subnets: subnetCount(subnets[p2p.GossipBlobSidecarMessage], blobSubnets)})
+	}
+
+	if epoch >= cfg.FuluForkEpoch {
+		topics = append(topics, peer.TopicExpectation{Name: gossipDataColumnSidecarMessage, Subnets: subnetCount(subnets[gossipDataColumnSidecarMessage], cfg.DataColumnSidecarSubnetCount)})
+	}
+
+	return &peer.TopicExpectations{
+		Fork:       fork,
+		ForkDigest: hex.EncodeToString(forkDigest[:]),
+		Topics:     topics,
+	}
+}
+
+// subnetCount returns how many of a topic's subnets the subnet configuration selects.
+func subnetCount(cfg *eth.SubnetConfig, total uint64) int {
+	if cfg == nil {
+		return int(total)
+	}
+
+	switch cfg.Type {
+	case eth.SubnetStatic:
+		return len(cfg.Subnets)
+	case eth.SubnetRandom:
+		return int(min(cfg.Count, total))
+	case eth.SubnetStaticRange:
+		return int(cfg.End - cfg.Start)
+	default:
+		return int(total)
+	}
+}
+
+// hermesBeaconConfig returns the beacon config to hand Hermes for the given epoch. Hermes sizes
+// the blob sidecar subnets from the pre-Electra count whatever the fork, so from Electra on it
+// gets a copy carrying the Electra count instead.
+func hermesBeaconConfig(cfg *params.BeaconChainConfig, epoch primitives.Epoch) *params.BeaconChainConfig {
+	if epoch < cfg.ElectraForkEpoch || cfg.BlobsidecarSubnetCount == cfg.BlobsidecarSubnetCountElectra {
+		return cfg
+	}
+
+	electra := cfg.Copy()
+	electra.BlobsidecarSubnetCount = cfg.BlobsidecarSubnetCountElectra
+
+	return electra
+}
+
+// hermesTopics returns the gossip topics a Hermes node configuration subscribes to, following
+// the same steps as Hermes: its subscription topics or the base topics, without the topics it
+// cannot build, and the subnets of the node's subnet configuration.
+func hermesTopics(cfg *eth.NodeConfig) []peer.TopicExpectation {
+	names := hermesBaseTopics
+	if len(cfg.SubscriptionTopics) > 0 {
+		names = cfg.SubscriptionTopics
+	}
+
+	topics := make([]peer.TopicExpectation, 0, len(names))
+
+	for _, name := range names {
+		if !hermesKnownTopics[name] {
+			continue
+		}
+
+		topic := peer.TopicExpectation{Name: name}
+
+		switch name {
+		case p2p.GossipAttestationMessage:
+			topic.Subnets = subnetCount(cfg.SubnetConfigs[name], cfg.BeaconConfig.AttestationSubnetCount)
+		case p2p.GossipSyncCommitteeMessage:
+			topic.Subnets = subnetCount(cfg.SubnetConfigs[name], cfg.BeaconConfig.SyncCommitteeSubnetCount)
+		case p2p.GossipBlobSidecarMessage:
+			topic.Subnets = subnetCount(cfg.SubnetConfigs[name], cfg.BeaconConfig.BlobsidecarSubnetCount)
+		}
+
+		topics = append(topics, topic)
+	}
+
+	return topics
+}
+
+// checkTopics compares the topics Hermes will subscribe to with those expected for the fork,
+// so a node that cannot follow the fork fails before the run instead of in its report.
+func checkTopics(expected *peer.TopicExpectations, planned []peer.TopicExpectation) error {
+	plannedSubnets := make(map[string]int, len(planned))
+	for _, topic := range planned {
+		plannedSubnets[topic.Name] = max(topic.Subnets, 1)
+	}
+
+	expectedNames := make(map[string]bool, len(expected.Topics))
+	problems := make([]string, 0)
+
+	for _, topic := range expected.Topics {
+		expectedNames[topic.Name] = true
+
+		want := max(topic.Subnets, 1)
+		if got := plannedSubnets[topic.Name]; got != want {
+			problems = append(problems, peer.TopicShortfall{Name: topic.Name, Expected: want, Subscribed: got}.String())
+		}
+	}
+
+	for _, topic := range planned {
+		if !expectedNames[topic.Name] {
+			problems = append(problems, topic.Name+" (not used in "+expected.Fork+")")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("hermes would not subscribe to the gossip topics of the %s fork: %s", expected.Fork, strings.Join(problems, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OffchainLabs/prysm/v6/beacon-chain/p2p"
+	"github.com/OffchainLabs/prysm/v6/config/params"
+	"github.com/OffchainLabs/prysm/v6/consensus-types/primitives"
+	"github.com/probe-lab/hermes/eth"
+)
+
+// forkSchedule returns a mainnet config with Deneb, Electra and Fulu at epochs 10, 20 and 30.
+func forkSchedule() *params.BeaconChainConfig {
+	cfg := params.MainnetConfig().Copy()
+	cfg.AltairForkEpoch = 0
+	cfg.BellatrixForkEpoch = 0
+	cfg.CapellaForkEpoch = 0
+	cfg.DenebForkEpoch = 10
+	cfg.ElectraForkEpoch = 20
+	cfg.FuluForkEpoch = 30
+
+	return cfg
+}
+
+func TestExpectedTopicsFollowForkSchedule(t *testing.T) {
+	cfg := forkSchedule()
+
+	subnets := func(epoch uint64, name string) (int, bool) {
+		for _, topic := range expectedTopics(cfg, primitives.Epoch(epoch), [4]byte{}, nil).Topics {
+			if topic.Name == name {
+				return topic.Subnets, true
+			}
+		}
+
+		return 0, false
+	}
+
+	if count, ok := subnets(15, p2p.GossipBlobSidecarMessage); !ok || count != int(cfg.BlobsidecarSubnetCount) {
+		t.Errorf("Expected %d blob subnets in Deneb, got %d", cfg.BlobsidecarSubnetCount, count)
+	}
+
+	if count, ok := subnets(25, p2p.GossipBlobSidecarMessage); !ok || count != int(cfg.BlobsidecarSubnetCountElectra) {
+		t.Errorf("Expected %d blob subnets in Electra, got %d", cfg.BlobsidecarSubnetCountElectra, count)
+	}
+
+	if _, ok := subnets(35, p2p.GossipBlobSidecarMessage); ok {
+		t.Error("Expected no blob sidecars in Fulu")
+	}
+
+	if count, ok := subnets(35, gossipDataColumnSidecarMessage); !ok || count != int(cfg.DataColumnSidecarSubnetCount) {
+		t.Errorf("Expected %d data column subnets in Fulu, got %d", cfg.DataColumnSidecarSubnetCount, count)
+	}
+}
+
+func TestCheckTopics(t *testing.T) {
+	cfg := forkSchedule()
+	node := &eth.NodeConfig{
+		BeaconConfig:  cfg,
+		SubnetConfigs: map[string]*eth.SubnetConfig{p2p.GossipAttestationMessage: {Type: eth.SubnetRandom, Count: 4}},
+	}
+
+	check := func(epoch uint64) error {
+		expected := expectedTopics(cfg, primitives.Epoch(epoch), [4]byte{}, node.SubnetConfigs)
+		node.BeaconConfig = hermesBeaconConfig(cfg, primitives.Epoch(epoch))
+
+		return checkTopics(expected, hermesTopics(node))
+	}
+
+	if err := check(15); err != nil {
+		t.Errorf("Expected the Deneb topics to match, got %v", err)
+	}
+
+	// Hermes gets the Electra blob subnet count from the config handed to it
+	if err := check(25); err != nil {
+		t.Errorf("Expected the Electra topics to match, got %v", err)
+	}
+
+	if cfg.BlobsidecarSubnetCount == cfg.BlobsidecarSubnetCountElectra {
+		t.Error("Expected the shared config to be left alone")
+	}
+
+	// Hermes cannot subscribe to data columns
+	err := check(35)
+	if err == nil || !strings.Contains(err.Error(), "data_column_sidecar (0 of") || !strings.Contains(err.Error(), "blob_sidecar (not used in fulu)") {
+		t.Errorf("Expected the Fulu topics to mismatch, got %v", err)
+	}
+}
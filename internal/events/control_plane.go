@@ -0,0 +1,47 @@
+package events
+
+import (
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// recordControlPlane counts the gossipsub control messages and full messages exchanged with a
+// peer, and the messages it was first to deliver, against the peer.
+func recordControlPlane(tool common.ToolInterface, peerID string, event *host.TraceEvent) {
+	switch event.Type {
+	case peer.RouterRecvRPC, peer.RouterSendRPC:
+		meta, ok := event.Payload.(*host.RpcMeta)
+		if !ok {
+			return
+		}
+
+		var ihave, iwant, idontwant int
+		if meta.Control != nil {
+			ihave, iwant, idontwant = ihaveIDs(meta.Control), iwantIDs(meta.Control), idontwantIDs(meta.Control)
+		}
+
+		if ihave == 0 && iwant == 0 && idontwant == 0 && len(meta.Messages) == 0 {
+			return
+		}
+
+		tool.UpdateOrCreatePeer(peerID, func(p interface{}) {
+			if peerStats, ok := p.(*peer.Stats); ok {
+				peerStats.RecordControlRPC(event.Type == peer.RouterSendRPC, ihave, iwant, idontwant, len(meta.Messages))
+			}
+		})
+	case peer.RouterDeliver:
+		// Our own messages are delivered from our peer ID, they say nothing about a peer
+		payload, ok := event.Payload.(map[string]interface{})
+		if local, _ := payload["Local"].(bool); !ok || local {
+			return
+		}
+
+		tool.UpdateOrCreatePeer(peerID, func(p interface{}) {
+			if peerStats, ok := p.(*peer.Stats); ok {
+				peerStats.RecordFirstDelivery()
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestRecordControlPlane(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tool := NewMockToolInterface()
+	tool.peers["a"] = &peer.Stats{PeerID: "a"}
+
+	rpc := func(eventType string, meta *host.RpcMeta) *host.TraceEvent {
+		return &host.TraceEvent{Type: eventType, Timestamp: base, Payload: meta}
+	}
+
+	deliver := func(local bool) *host.TraceEvent {
+		return &host.TraceEvent{
+			Type:      peer.RouterDeliver,
+			Timestamp: base,
+			Payload:   map[string]interface{}{"PeerID": "a", "Local": local},
+		}
+	}
+
+	events := []*host.TraceEvent{
+		rpc(peer.RouterSendRPC, &host.RpcMeta{
+			Messages: []host.RpcMetaMsg{{MsgID: "1"}, {MsgID: "2"}},
+			Control:  &host.RpcMetaControl{IHave: []host.RpcControlIHave{{MsgIDs: []string{"3", "4", "5", "6"}}}},
+		}),
+		rpc(peer.RouterRecvRPC, &host.RpcMeta{
+			Messages: []host.RpcMetaMsg{{MsgID: "7"}},
+			Control: &host.RpcMetaControl{
+				IWant:     []host.RpcControlIWant{{MsgIDs: []string{"3", "4"}}},
+				Idontwant: []host.RpcControlIdontWant{{MsgIDs: []string{"8"}}},
+			},
+		}),
+		rpc(peer.RouterRecvRPC, &host.RpcMeta{}), // Subscriptions only
+		deliver(false),
+		deliver(true), // Our own message
+	}
+
+	for _, e := range events {
+		recordControlPlane(tool, "a", e)
+	}
+
+	stats, _ := tool.peers["a"].(*peer.Stats)
+
+	control := stats.ControlPlane
+	if control == nil {
+		t.Fatal("expected control plane counts to be recorded")
+	}
+
+	want := peer.ControlPlaneStats{
+		IHaveSent:         4,
+		IWantReceived:     2,
+		IDontWantReceived: 1,
+		MessagesSent:      2,
+		MessagesReceived:  1,
+		FirstDeliveries:   1,
+		IWantRatio:        0.5,
+		GossipReliance:    1,
+		FirstDeliveryRate: 1,
+	}
+
+	if *control != want {
+		t.Errorf("got %+v, want %+v", *control, want)
+	}
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/probe-lab/hermes/host"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/hermes-peer-score/constants"
 	"github.com/ethpandaops/hermes-peer-score/internal/common"
 	"github.com/ethpandaops/hermes-peer-score/internal/peer"
 )
@@ -33,7 +35,8 @@ func (h *ConnectionHandler) EventType() string {
 // HandleEvent processes a connection event.
 func (h *ConnectionHandler) HandleEvent(ctx context.Context, event *host.TraceEvent) error {
 	peerID := common.GetPeerID(event)
-	now := time.Now()
+	connectedAt := common.GetEventTime(event)
+	link := connectionLink(event)
 
 	h.logger.WithFields(logrus.Fields{
 		"peer_id": common.FormatShortPeerID(peerID),
@@ -44,14 +47,25 @@ func (h *ConnectionHandler) HandleEvent(ctx context.Context, event *host.TraceEv
 	if !exists {
 		// Create new peer
 		h.tool.CreatePeer(peerID)
-		h.logger.WithField("peer_id", common.FormatShortPeerID(peerID)).Info("New peer connection")
+		h.logger.WithField("peer_id", common.FormatShortPeerID(peerID)).Log(h.tool.GetEventLogLevel(), "New peer connection")
 	}
 
-	// Update peer with connection information.
+	// Update peer with connection information, libp2p may report the same connection twice
 	h.tool.UpdatePeer(peerID, func(p interface{}) {
-		if peerStats, ok := p.(*peer.Stats); ok {
-			h.updatePeerConnection(peerStats, now)
+		peerStats, ok := p.(*peer.Stats)
+		if !ok {
+			return
 		}
+
+		if peerStats.IsDuplicateConnection(link.key, connectedAt, constants.ConnectionDedupWindow) {
+			h.logger.WithFields(logrus.Fields{
+				"peer_id": common.FormatShortPeerID(peerID),
+			}).Debug("Dropped duplicate connection event")
+
+			return
+		}
+
+		h.updatePeerConnection(peerStats, connectedAt, link)
 	})
 
 	// Increment connection event count.
@@ -61,13 +75,29 @@ func (h *ConnectionHandler) HandleEvent(ctx context.Context, event *host.TraceEv
 }
 
 // updatePeerConnection updates peer connection information.
-func (h *ConnectionHandler) updatePeerConnection(peerStats *peer.Stats, connectedAt time.Time) {
+func (h *ConnectionHandler) updatePeerConnection(peerStats *peer.Stats, connectedAt time.Time, link linkInfo) {
 	// Update last seen time
 	peerStats.LastSeenAt = &connectedAt
 
+	// Peers are created at processing time, so pull first seen back to the trace time
+	if peerStats.FirstSeenAt == nil || connectedAt.Before(*peerStats.FirstSeenAt) {
+		firstSeen := connectedAt
+		peerStats.FirstSeenAt = &firstSeen
+	}
+
+	// A reconnecting peer is interesting, capture its detail from here on
+	if len(peerStats.ConnectionSessions) > 0 {
+		peerStats.MarkInteresting(peer.SampleReconnected, connectedAt)
+	}
+
 	// Start a new connection session
 	session := peer.ConnectionSession{
 		ConnectedAt:   &connectedAt,
+		Direction:     link.direction,
+		Transport:     link.transport,
+		Muxer:         link.muxer,
+		Security:      link.security,
+		ConnectionKey: link.key,
 		MessageCount:  0,
 		Disconnected:  false,
 		PeerScores:    []peer.PeerScoreSnapshot{},
@@ -84,3 +114,52 @@ func (h *ConnectionHandler) updatePeerConnection(peerStats *peer.Stats, connecte
 		"session_count": len(peerStats.ConnectionSessions),
 	}).Debug("Updated peer connection")
 }
+
+// linkInfo describes how a connection was established.
+type linkInfo struct {
+	direction string
+	transport string
+	muxer     string
+	security  string
+	key       string // Identifies the connection, empty when the payload does not
+}
+
+// connectionLink reads the direction, transport and protocols of a connection. Hermes
+// reports the remote multiaddr, from which the transport and the protocols built into it
+// follow; muxer and security fields are used when the payload carries them. The connection
+// is keyed by its ID when the payload carries one, else by when it was opened.
+func connectionLink(event *host.TraceEvent) linkInfo {
+	remoteAddr := common.GetPayloadString(event, "RemoteMaddrs")
+
+	link := linkInfo{
+		direction: connectionDirection(common.GetPayloadString(event, "Direction")),
+		transport: peer.ClassifyTransport(remoteAddr),
+		key: peer.ConnectionKey(common.GetPayloadString(event, "ConnID"),
+			common.GetPayloadString(event, "Opened"), remoteAddr),
+	}
+
+	link.muxer, link.security = peer.ImpliedProtocols(link.transport)
+
+	if muxer := common.GetPayloadString(event, "StreamMultiplexer"); muxer != "" {
+		link.muxer = muxer
+	}
+
+	if security := common.GetPayloadString(event, "Security"); security != "" {
+		link.security = security
+	}
+
+	return link
+}
+
+// connectionDirection normalises a libp2p connection direction to inbound or outbound,
+// leaving it empty when libp2p did not know it.
+func connectionDirection(direction string) string {
+	switch strings.ToLower(direction) {
+	case peer.DirectionInbound:
+		return peer.DirectionInbound
+	case peer.DirectionOutbound:
+		return peer.DirectionOutbound
+	default:
+		return ""
+	}
+}
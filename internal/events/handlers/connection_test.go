@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// multiaddr stands in for the multiaddr Hermes puts in connection payloads.
+type multiaddr string
+
+func (m multiaddr) String() string {
+	return string(m)
+}
+
+func TestConnectionLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    linkInfo
+	}{
+		{
+			name: "hermes tcp payload",
+			payload: struct {
+				RemotePeer   string
+				RemoteMaddrs interface{ String() string }
+				Direction    string
+			}{RemotePeer: "peer", RemoteMaddrs: multiaddr("/ip4/1.2.3.4/tcp/9000"), Direction: "Inbound"},
+			want: linkInfo{direction: peer.DirectionInbound, transport: peer.TransportTCP},
+		},
+		{
+			name: "hermes quic payload",
+			payload: struct {
+				RemoteMaddrs interface{ String() string }
+				Direction    string
+			}{RemoteMaddrs: multiaddr("/ip4/1.2.3.4/udp/9001/quic-v1"), Direction: "Outbound"},
+			want: linkInfo{direction: peer.DirectionOutbound, transport: peer.TransportQUIC, muxer: "quic", security: "tls"},
+		},
+		{
+			name: "payload with negotiated protocols",
+			payload: map[string]interface{}{
+				"RemoteMaddrs":      "/ip4/1.2.3.4/tcp/9000",
+				"StreamMultiplexer": "/yamux/1.0.0",
+				"Security":          "/noise",
+			},
+			want: linkInfo{transport: peer.TransportTCP, muxer: "/yamux/1.0.0", security: "/noise"},
+		},
+		{
+			name: "payload with a connection id",
+			payload: map[string]interface{}{
+				"RemoteMaddrs": "/ip4/1.2.3.4/tcp/9000",
+				"Opened":       "2025-06-01T12:00:00Z",
+				"ConnID":       "7",
+			},
+			want: linkInfo{transport: peer.TransportTCP, key: "connection_id:7"},
+		},
+		{
+			name: "payload with the open time",
+			payload: map[string]interface{}{
+				"RemoteMaddrs": "/ip4/1.2.3.4/tcp/9000",
+				"Opened":       "2025-06-01T12:00:00Z",
+			},
+			want: linkInfo{transport: peer.TransportTCP, key: "opened:2025-06-01T12:00:00Z|/ip4/1.2.3.4/tcp/9000"},
+		},
+		{
+			name:    "no multiaddr",
+			payload: map[string]interface{}{"RemoteMaddrs": nil},
+			want:    linkInfo{transport: peer.TransportUnknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := connectionLink(&host.TraceEvent{Type: "CONNECTED", Payload: tt.payload})
+			if got != tt.want {
+				t.Errorf("connectionLink() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
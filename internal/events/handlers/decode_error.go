@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/events/parsers"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// DecodeErrorHandler handles gossipsub message rejections, counting those caused by
+// undecodable or malformed payloads against the sending peer.
+type DecodeErrorHandler struct {
+	tool   common.ToolInterface
+	logger logrus.FieldLogger
+	parser *parsers.DefaultParser
+}
+
+// NewDecodeErrorHandler creates a new decode error event handler.
+func NewDecodeErrorHandler(tool common.ToolInterface, logger logrus.FieldLogger) *DecodeErrorHandler {
+	return &DecodeErrorHandler{
+		tool:   tool,
+		logger: logger.WithField("handler", "decode_error"),
+		parser: &parsers.DefaultParser{},
+	}
+}
+
+// EventType returns the event type this handler manages.
+func (h *DecodeErrorHandler) EventType() string {
+	return "REJECT_MESSAGE"
+}
+
+// HandleEvent processes a message rejection event.
+func (h *DecodeErrorHandler) HandleEvent(ctx context.Context, event *host.TraceEvent) error {
+	payload, ok := event.Payload.(map[string]interface{})
+	if !ok {
+		h.logger.WithFields(common.EventFields(event)).Error("failed to convert reject payload to map[string]interface{}")
+
+		return nil
+	}
+
+	peerID := common.GetPeerID(event)
+	if peerID == constants.Unknown {
+		h.logger.WithFields(common.EventFields(event)).Error("reject event missing or invalid peer ID")
+
+		return nil
+	}
+
+	rejectData, err := h.parser.ParseRejectFromMap(payload, common.GetEventTime(event))
+	if err != nil {
+		h.logger.WithFields(common.EventFields(event)).WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Error("failed to parse reject data")
+
+		return nil
+	}
+
+	kind, isDecodeError := peer.ClassifyRejectReason(rejectData.Reason)
+	if !isDecodeError {
+		return nil
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"peer_id": common.FormatShortPeerID(peerID),
+		"topic":   rejectData.Topic,
+		"kind":    kind,
+		"reason":  rejectData.Reason,
+	}).Debug("Processing decode error event")
+
+	h.tool.UpdateOrCreatePeer(peerID, func(p interface{}) {
+		if peerStats, ok := p.(*peer.Stats); ok {
+			peerStats.RecordDecodeError(kind, rejectData.Topic, rejectData.Reason, rejectData.Timestamp)
+			peerStats.MarkInteresting(peer.SampleDecodeError, rejectData.Timestamp)
+		}
+	})
+
+	return nil
+}
@@ -33,14 +33,14 @@ func (h *DisconnectionHandler) EventType() string {
 // HandleEvent processes a disconnection event.
 func (h *DisconnectionHandler) HandleEvent(ctx context.Context, event *host.TraceEvent) error {
 	peerID := common.GetPeerID(event)
-	now := time.Now()
+	disconnectedAt := common.GetEventTime(event)
 
 	h.logger.WithField("peer_id", common.FormatShortPeerID(peerID)).Debug("Processing disconnection event")
 
 	// Check if peer exists
 	_, exists := h.tool.GetPeer(peerID)
 	if !exists {
-		h.logger.WithField("peer_id", common.FormatShortPeerID(peerID)).Warn("Received disconnection event for peer we've never seen")
+		h.logger.WithFields(common.EventFields(event)).WithField("peer_id", common.FormatShortPeerID(peerID)).Warn("Received disconnection event for peer we've never seen")
 
 		return nil
 	}
@@ -48,14 +48,14 @@ func (h *DisconnectionHandler) HandleEvent(ctx context.Context, event *host.Trac
 	// Update peer to mark current session as disconnected.
 	h.tool.UpdatePeer(peerID, func(p interface{}) {
 		if stats, ok := p.(*peer.Stats); ok {
-			h.markSessionDisconnected(stats, now)
+			h.markSessionDisconnected(stats, disconnectedAt)
 		}
 	})
 
 	// Increment disconnection event count
 	h.tool.IncrementEventCount(peerID, "DISCONNECTED")
 
-	h.logger.WithField("peer_id", common.FormatShortPeerID(peerID)).Info("Peer disconnected")
+	h.logger.WithField("peer_id", common.FormatShortPeerID(peerID)).Log(h.tool.GetEventLogLevel(), "Peer disconnected")
 
 	return nil
 }
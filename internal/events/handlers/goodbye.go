@@ -37,22 +37,22 @@ func (h *GoodbyeHandler) EventType() string {
 func (h *GoodbyeHandler) HandleEvent(ctx context.Context, event *host.TraceEvent) error {
 	payload, ok := event.Payload.(map[string]interface{})
 	if !ok {
-		h.logger.Error("failed to convert goodbye payload to map[string]interface{}")
+		h.logger.WithFields(common.EventFields(event)).Error("failed to convert goodbye payload to map[string]interface{}")
 
 		return nil
 	}
 
 	peerID := common.GetPeerID(event)
 	if peerID == constants.Unknown {
-		h.logger.Error("goodbye event missing or invalid peer ID")
+		h.logger.WithFields(common.EventFields(event)).Error("goodbye event missing or invalid peer ID")
 
 		return nil
 	}
 
 	// Parse goodbye data
-	goodbyeData, err := h.parser.ParseGoodbyeFromMap(payload)
+	goodbyeData, err := h.parser.ParseGoodbyeFromMap(payload, common.GetEventTime(event))
 	if err != nil {
-		h.logger.WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Error("failed to parse goodbye data")
+		h.logger.WithFields(common.EventFields(event)).WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Error("failed to parse goodbye data")
 
 		return nil
 	}
@@ -81,56 +81,28 @@ func (h *GoodbyeHandler) HandleEvent(ctx context.Context, event *host.TraceEvent
 
 // addGoodbyeEvent adds a goodbye event to the peer's current session.
 func (h *GoodbyeHandler) addGoodbyeEvent(peerStats *peer.Stats, goodbyeData *parsers.GoodbyeData) {
-	// Find the most recent active session
-	for i := len(peerStats.ConnectionSessions) - 1; i >= 0; i-- {
-		session := &peerStats.ConnectionSessions[i]
-		if !session.Disconnected {
-			// Add goodbye event to this session
-			goodbyeEvent := peer.GoodbyeEvent{
-				Code:      goodbyeData.Code,
-				Reason:    goodbyeData.Reason,
-				Timestamp: goodbyeData.Timestamp,
-			}
-
-			session.GoodbyeEvents = append(session.GoodbyeEvents, goodbyeEvent)
-
-			h.logger.WithFields(logrus.Fields{
-				"peer_id":   common.FormatShortPeerID(peerStats.PeerID),
-				"code":      goodbyeData.Code,
-				"reason":    goodbyeData.Reason,
-				"timestamp": goodbyeData.Timestamp,
-			}).Debug("Added goodbye event")
-
-			return
-		}
-	}
+	peerStats.MarkInteresting(peer.SampleGoodbye, goodbyeData.Timestamp)
 
-	// No active session found, create a new one for this goodbye event.
-	h.logger.WithField("peer_id", common.FormatShortPeerID(peerStats.PeerID)).Debug("Creating new session for goodbye event")
+	// Goodbyes often trail the disconnect, they still belong to the session that just ended
+	session, postDisconnect := peerStats.AssignEvent(h.EventType(), goodbyeData.Timestamp, h.tool.GetLateEventGrace())
+	if session == nil {
+		h.logger.WithField("peer_id", common.FormatShortPeerID(peerStats.PeerID)).Debug("Dropped goodbye arriving after the disconnect grace window")
 
-	now := goodbyeData.Timestamp
-	session := peer.ConnectionSession{
-		ConnectedAt:   &now,
-		Disconnected:  false,
-		PeerScores:    []peer.PeerScoreSnapshot{},
-		GoodbyeEvents: []peer.GoodbyeEvent{},
-		MeshEvents:    []peer.MeshEvent{},
+		return
 	}
 
-	// Add the goodbye event to the new session
-	goodbyeEvent := peer.GoodbyeEvent{
-		Timestamp: goodbyeData.Timestamp,
-		Code:      goodbyeData.Code,
-		Reason:    goodbyeData.Reason,
-	}
-
-	session.GoodbyeEvents = append(session.GoodbyeEvents, goodbyeEvent)
-	peerStats.ConnectionSessions = append(peerStats.ConnectionSessions, session)
+	session.GoodbyeEvents = append(session.GoodbyeEvents, peer.GoodbyeEvent{
+		Code:           goodbyeData.Code,
+		Reason:         goodbyeData.Reason,
+		Timestamp:      goodbyeData.Timestamp,
+		PostDisconnect: postDisconnect,
+	})
 
 	h.logger.WithFields(logrus.Fields{
-		"peer_id":   common.FormatShortPeerID(peerStats.PeerID),
-		"code":      goodbyeData.Code,
-		"reason":    goodbyeData.Reason,
-		"timestamp": goodbyeData.Timestamp,
-	}).Debug("Added goodbye event to new session")
+		"peer_id":         common.FormatShortPeerID(peerStats.PeerID),
+		"code":            goodbyeData.Code,
+		"reason":          goodbyeData.Reason,
+		"timestamp":       goodbyeData.Timestamp,
+		"post_disconnect": postDisconnect,
+	}).Debug("Added goodbye event")
 }
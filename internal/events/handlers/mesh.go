@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"github.com/probe-lab/hermes/host"
 	"github.com/sirupsen/logrus"
@@ -68,22 +69,22 @@ func (h *PruneHandler) HandleEvent(ctx context.Context, event *host.TraceEvent)
 func (h *GraftHandler) handleMeshEvent(event *host.TraceEvent, eventType string) error {
 	payload, ok := event.Payload.(map[string]interface{})
 	if !ok {
-		h.logger.Errorf("failed to convert %s payload to map[string]interface{}", eventType)
+		h.logger.WithFields(common.EventFields(event)).Errorf("failed to convert %s payload to map[string]interface{}", eventType)
 
 		return nil
 	}
 
 	peerID := common.GetPeerID(event)
 	if peerID == constants.Unknown {
-		h.logger.Errorf("%s event missing or invalid peer ID", eventType)
+		h.logger.WithFields(common.EventFields(event)).Errorf("%s event missing or invalid peer ID", eventType)
 
 		return nil
 	}
 
 	// Parse mesh data
-	meshData, err := h.parser.ParseMeshFromMap(payload, eventType)
+	meshData, err := h.parser.ParseMeshFromMap(payload, eventType, common.GetEventTime(event))
 	if err != nil {
-		h.logger.WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Errorf("failed to parse %s data", eventType)
+		h.logger.WithFields(common.EventFields(event)).WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Errorf("failed to parse %s data", eventType)
 
 		return nil
 	}
@@ -99,7 +100,7 @@ func (h *GraftHandler) handleMeshEvent(event *host.TraceEvent, eventType string)
 	// Update or create peer with mesh event
 	h.tool.UpdateOrCreatePeer(peerID, func(p interface{}) {
 		if peerStats, ok := p.(*peer.Stats); ok {
-			addMeshEvent(h.logger, peerStats, meshData)
+			addMeshEvent(h.logger, peerStats, meshData, h.tool.GetLateEventGrace(), h.tool.GetEventSampleRate(eventType))
 		}
 	})
 
@@ -116,22 +117,22 @@ func (h *GraftHandler) handleMeshEvent(event *host.TraceEvent, eventType string)
 func (h *PruneHandler) handleMeshEvent(event *host.TraceEvent, eventType string) error {
 	payload, ok := event.Payload.(map[string]interface{})
 	if !ok {
-		h.logger.Errorf("failed to convert %s payload to map[string]interface{}", eventType)
+		h.logger.WithFields(common.EventFields(event)).Errorf("failed to convert %s payload to map[string]interface{}", eventType)
 
 		return nil
 	}
 
 	peerID := common.GetPeerID(event)
 	if peerID == constants.Unknown {
-		h.logger.Errorf("%s event missing or invalid peer ID", eventType)
+		h.logger.WithFields(common.EventFields(event)).Errorf("%s event missing or invalid peer ID", eventType)
 
 		return nil
 	}
 
 	// Parse mesh data
-	meshData, err := h.parser.ParseMeshFromMap(payload, eventType)
+	meshData, err := h.parser.ParseMeshFromMap(payload, eventType, common.GetEventTime(event))
 	if err != nil {
-		h.logger.WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Errorf("failed to parse %s data", eventType)
+		h.logger.WithFields(common.EventFields(event)).WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Errorf("failed to parse %s data", eventType)
 
 		return nil
 	}
@@ -147,7 +148,7 @@ func (h *PruneHandler) handleMeshEvent(event *host.TraceEvent, eventType string)
 	// Update or create peer with mesh event
 	h.tool.UpdateOrCreatePeer(peerID, func(p interface{}) {
 		if peerStats, ok := p.(*peer.Stats); ok {
-			addMeshEvent(h.logger, peerStats, meshData)
+			addMeshEvent(h.logger, peerStats, meshData, h.tool.GetLateEventGrace(), h.tool.GetEventSampleRate(eventType))
 		}
 	})
 
@@ -160,64 +161,35 @@ func (h *PruneHandler) handleMeshEvent(event *host.TraceEvent, eventType string)
 	return nil
 }
 
-// addMeshEvent adds a mesh event to the peer's current session (shared implementation).
-func addMeshEvent(logger logrus.FieldLogger, peerStats *peer.Stats, meshData *parsers.MeshData) {
-	// Find the most recent active session
-	for i := len(peerStats.ConnectionSessions) - 1; i >= 0; i-- {
-		session := &peerStats.ConnectionSessions[i]
-		if !session.Disconnected {
-			// Add mesh event to this session
-			meshEvent := peer.MeshEvent{
-				Type:      meshData.Type,
-				Direction: meshData.Direction,
-				Topic:     meshData.Topic,
-				Reason:    meshData.Reason,
-				Timestamp: meshData.Timestamp,
-			}
-
-			session.MeshEvents = append(session.MeshEvents, meshEvent)
-
-			logger.WithFields(logrus.Fields{
-				"peer_id":   common.FormatShortPeerID(peerStats.PeerID),
-				"type":      meshData.Type,
-				"direction": meshData.Direction,
-				"topic":     meshData.Topic,
-				"timestamp": meshData.Timestamp,
-			}).Debug("Added mesh event")
-
-			return
-		}
-	}
+// addMeshEvent adds a mesh event to the peer's current session (shared implementation),
+// recording one in rate of the session's events of its type in full.
+func addMeshEvent(logger logrus.FieldLogger, peerStats *peer.Stats, meshData *parsers.MeshData, grace time.Duration, rate int) {
+	session, postDisconnect := peerStats.AssignEvent(meshData.Type, meshData.Timestamp, grace)
+	if session == nil {
+		logger.WithField("peer_id", common.FormatShortPeerID(peerStats.PeerID)).Debugf("Dropped %s arriving after the disconnect grace window", meshData.Type)
 
-	// No active session found, create a new one for this mesh event
-	logger.WithField("peer_id", common.FormatShortPeerID(peerStats.PeerID)).Debug("Creating new session for mesh event")
-
-	now := meshData.Timestamp
-	session := peer.ConnectionSession{
-		ConnectedAt:   &now,
-		Disconnected:  false,
-		PeerScores:    []peer.PeerScoreSnapshot{},
-		GoodbyeEvents: []peer.GoodbyeEvent{},
-		MeshEvents:    []peer.MeshEvent{},
+		return
 	}
 
-	// Add the mesh event to the new session
-	meshEvent := peer.MeshEvent{
-		Timestamp: meshData.Timestamp,
-		Type:      meshData.Type,
-		Topic:     meshData.Topic,
-		Direction: meshData.Direction,
-		Reason:    meshData.Reason,
+	// Peers outside the detail sample keep their sessions but not their mesh events, and
+	// sampled types keep one in rate
+	if peerStats.CapturesDetail() && session.SampleEvent(meshData.Type, rate) {
+		session.MeshEvents = append(session.MeshEvents, peer.MeshEvent{
+			Type:           meshData.Type,
+			Direction:      meshData.Direction,
+			Topic:          meshData.Topic,
+			Reason:         meshData.Reason,
+			Timestamp:      meshData.Timestamp,
+			PostDisconnect: postDisconnect,
+		})
 	}
 
-	session.MeshEvents = append(session.MeshEvents, meshEvent)
-
-	peerStats.ConnectionSessions = append(peerStats.ConnectionSessions, session)
-
 	logger.WithFields(logrus.Fields{
-		"peer_id":   common.FormatShortPeerID(peerStats.PeerID),
-		"type":      meshData.Type,
-		"topic":     meshData.Topic,
-		"timestamp": meshData.Timestamp,
-	}).Debug("Added mesh event to new session")
+		"peer_id":         common.FormatShortPeerID(peerStats.PeerID),
+		"type":            meshData.Type,
+		"direction":       meshData.Direction,
+		"topic":           meshData.Topic,
+		"timestamp":       meshData.Timestamp,
+		"post_disconnect": postDisconnect,
+	}).Debug("Added mesh event")
 }
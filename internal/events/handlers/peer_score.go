@@ -36,22 +36,22 @@ func (h *PeerScoreHandler) EventType() string {
 func (h *PeerScoreHandler) HandleEvent(ctx context.Context, event *host.TraceEvent) error {
 	payload, ok := event.Payload.(map[string]interface{})
 	if !ok {
-		h.logger.Error("failed to convert peer score payload to map[string]interface{}")
+		h.logger.WithFields(common.EventFields(event)).Error("failed to convert peer score payload to map[string]interface{}")
 
 		return nil
 	}
 
 	peerID, ok := payload["PeerID"].(string)
 	if !ok {
-		h.logger.Error("peer score event missing or invalid PeerID")
+		h.logger.WithFields(common.EventFields(event)).Error("peer score event missing or invalid PeerID")
 
 		return nil
 	}
 
 	// Parse the peer score data
-	scoreData, err := h.parser.ParsePeerScoreFromMap(payload)
+	scoreData, err := h.parser.ParsePeerScoreFromMap(payload, common.GetEventTime(event))
 	if err != nil {
-		h.logger.WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Error("failed to parse peer score data")
+		h.logger.WithFields(common.EventFields(event)).WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Error("failed to parse peer score data")
 
 		return nil
 	}
@@ -79,57 +79,22 @@ func (h *PeerScoreHandler) HandleEvent(ctx context.Context, event *host.TraceEve
 
 // addPeerScore adds a peer score snapshot to the peer's current session.
 func (h *PeerScoreHandler) addPeerScore(peerStats *peer.Stats, scoreData *parsers.PeerScoreData) {
-	// Find the most recent active session
-	for i := len(peerStats.ConnectionSessions) - 1; i >= 0; i-- {
-		session := &peerStats.ConnectionSessions[i]
-		if !session.Disconnected {
-			// Add score to this session
-			scoreSnapshot := peer.PeerScoreSnapshot{
-				Score:              scoreData.Score,
-				Timestamp:          scoreData.Timestamp,
-				AppSpecificScore:   scoreData.AppSpecificScore,
-				IPColocationFactor: scoreData.IPColocationFactor,
-				BehaviourPenalty:   scoreData.BehaviourPenalty,
-				Topics:             make([]peer.TopicScore, 0, len(scoreData.Topics)),
-			}
-
-			// Copy topic scores with full data
-			for _, topicScore := range scoreData.Topics {
-				scoreSnapshot.Topics = append(scoreSnapshot.Topics, peer.TopicScore{
-					Topic:                    topicScore.Topic,
-					TimeInMesh:               topicScore.TimeInMesh,
-					FirstMessageDeliveries:   topicScore.FirstMessageDeliveries,
-					MeshMessageDeliveries:    topicScore.MeshMessageDeliveries,
-					InvalidMessageDeliveries: topicScore.InvalidMessageDeliveries,
-				})
-			}
-
-			session.PeerScores = append(session.PeerScores, scoreSnapshot)
-
-			h.logger.WithFields(logrus.Fields{
-				"peer_id":   common.FormatShortPeerID(peerStats.PeerID),
-				"score":     scoreData.Score,
-				"topics":    len(scoreData.Topics),
-				"timestamp": scoreData.Timestamp,
-			}).Debug("Added peer score snapshot")
-
-			return
-		}
+	// A negative score makes the peer interesting, capturing its detail from here on
+	if scoreData.Score < 0 {
+		peerStats.MarkInteresting(peer.SampleNegativeScore, scoreData.Timestamp)
 	}
 
-	// No active session found, create a new one for this score event.
-	h.logger.WithField("peer_id", common.FormatShortPeerID(peerStats.PeerID)).Debug("Creating new session for peer score event")
+	// Gossipsub keeps scoring peers for a while after they disconnect, those scores are dropped
+	session, postDisconnect := peerStats.AssignEvent(h.EventType(), scoreData.Timestamp, h.tool.GetLateEventGrace())
+	if session == nil {
+		h.logger.WithField("peer_id", common.FormatShortPeerID(peerStats.PeerID)).Debug("Dropped peer score arriving after the disconnect grace window")
 
-	now := scoreData.Timestamp
-	session := peer.ConnectionSession{
-		ConnectedAt:   &now,
-		Disconnected:  false,
-		PeerScores:    []peer.PeerScoreSnapshot{},
-		GoodbyeEvents: []peer.GoodbyeEvent{},
-		MeshEvents:    []peer.MeshEvent{},
+		return
 	}
 
-	// Add the score to the new session
+	// Scores taken while delegated validation's beacon node was degraded may punish the peer for our failures
+	beaconDegraded := h.tool.IsBackendDegraded(scoreData.Timestamp)
+
 	scoreSnapshot := peer.PeerScoreSnapshot{
 		Score:              scoreData.Score,
 		Timestamp:          scoreData.Timestamp,
@@ -137,6 +102,8 @@ func (h *PeerScoreHandler) addPeerScore(peerStats *peer.Stats, scoreData *parser
 		IPColocationFactor: scoreData.IPColocationFactor,
 		BehaviourPenalty:   scoreData.BehaviourPenalty,
 		Topics:             make([]peer.TopicScore, 0, len(scoreData.Topics)),
+		PostDisconnect:     postDisconnect,
+		BeaconDegraded:     beaconDegraded,
 	}
 
 	// Copy topic scores with full data
@@ -150,14 +117,18 @@ func (h *PeerScoreHandler) addPeerScore(peerStats *peer.Stats, scoreData *parser
 		})
 	}
 
-	session.PeerScores = append(session.PeerScores, scoreSnapshot)
-
-	peerStats.ConnectionSessions = append(peerStats.ConnectionSessions, session)
+	// Peers outside the detail sample keep their sessions but not their snapshots, and
+	// sampled snapshots keep one in the rate
+	if peerStats.CapturesDetail() && session.SampleEvent(h.EventType(), h.tool.GetEventSampleRate(h.EventType())) {
+		session.PeerScores = append(session.PeerScores, scoreSnapshot)
+	}
 
 	h.logger.WithFields(logrus.Fields{
-		"peer_id":   common.FormatShortPeerID(peerStats.PeerID),
-		"score":     scoreData.Score,
-		"topics":    len(scoreData.Topics),
-		"timestamp": scoreData.Timestamp,
-	}).Debug("Added peer score snapshot to new session")
+		"peer_id":         common.FormatShortPeerID(peerStats.PeerID),
+		"score":           scoreData.Score,
+		"topics":          len(scoreData.Topics),
+		"timestamp":       scoreData.Timestamp,
+		"post_disconnect": postDisconnect,
+		"beacon_degraded": beaconDegraded,
+	}).Debug("Added peer score snapshot")
 }
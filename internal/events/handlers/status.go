@@ -7,7 +7,9 @@ import (
 	"github.com/probe-lab/hermes/host"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/hermes-peer-score/constants"
 	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/events/parsers"
 	"github.com/ethpandaops/hermes-peer-score/internal/peer"
 )
 
@@ -15,6 +17,7 @@ import (
 type StatusHandler struct {
 	tool   common.ToolInterface
 	logger logrus.FieldLogger
+	parser *parsers.DefaultParser
 }
 
 // NewStatusHandler creates a new status event handler.
@@ -22,6 +25,7 @@ func NewStatusHandler(tool common.ToolInterface, logger logrus.FieldLogger) *Sta
 	return &StatusHandler{
 		tool:   tool,
 		logger: logger.WithField("handler", "status"),
+		parser: &parsers.DefaultParser{},
 	}
 }
 
@@ -34,16 +38,14 @@ func (h *StatusHandler) EventType() string {
 func (h *StatusHandler) HandleEvent(ctx context.Context, event *host.TraceEvent) error {
 	payload, ok := event.Payload.(map[string]interface{})
 	if !ok {
-		h.logger.Error("failed to convert status payload to map[string]interface{}")
+		h.logger.WithFields(common.EventFields(event)).Error("failed to convert status payload to map[string]interface{}")
 
 		return nil
 	}
 
 	peerID, ok := payload["PeerID"].(string)
 	if !ok {
-		h.logger.WithFields(logrus.Fields{
-			"payload": payload,
-		}).Error("status event missing or invalid PeerID")
+		h.logger.WithFields(common.EventFields(event)).WithField("payload", payload).Error("status event missing or invalid PeerID")
 
 		return nil
 	}
@@ -57,10 +59,19 @@ func (h *StatusHandler) HandleEvent(ctx context.Context, event *host.TraceEvent)
 		h.logger.WithField("peer_id", common.FormatShortPeerID(peerID)).Debug("Created peer from status event")
 	}
 
+	status, err := h.parser.ParseStatusFromMap(payload, common.GetEventTime(event))
+	if err != nil {
+		h.logger.WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Debug("Status event carries no status")
+	}
+
 	// Update peer with status information
 	h.tool.UpdatePeer(peerID, func(p interface{}) {
 		if peerStats, ok := p.(*peer.Stats); ok {
 			h.handleStatusUpdate(peerStats, payload, event.Timestamp)
+
+			if status != nil {
+				recordStatus(peerStats, status, false, h.tool.GetLateEventGrace())
+			}
 		}
 	})
 
@@ -81,6 +92,11 @@ func (h *StatusHandler) handleStatusUpdate(peerStats *peer.Stats, payload map[st
 			peerStats.ClientType = clientType
 		}
 
+		// Unrecognised agents are what the client normalizer is taught from
+		if clientType == "unknown" {
+			peerStats.MarkInteresting(peer.SampleUnknownClient, eventTime)
+		}
+
 		if peerStats.ClientAgent == "" {
 			peerStats.ClientAgent = agentVersion
 		}
@@ -92,7 +108,7 @@ func (h *StatusHandler) handleStatusUpdate(peerStats *peer.Stats, payload map[st
 			"peer_id":      common.FormatShortPeerID(peerStats.PeerID),
 			"client_type":  clientType,
 			"client_agent": agentVersion,
-		}).Info("Peer identified")
+		}).Log(h.tool.GetEventLogLevel(), "Peer identified")
 	}
 
 	h.logger.WithFields(logrus.Fields{
@@ -114,3 +130,108 @@ func (h *StatusHandler) setSessionIdentified(peerStats *peer.Stats, eventTime ti
 		currentSession.IdentifiedAt = &eventTime
 	}
 }
+
+// InboundStatusHandler handles the status requests peers send us, which carry their status.
+type InboundStatusHandler struct {
+	tool   common.ToolInterface
+	logger logrus.FieldLogger
+	parser *parsers.DefaultParser
+}
+
+// NewInboundStatusHandler creates a new inbound status event handler.
+func NewInboundStatusHandler(tool common.ToolInterface, logger logrus.FieldLogger) *InboundStatusHandler {
+	return &InboundStatusHandler{
+		tool:   tool,
+		logger: logger.WithField("handler", "inbound_status"),
+		parser: &parsers.DefaultParser{},
+	}
+}
+
+// EventType returns the event type this handler manages.
+func (h *InboundStatusHandler) EventType() string {
+	return "HANDLE_STATUS"
+}
+
+// HandleEvent records the status a peer sent us.
+func (h *InboundStatusHandler) HandleEvent(ctx context.Context, event *host.TraceEvent) error {
+	payload, ok := event.Payload.(map[string]interface{})
+	if !ok {
+		h.logger.WithFields(common.EventFields(event)).Error("failed to convert inbound status payload to map[string]interface{}")
+
+		return nil
+	}
+
+	peerID := common.GetPeerID(event)
+	if peerID == constants.Unknown {
+		h.logger.WithFields(common.EventFields(event)).Error("inbound status event missing or invalid peer ID")
+
+		return nil
+	}
+
+	status, err := h.parser.ParseStatusFromMap(payload, common.GetEventTime(event))
+	if err != nil {
+		h.logger.WithError(err).WithField("peer_id", common.FormatShortPeerID(peerID)).Debug("Inbound status event carries no status")
+
+		return nil
+	}
+
+	h.tool.UpdatePeer(peerID, func(p interface{}) {
+		if peerStats, ok := p.(*peer.Stats); ok {
+			recordStatus(peerStats, status, true, h.tool.GetLateEventGrace())
+		}
+	})
+
+	return nil
+}
+
+// recordStatus adds a status update to the peer's session. Statuses of peers without a
+// session yet are dropped rather than opening one, the handshake status precedes CONNECTED.
+// The answers to our requests are numbered within the session and stamped with the time since
+// connecting, so retries after failed attempts show how long identifying the peer took.
+func recordStatus(peerStats *peer.Stats, status *parsers.StatusData, inbound bool, grace time.Duration) {
+	if len(peerStats.ConnectionSessions) == 0 {
+		return
+	}
+
+	eventType := "REQUEST_STATUS"
+	if inbound {
+		eventType = "HANDLE_STATUS"
+	}
+
+	session, _ := peerStats.AssignEvent(eventType, status.Timestamp, grace)
+	if session == nil {
+		return
+	}
+
+	update := peer.StatusUpdate{
+		Timestamp:      status.Timestamp,
+		Inbound:        inbound,
+		HeadSlot:       status.HeadSlot,
+		FinalizedEpoch: status.FinalizedEpoch,
+		LocalHeadSlot:  status.LocalHeadSlot,
+		Error:          status.Error,
+	}
+
+	if !inbound {
+		update.Attempt = statusRequests(session) + 1
+
+		if session.ConnectedAt != nil {
+			update.LatencyMs = max(float64(status.Timestamp.Sub(*session.ConnectedAt))/float64(time.Millisecond), 0)
+		}
+	}
+
+	session.StatusUpdates = append(session.StatusUpdates, update)
+}
+
+// statusRequests counts the answers to status requests of ours the session already holds.
+func statusRequests(session *peer.ConnectionSession) int {
+	requests := 0
+
+	for _, update := range session.StatusUpdates {
+		if !update.Inbound {
+			requests++
+		}
+	}
+
+	return requests
+}
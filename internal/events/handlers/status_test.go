@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/events/parsers"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestRecordStatus(t *testing.T) {
+	connected := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	parser := &parsers.DefaultParser{}
+
+	events := []struct {
+		payload map[string]interface{}
+		inbound bool
+		after   time.Duration
+	}{
+		{payload: map[string]interface{}{"PeerID": "p", "Error": "stream reset"}, after: 250 * time.Millisecond},
+		{payload: map[string]interface{}{"PeerID": "p", "HeadSlot": uint64(100), "FinalizedEpoch": uint64(2)}, after: 2500 * time.Millisecond},
+		{payload: map[string]interface{}{"PeerID": "p", "HeadSlot": uint64(125), "FinalizedEpoch": uint64(3)}, after: time.Minute},
+		{payload: map[string]interface{}{"PeerID": "p", "Request": map[string]interface{}{"HeadSlot": uint64(130), "FinalizedEpoch": uint64(3)}, "Response": map[string]interface{}{"HeadSlot": uint64(131)}}, inbound: true, after: 2 * time.Minute},
+	}
+
+	want := []peer.StatusUpdate{
+		{Timestamp: connected.Add(250 * time.Millisecond), Error: "stream reset", Attempt: 1, LatencyMs: 250},
+		{Timestamp: connected.Add(2500 * time.Millisecond), HeadSlot: 100, FinalizedEpoch: 2, Attempt: 2, LatencyMs: 2500},
+		{Timestamp: connected.Add(time.Minute), HeadSlot: 125, FinalizedEpoch: 3, Attempt: 3, LatencyMs: 60000},
+		{Timestamp: connected.Add(2 * time.Minute), HeadSlot: 130, FinalizedEpoch: 3, LocalHeadSlot: 131, Inbound: true},
+	}
+
+	stats := &peer.Stats{ConnectionSessions: []peer.ConnectionSession{{ConnectedAt: &connected}}}
+
+	for _, event := range events {
+		status, err := parser.ParseStatusFromMap(event.payload, connected.Add(event.after))
+		if err != nil {
+			t.Fatalf("Expected no error parsing %v, got %v", event.payload, err)
+		}
+
+		recordStatus(stats, status, event.inbound, time.Minute)
+	}
+
+	got := stats.ConnectionSessions[0].StatusUpdates
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d status updates, got %+v", len(want), got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Update %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := parser.ParseStatusFromMap(map[string]interface{}{"PeerID": "p"}, connected); err == nil {
+		t.Error("Expected an error parsing a payload without a status")
+	}
+
+	// The handshake status can precede CONNECTED, it must not open a session
+	fresh := &peer.Stats{}
+	recordStatus(fresh, &parsers.StatusData{Timestamp: connected, HeadSlot: 100}, false, time.Minute)
+
+	if len(fresh.ConnectionSessions) != 0 {
+		t.Errorf("Expected no session opened by a status, got %+v", fresh.ConnectionSessions)
+	}
+}
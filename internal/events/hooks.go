@@ -0,0 +1,158 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// AllEvents is the event type a hook registers for to be called with every trace event.
+const AllEvents = "*"
+
+// HookFunc is a custom handler called with the trace events of the type it was added for.
+type HookFunc func(ctx context.Context, event *host.TraceEvent) error
+
+// hook is a registered custom handler and its call statistics.
+type hook struct {
+	fn      HookFunc
+	stats   peer.EventHookStats
+	totalMs float64
+}
+
+// Hooks calls custom event handlers alongside the built-in ones. Hooks run inline, in
+// registration order, so they should return quickly. Errors and panics are counted and logged
+// per hook, and never stop the event from being processed.
+type Hooks struct {
+	mu      sync.Mutex
+	byType  map[string][]*hook
+	ordered []*hook
+	logger  logrus.FieldLogger
+}
+
+// NewHooks creates an empty set of hooks.
+func NewHooks(logger logrus.FieldLogger) *Hooks {
+	return &Hooks{
+		byType: make(map[string][]*hook),
+		logger: logger.WithField("component", "event_hooks"),
+	}
+}
+
+// Add registers fn for an event type, or for every event with AllEvents, and returns the
+// name its statistics are reported under.
+func (h *Hooks) Add(eventType string, fn HookFunc) (string, error) {
+	if eventType == "" {
+		return "", fmt.Errorf("hook must specify a non-empty event type")
+	}
+
+	if fn == nil {
+		return "", fmt.Errorf("hook for event type %s must not be nil", eventType)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	registered := &hook{
+		fn: fn,
+		stats: peer.EventHookStats{
+			Name:      fmt.Sprintf("%s#%d", eventType, len(h.byType[eventType])+1),
+			EventType: eventType,
+		},
+	}
+
+	h.byType[eventType] = append(h.byType[eventType], registered)
+	h.ordered = append(h.ordered, registered)
+
+	h.logger.WithField("hook", registered.stats.Name).Debug("Registered event hook")
+
+	return registered.stats.Name, nil
+}
+
+// Run calls the hooks registered for the event's type, then those registered for every event.
+func (h *Hooks) Run(ctx context.Context, event *host.TraceEvent) {
+	h.mu.Lock()
+	matched := make([]*hook, 0, len(h.byType[event.Type])+len(h.byType[AllEvents]))
+	matched = append(matched, h.byType[event.Type]...)
+	matched = append(matched, h.byType[AllEvents]...)
+	h.mu.Unlock()
+
+	for _, registered := range matched {
+		started := time.Now()
+		panicked, err := callHook(ctx, registered.fn, event)
+		elapsedMs := float64(time.Since(started)) / float64(time.Millisecond)
+
+		h.mu.Lock()
+		stats := &registered.stats
+		stats.Calls++
+		registered.totalMs += elapsedMs
+		stats.MaxMs = max(stats.MaxMs, elapsedMs)
+
+		// Only the first failure of a hook is logged as a warning, a broken hook fails every call
+		firstFailure := err != nil && stats.Errors+stats.Panics == 0
+
+		if err != nil {
+			stats.LastError = err.Error()
+
+			if panicked {
+				stats.Panics++
+			} else {
+				stats.Errors++
+			}
+		}
+		h.mu.Unlock()
+
+		if err == nil {
+			continue
+		}
+
+		logger := h.logger.WithError(err).WithFields(common.EventFields(event)).WithFields(logrus.Fields{
+			"hook":     registered.stats.Name,
+			"panicked": panicked,
+		})
+
+		if firstFailure {
+			logger.Warn("Event hook failed, the event is still processed")
+		} else {
+			logger.Debug("Event hook failed")
+		}
+	}
+}
+
+// callHook calls fn, turning a panic into an error.
+func callHook(ctx context.Context, fn HookFunc, event *host.TraceEvent) (panicked bool, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("hook panicked: %v", recovered)
+			panicked = true
+		}
+	}()
+
+	return false, fn(ctx, event)
+}
+
+// Stats adds the hooks' call statistics to the data quality statistics.
+func (h *Hooks) Stats(stats *peer.DataQualityStats) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.ordered) == 0 {
+		return
+	}
+
+	stats.EventHooks = make([]peer.EventHookStats, 0, len(h.ordered))
+
+	for _, registered := range h.ordered {
+		entry := registered.stats
+		if entry.Calls > 0 {
+			entry.MeanMs = registered.totalMs / float64(entry.Calls)
+		}
+
+		stats.EventHooks = append(stats.EventHooks, entry)
+	}
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestHooks(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	hooks := NewHooks(logger)
+
+	var seen []string
+
+	register := func(eventType string, fn HookFunc) string {
+		name, err := hooks.Add(eventType, fn)
+		if err != nil {
+			t.Fatalf("Expected no error adding a %s hook, got %v", eventType, err)
+		}
+
+		return name
+	}
+
+	names := []string{
+		register("CONNECTED", func(_ context.Context, event *host.TraceEvent) error {
+			seen = append(seen, "connected:"+event.Type)
+
+			return nil
+		}),
+		register(AllEvents, func(_ context.Context, event *host.TraceEvent) error {
+			seen = append(seen, "all:"+event.Type)
+
+			return errors.New("export failed")
+		}),
+		register("CONNECTED", func(context.Context, *host.TraceEvent) error {
+			panic("broken hook")
+		}),
+	}
+
+	if names[0] != "CONNECTED#1" || names[1] != "*#1" || names[2] != "CONNECTED#2" {
+		t.Errorf("Unexpected hook names %v", names)
+	}
+
+	if _, err := hooks.Add("", func(context.Context, *host.TraceEvent) error { return nil }); err == nil {
+		t.Error("Expected an error adding a hook without an event type")
+	}
+
+	if _, err := hooks.Add("CONNECTED", nil); err == nil {
+		t.Error("Expected an error adding a nil hook")
+	}
+
+	for _, eventType := range []string{"CONNECTED", "DISCONNECTED", "CONNECTED"} {
+		hooks.Run(context.Background(), &host.TraceEvent{Type: eventType})
+	}
+
+	want := []string{"connected:CONNECTED", "all:CONNECTED", "all:DISCONNECTED", "connected:CONNECTED", "all:CONNECTED"}
+	if len(seen) != len(want) {
+		t.Fatalf("Expected hooks called as %v, got %v", want, seen)
+	}
+
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("Call %d: got %s, want %s", i, seen[i], want[i])
+		}
+	}
+
+	var stats peer.DataQualityStats
+	hooks.Stats(&stats)
+
+	tests := []struct {
+		name      string
+		calls     int
+		errors    int
+		panics    int
+		lastError string
+	}{
+		{name: "CONNECTED#1", calls: 2},
+		{name: "*#1", calls: 3, errors: 3, lastError: "export failed"},
+		{name: "CONNECTED#2", calls: 2, panics: 2, lastError: "hook panicked: broken hook"},
+	}
+
+	if len(stats.EventHooks) != len(tests) {
+		t.Fatalf("Expected %d hooks in the stats, got %+v", len(tests), stats.EventHooks)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stats.EventHooks[i]
+			if got.Name != tt.name || got.Calls != tt.calls || got.Errors != tt.errors || got.Panics != tt.panics || got.LastError != tt.lastError {
+				t.Errorf("Unexpected stats %+v", got)
+			}
+		})
+	}
+}
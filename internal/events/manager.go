@@ -7,23 +7,40 @@ import (
 	"github.com/probe-lab/hermes/host"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
 	"github.com/ethpandaops/hermes-peer-score/internal/common"
 	"github.com/ethpandaops/hermes-peer-score/internal/events/handlers"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/peertrace"
 )
 
 // DefaultManager implements the Manager interface.
 type DefaultManager struct {
-	handlers map[string]Handler
-	tool     common.ToolInterface
-	logger   logrus.FieldLogger
+	handlers  map[string]Handler
+	ordering  *OrderingChecker
+	reqresp   *ReqRespLimiter
+	unhandled *UnhandledCapture
+	hooks     *Hooks
+	timeline  *peer.TimelineRecorder
+	topics    *peer.SubscriptionRecorder
+	router    *peer.RouterRecorder
+	whitelist *TopicFilter
+	trace     *peertrace.Trace
+	beacon    *beaconsync.Tracker
+	tool      common.ToolInterface
+	logger    logrus.FieldLogger
 }
 
 // NewManager creates a new event manager with the given tool interface.
 func NewManager(tool common.ToolInterface, logger logrus.FieldLogger) *DefaultManager {
 	return &DefaultManager{
-		handlers: make(map[string]Handler),
-		tool:     tool,
-		logger:   logger,
+		handlers:  make(map[string]Handler),
+		ordering:  NewOrderingChecker(),
+		reqresp:   NewReqRespLimiter(),
+		unhandled: NewUnhandledCapture(),
+		hooks:     NewHooks(logger),
+		tool:      tool,
+		logger:    logger,
 	}
 }
 
@@ -59,16 +76,75 @@ func (m *DefaultManager) HandleEvent(ctx context.Context, event *host.TraceEvent
 		"event_type": event.Type,
 	})
 
+	// Events of topics outside the whitelist are discarded before anything counts them
+	if m.whitelist != nil && !m.whitelist.Allow(event) {
+		return nil
+	}
+
 	// Count the event by peer ID and event type
 	peerID := common.GetPeerID(event)
 	if peerID != "" && peerID != "unknown" {
 		m.tool.IncrementEventCount(peerID, event.Type)
+
+		// Keep every event of the traced peer in full
+		if m.trace != nil && peerID == m.trace.PeerID() {
+			m.trace.Event(event)
+		}
+
+		// Bucket the event by its trace time for burst detection
+		if m.timeline != nil {
+			m.timeline.Record(peerID, event.Type, common.GetEventTime(event))
+		}
+
+		// Flag events processed behind their trace timestamp order
+		if m.ordering.Observe(peerID, event) {
+			eventLogger.WithField("peer_id", common.FormatShortPeerID(peerID)).Debug("Event processed out of trace timestamp order")
+		}
+
+		// Count requests beyond the rate limits or that Hermes could not read against the peer
+		recordReqRespAbuse(m.tool, m.reqresp, peerID, event)
+		if event.Type == "DISCONNECTED" {
+			m.reqresp.Forget(peerID)
+		}
+
+		// Record the request streams Hermes reset, our side's terminations
+		recordLocalTermination(m.tool, peerID, event)
+
+		// Requests the beacon node behind delegated validation failed are our backend's, not the peer's
+		recordBeaconSyncFailure(m.beacon, event)
+
+		// Count the gossipsub control plane per peer, to tell mesh peers from gossip leeches
+		recordControlPlane(m.tool, peerID, event)
+	}
+
+	// Custom handlers see every event, whatever the built-in processing does with it
+	m.hooks.Run(ctx, event)
+
+	// Sample the local router's behaviour, events only the router metrics use need no handler
+	if m.router != nil && recordRouterEvent(m.router, event) {
+		return nil
+	}
+
+	// Record the local node's topic subscriptions, which carry no remote peer
+	if m.topics != nil && (event.Type == peer.SubscriptionJoin || event.Type == peer.SubscriptionLeave) {
+		if payload, ok := event.Payload.(map[string]interface{}); ok {
+			if topic, ok := payload["Topic"].(string); ok {
+				m.topics.Record(event.Type, topic, common.GetEventTime(event))
+			}
+		}
+
+		return nil
 	}
 
 	// Find and execute the appropriate handler
 	handler, exists := m.handlers[event.Type]
 	if !exists {
-		eventLogger.Debug("Unhandled event type")
+		// Capture samples of unhandled types, a new one may be worth parsing
+		if m.unhandled.Observe(event) {
+			eventLogger.Info("First event of unhandled type captured")
+		} else {
+			eventLogger.Debug("Unhandled event type")
+		}
 
 		return nil
 	}
@@ -81,6 +157,70 @@ func (m *DefaultManager) HandleEvent(ctx context.Context, event *host.TraceEvent
 	return nil
 }
 
+// AddHook attaches a custom handler for an event type, or for every event with AllEvents, and
+// returns the name its statistics are reported under. Hooks run alongside the built-in handlers.
+func (m *DefaultManager) AddHook(eventType string, fn HookFunc) (string, error) {
+	return m.hooks.Add(eventType, fn)
+}
+
+// SetTimeline sets the recorder events are bucketed into by peer and type.
+func (m *DefaultManager) SetTimeline(timeline *peer.TimelineRecorder) {
+	m.timeline = timeline
+}
+
+// SetRouter sets the recorder the local gossipsub router's mesh and gossip events are sampled in.
+func (m *DefaultManager) SetRouter(router *peer.RouterRecorder) {
+	m.router = router
+}
+
+// SetSubscriptions sets the recorder the local node's topic JOIN and LEAVE events are recorded in.
+func (m *DefaultManager) SetSubscriptions(topics *peer.SubscriptionRecorder) {
+	m.topics = topics
+}
+
+// SetPeerTrace sets the trace every event of its peer is recorded in.
+func (m *DefaultManager) SetPeerTrace(trace *peertrace.Trace) {
+	m.trace = trace
+}
+
+// SetBeaconSync sets the tracker the requests the beacon node failed to serve are recorded in.
+func (m *DefaultManager) SetBeaconSync(tracker *beaconsync.Tracker) {
+	m.beacon = tracker
+}
+
+// SetTopicWhitelist restricts processing to the events of the given gossip topic names.
+func (m *DefaultManager) SetTopicWhitelist(topics []string) {
+	m.whitelist = NewTopicFilter(topics)
+}
+
+// TopicWhitelist returns the topic whitelist and the events it discarded, or nil without one.
+func (m *DefaultManager) TopicWhitelist() *peer.TopicWhitelist {
+	if m.whitelist == nil {
+		return nil
+	}
+
+	return m.whitelist.Report()
+}
+
+// DataQuality returns the event ordering, unhandled event and peer ID extraction statistics
+// gathered so far.
+func (m *DefaultManager) DataQuality() peer.DataQualityStats {
+	stats := m.ordering.Stats()
+	m.unhandled.Stats(&stats)
+	m.hooks.Stats(&stats)
+
+	for payloadType, count := range common.PeerIDReflectionFallbacks() {
+		if stats.PeerIDReflectionFallbacksByType == nil {
+			stats.PeerIDReflectionFallbacksByType = make(map[string]int)
+		}
+
+		stats.PeerIDReflectionFallbacksByType[payloadType] = count
+		stats.PeerIDReflectionFallbacks += count
+	}
+
+	return stats
+}
+
 // RegisterDefaultHandlers registers all the default event handlers.
 func (m *DefaultManager) RegisterDefaultHandlers() error {
 	// Register all event handlers
@@ -88,10 +228,12 @@ func (m *DefaultManager) RegisterDefaultHandlers() error {
 		handlers.NewConnectionHandler(m.tool, m.logger),
 		handlers.NewDisconnectionHandler(m.tool, m.logger),
 		handlers.NewStatusHandler(m.tool, m.logger),
+		handlers.NewInboundStatusHandler(m.tool, m.logger),
 		handlers.NewPeerScoreHandler(m.tool, m.logger),
 		handlers.NewGoodbyeHandler(m.tool, m.logger),
 		handlers.NewGraftHandler(m.tool, m.logger),
 		handlers.NewPruneHandler(m.tool, m.logger),
+		handlers.NewDecodeErrorHandler(m.tool, m.logger),
 	}
 
 	for _, handler := range eventHandlers {
@@ -3,9 +3,12 @@ package events
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/probe-lab/hermes/host"
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
 )
 
 // MockHandler for testing.
@@ -82,6 +85,22 @@ func (m *MockToolInterface) IncrementMessageCount(peerID string) {
 	// Mock implementation - in a real test this could track message counts
 }
 
+func (m *MockToolInterface) GetLateEventGrace() time.Duration {
+	return constants.DefaultLateEventGrace
+}
+
+func (m *MockToolInterface) GetEventLogLevel() logrus.Level {
+	return logrus.DebugLevel
+}
+
+func (m *MockToolInterface) GetEventSampleRate(_ string) int {
+	return 0
+}
+
+func (m *MockToolInterface) IsBackendDegraded(_ time.Time) bool {
+	return false
+}
+
 func TestEventManager(t *testing.T) {
 	tool := NewMockToolInterface()
 	logger := logrus.New()
@@ -136,44 +155,3 @@ func TestEventManager(t *testing.T) {
 		t.Errorf("Expected no error for unhandled event, got %v", err)
 	}
 }
-
-func TestGetPeerID(t *testing.T) {
-	tests := []struct {
-		name     string
-		event    *host.TraceEvent
-		expected string
-	}{
-		{
-			name:     "nil event",
-			event:    nil,
-			expected: unknown,
-		},
-		{
-			name: "event with string PeerID in payload map",
-			event: &host.TraceEvent{
-				Payload: map[string]interface{}{
-					"PeerID": "test-peer-123",
-				},
-			},
-			expected: "test-peer-123",
-		},
-		{
-			name: "event with no peer ID",
-			event: &host.TraceEvent{
-				Payload: map[string]interface{}{
-					"other": "value",
-				},
-			},
-			expected: unknown,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := GetPeerID(tt.event)
-			if result != tt.expected {
-				t.Errorf("Expected %s, got %s", tt.expected, result)
-			}
-		})
-	}
-}
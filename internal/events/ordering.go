@@ -0,0 +1,73 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// OrderingChecker verifies that trace events for each peer are processed in timestamp order.
+type OrderingChecker struct {
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+	stats    peer.DataQualityStats
+}
+
+// NewOrderingChecker creates a new ordering checker.
+func NewOrderingChecker() *OrderingChecker {
+	return &OrderingChecker{
+		lastSeen: make(map[string]time.Time),
+		stats: peer.DataQualityStats{
+			OutOfOrderByType: make(map[string]int),
+		},
+	}
+}
+
+// Observe records an event and reports whether it was processed out of order, that is
+// stamped earlier than an event already processed for the same peer.
+func (c *OrderingChecker) Observe(peerID string, event *host.TraceEvent) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.EventsChecked++
+
+	if event.Timestamp.IsZero() {
+		c.stats.MissingTimestamps++
+
+		return false
+	}
+
+	last, seen := c.lastSeen[peerID]
+	if seen && event.Timestamp.Before(last) {
+		c.stats.OutOfOrderEvents++
+		c.stats.OutOfOrderByType[event.Type]++
+
+		if lag := last.Sub(event.Timestamp).Seconds(); lag > c.stats.MaxLagSeconds {
+			c.stats.MaxLagSeconds = lag
+		}
+
+		return true
+	}
+
+	c.lastSeen[peerID] = event.Timestamp
+
+	return false
+}
+
+// Stats returns a snapshot of the ordering statistics.
+func (c *OrderingChecker) Stats() peer.DataQualityStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.OutOfOrderByType = make(map[string]int, len(c.stats.OutOfOrderByType))
+
+	for eventType, count := range c.stats.OutOfOrderByType {
+		stats.OutOfOrderByType[eventType] = count
+	}
+
+	return stats
+}
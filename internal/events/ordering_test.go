@@ -0,0 +1,129 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+)
+
+func TestOrderingChecker(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	type observation struct {
+		peerID    string
+		eventType string
+		offset    time.Duration
+		zero      bool
+	}
+
+	tests := []struct {
+		name             string
+		events           []observation
+		wantOutOfOrder   []bool
+		wantMissing      int
+		wantMaxLag       float64
+		wantOutOfOrderBy map[string]int
+	}{
+		{
+			name: "in order",
+			events: []observation{
+				{peerID: "a", eventType: "CONNECTED"},
+				{peerID: "a", eventType: "PEERSCORE", offset: time.Second},
+				{peerID: "a", eventType: "DISCONNECTED", offset: 2 * time.Second},
+			},
+			wantOutOfOrder:   []bool{false, false, false},
+			wantOutOfOrderBy: map[string]int{},
+		},
+		{
+			name: "equal timestamps are in order",
+			events: []observation{
+				{peerID: "a", eventType: "CONNECTED", offset: time.Second},
+				{peerID: "a", eventType: "GRAFT", offset: time.Second},
+			},
+			wantOutOfOrder:   []bool{false, false},
+			wantOutOfOrderBy: map[string]int{},
+		},
+		{
+			name: "late event for the same peer",
+			events: []observation{
+				{peerID: "a", eventType: "CONNECTED", offset: 5 * time.Second},
+				{peerID: "a", eventType: "HANDLE_GOODBYE", offset: 2 * time.Second},
+				{peerID: "a", eventType: "DISCONNECTED", offset: 3 * time.Second},
+			},
+			wantOutOfOrder:   []bool{false, true, true},
+			wantMaxLag:       3,
+			wantOutOfOrderBy: map[string]int{"HANDLE_GOODBYE": 1, "DISCONNECTED": 1},
+		},
+		{
+			name: "peers are ordered independently",
+			events: []observation{
+				{peerID: "a", eventType: "CONNECTED", offset: 5 * time.Second},
+				{peerID: "b", eventType: "CONNECTED", offset: time.Second},
+			},
+			wantOutOfOrder:   []bool{false, false},
+			wantOutOfOrderBy: map[string]int{},
+		},
+		{
+			name: "missing timestamps are counted but not ordered",
+			events: []observation{
+				{peerID: "a", eventType: "CONNECTED", offset: time.Second},
+				{peerID: "a", eventType: "PEERSCORE", zero: true},
+			},
+			wantOutOfOrder:   []bool{false, false},
+			wantMissing:      1,
+			wantOutOfOrderBy: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewOrderingChecker()
+
+			for i, obs := range tt.events {
+				event := &host.TraceEvent{Type: obs.eventType}
+				if !obs.zero {
+					event.Timestamp = base.Add(obs.offset)
+				}
+
+				if got := checker.Observe(obs.peerID, event); got != tt.wantOutOfOrder[i] {
+					t.Errorf("event %d: expected out of order %v, got %v", i, tt.wantOutOfOrder[i], got)
+				}
+			}
+
+			stats := checker.Stats()
+			if stats.EventsChecked != len(tt.events) {
+				t.Errorf("expected %d events checked, got %d", len(tt.events), stats.EventsChecked)
+			}
+
+			outOfOrder := 0
+			for _, flagged := range tt.wantOutOfOrder {
+				if flagged {
+					outOfOrder++
+				}
+			}
+
+			if stats.OutOfOrderEvents != outOfOrder {
+				t.Errorf("expected %d out-of-order events, got %d", outOfOrder, stats.OutOfOrderEvents)
+			}
+
+			if stats.MissingTimestamps != tt.wantMissing {
+				t.Errorf("expected %d missing timestamps, got %d", tt.wantMissing, stats.MissingTimestamps)
+			}
+
+			if stats.MaxLagSeconds != tt.wantMaxLag {
+				t.Errorf("expected max lag %.1fs, got %.1fs", tt.wantMaxLag, stats.MaxLagSeconds)
+			}
+
+			if len(stats.OutOfOrderByType) != len(tt.wantOutOfOrderBy) {
+				t.Errorf("expected out-of-order types %v, got %v", tt.wantOutOfOrderBy, stats.OutOfOrderByType)
+			}
+
+			for eventType, count := range tt.wantOutOfOrderBy {
+				if stats.OutOfOrderByType[eventType] != count {
+					t.Errorf("expected %d out-of-order %s events, got %d", count, eventType, stats.OutOfOrderByType[eventType])
+				}
+			}
+		})
+	}
+}
@@ -11,10 +11,10 @@ import (
 // DefaultParser provides common parsing functionality.
 type DefaultParser struct{}
 
-// ParsePeerScoreFromMap parses peer score data from a map payload.
-func (p *DefaultParser) ParsePeerScoreFromMap(payload map[string]interface{}) (*PeerScoreData, error) {
+// ParsePeerScoreFromMap parses peer score data from a map payload, stamped with the trace event time.
+func (p *DefaultParser) ParsePeerScoreFromMap(payload map[string]interface{}, timestamp time.Time) (*PeerScoreData, error) {
 	score := &PeerScoreData{
-		Timestamp: time.Now(),
+		Timestamp: timestamp,
 		Topics:    make([]TopicScore, 0),
 	}
 
@@ -53,10 +53,10 @@ func (p *DefaultParser) ParsePeerScoreFromMap(payload map[string]interface{}) (*
 	return score, nil
 }
 
-// ParseGoodbyeFromMap parses goodbye event data from a map payload.
-func (p *DefaultParser) ParseGoodbyeFromMap(payload map[string]interface{}) (*GoodbyeData, error) {
+// ParseGoodbyeFromMap parses goodbye event data from a map payload, stamped with the trace event time.
+func (p *DefaultParser) ParseGoodbyeFromMap(payload map[string]interface{}, timestamp time.Time) (*GoodbyeData, error) {
 	goodbye := &GoodbyeData{
-		Timestamp: time.Now(),
+		Timestamp: timestamp,
 	}
 
 	if val, ok := payload["Code"]; ok {
@@ -74,10 +74,81 @@ func (p *DefaultParser) ParseGoodbyeFromMap(payload map[string]interface{}) (*Go
 	return goodbye, nil
 }
 
-// ParseMeshFromMap parses mesh event data from a map payload.
-func (p *DefaultParser) ParseMeshFromMap(payload map[string]interface{}, eventType string) (*MeshData, error) {
+// ParseStatusFromMap parses the peer's status from a map payload, stamped with the trace event
+// time. Statuses the peer sent us are nested under Request, responses to ours are top level.
+func (p *DefaultParser) ParseStatusFromMap(payload map[string]interface{}, timestamp time.Time) (*StatusData, error) {
+	status := &StatusData{
+		Timestamp: timestamp,
+	}
+
+	if val, ok := payload["Error"].(string); ok {
+		status.Error = val
+	}
+
+	status.Success = status.Error == ""
+
+	fields := payload
+	if request, ok := payload["Request"].(map[string]interface{}); ok {
+		fields = request
+	}
+
+	val, ok := fields["HeadSlot"]
+	if !ok {
+		if status.Error != "" {
+			return status, nil
+		}
+
+		return nil, fmt.Errorf("status payload has neither a head slot nor an error")
+	}
+
+	headSlot, err := parseUint64(val)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse head slot: %w", err)
+	}
+
+	status.HeadSlot = headSlot
+
+	if val, ok := fields["FinalizedEpoch"]; ok {
+		if epoch, err := parseUint64(val); err == nil {
+			status.FinalizedEpoch = epoch
+		}
+	}
+
+	// Statuses peers sent us carry the one we answered with
+	if response, ok := payload["Response"].(map[string]interface{}); ok {
+		if slot, err := parseUint64(response["HeadSlot"]); err == nil {
+			status.LocalHeadSlot = slot
+		}
+	}
+
+	return status, nil
+}
+
+// ParseRejectFromMap parses message rejection data from a map payload, stamped with the trace event time.
+func (p *DefaultParser) ParseRejectFromMap(payload map[string]interface{}, timestamp time.Time) (*RejectData, error) {
+	reject := &RejectData{
+		Timestamp: timestamp,
+	}
+
+	if val, ok := payload["Topic"]; ok {
+		if topic, ok := val.(string); ok {
+			reject.Topic = topic
+		}
+	}
+
+	if val, ok := payload["Reason"]; ok {
+		if reason, ok := val.(string); ok {
+			reject.Reason = reason
+		}
+	}
+
+	return reject, nil
+}
+
+// ParseMeshFromMap parses mesh event data from a map payload, stamped with the trace event time.
+func (p *DefaultParser) ParseMeshFromMap(payload map[string]interface{}, eventType string, timestamp time.Time) (*MeshData, error) {
 	mesh := &MeshData{
-		Timestamp: time.Now(),
+		Timestamp: timestamp,
 		Type:      eventType,
 	}
 
@@ -0,0 +1,87 @@
+package parsers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// benchSlot is an SSZ style wrapper, parsed through its Uint64 method.
+type benchSlot struct{ slot uint64 }
+
+func (s benchSlot) Uint64() uint64 { return s.slot }
+
+// benchEpoch is a wrapper without methods, parsed through its Value field.
+type benchEpoch struct{ Value uint64 }
+
+// benchSSZUint64 is a named numeric type, parsed by its kind.
+type benchSSZUint64 uint64
+
+// benchPeerScore is a PEERSCORE payload as Hermes sends it, with a score per topic.
+func benchPeerScore(topics int) map[string]interface{} {
+	scores := make([]interface{}, 0, topics)
+
+	for i := 0; i < topics; i++ {
+		scores = append(scores, map[string]interface{}{
+			"Topic":                    fmt.Sprintf("/eth2/4a26c58b/beacon_attestation_%d/ssz_snappy", i),
+			"TimeInMesh":               int64(20 * time.Second),
+			"FirstMessageDeliveries":   3.0,
+			"MeshMessageDeliveries":    2.5,
+			"InvalidMessageDeliveries": 0.0,
+		})
+	}
+
+	return map[string]interface{}{
+		"PeerID":             "16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6",
+		"Score":              12.5,
+		"AppSpecificScore":   0.0,
+		"IPColocationFactor": 0.0,
+		"BehaviourPenalty":   0.0,
+		"Topics":             scores,
+	}
+}
+
+func BenchmarkParsePeerScoreFromMap(b *testing.B) {
+	parser := &DefaultParser{}
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, topics := range []int{2, 64} {
+		payload := benchPeerScore(topics)
+
+		b.Run(fmt.Sprintf("topics=%d", topics), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				if _, err := parser.ParsePeerScoreFromMap(payload, at); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseUint64(b *testing.B) {
+	values := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "uint64", value: uint64(11800000)},
+		{name: "int64", value: int64(11800000)},
+		{name: "string", value: "11800000"},
+		{name: "method", value: benchSlot{slot: 11800000}},
+		{name: "field", value: &benchEpoch{Value: 368748}},
+		{name: "kind", value: benchSSZUint64(11800000)},
+	}
+
+	for _, tt := range values {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				if _, err := parseUint64(tt.value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -37,6 +37,13 @@ type MeshData struct {
 	Reason    string    `json:"reason"`
 }
 
+// RejectData represents a parsed gossipsub message rejection.
+type RejectData struct {
+	Timestamp time.Time `json:"timestamp"`
+	Topic     string    `json:"topic"`
+	Reason    string    `json:"reason"`
+}
+
 // ConnectionData represents parsed connection event information.
 type ConnectionData struct {
 	Timestamp   time.Time `json:"timestamp"`
@@ -53,7 +60,11 @@ type DisconnectionData struct {
 
 // StatusData represents parsed status event information.
 type StatusData struct {
-	Timestamp time.Time `json:"timestamp"`
-	PeerID    string    `json:"peer_id"`
-	Success   bool      `json:"success"`
+	Timestamp      time.Time `json:"timestamp"`
+	PeerID         string    `json:"peer_id"`
+	Success        bool      `json:"success"`
+	HeadSlot       uint64    `json:"head_slot"`
+	FinalizedEpoch uint64    `json:"finalized_epoch"`
+	LocalHeadSlot  uint64    `json:"local_head_slot,omitempty"` // Our head slot, as we answered a status the peer sent us
+	Error          string    `json:"error,omitempty"`
 }
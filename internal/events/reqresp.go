@@ -0,0 +1,189 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// reqRespQuota allows a number of requests of one protocol from one peer per period.
+type reqRespQuota struct {
+	requests int
+	period   time.Duration
+}
+
+// reqRespQuotas are Lighthouse's default inbound quotas for the protocols Hermes serves
+// without forwarding to the beacon node. Hermes enforces none of them itself.
+var reqRespQuotas = map[string]reqRespQuota{
+	"HANDLE_STATUS":   {requests: 5, period: 15 * time.Second},
+	"HANDLE_PING":     {requests: 2, period: 10 * time.Second},
+	"HANDLE_METADATA": {requests: 2, period: 5 * time.Second},
+	"HANDLE_GOODBYE":  {requests: 1, period: 10 * time.Second},
+}
+
+// reqRespBucket is the token bucket of one peer and protocol.
+type reqRespBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// ReqRespLimiter applies the inbound req/resp quotas to the requests peers send us, the way a
+// consensus client's rate limiter would, so the requests it would refuse can be counted.
+type ReqRespLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]*reqRespBucket // Peer ID -> event type -> bucket
+}
+
+// NewReqRespLimiter creates a new req/resp limiter.
+func NewReqRespLimiter() *ReqRespLimiter {
+	return &ReqRespLimiter{buckets: make(map[string]map[string]*reqRespBucket)}
+}
+
+// Allow takes a request of the event type's protocol from the peer at the given time and reports
+// whether it was within the quota. Protocols without a quota are always allowed.
+func (l *ReqRespLimiter) Allow(peerID, eventType string, at time.Time) bool {
+	quota, limited := reqRespQuotas[eventType]
+	if !limited {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	protocols, exists := l.buckets[peerID]
+	if !exists {
+		protocols = make(map[string]*reqRespBucket)
+		l.buckets[peerID] = protocols
+	}
+
+	bucket, exists := protocols[eventType]
+	if !exists {
+		bucket = &reqRespBucket{tokens: float64(quota.requests), last: at}
+		protocols[eventType] = bucket
+	}
+
+	// Events processed out of order refill nothing rather than draining the bucket
+	if elapsed := at.Sub(bucket.last); elapsed > 0 {
+		refill := elapsed.Seconds() * float64(quota.requests) / quota.period.Seconds()
+		bucket.tokens = min(float64(quota.requests), bucket.tokens+refill)
+		bucket.last = at
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+
+	return true
+}
+
+// Forget drops the peer's buckets once it disconnects, so the limiter only holds connected
+// peers. A peer that reconnects starts with full quotas.
+func (l *ReqRespLimiter) Forget(peerID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.buckets, peerID)
+}
+
+// recordReqRespAbuse counts a request a peer sent us against it when it broke the protocol's
+// quota or Hermes failed to read it. Every handled req/resp protocol is traced as HANDLE_<name>,
+// gossip messages arrive as HANDLE_MESSAGE and are not requests.
+func recordReqRespAbuse(tool common.ToolInterface, limiter *ReqRespLimiter, peerID string, event *host.TraceEvent) {
+	if !strings.HasPrefix(event.Type, "HANDLE_") || event.Type == "HANDLE_MESSAGE" {
+		return
+	}
+
+	at := common.GetEventTime(event)
+
+	var kind, reason string
+
+	if payload, ok := event.Payload.(map[string]interface{}); ok {
+		if errText, ok := payload["Error"].(string); ok {
+			reason = errText
+			kind, _ = peer.ClassifyReqRespError(errText)
+		}
+	}
+
+	if !limiter.Allow(peerID, event.Type, at) && kind == "" {
+		kind = peer.ReqRespAbuseRateLimit
+		reason = "exceeded " + event.Type + " quota"
+	}
+
+	if kind == "" {
+		return
+	}
+
+	tool.UpdateOrCreatePeer(peerID, func(p interface{}) {
+		if peerStats, ok := p.(*peer.Stats); ok {
+			peerStats.RecordReqRespAbuse(kind, event.Type, reason, at)
+			peerStats.MarkInteresting(peer.SampleReqRespAbuse, at)
+		}
+	})
+}
+
+// recordLocalTermination records a request stream Hermes reset after failing to handle the
+// request, on the session the request came in. Hermes resets the stream of every handler that
+// returns an error, and traces the error with the request.
+func recordLocalTermination(tool common.ToolInterface, peerID string, event *host.TraceEvent) {
+	if !strings.HasPrefix(event.Type, "HANDLE_") || event.Type == "HANDLE_MESSAGE" {
+		return
+	}
+
+	payload, ok := event.Payload.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	reason, _ := payload["Error"].(string)
+
+	kind, local := peer.ClassifyLocalTermination(reason)
+	if !local {
+		return
+	}
+
+	at := common.GetEventTime(event)
+
+	tool.UpdateOrCreatePeer(peerID, func(p interface{}) {
+		peerStats, ok := p.(*peer.Stats)
+		if !ok {
+			return
+		}
+
+		session, _ := peerStats.AssignEvent(event.Type, at, tool.GetLateEventGrace())
+		if session == nil {
+			return
+		}
+
+		session.LocalTerminations = append(session.LocalTerminations, peer.LocalTermination{
+			Timestamp: at,
+			Kind:      kind,
+			Protocol:  event.Type,
+			Reason:    reason,
+		})
+	})
+}
+
+// recordBeaconSyncFailure records a request Hermes failed because the beacon node it forwards
+// block and blob requests to was not synced, had pruned the data or could not be reached.
+func recordBeaconSyncFailure(tracker *beaconsync.Tracker, event *host.TraceEvent) {
+	if tracker == nil || !strings.HasPrefix(event.Type, "HANDLE_") || event.Type == "HANDLE_MESSAGE" {
+		return
+	}
+
+	payload, ok := event.Payload.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if reason, ok := payload["Error"].(string); ok {
+		tracker.RecordRequest(event.Type, reason, common.GetEventTime(event))
+	}
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestReqRespLimiter(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewReqRespLimiter()
+
+	// Two pings per ten seconds, the third in a burst is refused
+	for i, want := range []bool{true, true, false} {
+		if got := limiter.Allow("a", "HANDLE_PING", base); got != want {
+			t.Errorf("ping %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	// Other peers and protocols have their own quota
+	if !limiter.Allow("b", "HANDLE_PING", base) || !limiter.Allow("a", "HANDLE_STATUS", base) {
+		t.Error("expected other peers and protocols to be allowed")
+	}
+
+	// A token is back after half the period
+	if !limiter.Allow("a", "HANDLE_PING", base.Add(5*time.Second)) {
+		t.Error("expected a ping to be allowed after the refill")
+	}
+
+	// Out of order events refill nothing
+	if limiter.Allow("a", "HANDLE_PING", base) {
+		t.Error("expected an out of order ping to be refused")
+	}
+
+	// Protocols without a quota are never limited
+	for range 100 {
+		if !limiter.Allow("a", "HANDLE_BLOCKS_BY_RANGE", base) {
+			t.Fatal("expected unquoted protocols to be allowed")
+		}
+	}
+
+	// A disconnected peer's buckets are dropped, it reconnects with full quotas
+	limiter.Forget("a")
+
+	if _, exists := limiter.buckets["a"]; exists {
+		t.Error("expected the forgotten peer's buckets to be dropped")
+	}
+
+	if !limiter.Allow("a", "HANDLE_PING", base) {
+		t.Error("expected a ping to be allowed after the peer reconnected")
+	}
+}
+
+func TestRecordReqRespAbuse(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tool := NewMockToolInterface()
+	tool.peers["a"] = &peer.Stats{PeerID: "a"}
+	limiter := NewReqRespLimiter()
+
+	event := func(eventType string, errText interface{}) *host.TraceEvent {
+		return &host.TraceEvent{
+			Type:      eventType,
+			Timestamp: base,
+			Payload:   map[string]interface{}{"PeerID": "a", "Error": errText},
+		}
+	}
+
+	events := []*host.TraceEvent{
+		event("HANDLE_METADATA", nil),
+		event("HANDLE_METADATA", nil),
+		event("HANDLE_METADATA", nil), // Beyond the quota
+		event("HANDLE_STATUS", "read request data *pb.Status: snappy: corrupt input"),
+		event("HANDLE_STATUS", "read request data *pb.Status: i/o deadline reached"),
+		event("HANDLE_MESSAGE", "read request data"), // Gossip, not a request
+		event("REQUEST_STATUS", "read request data"), // Our own request
+	}
+
+	for _, e := range events {
+		recordReqRespAbuse(tool, limiter, "a", e)
+	}
+
+	stats, _ := tool.peers["a"].(*peer.Stats)
+
+	abuse := stats.ReqRespAbuse
+	if abuse == nil {
+		t.Fatal("expected req/resp abuse to be recorded")
+	}
+
+	if abuse.Total != 2 || abuse.ByKind[peer.ReqRespAbuseRateLimit] != 1 || abuse.ByKind[peer.ReqRespAbuseMalformed] != 1 {
+		t.Errorf("unexpected abuse counts: %+v", abuse)
+	}
+
+	if abuse.ByProtocol["HANDLE_METADATA"] != 1 || abuse.ByProtocol["HANDLE_STATUS"] != 1 {
+		t.Errorf("unexpected protocol breakdown: %v", abuse.ByProtocol)
+	}
+}
+
+func TestRecordLocalTermination(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tool := NewMockToolInterface()
+	tool.peers["a"] = &peer.Stats{PeerID: "a", ConnectionSessions: []peer.ConnectionSession{{ConnectedAt: &base}}}
+
+	event := func(eventType string, errText interface{}) *host.TraceEvent {
+		return &host.TraceEvent{
+			Type:      eventType,
+			Timestamp: base.Add(time.Second),
+			Payload:   map[string]interface{}{"PeerID": "a", "Error": errText},
+		}
+	}
+
+	events := []*host.TraceEvent{
+		event("HANDLE_METADATA", nil),
+		event("HANDLE_STATUS", "read request data *pb.Status: snappy: corrupt input"),
+		event("HANDLE_PING", "read request data *primitives.SSZUint64: i/o deadline reached"),
+		event("HANDLE_METADATA", "stream reset"),                // The peer reset it
+		event("HANDLE_MESSAGE", "read request data"),            // Gossip, not a request
+		event("REQUEST_STATUS", "read request data: snappy: x"), // Our own request
+	}
+
+	for _, e := range events {
+		recordLocalTermination(tool, "a", e)
+	}
+
+	stats, _ := tool.peers["a"].(*peer.Stats)
+
+	terminations := stats.ConnectionSessions[0].LocalTerminations
+	if len(terminations) != 2 {
+		t.Fatalf("expected 2 local terminations, got %+v", terminations)
+	}
+
+	if terminations[0].Kind != peer.TerminationMalformed || terminations[0].Protocol != "HANDLE_STATUS" ||
+		terminations[1].Kind != peer.TerminationTimeout || terminations[1].Protocol != "HANDLE_PING" {
+		t.Errorf("unexpected terminations: %+v", terminations)
+	}
+}
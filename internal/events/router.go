@@ -0,0 +1,78 @@
+package events
+
+import (
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// recordRouterEvent feeds a local gossipsub router event into the recorder. It reports whether
+// the event is only of interest to the router metrics, so no handler needs to see it.
+func recordRouterEvent(router *peer.RouterRecorder, event *host.TraceEvent) bool {
+	at := common.GetEventTime(event)
+
+	switch event.Type {
+	case peer.RouterGraft, peer.RouterPrune:
+		if payload, ok := event.Payload.(map[string]interface{}); ok {
+			if topic, ok := payload["Topic"].(string); ok {
+				router.Mesh(event.Type, common.GetPeerID(event), topic, at)
+			}
+		}
+
+		return false
+	case peer.RouterRemovePeer:
+		router.RemovePeer(common.GetPeerID(event), at)
+	case peer.RouterDeliver, peer.RouterDuplicate:
+		router.Message(event.Type == peer.RouterDuplicate, at)
+	case peer.RouterPublish:
+		router.Publish(eventTopic(event), at)
+	case peer.RouterReject:
+		// Our own messages carry our peer ID, so they are not charged to a peer as decode errors
+		payload, ok := event.Payload.(map[string]interface{})
+		if local, _ := payload["Local"].(bool); !ok || !local {
+			return false
+		}
+
+		reason, _ := payload["Reason"].(string)
+		router.LocalReject(eventTopic(event), reason, at)
+	case peer.RouterRecvRPC, peer.RouterSendRPC:
+		if meta, ok := event.Payload.(*host.RpcMeta); ok && meta.Control != nil {
+			router.Control(event.Type == peer.RouterSendRPC, ihaveIDs(meta.Control), iwantIDs(meta.Control), at)
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ihaveIDs counts the message IDs announced in an RPC's IHAVE control messages.
+func ihaveIDs(control *host.RpcMetaControl) int {
+	count := 0
+	for _, ihave := range control.IHave {
+		count += len(ihave.MsgIDs)
+	}
+
+	return count
+}
+
+// iwantIDs counts the message IDs requested in an RPC's IWANT control messages.
+func iwantIDs(control *host.RpcMetaControl) int {
+	count := 0
+	for _, iwant := range control.IWant {
+		count += len(iwant.MsgIDs)
+	}
+
+	return count
+}
+
+// idontwantIDs counts the message IDs in an RPC's IDONTWANT control messages.
+func idontwantIDs(control *host.RpcMetaControl) int {
+	count := 0
+	for _, idontwant := range control.Idontwant {
+		count += len(idontwant.MsgIDs)
+	}
+
+	return count
+}
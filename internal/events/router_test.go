@@ -0,0 +1,85 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestRecordRouterEvent(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		event    *host.TraceEvent
+		consumed bool
+	}{
+		{
+			name:  "graft is left for the mesh handler",
+			event: &host.TraceEvent{Type: "GRAFT", Payload: map[string]interface{}{"PeerID": "peer-a", "Topic": "beacon_block"}},
+		},
+		{
+			name:     "duplicate",
+			event:    &host.TraceEvent{Type: "DUPLICATE_MESSAGE", Payload: map[string]interface{}{"PeerID": "peer-a"}},
+			consumed: true,
+		},
+		{
+			name:     "received gossip",
+			event:    &host.TraceEvent{Type: "RECV_RPC", Payload: &host.RpcMeta{Control: &host.RpcMetaControl{IHave: []host.RpcControlIHave{{MsgIDs: []string{"1", "2"}}}}}},
+			consumed: true,
+		},
+		{
+			name:     "sent gossip",
+			event:    &host.TraceEvent{Type: "SEND_RPC", Payload: &host.RpcMeta{Control: &host.RpcMetaControl{IWant: []host.RpcControlIWant{{MsgIDs: []string{"1"}}}}}},
+			consumed: true,
+		},
+		{
+			name:     "published",
+			event:    &host.TraceEvent{Type: "PUBLISH_MESSAGE", Payload: map[string]interface{}{"MsgID": "1", "Topic": "beacon_attestation_3"}},
+			consumed: true,
+		},
+		{
+			name: "our own message rejected",
+			event: &host.TraceEvent{Type: "REJECT_MESSAGE", Payload: map[string]interface{}{
+				"PeerID": "us", "Topic": "beacon_attestation_3", "Reason": "validation failed", "Local": true,
+			}},
+			consumed: true,
+		},
+		{
+			name: "peer message rejected is left for the decode error handler",
+			event: &host.TraceEvent{Type: "REJECT_MESSAGE", Payload: map[string]interface{}{
+				"PeerID": "peer-a", "Topic": "beacon_block", "Reason": "validation failed", "Local": false,
+			}},
+		},
+		{
+			name:  "other",
+			event: &host.TraceEvent{Type: "PEERSCORE", Payload: map[string]interface{}{"PeerID": "peer-a"}},
+		},
+	}
+
+	router := peer.NewRouterRecorder(start, time.Minute)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.Timestamp = start.Add(time.Second)
+
+			if consumed := recordRouterEvent(router, tt.event); consumed != tt.consumed {
+				t.Errorf("Expected consumed %v, got %v", tt.consumed, consumed)
+			}
+		})
+	}
+
+	metrics := router.Snapshot(start.Add(time.Second))
+
+	if metrics.Topics[0].FinalMesh != 1 || metrics.Totals.Duplicates != 1 || metrics.Totals.IHaveReceived != 2 || metrics.Totals.IWantSent != 1 {
+		t.Errorf("Unexpected router metrics %+v", metrics)
+	}
+
+	publishing := metrics.Publishing
+	if publishing == nil || publishing.Published != 1 || publishing.Rejected != 1 || publishing.Topics[0].RejectReasons["validation failed"] != 1 {
+		t.Errorf("Expected one published and one locally rejected message, got %+v", publishing)
+	}
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// TopicFilter discards the trace events of gossip topics outside a whitelist before they are
+// processed, and removes those topics from peer scores. Events without a topic pass, as do the
+// local node's subscriptions, so the subscription check still sees every topic Hermes joined.
+type TopicFilter struct {
+	topics []string
+
+	mu            sync.Mutex
+	dropped       int
+	droppedByType map[string]int
+	droppedScores int
+}
+
+// NewTopicFilter creates a filter keeping the given gossip topic names.
+func NewTopicFilter(topics []string) *TopicFilter {
+	return &TopicFilter{
+		topics:        topics,
+		droppedByType: make(map[string]int),
+	}
+}
+
+// Allow reports whether the event is to be processed. Peer score events are always processed,
+// with the scores of topics outside the whitelist removed from their payload.
+func (f *TopicFilter) Allow(event *host.TraceEvent) bool {
+	if event.Type == peer.SubscriptionJoin || event.Type == peer.SubscriptionLeave {
+		return true
+	}
+
+	if topic := eventTopic(event); topic != "" {
+		if peer.TopicWhitelisted(f.topics, topic) {
+			return true
+		}
+
+		f.mu.Lock()
+		f.dropped++
+		f.droppedByType[event.Type]++
+		f.mu.Unlock()
+
+		return false
+	}
+
+	if removed := f.filterTopicScores(event.Payload); removed > 0 {
+		f.mu.Lock()
+		f.droppedScores += removed
+		f.mu.Unlock()
+	}
+
+	return true
+}
+
+// filterTopicScores removes the topic scores outside the whitelist from a peer score payload
+// and returns how many were removed.
+func (f *TopicFilter) filterTopicScores(payload interface{}) int {
+	switch p := payload.(type) {
+	case map[string]interface{}:
+		scores, ok := p["Topics"].([]interface{})
+		if !ok {
+			return 0
+		}
+
+		kept := make([]interface{}, 0, len(scores))
+
+		for _, score := range scores {
+			if entry, ok := score.(map[string]interface{}); ok {
+				if topic, ok := entry["Topic"].(string); ok && !peer.TopicWhitelisted(f.topics, topic) {
+					continue
+				}
+			}
+
+			kept = append(kept, score)
+		}
+
+		p["Topics"] = kept
+
+		return len(scores) - len(kept)
+	case *host.TraceEventPeerScore:
+		kept := p.Topics[:0]
+
+		for _, score := range p.Topics {
+			if peer.TopicWhitelisted(f.topics, score.Topic) {
+				kept = append(kept, score)
+			}
+		}
+
+		removed := len(p.Topics) - len(kept)
+		p.Topics = kept
+
+		return removed
+	default:
+		return 0
+	}
+}
+
+// Report returns the whitelist and the events discarded for topics outside it.
+func (f *TopicFilter) Report() *peer.TopicWhitelist {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report := &peer.TopicWhitelist{
+		Topics:             append([]string(nil), f.topics...),
+		DroppedEvents:      f.dropped,
+		DroppedTopicScores: f.droppedScores,
+	}
+
+	if len(f.droppedByType) > 0 {
+		report.DroppedByType = make(map[string]int, len(f.droppedByType))
+		for eventType, count := range f.droppedByType {
+			report.DroppedByType[eventType] = count
+		}
+	}
+
+	return report
+}
+
+// eventTopic returns the gossip topic an event concerns, or "" for events of no topic.
+func eventTopic(event *host.TraceEvent) string {
+	if event.Topic != "" {
+		return event.Topic
+	}
+
+	if payload, ok := event.Payload.(map[string]interface{}); ok {
+		if topic, ok := payload["Topic"].(string); ok {
+			return topic
+		}
+	}
+
+	return ""
+}
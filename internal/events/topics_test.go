@@ -0,0 +1,61 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/probe-lab/hermes/host"
+)
+
+func TestTopicFilter(t *testing.T) {
+	const (
+		block       = "/eth2/4a26c58b/beacon_block/ssz_snappy"
+		attestation = "/eth2/4a26c58b/beacon_attestation_3/ssz_snappy"
+	)
+
+	filter := NewTopicFilter([]string{"beacon_block"})
+
+	tests := []struct {
+		name  string
+		event *host.TraceEvent
+		want  bool
+	}{
+		{name: "whitelisted topic", event: &host.TraceEvent{Type: "DELIVER_MESSAGE", Topic: block}, want: true},
+		{name: "subnet topic", event: &host.TraceEvent{Type: "DELIVER_MESSAGE", Topic: attestation}, want: false},
+		{name: "topic in payload", event: &host.TraceEvent{Type: "GRAFT", Payload: map[string]interface{}{"Topic": attestation}}, want: false},
+		{name: "subscription", event: &host.TraceEvent{Type: "JOIN", Payload: map[string]interface{}{"Topic": attestation}}, want: true},
+		{name: "no topic", event: &host.TraceEvent{Type: "CONNECTED", Payload: map[string]interface{}{"RemotePeer": "peer"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Allow(tt.event); got != tt.want {
+				t.Errorf("Expected Allow to return %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	score := &host.TraceEvent{Type: "PEERSCORE", Payload: map[string]interface{}{
+		"PeerID": "peer",
+		"Topics": []interface{}{
+			map[string]interface{}{"Topic": block},
+			map[string]interface{}{"Topic": attestation},
+		},
+	}}
+
+	if !filter.Allow(score) {
+		t.Fatal("Expected peer score events to be processed")
+	}
+
+	if topics := score.Payload.(map[string]interface{})["Topics"].([]interface{}); len(topics) != 1 {
+		t.Errorf("Expected only the whitelisted topic score to be kept, got %v", topics)
+	}
+
+	report := filter.Report()
+	if report.DroppedEvents != 2 || report.DroppedByType["DELIVER_MESSAGE"] != 1 || report.DroppedByType["GRAFT"] != 1 {
+		t.Errorf("Expected 2 dropped events by type, got %+v", report)
+	}
+
+	if report.DroppedTopicScores != 1 {
+		t.Errorf("Expected 1 dropped topic score, got %d", report.DroppedTopicScores)
+	}
+}
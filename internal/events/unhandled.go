@@ -0,0 +1,100 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// UnhandledCapture counts trace events of types no handler parses and keeps the first few
+// payloads of each type. Both the samples and the number of tracked types are bounded.
+type UnhandledCapture struct {
+	mu        sync.Mutex
+	types     map[string]*peer.UnhandledEventType
+	total     int
+	untracked int
+}
+
+// NewUnhandledCapture creates an empty unhandled event capture.
+func NewUnhandledCapture() *UnhandledCapture {
+	return &UnhandledCapture{
+		types: make(map[string]*peer.UnhandledEventType),
+	}
+}
+
+// Observe records an unhandled event and reports whether its type was seen for the first time.
+func (c *UnhandledCapture) Observe(event *host.TraceEvent) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total++
+
+	captured, seen := c.types[event.Type]
+	if !seen {
+		if len(c.types) >= constants.UnhandledEventTypeLimit {
+			c.untracked++
+
+			return false
+		}
+
+		captured = &peer.UnhandledEventType{
+			Type:        event.Type,
+			FirstSeenAt: common.GetEventTime(event),
+		}
+		c.types[event.Type] = captured
+	}
+
+	captured.Count++
+
+	if len(captured.Samples) < constants.UnhandledEventSamples {
+		captured.Samples = append(captured.Samples, samplePayload(event.Payload))
+	}
+
+	return !seen
+}
+
+// Stats adds the captured event types to the data quality statistics.
+func (c *UnhandledCapture) Stats(stats *peer.DataQualityStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats.UnhandledEvents = c.total
+	stats.UntrackedEvents = c.untracked
+	stats.UnhandledTypes = make([]peer.UnhandledEventType, 0, len(c.types))
+
+	for _, captured := range c.types {
+		entry := *captured
+		entry.Samples = append([]string(nil), captured.Samples...)
+		stats.UnhandledTypes = append(stats.UnhandledTypes, entry)
+	}
+
+	sort.Slice(stats.UnhandledTypes, func(i, j int) bool {
+		if stats.UnhandledTypes[i].Count != stats.UnhandledTypes[j].Count {
+			return stats.UnhandledTypes[i].Count > stats.UnhandledTypes[j].Count
+		}
+
+		return stats.UnhandledTypes[i].Type < stats.UnhandledTypes[j].Type
+	})
+}
+
+// samplePayload encodes a payload as JSON, truncated to the sample size limit.
+func samplePayload(payload interface{}) string {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		// Fall back to the Go representation for payloads JSON cannot encode
+		data = []byte(fmt.Sprintf("%+v", payload))
+	}
+
+	if len(data) > constants.UnhandledEventSampleBytes {
+		return string(data[:constants.UnhandledEventSampleBytes]) + "…"
+	}
+
+	return string(data)
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestUnhandledCapture(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	event := func(eventType string, payload interface{}) *host.TraceEvent {
+		return &host.TraceEvent{Type: eventType, Timestamp: base, Payload: payload}
+	}
+
+	tests := []struct {
+		name          string
+		events        []*host.TraceEvent
+		wantNew       []bool
+		wantTotal     int
+		wantUntracked int
+		wantTypes     []string // In count order
+		wantSamples   map[string]int
+	}{
+		{
+			name: "counts and first samples per type",
+			events: []*host.TraceEvent{
+				event("NEW_TYPE", map[string]interface{}{"n": 1}),
+				event("OTHER", nil),
+				event("NEW_TYPE", map[string]interface{}{"n": 2}),
+				event("NEW_TYPE", map[string]interface{}{"n": 3}),
+				event("NEW_TYPE", map[string]interface{}{"n": 4}),
+			},
+			wantNew:     []bool{true, true, false, false, false},
+			wantTotal:   5,
+			wantTypes:   []string{"NEW_TYPE", "OTHER"},
+			wantSamples: map[string]int{"NEW_TYPE": constants.UnhandledEventSamples, "OTHER": 1},
+		},
+		{
+			name: "types beyond the limit are only counted",
+			events: func() []*host.TraceEvent {
+				events := make([]*host.TraceEvent, 0, constants.UnhandledEventTypeLimit+1)
+				for i := 0; i <= constants.UnhandledEventTypeLimit; i++ {
+					events = append(events, event("TYPE_"+strings.Repeat("X", i), nil))
+				}
+
+				return events
+			}(),
+			wantTotal:     constants.UnhandledEventTypeLimit + 1,
+			wantUntracked: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := NewUnhandledCapture()
+
+			for i, e := range tt.events {
+				isNew := capture.Observe(e)
+				if tt.wantNew != nil && isNew != tt.wantNew[i] {
+					t.Errorf("event %d: Observe() = %v, want %v", i, isNew, tt.wantNew[i])
+				}
+			}
+
+			var stats peer.DataQualityStats
+			capture.Stats(&stats)
+
+			if stats.UnhandledEvents != tt.wantTotal || stats.UntrackedEvents != tt.wantUntracked {
+				t.Errorf("UnhandledEvents = %d, UntrackedEvents = %d, want %d, %d",
+					stats.UnhandledEvents, stats.UntrackedEvents, tt.wantTotal, tt.wantUntracked)
+			}
+
+			for i, eventType := range tt.wantTypes {
+				if stats.UnhandledTypes[i].Type != eventType {
+					t.Errorf("type %d = %s, want %s", i, stats.UnhandledTypes[i].Type, eventType)
+				}
+
+				if samples := len(stats.UnhandledTypes[i].Samples); samples != tt.wantSamples[eventType] {
+					t.Errorf("%s samples = %d, want %d", eventType, samples, tt.wantSamples[eventType])
+				}
+			}
+		})
+	}
+}
+
+func TestSamplePayloadTruncates(t *testing.T) {
+	sample := samplePayload(map[string]string{"data": strings.Repeat("a", constants.UnhandledEventSampleBytes)})
+
+	if len(sample) > constants.UnhandledEventSampleBytes+len("…") {
+		t.Errorf("sample length = %d, want at most %d", len(sample), constants.UnhandledEventSampleBytes)
+	}
+
+	if !strings.HasSuffix(sample, "…") {
+		t.Errorf("expected truncated sample to end with an ellipsis")
+	}
+}
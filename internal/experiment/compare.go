@@ -0,0 +1,200 @@
+package experiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// phaseReport is the subset of a sub-run's JSON report needed for the comparison.
+type phaseReport struct {
+	Peers map[string]*peer.Stats `json:"peers"`
+}
+
+// LoadPhasePeers reads the peers recorded in a sub-run's JSON report.
+func LoadPhasePeers(path string) (map[string]*peer.Stats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read phase report: %w", err)
+	}
+
+	var report phaseReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse phase report: %w", err)
+	}
+
+	return report.Peers, nil
+}
+
+// PhasePeers holds the peers one phase observed.
+type PhasePeers struct {
+	Phase Phase
+	Peers map[string]*peer.Stats
+}
+
+// peerModeAccumulator collects a peer's observations in one mode before averaging.
+type peerModeAccumulator struct {
+	stats    ModeStats
+	scoreSum float64
+}
+
+// Compare aligns peers across phases and compares their scores and goodbyes by validation mode.
+func Compare(phases []PhasePeers) *Result {
+	result := &Result{
+		Phases: make([]Phase, 0, len(phases)),
+		Modes:  make(map[config.ValidationMode]*ModeSummary),
+		Peers:  make([]PeerComparison, 0),
+	}
+
+	accumulators := make(map[string]map[config.ValidationMode]*peerModeAccumulator)
+	clientTypes := make(map[string]string)
+
+	for _, phase := range phases {
+		result.Phases = append(result.Phases, phase.Phase)
+
+		mode := phase.Phase.Mode
+		if result.Modes[mode] == nil {
+			result.Modes[mode] = &ModeSummary{GoodbyeReasons: make(map[string]int)}
+		}
+
+		result.Modes[mode].Phases++
+
+		for peerID, stats := range phase.Peers {
+			if stats == nil || len(stats.ConnectionSessions) == 0 {
+				continue
+			}
+
+			if accumulators[peerID] == nil {
+				accumulators[peerID] = make(map[config.ValidationMode]*peerModeAccumulator)
+			}
+
+			acc := accumulators[peerID][mode]
+			if acc == nil {
+				acc = &peerModeAccumulator{stats: ModeStats{MinScore: math.Inf(1), GoodbyeReasons: make(map[string]int)}}
+				accumulators[peerID][mode] = acc
+			}
+
+			acc.add(stats)
+
+			if stats.ClientType != "" {
+				clientTypes[peerID] = stats.ClientType
+			}
+		}
+	}
+
+	scoredPeers := make(map[config.ValidationMode]int)
+
+	for peerID, byMode := range accumulators {
+		delegated, independent := byMode[config.ValidationModeDelegated], byMode[config.ValidationModeIndependent]
+
+		switch {
+		case delegated == nil:
+			result.OnlyIndependent++
+
+			continue
+		case independent == nil:
+			result.OnlyDelegated++
+
+			continue
+		}
+
+		comparison := PeerComparison{
+			PeerID:     peerID,
+			ClientType: clientTypes[peerID],
+			Modes:      make(map[config.ValidationMode]*ModeStats, len(byMode)),
+		}
+
+		for mode, acc := range byMode {
+			stats := acc.finish()
+			comparison.Modes[mode] = stats
+
+			summary := result.Modes[mode]
+			summary.Sessions += stats.Sessions
+			summary.Goodbyes += stats.Goodbyes
+
+			for reason, count := range stats.GoodbyeReasons {
+				summary.GoodbyeReasons[reason] += count
+			}
+
+			if stats.ScoreSnapshots > 0 {
+				summary.MeanScore += stats.MeanScore
+				scoredPeers[mode]++
+			}
+		}
+
+		d, i := comparison.Modes[config.ValidationModeDelegated], comparison.Modes[config.ValidationModeIndependent]
+		comparison.GoodbyeRateDelta = i.GoodbyeRate() - d.GoodbyeRate()
+
+		if d.ScoreSnapshots > 0 && i.ScoreSnapshots > 0 {
+			delta := i.MeanScore - d.MeanScore
+			comparison.ScoreDelta = &delta
+		}
+
+		result.Peers = append(result.Peers, comparison)
+	}
+
+	result.AlignedPeers = len(result.Peers)
+
+	for mode, summary := range result.Modes {
+		if scoredPeers[mode] > 0 {
+			summary.MeanScore /= float64(scoredPeers[mode])
+		}
+
+		if summary.Sessions > 0 {
+			summary.GoodbyeRate = float64(summary.Goodbyes) / float64(summary.Sessions)
+		}
+	}
+
+	// Largest score differences first, peers without a score comparison last
+	sort.Slice(result.Peers, func(a, b int) bool {
+		da, db := result.Peers[a].ScoreDelta, result.Peers[b].ScoreDelta
+		if (da == nil) != (db == nil) {
+			return da != nil
+		}
+
+		if da != nil && math.Abs(*da) != math.Abs(*db) {
+			return math.Abs(*da) > math.Abs(*db)
+		}
+
+		return result.Peers[a].PeerID < result.Peers[b].PeerID
+	})
+
+	return result
+}
+
+// add accumulates a peer's sessions from one phase.
+func (a *peerModeAccumulator) add(stats *peer.Stats) {
+	a.stats.Phases++
+	a.stats.Sessions += len(stats.ConnectionSessions)
+
+	for _, session := range stats.ConnectionSessions {
+		for _, snapshot := range session.PeerScores {
+			a.stats.ScoreSnapshots++
+			a.scoreSum += snapshot.Score
+			a.stats.MinScore = math.Min(a.stats.MinScore, snapshot.Score)
+		}
+
+		for _, goodbye := range session.GoodbyeEvents {
+			a.stats.Goodbyes++
+			a.stats.GoodbyeReasons[goodbye.Reason]++
+		}
+	}
+}
+
+// finish returns the accumulated stats with the mean score computed.
+func (a *peerModeAccumulator) finish() *ModeStats {
+	stats := a.stats
+
+	if stats.ScoreSnapshots > 0 {
+		stats.MeanScore = a.scoreSum / float64(stats.ScoreSnapshots)
+	} else {
+		stats.MinScore = 0
+	}
+
+	return &stats
+}
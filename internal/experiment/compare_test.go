@@ -0,0 +1,140 @@
+package experiment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestPlanPhases(t *testing.T) {
+	phases := PlanPhases(3)
+
+	want := []config.ValidationMode{config.ValidationModeDelegated, config.ValidationModeIndependent, config.ValidationModeDelegated}
+	if len(phases) != len(want) {
+		t.Fatalf("PlanPhases(3) = %d phases, want %d", len(phases), len(want))
+	}
+
+	for i, phase := range phases {
+		if phase.Mode != want[i] || phase.Index != i {
+			t.Errorf("phase %d = %+v, want mode %s", i, phase, want[i])
+		}
+	}
+
+	if phases[1].Dir != "phase-02-independent" {
+		t.Errorf("phase dir = %q, want phase-02-independent", phases[1].Dir)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	session := func(goodbyes []string, scores ...float64) peer.ConnectionSession {
+		s := peer.ConnectionSession{ConnectedAt: &now}
+		for _, score := range scores {
+			s.PeerScores = append(s.PeerScores, peer.PeerScoreSnapshot{Score: score})
+		}
+
+		for _, reason := range goodbyes {
+			s.GoodbyeEvents = append(s.GoodbyeEvents, peer.GoodbyeEvent{Reason: reason})
+		}
+
+		return s
+	}
+
+	stats := func(client string, sessions ...peer.ConnectionSession) *peer.Stats {
+		return &peer.Stats{ClientType: client, ConnectionSessions: sessions}
+	}
+
+	phases := []PhasePeers{
+		{
+			Phase: Phase{Index: 0, Mode: config.ValidationModeDelegated},
+			Peers: map[string]*peer.Stats{
+				"a": stats("lighthouse", session(nil, 2, 4)),
+				"b": stats("prysm", session([]string{"too many peers"}, 1)),
+				"c": stats("teku", session(nil, 1)),
+			},
+		},
+		{
+			Phase: Phase{Index: 1, Mode: config.ValidationModeIndependent},
+			Peers: map[string]*peer.Stats{
+				"a": stats("lighthouse", session([]string{"score too low"}, -2)),
+				"b": stats("prysm", session(nil)),
+				"d": stats("nimbus", session(nil, 1)),
+			},
+		},
+		{
+			Phase: Phase{Index: 2, Mode: config.ValidationModeDelegated},
+			Peers: map[string]*peer.Stats{
+				"a": stats("lighthouse", session(nil, 3)),
+				"e": stats("lodestar"), // No sessions, not counted as seen
+			},
+		},
+	}
+
+	result := Compare(phases)
+
+	if result.AlignedPeers != 2 || result.OnlyDelegated != 1 || result.OnlyIndependent != 1 {
+		t.Fatalf("aligned = %d, only delegated = %d, only independent = %d, want 2, 1, 1",
+			result.AlignedPeers, result.OnlyDelegated, result.OnlyIndependent)
+	}
+
+	tests := []struct {
+		name             string
+		index            int
+		peerID           string
+		wantScoreDelta   *float64
+		wantGoodbyeDelta float64
+		wantPhases       int
+	}{
+		{
+			name:             "largest score difference first",
+			index:            0,
+			peerID:           "a",
+			wantScoreDelta:   ptr(-5), // Mean -2 independent vs mean 3 across two delegated phases
+			wantGoodbyeDelta: 1,
+			wantPhases:       2,
+		},
+		{
+			name:             "peer without independent scores has no score delta",
+			index:            1,
+			peerID:           "b",
+			wantGoodbyeDelta: -1,
+			wantPhases:       1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := result.Peers[tt.index]
+			if got.PeerID != tt.peerID {
+				t.Fatalf("peer %d = %s, want %s", tt.index, got.PeerID, tt.peerID)
+			}
+
+			switch {
+			case tt.wantScoreDelta == nil && got.ScoreDelta != nil:
+				t.Errorf("ScoreDelta = %v, want unset", *got.ScoreDelta)
+			case tt.wantScoreDelta != nil && (got.ScoreDelta == nil || *got.ScoreDelta != *tt.wantScoreDelta):
+				t.Errorf("ScoreDelta = %v, want %v", got.ScoreDelta, *tt.wantScoreDelta)
+			}
+
+			if got.GoodbyeRateDelta != tt.wantGoodbyeDelta {
+				t.Errorf("GoodbyeRateDelta = %v, want %v", got.GoodbyeRateDelta, tt.wantGoodbyeDelta)
+			}
+
+			if phases := got.Modes[config.ValidationModeDelegated].Phases; phases != tt.wantPhases {
+				t.Errorf("delegated phases = %d, want %d", phases, tt.wantPhases)
+			}
+		})
+	}
+
+	delegated := result.Modes[config.ValidationModeDelegated]
+	if delegated.Phases != 2 || delegated.Goodbyes != 1 || delegated.GoodbyeReasons["too many peers"] != 1 {
+		t.Errorf("delegated summary = %+v", delegated)
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
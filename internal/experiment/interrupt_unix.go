@@ -0,0 +1,15 @@
+//go:build !windows
+
+package experiment
+
+import (
+	"os"
+	"os/exec"
+)
+
+// interruptOnCancel makes cancelling the context interrupt the sub-run rather than kill it.
+func interruptOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+}
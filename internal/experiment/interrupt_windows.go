@@ -0,0 +1,19 @@
+//go:build windows
+
+package experiment
+
+import (
+	"os/exec"
+
+	"golang.org/x/sys/windows"
+)
+
+// interruptOnCancel makes cancelling the context interrupt the sub-run rather than kill it.
+// Windows cannot signal a process, so the sub-run gets its own console process group and is
+// sent a Ctrl+Break, which Go delivers to it as os.Interrupt.
+func interruptOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &windows.SysProcAttr{CreationFlags: windows.CREATE_NEW_PROCESS_GROUP}
+	cmd.Cancel = func() error {
+		return windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(cmd.Process.Pid))
+	}
+}
@@ -0,0 +1,211 @@
+package experiment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// subRunStopTimeout is how long an interrupted sub-run gets to write its reports.
+const subRunStopTimeout = time.Minute
+
+// modeOrder is the order validation modes alternate in, starting with delegated.
+var modeOrder = []config.ValidationMode{config.ValidationModeDelegated, config.ValidationModeIndependent}
+
+// PlanPhases plans count phases alternating between the validation modes.
+func PlanPhases(count int) []Phase {
+	phases := make([]Phase, 0, count)
+
+	for i := 0; i < count; i++ {
+		mode := modeOrder[i%len(modeOrder)]
+		phases = append(phases, Phase{
+			Index: i,
+			Mode:  mode,
+			Dir:   fmt.Sprintf("phase-%02d-%s", i+1, mode),
+		})
+	}
+
+	return phases
+}
+
+// PlanSweep plans one phase in the given validation mode for each value of the sweep.
+func PlanSweep(mode config.ValidationMode, sweep *config.ParamSweep) []Phase {
+	phases := make([]Phase, 0, len(sweep.Values))
+
+	for i, value := range sweep.Values {
+		phases = append(phases, Phase{
+			Index: i,
+			Mode:  mode,
+			Dir:   fmt.Sprintf("step-%02d-%s-%d", i+1, sweep.Param, value),
+			Args:  []string{fmt.Sprintf("--%s=%d", sweep.Flag(), value)},
+		})
+	}
+
+	return phases
+}
+
+// Runner runs experiment phases as sequential sub-runs of the peer score binary built
+// for each validation mode. The Hermes version is pinned at build time, so modes
+// cannot be switched inside one process.
+type Runner struct {
+	binaries      map[config.ValidationMode]string
+	args          []string
+	phaseDuration time.Duration
+	dir           string
+	logger        logrus.FieldLogger
+}
+
+// NewRunner creates a runner passing args to every sub-run, along with its validation mode and duration.
+func NewRunner(binaries map[config.ValidationMode]string, args []string, phaseDuration time.Duration, dir string, logger logrus.FieldLogger) *Runner {
+	return &Runner{
+		binaries:      binaries,
+		args:          args,
+		phaseDuration: phaseDuration,
+		dir:           dir,
+		logger:        logger.WithField("component", "validation_experiment"),
+	}
+}
+
+// Run runs the phases in order. Failed sub-runs are recorded on their phase and the
+// experiment continues; cancelling ctx interrupts the current sub-run and stops.
+func (r *Runner) Run(ctx context.Context, phases []Phase) ([]Phase, error) {
+	binaries := make(map[config.ValidationMode]string, len(r.binaries))
+
+	for mode, binary := range r.binaries {
+		path, err := resolveBinary(binary)
+		if err != nil {
+			return nil, fmt.Errorf("invalid binary for %s mode: %w", mode, err)
+		}
+
+		binaries[mode] = path
+	}
+
+	// Every sub-run shares one libp2p identity, so peers see the same node throughout
+	privateKey := os.Getenv(constants.PrivateKeyEnv)
+	if privateKey == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate libp2p identity: %w", err)
+		}
+
+		privateKey = hex.EncodeToString(key)
+	}
+
+	env := append(os.Environ(), constants.PrivateKeyEnv+"="+privateKey)
+	completed := make([]Phase, 0, len(phases))
+
+	for _, phase := range phases {
+		if ctx.Err() != nil {
+			break
+		}
+
+		phase.StartedAt = time.Now()
+
+		if err := r.runPhase(ctx, phase, binaries[phase.Mode], env); err != nil {
+			phase.Error = err.Error()
+			r.logger.WithError(err).WithField("phase", phase.Index+1).Warn("Experiment phase failed")
+		}
+
+		phase.EndedAt = time.Now()
+		completed = append(completed, phase)
+	}
+
+	return completed, nil
+}
+
+// runPhase runs one sub-run in the phase's own directory, so its reports and checkpoint stay separate.
+func (r *Runner) runPhase(ctx context.Context, phase Phase, binary string, env []string) error {
+	dir := filepath.Join(r.dir, phase.Dir)
+	if err := os.MkdirAll(dir, constants.DefaultDirPermissions); err != nil {
+		return fmt.Errorf("failed to create phase directory: %w", err)
+	}
+
+	args := append([]string{
+		"--validation-mode=" + string(phase.Mode),
+		"--duration=" + r.phaseDuration.String(),
+	}, r.args...)
+	args = append(args, phase.Args...)
+
+	r.logger.WithFields(logrus.Fields{
+		"phase":    phase.Index + 1,
+		"mode":     phase.Mode,
+		"duration": r.phaseDuration,
+		"dir":      dir,
+	}).Info("Starting experiment phase")
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Interrupt rather than kill, so the sub-run still writes reports for what it observed
+	interruptOnCancel(cmd)
+	cmd.WaitDelay = subRunStopTimeout
+
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("sub-run failed: %w", err)
+	}
+
+	return nil
+}
+
+// ReportFile returns the JSON report a phase's sub-run wrote. Reports are named after the
+// validation mode and the time they were written, so the latest one wins.
+func (r *Runner) ReportFile(phase Phase) (string, error) {
+	name := strings.TrimSuffix(constants.DefaultJSONReportFile, ".json")
+
+	matches, err := filepath.Glob(filepath.Join(r.dir, phase.Dir, fmt.Sprintf("%s-%s-*.json", name, phase.Mode)))
+	if err != nil {
+		return "", fmt.Errorf("failed to list phase reports: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no report written in %s", phase.Dir)
+	}
+
+	sort.Strings(matches)
+
+	return matches[len(matches)-1], nil
+}
+
+// LoadPeers reads the peers recorded in a phase's report.
+func (r *Runner) LoadPeers(phase Phase) (map[string]*peer.Stats, error) {
+	path, err := r.ReportFile(phase)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadPhasePeers(path)
+}
+
+// resolveBinary makes a binary path absolute, since sub-runs run in their phase directory.
+func resolveBinary(binary string) (string, error) {
+	if filepath.Base(binary) == binary {
+		return exec.LookPath(binary)
+	}
+
+	path, err := filepath.Abs(binary)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
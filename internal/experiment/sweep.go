@@ -0,0 +1,92 @@
+package experiment
+
+import (
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// SweepStep summarises the sub-test run with one value of the swept parameter.
+type SweepStep struct {
+	Phase       Phase             `json:"phase"`
+	Value       int               `json:"value"`
+	MeshDegree  config.MeshDegree `json:"mesh_degree"`
+	Reported    bool              `json:"reported"` // Whether the sub-test wrote a report, the stats are zero otherwise
+	Peers       int               `json:"peers"`
+	Sessions    int               `json:"sessions"`
+	MeanScore   float64           `json:"mean_score"` // Mean of per-peer mean scores, over peers with snapshots
+	Goodbyes    int               `json:"goodbyes"`
+	GoodbyeRate float64           `json:"goodbye_rate"` // Goodbyes per session
+	Grafts      int               `json:"grafts"`
+	Prunes      int               `json:"prunes"`
+	PruneRate   float64           `json:"prune_rate"` // Prunes per session
+}
+
+// SweepResult is the outcome of a mesh degree parameter sweep. Unlike the validation
+// experiment, every step sees its own peer set, so steps are compared in aggregate.
+type SweepResult struct {
+	Param         string        `json:"param"`
+	PhaseDuration time.Duration `json:"phase_duration"`
+	Steps         []SweepStep   `json:"steps"` // In the order they ran
+}
+
+// SummarizeSweep summarises each sweep phase from the peers its report recorded, keyed by
+// phase index. Phases without a report are listed with zero stats.
+func SummarizeSweep(sweep *config.ParamSweep, base config.MeshDegree, phases []Phase, peers map[int]map[string]*peer.Stats) *SweepResult {
+	result := &SweepResult{
+		Param: sweep.Param,
+		Steps: make([]SweepStep, 0, len(phases)),
+	}
+
+	for _, phase := range phases {
+		step := SweepStep{
+			Phase:      phase,
+			Value:      sweep.Values[phase.Index],
+			MeshDegree: base.With(sweep.Param, sweep.Values[phase.Index]),
+		}
+
+		stats, ok := peers[phase.Index]
+		step.Reported = ok
+
+		scoredPeers := 0
+
+		for _, p := range stats {
+			if p == nil || len(p.ConnectionSessions) == 0 {
+				continue
+			}
+
+			acc := &peerModeAccumulator{stats: ModeStats{GoodbyeReasons: make(map[string]int)}}
+			acc.add(p)
+
+			peerStats := acc.finish()
+			step.Peers++
+			step.Sessions += peerStats.Sessions
+			step.Goodbyes += peerStats.Goodbyes
+
+			if peerStats.ScoreSnapshots > 0 {
+				step.MeanScore += peerStats.MeanScore
+				scoredPeers++
+			}
+
+			for _, session := range p.ConnectionSessions {
+				grafts, prunes := session.MeshCounts()
+				step.Grafts += grafts
+				step.Prunes += prunes
+			}
+		}
+
+		if scoredPeers > 0 {
+			step.MeanScore /= float64(scoredPeers)
+		}
+
+		if step.Sessions > 0 {
+			step.GoodbyeRate = float64(step.Goodbyes) / float64(step.Sessions)
+			step.PruneRate = float64(step.Prunes) / float64(step.Sessions)
+		}
+
+		result.Steps = append(result.Steps, step)
+	}
+
+	return result
+}
@@ -0,0 +1,87 @@
+package experiment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestPlanSweep(t *testing.T) {
+	sweep := &config.ParamSweep{Param: config.MeshParamD, Values: []int{6, 10}}
+
+	phases := PlanSweep(config.ValidationModeIndependent, sweep)
+	if len(phases) != 2 {
+		t.Fatalf("PlanSweep = %d phases, want 2", len(phases))
+	}
+
+	if phases[1].Dir != "step-02-d-10" || phases[1].Mode != config.ValidationModeIndependent {
+		t.Errorf("phase 2 = %+v, want dir step-02-d-10 in independent mode", phases[1])
+	}
+
+	if len(phases[1].Args) != 1 || phases[1].Args[0] != "--gossip-d=10" {
+		t.Errorf("phase 2 args = %v, want [--gossip-d=10]", phases[1].Args)
+	}
+}
+
+func TestSummarizeSweep(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	session := func(goodbye bool, mesh []string, scores ...float64) peer.ConnectionSession {
+		s := peer.ConnectionSession{ConnectedAt: &now}
+		for _, score := range scores {
+			s.PeerScores = append(s.PeerScores, peer.PeerScoreSnapshot{Score: score})
+		}
+
+		if goodbye {
+			s.GoodbyeEvents = append(s.GoodbyeEvents, peer.GoodbyeEvent{Reason: "too many peers"})
+		}
+
+		for _, eventType := range mesh {
+			s.MeshEvents = append(s.MeshEvents, peer.MeshEvent{Type: eventType})
+		}
+
+		return s
+	}
+
+	sweep := &config.ParamSweep{Param: config.MeshParamD, Values: []int{6, 10}}
+	phases := PlanSweep(config.ValidationModeDelegated, sweep)
+
+	result := SummarizeSweep(sweep, config.DefaultMeshDegree(), phases, map[int]map[string]*peer.Stats{
+		0: {
+			"a": {ConnectionSessions: []peer.ConnectionSession{
+				session(true, []string{peer.MeshGraft, peer.MeshPrune}, 2, 4),
+				session(false, nil),
+			}},
+			"b": {ConnectionSessions: []peer.ConnectionSession{session(false, []string{peer.MeshPrune}, 1)}},
+			"c": {}, // No sessions, not counted
+		},
+	})
+
+	if len(result.Steps) != 2 {
+		t.Fatalf("steps = %d, want 2", len(result.Steps))
+	}
+
+	step := result.Steps[0]
+	if !step.Reported || step.MeshDegree.D != 6 || step.MeshDegree.Dhi != 12 {
+		t.Errorf("step 1 = %+v, want reported with D=6 Dhi=12", step)
+	}
+
+	if step.Peers != 2 || step.Sessions != 3 || step.Goodbyes != 1 || step.Grafts != 1 || step.Prunes != 2 {
+		t.Errorf("step 1 counts = %+v", step)
+	}
+
+	// Peer a means 3 and peer b means 1
+	if step.MeanScore != 2 {
+		t.Errorf("MeanScore = %v, want 2", step.MeanScore)
+	}
+
+	if step.PruneRate != 2.0/3.0 || step.GoodbyeRate != 1.0/3.0 {
+		t.Errorf("rates = %v prune, %v goodbye, want 2/3 and 1/3", step.PruneRate, step.GoodbyeRate)
+	}
+
+	if missing := result.Steps[1]; missing.Reported || missing.Peers != 0 || missing.MeshDegree.D != 10 {
+		t.Errorf("step 2 = %+v, want unreported with D=10", missing)
+	}
+}
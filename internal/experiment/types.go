@@ -0,0 +1,71 @@
+package experiment
+
+import (
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+)
+
+// Phase is one sub-run of the experiment, run with a single validation mode.
+type Phase struct {
+	Index     int                   `json:"index"`
+	Mode      config.ValidationMode `json:"mode"`
+	Dir       string                `json:"dir"`            // Working directory the sub-run wrote its reports to
+	Args      []string              `json:"args,omitempty"` // Extra flags for this phase, overriding the shared ones
+	StartedAt time.Time             `json:"started_at"`
+	EndedAt   time.Time             `json:"ended_at"`
+	Error     string                `json:"error,omitempty"`
+}
+
+// ModeStats aggregates one peer's observations across every phase run in one validation mode.
+type ModeStats struct {
+	Phases         int            `json:"phases"` // Phases the peer was seen in
+	Sessions       int            `json:"sessions"`
+	ScoreSnapshots int            `json:"score_snapshots"`
+	MeanScore      float64        `json:"mean_score"`
+	MinScore       float64        `json:"min_score"`
+	Goodbyes       int            `json:"goodbyes"`
+	GoodbyeReasons map[string]int `json:"goodbye_reasons,omitempty"`
+}
+
+// GoodbyeRate returns goodbyes received per connection session.
+func (s *ModeStats) GoodbyeRate() float64 {
+	if s.Sessions == 0 {
+		return 0
+	}
+
+	return float64(s.Goodbyes) / float64(s.Sessions)
+}
+
+// PeerComparison compares one peer seen in both validation modes. Deltas are
+// independent minus delegated, so a negative score delta means the peer scored
+// lower under independent validation.
+type PeerComparison struct {
+	PeerID           string                               `json:"peer_id"`
+	ClientType       string                               `json:"client_type"`
+	Modes            map[config.ValidationMode]*ModeStats `json:"modes"`
+	ScoreDelta       *float64                             `json:"score_delta,omitempty"` // Unset unless both modes have score snapshots
+	GoodbyeRateDelta float64                              `json:"goodbye_rate_delta"`
+}
+
+// ModeSummary aggregates the aligned peers' observations in one validation mode.
+type ModeSummary struct {
+	Phases         int            `json:"phases"`
+	Sessions       int            `json:"sessions"`
+	MeanScore      float64        `json:"mean_score"` // Mean of per-peer mean scores, over peers with snapshots
+	Goodbyes       int            `json:"goodbyes"`
+	GoodbyeRate    float64        `json:"goodbye_rate"`
+	GoodbyeReasons map[string]int `json:"goodbye_reasons,omitempty"`
+}
+
+// Result is the outcome of a validation mode experiment. Only peers seen in both
+// modes are compared, so differences are not down to a changed peer set.
+type Result struct {
+	PhaseDuration   time.Duration                          `json:"phase_duration"`
+	Phases          []Phase                                `json:"phases"`
+	AlignedPeers    int                                    `json:"aligned_peers"`
+	OnlyDelegated   int                                    `json:"only_delegated"`
+	OnlyIndependent int                                    `json:"only_independent"`
+	Modes           map[config.ValidationMode]*ModeSummary `json:"modes"`
+	Peers           []PeerComparison                       `json:"peers"` // Largest score differences first
+}
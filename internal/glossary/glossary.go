@@ -0,0 +1,50 @@
+// Package glossary defines the metrics reports show, for the readers of shared reports. Each
+// package declares the definitions of its metrics next to the code computing them, so a change
+// to a metric and to its definition land together.
+package glossary
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Definition explains one metric.
+type Definition struct {
+	Name    string   `json:"name"`              // As reports label the metric
+	Field   string   `json:"field"`             // JSON field of the struct holding it
+	Formula string   `json:"formula"`           // How it is computed
+	Caveats []string `json:"caveats,omitempty"` // Where it misleads, or what it does not mean
+}
+
+// Group holds the definitions of the metrics one struct carries.
+type Group struct {
+	Title       string       `json:"title"`
+	Source      string       `json:"source"` // Where reports carry the metrics
+	Definitions []Definition `json:"definitions"`
+}
+
+// Unknown returns the fields of the definitions that are not JSON fields of v, a struct or a
+// pointer to one. A definition left behind by a renamed or removed field shows up here.
+func Unknown(definitions []Definition, v interface{}) []string {
+	fields := make(map[string]bool)
+
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fields[name] = true
+	}
+
+	unknown := make([]string, 0)
+
+	for _, definition := range definitions {
+		if !fields[definition.Field] {
+			unknown = append(unknown, definition.Field)
+		}
+	}
+
+	return unknown
+}
@@ -0,0 +1,28 @@
+package glossary
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnknown(t *testing.T) {
+	type metrics struct {
+		Peers    int     `json:"peers"`
+		Rate     float64 `json:"rate,omitempty"`
+		Internal int     `json:"-"`
+	}
+
+	definitions := []Definition{
+		{Name: "Peers", Field: "peers"},
+		{Name: "Rate", Field: "rate"},
+		{Name: "Renamed", Field: "peer_count"},
+	}
+
+	want := []string{"peer_count"}
+
+	for _, v := range []interface{}{metrics{}, &metrics{}} {
+		if got := Unknown(definitions, v); !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected unknown fields %v, got %v", want, got)
+		}
+	}
+}
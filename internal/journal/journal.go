@@ -0,0 +1,216 @@
+// Package journal keeps a run's warnings and errors in a structured file next to its reports,
+// so an odd report can be debugged without running again with debug logging.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+)
+
+// Log fields the journal lifts out of an entry's fields into its own.
+const (
+	FieldComponent   = "component"
+	FieldHandler     = "handler"
+	FieldEventType   = "event_type"
+	FieldPeerID      = "peer_id"
+	FieldFingerprint = "event_fingerprint"
+)
+
+// Entry is one journaled warning or error.
+type Entry struct {
+	Time        time.Time         `json:"time"`
+	Level       string            `json:"level"`
+	Message     string            `json:"message"`
+	Error       string            `json:"error,omitempty"`
+	Component   string            `json:"component,omitempty"`
+	Handler     string            `json:"handler,omitempty"`
+	EventType   string            `json:"event_type,omitempty"`
+	PeerID      string            `json:"peer_id,omitempty"`
+	Fingerprint string            `json:"event_fingerprint,omitempty"` // Of the payload of the event the entry is about
+	Fields      map[string]string `json:"fields,omitempty"`            // The entry's other log fields
+}
+
+// Journal writes the warnings and errors logged during a run as JSON lines. It is a logrus
+// hook, so everything logged at warning level or above through the hooked logger is kept.
+// It is safe for concurrent use.
+type Journal struct {
+	mu      sync.Mutex
+	out     io.Writer
+	closer  io.Closer
+	path    string
+	entries int
+	err     error         // First write error, after which entries are dropped
+	policy  *prune.Policy // Fields dropped or hashed in every entry, nil writes them as they are
+}
+
+// New creates a journal writing to out.
+func New(out io.Writer) *Journal {
+	return &Journal{out: out}
+}
+
+// Open creates a journal writing to a file, appending to it when resuming a run and
+// truncating it otherwise.
+func Open(path string, resume bool) (*Journal, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if resume {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open error journal: %w", err)
+	}
+
+	return &Journal{out: file, closer: file, path: path}, nil
+}
+
+// SetPrunePolicy sets the fields dropped or hashed in every entry written from now on, as in
+// the reports. Paths match the entry's JSON, log fields sit under "fields".
+func (j *Journal) SetPrunePolicy(policy *prune.Policy) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.policy = policy
+}
+
+// Path returns the file the journal writes to, empty when it writes elsewhere.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Entries returns how many entries were written so far.
+func (j *Journal) Entries() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.entries
+}
+
+// Levels returns the levels the journal keeps, warnings and above.
+func (j *Journal) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+// Fire writes a log entry to the journal. A journal that cannot be written must not break
+// logging, so write errors are kept for Close instead of returned.
+func (j *Journal) Fire(entry *logrus.Entry) error {
+	j.Write(FromLogEntry(entry))
+
+	return nil
+}
+
+// Write writes an entry as one JSON line.
+func (j *Journal) Write(entry Entry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.out == nil || j.err != nil {
+		return
+	}
+
+	line, err := j.marshal(entry)
+	if err != nil {
+		j.err = fmt.Errorf("failed to marshal journal entry: %w", err)
+
+		return
+	}
+
+	if _, err := j.out.Write(append(line, '\n')); err != nil {
+		j.err = fmt.Errorf("failed to write journal entry: %w", err)
+
+		return
+	}
+
+	j.entries++
+}
+
+// marshal encodes an entry as JSON, pruned when a policy is set.
+func (j *Journal) marshal(entry Entry) ([]byte, error) {
+	line, err := json.Marshal(entry)
+	if err != nil || j.policy == nil {
+		return line, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(line, &decoded); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(j.policy.Value(decoded))
+}
+
+// Close closes the journal's file, if it opened one, and returns the first write error. Entries
+// logged after Close are dropped.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.out = nil
+
+	if j.closer != nil {
+		if err := j.closer.Close(); err != nil && j.err == nil {
+			j.err = fmt.Errorf("failed to close error journal: %w", err)
+		}
+
+		j.closer = nil
+	}
+
+	return j.err
+}
+
+// FromLogEntry converts a log entry, lifting the error and the fields the journal knows out of
+// the entry's fields.
+func FromLogEntry(entry *logrus.Entry) Entry {
+	journaled := Entry{
+		Time:    entry.Time.UTC(),
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := entry.Data[key]
+
+		switch key {
+		case logrus.ErrorKey:
+			if err, ok := value.(error); ok {
+				journaled.Error = err.Error()
+			} else {
+				journaled.Error = fmt.Sprint(value)
+			}
+		case FieldComponent:
+			journaled.Component = fmt.Sprint(value)
+		case FieldHandler:
+			journaled.Handler = fmt.Sprint(value)
+		case FieldEventType:
+			journaled.EventType = fmt.Sprint(value)
+		case FieldPeerID:
+			journaled.PeerID = fmt.Sprint(value)
+		case FieldFingerprint:
+			journaled.Fingerprint = fmt.Sprint(value)
+		default:
+			if journaled.Fields == nil {
+				journaled.Fields = make(map[string]string)
+			}
+
+			journaled.Fields[key] = fmt.Sprint(value)
+		}
+	}
+
+	return journaled
+}
@@ -0,0 +1,141 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+)
+
+func TestJournalHook(t *testing.T) {
+	var out bytes.Buffer
+
+	journal := New(&out)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(journal)
+
+	entry := logger.WithField(FieldComponent, "core_tool")
+	entry.Info("Not journaled")
+	entry.WithError(errors.New("invalid goodbye code")).WithFields(logrus.Fields{
+		FieldHandler:     "goodbye",
+		FieldEventType:   "HANDLE_GOODBYE",
+		FieldFingerprint: "3f9a0c1d2b4e5f60",
+		"attempt":        2,
+	}).Error("failed to parse goodbye data")
+	entry.Warn("Event hook failed")
+
+	if journal.Entries() != 2 {
+		t.Fatalf("Expected 2 entries, got %d", journal.Entries())
+	}
+
+	lines := make([]Entry, 0, 2)
+
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var line Entry
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Expected a JSON object per line, got %q: %v", scanner.Text(), err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	parsed := lines[0]
+	if parsed.Level != "error" || parsed.Error != "invalid goodbye code" || parsed.Component != "core_tool" || parsed.Handler != "goodbye" {
+		t.Errorf("Unexpected entry %+v", parsed)
+	}
+
+	if parsed.EventType != "HANDLE_GOODBYE" || parsed.Fingerprint != "3f9a0c1d2b4e5f60" {
+		t.Errorf("Expected the event to be identified, got %+v", parsed)
+	}
+
+	if len(parsed.Fields) != 1 || parsed.Fields["attempt"] != "2" {
+		t.Errorf("Expected the other fields to be kept, got %v", parsed.Fields)
+	}
+
+	if lines[1].Level != "warning" || lines[1].Fields != nil {
+		t.Errorf("Unexpected warning entry %+v", lines[1])
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.ndjson")
+
+	write := func(resume bool, message string) {
+		journal, err := Open(path, resume)
+		if err != nil {
+			t.Fatalf("Expected no error opening the journal, got %v", err)
+		}
+
+		journal.Write(Entry{Level: "error", Message: message})
+
+		if err := journal.Close(); err != nil {
+			t.Fatalf("Expected no error closing, got %v", err)
+		}
+
+		// Entries after Close are dropped
+		journal.Write(Entry{Level: "error", Message: "dropped"})
+	}
+
+	lines := func() int {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected the journal file, got %v", err)
+		}
+
+		return bytes.Count(data, []byte("\n"))
+	}
+
+	write(false, "first run")
+	write(true, "resumed")
+
+	if got := lines(); got != 2 {
+		t.Errorf("Expected a resumed run to append, got %d lines", got)
+	}
+
+	write(false, "next run")
+
+	if got := lines(); got != 1 {
+		t.Errorf("Expected a new run to truncate, got %d lines", got)
+	}
+}
+
+func TestJournalPrunePolicy(t *testing.T) {
+	var out bytes.Buffer
+
+	policy, err := prune.New([]string{"fields.client_agent"}, []string{"peer_id"}, "")
+	if err != nil {
+		t.Fatalf("Expected a valid policy, got %v", err)
+	}
+
+	journal := New(&out)
+	journal.SetPrunePolicy(policy)
+	journal.Write(Entry{Message: "Peer identified", PeerID: "16Uiu2HAmPeer", Fields: map[string]string{
+		"client_agent": "Lighthouse/v7.0.1-e42406d/x86_64-linux",
+		"client_type":  "lighthouse",
+	}})
+
+	var line Entry
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON line, got %q: %v", out.String(), err)
+	}
+
+	if _, ok := line.Fields["client_agent"]; ok || line.Fields["client_type"] != "lighthouse" {
+		t.Errorf("Expected only the agent dropped, got %+v", line.Fields)
+	}
+
+	if !strings.HasPrefix(line.PeerID, prune.HashPrefix) {
+		t.Errorf("Expected the peer ID hashed, got %q", line.PeerID)
+	}
+}
@@ -0,0 +1,94 @@
+package peer
+
+import "fmt"
+
+// Sides of a connection that initiated its disconnect.
+const (
+	InitiatorLocal  = "local"
+	InitiatorRemote = "remote"
+)
+
+// Goodbye severities, from routine to the peer penalising us.
+const (
+	GoodbyeInfo     = "info"
+	GoodbyeWarning  = "warning"
+	GoodbyeError    = "error"
+	GoodbyeCritical = "critical"
+)
+
+// goodbyeSeverities maps the goodbye codes of the consensus spec and the clients' extensions.
+var goodbyeSeverities = map[uint64]string{
+	1:   GoodbyeInfo,     // Client shut down
+	2:   GoodbyeWarning,  // Irrelevant network
+	3:   GoodbyeError,    // Fault or error
+	128: GoodbyeWarning,  // Unable to verify network
+	129: GoodbyeInfo,     // Too many peers
+	250: GoodbyeCritical, // Bad score
+	251: GoodbyeCritical, // Banned
+	252: GoodbyeCritical, // Banned IP
+}
+
+// SessionAttribution is how a session was opened and ended, for the peer modal's timeline.
+type SessionAttribution struct {
+	Direction           string   `json:"direction,omitempty"`
+	DisconnectInitiator string   `json:"disconnect_initiator,omitempty"` // One of the Initiator constants, empty when unknown
+	InitiatorReason     string   `json:"initiator_reason,omitempty"`
+	GoodbyeSeverities   []string `json:"goodbye_severities"` // One of the Goodbye constants per goodbye event, in order
+}
+
+// GoodbyeSeverity classifies a goodbye code. Codes no client documents are warnings.
+func GoodbyeSeverity(code uint64) string {
+	if severity, ok := goodbyeSeverities[code]; ok {
+		return severity
+	}
+
+	return GoodbyeWarning
+}
+
+// AttributeSessions attributes each session in order. Hermes never sends a goodbye, so a
+// goodbye from the peer means it initiated the disconnect, unless we had already ended the
+// session ourselves. A session closed by a collector gap has no known initiator.
+func AttributeSessions(sessions []ConnectionSession) []SessionAttribution {
+	attributions := make([]SessionAttribution, 0, len(sessions))
+
+	for i := range sessions {
+		session := &sessions[i]
+		attribution := SessionAttribution{
+			Direction:         session.Direction,
+			GoodbyeSeverities: make([]string, 0, len(session.GoodbyeEvents)),
+		}
+
+		for _, goodbye := range session.GoodbyeEvents {
+			attribution.GoodbyeSeverities = append(attribution.GoodbyeSeverities, GoodbyeSeverity(goodbye.Code))
+		}
+
+		attribution.DisconnectInitiator, attribution.InitiatorReason = disconnectInitiator(session)
+		attributions = append(attributions, attribution)
+	}
+
+	return attributions
+}
+
+// disconnectInitiator returns the side that ended a disconnected session and why we know.
+func disconnectInitiator(session *ConnectionSession) (string, string) {
+	switch {
+	case !session.Disconnected, session.EndedByGap:
+		return "", ""
+	case session.EndedInShutdown:
+		return InitiatorLocal, "Hermes shut down after the run"
+	case session.EndedByRampRestart:
+		return InitiatorLocal, "Hermes restarted into the next MaxPeers ramp step"
+	case session.EndedByLocalLimit:
+		return InitiatorLocal, "Closed without a goodbye while we were at our peer limit"
+	case session.EndedByResourceLimit:
+		return InitiatorLocal, "Closed without a goodbye while our resource manager was refusing resources"
+	}
+
+	for _, goodbye := range session.GoodbyeEvents {
+		if !goodbye.InShutdown {
+			return InitiatorRemote, fmt.Sprintf("The peer said goodbye (%d: %s)", goodbye.Code, goodbye.Reason)
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,39 @@
+package peer
+
+import "testing"
+
+func TestAttributeSessions(t *testing.T) {
+	sessions := []ConnectionSession{
+		{Direction: DirectionOutbound},
+		{Direction: DirectionInbound, Disconnected: true, GoodbyeEvents: []GoodbyeEvent{{Code: 129, Reason: "too many peers"}, {Code: 250, Reason: "bad score"}}},
+		{Direction: DirectionOutbound, Disconnected: true, EndedByLocalLimit: true},
+		{Disconnected: true, EndedByGap: true, GoodbyeEvents: []GoodbyeEvent{{Code: 3}}},
+		{Disconnected: true, EndedInShutdown: true, GoodbyeEvents: []GoodbyeEvent{{Code: 1, InShutdown: true}}},
+		{Disconnected: true},
+	}
+
+	wantInitiators := []string{"", InitiatorRemote, InitiatorLocal, "", InitiatorLocal, ""}
+
+	attributions := AttributeSessions(sessions)
+	if len(attributions) != len(sessions) {
+		t.Fatalf("Expected an attribution per session, got %d", len(attributions))
+	}
+
+	for i, attribution := range attributions {
+		if attribution.DisconnectInitiator != wantInitiators[i] || attribution.Direction != sessions[i].Direction {
+			t.Errorf("Session %d: expected %q initiated, got %+v", i, wantInitiators[i], attribution)
+		}
+
+		if (attribution.DisconnectInitiator == "") != (attribution.InitiatorReason == "") {
+			t.Errorf("Session %d: expected a reason exactly when the initiator is known, got %+v", i, attribution)
+		}
+	}
+
+	if severities := attributions[1].GoodbyeSeverities; len(severities) != 2 || severities[0] != GoodbyeInfo || severities[1] != GoodbyeCritical {
+		t.Errorf("Expected info then critical goodbyes, got %v", severities)
+	}
+
+	if severity := GoodbyeSeverity(42); severity != GoodbyeWarning {
+		t.Errorf("Expected an undocumented code to be a warning, got %s", severity)
+	}
+}
@@ -0,0 +1,85 @@
+package peer
+
+import (
+	"time"
+)
+
+// BootstrapNode is a boot node or static peer handed to discv5 to start discovery from.
+type BootstrapNode struct {
+	PeerID  string `json:"peer_id"`
+	ENR     string `json:"enr"`
+	Address string `json:"address,omitempty"` // IP and TCP port the ENR advertises
+	Origin  string `json:"origin"`            // OriginBootnode or OriginStatic
+}
+
+// BootstrapNodeStatus records whether a bootstrap node was connected to during the run and
+// how it responded. Discv5 lookups run over UDP and are not traced by Hermes, so a node is
+// only seen as contacted once a libp2p connection to it opened.
+type BootstrapNodeStatus struct {
+	BootstrapNode
+	ClientType           string     `json:"client_type,omitempty"`
+	Contacted            bool       `json:"contacted"`  // At least one connection, in either direction
+	Responsive           bool       `json:"responsive"` // At least one successful handshake
+	Sessions             int        `json:"sessions"`
+	SuccessfulHandshakes int        `json:"successful_handshakes"`
+	FailedHandshakes     int        `json:"failed_handshakes"`
+	FirstConnectedAt     *time.Time `json:"first_connected_at,omitempty"`
+	SecondsToConnect     float64    `json:"seconds_to_connect,omitempty"` // From the start of the run to the first connection
+	ConnectedAtEnd       bool       `json:"connected_at_end"`
+}
+
+// BootstrapNodeReport looks up each bootstrap node in the peer statistics, in the order the
+// nodes were given.
+func BootstrapNodeReport(nodes []BootstrapNode, peers map[string]*Stats, start time.Time) []BootstrapNodeStatus {
+	statuses := make([]BootstrapNodeStatus, 0, len(nodes))
+
+	for _, node := range nodes {
+		status := BootstrapNodeStatus{BootstrapNode: node}
+
+		stats := peers[node.PeerID]
+		if stats == nil || len(stats.ConnectionSessions) == 0 {
+			statuses = append(statuses, status)
+
+			continue
+		}
+
+		status.ClientType = stats.ClientType
+		status.Contacted = true
+		status.Sessions = len(stats.ConnectionSessions)
+		status.SuccessfulHandshakes = stats.SuccessfulHandshakes
+		status.FailedHandshakes = stats.FailedHandshakes
+		status.Responsive = stats.SuccessfulHandshakes > 0
+
+		for _, session := range stats.ConnectionSessions {
+			if session.ConnectedAt != nil && (status.FirstConnectedAt == nil || session.ConnectedAt.Before(*status.FirstConnectedAt)) {
+				connectedAt := *session.ConnectedAt
+				status.FirstConnectedAt = &connectedAt
+			}
+		}
+
+		if status.FirstConnectedAt != nil && !start.IsZero() {
+			status.SecondsToConnect = max(status.FirstConnectedAt.Sub(start).Seconds(), 0)
+		}
+
+		// Sessions closed by our own shutdown were still open when the run ended
+		last := stats.ConnectionSessions[len(stats.ConnectionSessions)-1]
+		status.ConnectedAtEnd = !last.Disconnected || last.EndedInShutdown
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// UnreachedBootstrapNodes counts the bootstrap nodes no connection was opened to.
+func UnreachedBootstrapNodes(statuses []BootstrapNodeStatus) int {
+	unreached := 0
+
+	for _, status := range statuses {
+		if !status.Contacted {
+			unreached++
+		}
+	}
+
+	return unreached
+}
@@ -0,0 +1,60 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBootstrapNodeReport(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	connected := start.Add(30 * time.Second)
+	reconnected := start.Add(10 * time.Minute)
+
+	nodes := []BootstrapNode{
+		{PeerID: "boot-1", Address: "10.0.0.1:9000", Origin: OriginBootnode},
+		{PeerID: "boot-2", Origin: OriginBootnode},
+		{PeerID: "static-1", Origin: OriginStatic},
+	}
+
+	peers := map[string]*Stats{
+		"boot-1": {
+			ClientType:           "lighthouse",
+			SuccessfulHandshakes: 1,
+			FailedHandshakes:     1,
+			ConnectionSessions: []ConnectionSession{
+				{ConnectedAt: &connected, Disconnected: true},
+				{ConnectedAt: &reconnected, Disconnected: true, EndedInShutdown: true},
+			},
+		},
+		"static-1": {
+			FailedHandshakes:   1,
+			ConnectionSessions: []ConnectionSession{{ConnectedAt: &connected, Disconnected: true}},
+		},
+	}
+
+	statuses := BootstrapNodeReport(nodes, peers, start)
+	if len(statuses) != 3 {
+		t.Fatalf("Expected a status per node, got %d", len(statuses))
+	}
+
+	boot := statuses[0]
+	if !boot.Contacted || !boot.Responsive || boot.Sessions != 2 || boot.ClientType != "lighthouse" {
+		t.Errorf("Unexpected status for the reached boot node %+v", boot)
+	}
+
+	if boot.SecondsToConnect != 30 || !boot.ConnectedAtEnd {
+		t.Errorf("Expected a first connection after 30s still open at the end, got %+v", boot)
+	}
+
+	if statuses[1].Contacted || statuses[1].Responsive {
+		t.Errorf("Expected the unseen boot node not to be contacted, got %+v", statuses[1])
+	}
+
+	if static := statuses[2]; !static.Contacted || static.Responsive || static.ConnectedAtEnd {
+		t.Errorf("Expected the static peer contacted without a handshake, got %+v", static)
+	}
+
+	if unreached := UnreachedBootstrapNodes(statuses); unreached != 1 {
+		t.Errorf("Expected 1 unreached node, got %d", unreached)
+	}
+}
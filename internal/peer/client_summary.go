@@ -0,0 +1,228 @@
+package peer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/glossary"
+)
+
+// ClientSummary aggregates the peers of one client type.
+type ClientSummary struct {
+	Client                string  `json:"client"`
+	Peers                 int     `json:"peers"`
+	Sessions              int     `json:"sessions"`
+	Disconnects           int     `json:"disconnects"`    // Before the run's shutdown
+	GoodbyeEvents         int     `json:"goodbye_events"` // Before the run's shutdown
+	SuccessfulHandshakes  int     `json:"successful_handshakes"`
+	FailedHandshakes      int     `json:"failed_handshakes"`
+	HandshakeSuccessRate  float64 `json:"handshake_success_rate"`  // Of the handshakes attempted
+	MedianDurationSeconds float64 `json:"median_duration_seconds"` // Of disconnected sessions
+	MedianScore           float64 `json:"median_score"`            // Of each scored peer's latest score, weighted by sampling
+	ScoredPeers           int     `json:"scored_peers"`
+	ReqRespAbuse          int     `json:"reqresp_abuse"` // Rate limited or malformed requests the client's peers sent us
+}
+
+// ClientSummaryDefinitions define the ClientSummary metrics.
+var ClientSummaryDefinitions = []glossary.Definition{
+	{
+		Name:    "Sessions",
+		Field:   "sessions",
+		Formula: "Sessions of the client's peers",
+	},
+	{
+		Name:    "Disconnects",
+		Field:   "disconnects",
+		Formula: "Sessions that disconnected before the run's shutdown",
+		Caveats: []string{"Either side may have closed the connection"},
+	},
+	{
+		Name:    "Goodbye Events",
+		Field:   "goodbye_events",
+		Formula: "Goodbye messages received before the run's shutdown",
+	},
+	{
+		Name:    "Handshake Success Rate",
+		Field:   "handshake_success_rate",
+		Formula: "Successful handshakes / (successful + failed handshakes)",
+		Caveats: []string{"Zero when none was attempted"},
+	},
+	{
+		Name:    "Median Duration",
+		Field:   "median_duration_seconds",
+		Formula: "Median seconds from connect to disconnect of the sessions counted as disconnects",
+		Caveats: []string{"Sessions still open at the end are left out, so long lived peers pull it down"},
+	},
+	{
+		Name:    "Median Score",
+		Field:   "median_score",
+		Formula: "Median of each scored peer's latest score snapshot",
+		Caveats: []string{"Zero when none of the client's peers was scored; check Scored Peers"},
+	},
+}
+
+// GoodbyeReasonCount counts the goodbyes sent with one code and reason.
+type GoodbyeReasonCount struct {
+	Code   uint64 `json:"code"`
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// SummarizeClients aggregates peers per client type, largest client first.
+func SummarizeClients(peers map[string]*Stats) []ClientSummary {
+	byClient := make(map[string]*ClientSummary)
+	durations := make(map[string][]float64)
+	scores := make(map[string][]float64)
+	scoreWeights := make(map[string][]float64)
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		client := stats.ClientType
+		if client == "" {
+			client = "unknown"
+		}
+
+		summary, ok := byClient[client]
+		if !ok {
+			summary = &ClientSummary{Client: client}
+			byClient[client] = summary
+		}
+
+		summary.Peers++
+		summary.SuccessfulHandshakes += stats.SuccessfulHandshakes
+		summary.FailedHandshakes += stats.FailedHandshakes
+
+		if stats.ReqRespAbuse != nil {
+			summary.ReqRespAbuse += stats.ReqRespAbuse.Total
+		}
+
+		var latest *PeerScoreSnapshot
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+
+			summary.Sessions++
+			summary.GoodbyeEvents += len(churnGoodbyes(session))
+
+			for j := range session.PeerScores {
+				if latest == nil || session.PeerScores[j].Timestamp.After(latest.Timestamp) {
+					latest = &session.PeerScores[j]
+				}
+			}
+
+			if !churned(session) {
+				continue
+			}
+
+			summary.Disconnects++
+
+			if duration, ok := completedDuration(*session); ok {
+				durations[client] = append(durations[client], duration.Seconds())
+			}
+		}
+
+		if latest != nil {
+			scores[client] = append(scores[client], latest.Score)
+			scoreWeights[client] = append(scoreWeights[client], stats.DetailWeight())
+		}
+	}
+
+	summaries := make([]ClientSummary, 0, len(byClient))
+
+	for client, summary := range byClient {
+		if handshakes := summary.SuccessfulHandshakes + summary.FailedHandshakes; handshakes > 0 {
+			summary.HandshakeSuccessRate = float64(summary.SuccessfulHandshakes) / float64(handshakes)
+		}
+
+		summary.MedianDurationSeconds = median(durations[client])
+		summary.MedianScore = WeightedMedian(scores[client], scoreWeights[client])
+		summary.ScoredPeers = len(scores[client])
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Peers != summaries[j].Peers {
+			return summaries[i].Peers > summaries[j].Peers
+		}
+
+		return summaries[i].Client < summaries[j].Client
+	})
+
+	return summaries
+}
+
+// SummarizeClientsFromInterface aggregates generic peer data per client type.
+func SummarizeClientsFromInterface(peers map[string]interface{}) []ClientSummary {
+	return SummarizeClients(statsFromInterface(peers))
+}
+
+// goodbyeReasonKey identifies a goodbye reason by its code and trimmed reason.
+type goodbyeReasonKey struct {
+	code   uint64
+	reason string
+}
+
+// countGoodbyeReason adds a goodbye to the counts by code and reason.
+func countGoodbyeReason(counts map[goodbyeReasonKey]*GoodbyeReasonCount, goodbye GoodbyeEvent) {
+	key := goodbyeReasonKey{code: goodbye.Code, reason: strings.TrimSpace(goodbye.Reason)}
+
+	if counts[key] == nil {
+		counts[key] = &GoodbyeReasonCount{Code: key.code, Reason: key.reason}
+	}
+
+	counts[key].Count++
+}
+
+// rankGoodbyeReasons returns the counted goodbye reasons most frequent first, at most limit.
+func rankGoodbyeReasons(counts map[goodbyeReasonKey]*GoodbyeReasonCount, limit int) []GoodbyeReasonCount {
+	reasons := make([]GoodbyeReasonCount, 0, len(counts))
+	for _, count := range counts {
+		reasons = append(reasons, *count)
+	}
+
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+
+		if reasons[i].Code != reasons[j].Code {
+			return reasons[i].Code < reasons[j].Code
+		}
+
+		return reasons[i].Reason < reasons[j].Reason
+	})
+
+	if len(reasons) > limit {
+		reasons = reasons[:limit]
+	}
+
+	return reasons
+}
+
+// TopGoodbyeReasons returns the most frequent goodbye code and reason pairs, at most limit.
+// Goodbyes sent during the run's shutdown are left out.
+func TopGoodbyeReasons(peers map[string]*Stats, limit int) []GoodbyeReasonCount {
+	counts := make(map[goodbyeReasonKey]*GoodbyeReasonCount)
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			for _, goodbye := range churnGoodbyes(&stats.ConnectionSessions[i]) {
+				countGoodbyeReason(counts, goodbye)
+			}
+		}
+	}
+
+	return rankGoodbyeReasons(counts, limit)
+}
+
+// TopGoodbyeReasonsFromInterface returns the most frequent goodbye reasons in generic peer data.
+func TopGoodbyeReasonsFromInterface(peers map[string]interface{}, limit int) []GoodbyeReasonCount {
+	return TopGoodbyeReasons(statsFromInterface(peers), limit)
+}
@@ -0,0 +1,93 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeClients(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	session := func(from, to int, score float64) ConnectionSession {
+		connectedAt := start.Add(time.Duration(from) * time.Second)
+		s := ConnectionSession{
+			ConnectedAt: &connectedAt,
+			PeerScores:  []PeerScoreSnapshot{{Timestamp: connectedAt, Score: score}},
+		}
+
+		if to >= 0 {
+			disconnectedAt := start.Add(time.Duration(to) * time.Second)
+			s.DisconnectedAt = &disconnectedAt
+			s.Disconnected = true
+		}
+
+		return s
+	}
+
+	peers := map[string]*Stats{
+		"a": {ClientType: "lighthouse", SuccessfulHandshakes: 2, ConnectionSessions: []ConnectionSession{session(0, 10, 1), session(20, 60, 5)}},
+		"b": {ClientType: "lighthouse", FailedHandshakes: 1, ConnectionSessions: []ConnectionSession{session(0, -1, 3)}},
+		"c": {ConnectionSessions: []ConnectionSession{{}}},
+	}
+
+	summaries := SummarizeClients(peers)
+
+	want := []ClientSummary{
+		{Client: "lighthouse", Peers: 2, Sessions: 3, Disconnects: 2, SuccessfulHandshakes: 2, FailedHandshakes: 1, HandshakeSuccessRate: 2.0 / 3, MedianDurationSeconds: 25, MedianScore: 4, ScoredPeers: 2},
+		{Client: "unknown", Peers: 1, Sessions: 1},
+	}
+
+	if len(summaries) != len(want) {
+		t.Fatalf("Expected %d clients, got %+v", len(want), summaries)
+	}
+
+	for i := range want {
+		if summaries[i] != want[i] {
+			t.Errorf("Client %d: got %+v, want %+v", i, summaries[i], want[i])
+		}
+	}
+}
+
+func TestTopGoodbyeReasons(t *testing.T) {
+	goodbyes := func(events ...GoodbyeEvent) *Stats {
+		return &Stats{ConnectionSessions: []ConnectionSession{{GoodbyeEvents: events}}}
+	}
+
+	peers := map[string]*Stats{
+		"a": goodbyes(GoodbyeEvent{Code: 129, Reason: "too many peers"}, GoodbyeEvent{Code: 1, Reason: "client shutdown"}),
+		"b": goodbyes(GoodbyeEvent{Code: 129, Reason: " too many peers "}),
+		"c": goodbyes(GoodbyeEvent{Code: 3, Reason: "fault/error"}),
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []GoodbyeReasonCount
+	}{
+		{
+			name:  "all",
+			limit: 10,
+			want:  []GoodbyeReasonCount{{Code: 129, Reason: "too many peers", Count: 2}, {Code: 1, Reason: "client shutdown", Count: 1}, {Code: 3, Reason: "fault/error", Count: 1}},
+		},
+		{
+			name:  "limited",
+			limit: 1,
+			want:  []GoodbyeReasonCount{{Code: 129, Reason: "too many peers", Count: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TopGoodbyeReasons(peers, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("reason %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
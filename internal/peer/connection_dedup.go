@@ -0,0 +1,100 @@
+package peer
+
+import (
+	"strings"
+	"time"
+)
+
+// How a duplicate CONNECTED event was matched to the connection already recorded.
+const (
+	DuplicateByConnectionID = "connection_id" // The payload's connection ID
+	DuplicateByOpened       = "opened"        // The time libp2p opened the connection and its remote address
+	DuplicateByWindow       = "window"        // No key, the peer's open session connected within the dedup window
+)
+
+// IsDuplicateConnection reports whether a CONNECTED event stamped at is another event for a
+// connection the peer already has a session for, counting it when it is. Events carrying a
+// key match the session with the same key. Without one, an event within window of the
+// connect of the peer's open session without a key is taken as a duplicate, so separate
+// connections to the same peer are only merged when libp2p tells nothing apart.
+func (s *Stats) IsDuplicateConnection(key string, at time.Time, window time.Duration) bool {
+	match := ""
+
+	if key != "" {
+		for i := len(s.ConnectionSessions) - 1; i >= 0; i-- {
+			if s.ConnectionSessions[i].ConnectionKey == key {
+				match = duplicateMatch(key)
+
+				break
+			}
+		}
+	} else if n := len(s.ConnectionSessions); n > 0 {
+		last := s.ConnectionSessions[n-1]
+
+		if !last.Disconnected && last.ConnectionKey == "" && last.ConnectedAt != nil {
+			if since := at.Sub(*last.ConnectedAt); since >= 0 && since <= window {
+				match = DuplicateByWindow
+			}
+		}
+	}
+
+	if match == "" {
+		return false
+	}
+
+	if s.DuplicateConnections == nil {
+		s.DuplicateConnections = make(map[string]int)
+	}
+
+	s.DuplicateConnections[match]++
+
+	return true
+}
+
+// ConnectionKey identifies the connection a CONNECTED event is about: its connection ID when
+// the payload carries one, else the time libp2p opened it with its remote address. It is
+// empty when neither is known.
+func ConnectionKey(connectionID, opened, remoteAddr string) string {
+	if connectionID != "" {
+		return DuplicateByConnectionID + ":" + connectionID
+	}
+
+	// Live payloads print the open time with its monotonic clock reading, which says nothing more
+	if i := strings.Index(opened, " m="); i >= 0 {
+		opened = opened[:i]
+	}
+
+	if opened == "" || strings.HasPrefix(opened, "0001-01-01") {
+		return ""
+	}
+
+	return DuplicateByOpened + ":" + opened + "|" + remoteAddr
+}
+
+// duplicateMatch returns how a key matched, from its prefix.
+func duplicateMatch(key string) string {
+	if strings.HasPrefix(key, DuplicateByConnectionID+":") {
+		return DuplicateByConnectionID
+	}
+
+	return DuplicateByOpened
+}
+
+// CountDuplicateConnections adds the duplicate CONNECTED events dropped per peer to the data
+// quality statistics.
+func CountDuplicateConnections(peers map[string]*Stats, stats *DataQualityStats) {
+	for _, peerStats := range peers {
+		if peerStats == nil {
+			continue
+		}
+
+		for match, count := range peerStats.DuplicateConnections {
+			if stats.DuplicateConnectionsByMatch == nil {
+				stats.DuplicateConnectionsByMatch = make(map[string]int)
+			}
+
+			stats.DuplicateConnections += count
+			stats.DuplicateConnectionsByMatch[match] += count
+		}
+	}
+}
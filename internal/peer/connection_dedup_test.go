@@ -0,0 +1,68 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsDuplicateConnection(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	window := 500 * time.Millisecond
+
+	at := func(ms int) *time.Time {
+		ts := start.Add(time.Duration(ms) * time.Millisecond)
+
+		return &ts
+	}
+
+	keyed := ConnectionKey("", "2025-06-01 12:00:00 +0000 UTC m=+1.5", "/ip4/1.2.3.4/tcp/9000")
+	if keyed != "opened:2025-06-01 12:00:00 +0000 UTC|/ip4/1.2.3.4/tcp/9000" {
+		t.Errorf("Expected the monotonic reading dropped from the key, got %q", keyed)
+	}
+
+	stats := &Stats{ConnectionSessions: []ConnectionSession{
+		{ConnectedAt: at(0), ConnectionKey: keyed, Disconnected: true, DisconnectedAt: at(2000)},
+		{ConnectedAt: at(5000)},
+	}}
+
+	tests := []struct {
+		name string
+		key  string
+		at   int
+		want bool
+	}{
+		{name: "same connection after its disconnect", key: keyed, at: 3000, want: true},
+		{name: "other connection", key: ConnectionKey("", "2025-06-01 12:00:05 +0000 UTC", "/ip4/1.2.3.4/tcp/9000"), at: 5100, want: false},
+		{name: "no key within the window", at: 5400, want: true},
+		{name: "no key beyond the window", at: 5600, want: false},
+		{name: "no key before the connect", at: 4900, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stats.IsDuplicateConnection(tt.key, *at(tt.at), window); got != tt.want {
+				t.Errorf("IsDuplicateConnection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if stats.DuplicateConnections[DuplicateByOpened] != 1 || stats.DuplicateConnections[DuplicateByWindow] != 1 {
+		t.Errorf("Expected one duplicate per match, got %v", stats.DuplicateConnections)
+	}
+
+	byID := &Stats{ConnectionSessions: []ConnectionSession{{ConnectedAt: at(0), ConnectionKey: ConnectionKey("42", "", "")}}}
+	if !byID.IsDuplicateConnection(ConnectionKey("42", "ignored", ""), *at(60000), window) {
+		t.Error("Expected a connection ID to match however late the event comes")
+	}
+
+	if ConnectionKey("", "0001-01-01T00:00:00Z", "/ip4/1.2.3.4/tcp/9000") != "" {
+		t.Error("Expected no key for a zero open time")
+	}
+
+	quality := DataQualityStats{}
+	CountDuplicateConnections(map[string]*Stats{"a": stats, "b": byID, "c": {}}, &quality)
+
+	if quality.DuplicateConnections != 3 || quality.DuplicateConnectionsByMatch[DuplicateByConnectionID] != 1 {
+		t.Errorf("Expected 3 duplicates, 1 by connection ID, got %+v", quality)
+	}
+}
@@ -0,0 +1,135 @@
+package peer
+
+import (
+	"sort"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Consensus verdicts, comparing whether we and the other vantage points scored a peer below zero.
+const (
+	ConsensusHostileToUs  = "hostile_to_us" // Only we scored the peer below zero, it treats us worse than others
+	ConsensusGenerallyBad = "generally_bad" // We and the other vantage points scored the peer below zero
+	ConsensusBadElsewhere = "bad_elsewhere" // Only the other vantage points scored the peer below zero
+	ConsensusAgreed       = "agreed"        // Nobody scored the peer below zero
+)
+
+// VantageScore is a peer's composite score as another monitoring node scored it.
+type VantageScore struct {
+	Instance string  `json:"instance"`
+	Score    float64 `json:"score"`
+}
+
+// ConsensusScore compares our composite score of a peer with the other vantage points' scores.
+// A composite score is the peer's time-weighted mean score across its sessions.
+type ConsensusScore struct {
+	Vantages    int     `json:"vantages"`     // Other vantage points that scored the peer
+	OtherMedian float64 `json:"other_median"` // Median of their scores
+	OtherMin    float64 `json:"other_min"`
+	OtherMax    float64 `json:"other_max"`
+	Verdict     string  `json:"verdict"` // One of the Consensus verdicts
+}
+
+// ConsensusPeer is a peer in the consensus view, with our composite score.
+type ConsensusPeer struct {
+	PeerID     string  `json:"peer_id"`
+	ClientType string  `json:"client_type"`
+	OurScore   float64 `json:"our_score"`
+	ConsensusScore
+}
+
+// ConsensusView shows how other monitoring nodes sharing a score feed scored the peers we
+// scored, to tell peers hostile toward us from peers that are bad for everyone.
+type ConsensusView struct {
+	Instances   []string        `json:"instances"`    // Other vantage points that shared scores
+	SharedPeers int             `json:"shared_peers"` // Peers we and at least one other vantage point scored
+	Verdicts    map[string]int  `json:"verdicts"`     // Shared peers per verdict
+	Peers       []ConsensusPeer `json:"peers"`        // Peers scored below zero anywhere, our lowest score first
+	Error       string          `json:"error,omitempty"`
+}
+
+// ApplyConsensus sets each scored peer's consensus score from the other vantage points' scores
+// by peer ID, and returns the consensus view listing up to limit peers scored below zero
+// anywhere. Peers no other vantage point scored are left without a consensus score.
+func ApplyConsensus(peers map[string]*Stats, others map[string][]VantageScore, instances []string, limit int) *ConsensusView {
+	view := &ConsensusView{
+		Instances: instances,
+		Verdicts:  make(map[string]int),
+		Peers:     make([]ConsensusPeer, 0),
+	}
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		scores := others[peerID]
+		ours, _, _, ok := stats.ScoreTotals()
+
+		if len(scores) == 0 || !ok {
+			continue
+		}
+
+		consensus := consensusScore(ours, scores)
+		stats.Consensus = consensus
+
+		view.SharedPeers++
+		view.Verdicts[consensus.Verdict]++
+
+		if consensus.Verdict != ConsensusAgreed {
+			view.Peers = append(view.Peers, ConsensusPeer{
+				PeerID:         peerID,
+				ClientType:     stats.ClientType,
+				OurScore:       ours,
+				ConsensusScore: *consensus,
+			})
+		}
+	}
+
+	sort.Slice(view.Peers, func(i, j int) bool {
+		if view.Peers[i].OurScore != view.Peers[j].OurScore {
+			return view.Peers[i].OurScore < view.Peers[j].OurScore
+		}
+
+		return view.Peers[i].PeerID < view.Peers[j].PeerID
+	})
+
+	if limit > 0 && len(view.Peers) > limit {
+		view.Peers = view.Peers[:limit]
+	}
+
+	return view
+}
+
+// consensusScore compares our score of a peer with the other vantage points' scores of it.
+func consensusScore(ours float64, scores []VantageScore) *ConsensusScore {
+	values := make([]float64, 0, len(scores))
+	for _, score := range scores {
+		values = append(values, score.Score)
+	}
+
+	sort.Float64s(values)
+
+	consensus := &ConsensusScore{
+		Vantages:    len(values),
+		OtherMedian: median(values),
+		OtherMin:    values[0],
+		OtherMax:    values[len(values)-1],
+	}
+
+	weBad := ours < constants.ConsensusBadScore
+	othersBad := consensus.OtherMedian < constants.ConsensusBadScore
+
+	switch {
+	case weBad && othersBad:
+		consensus.Verdict = ConsensusGenerallyBad
+	case weBad:
+		consensus.Verdict = ConsensusHostileToUs
+	case othersBad:
+		consensus.Verdict = ConsensusBadElsewhere
+	default:
+		consensus.Verdict = ConsensusAgreed
+	}
+
+	return consensus
+}
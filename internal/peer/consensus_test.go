@@ -0,0 +1,58 @@
+package peer
+
+import "testing"
+
+// scoredPeer returns a peer whose one session scored mean over a minute.
+func scoredPeer(clientType string, mean float64) *Stats {
+	return &Stats{
+		ClientType: clientType,
+		ConnectionSessions: []ConnectionSession{
+			{ScoreSummary: &SessionScoreSummary{Snapshots: 6, ScoredSeconds: 60, TimeWeightedMean: mean}},
+		},
+	}
+}
+
+func TestApplyConsensus(t *testing.T) {
+	peers := map[string]*Stats{
+		"16Uiu2HAmHostile":   scoredPeer("lighthouse", -20),
+		"16Uiu2HAmBad":       scoredPeer("prysm", -5),
+		"16Uiu2HAmElsewhere": scoredPeer("teku", 3),
+		"16Uiu2HAmFine":      scoredPeer("nimbus", 4),
+		"16Uiu2HAmUnshared":  scoredPeer("lodestar", -50),
+		"16Uiu2HAmUnscored":  {ClientType: "grandine"},
+	}
+
+	others := map[string][]VantageScore{
+		"16Uiu2HAmHostile":   {{Instance: "eu", Score: 2}, {Instance: "us", Score: 6}, {Instance: "ap", Score: -1}},
+		"16Uiu2HAmBad":       {{Instance: "eu", Score: -8}},
+		"16Uiu2HAmElsewhere": {{Instance: "eu", Score: -2}, {Instance: "us", Score: -4}},
+		"16Uiu2HAmFine":      {{Instance: "us", Score: 5}},
+		"16Uiu2HAmUnscored":  {{Instance: "us", Score: 5}},
+	}
+
+	view := ApplyConsensus(peers, others, []string{"ap", "eu", "us"}, 2)
+
+	if view.SharedPeers != 4 {
+		t.Errorf("Expected 4 shared peers, got %d", view.SharedPeers)
+	}
+
+	for verdict, want := range map[string]int{ConsensusHostileToUs: 1, ConsensusGenerallyBad: 1, ConsensusBadElsewhere: 1, ConsensusAgreed: 1} {
+		if got := view.Verdicts[verdict]; got != want {
+			t.Errorf("Expected %d %s peers, got %d", want, verdict, got)
+		}
+	}
+
+	// Peers scored below zero anywhere, our lowest score first, cut at the limit
+	if len(view.Peers) != 2 || view.Peers[0].PeerID != "16Uiu2HAmHostile" || view.Peers[1].PeerID != "16Uiu2HAmBad" {
+		t.Fatalf("Unexpected consensus peers %+v", view.Peers)
+	}
+
+	hostile := peers["16Uiu2HAmHostile"].Consensus
+	if hostile == nil || hostile.Vantages != 3 || hostile.OtherMedian != 2 || hostile.OtherMin != -1 || hostile.OtherMax != 6 {
+		t.Errorf("Unexpected consensus score %+v", hostile)
+	}
+
+	if peers["16Uiu2HAmUnshared"].Consensus != nil || peers["16Uiu2HAmUnscored"].Consensus != nil {
+		t.Error("Expected peers without scores on both sides left without a consensus score")
+	}
+}
@@ -0,0 +1,159 @@
+package peer
+
+import (
+	"sort"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// ControlPlaneStats counts the gossipsub control messages and full messages exchanged with a
+// peer. Control messages are counted by the message IDs they carry, as one RPC may batch many.
+type ControlPlaneStats struct {
+	IHaveSent         int `json:"ihave_sent"`         // IDs we announced to the peer
+	IHaveReceived     int `json:"ihave_received"`     // IDs the peer announced to us
+	IWantSent         int `json:"iwant_sent"`         // IDs we requested from the peer
+	IWantReceived     int `json:"iwant_received"`     // IDs the peer requested from us
+	IDontWantSent     int `json:"idontwant_sent"`     // IDs we told the peer not to send
+	IDontWantReceived int `json:"idontwant_received"` // IDs the peer told us not to send
+	MessagesSent      int `json:"messages_sent"`      // Full messages we sent the peer
+	MessagesReceived  int `json:"messages_received"`  // Full messages the peer sent us
+	FirstDeliveries   int `json:"first_deliveries"`   // Messages the peer was first to deliver to us
+
+	IWantRatio        float64 `json:"iwant_ratio"`         // IDs the peer requested per ID we announced to it
+	GossipReliance    float64 `json:"gossip_reliance"`     // IDs the peer requested per full message we sent it
+	FirstDeliveryRate float64 `json:"first_delivery_rate"` // First deliveries per full message the peer sent us
+	Leech             bool    `json:"leech"`               // Requests messages through gossip but never delivers any
+}
+
+// RecordControlRPC adds an RPC exchanged with the peer to its control plane counts.
+func (s *Stats) RecordControlRPC(sent bool, ihave, iwant, idontwant, messages int) {
+	if ihave == 0 && iwant == 0 && idontwant == 0 && messages == 0 {
+		return
+	}
+
+	control := s.controlPlane()
+
+	if sent {
+		control.IHaveSent += ihave
+		control.IWantSent += iwant
+		control.IDontWantSent += idontwant
+		control.MessagesSent += messages
+	} else {
+		control.IHaveReceived += ihave
+		control.IWantReceived += iwant
+		control.IDontWantReceived += idontwant
+		control.MessagesReceived += messages
+	}
+
+	control.rates()
+}
+
+// RecordFirstDelivery counts a message the peer was first to deliver to us.
+func (s *Stats) RecordFirstDelivery() {
+	control := s.controlPlane()
+	control.FirstDeliveries++
+	control.rates()
+}
+
+// controlPlane returns the peer's control plane counts, adding them on first use.
+func (s *Stats) controlPlane() *ControlPlaneStats {
+	if s.ControlPlane == nil {
+		s.ControlPlane = &ControlPlaneStats{}
+	}
+
+	return s.ControlPlane
+}
+
+// rates derives the ratios and the leech flag from the counts. Peers requesting fewer IDs than
+// the leech threshold are not flagged, a short session says little about how a peer behaves.
+func (c *ControlPlaneStats) rates() {
+	c.IWantRatio = 0
+	if c.IHaveSent > 0 {
+		c.IWantRatio = float64(c.IWantReceived) / float64(c.IHaveSent)
+	}
+
+	c.GossipReliance = 0
+	if c.MessagesSent > 0 {
+		c.GossipReliance = min(float64(c.IWantReceived)/float64(c.MessagesSent), 1)
+	}
+
+	c.FirstDeliveryRate = 0
+	if c.MessagesReceived > 0 {
+		c.FirstDeliveryRate = float64(c.FirstDeliveries) / float64(c.MessagesReceived)
+	}
+
+	c.Leech = c.IWantReceived >= constants.GossipLeechMinRequests && c.MessagesReceived == 0
+}
+
+// GossipLeech summarises a peer that requested messages from us through gossip without ever
+// delivering one.
+type GossipLeech struct {
+	PeerID        string `json:"peer_id"`
+	ClientType    string `json:"client_type"`
+	IWantReceived int    `json:"iwant_received"`
+	IHaveSent     int    `json:"ihave_sent"`
+	MessagesSent  int    `json:"messages_sent"`
+}
+
+// GossipLeeches returns up to limit leeching peers, those requesting the most IDs first.
+func GossipLeeches(peers map[string]*Stats, limit int) []GossipLeech {
+	leeches := make([]GossipLeech, 0)
+
+	for peerID, stats := range peers {
+		if stats == nil || stats.ControlPlane == nil || !stats.ControlPlane.Leech {
+			continue
+		}
+
+		leeches = append(leeches, GossipLeech{
+			PeerID:        peerID,
+			ClientType:    stats.ClientType,
+			IWantReceived: stats.ControlPlane.IWantReceived,
+			IHaveSent:     stats.ControlPlane.IHaveSent,
+			MessagesSent:  stats.ControlPlane.MessagesSent,
+		})
+	}
+
+	sort.Slice(leeches, func(i, j int) bool {
+		if leeches[i].IWantReceived != leeches[j].IWantReceived {
+			return leeches[i].IWantReceived > leeches[j].IWantReceived
+		}
+
+		return leeches[i].PeerID < leeches[j].PeerID
+	})
+
+	if limit > 0 && len(leeches) > limit {
+		leeches = leeches[:limit]
+	}
+
+	return leeches
+}
+
+// GossipLeechesByClient counts the leeching peers per client type.
+func GossipLeechesByClient(peers map[string]*Stats) map[string]int {
+	byClient := make(map[string]int)
+
+	for _, stats := range peers {
+		if stats == nil || stats.ControlPlane == nil || !stats.ControlPlane.Leech {
+			continue
+		}
+
+		client := stats.ClientType
+		if client == "" {
+			client = "unknown"
+		}
+
+		byClient[client]++
+	}
+
+	return byClient
+}
+
+// GossipLeechesFromInterface returns the leeching peers in generic peer data.
+func GossipLeechesFromInterface(peers map[string]interface{}, limit int) []GossipLeech {
+	return GossipLeeches(statsFromInterface(peers), limit)
+}
+
+// GossipLeechesByClientFromInterface counts the leeching peers per client type in generic peer data.
+func GossipLeechesByClientFromInterface(peers map[string]interface{}) map[string]int {
+	return GossipLeechesByClient(statsFromInterface(peers))
+}
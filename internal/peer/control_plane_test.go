@@ -0,0 +1,45 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+func TestGossipLeeches(t *testing.T) {
+	leech := &Stats{ClientType: "teku"}
+	leech.RecordControlRPC(true, 40, 0, 0, 5)
+	leech.RecordControlRPC(false, 0, constants.GossipLeechMinRequests, 0, 0)
+
+	// Requests as much but delivers a message
+	delivering := &Stats{ClientType: "lighthouse"}
+	delivering.RecordControlRPC(false, 0, constants.GossipLeechMinRequests, 0, 1)
+
+	// Requests too little to judge
+	quiet := &Stats{ClientType: "prysm"}
+	quiet.RecordControlRPC(false, 0, constants.GossipLeechMinRequests-1, 0, 0)
+
+	peers := map[string]*Stats{"leech": leech, "delivering": delivering, "quiet": quiet, "none": {}}
+
+	if !leech.ControlPlane.Leech || delivering.ControlPlane.Leech || quiet.ControlPlane.Leech {
+		t.Fatalf("Unexpected leech flags %+v %+v %+v", leech.ControlPlane, delivering.ControlPlane, quiet.ControlPlane)
+	}
+
+	if ratio := leech.ControlPlane.IWantRatio; ratio != float64(constants.GossipLeechMinRequests)/40 {
+		t.Errorf("Unexpected IWANT ratio %v", ratio)
+	}
+
+	// More IDs requested than messages sent caps the reliance
+	if reliance := leech.ControlPlane.GossipReliance; reliance != 1 {
+		t.Errorf("Expected the gossip reliance capped at 1, got %v", reliance)
+	}
+
+	leeches := GossipLeeches(peers, 0)
+	if len(leeches) != 1 || leeches[0].PeerID != "leech" || leeches[0].IWantReceived != constants.GossipLeechMinRequests {
+		t.Errorf("Expected only the leech, got %+v", leeches)
+	}
+
+	if byClient := GossipLeechesByClient(peers); len(byClient) != 1 || byClient["teku"] != 1 {
+		t.Errorf("Unexpected leeches by client %v", byClient)
+	}
+}
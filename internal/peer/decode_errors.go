@@ -0,0 +1,92 @@
+package peer
+
+import (
+	"sort"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// Decode error kinds derived from gossipsub rejection reasons.
+const (
+	DecodeErrorSignature  = "signature"
+	DecodeErrorValidation = "validation_failed"
+)
+
+// ClassifyRejectReason maps a gossipsub rejection reason to a decode error kind. Hermes
+// traces the reasons of go-libp2p-pubsub unchanged, which never say why a payload was bad:
+// a message that fails to decompress or to decode as SSZ fails Hermes's validator, and is
+// rejected as "validation failed" like any other invalid message. Rejections that say
+// nothing about the quality of the sender's payload (blacklists, throttling, full queues,
+// ignored or self-originated messages) are not counted.
+//
+// Gossipsub already counts every counted rejection against the sender as an invalid message
+// delivery, so decode errors are reported beside the scores rather than folded into them.
+func ClassifyRejectReason(reason string) (string, bool) {
+	switch reason {
+	case pubsub.RejectMissingSignature, pubsub.RejectInvalidSignature, pubsub.RejectUnexpectedSignature, pubsub.RejectUnexpectedAuthInfo:
+		return DecodeErrorSignature, true
+	case pubsub.RejectValidationFailed:
+		return DecodeErrorValidation, true
+	default:
+		return "", false
+	}
+}
+
+// RecordDecodeError adds a classified decode error to the peer's statistics.
+func (s *Stats) RecordDecodeError(kind, topic, reason string, at time.Time) {
+	if s.DecodeErrors == nil {
+		s.DecodeErrors = &DecodeErrorStats{
+			ByKind:  make(map[string]int),
+			ByTopic: make(map[string]int),
+		}
+	}
+
+	s.DecodeErrors.Total++
+	s.DecodeErrors.ByKind[kind]++
+	s.DecodeErrors.ByTopic[topic]++
+	s.DecodeErrors.LastReason = reason
+	s.DecodeErrors.LastSeenAt = &at
+}
+
+// DecodeErrorOffender summarises the decode errors attributed to a single peer.
+type DecodeErrorOffender struct {
+	PeerID     string         `json:"peer_id"`
+	ClientType string         `json:"client_type"`
+	Total      int            `json:"total"`
+	ByKind     map[string]int `json:"by_kind"`
+	ByTopic    map[string]int `json:"by_topic"`
+}
+
+// TopDecodeErrorOffenders returns up to limit peers with the most decode errors, worst first.
+func TopDecodeErrorOffenders(peers map[string]*Stats, limit int) []DecodeErrorOffender {
+	offenders := make([]DecodeErrorOffender, 0)
+
+	for peerID, stats := range peers {
+		if stats.DecodeErrors == nil || stats.DecodeErrors.Total == 0 {
+			continue
+		}
+
+		offenders = append(offenders, DecodeErrorOffender{
+			PeerID:     peerID,
+			ClientType: stats.ClientType,
+			Total:      stats.DecodeErrors.Total,
+			ByKind:     stats.DecodeErrors.ByKind,
+			ByTopic:    stats.DecodeErrors.ByTopic,
+		})
+	}
+
+	sort.Slice(offenders, func(i, j int) bool {
+		if offenders[i].Total != offenders[j].Total {
+			return offenders[i].Total > offenders[j].Total
+		}
+
+		return offenders[i].PeerID < offenders[j].PeerID
+	})
+
+	if limit > 0 && len(offenders) > limit {
+		offenders = offenders[:limit]
+	}
+
+	return offenders
+}
@@ -0,0 +1,129 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyRejectReason(t *testing.T) {
+	tests := []struct {
+		name         string
+		reason       string
+		expectedKind string
+		expectedOK   bool
+	}{
+		// The reasons go-libp2p-pubsub traces, pinned so an upstream change shows
+		{
+			name:         "invalid signature",
+			reason:       "invalid signature",
+			expectedKind: DecodeErrorSignature,
+			expectedOK:   true,
+		},
+		{
+			name:         "missing signature",
+			reason:       "missing signature",
+			expectedKind: DecodeErrorSignature,
+			expectedOK:   true,
+		},
+		{
+			name:         "unexpected signature",
+			reason:       "unexpected signature",
+			expectedKind: DecodeErrorSignature,
+			expectedOK:   true,
+		},
+		{
+			name:         "unexpected auth info",
+			reason:       "unexpected auth info",
+			expectedKind: DecodeErrorSignature,
+			expectedOK:   true,
+		},
+		{
+			name:         "validator rejection",
+			reason:       "validation failed",
+			expectedKind: DecodeErrorValidation,
+			expectedOK:   true,
+		},
+		{
+			name:       "validation throttled",
+			reason:     "validation throttled",
+			expectedOK: false,
+		},
+		{
+			name:       "validation ignored",
+			reason:     "validation ignored",
+			expectedOK: false,
+		},
+		{
+			name:       "validation queue full",
+			reason:     "validation queue full",
+			expectedOK: false,
+		},
+		{
+			name:       "blacklisted peer",
+			reason:     "blacklisted peer",
+			expectedOK: false,
+		},
+		{
+			name:       "blacklisted source",
+			reason:     "blacklisted source",
+			expectedOK: false,
+		},
+		{
+			name:       "self originated message",
+			reason:     "self originated message",
+			expectedOK: false,
+		},
+		{
+			name:       "empty reason",
+			reason:     "",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, ok := ClassifyRejectReason(tt.reason)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok %v, got %v", tt.expectedOK, ok)
+			}
+
+			if kind != tt.expectedKind {
+				t.Errorf("expected kind %q, got %q", tt.expectedKind, kind)
+			}
+		})
+	}
+}
+
+func TestTopDecodeErrorOffenders(t *testing.T) {
+	now := time.Now()
+
+	peers := map[string]*Stats{
+		"peer-a": {ClientType: "lighthouse"},
+		"peer-b": {ClientType: "prysm"},
+		"peer-c": {ClientType: "teku"},
+		"peer-d": {ClientType: "nimbus"},
+	}
+
+	peers["peer-a"].RecordDecodeError(DecodeErrorSignature, "/eth2/beacon_block", "invalid signature", now)
+	peers["peer-b"].RecordDecodeError(DecodeErrorValidation, "/eth2/beacon_block", "validation failed", now)
+	peers["peer-b"].RecordDecodeError(DecodeErrorValidation, "/eth2/beacon_attestation_1", "validation failed", now)
+	peers["peer-c"].RecordDecodeError(DecodeErrorValidation, "/eth2/beacon_block", "validation failed", now)
+
+	offenders := TopDecodeErrorOffenders(peers, 2)
+	if len(offenders) != 2 {
+		t.Fatalf("expected 2 offenders, got %d", len(offenders))
+	}
+
+	if offenders[0].PeerID != "peer-b" || offenders[0].Total != 2 {
+		t.Errorf("expected peer-b with 2 errors first, got %s with %d", offenders[0].PeerID, offenders[0].Total)
+	}
+
+	// Ties are broken by peer ID
+	if offenders[1].PeerID != "peer-a" {
+		t.Errorf("expected peer-a second, got %s", offenders[1].PeerID)
+	}
+
+	if offenders[0].ByKind[DecodeErrorValidation] != 2 || len(offenders[0].ByTopic) != 2 {
+		t.Errorf("unexpected breakdown for peer-b: %+v", offenders[0])
+	}
+}
@@ -0,0 +1,72 @@
+package peer
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// EpochSlice holds the headline statistics of one beacon epoch of the run, so incidents
+// reported elsewhere by epoch number can be looked up without converting timestamps. The
+// first and last epochs are cut to the run.
+type EpochSlice struct {
+	Epoch uint64 `json:"epoch"`
+	TimeSlice
+	Prunes int `json:"prunes"` // Mesh PRUNEs in the epoch, sent and received, weighted by sampling and rounded
+}
+
+// CalculateEpochSlices computes headline statistics per beacon epoch from start to end, as
+// CalculateTimeSlices does per slice of time. Nil without a slot clock.
+func CalculateEpochSlices(peers map[string]*Stats, clock *SlotClock, start, end time.Time) []EpochSlice {
+	if clock == nil || clock.slotsPerEpoch == 0 || clock.secondsPerSlot <= 0 || !end.After(start) {
+		return nil
+	}
+
+	first := clock.EpochAt(start)
+	last := clock.EpochAt(end.Add(-time.Nanosecond))
+
+	bounds := []time.Time{start}
+	for epoch := first + 1; epoch <= last; epoch++ {
+		bounds = append(bounds, clock.EpochStart(epoch))
+	}
+
+	bounds = append(bounds, end)
+
+	windows := calculateWindows(peers, bounds)
+	epochs := make([]EpochSlice, len(windows))
+
+	for i, window := range windows {
+		epochs[i] = EpochSlice{Epoch: first + uint64(i), TimeSlice: window} //nolint:gosec // ok.
+	}
+
+	// PRUNEs are only recorded for peers whose detail is captured and, when sampled, one in N, so
+	// each stands for its peer's sampling weight times the events it was sampled from
+	prunes := make([]float64, len(windows))
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		weight := stats.DetailWeight()
+
+		for _, session := range stats.ConnectionSessions {
+			factor := session.EventFactor(MeshPrune)
+
+			for _, event := range session.MeshEvents {
+				if event.Type != MeshPrune || event.Timestamp.Before(start) || !event.Timestamp.Before(end) {
+					continue
+				}
+
+				i := sort.Search(len(windows), func(i int) bool { return event.Timestamp.Before(bounds[i+1]) })
+				prunes[i] += weight * factor
+			}
+		}
+	}
+
+	for i := range epochs {
+		epochs[i].Prunes = int(math.Round(prunes[i]))
+	}
+
+	return epochs
+}
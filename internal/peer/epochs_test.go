@@ -0,0 +1,76 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateEpochSlices(t *testing.T) {
+	// 12 second slots and 32 slot epochs, epoch 100 starts at genesis+38400s
+	genesis := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewSlotClock(genesis, 12*time.Second, 32)
+
+	start := clock.EpochStart(100).Add(2 * time.Minute)
+	end := clock.EpochStart(102).Add(time.Minute)
+
+	at := func(offset time.Duration) *time.Time {
+		ts := clock.EpochStart(100).Add(offset)
+
+		return &ts
+	}
+
+	peers := map[string]*Stats{
+		"steady": {ConnectionSessions: []ConnectionSession{{
+			ConnectedAt:  at(3 * time.Minute),
+			IdentifiedAt: at(3 * time.Minute),
+			PeerScores:   []PeerScoreSnapshot{{Timestamp: *at(4 * time.Minute), Score: 8}, {Timestamp: *at(7 * time.Minute), Score: -2}},
+			MeshEvents: []MeshEvent{
+				{Timestamp: *at(5 * time.Minute), Type: MeshGraft},
+				{Timestamp: *at(7 * time.Minute), Type: MeshPrune},
+				{Timestamp: *at(15 * time.Minute), Type: MeshPrune},
+			},
+		}}},
+		"churner": {ConnectionSessions: []ConnectionSession{{
+			ConnectedAt: at(5 * time.Minute), DisconnectedAt: at(8 * time.Minute), Disconnected: true,
+			GoodbyeEvents: []GoodbyeEvent{{Timestamp: *at(8 * time.Minute), Code: 3, Reason: "client error"}},
+		}}},
+	}
+
+	epochs := CalculateEpochSlices(peers, clock, start, end)
+	if len(epochs) != 3 {
+		t.Fatalf("got %d epochs, want 3", len(epochs))
+	}
+
+	if epochs[0].Epoch != 100 || !epochs[0].Start.Equal(start) || !epochs[1].Start.Equal(clock.EpochStart(101)) || !epochs[2].End.Equal(end) {
+		t.Errorf("epochs = %+v, want 100 to 102 cut to the run", epochs)
+	}
+
+	first := epochs[0]
+	if first.Connections != 2 || first.SuccessfulHandshakes != 1 || first.FailedHandshakes != 1 || first.Goodbyes != 0 {
+		t.Errorf("epoch 100 = %+v, want 2 connections, one handshake failed", first)
+	}
+
+	second := epochs[1]
+	if second.Disconnects != 1 || second.Goodbyes != 1 || second.Prunes != 1 || second.ScoredPeers != 1 || second.MeanScore != -2 {
+		t.Errorf("epoch 101 = %+v, want the goodbye, a prune and the score of -2", second)
+	}
+
+	if first.Prunes != 0 || epochs[2].Prunes != 0 {
+		t.Errorf("prunes = %d and %d, want the prune after the run left out", first.Prunes, epochs[2].Prunes)
+	}
+
+	if CalculateEpochSlices(peers, nil, start, end) != nil {
+		t.Error("want no epochs without a slot clock")
+	}
+
+	// A prune recorded one in 10 stands for the ten seen
+	sampled := map[string]*Stats{"sampled": {ConnectionSessions: []ConnectionSession{{
+		ConnectedAt:  at(3 * time.Minute),
+		MeshEvents:   []MeshEvent{{Timestamp: *at(7 * time.Minute), Type: MeshPrune}},
+		EventSamples: EventSampleCounts{MeshPrune: {Seen: 10, Recorded: 1}},
+	}}}}
+
+	if got := CalculateEpochSlices(sampled, clock, start, end)[1].Prunes; got != 10 {
+		t.Errorf("sampled prunes = %d, want 10", got)
+	}
+}
@@ -0,0 +1,138 @@
+package peer
+
+import "sort"
+
+// EventSampleCount counts one event type of a session whose events are sampled: every event
+// seen, and the ones recorded in full.
+type EventSampleCount struct {
+	Seen     int `json:"seen"`
+	Recorded int `json:"recorded"`
+}
+
+// EventSampleCounts holds a session's counts by sampled event type.
+type EventSampleCounts map[string]EventSampleCount
+
+// SampleEvent counts an event of the type in the session and reports whether it is recorded
+// in full: the session's first and every rate-th after it, so each session keeps its first
+// graft and score snapshot. A rate of 1 or less records every event without counting.
+func (s *ConnectionSession) SampleEvent(eventType string, rate int) bool {
+	if rate <= 1 {
+		return true
+	}
+
+	if s.EventSamples == nil {
+		s.EventSamples = make(EventSampleCounts)
+	}
+
+	count := s.EventSamples[eventType]
+	record := count.Seen%rate == 0
+
+	count.Seen++
+	if record {
+		count.Recorded++
+	}
+
+	s.EventSamples[eventType] = count
+
+	return record
+}
+
+// EventCount returns how many events of the type the session saw, recorded being how many of
+// them it holds in full. The two differ only when the type is sampled.
+func (s *ConnectionSession) EventCount(eventType string, recorded int) int {
+	if count, ok := s.EventSamples[eventType]; ok {
+		return count.Seen
+	}
+
+	return recorded
+}
+
+// EventFactor returns how many events of the type each recorded one stands for, 1 unless the
+// type is sampled.
+func (s *ConnectionSession) EventFactor(eventType string) float64 {
+	count, ok := s.EventSamples[eventType]
+	if !ok || count.Recorded == 0 {
+		return 1
+	}
+
+	return float64(count.Seen) / float64(count.Recorded)
+}
+
+// MeshCounts returns the GRAFTs and PRUNEs the session saw, its recorded mesh events scaled up
+// to the events seen when they were sampled. Counts of mesh events must come from here rather
+// than from MeshEvents, which only holds the recorded ones.
+func (s *ConnectionSession) MeshCounts() (grafts, prunes int) {
+	for _, event := range s.MeshEvents {
+		switch event.Type {
+		case MeshGraft:
+			grafts++
+		case MeshPrune:
+			prunes++
+		}
+	}
+
+	return s.EventCount(MeshGraft, grafts), s.EventCount(MeshPrune, prunes)
+}
+
+// EventTypeSampling is how one event type was sampled over the run.
+type EventTypeSampling struct {
+	EventType string  `json:"event_type"`
+	Rate      int     `json:"rate"` // One in Rate events of a session recorded in full
+	Seen      int     `json:"seen"`
+	Recorded  int     `json:"recorded"`
+	Factor    float64 `json:"factor"` // Seen per recorded, what counts over the recorded events scale by
+}
+
+// EventSampling lists the event types recorded one in N, so counts over their recorded events
+// can be scaled to the events seen.
+type EventSampling struct {
+	Types []EventTypeSampling `json:"types"` // By event type
+}
+
+// SummarizeEventSampling totals the events seen and recorded for each sampled type, nil when
+// no type is sampled.
+func SummarizeEventSampling(peers map[string]*Stats, rates map[string]int) *EventSampling {
+	totals := make(map[string]*EventTypeSampling)
+
+	for eventType, rate := range rates {
+		if rate > 1 {
+			totals[eventType] = &EventTypeSampling{EventType: eventType, Rate: rate}
+		}
+	}
+
+	if len(totals) == 0 {
+		return nil
+	}
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for _, session := range stats.ConnectionSessions {
+			for eventType, count := range session.EventSamples {
+				if total, ok := totals[eventType]; ok {
+					total.Seen += count.Seen
+					total.Recorded += count.Recorded
+				}
+			}
+		}
+	}
+
+	sampling := &EventSampling{Types: make([]EventTypeSampling, 0, len(totals))}
+
+	for _, total := range totals {
+		total.Factor = float64(total.Rate)
+		if total.Recorded > 0 {
+			total.Factor = float64(total.Seen) / float64(total.Recorded)
+		}
+
+		sampling.Types = append(sampling.Types, *total)
+	}
+
+	sort.Slice(sampling.Types, func(i, j int) bool {
+		return sampling.Types[i].EventType < sampling.Types[j].EventType
+	})
+
+	return sampling
+}
@@ -0,0 +1,87 @@
+package peer
+
+import "testing"
+
+func TestSampleEvent(t *testing.T) {
+	session := &ConnectionSession{}
+
+	recorded := 0
+	for range 25 {
+		if session.SampleEvent(MeshPrune, 10) {
+			recorded++
+		}
+	}
+
+	if recorded != 3 {
+		t.Errorf("Expected 3 of 25 events recorded at 1 in 10, got %d", recorded)
+	}
+
+	if got := session.EventSamples[MeshPrune]; got != (EventSampleCount{Seen: 25, Recorded: 3}) {
+		t.Errorf("Expected 25 seen and 3 recorded, got %+v", got)
+	}
+
+	if got := session.EventCount(MeshPrune, recorded); got != 25 {
+		t.Errorf("Expected the exact count 25, got %d", got)
+	}
+
+	if !session.SampleEvent(MeshGraft, 1) || session.EventSamples[MeshGraft] != (EventSampleCount{}) {
+		t.Error("Expected an unsampled type recorded without counting")
+	}
+
+	if got := session.EventCount(MeshGraft, 4); got != 4 {
+		t.Errorf("Expected the recorded count for an unsampled type, got %d", got)
+	}
+}
+
+func TestMeshCounts(t *testing.T) {
+	session := &ConnectionSession{
+		MeshEvents: []MeshEvent{
+			{Type: MeshGraft}, {Type: MeshGraft}, {Type: MeshPrune}, {Type: MeshPrune},
+		},
+		EventSamples: EventSampleCounts{MeshPrune: {Seen: 15, Recorded: 2}},
+	}
+
+	if grafts, prunes := session.MeshCounts(); grafts != 2 || prunes != 15 {
+		t.Errorf("Expected 2 grafts and the 15 prunes seen, got %d and %d", grafts, prunes)
+	}
+
+	if got := session.EventFactor(MeshPrune); got != 7.5 {
+		t.Errorf("Expected a prune factor of 7.5, got %v", got)
+	}
+
+	if got := session.EventFactor(MeshGraft); got != 1 {
+		t.Errorf("Expected a graft factor of 1, got %v", got)
+	}
+}
+
+func TestSummarizeEventSampling(t *testing.T) {
+	if SummarizeEventSampling(map[string]*Stats{}, map[string]int{MeshPrune: 1}) != nil {
+		t.Fatal("Expected nil when no type is sampled")
+	}
+
+	peers := map[string]*Stats{
+		"a": {ConnectionSessions: []ConnectionSession{
+			{EventSamples: EventSampleCounts{MeshPrune: {Seen: 25, Recorded: 3}, "PEERSCORE": {Seen: 12, Recorded: 3}}},
+			{EventSamples: EventSampleCounts{MeshPrune: {Seen: 5, Recorded: 1}}},
+		}},
+		"b":       {ConnectionSessions: []ConnectionSession{{}}},
+		"missing": nil,
+	}
+
+	sampling := SummarizeEventSampling(peers, map[string]int{MeshPrune: 10, "PEERSCORE": 5, MeshGraft: 1})
+
+	want := []EventTypeSampling{
+		{EventType: "PEERSCORE", Rate: 5, Seen: 12, Recorded: 3, Factor: 4},
+		{EventType: MeshPrune, Rate: 10, Seen: 30, Recorded: 4, Factor: 7.5},
+	}
+
+	if len(sampling.Types) != len(want) {
+		t.Fatalf("Expected %d sampled types, got %+v", len(want), sampling.Types)
+	}
+
+	for i := range want {
+		if sampling.Types[i] != want[i] {
+			t.Errorf("Type %d: got %+v, want %+v", i, sampling.Types[i], want[i])
+		}
+	}
+}
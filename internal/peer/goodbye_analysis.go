@@ -1,7 +1,6 @@
 package peer
 
 import (
-	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -56,10 +55,6 @@ func AnalyzeGoodbyeReasons(events []GoodbyeEvent) map[string]*GoodbyeReasonStats
 		}
 	}
 
-	for _, stat := range stats {
-		fmt.Printf("stat: %+v\n", stat)
-	}
-
 	return stats
 }
 
@@ -74,7 +69,8 @@ func containsCode(codes []uint64, code uint64) bool {
 	return false
 }
 
-// CalculateGoodbyeEventsSummary aggregates goodbye event statistics from all peers.
+// CalculateGoodbyeEventsSummary aggregates goodbye event statistics from all peers, leaving
+// out the goodbyes sent during the run's shutdown.
 func CalculateGoodbyeEventsSummary(peers map[string]*Stats) GoodbyeEventsSummary {
 	var allEvents []GoodbyeEvent
 
@@ -82,8 +78,8 @@ func CalculateGoodbyeEventsSummary(peers map[string]*Stats) GoodbyeEventsSummary
 
 	// Collect all goodbye events from all peers
 	for _, peer := range peers {
-		for _, session := range peer.ConnectionSessions {
-			for _, goodbye := range session.GoodbyeEvents {
+		for i := range peer.ConnectionSessions {
+			for _, goodbye := range churnGoodbyes(&peer.ConnectionSessions[i]) {
 				allEvents = append(allEvents, goodbye)
 				codeFreq[goodbye.Code]++
 			}
@@ -136,8 +132,8 @@ func CalculateGoodbyeEventsSummaryFromInterface(peers map[string]interface{}) Go
 		// Handle different types of peer data
 		switch peer := peerData.(type) {
 		case *Stats:
-			for _, session := range peer.ConnectionSessions {
-				for _, goodbye := range session.GoodbyeEvents {
+			for i := range peer.ConnectionSessions {
+				for _, goodbye := range churnGoodbyes(&peer.ConnectionSessions[i]) {
 					allEvents = append(allEvents, goodbye)
 					codeFreq[goodbye.Code]++
 				}
@@ -151,7 +147,7 @@ func CalculateGoodbyeEventsSummaryFromInterface(peers map[string]interface{}) Go
 							for _, goodbyeData := range goodbyes {
 								if goodbyeMap, ok := goodbyeData.(map[string]interface{}); ok {
 									goodbye := extractGoodbyeEvent(goodbyeMap)
-									if goodbye != nil {
+									if goodbye != nil && !goodbye.InShutdown {
 										allEvents = append(allEvents, *goodbye)
 										codeFreq[goodbye.Code]++
 									}
@@ -241,6 +237,10 @@ func extractGoodbyeEvent(data map[string]interface{}) *GoodbyeEvent {
 		event.Reason = reason
 	}
 
+	if inShutdown, ok := data["in_shutdown"].(bool); ok {
+		event.InShutdown = inShutdown
+	}
+
 	return event
 }
 
@@ -0,0 +1,200 @@
+package peer
+
+import (
+	"sort"
+	"time"
+)
+
+// GoodbyeReconnectStats summarises what peers did after ending a session with a goodbye: whether
+// they connected to us again, how soon, and whether the next session lasted longer. Polite load
+// shedding, such as "too many peers", is followed by reconnects, a permanent rejection is not.
+type GoodbyeReconnectStats struct {
+	Code                   uint64  `json:"code,omitempty"`   // Set on the per-code breakdown
+	Reason                 string  `json:"reason,omitempty"` // Most common reason sent with the code
+	Client                 string  `json:"client,omitempty"` // Set on the per-client breakdown
+	Goodbyes               int     `json:"goodbyes"`         // Sessions ended by a goodbye
+	Reconnected            int     `json:"reconnected"`      // Of those, sessions followed by another one
+	MedianReconnectSeconds float64 `json:"median_reconnect_seconds"`
+	Compared               int     `json:"compared"`     // Reconnects whose session lengths could be compared
+	LongerAfter            int     `json:"longer_after"` // Of those, reconnects whose next session lasted longer
+}
+
+// GoodbyeReconnects breaks reconnects after a goodbye down by goodbye code and by client.
+type GoodbyeReconnects struct {
+	ByCode   []GoodbyeReconnectStats `json:"by_code"`
+	ByClient []GoodbyeReconnectStats `json:"by_client"`
+}
+
+// goodbyeReconnect is one session ended by a goodbye and what followed it.
+type goodbyeReconnect struct {
+	goodbye     GoodbyeEvent
+	client      string
+	reconnected bool
+	delay       time.Duration
+	compared    bool
+	longer      bool
+}
+
+// CalculateGoodbyeReconnects follows up every session a peer ended with a goodbye. The session's
+// last goodbye decides its code. Sessions cut short by a collector gap or by our own shutdown are
+// left out, as the peer had no chance to reconnect. A next session still open at end is measured
+// up to end, and only counts as compared once it has outlasted the goodbye session. Returns nil
+// when no session ended with a goodbye.
+func CalculateGoodbyeReconnects(peers map[string]*Stats, end time.Time) *GoodbyeReconnects {
+	var reconnects []goodbyeReconnect
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		client := stats.ClientType
+		if client == "" {
+			client = "unknown"
+		}
+
+		for i, session := range stats.ConnectionSessions {
+			if !session.Disconnected || session.EndedByGap || session.EndedInShutdown || session.EndedByRampRestart ||
+				len(session.GoodbyeEvents) == 0 {
+				continue
+			}
+
+			reconnect := goodbyeReconnect{
+				goodbye: session.GoodbyeEvents[len(session.GoodbyeEvents)-1],
+				client:  client,
+			}
+
+			if i+1 < len(stats.ConnectionSessions) {
+				followUpReconnect(&reconnect, session, stats.ConnectionSessions[i+1], end)
+			}
+
+			reconnects = append(reconnects, reconnect)
+		}
+	}
+
+	if len(reconnects) == 0 {
+		return nil
+	}
+
+	return &GoodbyeReconnects{
+		ByCode: summariseGoodbyeReconnects(reconnects, func(r goodbyeReconnect) GoodbyeReconnectStats {
+			return GoodbyeReconnectStats{Code: r.goodbye.Code}
+		}),
+		ByClient: summariseGoodbyeReconnects(reconnects, func(r goodbyeReconnect) GoodbyeReconnectStats {
+			return GoodbyeReconnectStats{Client: r.client}
+		}),
+	}
+}
+
+// followUpReconnect records how soon the next session followed the goodbye session and whether it
+// lasted longer.
+func followUpReconnect(reconnect *goodbyeReconnect, session, next ConnectionSession, end time.Time) {
+	if next.ConnectedAt == nil {
+		return
+	}
+
+	reconnect.reconnected = true
+
+	ended := reconnect.goodbye.Timestamp
+	if session.DisconnectedAt != nil {
+		ended = *session.DisconnectedAt
+	}
+
+	if delay := next.ConnectedAt.Sub(ended); delay > 0 {
+		reconnect.delay = delay
+	}
+
+	before, ok := sessionDuration(session)
+	if !ok {
+		return
+	}
+
+	if next.Disconnected {
+		after, ok := sessionDuration(next)
+		if !ok {
+			return
+		}
+
+		reconnect.compared = true
+		reconnect.longer = after > before
+
+		return
+	}
+
+	if end.Sub(*next.ConnectedAt) > before {
+		reconnect.compared = true
+		reconnect.longer = true
+	}
+}
+
+// summariseGoodbyeReconnects groups the followed-up goodbyes by the key returned for each, most
+// goodbyes first.
+func summariseGoodbyeReconnects(reconnects []goodbyeReconnect, key func(goodbyeReconnect) GoodbyeReconnectStats) []GoodbyeReconnectStats {
+	groups := make(map[GoodbyeReconnectStats]*GoodbyeReconnectStats)
+	delays := make(map[GoodbyeReconnectStats][]float64)
+	reasons := make(map[GoodbyeReconnectStats]map[goodbyeReasonKey]*GoodbyeReasonCount)
+
+	for _, reconnect := range reconnects {
+		k := key(reconnect)
+
+		group, ok := groups[k]
+		if !ok {
+			stats := k
+			group = &stats
+			groups[k] = group
+			reasons[k] = make(map[goodbyeReasonKey]*GoodbyeReasonCount)
+		}
+
+		group.Goodbyes++
+		countGoodbyeReason(reasons[k], reconnect.goodbye)
+
+		if !reconnect.reconnected {
+			continue
+		}
+
+		group.Reconnected++
+		delays[k] = append(delays[k], reconnect.delay.Seconds())
+
+		if reconnect.compared {
+			group.Compared++
+
+			if reconnect.longer {
+				group.LongerAfter++
+			}
+		}
+	}
+
+	summary := make([]GoodbyeReconnectStats, 0, len(groups))
+
+	for k, group := range groups {
+		group.MedianReconnectSeconds = median(delays[k])
+
+		if group.Client == "" {
+			if top := rankGoodbyeReasons(reasons[k], 1); len(top) > 0 {
+				group.Reason = top[0].Reason
+			}
+		}
+
+		summary = append(summary, *group)
+	}
+
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Goodbyes != summary[j].Goodbyes {
+			return summary[i].Goodbyes > summary[j].Goodbyes
+		}
+
+		if summary[i].Code != summary[j].Code {
+			return summary[i].Code < summary[j].Code
+		}
+
+		return summary[i].Client < summary[j].Client
+	})
+
+	return summary
+}
+
+// GoodbyeReconnectsFromInterface follows up goodbye sessions on generic peer data, as loaded from
+// a JSON report in HTML-only mode. Static peers and boot nodes are left out.
+func GoodbyeReconnectsFromInterface(peers map[string]interface{}, end time.Time) *GoodbyeReconnects {
+	return CalculateGoodbyeReconnects(GeneralPeers(statsFromInterface(peers)), end)
+}
@@ -0,0 +1,101 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateGoodbyeReconnects(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	tooManyPeers := GoodbyeEvent{Code: 129, Reason: "too many peers"}
+	irrelevant := GoodbyeEvent{Code: 3, Reason: "irrelevant network"}
+
+	peers := map[string]*Stats{
+		// Shed twice, back within a minute each time, and staying longer the second time.
+		"shed": {ClientType: "lighthouse", ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(60), Disconnected: true, GoodbyeEvents: []GoodbyeEvent{tooManyPeers}},
+			{ConnectedAt: at(90), DisconnectedAt: at(120), Disconnected: true, GoodbyeEvents: []GoodbyeEvent{tooManyPeers}},
+			{ConnectedAt: at(150)},
+		}},
+		// Rejected for good.
+		"rejected": {ClientType: "prysm", ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(10), Disconnected: true, GoodbyeEvents: []GoodbyeEvent{irrelevant}},
+		}},
+		// The goodbye session ended with our shutdown, so there was no chance to reconnect.
+		"shutdown": {ClientType: "teku", ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(10), Disconnected: true, EndedInShutdown: true, GoodbyeEvents: []GoodbyeEvent{irrelevant}},
+		}},
+		"no-goodbye": {ClientType: "nimbus", ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(10), Disconnected: true},
+			{ConnectedAt: at(20)},
+		}},
+	}
+
+	result := CalculateGoodbyeReconnects(peers, start.Add(time.Hour))
+	if result == nil {
+		t.Fatal("Expected goodbye reconnects")
+	}
+
+	if len(result.ByCode) != 2 {
+		t.Fatalf("Expected 2 goodbye codes, got %+v", result.ByCode)
+	}
+
+	shed := result.ByCode[0]
+	if shed.Code != 129 || shed.Reason != "too many peers" || shed.Goodbyes != 2 || shed.Reconnected != 2 {
+		t.Errorf("Expected both too many peers goodbyes to reconnect, got %+v", shed)
+	}
+
+	if shed.MedianReconnectSeconds != 30 {
+		t.Errorf("Expected a median reconnect of 30s, got %.1f", shed.MedianReconnectSeconds)
+	}
+
+	// 60s then 30s is shorter, and the open session outlasted the 30s before it.
+	if shed.Compared != 2 || shed.LongerAfter != 1 {
+		t.Errorf("Expected 1 of 2 reconnects to last longer, got %d of %d", shed.LongerAfter, shed.Compared)
+	}
+
+	rejected := result.ByCode[1]
+	if rejected.Code != 3 || rejected.Goodbyes != 1 || rejected.Reconnected != 0 {
+		t.Errorf("Expected the irrelevant network goodbye not to reconnect, got %+v", rejected)
+	}
+
+	if len(result.ByClient) != 2 || result.ByClient[0].Client != "lighthouse" || result.ByClient[1].Client != "prysm" {
+		t.Errorf("Expected lighthouse then prysm, got %+v", result.ByClient)
+	}
+
+	if CalculateGoodbyeReconnects(map[string]*Stats{"no-goodbye": peers["no-goodbye"]}, start) != nil {
+		t.Error("Expected nil without goodbyes")
+	}
+}
+
+func TestGoodbyeReconnectsOpenSession(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	disconnected := start.Add(time.Minute)
+	reconnected := start.Add(2 * time.Minute)
+
+	peers := map[string]*Stats{
+		"peer": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: &start, DisconnectedAt: &disconnected, Disconnected: true, GoodbyeEvents: []GoodbyeEvent{{Code: 129}}},
+			{ConnectedAt: &reconnected},
+		}},
+	}
+
+	// The open session has lasted 30s of the minute before it, too early to compare.
+	result := CalculateGoodbyeReconnects(peers, reconnected.Add(30*time.Second))
+
+	stats := result.ByCode[0]
+	if stats.Reconnected != 1 || stats.Compared != 0 {
+		t.Errorf("Expected a reconnect that could not be compared yet, got %+v", stats)
+	}
+
+	if result.ByClient[0].Client != "unknown" {
+		t.Errorf("Expected a peer without a client type to be unknown, got %s", result.ByClient[0].Client)
+	}
+}
@@ -0,0 +1,330 @@
+package peer
+
+import (
+	"sort"
+	"time"
+)
+
+// DivergenceEpisode is a run of consecutive statuses in one session whose head slot trailed
+// ours by more than the threshold, with what followed it.
+type DivergenceEpisode struct {
+	PeerID       string    `json:"peer_id"`
+	ClientType   string    `json:"client_type"`
+	Session      int       `json:"session"` // From 1
+	Start        time.Time `json:"start"`   // First lagging status
+	End          time.Time `json:"end"`     // Last lagging status
+	Statuses     int       `json:"statuses"`
+	MaxBehind    uint64    `json:"max_behind"` // Slots
+	Recovered    bool      `json:"recovered"`  // A later status in the session caught up
+	ScoreDrop    float64   `json:"score_drop"` // Largest fall below the score before the episode within the follow window
+	ScoreDropped bool      `json:"score_dropped"`
+	Goodbye      bool      `json:"goodbye"`      // The peer said goodbye within the follow window
+	Disconnected bool      `json:"disconnected"` // The session ended within the follow window
+}
+
+// HeadDivergence summarises how far the peers' head slots trailed ours over the run. A peer
+// that falls behind the chain scores our gossip as useless to it and tends to leave, which
+// explains churn the peer's goodbye alone does not.
+type HeadDivergence struct {
+	ThresholdSlots uint64  `json:"threshold_slots"` // Lagging by more than this starts an episode
+	FollowSeconds  float64 `json:"follow_seconds"`  // After an episode starts, the window score drops and goodbyes are attributed in
+	Peers          int     `json:"peers"`           // Peers with at least one status compared
+	Statuses       int     `json:"statuses"`
+	Estimated      int     `json:"estimated"` // Statuses compared against our head estimated from the slot clock or an earlier status
+
+	DivergingPeers int `json:"diverging_peers"`
+	Episodes       int `json:"episodes"`
+	WithScoreDrop  int `json:"with_score_drop"`
+	WithGoodbye    int `json:"with_goodbye"`
+	WithDisconnect int `json:"with_disconnect"`
+
+	// Sessions with an episode against those whose statuses stayed in sync, and how many of
+	// each the peer said goodbye in before Hermes shut down
+	DivergingSessions    int     `json:"diverging_sessions"`
+	DivergingGoodbyes    int     `json:"diverging_goodbyes"`
+	InSyncSessions       int     `json:"in_sync_sessions"`
+	InSyncGoodbyes       int     `json:"in_sync_goodbyes"`
+	DivergingGoodbyeRate float64 `json:"diverging_goodbye_rate"`
+	InSyncGoodbyeRate    float64 `json:"in_sync_goodbye_rate"`
+
+	Top []DivergenceEpisode `json:"top"` // Furthest behind first
+}
+
+// localHead is one sighting of our head slot, from a status we answered.
+type localHead struct {
+	at   time.Time
+	slot uint64
+}
+
+// AnalyzeHeadDivergence compares each status's head slot with ours at the time and records
+// the episodes a peer trailed by more than threshold slots. Our head is the one we answered a
+// peer's status with, or else the nearest one we answered with moved on by the slots elapsed,
+// or else the slot clock's current slot. Each status is annotated with the head it was compared
+// against. Nil without a slot clock.
+func AnalyzeHeadDivergence(peers map[string]*Stats, clock *SlotClock, threshold uint64, follow time.Duration, scoreDrop float64, limit int) *HeadDivergence {
+	if clock == nil {
+		return nil
+	}
+
+	result := &HeadDivergence{
+		ThresholdSlots: threshold,
+		FollowSeconds:  follow.Seconds(),
+		Top:            make([]DivergenceEpisode, 0),
+	}
+
+	heads := localHeads(peers)
+	episodes := make([]DivergenceEpisode, 0)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		compared := false
+		diverging := false
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			sessionEpisodes := make([]DivergenceEpisode, 0)
+
+			var current *DivergenceEpisode
+
+			for j := range session.StatusUpdates {
+				update := &session.StatusUpdates[j]
+				if update.Error != "" || update.HeadSlot == 0 {
+					continue
+				}
+
+				if update.LocalHeadSlot == 0 {
+					update.LocalHeadSlot = estimateLocalHead(heads, clock, update.Timestamp)
+					result.Estimated++
+				}
+
+				compared = true
+				result.Statuses++
+
+				behind := uint64(0)
+				if update.LocalHeadSlot > update.HeadSlot {
+					behind = update.LocalHeadSlot - update.HeadSlot
+				}
+
+				if behind <= threshold {
+					if current != nil {
+						current.Recovered = true
+						sessionEpisodes = append(sessionEpisodes, *current)
+						current = nil
+					}
+
+					continue
+				}
+
+				if current == nil {
+					current = &DivergenceEpisode{
+						PeerID:     peerID,
+						ClientType: stats.ClientType,
+						Session:    i + 1,
+						Start:      update.Timestamp,
+					}
+				}
+
+				current.End = update.Timestamp
+				current.Statuses++
+				current.MaxBehind = max(current.MaxBehind, behind)
+			}
+
+			if current != nil {
+				sessionEpisodes = append(sessionEpisodes, *current)
+			}
+
+			if len(session.StatusUpdates) == 0 {
+				continue
+			}
+
+			goodbye := sessionGoodbye(session)
+
+			if len(sessionEpisodes) == 0 {
+				result.InSyncSessions++
+
+				if goodbye {
+					result.InSyncGoodbyes++
+				}
+
+				continue
+			}
+
+			diverging = true
+			result.DivergingSessions++
+
+			if goodbye {
+				result.DivergingGoodbyes++
+			}
+
+			for _, episode := range sessionEpisodes {
+				followEpisode(&episode, session, follow, scoreDrop)
+				episodes = append(episodes, episode)
+			}
+		}
+
+		if compared {
+			result.Peers++
+		}
+
+		if diverging {
+			result.DivergingPeers++
+		}
+	}
+
+	for _, episode := range episodes {
+		result.Episodes++
+
+		if episode.ScoreDropped {
+			result.WithScoreDrop++
+		}
+
+		if episode.Goodbye {
+			result.WithGoodbye++
+		}
+
+		if episode.Disconnected {
+			result.WithDisconnect++
+		}
+	}
+
+	if result.DivergingSessions > 0 {
+		result.DivergingGoodbyeRate = float64(result.DivergingGoodbyes) / float64(result.DivergingSessions)
+	}
+
+	if result.InSyncSessions > 0 {
+		result.InSyncGoodbyeRate = float64(result.InSyncGoodbyes) / float64(result.InSyncSessions)
+	}
+
+	sort.Slice(episodes, func(i, j int) bool {
+		if episodes[i].MaxBehind != episodes[j].MaxBehind {
+			return episodes[i].MaxBehind > episodes[j].MaxBehind
+		}
+
+		if episodes[i].PeerID != episodes[j].PeerID {
+			return episodes[i].PeerID < episodes[j].PeerID
+		}
+
+		return episodes[i].Start.Before(episodes[j].Start)
+	})
+
+	if len(episodes) > limit {
+		episodes = episodes[:limit]
+	}
+
+	result.Top = append(result.Top, episodes...)
+
+	return result
+}
+
+// localHeads collects the heads we answered the peers' statuses with, in time order.
+func localHeads(peers map[string]*Stats) []localHead {
+	heads := make([]localHead, 0)
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for _, session := range stats.ConnectionSessions {
+			for _, update := range session.StatusUpdates {
+				if update.LocalHeadSlot > 0 {
+					heads = append(heads, localHead{at: update.Timestamp, slot: update.LocalHeadSlot})
+				}
+			}
+		}
+	}
+
+	sort.Slice(heads, func(i, j int) bool { return heads[i].at.Before(heads[j].at) })
+
+	return heads
+}
+
+// estimateLocalHead returns our head at t: the last one we answered with before t moved on by
+// the slots elapsed since, the first one after t moved back if there is none before, and the
+// slot clock's slot without any. A synced node's head follows the slot clock.
+func estimateLocalHead(heads []localHead, clock *SlotClock, at time.Time) uint64 {
+	slot := clock.SlotAt(at)
+	if len(heads) == 0 {
+		return slot
+	}
+
+	i := sort.Search(len(heads), func(i int) bool { return heads[i].at.After(at) })
+
+	nearest := heads[0]
+	if i > 0 {
+		nearest = heads[i-1]
+	}
+
+	seen := clock.SlotAt(nearest.at)
+	if slot >= seen {
+		return nearest.slot + (slot - seen)
+	}
+
+	if nearest.slot > seen-slot {
+		return nearest.slot - (seen - slot)
+	}
+
+	return 0
+}
+
+// sessionGoodbye returns whether the peer said goodbye in the session before Hermes shut down.
+func sessionGoodbye(session *ConnectionSession) bool {
+	for _, event := range session.GoodbyeEvents {
+		if !event.InShutdown {
+			return true
+		}
+	}
+
+	return false
+}
+
+// followEpisode records the score drop, goodbye and disconnect that followed an episode within
+// the follow window after it started. The score before the episode is the last snapshot taken
+// before it, or the first one within it.
+func followEpisode(episode *DivergenceEpisode, session *ConnectionSession, follow time.Duration, scoreDrop float64) {
+	until := episode.Start.Add(follow)
+	if episode.End.After(episode.Start) {
+		until = episode.End.Add(follow)
+	}
+
+	baseline, lowest := 0.0, 0.0
+	haveBaseline, haveLowest := false, false
+
+	for _, snapshot := range session.PeerScores {
+		if snapshot.BeaconDegraded || snapshot.Timestamp.After(until) {
+			continue
+		}
+
+		if snapshot.Timestamp.Before(episode.Start) {
+			baseline, haveBaseline = snapshot.Score, true
+
+			continue
+		}
+
+		if !haveBaseline {
+			baseline, haveBaseline = snapshot.Score, true
+		}
+
+		if !haveLowest || snapshot.Score < lowest {
+			lowest, haveLowest = snapshot.Score, true
+		}
+	}
+
+	if haveBaseline && haveLowest && baseline > lowest {
+		episode.ScoreDrop = baseline - lowest
+		episode.ScoreDropped = episode.ScoreDrop >= scoreDrop
+	}
+
+	for _, event := range session.GoodbyeEvents {
+		if !event.InShutdown && !event.Timestamp.Before(episode.Start) && !event.Timestamp.After(until) {
+			episode.Goodbye = true
+		}
+	}
+
+	if session.DisconnectedAt != nil && !session.DisconnectedAt.Before(episode.Start) && !session.DisconnectedAt.After(until) {
+		episode.Disconnected = true
+	}
+}
@@ -0,0 +1,106 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeHeadDivergence(t *testing.T) {
+	genesis := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewSlotClock(genesis, 12*time.Second, 32)
+	slot := func(s uint64) time.Time { return genesis.Add(time.Duration(s) * 12 * time.Second) }
+
+	if AnalyzeHeadDivergence(map[string]*Stats{}, nil, 32, 5*time.Minute, 10, 10) != nil {
+		t.Fatal("Expected nil without a slot clock")
+	}
+
+	disconnected := slot(1050)
+	peers := map[string]*Stats{
+		// Falls behind, loses score and says goodbye
+		"lagging": {
+			ClientType: "prysm",
+			ConnectionSessions: []ConnectionSession{{
+				DisconnectedAt: &disconnected,
+				StatusUpdates: []StatusUpdate{
+					{Timestamp: slot(1000), HeadSlot: 1000, LocalHeadSlot: 1000},
+					{Timestamp: slot(1020), HeadSlot: 960, LocalHeadSlot: 1020},
+					{Timestamp: slot(1040), HeadSlot: 970, LocalHeadSlot: 1040},
+				},
+				PeerScores: []PeerScoreSnapshot{
+					{Timestamp: slot(1010), Score: 5},
+					{Timestamp: slot(1030), Score: -8},
+					{Timestamp: slot(1045), Score: -2, BeaconDegraded: true},
+				},
+				GoodbyeEvents: []GoodbyeEvent{{Timestamp: slot(1045), Code: 3}},
+			}},
+		},
+		// Falls behind once and catches up, compared against our estimated head
+		"recovering": {
+			ClientType: "lighthouse",
+			ConnectionSessions: []ConnectionSession{{
+				StatusUpdates: []StatusUpdate{
+					{Timestamp: slot(1010), HeadSlot: 1010},
+					{Timestamp: slot(1030), HeadSlot: 990},
+					{Timestamp: slot(1060), HeadSlot: 1060},
+				},
+			}},
+		},
+		// In sync, says goodbye only at shutdown
+		"synced": {
+			ClientType: "teku",
+			ConnectionSessions: []ConnectionSession{{
+				StatusUpdates: []StatusUpdate{
+					{Timestamp: slot(1000), HeadSlot: 999},
+					{Timestamp: slot(1100), HeadSlot: 1100, LocalHeadSlot: 1100},
+					{Timestamp: slot(1110), Error: "stream reset"},
+				},
+				GoodbyeEvents: []GoodbyeEvent{{Timestamp: slot(1120), Code: 1, InShutdown: true}},
+			}},
+		},
+	}
+
+	result := AnalyzeHeadDivergence(peers, clock, 32, 5*time.Minute, 10, 10)
+
+	if result.Peers != 3 || result.Statuses != 8 || result.Estimated != 4 {
+		t.Errorf("Expected 3 peers, 8 statuses and 4 estimated, got %d, %d and %d", result.Peers, result.Statuses, result.Estimated)
+	}
+
+	if result.DivergingPeers != 2 || result.Episodes != 2 {
+		t.Fatalf("Expected 2 diverging peers with 2 episodes, got %d and %d", result.DivergingPeers, result.Episodes)
+	}
+
+	if result.WithScoreDrop != 1 || result.WithGoodbye != 1 || result.WithDisconnect != 1 {
+		t.Errorf("Expected 1 episode each with a score drop, goodbye and disconnect, got %d, %d and %d", result.WithScoreDrop, result.WithGoodbye, result.WithDisconnect)
+	}
+
+	if result.DivergingSessions != 2 || result.InSyncSessions != 1 {
+		t.Errorf("Expected 2 diverging and 1 in-sync session, got %d and %d", result.DivergingSessions, result.InSyncSessions)
+	}
+
+	if result.DivergingGoodbyeRate != 0.5 || result.InSyncGoodbyeRate != 0 {
+		t.Errorf("Expected goodbye rates 0.5 and 0, got %.2f and %.2f", result.DivergingGoodbyeRate, result.InSyncGoodbyeRate)
+	}
+
+	lagging := result.Top[0]
+	if lagging.PeerID != "lagging" || lagging.MaxBehind != 70 || lagging.Statuses != 2 || lagging.Recovered {
+		t.Errorf("Expected the lagging peer 70 slots behind over 2 statuses first, got %+v", lagging)
+	}
+
+	if lagging.ScoreDrop != 13 || !lagging.ScoreDropped || !lagging.Goodbye || !lagging.Disconnected {
+		t.Errorf("Expected the lagging episode to drop 13 and end in a goodbye, got %+v", lagging)
+	}
+
+	recovering := result.Top[1]
+	if recovering.PeerID != "recovering" || recovering.MaxBehind != 40 || !recovering.Recovered || recovering.ScoreDropped {
+		t.Errorf("Expected the recovering peer 40 slots behind and recovered, got %+v", recovering)
+	}
+
+	// Our head moved on from the nearest status we answered
+	if got := peers["recovering"].ConnectionSessions[0].StatusUpdates[1].LocalHeadSlot; got != 1030 {
+		t.Errorf("Expected our estimated head 1030, got %d", got)
+	}
+
+	if limited := AnalyzeHeadDivergence(peers, clock, 32, 5*time.Minute, 10, 1); len(limited.Top) != 1 || limited.Episodes != 2 {
+		t.Errorf("Expected 1 of 2 episodes listed, got %d of %d", len(limited.Top), limited.Episodes)
+	}
+}
@@ -0,0 +1,119 @@
+package peer
+
+import "github.com/ethpandaops/hermes-peer-score/internal/glossary"
+
+// Mesh event types, as traced by Hermes.
+const (
+	MeshGraft = "GRAFT"
+	MeshPrune = "PRUNE"
+)
+
+// HermesMetrics holds the run metrics most sensitive to changes in Hermes itself,
+// used to compare runs across Hermes fork bumps.
+type HermesMetrics struct {
+	Peers                  int     `json:"peers"`                      // Peers with at least one session
+	MeshPeers              int     `json:"mesh_peers"`                 // Peers grafted into the mesh at least once
+	MeshAdoption           float64 `json:"mesh_adoption"`              // Share of peers grafted into the mesh
+	ScoredSessions         int     `json:"scored_sessions"`            // Sessions with a score snapshot
+	MedianTimeToFirstScore float64 `json:"median_time_to_first_score"` // Seconds from connect to the first score snapshot
+	Grafts                 int     `json:"grafts"`                     // GRAFT events across all sessions
+	Prunes                 int     `json:"prunes"`                     // PRUNE events across all sessions
+	PruneRate              float64 `json:"prune_rate"`                 // PRUNE events per GRAFT
+}
+
+// HermesMetricsDefinitions define the HermesMetrics rates.
+var HermesMetricsDefinitions = []glossary.Definition{
+	{
+		Name:    "Mesh Adoption",
+		Field:   "mesh_adoption",
+		Formula: "Peers grafted into the mesh at least once / peers with a session",
+		Caveats: []string{"A fraction, each peer weighted by its detail sampling weight"},
+	},
+	{
+		Name:    "Prune Rate",
+		Field:   "prune_rate",
+		Formula: "PRUNE events / GRAFT events",
+		Caveats: []string{"Per GRAFT, not per session; sampled mesh events are scaled up to the events seen"},
+	},
+	{
+		Name:    "Median Time To First Score",
+		Field:   "median_time_to_first_score",
+		Formula: "Weighted median of the seconds from connect to a session's first score snapshot",
+		Caveats: []string{"Only sessions that were scored at all"},
+	},
+}
+
+// CalculateHermesMetrics derives the Hermes sensitive metrics from the peer statistics.
+// Counts are of the recorded detail, scaled up to the events seen where mesh events were
+// sampled one in N, while the rates and median weight each peer by its sampling weight, so
+// they stay representative when only a sample of peers was captured.
+func CalculateHermesMetrics(peers map[string]*Stats) HermesMetrics {
+	var (
+		metrics      HermesMetrics
+		firstScoreAt = make([]float64, 0)
+		scoreWeights = make([]float64, 0)
+
+		peerWeight, meshWeight, graftWeight, pruneWeight float64
+	)
+
+	for _, stats := range peers {
+		if stats == nil || len(stats.ConnectionSessions) == 0 {
+			continue
+		}
+
+		metrics.Peers++
+
+		weight := stats.DetailWeight()
+		peerWeight += weight
+		grafted := false
+
+		for _, session := range stats.ConnectionSessions {
+			grafts, prunes := session.MeshCounts()
+			grafted = grafted || grafts > 0
+
+			metrics.Grafts += grafts
+			metrics.Prunes += prunes
+			graftWeight += weight * float64(grafts)
+			pruneWeight += weight * float64(prunes)
+
+			if session.ConnectedAt == nil || len(session.PeerScores) == 0 {
+				continue
+			}
+
+			// Snapshots are appended as they arrive, so the earliest may not be first after a resume
+			first := session.PeerScores[0].Timestamp
+			for _, snapshot := range session.PeerScores[1:] {
+				if snapshot.Timestamp.Before(first) {
+					first = snapshot.Timestamp
+				}
+			}
+
+			metrics.ScoredSessions++
+			firstScoreAt = append(firstScoreAt, max(first.Sub(*session.ConnectedAt).Seconds(), 0))
+			scoreWeights = append(scoreWeights, weight)
+		}
+
+		if grafted {
+			metrics.MeshPeers++
+			meshWeight += weight
+		}
+	}
+
+	if peerWeight > 0 {
+		metrics.MeshAdoption = meshWeight / peerWeight
+	}
+
+	if graftWeight > 0 {
+		metrics.PruneRate = pruneWeight / graftWeight
+	}
+
+	metrics.MedianTimeToFirstScore = WeightedMedian(firstScoreAt, scoreWeights)
+
+	return metrics
+}
+
+// CalculateHermesMetricsFromInterface derives the Hermes sensitive metrics from generic peer
+// data, as loaded from a JSON report.
+func CalculateHermesMetricsFromInterface(peers map[string]interface{}) HermesMetrics {
+	return CalculateHermesMetrics(statsFromInterface(peers))
+}
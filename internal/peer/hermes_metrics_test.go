@@ -0,0 +1,122 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateHermesMetrics(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	session := func(firstScore time.Duration, meshEvents ...string) ConnectionSession {
+		connected := start
+		s := ConnectionSession{ConnectedAt: &connected}
+
+		if firstScore > 0 {
+			s.PeerScores = []PeerScoreSnapshot{
+				{Timestamp: start.Add(firstScore * 2)},
+				{Timestamp: start.Add(firstScore)},
+			}
+		}
+
+		for _, eventType := range meshEvents {
+			s.MeshEvents = append(s.MeshEvents, MeshEvent{Type: eventType})
+		}
+
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		peers    map[string]*Stats
+		expected HermesMetrics
+	}{
+		{
+			name:     "no peers",
+			peers:    map[string]*Stats{},
+			expected: HermesMetrics{},
+		},
+		{
+			name: "mixed mesh participation",
+			peers: map[string]*Stats{
+				"a": {ConnectionSessions: []ConnectionSession{session(10*time.Second, MeshGraft, MeshPrune)}},
+				"b": {ConnectionSessions: []ConnectionSession{session(30*time.Second, MeshGraft), session(20 * time.Second)}},
+				"c": {ConnectionSessions: []ConnectionSession{session(0, MeshPrune)}},
+				"d": {ConnectionSessions: []ConnectionSession{session(40 * time.Second)}},
+				"e": {}, // No sessions, not counted
+			},
+			expected: HermesMetrics{
+				Peers:                  4,
+				MeshPeers:              2,
+				MeshAdoption:           0.5,
+				ScoredSessions:         4,
+				MedianTimeToFirstScore: 25,
+				Grafts:                 2,
+				Prunes:                 2,
+				PruneRate:              1,
+			},
+		},
+		{
+			name: "sampled peers are weighted",
+			peers: map[string]*Stats{
+				"baseline": {
+					Sample:             &DetailSample{Captured: true, Reason: SampleBaseline, Weight: 4},
+					ConnectionSessions: []ConnectionSession{session(10*time.Second, MeshGraft, MeshPrune)},
+				},
+				"goodbye": {
+					Sample:             &DetailSample{Captured: true, Reason: SampleGoodbye, Weight: 1},
+					ConnectionSessions: []ConnectionSession{session(30*time.Second, MeshGraft)},
+				},
+				"reconnected": {
+					Sample:             &DetailSample{Captured: true, Reason: SampleReconnected, Weight: 1},
+					ConnectionSessions: []ConnectionSession{session(40 * time.Second)},
+				},
+				"not-captured": {
+					Sample:             &DetailSample{},
+					ConnectionSessions: []ConnectionSession{session(0)},
+				},
+			},
+			expected: HermesMetrics{
+				Peers:                  4,
+				MeshPeers:              2,
+				MeshAdoption:           5.0 / 6,
+				ScoredSessions:         3,
+				MedianTimeToFirstScore: 10,
+				Grafts:                 2,
+				Prunes:                 1,
+				PruneRate:              0.8,
+			},
+		},
+		{
+			name: "sampled mesh events count every event seen",
+			peers: map[string]*Stats{
+				"a": {ConnectionSessions: []ConnectionSession{func() ConnectionSession {
+					s := session(10*time.Second, MeshGraft, MeshPrune, MeshPrune)
+					s.EventSamples = EventSampleCounts{MeshPrune: {Seen: 12, Recorded: 2}}
+
+					return s
+				}()}},
+				"b": {ConnectionSessions: []ConnectionSession{session(30*time.Second, MeshGraft, MeshGraft, MeshGraft)}},
+			},
+			expected: HermesMetrics{
+				Peers:                  2,
+				MeshPeers:              2,
+				MeshAdoption:           1,
+				ScoredSessions:         2,
+				MedianTimeToFirstScore: 20,
+				Grafts:                 4,
+				Prunes:                 12,
+				PruneRate:              3,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateHermesMetrics(tt.peers)
+			if got != tt.expected {
+				t.Errorf("CalculateHermesMetrics() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
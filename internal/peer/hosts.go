@@ -0,0 +1,74 @@
+package peer
+
+// HostPeers holds the peers observed by one of several parallel Hermes hosts.
+type HostPeers struct {
+	Label      string
+	Libp2pPort int
+	Devp2pPort int
+	Settings   string // Overrides of the run's settings in the --hosts syntax
+	Peers      map[string]*Stats
+}
+
+// HostSummary holds the headline statistics of one of several parallel Hermes hosts,
+// used to compare configurations against the same peer population.
+type HostSummary struct {
+	Label                string         `json:"label"`
+	Libp2pPort           int            `json:"libp2p_port"`
+	Devp2pPort           int            `json:"devp2p_port"`
+	Settings             string         `json:"settings,omitempty"`
+	Primary              bool           `json:"primary"`
+	UniquePeers          int            `json:"unique_peers"`
+	SharedPeers          int            `json:"shared_peers"` // Peers also seen by at least one other host
+	TotalConnections     int            `json:"total_connections"`
+	SuccessfulHandshakes int            `json:"successful_handshakes"`
+	FailedHandshakes     int            `json:"failed_handshakes"`
+	ClientDistribution   map[string]int `json:"client_distribution"`
+}
+
+// SummarizeHosts builds a summary per host, the first host being the primary one.
+// When phases are given, connection statistics only cover the measurement window.
+func SummarizeHosts(hosts []HostPeers, phases *RunPhases) []HostSummary {
+	calculator := NewStatsCalculator()
+
+	// Count how many hosts saw each peer
+	seenBy := make(map[string]int)
+
+	for _, host := range hosts {
+		for peerID := range host.Peers {
+			seenBy[peerID]++
+		}
+	}
+
+	summaries := make([]HostSummary, 0, len(hosts))
+
+	for i, host := range hosts {
+		stats := calculator.CalculateConnectionStats(host.Peers)
+		if phases != nil {
+			stats = calculator.CalculateConnectionStatsInWindow(host.Peers, phases)
+		}
+
+		shared := 0
+
+		for peerID := range host.Peers {
+			if seenBy[peerID] > 1 {
+				shared++
+			}
+		}
+
+		summaries = append(summaries, HostSummary{
+			Label:                host.Label,
+			Libp2pPort:           host.Libp2pPort,
+			Devp2pPort:           host.Devp2pPort,
+			Settings:             host.Settings,
+			Primary:              i == 0,
+			UniquePeers:          len(host.Peers),
+			SharedPeers:          shared,
+			TotalConnections:     stats.TotalConnections,
+			SuccessfulHandshakes: stats.SuccessfulHandshakes,
+			FailedHandshakes:     stats.FailedHandshakes,
+			ClientDistribution:   calculator.CalculateClientDistribution(host.Peers),
+		})
+	}
+
+	return summaries
+}
@@ -0,0 +1,63 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeHosts(t *testing.T) {
+	now := time.Now()
+
+	connected := func(identified bool) ConnectionSession {
+		session := ConnectionSession{ConnectedAt: &now}
+		if identified {
+			session.IdentifiedAt = &now
+		}
+
+		return session
+	}
+
+	hosts := []HostPeers{
+		{
+			Label:      "baseline",
+			Libp2pPort: 9000,
+			Peers: map[string]*Stats{
+				"shared": {ClientType: "lighthouse", ConnectionSessions: []ConnectionSession{connected(true)}},
+				"only-a": {ClientType: "prysm", ConnectionSessions: []ConnectionSession{connected(false)}},
+			},
+		},
+		{
+			Label: "experiment",
+			Peers: map[string]*Stats{
+				"shared": {ClientType: "lighthouse", ConnectionSessions: []ConnectionSession{connected(true), connected(true)}},
+			},
+		},
+	}
+
+	summaries := SummarizeHosts(hosts, nil)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(summaries))
+	}
+
+	baseline, experiment := summaries[0], summaries[1]
+
+	if !baseline.Primary || experiment.Primary {
+		t.Errorf("expected only the first host to be primary")
+	}
+
+	if baseline.UniquePeers != 2 || baseline.SharedPeers != 1 {
+		t.Errorf("unexpected baseline peers: unique=%d shared=%d", baseline.UniquePeers, baseline.SharedPeers)
+	}
+
+	if baseline.SuccessfulHandshakes != 1 || baseline.FailedHandshakes != 1 {
+		t.Errorf("unexpected baseline handshakes: %+v", baseline)
+	}
+
+	if experiment.TotalConnections != 2 || experiment.SharedPeers != 1 {
+		t.Errorf("unexpected experiment summary: %+v", experiment)
+	}
+
+	if experiment.ClientDistribution["lighthouse"] != 1 {
+		t.Errorf("unexpected experiment clients: %v", experiment.ClientDistribution)
+	}
+}
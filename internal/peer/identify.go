@@ -0,0 +1,72 @@
+package peer
+
+import (
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// IdentifyAttempts condenses our status requests to a peer, each an attempt at identifying it.
+// A peer that took several attempts, or failed repeatedly and then said goodbye, hides behind a
+// plain handshake success or failure count.
+type IdentifyAttempts struct {
+	Attempts           int       `json:"attempts"`
+	Failed             int       `json:"failed"`
+	AttemptsToIdentify int       `json:"attempts_to_identify"` // Up to and including the first that succeeded, zero when none did
+	LatenciesMs        []float64 `json:"latencies_ms"`         // From connecting to the answer of each attempt up to the identifying one
+	FailedThenGoodbye  bool      `json:"failed_then_goodbye"`  // A session saw repeated failed attempts, then a goodbye
+}
+
+// IdentifyAttempts counts our status requests across the peer's sessions, in session order.
+func (s *Stats) IdentifyAttempts() IdentifyAttempts {
+	result := IdentifyAttempts{LatenciesMs: make([]float64, 0)}
+
+	for _, session := range s.ConnectionSessions {
+		failed := 0
+
+		for _, update := range session.StatusUpdates {
+			if update.Inbound {
+				continue
+			}
+
+			result.Attempts++
+
+			if result.AttemptsToIdentify == 0 {
+				result.LatenciesMs = append(result.LatenciesMs, update.LatencyMs)
+			}
+
+			if update.Error != "" {
+				result.Failed++
+				failed++
+
+				if failed == constants.IdentifyRepeatedFailures && goodbyeAfter(session, update) {
+					result.FailedThenGoodbye = true
+				}
+
+				continue
+			}
+
+			if result.AttemptsToIdentify == 0 {
+				result.AttemptsToIdentify = result.Attempts
+			}
+		}
+	}
+
+	return result
+}
+
+// goodbyeAfter reports whether the peer said goodbye in the session at or after the update.
+func goodbyeAfter(session ConnectionSession, update StatusUpdate) bool {
+	for _, goodbye := range session.GoodbyeEvents {
+		if !goodbye.Timestamp.Before(update.Timestamp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IdentifyRetryPeer is a peer at least one of our status requests to failed.
+type IdentifyRetryPeer struct {
+	PeerID     string `json:"peer_id"`
+	ClientType string `json:"client_type"`
+	IdentifyAttempts
+}
@@ -0,0 +1,58 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdentifyAttempts(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	failed := func(seconds, attempt int) StatusUpdate {
+		return StatusUpdate{Timestamp: start.Add(time.Duration(seconds) * time.Second), Attempt: attempt, LatencyMs: float64(seconds % 60 * 1000), Error: "stream reset"}
+	}
+
+	stats := &Stats{ConnectionSessions: []ConnectionSession{
+		// Two failed attempts, then the peer says goodbye
+		{
+			StatusUpdates: []StatusUpdate{failed(1, 1), failed(3, 2)},
+			GoodbyeEvents: []GoodbyeEvent{{Timestamp: start.Add(4 * time.Second), Code: 3}},
+		},
+		// Reconnected, one more failure before the peer answers, inbound statuses are not ours
+		{
+			StatusUpdates: []StatusUpdate{
+				{Timestamp: start.Add(61 * time.Second), Inbound: true, HeadSlot: 99},
+				failed(62, 1),
+				{Timestamp: start.Add(65 * time.Second), Attempt: 2, LatencyMs: 5000, HeadSlot: 100},
+				{Timestamp: start.Add(125 * time.Second), Attempt: 3, LatencyMs: 65000, HeadSlot: 105},
+			},
+		},
+	}}
+
+	got := stats.IdentifyAttempts()
+
+	if got.Attempts != 5 || got.Failed != 3 || got.AttemptsToIdentify != 4 || !got.FailedThenGoodbye {
+		t.Errorf("Unexpected attempts %+v", got)
+	}
+
+	want := []float64{1000, 3000, 2000, 5000}
+	if len(got.LatenciesMs) != len(want) {
+		t.Fatalf("Expected latencies %v, got %v", want, got.LatenciesMs)
+	}
+
+	for i := range want {
+		if got.LatenciesMs[i] != want[i] {
+			t.Errorf("Latency %d: got %.0f, want %.0f", i, got.LatenciesMs[i], want[i])
+		}
+	}
+
+	// A goodbye before the second failure does not follow the failures
+	early := &Stats{ConnectionSessions: []ConnectionSession{{
+		StatusUpdates: []StatusUpdate{failed(1, 1), failed(3, 2)},
+		GoodbyeEvents: []GoodbyeEvent{{Timestamp: start.Add(2 * time.Second)}},
+	}}}
+
+	if got := early.IdentifyAttempts(); got.FailedThenGoodbye || got.AttemptsToIdentify != 0 {
+		t.Errorf("Expected no goodbye after the failures and no identification, got %+v", got)
+	}
+}
@@ -14,6 +14,7 @@ type Repository interface {
 	GetAllPeers() map[string]*Stats
 	GetPeerEventCounts() map[string]map[string]int
 	IncrementEventCount(peerID, eventType string)
+	Restore(peers map[string]*Stats, eventCounts map[string]map[string]int)
 	GetMutex() *sync.RWMutex
 	GetEventMutex() *sync.RWMutex
 }
@@ -32,6 +33,8 @@ type SessionManager interface {
 // StatsCalculator defines the interface for calculating peer statistics.
 type StatsCalculator interface {
 	CalculateConnectionStats(peers map[string]*Stats) ConnectionStats
+	CalculateConnectionStatsInWindow(peers map[string]*Stats, phases *RunPhases) ConnectionStats
+	CalculateReconciledHandshakes(peers map[string]*Stats, retryWindow time.Duration) ReconciledHandshakeStats
 	CalculateClientDistribution(peers map[string]*Stats) map[string]int
 	CalculateDurationStats(peers map[string]*Stats) DurationStats
 }
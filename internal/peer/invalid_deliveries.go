@@ -0,0 +1,148 @@
+package peer
+
+import (
+	"sort"
+	"time"
+)
+
+// InvalidDeliveryPeer is a peer whose topic score counted invalid message deliveries.
+type InvalidDeliveryPeer struct {
+	PeerID        string    `json:"peer_id"`
+	ClientType    string    `json:"client_type"`
+	MaxDeliveries float64   `json:"max_deliveries"` // Highest InvalidMessageDeliveries in any snapshot
+	FirstSeenAt   time.Time `json:"first_seen_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+}
+
+// InvalidDeliveryAnomaly is a topic on which several peers' scores count invalid message
+// deliveries. One misbehaving peer is routine, many on the same topic usually point at Hermes
+// propagating or misjudging invalid messages rather than at the peers.
+type InvalidDeliveryAnomaly struct {
+	Topic          string                `json:"topic"`
+	FirstSeenAt    time.Time             `json:"first_seen_at"`
+	LastSeenAt     time.Time             `json:"last_seen_at"`
+	MaxDeliveries  float64               `json:"max_deliveries"`
+	EstimatedPeers float64               `json:"estimated_peers"` // Peers weighted by their sampling weight, what Peers stands for
+	Peers          []InvalidDeliveryPeer `json:"peers"`           // Most deliveries first
+}
+
+// Sampled reports whether the anomaly's peers stand for more peers than were captured.
+func (a InvalidDeliveryAnomaly) Sampled() bool {
+	return a.EstimatedPeers != float64(len(a.Peers))
+}
+
+// InvalidDeliveries lists the topics whose invalid message deliveries span at least MinPeers
+// peers. Only score snapshots of peers whose detail is captured are checked, each peer counting
+// for its sampling weight.
+type InvalidDeliveries struct {
+	MinPeers       int                      `json:"min_peers"`
+	AffectedTopics int                      `json:"affected_topics"` // Topics with invalid deliveries from any peer
+	Anomalies      []InvalidDeliveryAnomaly `json:"anomalies"`       // Most peers first
+}
+
+// DetectInvalidDeliveries finds the topics where at least minPeers peers, by their sampling
+// weight, show nonzero invalid message deliveries in their score snapshots.
+func DetectInvalidDeliveries(peers map[string]*Stats, minPeers int) *InvalidDeliveries {
+	byTopic := make(map[string]map[string]*InvalidDeliveryPeer)
+	weights := make(map[string]float64, len(peers))
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		weights[peerID] = stats.DetailWeight()
+
+		for _, session := range stats.ConnectionSessions {
+			for _, snapshot := range session.PeerScores {
+				topics, err := snapshot.TopicScores()
+				if err != nil {
+					continue
+				}
+
+				for _, topic := range topics {
+					if topic.InvalidMessageDeliveries <= 0 {
+						continue
+					}
+
+					if byTopic[topic.Topic] == nil {
+						byTopic[topic.Topic] = make(map[string]*InvalidDeliveryPeer)
+					}
+
+					offender := byTopic[topic.Topic][peerID]
+					if offender == nil {
+						offender = &InvalidDeliveryPeer{
+							PeerID:      peerID,
+							ClientType:  stats.ClientType,
+							FirstSeenAt: snapshot.Timestamp,
+							LastSeenAt:  snapshot.Timestamp,
+						}
+						byTopic[topic.Topic][peerID] = offender
+					}
+
+					offender.MaxDeliveries = max(offender.MaxDeliveries, topic.InvalidMessageDeliveries)
+
+					if snapshot.Timestamp.Before(offender.FirstSeenAt) {
+						offender.FirstSeenAt = snapshot.Timestamp
+					}
+
+					if snapshot.Timestamp.After(offender.LastSeenAt) {
+						offender.LastSeenAt = snapshot.Timestamp
+					}
+				}
+			}
+		}
+	}
+
+	result := &InvalidDeliveries{
+		MinPeers:       minPeers,
+		AffectedTopics: len(byTopic),
+		Anomalies:      make([]InvalidDeliveryAnomaly, 0),
+	}
+
+	for topic, offenders := range byTopic {
+		estimated := 0.0
+		for peerID := range offenders {
+			estimated += weights[peerID]
+		}
+
+		if estimated < float64(minPeers) {
+			continue
+		}
+
+		anomaly := InvalidDeliveryAnomaly{Topic: topic, EstimatedPeers: estimated, Peers: make([]InvalidDeliveryPeer, 0, len(offenders))}
+
+		for _, offender := range offenders {
+			if anomaly.FirstSeenAt.IsZero() || offender.FirstSeenAt.Before(anomaly.FirstSeenAt) {
+				anomaly.FirstSeenAt = offender.FirstSeenAt
+			}
+
+			if offender.LastSeenAt.After(anomaly.LastSeenAt) {
+				anomaly.LastSeenAt = offender.LastSeenAt
+			}
+
+			anomaly.MaxDeliveries = max(anomaly.MaxDeliveries, offender.MaxDeliveries)
+			anomaly.Peers = append(anomaly.Peers, *offender)
+		}
+
+		sort.Slice(anomaly.Peers, func(i, j int) bool {
+			if anomaly.Peers[i].MaxDeliveries != anomaly.Peers[j].MaxDeliveries {
+				return anomaly.Peers[i].MaxDeliveries > anomaly.Peers[j].MaxDeliveries
+			}
+
+			return anomaly.Peers[i].PeerID < anomaly.Peers[j].PeerID
+		})
+
+		result.Anomalies = append(result.Anomalies, anomaly)
+	}
+
+	sort.Slice(result.Anomalies, func(i, j int) bool {
+		if result.Anomalies[i].EstimatedPeers != result.Anomalies[j].EstimatedPeers {
+			return result.Anomalies[i].EstimatedPeers > result.Anomalies[j].EstimatedPeers
+		}
+
+		return result.Anomalies[i].Topic < result.Anomalies[j].Topic
+	})
+
+	return result
+}
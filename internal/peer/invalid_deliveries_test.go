@@ -0,0 +1,102 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectInvalidDeliveries(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	snapshot := func(seconds int, topic string, invalid float64) PeerScoreSnapshot {
+		return PeerScoreSnapshot{
+			Timestamp: start.Add(time.Duration(seconds) * time.Second),
+			Topics:    []TopicScore{{Topic: topic, InvalidMessageDeliveries: invalid}},
+		}
+	}
+
+	scored := func(client string, snapshots ...PeerScoreSnapshot) *Stats {
+		return &Stats{ClientType: client, ConnectionSessions: []ConnectionSession{{PeerScores: snapshots}}}
+	}
+
+	peers := map[string]*Stats{
+		"a": scored("lighthouse", snapshot(10, "beacon_block", 2), snapshot(20, "beacon_block", 5), snapshot(30, "beacon_block", 0)),
+		"b": scored("prysm", snapshot(40, "beacon_block", 1)),
+		"c": scored("teku", snapshot(50, "voluntary_exit", 3), snapshot(15, "beacon_block", 0)),
+	}
+
+	tests := []struct {
+		name      string
+		minPeers  int
+		anomalies []string
+	}{
+		{name: "several peers", minPeers: 2, anomalies: []string{"beacon_block"}},
+		{name: "single peer", minPeers: 1, anomalies: []string{"beacon_block", "voluntary_exit"}},
+		{name: "too few peers", minPeers: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DetectInvalidDeliveries(peers, tt.minPeers)
+
+			if result.AffectedTopics != 2 {
+				t.Errorf("Expected 2 affected topics, got %d", result.AffectedTopics)
+			}
+
+			if len(result.Anomalies) != len(tt.anomalies) {
+				t.Fatalf("Expected anomalies %v, got %+v", tt.anomalies, result.Anomalies)
+			}
+
+			for i, topic := range tt.anomalies {
+				if result.Anomalies[i].Topic != topic {
+					t.Errorf("Anomaly %d: got topic %s, want %s", i, result.Anomalies[i].Topic, topic)
+				}
+			}
+		})
+	}
+
+	anomaly := DetectInvalidDeliveries(peers, 2).Anomalies[0]
+
+	if !anomaly.FirstSeenAt.Equal(start.Add(10*time.Second)) || !anomaly.LastSeenAt.Equal(start.Add(40*time.Second)) || anomaly.MaxDeliveries != 5 {
+		t.Errorf("Unexpected anomaly window %+v", anomaly)
+	}
+
+	want := []InvalidDeliveryPeer{
+		{PeerID: "a", ClientType: "lighthouse", MaxDeliveries: 5, FirstSeenAt: start.Add(10 * time.Second), LastSeenAt: start.Add(20 * time.Second)},
+		{PeerID: "b", ClientType: "prysm", MaxDeliveries: 1, FirstSeenAt: start.Add(40 * time.Second), LastSeenAt: start.Add(40 * time.Second)},
+	}
+
+	if len(anomaly.Peers) != len(want) {
+		t.Fatalf("Expected %d peers, got %+v", len(want), anomaly.Peers)
+	}
+
+	for i := range want {
+		if anomaly.Peers[i] != want[i] {
+			t.Errorf("Peer %d: got %+v, want %+v", i, anomaly.Peers[i], want[i])
+		}
+	}
+}
+
+func TestDetectInvalidDeliveriesWeightsSampledPeers(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// One baseline-sampled peer stands for four
+	peers := map[string]*Stats{
+		"a": {
+			Sample: &DetailSample{Captured: true, Reason: SampleBaseline, Weight: 4},
+			ConnectionSessions: []ConnectionSession{{PeerScores: []PeerScoreSnapshot{
+				{Timestamp: at, Topics: []TopicScore{{Topic: "beacon_block", InvalidMessageDeliveries: 1}}},
+			}}},
+		},
+	}
+
+	result := DetectInvalidDeliveries(peers, 3)
+
+	if len(result.Anomalies) != 1 {
+		t.Fatalf("Expected 1 anomaly, got %+v", result.Anomalies)
+	}
+
+	if result.Anomalies[0].EstimatedPeers != 4 || len(result.Anomalies[0].Peers) != 1 {
+		t.Errorf("Expected 4 estimated peers from 1 sampled, got %+v", result.Anomalies[0])
+	}
+}
@@ -0,0 +1,70 @@
+package peer
+
+import "time"
+
+// AssignEvent returns the session an event stamped at belongs to, and whether it arrived
+// after that session's disconnect. Events without an active session belong to the session
+// that ended last when they arrive within grace of its disconnect: final scores and
+// goodbyes often trail the DISCONNECTED trace. Later events are dropped, counted by type,
+// and nil is returned. A peer that never had a session gets one opened at the event.
+func (s *Stats) AssignEvent(eventType string, at time.Time, grace time.Duration) (*ConnectionSession, bool) {
+	for i := len(s.ConnectionSessions) - 1; i >= 0; i-- {
+		if !s.ConnectionSessions[i].Disconnected {
+			return &s.ConnectionSessions[i], false
+		}
+	}
+
+	if len(s.ConnectionSessions) == 0 {
+		s.ConnectionSessions = append(s.ConnectionSessions, ConnectionSession{
+			ConnectedAt:   &at,
+			PeerScores:    []PeerScoreSnapshot{},
+			GoodbyeEvents: []GoodbyeEvent{},
+			MeshEvents:    []MeshEvent{},
+		})
+
+		return &s.ConnectionSessions[0], false
+	}
+
+	last := &s.ConnectionSessions[len(s.ConnectionSessions)-1]
+
+	// Stamped before the disconnect, the event was only processed late
+	if last.DisconnectedAt != nil && !at.After(*last.DisconnectedAt) {
+		return last, false
+	}
+
+	if last.DisconnectedAt != nil && !at.After(last.DisconnectedAt.Add(grace)) {
+		last.LateEvents++
+
+		return last, true
+	}
+
+	if s.DroppedLateEvents == nil {
+		s.DroppedLateEvents = make(map[string]int)
+	}
+
+	s.DroppedLateEvents[eventType]++
+
+	return nil, false
+}
+
+// CountLateEvents adds the events that arrived after their peer disconnected to the data
+// quality statistics.
+func CountLateEvents(peers map[string]*Stats, grace time.Duration, stats *DataQualityStats) {
+	stats.LateEventGraceSeconds = grace.Seconds()
+	stats.LateEventsDroppedByType = make(map[string]int)
+
+	for _, peerStats := range peers {
+		if peerStats == nil {
+			continue
+		}
+
+		for _, session := range peerStats.ConnectionSessions {
+			stats.LateEventsAssigned += session.LateEvents
+		}
+
+		for eventType, count := range peerStats.DroppedLateEvents {
+			stats.LateEventsDropped += count
+			stats.LateEventsDroppedByType[eventType] += count
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssignEvent(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	disconnectedAt := start.Add(time.Minute)
+	grace := 10 * time.Second
+
+	ended := func() ConnectionSession {
+		connected, disconnected := start, disconnectedAt
+
+		return ConnectionSession{ConnectedAt: &connected, DisconnectedAt: &disconnected, Disconnected: true}
+	}
+
+	tests := []struct {
+		name         string
+		sessions     []ConnectionSession
+		at           time.Time
+		wantSession  int // Index of the assigned session, -1 when dropped
+		wantPost     bool
+		wantSessions int
+	}{
+		{
+			name:         "active session",
+			sessions:     []ConnectionSession{ended(), {ConnectedAt: &disconnectedAt}},
+			at:           disconnectedAt.Add(time.Hour),
+			wantSession:  1,
+			wantSessions: 2,
+		},
+		{
+			name:         "first event opens a session",
+			at:           start,
+			wantSession:  0,
+			wantSessions: 1,
+		},
+		{
+			name:         "processed late but stamped before the disconnect",
+			sessions:     []ConnectionSession{ended()},
+			at:           disconnectedAt.Add(-time.Second),
+			wantSession:  0,
+			wantSessions: 1,
+		},
+		{
+			name:         "within the grace window",
+			sessions:     []ConnectionSession{ended()},
+			at:           disconnectedAt.Add(grace),
+			wantSession:  0,
+			wantPost:     true,
+			wantSessions: 1,
+		},
+		{
+			name:         "beyond the grace window",
+			sessions:     []ConnectionSession{ended()},
+			at:           disconnectedAt.Add(grace + time.Second),
+			wantSession:  -1,
+			wantSessions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &Stats{ConnectionSessions: tt.sessions}
+
+			session, post := stats.AssignEvent("PEERSCORE", tt.at, grace)
+
+			if len(stats.ConnectionSessions) != tt.wantSessions {
+				t.Fatalf("expected %d sessions, got %d", tt.wantSessions, len(stats.ConnectionSessions))
+			}
+
+			if tt.wantSession < 0 {
+				if session != nil || stats.DroppedLateEvents["PEERSCORE"] != 1 {
+					t.Errorf("expected the event to be dropped and counted, got %+v, %v", session, stats.DroppedLateEvents)
+				}
+
+				return
+			}
+
+			if session != &stats.ConnectionSessions[tt.wantSession] || post != tt.wantPost {
+				t.Errorf("expected session %d with post disconnect %v, got %+v, %v", tt.wantSession, tt.wantPost, session, post)
+			}
+
+			if tt.wantPost && session.LateEvents != 1 {
+				t.Errorf("expected the late event to be counted on the session, got %d", session.LateEvents)
+			}
+		})
+	}
+}
+
+func TestCountLateEvents(t *testing.T) {
+	peers := map[string]*Stats{
+		"a": {
+			ConnectionSessions: []ConnectionSession{{LateEvents: 2}, {LateEvents: 1}},
+			DroppedLateEvents:  map[string]int{"PEERSCORE": 4},
+		},
+		"b": {DroppedLateEvents: map[string]int{"PEERSCORE": 1, "GRAFT": 2}},
+		"c": nil,
+	}
+
+	var stats DataQualityStats
+
+	CountLateEvents(peers, 10*time.Second, &stats)
+
+	if stats.LateEventGraceSeconds != 10 || stats.LateEventsAssigned != 3 || stats.LateEventsDropped != 7 {
+		t.Errorf("unexpected late event counts: %+v", stats)
+	}
+
+	if stats.LateEventsDroppedByType["PEERSCORE"] != 5 || stats.LateEventsDroppedByType["GRAFT"] != 2 {
+		t.Errorf("unexpected dropped late events by type: %v", stats.LateEventsDroppedByType)
+	}
+}
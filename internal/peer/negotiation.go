@@ -0,0 +1,216 @@
+package peer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Connection directions of negotiation failures.
+const (
+	NegotiationInbound  = "inbound"
+	NegotiationOutbound = "outbound"
+)
+
+// Stages of connection establishment a negotiation failure is attributed to.
+const (
+	NegotiationTransport = "transport" // The dial or accept itself: refused, unreachable, reset
+	NegotiationSecurity  = "security"  // Security protocol selection or the Noise handshake
+	NegotiationMuxer     = "muxer"     // Stream multiplexer selection
+	NegotiationTimeout   = "timeout"   // The upgrade did not finish in time
+	NegotiationRejected  = "rejected"  // Our connection gater or resource manager refused the connection
+	NegotiationOther     = "other"
+)
+
+// ClassifyNegotiationError maps a libp2p dial or upgrade error to the stage it failed at, and
+// reports whether the peer offered no protocol we support at that stage, as an outdated or
+// downgraded peer would. Errors that say nothing about the peer, such as dial backoff, missing
+// addresses and cancelled dials, are not counted.
+func ClassifyNegotiationError(message string) (stage string, unsupported, counted bool) {
+	lower := strings.ToLower(message)
+
+	switch {
+	case strings.Contains(lower, "dial backoff"), strings.Contains(lower, "no addresses"),
+		strings.Contains(lower, "context canceled"), strings.Contains(lower, "dial to self"):
+		return "", false, false
+	}
+
+	unsupported = strings.Contains(lower, "protocols not supported") || strings.Contains(lower, "protocol not supported")
+
+	switch {
+	case strings.Contains(lower, "gater rejected"), strings.Contains(lower, "resource manager"),
+		strings.Contains(lower, "resource limit exceeded"):
+		stage = NegotiationRejected
+	case strings.Contains(lower, "negotiate stream multiplexer"), strings.Contains(lower, "muxer"):
+		stage = NegotiationMuxer
+	case strings.Contains(lower, "negotiate security protocol"), strings.Contains(lower, "noise"),
+		strings.Contains(lower, "peer id mismatch"), strings.Contains(lower, "unexpected peer"):
+		stage = NegotiationSecurity
+	case strings.Contains(lower, "timeout"), strings.Contains(lower, "deadline exceeded"):
+		stage = NegotiationTimeout
+	case strings.Contains(lower, "connection refused"), strings.Contains(lower, "connection reset"),
+		strings.Contains(lower, "unreachable"), strings.Contains(lower, "no route to host"),
+		strings.Contains(lower, "failed to dial"):
+		stage = NegotiationTransport
+	default:
+		stage = NegotiationOther
+	}
+
+	return stage, unsupported, true
+}
+
+// NegotiationRemote counts the failures of one remote: an IP address for inbound connections,
+// whose peer ID is not known before the security handshake, or a peer ID for outbound dials.
+type NegotiationRemote struct {
+	Remote   string `json:"remote"`
+	Failures int    `json:"failures"`
+}
+
+// NegotiationCounts are the negotiation failures of one direction.
+type NegotiationCounts struct {
+	Direction   string              `json:"direction"`
+	Failures    int                 `json:"failures"`
+	Unsupported int                 `json:"unsupported"` // Peer offered no security or muxer protocol we support
+	ByStage     map[string]int      `json:"by_stage"`
+	Examples    map[string]string   `json:"examples"` // First error seen per stage
+	Remotes     int                 `json:"remotes"`  // Distinct remotes that failed
+	TopRemotes  []NegotiationRemote `json:"top_remotes"`
+}
+
+// NegotiationFailures are the connections that failed before a CONNECTED event could fire,
+// which are otherwise invisible to the peer statistics.
+type NegotiationFailures struct {
+	Inbound  *NegotiationCounts `json:"inbound,omitempty"`
+	Outbound *NegotiationCounts `json:"outbound,omitempty"`
+}
+
+// Directions returns the counts of the directions that saw failures, inbound first.
+func (f *NegotiationFailures) Directions() []*NegotiationCounts {
+	directions := make([]*NegotiationCounts, 0, 2)
+
+	for _, counts := range []*NegotiationCounts{f.Inbound, f.Outbound} {
+		if counts != nil {
+			directions = append(directions, counts)
+		}
+	}
+
+	return directions
+}
+
+// negotiationTally accumulates the failures of one direction.
+type negotiationTally struct {
+	failures    int
+	unsupported int
+	byStage     map[string]int
+	examples    map[string]string
+	remotes     map[string]int
+}
+
+// NegotiationRecorder counts connection negotiation failures. It is safe for concurrent use.
+type NegotiationRecorder struct {
+	mu         sync.Mutex
+	directions map[string]*negotiationTally
+}
+
+// NewNegotiationRecorder creates an empty negotiation failure recorder.
+func NewNegotiationRecorder() *NegotiationRecorder {
+	return &NegotiationRecorder{directions: make(map[string]*negotiationTally)}
+}
+
+// Record classifies and counts a failed connection, returning whether it was counted.
+func (r *NegotiationRecorder) Record(direction, remote, message string) bool {
+	stage, unsupported, counted := ClassifyNegotiationError(message)
+	if !counted {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tally, ok := r.directions[direction]
+	if !ok {
+		tally = &negotiationTally{
+			byStage:  make(map[string]int),
+			examples: make(map[string]string),
+			remotes:  make(map[string]int),
+		}
+		r.directions[direction] = tally
+	}
+
+	tally.failures++
+	tally.byStage[stage]++
+
+	if unsupported {
+		tally.unsupported++
+	}
+
+	if _, seen := tally.examples[stage]; !seen {
+		tally.examples[stage] = message
+	}
+
+	if remote != "" {
+		tally.remotes[remote]++
+	}
+
+	return true
+}
+
+// Snapshot returns the failures counted so far, or nil when there were none.
+func (r *NegotiationRecorder) Snapshot() *NegotiationFailures {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.directions) == 0 {
+		return nil
+	}
+
+	return &NegotiationFailures{
+		Inbound:  r.directions[NegotiationInbound].counts(NegotiationInbound),
+		Outbound: r.directions[NegotiationOutbound].counts(NegotiationOutbound),
+	}
+}
+
+// counts copies the tally, keeping the remotes with the most failures.
+func (t *negotiationTally) counts(direction string) *NegotiationCounts {
+	if t == nil {
+		return nil
+	}
+
+	counts := &NegotiationCounts{
+		Direction:   direction,
+		Failures:    t.failures,
+		Unsupported: t.unsupported,
+		ByStage:     make(map[string]int, len(t.byStage)),
+		Examples:    make(map[string]string, len(t.examples)),
+		Remotes:     len(t.remotes),
+		TopRemotes:  make([]NegotiationRemote, 0, len(t.remotes)),
+	}
+
+	for stage, count := range t.byStage {
+		counts.ByStage[stage] = count
+	}
+
+	for stage, example := range t.examples {
+		counts.Examples[stage] = example
+	}
+
+	for remote, failures := range t.remotes {
+		counts.TopRemotes = append(counts.TopRemotes, NegotiationRemote{Remote: remote, Failures: failures})
+	}
+
+	sort.Slice(counts.TopRemotes, func(i, j int) bool {
+		if counts.TopRemotes[i].Failures != counts.TopRemotes[j].Failures {
+			return counts.TopRemotes[i].Failures > counts.TopRemotes[j].Failures
+		}
+
+		return counts.TopRemotes[i].Remote < counts.TopRemotes[j].Remote
+	})
+
+	if len(counts.TopRemotes) > constants.NegotiationRemoteLimit {
+		counts.TopRemotes = counts.TopRemotes[:constants.NegotiationRemoteLimit]
+	}
+
+	return counts
+}
@@ -0,0 +1,84 @@
+package peer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClassifyNegotiationError(t *testing.T) {
+	tests := []struct {
+		message     string
+		stage       string
+		unsupported bool
+		counted     bool
+	}{
+		{"failed to negotiate security protocol: protocols not supported: [/noise]", NegotiationSecurity, true, true},
+		{"failed to negotiate stream multiplexer: protocols not supported: [/yamux/1.0.0 /mplex/6.7.0]", NegotiationMuxer, true, true},
+		{"failed to negotiate security protocol: read: connection reset by peer", NegotiationSecurity, false, true},
+		{"failed to dial: dial tcp4 1.2.3.4:9000: connect: connection refused", NegotiationTransport, false, true},
+		{"failed to dial: context deadline exceeded", NegotiationTimeout, false, true},
+		{"gater rejected connection with peer", NegotiationRejected, false, true},
+		{"something odd", NegotiationOther, false, true},
+		{"failed to dial: dial backoff", "", false, false},
+		{"failed to dial: context canceled", "", false, false},
+	}
+
+	for _, tt := range tests {
+		stage, unsupported, counted := ClassifyNegotiationError(tt.message)
+		if stage != tt.stage || unsupported != tt.unsupported || counted != tt.counted {
+			t.Errorf("%q: got (%q, %v, %v), want (%q, %v, %v)",
+				tt.message, stage, unsupported, counted, tt.stage, tt.unsupported, tt.counted)
+		}
+	}
+}
+
+func TestNegotiationRecorder(t *testing.T) {
+	recorder := NewNegotiationRecorder()
+
+	if recorder.Snapshot() != nil {
+		t.Fatal("Expected no snapshot before any failure")
+	}
+
+	if recorder.Record(NegotiationOutbound, "peer-a", "failed to dial: dial backoff") {
+		t.Error("Expected dial backoff not to be counted")
+	}
+
+	recorder.Record(NegotiationInbound, "/ip4/1.2.3.4", "failed to negotiate security protocol: protocols not supported: [/noise]")
+	recorder.Record(NegotiationInbound, "/ip4/1.2.3.4", "failed to negotiate security protocol: EOF")
+	recorder.Record(NegotiationInbound, "/ip4/5.6.7.8", "failed to negotiate stream multiplexer: protocols not supported: [/yamux/1.0.0]")
+
+	for i := 0; i < 12; i++ {
+		recorder.Record(NegotiationOutbound, fmt.Sprintf("peer-%02d", i), "failed to dial: connection refused")
+	}
+
+	snapshot := recorder.Snapshot()
+	if snapshot == nil || snapshot.Inbound == nil || snapshot.Outbound == nil {
+		t.Fatalf("Expected both directions, got %+v", snapshot)
+	}
+
+	inbound := snapshot.Inbound
+	if inbound.Failures != 3 || inbound.Unsupported != 2 || inbound.Remotes != 2 {
+		t.Errorf("Unexpected inbound counts %+v", inbound)
+	}
+
+	if inbound.ByStage[NegotiationSecurity] != 2 || inbound.ByStage[NegotiationMuxer] != 1 {
+		t.Errorf("Unexpected inbound stages %v", inbound.ByStage)
+	}
+
+	if inbound.Examples[NegotiationSecurity] != "failed to negotiate security protocol: protocols not supported: [/noise]" {
+		t.Errorf("Expected the first security error as example, got %q", inbound.Examples[NegotiationSecurity])
+	}
+
+	if inbound.TopRemotes[0] != (NegotiationRemote{Remote: "/ip4/1.2.3.4", Failures: 2}) {
+		t.Errorf("Expected the remote with most failures first, got %+v", inbound.TopRemotes)
+	}
+
+	outbound := snapshot.Outbound
+	if outbound.Failures != 12 || outbound.Remotes != 12 || len(outbound.TopRemotes) != 10 {
+		t.Errorf("Expected 12 outbound failures with the top 10 remotes, got %+v", outbound)
+	}
+
+	if directions := snapshot.Directions(); len(directions) != 2 || directions[0].Direction != NegotiationInbound {
+		t.Errorf("Expected inbound then outbound, got %+v", directions)
+	}
+}
@@ -0,0 +1,133 @@
+package peer
+
+import (
+	"sort"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Peer origins, how a peer came to be connected to us. Static peers, boot nodes and our own
+// canaries are known by peer ID, the others are told apart by who opened their first session.
+const (
+	OriginStatic   = "static"
+	OriginBootnode = "bootnode"
+	OriginDiscv5   = "discv5"
+	OriginIncoming = "incoming"
+	OriginCanary   = "canary"
+)
+
+// OriginStats summarises session stability for the peers of one origin.
+type OriginStats struct {
+	Origin                string  `json:"origin"`
+	Peers                 int     `json:"peers"`
+	Sessions              int     `json:"sessions"`
+	Disconnected          int     `json:"disconnected"`
+	ShortLived            int     `json:"short_lived"`             // Disconnected within the short session threshold
+	WithGoodbye           int     `json:"with_goodbye"`            // Sessions the peer sent a goodbye in
+	MedianDurationSeconds float64 `json:"median_duration_seconds"` // Of disconnected sessions
+}
+
+// TagOrigins sets each peer's origin. Peers in known, keyed by peer ID, take the origin given
+// there. The others are incoming when they opened their first session, and otherwise were
+// dialed by Hermes, which only dials the peers discv5 finds.
+func TagOrigins(peers map[string]*Stats, known map[string]string) {
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		if origin, ok := known[peerID]; ok {
+			stats.Origin = origin
+
+			continue
+		}
+
+		stats.Origin = OriginDiscv5
+		if len(stats.ConnectionSessions) > 0 && stats.ConnectionSessions[0].Direction == DirectionInbound {
+			stats.Origin = OriginIncoming
+		}
+	}
+}
+
+// GeneralPeers returns the peers whose behaviour counts towards churn statistics, leaving out
+// static peers, boot nodes and canaries. Boot nodes serve discovery and churn by design, static
+// peers are kept connected and canaries are our own, so none says how the network treats us.
+func GeneralPeers(peers map[string]*Stats) map[string]*Stats {
+	general := make(map[string]*Stats, len(peers))
+
+	for peerID, stats := range peers {
+		if stats != nil && (stats.Origin == OriginStatic || stats.Origin == OriginBootnode || stats.Origin == OriginCanary) {
+			continue
+		}
+
+		general[peerID] = stats
+	}
+
+	return general
+}
+
+// OriginBreakdown summarises session stability per peer origin, largest origin first.
+// Peers recorded before origins were tagged are left out.
+func OriginBreakdown(peers map[string]*Stats) []OriginStats {
+	byOrigin := make(map[string]*OriginStats)
+	durations := make(map[string][]float64)
+
+	for _, peer := range peers {
+		if peer == nil || peer.Origin == "" {
+			continue
+		}
+
+		stats, ok := byOrigin[peer.Origin]
+		if !ok {
+			stats = &OriginStats{Origin: peer.Origin}
+			byOrigin[peer.Origin] = stats
+		}
+
+		stats.Peers++
+
+		for _, session := range peer.ConnectionSessions {
+			stats.Sessions++
+
+			if len(churnGoodbyes(&session)) > 0 {
+				stats.WithGoodbye++
+			}
+
+			if !churned(&session) {
+				continue
+			}
+
+			stats.Disconnected++
+
+			if duration, ok := completedDuration(session); ok {
+				durations[peer.Origin] = append(durations[peer.Origin], duration.Seconds())
+
+				if duration < constants.ShortSessionDuration {
+					stats.ShortLived++
+				}
+			}
+		}
+	}
+
+	breakdown := make([]OriginStats, 0, len(byOrigin))
+
+	for origin, stats := range byOrigin {
+		stats.MedianDurationSeconds = median(durations[origin])
+		breakdown = append(breakdown, *stats)
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Peers != breakdown[j].Peers {
+			return breakdown[i].Peers > breakdown[j].Peers
+		}
+
+		return breakdown[i].Origin < breakdown[j].Origin
+	})
+
+	return breakdown
+}
+
+// OriginBreakdownFromInterface summarises session stability per peer origin on generic peer
+// data, as loaded from a JSON report in HTML-only mode.
+func OriginBreakdownFromInterface(peers map[string]interface{}) []OriginStats {
+	return OriginBreakdown(statsFromInterface(peers))
+}
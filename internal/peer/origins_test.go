@@ -0,0 +1,88 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrigins(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	peers := map[string]*Stats{
+		"boot": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(10), Disconnected: true, Direction: DirectionOutbound},
+			{ConnectedAt: at(20), DisconnectedAt: at(25), Disconnected: true, Direction: DirectionOutbound},
+		}},
+		"static": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), Direction: DirectionInbound},
+		}},
+		"dialed": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(600), Disconnected: true, Direction: DirectionOutbound,
+				GoodbyeEvents: []GoodbyeEvent{{Code: 3}}},
+		}},
+		"incoming": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(0), Direction: DirectionInbound},
+		}},
+		"no-sessions": {},
+	}
+
+	TagOrigins(peers, map[string]string{"boot": OriginBootnode, "static": OriginStatic})
+
+	want := map[string]string{
+		"boot":        OriginBootnode,
+		"static":      OriginStatic,
+		"dialed":      OriginDiscv5,
+		"incoming":    OriginIncoming,
+		"no-sessions": OriginDiscv5,
+	}
+
+	for peerID, origin := range want {
+		if got := peers[peerID].Origin; got != origin {
+			t.Errorf("Expected %s to be tagged %s, got %s", peerID, origin, got)
+		}
+	}
+
+	general := GeneralPeers(peers)
+	if len(general) != 3 {
+		t.Fatalf("Expected 3 general peers, got %d", len(general))
+	}
+
+	if _, ok := general["boot"]; ok {
+		t.Error("Expected boot nodes to be left out of the general peers")
+	}
+
+	if _, ok := general["static"]; ok {
+		t.Error("Expected static peers to be left out of the general peers")
+	}
+
+	breakdown := OriginBreakdown(peers)
+	if len(breakdown) != 4 {
+		t.Fatalf("Expected 4 origins, got %d", len(breakdown))
+	}
+
+	// discv5 has the most peers, the single peer origins follow by name
+	if breakdown[0].Origin != OriginDiscv5 || breakdown[1].Origin != OriginBootnode {
+		t.Fatalf("Unexpected order: %+v", breakdown)
+	}
+
+	boot := breakdown[1]
+	if boot.Sessions != 2 || boot.Disconnected != 2 || boot.ShortLived != 2 || boot.MedianDurationSeconds != 7.5 {
+		t.Errorf("Unexpected boot node stats: %+v", boot)
+	}
+
+	if discv5 := breakdown[0]; discv5.Peers != 2 || discv5.WithGoodbye != 1 || discv5.ShortLived != 0 || discv5.MedianDurationSeconds != 600 {
+		t.Errorf("Unexpected discv5 stats: %+v", discv5)
+	}
+
+	// Canaries are tagged by the run that staged them, after the other origins
+	peers["incoming"].Origin = OriginCanary
+	if _, ok := GeneralPeers(peers)["incoming"]; ok {
+		t.Error("Expected canaries to be left out of the general peers")
+	}
+}
@@ -0,0 +1,184 @@
+package peer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// PreviousRun is the peer set of an earlier run, as read from its JSON report.
+type PreviousRun struct {
+	File      string
+	StartTime time.Time
+	Peers     map[string]string // Peer ID -> client type, static peers and boot nodes left out
+}
+
+// previousReport is the part of a JSON report the peer overlap needs.
+type previousReport struct {
+	StartTime time.Time `json:"start_time"`
+	Peers     map[string]struct {
+		ClientType string `json:"client_type"`
+		Origin     string `json:"origin"`
+	} `json:"peers"`
+}
+
+// LoadPreviousRun reads the peer set of an earlier run from its JSON report.
+func LoadPreviousRun(path string) (*PreviousRun, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous report: %w", err)
+	}
+
+	var report previousReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse previous report: %w", err)
+	}
+
+	run := &PreviousRun{File: path, StartTime: report.StartTime, Peers: make(map[string]string, len(report.Peers))}
+
+	for peerID, stats := range report.Peers {
+		if stats.Origin == OriginStatic || stats.Origin == OriginBootnode {
+			continue
+		}
+
+		run.Peers[peerID] = stats.ClientType
+	}
+
+	return run, nil
+}
+
+// RunOverlap compares this run's peer set with one earlier run's.
+type RunOverlap struct {
+	File          string    `json:"file"`
+	StartTime     time.Time `json:"start_time"`
+	PreviousPeers int       `json:"previous_peers"`
+	Returning     int       `json:"returning"` // Peers seen in both runs
+	New           int       `json:"new"`       // Peers of this run the earlier one did not see
+	Gone          int       `json:"gone"`      // Peers of the earlier run this one did not see
+	Jaccard       float64   `json:"jaccard"`   // Peers in both runs over peers in either
+}
+
+// ClientRetention counts, for one client, the peers this run shares with the earlier runs.
+type ClientRetention struct {
+	Client        string  `json:"client"`
+	Peers         int     `json:"peers"`          // Peers of this run
+	Returning     int     `json:"returning"`      // Of those, peers an earlier run saw
+	New           int     `json:"new"`            // Of those, peers no earlier run saw
+	PreviousPeers int     `json:"previous_peers"` // Peers the earlier runs saw
+	RetentionRate float64 `json:"retention_rate"` // Share of the earlier runs' peers seen again
+}
+
+// PeerOverlap sets this run's peers against the peers of earlier runs. A high overlap means the
+// same peers are cycling through our connections, a low one that the network is turning over.
+type PeerOverlap struct {
+	Peers     int               `json:"peers"`     // Peers of this run
+	Returning int               `json:"returning"` // Peers any earlier run saw
+	New       int               `json:"new"`       // Peers no earlier run saw
+	Runs      []RunOverlap      `json:"runs"`      // Newest first
+	Clients   []ClientRetention `json:"clients"`   // Largest client first
+}
+
+// CalculatePeerOverlap compares the peers of this run with those of the earlier runs. A peer's
+// client is the one this run saw, or for peers only earlier runs saw, the newest run's.
+// Returns nil without earlier runs.
+func CalculatePeerOverlap(peers map[string]*Stats, previous []*PreviousRun) *PeerOverlap {
+	if len(previous) == 0 {
+		return nil
+	}
+
+	runs := append([]*PreviousRun(nil), previous...)
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartTime.After(runs[j].StartTime)
+	})
+
+	overlap := &PeerOverlap{Peers: len(peers), Runs: make([]RunOverlap, 0, len(runs))}
+
+	// Newest runs first, so the newest client type of a peer wins
+	seenBefore := make(map[string]string)
+
+	for _, run := range runs {
+		stats := RunOverlap{File: run.File, StartTime: run.StartTime, PreviousPeers: len(run.Peers)}
+
+		for peerID, client := range run.Peers {
+			if _, ok := peers[peerID]; ok {
+				stats.Returning++
+			}
+
+			if _, ok := seenBefore[peerID]; !ok {
+				seenBefore[peerID] = client
+			}
+		}
+
+		stats.New = len(peers) - stats.Returning
+		stats.Gone = len(run.Peers) - stats.Returning
+
+		if union := len(peers) + stats.Gone; union > 0 {
+			stats.Jaccard = float64(stats.Returning) / float64(union)
+		}
+
+		overlap.Runs = append(overlap.Runs, stats)
+	}
+
+	byClient := make(map[string]*ClientRetention)
+
+	client := func(name string) *ClientRetention {
+		if name == "" {
+			name = "unknown"
+		}
+
+		if byClient[name] == nil {
+			byClient[name] = &ClientRetention{Client: name}
+		}
+
+		return byClient[name]
+	}
+
+	for peerID, stats := range peers {
+		var clientType string
+		if stats != nil {
+			clientType = stats.ClientType
+		}
+
+		retention := client(clientType)
+		retention.Peers++
+
+		if _, ok := seenBefore[peerID]; ok {
+			retention.Returning++
+			overlap.Returning++
+		} else {
+			retention.New++
+			overlap.New++
+		}
+	}
+
+	// Earlier peers count towards the client this run saw them as, if it saw them
+	for peerID, clientType := range seenBefore {
+		if stats, ok := peers[peerID]; ok && stats != nil {
+			clientType = stats.ClientType
+		}
+
+		client(clientType).PreviousPeers++
+	}
+
+	overlap.Clients = make([]ClientRetention, 0, len(byClient))
+
+	for _, retention := range byClient {
+		if retention.PreviousPeers > 0 {
+			retention.RetentionRate = float64(retention.Returning) / float64(retention.PreviousPeers)
+		}
+
+		overlap.Clients = append(overlap.Clients, *retention)
+	}
+
+	sort.Slice(overlap.Clients, func(i, j int) bool {
+		if overlap.Clients[i].Peers != overlap.Clients[j].Peers {
+			return overlap.Clients[i].Peers > overlap.Clients[j].Peers
+		}
+
+		return overlap.Clients[i].Client < overlap.Clients[j].Client
+	})
+
+	return overlap
+}
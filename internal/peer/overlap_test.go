@@ -0,0 +1,107 @@
+package peer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCalculatePeerOverlap(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	peers := map[string]*Stats{
+		"a": {ClientType: "lighthouse"},
+		"b": {ClientType: "lighthouse"},
+		"c": {ClientType: "prysm"},
+		"d": {ClientType: "teku"},
+	}
+
+	older := &PreviousRun{File: "older.json", StartTime: start.Add(-48 * time.Hour), Peers: map[string]string{
+		"a": "lighthouse",
+		"e": "nimbus",
+	}}
+	newer := &PreviousRun{File: "newer.json", StartTime: start.Add(-24 * time.Hour), Peers: map[string]string{
+		"a": "lighthouse",
+		"c": "prysm",
+		"f": "lighthouse",
+	}}
+
+	overlap := CalculatePeerOverlap(peers, []*PreviousRun{older, newer})
+	if overlap == nil {
+		t.Fatal("Expected a peer overlap")
+	}
+
+	if overlap.Peers != 4 || overlap.Returning != 2 || overlap.New != 2 {
+		t.Errorf("Expected 2 returning and 2 new of 4 peers, got %+v", overlap)
+	}
+
+	if len(overlap.Runs) != 2 || overlap.Runs[0].File != "newer.json" {
+		t.Fatalf("Expected the newer run first, got %+v", overlap.Runs)
+	}
+
+	// Newer run: a and c returned, b and d are new, f is gone; 2 shared of 5 in either
+	if run := overlap.Runs[0]; run.Returning != 2 || run.New != 2 || run.Gone != 1 || run.Jaccard != 0.4 {
+		t.Errorf("Unexpected overlap with the newer run %+v", run)
+	}
+
+	// Older run: a returned, e is gone; 1 shared of 5 in either
+	if run := overlap.Runs[1]; run.Returning != 1 || run.Gone != 1 || run.Jaccard != 0.2 {
+		t.Errorf("Unexpected overlap with the older run %+v", run)
+	}
+
+	want := map[string]ClientRetention{
+		"lighthouse": {Client: "lighthouse", Peers: 2, Returning: 1, New: 1, PreviousPeers: 2, RetentionRate: 0.5},
+		"prysm":      {Client: "prysm", Peers: 1, Returning: 1, PreviousPeers: 1, RetentionRate: 1},
+		"teku":       {Client: "teku", Peers: 1, New: 1},
+		"nimbus":     {Client: "nimbus", PreviousPeers: 1},
+	}
+
+	if len(overlap.Clients) != len(want) || overlap.Clients[0].Client != "lighthouse" {
+		t.Fatalf("Expected %d clients, largest first, got %+v", len(want), overlap.Clients)
+	}
+
+	for _, retention := range overlap.Clients {
+		if retention != want[retention.Client] {
+			t.Errorf("Client %s: got %+v, want %+v", retention.Client, retention, want[retention.Client])
+		}
+	}
+
+	if CalculatePeerOverlap(peers, nil) != nil {
+		t.Error("Expected no overlap without earlier runs")
+	}
+}
+
+func TestLoadPreviousRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peer-score-report.json")
+
+	report := `{
+		"start_time": "2025-06-01T12:00:00Z",
+		"peers": {
+			"a": {"client_type": "lighthouse", "origin": "discv5", "connection_sessions": []},
+			"boot": {"client_type": "prysm", "origin": "bootnode"},
+			"old": {"client_type": "teku"}
+		}
+	}`
+
+	if err := os.WriteFile(path, []byte(report), 0o600); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	run, err := LoadPreviousRun(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading the report, got %v", err)
+	}
+
+	if !run.StartTime.Equal(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected start time %s", run.StartTime)
+	}
+
+	if len(run.Peers) != 2 || run.Peers["a"] != "lighthouse" || run.Peers["old"] != "teku" {
+		t.Errorf("Expected the boot node to be left out, got %v", run.Peers)
+	}
+
+	if _, err := LoadPreviousRun(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing report")
+	}
+}
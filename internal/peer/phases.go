@@ -0,0 +1,116 @@
+package peer
+
+import "time"
+
+// Run phase names.
+const (
+	PhaseWarmup   = "warmup"
+	PhaseMeasure  = "measure"
+	PhaseCooldown = "cooldown"
+	PhaseShutdown = "shutdown"
+	PhaseComplete = "complete"
+)
+
+// RunPhases records the boundaries of a run's warmup, measurement and cooldown phases.
+// Headline statistics are computed only from sessions that connect inside the measurement window.
+type RunPhases struct {
+	WarmupStart  time.Time `json:"warmup_start"`
+	MeasureStart time.Time `json:"measure_start"`
+	MeasureEnd   time.Time `json:"measure_end"`
+	CooldownEnd  time.Time `json:"cooldown_end"`
+	EndedInPhase string    `json:"ended_in_phase,omitempty"`
+	Interrupted  bool      `json:"interrupted,omitempty"`
+}
+
+// NewRunPhases plans the phase boundaries for a run starting at start.
+func NewRunPhases(start time.Time, warmup, measure, cooldown time.Duration) *RunPhases {
+	measureStart := start.Add(warmup)
+	measureEnd := measureStart.Add(measure)
+
+	return &RunPhases{
+		WarmupStart:  start,
+		MeasureStart: measureStart,
+		MeasureEnd:   measureEnd,
+		CooldownEnd:  measureEnd.Add(cooldown),
+	}
+}
+
+// PhaseAt returns the phase the run is in at the given time.
+func (p *RunPhases) PhaseAt(t time.Time) string {
+	switch {
+	case t.Before(p.MeasureStart):
+		return PhaseWarmup
+	case t.Before(p.MeasureEnd):
+		return PhaseMeasure
+	case t.Before(p.CooldownEnd):
+		return PhaseCooldown
+	default:
+		return PhaseComplete
+	}
+}
+
+// Finish records when the run actually ended, clamping the measurement window to it
+// so interrupted runs only report on the time that was observed.
+func (p *RunPhases) Finish(end time.Time) {
+	p.EndedInPhase = p.PhaseAt(end)
+	p.Interrupted = end.Before(p.CooldownEnd)
+
+	if end.Before(p.MeasureEnd) {
+		p.MeasureEnd = end
+	}
+
+	if end.Before(p.MeasureStart) {
+		p.MeasureStart = end
+	}
+
+	if end.Before(p.CooldownEnd) {
+		p.CooldownEnd = end
+	}
+}
+
+// RunGap is a period the collector was down between a checkpoint and a resumed run.
+// Nothing was observed during a gap, so sessions open at the checkpoint are closed at its start.
+type RunGap struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Seconds float64   `json:"seconds"`
+}
+
+// NewRunGap creates a gap between the last checkpoint and the resumption.
+func NewRunGap(from, to time.Time) RunGap {
+	return RunGap{From: from, To: to, Seconds: to.Sub(from).Seconds()}
+}
+
+// InMeasurement reports whether t falls inside the measurement window.
+func (p *RunPhases) InMeasurement(t time.Time) bool {
+	return !t.Before(p.MeasureStart) && t.Before(p.MeasureEnd)
+}
+
+// MeasuredPeers returns a view of the peers holding only sessions that connected
+// inside the measurement window. Peers without such sessions are left out.
+func (p *RunPhases) MeasuredPeers(peers map[string]*Stats) map[string]*Stats {
+	measured := make(map[string]*Stats, len(peers))
+
+	for peerID, peer := range peers {
+		sessions := make([]ConnectionSession, 0, len(peer.ConnectionSessions))
+
+		for _, session := range peer.ConnectionSessions {
+			if session.ConnectedAt != nil && p.InMeasurement(*session.ConnectedAt) {
+				sessions = append(sessions, session)
+			}
+		}
+
+		if len(sessions) > 0 {
+			view := *peer
+			view.ConnectionSessions = sessions
+			measured[peerID] = &view
+		}
+	}
+
+	return measured
+}
+
+// MeasurementDuration returns the length of the measurement window.
+func (p *RunPhases) MeasurementDuration() time.Duration {
+	return p.MeasureEnd.Sub(p.MeasureStart)
+}
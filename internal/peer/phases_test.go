@@ -0,0 +1,89 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunPhases(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	phases := NewRunPhases(start, time.Minute, 2*time.Minute, 30*time.Second)
+
+	tests := []struct {
+		name          string
+		at            time.Time
+		expectedPhase string
+		inMeasurement bool
+	}{
+		{"run start", start, PhaseWarmup, false},
+		{"measurement start", start.Add(time.Minute), PhaseMeasure, true},
+		{"mid measurement", start.Add(2 * time.Minute), PhaseMeasure, true},
+		{"measurement end", start.Add(3 * time.Minute), PhaseCooldown, false},
+		{"after cooldown", start.Add(4 * time.Minute), PhaseComplete, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if phase := phases.PhaseAt(tt.at); phase != tt.expectedPhase {
+				t.Errorf("expected phase %s, got %s", tt.expectedPhase, phase)
+			}
+
+			if in := phases.InMeasurement(tt.at); in != tt.inMeasurement {
+				t.Errorf("expected in measurement %v, got %v", tt.inMeasurement, in)
+			}
+		})
+	}
+}
+
+func TestRunPhasesFinishInterrupted(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	phases := NewRunPhases(start, time.Minute, 2*time.Minute, 30*time.Second)
+
+	phases.Finish(start.Add(90 * time.Second))
+
+	if !phases.Interrupted || phases.EndedInPhase != PhaseMeasure {
+		t.Errorf("expected interrupted during measure, got interrupted=%v phase=%s", phases.Interrupted, phases.EndedInPhase)
+	}
+
+	if phases.MeasurementDuration() != 30*time.Second {
+		t.Errorf("expected measurement window clamped to 30s, got %s", phases.MeasurementDuration())
+	}
+}
+
+func TestCalculateConnectionStatsInWindow(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	phases := NewRunPhases(start, time.Minute, 2*time.Minute, time.Minute)
+
+	at := func(offset time.Duration) *time.Time {
+		ts := start.Add(offset)
+
+		return &ts
+	}
+
+	peers := map[string]*Stats{
+		"warmup-peer": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(10 * time.Second), IdentifiedAt: at(11 * time.Second)},
+		}},
+		"measured-peer": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(10 * time.Second), IdentifiedAt: at(11 * time.Second), Disconnected: true},
+			{ConnectedAt: at(90 * time.Second), IdentifiedAt: at(91 * time.Second)},
+			{ConnectedAt: at(100 * time.Second), Disconnected: true},
+		}},
+		"cooldown-peer": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(200 * time.Second)},
+		}},
+	}
+
+	stats := NewStatsCalculator().CalculateConnectionStatsInWindow(peers, phases)
+
+	expected := ConnectionStats{
+		TotalConnections:     2,
+		SuccessfulHandshakes: 1,
+		FailedHandshakes:     1,
+		ConnectedPeers:       1,
+	}
+
+	if stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
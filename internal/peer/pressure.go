@@ -0,0 +1,147 @@
+package peer
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// PeerPressure describes how often our own MaxPeers limit was reached and how many sessions
+// it likely ended. Hermes stops dialing at MaxPeers and libp2p trims connections above its
+// high water mark, both without a goodbye, so a session closed without a goodbye while we
+// were at capacity is attributed to our limit rather than to the peer.
+type PeerPressure struct {
+	MaxPeers          int        `json:"max_peers"`
+	CapacityRatio     float64    `json:"capacity_ratio"` // Share of MaxPeers counted as being at capacity
+	Capacity          int        `json:"capacity"`       // Peer count at which we are at capacity
+	PeakPeers         int        `json:"peak_peers"`
+	PeakAt            *time.Time `json:"peak_at,omitempty"`
+	FirstAtCapacity   *time.Time `json:"first_at_capacity,omitempty"`
+	CapacityPeriods   int        `json:"capacity_periods"` // Times the peer count rose to capacity
+	SecondsAtCapacity float64    `json:"seconds_at_capacity"`
+	Disconnects       int        `json:"disconnects"`
+	EndedByPeer       int        `json:"ended_by_peer"`        // The peer said goodbye
+	EndedByLocalLimit int        `json:"ended_by_local_limit"` // Ended without a goodbye while we were at capacity
+	Rejected          int        `json:"rejected"`             // Local limit sessions shorter than a short session, turned away rather than pruned
+	Pruned            int        `json:"pruned"`               // Local limit sessions that had been established
+	Distorted         bool       `json:"distorted"`            // Local limit drops are a large enough share of disconnects to skew churn statistics
+}
+
+// peerCountChange is a session opening or closing in the peer count sweep.
+type peerCountChange struct {
+	at      time.Time
+	delta   int
+	session *ConnectionSession
+}
+
+// AnalyzePeerPressure reconstructs our peer count from the sessions' connect and disconnect
+// times, records when it reached capacity, and tags the sessions ended by our own limit.
+// Sessions closed at a checkpoint gap, during shutdown or by a MaxPeers ramp restart are not
+// counted as disconnects.
+func AnalyzePeerPressure(peers map[string]*Stats, maxPeers int, ratio float64, end time.Time) *PeerPressure {
+	pressure := &PeerPressure{
+		MaxPeers:      maxPeers,
+		CapacityRatio: ratio,
+		Capacity:      max(int(math.Ceil(float64(maxPeers)*ratio)), 1),
+	}
+
+	changes := make([]peerCountChange, 0)
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			if session.ConnectedAt == nil {
+				continue
+			}
+
+			changes = append(changes, peerCountChange{at: *session.ConnectedAt, delta: 1, session: session})
+
+			if session.Disconnected && session.DisconnectedAt != nil {
+				changes = append(changes, peerCountChange{at: *session.DisconnectedAt, delta: -1, session: session})
+			}
+		}
+	}
+
+	// Connects go first at equal times, so a session closing as another opens sees the full count
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].at.Equal(changes[j].at) {
+			return changes[i].at.Before(changes[j].at)
+		}
+
+		return changes[i].delta > changes[j].delta
+	})
+
+	var (
+		count      int
+		atCapacity *time.Time
+	)
+
+	for _, change := range changes {
+		if change.delta < 0 {
+			pressure.tagSession(change.session, count >= pressure.Capacity)
+		}
+
+		count += change.delta
+
+		if count > pressure.PeakPeers {
+			pressure.PeakPeers = count
+			at := change.at
+			pressure.PeakAt = &at
+		}
+
+		switch {
+		case count >= pressure.Capacity && atCapacity == nil:
+			at := change.at
+			atCapacity = &at
+			pressure.CapacityPeriods++
+
+			if pressure.FirstAtCapacity == nil {
+				pressure.FirstAtCapacity = &at
+			}
+		case count < pressure.Capacity && atCapacity != nil:
+			pressure.SecondsAtCapacity += change.at.Sub(*atCapacity).Seconds()
+			atCapacity = nil
+		}
+	}
+
+	if atCapacity != nil && end.After(*atCapacity) {
+		pressure.SecondsAtCapacity += end.Sub(*atCapacity).Seconds()
+	}
+
+	pressure.Distorted = pressure.EndedByLocalLimit > 0 &&
+		float64(pressure.EndedByLocalLimit) >= constants.PeerPressureDistortionShare*float64(pressure.Disconnects)
+
+	return pressure
+}
+
+// tagSession counts a disconnect, and tags the session as ended by our limit when the peer
+// did not say goodbye and we were at capacity.
+func (p *PeerPressure) tagSession(session *ConnectionSession, atCapacity bool) {
+	session.EndedByLocalLimit = false
+
+	if session.EndedByGap || session.EndedInShutdown || session.EndedByRampRestart {
+		return
+	}
+
+	p.Disconnects++
+
+	switch {
+	case len(session.GoodbyeEvents) > 0:
+		p.EndedByPeer++
+	case atCapacity:
+		session.EndedByLocalLimit = true
+		p.EndedByLocalLimit++
+
+		if session.DisconnectedAt.Sub(*session.ConnectedAt) < constants.ShortSessionDuration {
+			p.Rejected++
+		} else {
+			p.Pruned++
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzePeerPressure(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	session := func(from, to int, goodbye bool) ConnectionSession {
+		s := ConnectionSession{ConnectedAt: at(from)}
+		if to >= 0 {
+			s.DisconnectedAt = at(to)
+			s.Disconnected = true
+		}
+
+		if goodbye {
+			s.GoodbyeEvents = []GoodbyeEvent{{Timestamp: *at(to), Code: 129}}
+		}
+
+		return s
+	}
+
+	// A limit of 3 peers: the count reaches 3 at 10s and drops below it at 100s
+	peers := map[string]*Stats{
+		"a": {ConnectionSessions: []ConnectionSession{session(0, -1, false)}},
+		"b": {ConnectionSessions: []ConnectionSession{session(10, 200, false)}},
+		"c": {ConnectionSessions: []ConnectionSession{session(20, 30, false), session(40, 100, true)}},
+		"d": {ConnectionSessions: []ConnectionSession{session(35, 90, false)}},
+		"e": {ConnectionSessions: []ConnectionSession{{ConnectedAt: at(5), DisconnectedAt: at(50), Disconnected: true, EndedByGap: true}}},
+	}
+
+	pressure := AnalyzePeerPressure(peers, 3, 1, start.Add(300*time.Second))
+
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "capacity", got: pressure.Capacity, want: 3},
+		{name: "peak peers", got: pressure.PeakPeers, want: 5},
+		{name: "capacity periods", got: pressure.CapacityPeriods, want: 1},
+		{name: "disconnects", got: pressure.Disconnects, want: 4},
+		{name: "ended by peer", got: pressure.EndedByPeer, want: 1},
+		{name: "ended by local limit", got: pressure.EndedByLocalLimit, want: 2},
+		{name: "rejected", got: pressure.Rejected, want: 1},
+		{name: "pruned", got: pressure.Pruned, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %d, want %d", tt.got, tt.want)
+			}
+		})
+	}
+
+	if pressure.FirstAtCapacity == nil || !pressure.FirstAtCapacity.Equal(*at(10)) {
+		t.Errorf("expected capacity to be first reached at 10s, got %v", pressure.FirstAtCapacity)
+	}
+
+	// b leaving at 200s is below capacity, so it is attributed to neither side
+	if pressure.SecondsAtCapacity != 90 {
+		t.Errorf("expected 90s at capacity, got %f", pressure.SecondsAtCapacity)
+	}
+
+	if !peers["c"].ConnectionSessions[0].EndedByLocalLimit || peers["c"].ConnectionSessions[1].EndedByLocalLimit || peers["b"].ConnectionSessions[0].EndedByLocalLimit {
+		t.Error("expected only sessions closed without a goodbye at capacity to be tagged")
+	}
+
+	if !pressure.Distorted {
+		t.Error("expected half the disconnects ended by our limit to distort churn statistics")
+	}
+}
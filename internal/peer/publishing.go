@@ -0,0 +1,113 @@
+package peer
+
+import (
+	"sort"
+	"time"
+)
+
+// Hermes traces of our node's own publishing.
+const (
+	RouterPublish = "PUBLISH_MESSAGE"
+	RouterReject  = "REJECT_MESSAGE" // Our own messages are told apart from peers' by the Local flag
+)
+
+// PublishTopic is what our node published on one topic.
+type PublishTopic struct {
+	Topic         string         `json:"topic"`
+	Published     int            `json:"published"`
+	Rejected      int            `json:"rejected"` // Published messages our own validator rejected
+	RejectReasons map[string]int `json:"reject_reasons,omitempty"`
+}
+
+// PublishActivity is our node's own gossip publishing over the run. Gossipsub does not tell a
+// publisher when peers reject its messages, they only count them against its score, so the
+// messages our own validator rejected stand in for the ones peers would reject.
+type PublishActivity struct {
+	Published     int            `json:"published"`
+	Rejected      int            `json:"rejected"`
+	PeakPerBucket int            `json:"peak_per_bucket"` // Most messages published in one router bucket
+	Topics        []PublishTopic `json:"topics"`          // Most published first
+}
+
+// Publish counts a message our node published on a topic.
+func (r *RouterRecorder) Publish(topic string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.advance(at).Published++
+	r.publishTopic(topic).Published++
+}
+
+// LocalReject counts a message our node published that its own validator rejected, by reason.
+func (r *RouterRecorder) LocalReject(topic, reason string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.advance(at).PublishRejected++
+
+	stats := r.publishTopic(topic)
+	stats.Rejected++
+
+	if reason == "" {
+		reason = "unknown"
+	}
+
+	if stats.RejectReasons == nil {
+		stats.RejectReasons = make(map[string]int)
+	}
+
+	stats.RejectReasons[reason]++
+}
+
+// publishTopic returns the publish counts of a topic, adding them on first use.
+func (r *RouterRecorder) publishTopic(topic string) *PublishTopic {
+	if r.publishing == nil {
+		r.publishing = make(map[string]*PublishTopic)
+	}
+
+	stats, ok := r.publishing[topic]
+	if !ok {
+		stats = &PublishTopic{Topic: topic}
+		r.publishing[topic] = stats
+	}
+
+	return stats
+}
+
+// publishActivity summarises the publishing recorded in the samples, or nil when our node
+// published nothing.
+func (r *RouterRecorder) publishActivity(samples []RouterSample) *PublishActivity {
+	if len(r.publishing) == 0 {
+		return nil
+	}
+
+	activity := &PublishActivity{Topics: make([]PublishTopic, 0, len(r.publishing))}
+
+	for _, stats := range r.publishing {
+		copied := *stats
+		if stats.RejectReasons != nil {
+			copied.RejectReasons = make(map[string]int, len(stats.RejectReasons))
+			for reason, count := range stats.RejectReasons {
+				copied.RejectReasons[reason] = count
+			}
+		}
+
+		activity.Published += stats.Published
+		activity.Rejected += stats.Rejected
+		activity.Topics = append(activity.Topics, copied)
+	}
+
+	for _, sample := range samples {
+		activity.PeakPerBucket = max(activity.PeakPerBucket, sample.Published)
+	}
+
+	sort.Slice(activity.Topics, func(i, j int) bool {
+		if activity.Topics[i].Published != activity.Topics[j].Published {
+			return activity.Topics[i].Published > activity.Topics[j].Published
+		}
+
+		return activity.Topics[i].Topic < activity.Topics[j].Topic
+	})
+
+	return activity
+}
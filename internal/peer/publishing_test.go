@@ -0,0 +1,55 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPublishActivity(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	recorder := NewRouterRecorder(start, time.Minute)
+
+	// Bucket 0: three attestations, one of them rejected by our own validator
+	recorder.Publish("beacon_attestation_3", at(1))
+	recorder.Publish("beacon_attestation_3", at(2))
+	recorder.Publish("beacon_attestation_3", at(3))
+	recorder.LocalReject("beacon_attestation_3", "validation ignored", at(3))
+
+	// Bucket 1: one aggregate, rejected without a reason
+	recorder.Publish("beacon_aggregate_and_proof", at(70))
+	recorder.LocalReject("beacon_aggregate_and_proof", "", at(70))
+
+	metrics := recorder.Snapshot(at(90))
+
+	publishing := metrics.Publishing
+	if publishing == nil {
+		t.Fatal("Expected publish activity")
+	}
+
+	if publishing.Published != 4 || publishing.Rejected != 2 || publishing.PeakPerBucket != 3 {
+		t.Errorf("Unexpected publish totals %+v", publishing)
+	}
+
+	if metrics.Totals.Published != 4 || metrics.Samples[1].PublishRejected != 1 {
+		t.Errorf("Expected publishing in the router samples, got totals %+v", metrics.Totals)
+	}
+
+	if len(publishing.Topics) != 2 || publishing.Topics[0].Topic != "beacon_attestation_3" {
+		t.Fatalf("Expected the most published topic first, got %+v", publishing.Topics)
+	}
+
+	if publishing.Topics[0].RejectReasons["validation ignored"] != 1 || publishing.Topics[1].RejectReasons["unknown"] != 1 {
+		t.Errorf("Unexpected reject reasons %+v", publishing.Topics)
+	}
+
+	quiet := NewRouterRecorder(start, time.Minute)
+	quiet.Message(false, at(1))
+
+	if quiet.Snapshot(at(60)).Publishing != nil {
+		t.Error("Expected no publish activity when nothing was published")
+	}
+}
@@ -0,0 +1,224 @@
+package peer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// RampRestart is a restart of Hermes into the next step of a MaxPeers ramp. Hermes reads
+// MaxPeers once when its node starts, so each step needs a fresh node.
+type RampRestart struct {
+	Step     int       `json:"step"`
+	MaxPeers int       `json:"max_peers"`
+	Start    time.Time `json:"start"` // Hermes was being stopped
+	End      time.Time `json:"end"`   // The new node was up, or the restart failed
+	Error    string    `json:"error,omitempty"`
+}
+
+// RampStep is one step of a MaxPeers ramp with the run's headline statistics during it.
+type RampStep struct {
+	Step     int          `json:"step"` // From 1
+	MaxPeers int          `json:"max_peers"`
+	Restart  *RampRestart `json:"restart,omitempty"` // Nil when not observed, e.g. for the first step
+	TimeSlice
+	ShortLived     int     `json:"short_lived"`      // Disconnects of sessions shorter than a short session
+	ChurnPerHour   float64 `json:"churn_per_hour"`   // Disconnects per hour of the step
+	PeakPeers      int     `json:"peak_peers"`       // Most sessions open at once
+	MeanPeers      float64 `json:"mean_peers"`       // Sessions open on average over the step
+	Fill           float64 `json:"fill"`             // Mean peers over MaxPeers
+	EndedByRestart int     `json:"ended_by_restart"` // Sessions the restart into the next step closed
+}
+
+// MaxPeersRamp is how the peers responded to stepping our MaxPeers during the run.
+type MaxPeersRamp struct {
+	StepSeconds float64    `json:"step_seconds"`
+	Steps       []RampStep `json:"steps"`
+}
+
+// AnalyzeMaxPeersRamp tags each session with the ramp step it connected in, and the sessions
+// closed by a restart into the next step, then computes the headline statistics per step.
+// Steps start every step from start, the last one lasts until end. Steps after a failed
+// restart never ran and are left out. Returns nil without a ramp.
+func AnalyzeMaxPeersRamp(peers map[string]*Stats, ramp []int, step time.Duration, start, end time.Time, restarts []RampRestart) *MaxPeersRamp {
+	if len(ramp) == 0 || step <= 0 || !end.After(start) {
+		return nil
+	}
+
+	byStep := make(map[int]RampRestart, len(restarts))
+	for _, restart := range restarts {
+		byStep[restart.Step] = restart
+	}
+
+	bounds := []time.Time{start}
+
+	for i := 1; i < len(ramp); i++ {
+		if restart, ok := byStep[i+1]; ok && restart.Error != "" {
+			break
+		}
+
+		next := start.Add(time.Duration(i) * step)
+		if !next.Before(end) {
+			break
+		}
+
+		bounds = append(bounds, next)
+	}
+
+	bounds = append(bounds, end)
+	steps := len(bounds) - 1
+
+	tagRampSessions(peers, bounds, restarts)
+
+	result := &MaxPeersRamp{StepSeconds: step.Seconds(), Steps: make([]RampStep, steps)}
+
+	for i, slice := range calculateWindows(GeneralPeers(peers), bounds) {
+		result.Steps[i] = RampStep{Step: i + 1, MaxPeers: ramp[i], TimeSlice: slice}
+
+		if restart, ok := byStep[i+1]; ok {
+			copied := restart
+			result.Steps[i].Restart = &copied
+		}
+	}
+
+	for _, stats := range GeneralPeers(peers) {
+		for _, session := range stats.ConnectionSessions {
+			if !session.Disconnected || session.DisconnectedAt == nil || session.ConnectedAt == nil {
+				continue
+			}
+
+			i := windowOf(bounds, *session.DisconnectedAt)
+			if i < 0 {
+				continue
+			}
+
+			// Restarts begin on the next step's boundary, so their closes fall in the next step
+			if session.EndedByRampRestart {
+				result.Steps[max(i-1, 0)].EndedByRestart++
+			} else if session.DisconnectedAt.Sub(*session.ConnectedAt) < constants.ShortSessionDuration {
+				result.Steps[i].ShortLived++
+			}
+		}
+	}
+
+	occupancy(GeneralPeers(peers), bounds, result.Steps)
+
+	for i := range result.Steps {
+		rampStep := &result.Steps[i]
+
+		if hours := rampStep.End.Sub(rampStep.Start).Hours(); hours > 0 {
+			rampStep.ChurnPerHour = float64(rampStep.Disconnects) / hours
+		}
+
+		rampStep.Fill = rampStep.MeanPeers / float64(rampStep.MaxPeers)
+	}
+
+	return result
+}
+
+// tagRampSessions records the step each session connected in, and flags the sessions that were
+// open when a restart began and closed before the new node was up.
+func tagRampSessions(peers map[string]*Stats, bounds []time.Time, restarts []RampRestart) {
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			session.RampStep = 0
+			session.EndedByRampRestart = false
+
+			if session.ConnectedAt == nil {
+				continue
+			}
+
+			session.RampStep = windowOf(bounds, *session.ConnectedAt) + 1
+
+			if !session.Disconnected || session.DisconnectedAt == nil {
+				continue
+			}
+
+			for _, restart := range restarts {
+				if restart.Error == "" && session.ConnectedAt.Before(restart.Start) &&
+					!session.DisconnectedAt.Before(restart.Start) && !session.DisconnectedAt.After(restart.End) {
+					session.EndedByRampRestart = true
+
+					break
+				}
+			}
+		}
+	}
+}
+
+// windowOf returns the window between consecutive bounds a time falls in, or -1 outside them.
+func windowOf(bounds []time.Time, t time.Time) int {
+	last := len(bounds) - 1
+	if t.Before(bounds[0]) || !t.Before(bounds[last]) {
+		return -1
+	}
+
+	return sort.Search(last, func(i int) bool { return t.Before(bounds[i+1]) })
+}
+
+// occupancy rebuilds the number of open sessions over the run and records each step's peak
+// and time-weighted mean.
+func occupancy(peers map[string]*Stats, bounds []time.Time, steps []RampStep) {
+	changes := make([]peerCountChange, 0)
+
+	for _, stats := range peers {
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			if session.ConnectedAt == nil {
+				continue
+			}
+
+			changes = append(changes, peerCountChange{at: *session.ConnectedAt, delta: 1})
+
+			if session.Disconnected && session.DisconnectedAt != nil {
+				changes = append(changes, peerCountChange{at: *session.DisconnectedAt, delta: -1})
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].at.Equal(changes[j].at) {
+			return changes[i].at.Before(changes[j].at)
+		}
+
+		return changes[i].delta > changes[j].delta
+	})
+
+	count, next := 0, 0
+
+	for i := range steps {
+		from, to := bounds[i], bounds[i+1]
+
+		// Sessions opened and closed before the step make up its starting count
+		for next < len(changes) && changes[next].at.Before(from) {
+			count += changes[next].delta
+			next++
+		}
+
+		var area float64
+
+		steps[i].PeakPeers = count
+		at := from
+
+		for next < len(changes) && changes[next].at.Before(to) {
+			area += float64(count) * changes[next].at.Sub(at).Seconds()
+			at = changes[next].at
+			count += changes[next].delta
+			next++
+
+			steps[i].PeakPeers = max(steps[i].PeakPeers, count)
+		}
+
+		area += float64(count) * to.Sub(at).Seconds()
+
+		if seconds := to.Sub(from).Seconds(); seconds > 0 {
+			steps[i].MeanPeers = area / seconds
+		}
+	}
+}
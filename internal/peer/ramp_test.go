@@ -0,0 +1,79 @@
+package peer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeMaxPeersRamp(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) *time.Time {
+		value := start.Add(offset)
+
+		return &value
+	}
+
+	peers := map[string]*Stats{
+		"a": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(time.Minute), IdentifiedAt: at(time.Minute), DisconnectedAt: at(30*time.Minute + 5*time.Second), Disconnected: true},
+			{ConnectedAt: at(31 * time.Minute), IdentifiedAt: at(31 * time.Minute), DisconnectedAt: at(31*time.Minute + 10*time.Second), Disconnected: true},
+		}},
+		"b": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(2 * time.Minute), IdentifiedAt: at(2 * time.Minute), PeerScores: []PeerScoreSnapshot{
+				{Timestamp: *at(5 * time.Minute), Score: 10},
+			}},
+		}},
+		"boot": {Origin: OriginBootnode, ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(3 * time.Minute), DisconnectedAt: at(40 * time.Minute), Disconnected: true},
+		}},
+	}
+
+	restarts := []RampRestart{
+		{Step: 2, MaxPeers: 4, Start: *at(30 * time.Minute), End: *at(30*time.Minute + 10*time.Second)},
+		{Step: 3, MaxPeers: 8, Start: *at(60 * time.Minute), End: *at(60*time.Minute + 5*time.Second)},
+	}
+
+	ramp := AnalyzeMaxPeersRamp(peers, []int{2, 4, 8}, 30*time.Minute, start, start.Add(70*time.Minute), restarts)
+	if ramp == nil || len(ramp.Steps) != 3 {
+		t.Fatalf("Expected 3 ramp steps, got %+v", ramp)
+	}
+
+	if !peers["a"].ConnectionSessions[0].EndedByRampRestart || peers["a"].ConnectionSessions[1].EndedByRampRestart {
+		t.Error("Expected only the session open at the first restart to be closed by it")
+	}
+
+	if peers["a"].ConnectionSessions[1].RampStep != 2 || peers["boot"].ConnectionSessions[0].RampStep != 1 {
+		t.Error("Expected sessions to be tagged with the step they connected in")
+	}
+
+	first := ramp.Steps[0]
+	if first.MaxPeers != 2 || first.Restart != nil || first.Connections != 2 || first.Disconnects != 0 || first.EndedByRestart != 1 {
+		t.Errorf("Unexpected first step %+v", first)
+	}
+
+	// a from minute 1 and b from minute 2 until the end of the 30 minute step
+	if first.PeakPeers != 2 || math.Abs(first.MeanPeers-1.9) > 1e-9 || first.MeanScore != 10 {
+		t.Errorf("Unexpected first step occupancy %+v", first)
+	}
+
+	second := ramp.Steps[1]
+	if second.Restart == nil || second.Connections != 1 || second.Disconnects != 1 || second.ShortLived != 1 || second.ChurnPerHour != 2 {
+		t.Errorf("Unexpected second step %+v", second)
+	}
+
+	if third := ramp.Steps[2]; third.MaxPeers != 8 || !third.End.Equal(start.Add(70*time.Minute)) || third.Fill != 0.125 {
+		t.Errorf("Unexpected third step %+v", third)
+	}
+
+	restarts[1].Error = "hermes node did not stop"
+
+	ramp = AnalyzeMaxPeersRamp(peers, []int{2, 4, 8}, 30*time.Minute, start, start.Add(70*time.Minute), restarts)
+	if len(ramp.Steps) != 2 || !ramp.Steps[1].End.Equal(start.Add(70*time.Minute)) {
+		t.Errorf("Expected the ramp to stop at the failed restart, got %+v", ramp.Steps)
+	}
+
+	if AnalyzeMaxPeersRamp(peers, nil, 30*time.Minute, start, start.Add(time.Hour), nil) != nil {
+		t.Error("Expected no ramp statistics without a ramp")
+	}
+}
@@ -14,6 +14,9 @@ type InMemoryRepository struct {
 	eventCounts map[string]map[string]int
 	mu          sync.RWMutex
 	eventsMu    sync.RWMutex
+	sampler     *DetailSampler
+	spiller     *Spiller
+	clock       func() time.Time
 	logger      logrus.FieldLogger
 }
 
@@ -22,10 +25,27 @@ func NewInMemoryRepository(logger logrus.FieldLogger) *InMemoryRepository {
 	return &InMemoryRepository{
 		peers:       make(map[string]*Stats),
 		eventCounts: make(map[string]map[string]int),
+		clock:       time.Now,
 		logger:      logger.WithField("component", "peer_repository"),
 	}
 }
 
+// SetSampler samples new peers for detailed capture, nil captures every peer in full.
+func (r *InMemoryRepository) SetSampler(sampler *DetailSampler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sampler = sampler
+}
+
+// SetClock sets the source of the first and last seen times of new peers, time.Now by default.
+func (r *InMemoryRepository) SetClock(clock func() time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clock = clock
+}
+
 // GetPeer retrieves a peer by ID.
 func (r *InMemoryRepository) GetPeer(peerID string) (*Stats, bool) {
 	r.mu.RLock()
@@ -47,7 +67,7 @@ func (r *InMemoryRepository) CreatePeer(peerID string) *Stats {
 		return existing
 	}
 
-	now := time.Now()
+	now := r.clock()
 	peer := &Stats{
 		PeerID:             peerID,
 		ConnectionSessions: make([]ConnectionSession, 0),
@@ -55,6 +75,7 @@ func (r *InMemoryRepository) CreatePeer(peerID string) *Stats {
 		TotalMessageCount:  0,
 		FirstSeenAt:        &now,
 		LastSeenAt:         &now,
+		Sample:             r.sample(peerID),
 	}
 
 	r.peers[peerID] = peer
@@ -77,6 +98,7 @@ func (r *InMemoryRepository) UpdatePeer(peerID string, updateFn func(*Stats)) {
 	}
 
 	updateFn(peer)
+	packScores(peer)
 }
 
 // UpdateOrCreatePeer safely updates a peer or creates one if it doesn't exist.
@@ -87,7 +109,7 @@ func (r *InMemoryRepository) UpdateOrCreatePeer(peerID string, updateFn func(*St
 	peer, exists := r.peers[peerID]
 	if !exists {
 		// Create a new peer with default values
-		now := time.Now()
+		now := r.clock()
 		peer = &Stats{
 			PeerID:             peerID,
 			ClientType:         constants.Unknown,
@@ -96,12 +118,23 @@ func (r *InMemoryRepository) UpdateOrCreatePeer(peerID string, updateFn func(*St
 			LastSeenAt:         &now,
 			TotalConnections:   0,
 			ConnectionSessions: []ConnectionSession{},
+			Sample:             r.sample(peerID),
 		}
 		r.peers[peerID] = peer
 		r.logger.WithField("peer_id", formatShortPeerID(peerID)).Debug("Created new peer from event")
 	}
 
 	updateFn(peer)
+	packScores(peer)
+}
+
+// sample draws the sampling decision for a new peer, nil when sampling is disabled.
+func (r *InMemoryRepository) sample(peerID string) *DetailSample {
+	if r.sampler == nil {
+		return nil
+	}
+
+	return r.sampler.Decide(peerID)
 }
 
 // GetAllPeers returns a copy of all peers (thread-safe).
@@ -147,6 +180,33 @@ func (r *InMemoryRepository) IncrementEventCount(peerID, eventType string) {
 	r.eventCounts[peerID][eventType]++
 }
 
+// Restore replaces the repository contents with peers and event counts restored from a checkpoint.
+func (r *InMemoryRepository) Restore(peers map[string]*Stats, eventCounts map[string]map[string]int) {
+	r.mu.Lock()
+	r.peers = make(map[string]*Stats, len(peers))
+
+	for peerID, peer := range peers {
+		r.peers[peerID] = r.deepCopyPeer(peer)
+		packScores(r.peers[peerID])
+	}
+
+	r.mu.Unlock()
+
+	r.eventsMu.Lock()
+	r.eventCounts = make(map[string]map[string]int, len(eventCounts))
+
+	for peerID, events := range eventCounts {
+		r.eventCounts[peerID] = make(map[string]int, len(events))
+		for eventType, count := range events {
+			r.eventCounts[peerID][eventType] = count
+		}
+	}
+
+	r.eventsMu.Unlock()
+
+	r.logger.WithField("peers", len(peers)).Info("Restored peers from checkpoint")
+}
+
 // GetMutex returns the main mutex for external synchronization if needed.
 func (r *InMemoryRepository) GetMutex() *sync.RWMutex {
 	return &r.mu
@@ -194,14 +254,22 @@ func (r *InMemoryRepository) deepCopyPeer(original *Stats) *Stats {
 	}
 
 	return &Stats{
-		PeerID:             original.PeerID,
-		ClientType:         original.ClientType,
-		ClientAgent:        original.ClientAgent,
-		ConnectionSessions: sessionsCopy,
-		TotalConnections:   original.TotalConnections,
-		TotalMessageCount:  original.TotalMessageCount,
-		FirstSeenAt:        copyTimePtr(original.FirstSeenAt),
-		LastSeenAt:         copyTimePtr(original.LastSeenAt),
+		PeerID:               original.PeerID,
+		ClientType:           original.ClientType,
+		ClientAgent:          original.ClientAgent,
+		Origin:               original.Origin,
+		ConnectionSessions:   sessionsCopy,
+		TotalConnections:     original.TotalConnections,
+		TotalMessageCount:    original.TotalMessageCount,
+		FirstSeenAt:          copyTimePtr(original.FirstSeenAt),
+		LastSeenAt:           copyTimePtr(original.LastSeenAt),
+		DecodeErrors:         copyDecodeErrors(original.DecodeErrors),
+		ReqRespAbuse:         copyReqRespAbuse(original.ReqRespAbuse),
+		ControlPlane:         copyControlPlane(original.ControlPlane),
+		Sample:               copyDetailSample(original.Sample),
+		DroppedLateEvents:    copyCounts(original.DroppedLateEvents),
+		DuplicateConnections: copyCounts(original.DuplicateConnections),
+		Consensus:            copyConsensus(original.Consensus),
 	}
 }
 
@@ -211,17 +279,25 @@ func (r *InMemoryRepository) deepCopySession(original ConnectionSession) Connect
 	scoresCopy := make([]PeerScoreSnapshot, len(original.PeerScores))
 
 	for i, score := range original.PeerScores {
-		// Deep copy topics slice
-		topicsCopy := make([]TopicScore, len(score.Topics))
-		copy(topicsCopy, score.Topics)
+		// Deep copy topics slice, packed topics are never modified so they are shared
+		var topicsCopy []TopicScore
+		if score.packedTopics == nil {
+			topicsCopy = make([]TopicScore, len(score.Topics))
+			copy(topicsCopy, score.Topics)
+		}
 
 		scoresCopy[i] = PeerScoreSnapshot{
 			Timestamp:          score.Timestamp,
+			Slot:               score.Slot,
+			Epoch:              score.Epoch,
 			Score:              score.Score,
 			AppSpecificScore:   score.AppSpecificScore,
 			IPColocationFactor: score.IPColocationFactor,
 			BehaviourPenalty:   score.BehaviourPenalty,
 			Topics:             topicsCopy,
+			PostDisconnect:     score.PostDisconnect,
+			BeaconDegraded:     score.BeaconDegraded,
+			packedTopics:       score.packedTopics,
 		}
 	}
 
@@ -233,17 +309,166 @@ func (r *InMemoryRepository) deepCopySession(original ConnectionSession) Connect
 	meshCopy := make([]MeshEvent, len(original.MeshEvents))
 	copy(meshCopy, original.MeshEvents)
 
+	// Deep copy local terminations, nil while Hermes reset no stream
+	var terminationsCopy []LocalTermination
+	if original.LocalTerminations != nil {
+		terminationsCopy = make([]LocalTermination, len(original.LocalTerminations))
+		copy(terminationsCopy, original.LocalTerminations)
+	}
+
+	// Deep copy status updates, nil while the peer sent none
+	var statusCopy []StatusUpdate
+	if original.StatusUpdates != nil {
+		statusCopy = make([]StatusUpdate, len(original.StatusUpdates))
+		copy(statusCopy, original.StatusUpdates)
+	}
+
 	return ConnectionSession{
-		ConnectedAt:    copyTimePtr(original.ConnectedAt),
-		IdentifiedAt:   copyTimePtr(original.IdentifiedAt),
-		DisconnectedAt: copyTimePtr(original.DisconnectedAt),
-		MessageCount:   original.MessageCount,
-		Duration:       copyDurationPtr(original.Duration),
-		Disconnected:   original.Disconnected,
-		PeerScores:     scoresCopy,
-		GoodbyeEvents:  goodbyesCopy,
-		MeshEvents:     meshCopy,
+		ConnectedAt:          copyTimePtr(original.ConnectedAt),
+		Direction:            original.Direction,
+		Transport:            original.Transport,
+		Muxer:                original.Muxer,
+		Security:             original.Security,
+		IdentifiedAt:         copyTimePtr(original.IdentifiedAt),
+		DisconnectedAt:       copyTimePtr(original.DisconnectedAt),
+		ConnectedSlot:        original.ConnectedSlot,
+		ConnectedEpoch:       original.ConnectedEpoch,
+		DisconnectedSlot:     original.DisconnectedSlot,
+		DisconnectedEpoch:    original.DisconnectedEpoch,
+		MessageCount:         original.MessageCount,
+		Duration:             copyDurationPtr(original.Duration),
+		Disconnected:         original.Disconnected,
+		EndedByGap:           original.EndedByGap,
+		EndedByLocalLimit:    original.EndedByLocalLimit,
+		EndedByResourceLimit: original.EndedByResourceLimit,
+		EndedInShutdown:      original.EndedInShutdown,
+		EndedByRampRestart:   original.EndedByRampRestart,
+		Censored:             original.Censored,
+		ConnectionKey:        original.ConnectionKey,
+		RampStep:             original.RampStep,
+		LateEvents:           original.LateEvents,
+		PeerScores:           scoresCopy,
+		ScoreSummary:         copyScoreSummary(original.ScoreSummary),
+		GoodbyeEvents:        goodbyesCopy,
+		LocalTerminations:    terminationsCopy,
+		MeshEvents:           meshCopy,
+		StatusUpdates:        statusCopy,
+		EventSamples:         copyEventSamples(original.EventSamples),
+		spilled:              original.spilled,
+	}
+}
+
+// copyScoreSummary creates a copy of a session's score summary.
+func copyScoreSummary(original *SessionScoreSummary) *SessionScoreSummary {
+	if original == nil {
+		return nil
+	}
+
+	copied := *original
+
+	return &copied
+}
+
+// copyConsensus creates a copy of a peer's consensus score.
+func copyConsensus(original *ConsensusScore) *ConsensusScore {
+	if original == nil {
+		return nil
 	}
+
+	copied := *original
+
+	return &copied
+}
+
+// copyDetailSample creates a deep copy of a peer's sampling decision.
+func copyDetailSample(original *DetailSample) *DetailSample {
+	if original == nil {
+		return nil
+	}
+
+	copied := *original
+	copied.PromotedAt = copyTimePtr(original.PromotedAt)
+
+	return &copied
+}
+
+// copyEventSamples creates a copy of a session's sampled event counts.
+func copyEventSamples(original EventSampleCounts) EventSampleCounts {
+	if original == nil {
+		return nil
+	}
+
+	copied := make(EventSampleCounts, len(original))
+	for eventType, count := range original {
+		copied[eventType] = count
+	}
+
+	return copied
+}
+
+// copyCounts creates a copy of a count map.
+func copyCounts(original map[string]int) map[string]int {
+	if original == nil {
+		return nil
+	}
+
+	copied := make(map[string]int, len(original))
+	for key, count := range original {
+		copied[key] = count
+	}
+
+	return copied
+}
+
+// copyDecodeErrors creates a deep copy of decode error statistics.
+func copyDecodeErrors(original *DecodeErrorStats) *DecodeErrorStats {
+	if original == nil {
+		return nil
+	}
+
+	copied := &DecodeErrorStats{
+		Total:      original.Total,
+		ByKind:     make(map[string]int, len(original.ByKind)),
+		ByTopic:    make(map[string]int, len(original.ByTopic)),
+		LastReason: original.LastReason,
+		LastSeenAt: copyTimePtr(original.LastSeenAt),
+	}
+
+	for kind, count := range original.ByKind {
+		copied.ByKind[kind] = count
+	}
+
+	for topic, count := range original.ByTopic {
+		copied.ByTopic[topic] = count
+	}
+
+	return copied
+}
+
+// copyReqRespAbuse creates a deep copy of req/resp abuse statistics.
+func copyReqRespAbuse(original *ReqRespAbuseStats) *ReqRespAbuseStats {
+	if original == nil {
+		return nil
+	}
+
+	return &ReqRespAbuseStats{
+		Total:      original.Total,
+		ByKind:     copyCounts(original.ByKind),
+		ByProtocol: copyCounts(original.ByProtocol),
+		LastReason: original.LastReason,
+		LastSeenAt: copyTimePtr(original.LastSeenAt),
+	}
+}
+
+// copyControlPlane creates a copy of gossipsub control plane counts.
+func copyControlPlane(original *ControlPlaneStats) *ControlPlaneStats {
+	if original == nil {
+		return nil
+	}
+
+	copied := *original
+
+	return &copied
 }
 
 // hasActiveSession checks if a peer has any active (non-disconnected) sessions.
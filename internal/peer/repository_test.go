@@ -89,13 +89,19 @@ func TestDeepCopy(t *testing.T) {
 	// Add a session with some data
 	now := time.Now()
 	session := ConnectionSession{
-		ConnectedAt:   &now,
-		IdentifiedAt:  &now,
-		MessageCount:  10,
-		Disconnected:  false,
-		PeerScores:    []PeerScoreSnapshot{{Score: 1.5, Timestamp: now}},
-		GoodbyeEvents: []GoodbyeEvent{{Code: 1, Timestamp: now}},
-		MeshEvents:    []MeshEvent{{Type: "GRAFT", Timestamp: now}},
+		ConnectedAt:       &now,
+		Direction:         DirectionInbound,
+		Transport:         TransportQUIC,
+		Muxer:             "quic",
+		Security:          "tls",
+		IdentifiedAt:      &now,
+		EndedByLocalLimit: true,
+		MessageCount:      10,
+		Disconnected:      false,
+		PeerScores:        []PeerScoreSnapshot{{Score: 1.5, Timestamp: now}},
+		GoodbyeEvents:     []GoodbyeEvent{{Code: 1, Timestamp: now}},
+		MeshEvents:        []MeshEvent{{Type: "GRAFT", Timestamp: now}},
+		StatusUpdates:     []StatusUpdate{{Timestamp: now, HeadSlot: 100}},
 	}
 
 	repo.UpdatePeer(peerID, func(p *Stats) {
@@ -106,9 +112,19 @@ func TestDeepCopy(t *testing.T) {
 	allPeers := repo.GetAllPeers()
 	copiedPeer := allPeers[peerID]
 
+	copied := copiedPeer.ConnectionSessions[0]
+	if copied.Direction != session.Direction || copied.Transport != session.Transport || copied.Muxer != session.Muxer || copied.Security != session.Security || !copied.EndedByLocalLimit {
+		t.Errorf("Deep copy lost the session's link details or tags: %+v", copied)
+	}
+
+	if len(copied.StatusUpdates) != 1 || copied.StatusUpdates[0].HeadSlot != 100 {
+		t.Errorf("Deep copy lost the session's status updates: %+v", copied.StatusUpdates)
+	}
+
 	// Modify the copied peer
 	copiedPeer.ClientType = "modified"
 	copiedPeer.ConnectionSessions[0].MessageCount = 999
+	copiedPeer.ConnectionSessions[0].StatusUpdates[0].HeadSlot = 999
 
 	// Verify original is unchanged
 	originalPeer, _ := repo.GetPeer(peerID)
@@ -116,7 +132,7 @@ func TestDeepCopy(t *testing.T) {
 		t.Error("Deep copy failed: original peer was modified")
 	}
 
-	if originalPeer.ConnectionSessions[0].MessageCount == 999 {
+	if originalPeer.ConnectionSessions[0].MessageCount == 999 || originalPeer.ConnectionSessions[0].StatusUpdates[0].HeadSlot == 999 {
 		t.Error("Deep copy failed: original session was modified")
 	}
 }
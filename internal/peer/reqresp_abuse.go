@@ -0,0 +1,165 @@
+package peer
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Req/resp abuse kinds, requests beyond the protocol's rate limit and frames we could not read.
+const (
+	ReqRespAbuseRateLimit = "rate_limited"
+	ReqRespAbuseMalformed = "malformed"
+)
+
+// reqRespDecodePrefix is how Hermes wraps the error of decoding a request frame read from the
+// peer's stream, e.g. "read request data *pb.Status: snappy: corrupt input".
+const reqRespDecodePrefix = "read request data "
+
+// reqRespDecodeErrors are the messages of the errors decoding a request frame returns when the
+// peer's bytes are bad: Prysm's ssz_snappy encoder for the length prefix, golang/snappy for the
+// compressed body and fastssz or the request type for the SSZ payload. Reading the stream can
+// also fail on timeouts or resets, which say nothing about the frame.
+var reqRespDecodeErrors = []string{
+	"provided header exceeds the max varint length",
+	"varint did not decode entire byte slice",
+	"goes over the provided max limit of",
+	"snappy: corrupt input",
+	"snappy: decoded block is too large",
+	"snappy: unsupported input",
+	"snappy: unsupported literal length",
+	"incorrect size",
+	"incorrect offset",
+	"offset exceeds size of buffer",
+	"offset is less than previous offset",
+	"buffer too small to hold an offset",
+	"list offsets must be multiples of the offset size",
+	"list length longer than ssz max length",
+	"list length is higher than max value",
+	"bytes array does not have the correct length",
+	"vector does not have the correct length",
+	"invalid encoding",
+	"invalid ssz encoding",
+	"expected buffer of length",
+}
+
+// ClassifyReqRespError maps the error Hermes traced while handling a peer's request to an
+// abuse kind. Only the decode errors of the request frame Hermes read from the peer count as
+// malformed, errors from our own handler or beacon node do not.
+func ClassifyReqRespError(reason string) (string, bool) {
+	lower := strings.ToLower(reason)
+
+	switch {
+	case lower == "":
+		return "", false
+	case strings.Contains(lower, "rate limit"), strings.Contains(lower, "too many requests"):
+		return ReqRespAbuseRateLimit, true
+	}
+
+	_, cause, found := strings.Cut(reason, reqRespDecodePrefix)
+	if !found {
+		return "", false
+	}
+
+	for _, decodeErr := range reqRespDecodeErrors {
+		if strings.Contains(cause, decodeErr) {
+			return ReqRespAbuseMalformed, true
+		}
+	}
+
+	return "", false
+}
+
+// RecordReqRespAbuse adds a classified req/resp abuse occurrence to the peer's statistics.
+func (s *Stats) RecordReqRespAbuse(kind, protocol, reason string, at time.Time) {
+	if s.ReqRespAbuse == nil {
+		s.ReqRespAbuse = &ReqRespAbuseStats{
+			ByKind:     make(map[string]int),
+			ByProtocol: make(map[string]int),
+		}
+	}
+
+	s.ReqRespAbuse.Total++
+	s.ReqRespAbuse.ByKind[kind]++
+	s.ReqRespAbuse.ByProtocol[protocol]++
+	s.ReqRespAbuse.LastReason = reason
+	s.ReqRespAbuse.LastSeenAt = &at
+}
+
+// ReqRespAbuser summarises the req/resp abuse attributed to a single peer.
+type ReqRespAbuser struct {
+	PeerID     string         `json:"peer_id"`
+	ClientType string         `json:"client_type"`
+	Total      int            `json:"total"`
+	ByKind     map[string]int `json:"by_kind"`
+	ByProtocol map[string]int `json:"by_protocol"`
+}
+
+// TopReqRespAbusers returns up to limit peers with the most req/resp abuse, worst first.
+func TopReqRespAbusers(peers map[string]*Stats, limit int) []ReqRespAbuser {
+	abusers := make([]ReqRespAbuser, 0)
+
+	for peerID, stats := range peers {
+		if stats == nil || stats.ReqRespAbuse == nil || stats.ReqRespAbuse.Total == 0 {
+			continue
+		}
+
+		abusers = append(abusers, ReqRespAbuser{
+			PeerID:     peerID,
+			ClientType: stats.ClientType,
+			Total:      stats.ReqRespAbuse.Total,
+			ByKind:     stats.ReqRespAbuse.ByKind,
+			ByProtocol: stats.ReqRespAbuse.ByProtocol,
+		})
+	}
+
+	sort.Slice(abusers, func(i, j int) bool {
+		if abusers[i].Total != abusers[j].Total {
+			return abusers[i].Total > abusers[j].Total
+		}
+
+		return abusers[i].PeerID < abusers[j].PeerID
+	})
+
+	if limit > 0 && len(abusers) > limit {
+		abusers = abusers[:limit]
+	}
+
+	return abusers
+}
+
+// ReqRespAbuseByClient counts req/resp abuse per client type and kind.
+func ReqRespAbuseByClient(peers map[string]*Stats) map[string]map[string]int {
+	byClient := make(map[string]map[string]int)
+
+	for _, stats := range peers {
+		if stats == nil || stats.ReqRespAbuse == nil || stats.ReqRespAbuse.Total == 0 {
+			continue
+		}
+
+		client := stats.ClientType
+		if client == "" {
+			client = "unknown"
+		}
+
+		if byClient[client] == nil {
+			byClient[client] = make(map[string]int)
+		}
+
+		for kind, count := range stats.ReqRespAbuse.ByKind {
+			byClient[client][kind] += count
+		}
+	}
+
+	return byClient
+}
+
+// TopReqRespAbusersFromInterface returns the peers with the most req/resp abuse in generic peer data.
+func TopReqRespAbusersFromInterface(peers map[string]interface{}, limit int) []ReqRespAbuser {
+	return TopReqRespAbusers(statsFromInterface(peers), limit)
+}
+
+// ReqRespAbuseByClientFromInterface counts req/resp abuse per client type in generic peer data.
+func ReqRespAbuseByClientFromInterface(peers map[string]interface{}) map[string]map[string]int {
+	return ReqRespAbuseByClient(statsFromInterface(peers))
+}
@@ -0,0 +1,135 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyReqRespError(t *testing.T) {
+	tests := []struct {
+		name         string
+		reason       string
+		expectedKind string
+		expectedOK   bool
+	}{
+		{
+			name:         "undecodable request",
+			reason:       "read status data from delegate: read request data *pb.Status: snappy: corrupt input",
+			expectedKind: ReqRespAbuseMalformed,
+			expectedOK:   true,
+		},
+		{
+			name:         "oversized frame",
+			reason:       "read request data *pb.Status: remaining bytes 20971520 goes over the provided max limit of 10485760",
+			expectedKind: ReqRespAbuseMalformed,
+			expectedOK:   true,
+		},
+		{
+			name:         "wrong ssz size",
+			reason:       "read status data from delegate: read request data *pb.Status: incorrect size",
+			expectedKind: ReqRespAbuseMalformed,
+			expectedOK:   true,
+		},
+		{
+			name:         "short ping body",
+			reason:       "read sequence number: read request data *primitives.SSZUint64: expected buffer of length 8 received 3",
+			expectedKind: ReqRespAbuseMalformed,
+			expectedOK:   true,
+		},
+		{
+			name:       "our handler's invalid state",
+			reason:     "write meta data v2: invalid metadata sequence",
+			expectedOK: false,
+		},
+		{
+			name:       "beacon node error",
+			reason:     "copy data from downstream to upstream: response exceeds max size",
+			expectedOK: false,
+		},
+		{
+			name:       "truncated frame",
+			reason:     "read request data *pb.Status: unexpected EOF",
+			expectedOK: false,
+		},
+		{
+			name:         "rate limited",
+			reason:       "rate limit exceeded",
+			expectedKind: ReqRespAbuseRateLimit,
+			expectedOK:   true,
+		},
+		{
+			name:       "read deadline",
+			reason:     "read request data *pb.Status: i/o deadline reached",
+			expectedOK: false,
+		},
+		{
+			name:       "stream reset",
+			reason:     "respond status to upstream: stream reset",
+			expectedOK: false,
+		},
+		{
+			name:       "empty reason",
+			reason:     "",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, ok := ClassifyReqRespError(tt.reason)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok %v, got %v", tt.expectedOK, ok)
+			}
+
+			if kind != tt.expectedKind {
+				t.Errorf("expected kind %q, got %q", tt.expectedKind, kind)
+			}
+		})
+	}
+}
+
+func TestReqRespAbuseAggregates(t *testing.T) {
+	now := time.Now()
+
+	peers := map[string]*Stats{
+		"peer-a": {ClientType: "lighthouse"},
+		"peer-b": {ClientType: "prysm"},
+		"peer-c": {ClientType: "prysm"},
+		"peer-d": {},
+	}
+
+	peers["peer-a"].RecordReqRespAbuse(ReqRespAbuseMalformed, "HANDLE_STATUS", "read request data", now)
+	peers["peer-b"].RecordReqRespAbuse(ReqRespAbuseRateLimit, "HANDLE_PING", "exceeded HANDLE_PING quota", now)
+	peers["peer-b"].RecordReqRespAbuse(ReqRespAbuseRateLimit, "HANDLE_STATUS", "exceeded HANDLE_STATUS quota", now)
+	peers["peer-c"].RecordReqRespAbuse(ReqRespAbuseMalformed, "HANDLE_METADATA", "read request data", now)
+
+	abusers := TopReqRespAbusers(peers, 2)
+	if len(abusers) != 2 {
+		t.Fatalf("expected 2 abusers, got %d", len(abusers))
+	}
+
+	if abusers[0].PeerID != "peer-b" || abusers[0].Total != 2 || len(abusers[0].ByProtocol) != 2 {
+		t.Errorf("expected peer-b with 2 occurrences on 2 protocols first, got %+v", abusers[0])
+	}
+
+	// Ties are broken by peer ID
+	if abusers[1].PeerID != "peer-a" {
+		t.Errorf("expected peer-a second, got %s", abusers[1].PeerID)
+	}
+
+	byClient := ReqRespAbuseByClient(peers)
+	if byClient["prysm"][ReqRespAbuseRateLimit] != 2 || byClient["prysm"][ReqRespAbuseMalformed] != 1 {
+		t.Errorf("unexpected prysm breakdown: %v", byClient["prysm"])
+	}
+
+	if _, ok := byClient["unknown"]; ok {
+		t.Errorf("expected peers without abuse to be left out, got %v", byClient)
+	}
+
+	for _, summary := range SummarizeClients(peers) {
+		want := map[string]int{"lighthouse": 1, "prysm": 3, "unknown": 0}[summary.Client]
+		if summary.ReqRespAbuse != want {
+			t.Errorf("expected %d abuse occurrences for %s, got %d", want, summary.Client, summary.ReqRespAbuse)
+		}
+	}
+}
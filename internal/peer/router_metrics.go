@@ -0,0 +1,278 @@
+package peer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Local gossipsub router event types, as traced by Hermes.
+const (
+	RouterGraft      = "GRAFT"
+	RouterPrune      = "PRUNE"
+	RouterRemovePeer = "REMOVE_PEER"
+	RouterDeliver    = "DELIVER_MESSAGE"
+	RouterDuplicate  = "DUPLICATE_MESSAGE"
+	RouterRecvRPC    = "RECV_RPC"
+	RouterSendRPC    = "SEND_RPC"
+)
+
+// RouterCounts holds the local router's message and gossip control counts over a period.
+type RouterCounts struct {
+	Delivered     int     `json:"delivered"`      // Messages delivered for the first time
+	Duplicates    int     `json:"duplicates"`     // Messages received again after delivery
+	DuplicateRate float64 `json:"duplicate_rate"` // Duplicates per message received
+	IHaveReceived int     `json:"ihave_received"` // Message IDs peers announced to us
+	IWantSent     int     `json:"iwant_sent"`     // Message IDs we requested from peers
+	IWantRatio    float64 `json:"iwant_ratio"`    // IDs requested per ID announced to us
+	IHaveSent     int     `json:"ihave_sent"`     // Message IDs we announced to peers
+	IWantReceived int     `json:"iwant_received"` // Message IDs peers requested from us
+
+	Published       int `json:"published"`        // Messages our node published
+	PublishRejected int `json:"publish_rejected"` // Of those, messages our own validator rejected
+}
+
+// add accumulates other's counts, the rates are left for rates to derive.
+func (c *RouterCounts) add(other RouterCounts) {
+	c.Delivered += other.Delivered
+	c.Duplicates += other.Duplicates
+	c.IHaveReceived += other.IHaveReceived
+	c.IWantSent += other.IWantSent
+	c.IHaveSent += other.IHaveSent
+	c.IWantReceived += other.IWantReceived
+	c.Published += other.Published
+	c.PublishRejected += other.PublishRejected
+}
+
+// rates derives the duplicate rate and IWANT/IHAVE ratio from the counts.
+func (c *RouterCounts) rates() {
+	c.DuplicateRate = 0
+	if received := c.Delivered + c.Duplicates; received > 0 {
+		c.DuplicateRate = float64(c.Duplicates) / float64(received)
+	}
+
+	c.IWantRatio = 0
+	if c.IHaveReceived > 0 {
+		c.IWantRatio = float64(c.IWantSent) / float64(c.IHaveReceived)
+	}
+}
+
+// RouterSample is one time bucket of the local router's behaviour.
+type RouterSample struct {
+	BucketStart time.Time      `json:"bucket_start"`
+	MeshPeers   int            `json:"mesh_peers"` // Mesh peers across all topics at the end of the bucket
+	MeshSizes   map[string]int `json:"mesh_sizes"` // Topic -> mesh peers at the end of the bucket
+	RouterCounts
+}
+
+// RouterTopic summarises one topic's mesh size over the samples since it was first grafted.
+type RouterTopic struct {
+	Topic     string  `json:"topic"`
+	MinMesh   int     `json:"min_mesh"`
+	MaxMesh   int     `json:"max_mesh"`
+	MeanMesh  float64 `json:"mean_mesh"`
+	FinalMesh int     `json:"final_mesh"`
+}
+
+// RouterMetrics is our own node's gossipsub router behaviour over the run, sampled per bucket,
+// so the peers' reactions to us can be read against what we did rather than as a black box.
+type RouterMetrics struct {
+	Start         time.Time      `json:"start"`
+	BucketSeconds float64        `json:"bucket_seconds"`
+	Totals        RouterCounts   `json:"totals"`
+	Topics        []RouterTopic  `json:"topics"` // By topic name
+	Samples       []RouterSample `json:"samples"`
+
+	Publishing *PublishActivity `json:"publishing,omitempty"` // Nil when our node published nothing
+}
+
+// RouterRecorder samples the local router's mesh sizes and gossip counts into fixed-width
+// time buckets as its trace events are processed.
+type RouterRecorder struct {
+	mu       sync.Mutex
+	start    time.Time
+	width    time.Duration
+	mesh     map[string]map[string]struct{} // Topic -> peers in our mesh
+	samples  []RouterSample
+	recorded int
+
+	publishing map[string]*PublishTopic // Topic -> what we published on it
+}
+
+// NewRouterRecorder creates a recorder with buckets of the given width, aligned to start.
+func NewRouterRecorder(start time.Time, width time.Duration) *RouterRecorder {
+	return &RouterRecorder{
+		start: start.Truncate(width),
+		width: width,
+		mesh:  make(map[string]map[string]struct{}),
+	}
+}
+
+// Mesh records a GRAFT or PRUNE of a peer in our mesh for a topic.
+func (r *RouterRecorder) Mesh(eventType, peerID, topic string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.advance(at)
+
+	switch eventType {
+	case RouterGraft:
+		if r.mesh[topic] == nil {
+			r.mesh[topic] = make(map[string]struct{})
+		}
+
+		r.mesh[topic][peerID] = struct{}{}
+	case RouterPrune:
+		if r.mesh[topic] == nil {
+			return
+		}
+
+		delete(r.mesh[topic], peerID)
+	default:
+		return
+	}
+
+	r.samples[len(r.samples)-1].MeshSizes[topic] = len(r.mesh[topic])
+}
+
+// RemovePeer drops a peer the router no longer tracks from every topic mesh.
+func (r *RouterRecorder) RemovePeer(peerID string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.advance(at)
+
+	for topic, members := range r.mesh {
+		if _, exists := members[peerID]; !exists {
+			continue
+		}
+
+		delete(members, peerID)
+		r.samples[len(r.samples)-1].MeshSizes[topic] = len(members)
+	}
+}
+
+// Message counts a message the router delivered or received again as a duplicate.
+func (r *RouterRecorder) Message(duplicate bool, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sample := r.advance(at)
+
+	if duplicate {
+		sample.Duplicates++
+	} else {
+		sample.Delivered++
+	}
+}
+
+// Control counts the IHAVE and IWANT message IDs of an RPC we sent or received.
+func (r *RouterRecorder) Control(sent bool, ihave, iwant int, at time.Time) {
+	if ihave == 0 && iwant == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sample := r.advance(at)
+
+	if sent {
+		sample.IHaveSent += ihave
+		sample.IWantSent += iwant
+	} else {
+		sample.IHaveReceived += ihave
+		sample.IWantReceived += iwant
+	}
+}
+
+// advance adds the buckets up to the one at falls into, carrying the mesh sizes forward, and
+// returns that bucket. Events stamped before the first bucket are counted in it.
+func (r *RouterRecorder) advance(at time.Time) *RouterSample {
+	index := 0
+	if at.After(r.start) {
+		index = int(at.Sub(r.start) / r.width)
+	}
+
+	r.recorded++
+
+	for len(r.samples) <= index {
+		sizes := make(map[string]int, len(r.mesh))
+		for topic, members := range r.mesh {
+			sizes[topic] = len(members)
+		}
+
+		r.samples = append(r.samples, RouterSample{
+			BucketStart: r.start.Add(time.Duration(len(r.samples)) * r.width),
+			MeshSizes:   sizes,
+		})
+	}
+
+	return &r.samples[index]
+}
+
+// Snapshot returns the samples up to end, or nil if no router event was recorded.
+func (r *RouterRecorder) Snapshot(end time.Time) *RouterMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recorded == 0 {
+		return nil
+	}
+
+	// Sample the buckets without router events up to the end of the run
+	recorded := r.recorded
+	r.advance(end)
+	r.recorded = recorded
+
+	metrics := &RouterMetrics{
+		Start:         r.start,
+		BucketSeconds: r.width.Seconds(),
+		Topics:        make([]RouterTopic, 0),
+		Samples:       make([]RouterSample, 0, len(r.samples)),
+	}
+
+	topics := make(map[string]*RouterTopic)
+	buckets := make(map[string]int)
+
+	for _, sample := range r.samples {
+		copied := sample
+		copied.MeshSizes = make(map[string]int, len(sample.MeshSizes))
+		copied.rates()
+
+		for topic, size := range sample.MeshSizes {
+			copied.MeshSizes[topic] = size
+			copied.MeshPeers += size
+
+			summary, exists := topics[topic]
+			if !exists {
+				summary = &RouterTopic{Topic: topic, MinMesh: size}
+				topics[topic] = summary
+			}
+
+			summary.MinMesh = min(summary.MinMesh, size)
+			summary.MaxMesh = max(summary.MaxMesh, size)
+			summary.MeanMesh += float64(size)
+			summary.FinalMesh = size
+			buckets[topic]++
+		}
+
+		metrics.Totals.add(sample.RouterCounts)
+		metrics.Samples = append(metrics.Samples, copied)
+	}
+
+	metrics.Totals.rates()
+
+	for topic, summary := range topics {
+		summary.MeanMesh /= float64(buckets[topic])
+		metrics.Topics = append(metrics.Topics, *summary)
+	}
+
+	sort.Slice(metrics.Topics, func(i, j int) bool {
+		return metrics.Topics[i].Topic < metrics.Topics[j].Topic
+	})
+
+	metrics.Publishing = r.publishActivity(metrics.Samples)
+
+	return metrics
+}
@@ -0,0 +1,72 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRouterRecorder(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	recorder := NewRouterRecorder(start, time.Minute)
+
+	if recorder.Snapshot(at(60)) != nil {
+		t.Fatal("Expected no metrics before any router event")
+	}
+
+	// Bucket 0: two peers grafted, three deliveries and a duplicate, gossip both ways
+	recorder.Mesh(RouterGraft, "a", "beacon_block", at(1))
+	recorder.Mesh(RouterGraft, "b", "beacon_block", at(2))
+	recorder.Message(false, at(3))
+	recorder.Message(false, at(4))
+	recorder.Message(false, at(5))
+	recorder.Message(true, at(6))
+	recorder.Control(false, 10, 0, at(7))
+	recorder.Control(true, 4, 5, at(8))
+
+	// Bucket 2: one peer pruned, the other removed, a prune for an unknown topic is ignored
+	recorder.Mesh(RouterPrune, "a", "beacon_block", at(130))
+	recorder.Mesh(RouterPrune, "a", "voluntary_exit", at(131))
+	recorder.Mesh(RouterGraft, "c", "voluntary_exit", at(132))
+	recorder.RemovePeer("b", at(133))
+
+	metrics := recorder.Snapshot(at(250))
+
+	if len(metrics.Samples) != 5 {
+		t.Fatalf("Expected 5 samples up to the end of the run, got %d", len(metrics.Samples))
+	}
+
+	meshPeers := []int{2, 2, 1, 1, 1}
+	for i, want := range meshPeers {
+		if metrics.Samples[i].MeshPeers != want {
+			t.Errorf("Sample %d: got %d mesh peers, want %d", i, metrics.Samples[i].MeshPeers, want)
+		}
+	}
+
+	first := metrics.Samples[0]
+	if first.Delivered != 3 || first.Duplicates != 1 || first.DuplicateRate != 0.25 || first.IWantSent != 5 || first.IWantRatio != 0.5 {
+		t.Errorf("Unexpected first sample %+v", first)
+	}
+
+	if metrics.Totals.IHaveReceived != 10 || metrics.Totals.IHaveSent != 4 || metrics.Totals.DuplicateRate != 0.25 {
+		t.Errorf("Unexpected totals %+v", metrics.Totals)
+	}
+
+	want := []RouterTopic{
+		{Topic: "beacon_block", MinMesh: 0, MaxMesh: 2, MeanMesh: 0.8, FinalMesh: 0},
+		{Topic: "voluntary_exit", MinMesh: 1, MaxMesh: 1, MeanMesh: 1, FinalMesh: 1},
+	}
+
+	if len(metrics.Topics) != len(want) {
+		t.Fatalf("Expected %d topics, got %+v", len(want), metrics.Topics)
+	}
+
+	for i := range want {
+		if metrics.Topics[i] != want[i] {
+			t.Errorf("Topic %d: got %+v, want %+v", i, metrics.Topics[i], want[i])
+		}
+	}
+}
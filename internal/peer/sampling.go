@@ -0,0 +1,209 @@
+package peer
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sort"
+	"time"
+)
+
+// Reasons a peer's full detail is captured. Baseline peers were drawn at random, every
+// other reason marks a peer as interesting, and interesting peers are always captured.
+const (
+	SampleBaseline      = "baseline"
+	SampleReconnected   = "reconnected"
+	SampleGoodbye       = "goodbye"
+	SampleDecodeError   = "decode_error"
+	SampleReqRespAbuse  = "reqresp_abuse"
+	SampleNegativeScore = "negative_score"
+	SampleUnknownClient = "unknown_client"
+	SampleTraced        = "traced"
+)
+
+// DetailSample records whether a peer's full detail (score snapshots, mesh events and
+// event timeline) is captured, and the weight the peer carries in aggregates over that detail.
+type DetailSample struct {
+	Captured   bool       `json:"captured"`
+	Reason     string     `json:"reason,omitempty"`      // Why detail is captured, one of the Sample constants
+	Weight     float64    `json:"weight"`                // 1/rate for baseline peers, 1 for interesting peers, 0 when not captured
+	PromotedAt *time.Time `json:"promoted_at,omitempty"` // When a peer outside the baseline became interesting, detail before it is missing
+}
+
+// DetailSampler draws the random baseline of peers whose full detail is captured. The draw
+// hashes the peer ID with a seed, so it is reproducible across restarts and independent of
+// how the peer behaves.
+type DetailSampler struct {
+	rate   float64
+	seed   int64
+	traced string
+}
+
+// NewDetailSampler creates a sampler capturing the given share of peers as the baseline.
+func NewDetailSampler(rate float64, seed int64) *DetailSampler {
+	return &DetailSampler{rate: rate, seed: seed}
+}
+
+// Rate returns the share of peers drawn into the baseline.
+func (s *DetailSampler) Rate() float64 {
+	return s.rate
+}
+
+// Seed returns the seed the baseline is drawn with.
+func (s *DetailSampler) Seed() int64 {
+	return s.seed
+}
+
+// SetTraced captures the traced peer's detail whatever the draw.
+func (s *DetailSampler) SetTraced(peerID string) {
+	s.traced = peerID
+}
+
+// Decide draws whether a new peer joins the baseline.
+func (s *DetailSampler) Decide(peerID string) *DetailSample {
+	if s.traced != "" && peerID == s.traced {
+		return &DetailSample{Captured: true, Reason: SampleTraced, Weight: 1}
+	}
+
+	if s.draw(peerID) < s.rate {
+		return &DetailSample{Captured: true, Reason: SampleBaseline, Weight: 1 / s.rate}
+	}
+
+	return &DetailSample{}
+}
+
+// draw maps the peer ID to a uniform value in [0, 1).
+func (s *DetailSampler) draw(peerID string) float64 {
+	var seed [8]byte
+
+	binary.BigEndian.PutUint64(seed[:], uint64(s.seed))
+
+	h := fnv.New64a()
+	_, _ = h.Write(seed[:])
+	_, _ = h.Write([]byte(peerID))
+
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
+
+// CapturesDetail reports whether the peer's full detail is recorded. Peers are always
+// captured when sampling is disabled.
+func (s *Stats) CapturesDetail() bool {
+	return s.Sample == nil || s.Sample.Captured
+}
+
+// DetailWeight returns the weight of the peer's detail in aggregates, so that the captured
+// peers stand in for the ones that were not.
+func (s *Stats) DetailWeight() float64 {
+	if s.Sample == nil {
+		return 1
+	}
+
+	return s.Sample.Weight
+}
+
+// MarkInteresting moves the peer into the interesting stratum, capturing its detail from
+// now on. Interesting peers are captured whatever the draw, so they carry a weight of 1.
+func (s *Stats) MarkInteresting(reason string, at time.Time) {
+	if s.Sample == nil || (s.Sample.Captured && s.Sample.Reason != SampleBaseline) {
+		return
+	}
+
+	if !s.Sample.Captured {
+		s.Sample.Captured = true
+		s.Sample.PromotedAt = &at
+	}
+
+	s.Sample.Reason = reason
+	s.Sample.Weight = 1
+}
+
+// SamplingSummary describes how peers were sampled for detailed capture.
+type SamplingSummary struct {
+	Rate           float64        `json:"rate"`
+	Seed           int64          `json:"seed"`
+	Peers          int            `json:"peers"`
+	Captured       int            `json:"captured"`
+	Baseline       int            `json:"baseline"`        // Captured as part of the random baseline only
+	Interesting    int            `json:"interesting"`     // Captured because of their behaviour
+	Promoted       int            `json:"promoted"`        // Interesting peers captured only from the moment they became interesting
+	Reasons        map[string]int `json:"reasons"`         // Interesting peers per reason
+	EstimatedPeers float64        `json:"estimated_peers"` // Sum of weights, should be close to Peers
+}
+
+// SummarizeSampling counts the peers in each sampling stratum.
+func SummarizeSampling(peers map[string]*Stats, rate float64, seed int64) *SamplingSummary {
+	summary := &SamplingSummary{
+		Rate:    rate,
+		Seed:    seed,
+		Reasons: make(map[string]int),
+	}
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		summary.Peers++
+
+		if !stats.CapturesDetail() {
+			continue
+		}
+
+		summary.Captured++
+		summary.EstimatedPeers += stats.DetailWeight()
+
+		if stats.Sample == nil || stats.Sample.Reason == SampleBaseline {
+			summary.Baseline++
+
+			continue
+		}
+
+		summary.Interesting++
+		summary.Reasons[stats.Sample.Reason]++
+
+		if stats.Sample.PromotedAt != nil {
+			summary.Promoted++
+		}
+	}
+
+	return summary
+}
+
+// WeightedMedian returns the median of values under the given weights, averaging the two
+// middle values when the cumulative weight splits exactly between them.
+func WeightedMedian(values, weights []float64) float64 {
+	order := make([]int, 0, len(values))
+	total := 0.0
+
+	for i := range values {
+		if weights[i] > 0 {
+			order = append(order, i)
+			total += weights[i]
+		}
+	}
+
+	if total <= 0 {
+		return 0
+	}
+
+	sort.Slice(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+
+	half := total / 2
+	cumulative := 0.0
+
+	for i, idx := range order {
+		cumulative += weights[idx]
+
+		if math.Abs(cumulative-half) <= 1e-9*total && i+1 < len(order) {
+			return (values[idx] + values[order[i+1]]) / 2
+		}
+
+		if cumulative > half {
+			return values[idx]
+		}
+	}
+
+	return values[order[len(order)-1]]
+}
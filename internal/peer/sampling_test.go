@@ -0,0 +1,144 @@
+package peer
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDetailSampler(t *testing.T) {
+	sampler := NewDetailSampler(0.1, 42)
+
+	captured := 0
+
+	for i := 0; i < 10000; i++ {
+		peerID := fmt.Sprintf("16Uiu2HAm%d", i)
+
+		sample := sampler.Decide(peerID)
+		if *sample != *sampler.Decide(peerID) {
+			t.Fatalf("expected the same decision for %s on every draw", peerID)
+		}
+
+		if !sample.Captured {
+			if sample.Weight != 0 {
+				t.Errorf("expected uncaptured peers to carry no weight, got %f", sample.Weight)
+			}
+
+			continue
+		}
+
+		captured++
+
+		if sample.Reason != SampleBaseline || math.Abs(sample.Weight-10) > 1e-9 {
+			t.Errorf("expected baseline peers weighted by the inverse rate, got %+v", sample)
+		}
+	}
+
+	if captured < 900 || captured > 1100 {
+		t.Errorf("expected about 1000 baseline peers at a rate of 0.1, got %d", captured)
+	}
+
+	other := NewDetailSampler(0.1, 43)
+	differs := false
+
+	for i := 0; i < 100 && !differs; i++ {
+		peerID := fmt.Sprintf("16Uiu2HAm%d", i)
+		differs = sampler.Decide(peerID).Captured != other.Decide(peerID).Captured
+	}
+
+	if !differs {
+		t.Error("expected another seed to draw another baseline")
+	}
+
+	// The traced peer is captured whatever the draw
+	for i := 0; i < 100; i++ {
+		peerID := fmt.Sprintf("16Uiu2HAm%d", i)
+		if sampler.Decide(peerID).Captured {
+			continue
+		}
+
+		sampler.SetTraced(peerID)
+
+		if sample := sampler.Decide(peerID); !sample.Captured || sample.Reason != SampleTraced || sample.Weight != 1 {
+			t.Errorf("expected the traced peer captured as interesting, got %+v", sample)
+		}
+
+		break
+	}
+}
+
+func TestMarkInteresting(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		sample   *DetailSample
+		expected *DetailSample
+	}{
+		{
+			name:     "sampling disabled",
+			sample:   nil,
+			expected: nil,
+		},
+		{
+			name:     "uncaptured peer is promoted",
+			sample:   &DetailSample{},
+			expected: &DetailSample{Captured: true, Reason: SampleGoodbye, Weight: 1, PromotedAt: &at},
+		},
+		{
+			name:     "baseline peer moves to the interesting stratum",
+			sample:   &DetailSample{Captured: true, Reason: SampleBaseline, Weight: 10},
+			expected: &DetailSample{Captured: true, Reason: SampleGoodbye, Weight: 1},
+		},
+		{
+			name:     "first reason is kept",
+			sample:   &DetailSample{Captured: true, Reason: SampleReconnected, Weight: 1},
+			expected: &DetailSample{Captured: true, Reason: SampleReconnected, Weight: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &Stats{Sample: tt.sample}
+			stats.MarkInteresting(SampleGoodbye, at)
+
+			if (stats.Sample == nil) != (tt.expected == nil) {
+				t.Fatalf("MarkInteresting() sample = %+v, want %+v", stats.Sample, tt.expected)
+			}
+
+			if stats.Sample == nil {
+				return
+			}
+
+			got, want := *stats.Sample, *tt.expected
+			if (got.PromotedAt == nil) != (want.PromotedAt == nil) {
+				t.Fatalf("MarkInteresting() promoted at = %v, want %v", got.PromotedAt, want.PromotedAt)
+			}
+
+			got.PromotedAt, want.PromotedAt = nil, nil
+			if got != want {
+				t.Errorf("MarkInteresting() sample = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSummarizeSampling(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	summary := SummarizeSampling(map[string]*Stats{
+		"baseline":    {Sample: &DetailSample{Captured: true, Reason: SampleBaseline, Weight: 10}},
+		"promoted":    {Sample: &DetailSample{Captured: true, Reason: SampleGoodbye, Weight: 1, PromotedAt: &at}},
+		"interesting": {Sample: &DetailSample{Captured: true, Reason: SampleReconnected, Weight: 1}},
+		"skipped":     {Sample: &DetailSample{}},
+	}, 0.1, 42)
+
+	if summary.Peers != 4 || summary.Captured != 3 || summary.Baseline != 1 || summary.Interesting != 2 || summary.Promoted != 1 {
+		t.Errorf("unexpected sampling summary: %+v", summary)
+	}
+
+	if summary.EstimatedPeers != 12 || summary.Reasons[SampleGoodbye] != 1 {
+		t.Errorf("unexpected sampling weights: %+v", summary)
+	}
+}
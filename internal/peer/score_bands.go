@@ -0,0 +1,208 @@
+package peer
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// ScorePercentiles holds the 10th, 50th and 90th percentile of a set of peer scores.
+type ScorePercentiles struct {
+	P10 float64 `json:"p10"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+}
+
+// ScoreBandBucket holds the score percentiles across the peers scored within one time bucket.
+type ScoreBandBucket struct {
+	Start time.Time        `json:"start"`
+	Peers int              `json:"peers"`
+	Min   ScorePercentiles `json:"min"`  // Of each peer's lowest score in the bucket
+	Mean  ScorePercentiles `json:"mean"` // Of each peer's mean score in the bucket
+}
+
+// ScoreBands summarises how well Hermes' peers score across the run, and how close the
+// worst of them come to the gossipsub thresholds.
+type ScoreBands struct {
+	Peers         int               `json:"peers"` // Peers with at least one score snapshot
+	Snapshots     int               `json:"snapshots"`
+	Min           ScorePercentiles  `json:"min"`  // Of each peer's lowest score over the run
+	Mean          ScorePercentiles  `json:"mean"` // Of each peer's mean score over the run
+	BucketSeconds float64           `json:"bucket_seconds"`
+	Buckets       []ScoreBandBucket `json:"buckets"`
+	BelowGossip   int               `json:"below_gossip"`   // Peers whose lowest score fell below the gossip threshold
+	BelowPublish  int               `json:"below_publish"`  // Peers whose lowest score fell below the publish threshold
+	BelowGraylist int               `json:"below_graylist"` // Peers whose lowest score fell below the graylist threshold
+}
+
+// scoreAccumulator tracks the lowest and mean score of one peer.
+type scoreAccumulator struct {
+	min   float64
+	sum   float64
+	count int
+}
+
+func (a *scoreAccumulator) add(score float64) {
+	if a.count == 0 || score < a.min {
+		a.min = score
+	}
+
+	a.sum += score
+	a.count++
+}
+
+// CalculateScoreBands computes score percentiles across peers over the whole run and per
+// time bucket from start. Percentiles weight peers by their detail sampling weight.
+func CalculateScoreBands(peers map[string]*Stats, start time.Time, width time.Duration) *ScoreBands {
+	bands := &ScoreBands{
+		BucketSeconds: width.Seconds(),
+		Buckets:       make([]ScoreBandBucket, 0),
+	}
+
+	var (
+		runMins, runMeans, runWeights []float64
+		bucketScores                  = make(map[int]map[string]*scoreAccumulator)
+		bucketWeights                 = make(map[string]float64)
+	)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		run := &scoreAccumulator{}
+
+		for _, session := range stats.ConnectionSessions {
+			for _, snapshot := range session.PeerScores {
+				run.add(snapshot.Score)
+
+				bucket := 0
+				if width > 0 && snapshot.Timestamp.After(start) {
+					bucket = int(snapshot.Timestamp.Sub(start) / width)
+				}
+
+				if bucketScores[bucket] == nil {
+					bucketScores[bucket] = make(map[string]*scoreAccumulator)
+				}
+
+				if bucketScores[bucket][peerID] == nil {
+					bucketScores[bucket][peerID] = &scoreAccumulator{}
+				}
+
+				bucketScores[bucket][peerID].add(snapshot.Score)
+			}
+		}
+
+		if run.count == 0 {
+			continue
+		}
+
+		bands.Peers++
+		bands.Snapshots += run.count
+		runMins = append(runMins, run.min)
+		runMeans = append(runMeans, run.sum/float64(run.count))
+		runWeights = append(runWeights, stats.DetailWeight())
+		bucketWeights[peerID] = stats.DetailWeight()
+
+		switch {
+		case run.min < constants.GraylistScoreThreshold:
+			bands.BelowGraylist++
+
+			fallthrough
+		case run.min < constants.PublishScoreThreshold:
+			bands.BelowPublish++
+
+			fallthrough
+		case run.min < constants.GossipScoreThreshold:
+			bands.BelowGossip++
+		}
+	}
+
+	bands.Min = scorePercentiles(runMins, runWeights)
+	bands.Mean = scorePercentiles(runMeans, runWeights)
+
+	indexes := make([]int, 0, len(bucketScores))
+	for bucket := range bucketScores {
+		indexes = append(indexes, bucket)
+	}
+
+	sort.Ints(indexes)
+
+	for _, bucket := range indexes {
+		var mins, means, weights []float64
+
+		for peerID, acc := range bucketScores[bucket] {
+			mins = append(mins, acc.min)
+			means = append(means, acc.sum/float64(acc.count))
+			weights = append(weights, bucketWeights[peerID])
+		}
+
+		bands.Buckets = append(bands.Buckets, ScoreBandBucket{
+			Start: start.Add(time.Duration(bucket) * width),
+			Peers: len(mins),
+			Min:   scorePercentiles(mins, weights),
+			Mean:  scorePercentiles(means, weights),
+		})
+	}
+
+	return bands
+}
+
+// ScoreBandsFromInterface computes score bands from report peer data.
+func ScoreBandsFromInterface(peers map[string]interface{}, start time.Time, width time.Duration) *ScoreBands {
+	return CalculateScoreBands(statsFromInterface(peers), start, width)
+}
+
+// scorePercentiles returns the 10th, 50th and 90th weighted percentile of the scores.
+func scorePercentiles(scores, weights []float64) ScorePercentiles {
+	return ScorePercentiles{
+		P10: weightedPercentile(scores, weights, 0.1),
+		P50: weightedPercentile(scores, weights, 0.5),
+		P90: weightedPercentile(scores, weights, 0.9),
+	}
+}
+
+// weightedPercentile returns the nearest rank percentile p of the values under the given
+// weights: the lowest value whose cumulative weight reaches p of the total.
+func weightedPercentile(values, weights []float64, p float64) float64 {
+	order := make([]int, 0, len(values))
+	total := 0.0
+
+	for i := range values {
+		if weights[i] > 0 {
+			order = append(order, i)
+			total += weights[i]
+		}
+	}
+
+	if total <= 0 {
+		return 0
+	}
+
+	sort.Slice(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+
+	target := p * total
+	cumulative := 0.0
+
+	for _, idx := range order {
+		cumulative += weights[idx]
+
+		if cumulative >= target-1e-9*total {
+			return values[idx]
+		}
+	}
+
+	return values[order[len(order)-1]]
+}
+
+// ScoreBandWidth returns the bucket width that splits a run into at most the configured
+// number of score band buckets, and no less than a minute.
+func ScoreBandWidth(duration time.Duration) time.Duration {
+	width := time.Duration(math.Ceil(float64(duration) / constants.ScoreBandBuckets))
+
+	return max(width, time.Minute)
+}
@@ -0,0 +1,106 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeightedPercentile(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		weights []float64
+		p       float64
+		want    float64
+	}{
+		{name: "empty", p: 0.5, want: 0},
+		{name: "p10 of ten", values: []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, weights: []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, p: 0.1, want: 1},
+		{name: "p50 of ten", values: []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, weights: []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, p: 0.5, want: 5},
+		{name: "p90 of ten", values: []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, weights: []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, p: 0.9, want: 9},
+		{name: "heavy value dominates", values: []float64{-100, 5}, weights: []float64{1, 9}, p: 0.1, want: -100},
+		{name: "heavy value dominates median", values: []float64{-100, 5}, weights: []float64{1, 9}, p: 0.5, want: 5},
+		{name: "zero weight ignored", values: []float64{-100, 5}, weights: []float64{0, 1}, p: 0.1, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weightedPercentile(tt.values, tt.weights, tt.p); got != tt.want {
+				t.Errorf("weightedPercentile() = %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreBands(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	snapshots := func(offsets []int, scores ...float64) []ConnectionSession {
+		session := ConnectionSession{}
+		for i, score := range scores {
+			session.PeerScores = append(session.PeerScores, PeerScoreSnapshot{
+				Timestamp: start.Add(time.Duration(offsets[i]) * time.Minute),
+				Score:     score,
+			})
+		}
+
+		return []ConnectionSession{session}
+	}
+
+	peers := map[string]*Stats{
+		"healthy":    {ConnectionSessions: snapshots([]int{0, 1}, 10, 20)},
+		"gossip":     {ConnectionSessions: snapshots([]int{0}, -5000)},
+		"publish":    {ConnectionSessions: snapshots([]int{1}, -9000)},
+		"graylisted": {ConnectionSessions: snapshots([]int{1}, -20000)},
+		"unscored":   {ConnectionSessions: []ConnectionSession{{}}},
+		"nil":        nil,
+	}
+
+	bands := CalculateScoreBands(peers, start, time.Minute)
+
+	if bands.Peers != 4 || bands.Snapshots != 5 {
+		t.Fatalf("expected 4 scored peers with 5 snapshots, got %d and %d", bands.Peers, bands.Snapshots)
+	}
+
+	if bands.BelowGossip != 3 || bands.BelowPublish != 2 || bands.BelowGraylist != 1 {
+		t.Errorf("unexpected threshold counts: gossip %d, publish %d, graylist %d", bands.BelowGossip, bands.BelowPublish, bands.BelowGraylist)
+	}
+
+	if bands.Min.P10 != -20000 || bands.Min.P90 != 10 || bands.Mean.P90 != 15 {
+		t.Errorf("unexpected run percentiles: min %+v, mean %+v", bands.Min, bands.Mean)
+	}
+
+	if len(bands.Buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(bands.Buckets))
+	}
+
+	if bands.Buckets[0].Peers != 2 || bands.Buckets[1].Peers != 3 || !bands.Buckets[1].Start.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected buckets: %+v", bands.Buckets)
+	}
+
+	// A sampled baseline peer stands in for the peers that were not captured
+	peers["healthy"].Sample = &DetailSample{Captured: true, Reason: SampleBaseline, Weight: 10}
+
+	if weighted := CalculateScoreBands(peers, start, time.Minute); weighted.Min.P50 != 10 {
+		t.Errorf("expected the weighted baseline peer to set the median lowest score, got %+v", weighted.Min)
+	}
+}
+
+func TestScoreBandWidth(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     time.Duration
+	}{
+		{duration: 0, want: time.Minute},
+		{duration: 10 * time.Minute, want: time.Minute},
+		{duration: 2 * time.Hour, want: 2 * time.Minute},
+		{duration: 5 * time.Hour, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration.String(), func(t *testing.T) {
+			if got := ScoreBandWidth(tt.duration); got != tt.want {
+				t.Errorf("ScoreBandWidth(%s) = %s, want %s", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
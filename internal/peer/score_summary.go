@@ -0,0 +1,213 @@
+package peer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/glossary"
+)
+
+// SessionScoreSummary condenses a session's score snapshots into time-weighted figures, which
+// a single outlying snapshot moves far less than the session's lowest or highest score. Each
+// snapshot's score is taken to hold until the next snapshot, or until the session ended.
+type SessionScoreSummary struct {
+	Snapshots           int     `json:"snapshots"`
+	ScoredSeconds       float64 `json:"scored_seconds"`        // From the first snapshot to the end of the session
+	TimeWeightedMean    float64 `json:"time_weighted_mean"`    // Mean score over the scored seconds
+	AreaBelowZero       float64 `json:"area_below_zero"`       // Negative scores integrated over time, in score seconds, zero or less
+	SecondsBelowPublish float64 `json:"seconds_below_publish"` // Time spent below the publish threshold
+}
+
+// SessionScoreDefinitions define the SessionScoreSummary metrics.
+var SessionScoreDefinitions = []glossary.Definition{
+	{
+		Name:    "Time-Weighted Mean Score",
+		Field:   "time_weighted_mean",
+		Formula: "Σ score × seconds held / scored seconds, each snapshot held until the next or the end of the session",
+		Caveats: []string{
+			"Snapshots arriving after the disconnect, or taken while delegated validation's beacon node was degraded, are left out",
+			"The plain mean of the snapshots when they all fall at the very end of the session",
+		},
+	},
+	{
+		Name:    "Score Area Below Zero",
+		Field:   "area_below_zero",
+		Formula: "Σ score × seconds held over the negative snapshots, in score seconds",
+		Caveats: []string{"Zero or less, so the worst peers have the most negative area"},
+	},
+	{
+		Name:    "Seconds Below Publish",
+		Field:   "seconds_below_publish",
+		Formula: "Σ seconds held by snapshots below the publish threshold",
+	},
+}
+
+// SummarizeSessionScores adds a score summary to every session with score snapshots. Sessions
+// still open are taken to end at end. Snapshots that arrived after the disconnect are left out,
+// and so are those taken while delegated validation's beacon node was degraded.
+func SummarizeSessionScores(peers map[string]*Stats, end time.Time) {
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+
+			sessionEnd := end
+			if session.DisconnectedAt != nil {
+				sessionEnd = *session.DisconnectedAt
+			}
+
+			session.ScoreSummary = SummarizeScores(session.PeerScores, sessionEnd)
+		}
+	}
+}
+
+// SummarizeScores summarises score snapshots taken up to end, nil when there are none.
+func SummarizeScores(snapshots []PeerScoreSnapshot, end time.Time) *SessionScoreSummary {
+	var (
+		summary  SessionScoreSummary
+		weighted float64
+		sum      float64
+	)
+
+	for i, snapshot := range snapshots {
+		if snapshot.PostDisconnect {
+			continue
+		}
+
+		// A degraded beacon node ends the previous score's hold without holding a score itself,
+		// its failures are ours and not the peer's
+		if snapshot.BeaconDegraded {
+			continue
+		}
+
+		until := end
+		for _, next := range snapshots[i+1:] {
+			if !next.PostDisconnect {
+				until = next.Timestamp
+
+				break
+			}
+		}
+
+		// Out of order or trailing snapshots hold for no time at all
+		seconds := max(until.Sub(snapshot.Timestamp).Seconds(), 0)
+
+		summary.Snapshots++
+		summary.ScoredSeconds += seconds
+		weighted += snapshot.Score * seconds
+		sum += snapshot.Score
+
+		if snapshot.Score < 0 {
+			summary.AreaBelowZero += snapshot.Score * seconds
+		}
+
+		if snapshot.Score < constants.PublishScoreThreshold {
+			summary.SecondsBelowPublish += seconds
+		}
+	}
+
+	if summary.Snapshots == 0 {
+		return nil
+	}
+
+	// Snapshots taken at the very end of a session carry no time, fall back to their plain mean
+	if summary.ScoredSeconds > 0 {
+		summary.TimeWeightedMean = weighted / summary.ScoredSeconds
+	} else {
+		summary.TimeWeightedMean = sum / float64(summary.Snapshots)
+	}
+
+	return &summary
+}
+
+// ScoreTotals adds up the score summaries of a peer's sessions: the time-weighted mean across
+// them, the area below zero and the seconds below the publish threshold. The last result is
+// false when no session has a summary.
+func (s *Stats) ScoreTotals() (mean, areaBelowZero, secondsBelowPublish float64, ok bool) {
+	var (
+		weighted, seconds, plain float64
+		sessions                 int
+	)
+
+	for _, session := range s.ConnectionSessions {
+		summary := session.ScoreSummary
+		if summary == nil {
+			continue
+		}
+
+		sessions++
+		weighted += summary.TimeWeightedMean * summary.ScoredSeconds
+		seconds += summary.ScoredSeconds
+		plain += summary.TimeWeightedMean
+		areaBelowZero += summary.AreaBelowZero
+		secondsBelowPublish += summary.SecondsBelowPublish
+	}
+
+	if sessions == 0 {
+		return 0, 0, 0, false
+	}
+
+	mean = plain / float64(sessions)
+	if seconds > 0 {
+		mean = weighted / seconds
+	}
+
+	return mean, areaBelowZero, secondsBelowPublish, true
+}
+
+// ScoredPeer summarises how badly a peer scored over the run.
+type ScoredPeer struct {
+	PeerID              string  `json:"peer_id"`
+	ClientType          string  `json:"client_type"`
+	TimeWeightedMean    float64 `json:"time_weighted_mean"`
+	AreaBelowZero       float64 `json:"area_below_zero"`
+	SecondsBelowPublish float64 `json:"seconds_below_publish"`
+}
+
+// WorstScoredPeers returns up to limit peers whose scores spent time below zero, the largest
+// area below zero first.
+func WorstScoredPeers(peers map[string]*Stats, limit int) []ScoredPeer {
+	worst := make([]ScoredPeer, 0)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		mean, area, below, ok := stats.ScoreTotals()
+		if !ok || area >= 0 {
+			continue
+		}
+
+		worst = append(worst, ScoredPeer{
+			PeerID:              peerID,
+			ClientType:          stats.ClientType,
+			TimeWeightedMean:    mean,
+			AreaBelowZero:       area,
+			SecondsBelowPublish: below,
+		})
+	}
+
+	sort.Slice(worst, func(i, j int) bool {
+		if worst[i].AreaBelowZero != worst[j].AreaBelowZero {
+			return worst[i].AreaBelowZero < worst[j].AreaBelowZero
+		}
+
+		return worst[i].PeerID < worst[j].PeerID
+	})
+
+	if limit > 0 && len(worst) > limit {
+		worst = worst[:limit]
+	}
+
+	return worst
+}
+
+// WorstScoredPeersFromInterface returns the worst scored peers in generic peer data.
+func WorstScoredPeersFromInterface(peers map[string]interface{}, limit int) []ScoredPeer {
+	return WorstScoredPeers(statsFromInterface(peers), limit)
+}
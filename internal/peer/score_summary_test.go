@@ -0,0 +1,143 @@
+package peer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSummarizeScores(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int, score float64) PeerScoreSnapshot {
+		return PeerScoreSnapshot{Timestamp: start.Add(time.Duration(seconds) * time.Second), Score: score}
+	}
+
+	// 10s at 5, a 5s dip to -10000, then 45s at 1 up to the end
+	snapshots := []PeerScoreSnapshot{at(0, 5), at(10, -10000), at(15, 1)}
+	late := at(70, -20000)
+	late.PostDisconnect = true
+	snapshots = append(snapshots, late)
+
+	summary := SummarizeScores(snapshots, start.Add(time.Minute))
+	if summary == nil {
+		t.Fatal("Expected a summary")
+	}
+
+	if summary.Snapshots != 3 {
+		t.Errorf("Expected 3 snapshots, post-disconnect left out, got %d", summary.Snapshots)
+	}
+
+	if summary.ScoredSeconds != 60 {
+		t.Errorf("Expected 60 scored seconds, got %f", summary.ScoredSeconds)
+	}
+
+	wantMean := (5*10 - 10000*5 + 1*45) / 60.0
+	if math.Abs(summary.TimeWeightedMean-wantMean) > 1e-9 {
+		t.Errorf("Expected time-weighted mean %f, got %f", wantMean, summary.TimeWeightedMean)
+	}
+
+	if summary.AreaBelowZero != -50000 {
+		t.Errorf("Expected area below zero -50000, got %f", summary.AreaBelowZero)
+	}
+
+	if summary.SecondsBelowPublish != 5 {
+		t.Errorf("Expected 5 seconds below the publish threshold, got %f", summary.SecondsBelowPublish)
+	}
+
+	if SummarizeScores(nil, start) != nil {
+		t.Error("Expected no summary without snapshots")
+	}
+
+	// A single snapshot at the end of a session holds for no time, its score is the mean
+	if single := SummarizeScores([]PeerScoreSnapshot{at(60, -3)}, start.Add(time.Minute)); single.TimeWeightedMean != -3 || single.ScoredSeconds != 0 {
+		t.Errorf("Expected a plain mean of -3 over 0 seconds, got %+v", single)
+	}
+
+	// A dip while the beacon node was degraded is not the peer's, and ends the score before it
+	degraded := at(10, -10000)
+	degraded.BeaconDegraded = true
+
+	paused := SummarizeScores([]PeerScoreSnapshot{at(0, 5), degraded, at(15, 1)}, start.Add(time.Minute))
+	if paused.Snapshots != 2 || paused.ScoredSeconds != 55 || paused.AreaBelowZero != 0 {
+		t.Errorf("Expected the degraded snapshot left out, got %+v", paused)
+	}
+}
+
+func TestSummarizeSessionScoresAndTotals(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	disconnectedAt := start.Add(20 * time.Second)
+
+	stats := &Stats{
+		PeerID: "peer",
+		ConnectionSessions: []ConnectionSession{
+			{
+				DisconnectedAt: &disconnectedAt,
+				Disconnected:   true,
+				PeerScores:     []PeerScoreSnapshot{{Timestamp: start, Score: -2}},
+			},
+			{
+				PeerScores: []PeerScoreSnapshot{{Timestamp: start.Add(40 * time.Second), Score: 4}},
+			},
+			{},
+		},
+	}
+
+	SummarizeSessionScores(map[string]*Stats{stats.PeerID: stats}, start.Add(100*time.Second))
+
+	if got := stats.ConnectionSessions[0].ScoreSummary; got == nil || got.ScoredSeconds != 20 || got.AreaBelowZero != -40 {
+		t.Errorf("Expected the closed session scored for 20s with area -40, got %+v", got)
+	}
+
+	if got := stats.ConnectionSessions[1].ScoreSummary; got == nil || got.ScoredSeconds != 60 {
+		t.Errorf("Expected the open session scored up to the end for 60s, got %+v", got)
+	}
+
+	if stats.ConnectionSessions[2].ScoreSummary != nil {
+		t.Error("Expected no summary for a session without scores")
+	}
+
+	mean, area, below, ok := stats.ScoreTotals()
+	if !ok {
+		t.Fatal("Expected score totals")
+	}
+
+	if want := (-2*20 + 4*60) / 80.0; mean != want {
+		t.Errorf("Expected mean %f, got %f", want, mean)
+	}
+
+	if area != -40 || below != 0 {
+		t.Errorf("Expected area -40 and no time below publish, got %f and %f", area, below)
+	}
+
+	if _, _, _, ok := (&Stats{}).ScoreTotals(); ok {
+		t.Error("Expected no totals for a peer without summaries")
+	}
+}
+
+func TestWorstScoredPeers(t *testing.T) {
+	session := func(area float64) []ConnectionSession {
+		return []ConnectionSession{{ScoreSummary: &SessionScoreSummary{Snapshots: 1, ScoredSeconds: 10, AreaBelowZero: area}}}
+	}
+
+	peers := map[string]*Stats{
+		"a": {ClientType: "teku", ConnectionSessions: session(-10)},
+		"b": {ClientType: "prysm", ConnectionSessions: session(-500)},
+		"c": {ClientType: "nimbus", ConnectionSessions: session(0)},
+		"d": {ClientType: "lodestar"},
+		"e": {ClientType: "grandine", ConnectionSessions: session(-20)},
+	}
+
+	worst := WorstScoredPeers(peers, 2)
+	if len(worst) != 2 || worst[0].PeerID != "b" || worst[1].PeerID != "e" {
+		t.Fatalf("Expected peers b and e, worst first, got %+v", worst)
+	}
+
+	if worst[0].ClientType != "prysm" || worst[0].AreaBelowZero != -500 {
+		t.Errorf("Unexpected worst peer %+v", worst[0])
+	}
+
+	if all := WorstScoredPeers(peers, 0); len(all) != 3 {
+		t.Errorf("Expected the 3 peers below zero without a limit, got %d", len(all))
+	}
+}
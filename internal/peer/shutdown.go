@@ -0,0 +1,207 @@
+package peer
+
+import (
+	"sort"
+	"time"
+)
+
+// Teardown outcomes of a peer still connected when the run's shutdown began.
+const (
+	TeardownGoodbye    = "goodbye"    // The peer said goodbye before its connection closed
+	TeardownClosed     = "closed"     // The connection closed without a goodbye
+	TeardownUnobserved = "unobserved" // Still open when Hermes stopped reporting events
+)
+
+// PeerTeardown is how one peer's connection ended during shutdown.
+type PeerTeardown struct {
+	PeerID       string  `json:"peer_id"`
+	ClientType   string  `json:"client_type"`
+	Outcome      string  `json:"outcome"` // One of the Teardown constants
+	Code         uint64  `json:"code,omitempty"`
+	Reason       string  `json:"reason,omitempty"`
+	AfterSeconds float64 `json:"after_seconds"` // From the start of shutdown to the goodbye or close
+}
+
+// TeardownCode counts the goodbyes sent with one code during shutdown.
+type TeardownCode struct {
+	Code   uint64 `json:"code"`
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// ShutdownTeardown records how the peers connected at the end of the run reacted while
+// Hermes shut down. Hermes closes its connections without sending a goodbye, so a peer
+// either says goodbye itself, sees the connection close, or is still connected when Hermes
+// stops reporting events and its reaction is lost.
+type ShutdownTeardown struct {
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Seconds    float64        `json:"seconds"`
+	Completed  bool           `json:"completed"` // Hermes stopped within the shutdown timeout
+	Peers      int            `json:"peers"`     // Connected when shutdown began
+	Goodbye    int            `json:"goodbye"`
+	Closed     int            `json:"closed"`
+	Unobserved int            `json:"unobserved"`
+	Included   bool           `json:"included,omitempty"` // Counted in the churn, goodbye and duration statistics
+	Codes      []TeardownCode `json:"codes"`              // Most frequent first
+	Teardowns  []PeerTeardown `json:"teardowns"`
+}
+
+// teardownOrder sorts goodbyes first and unobserved peers last.
+var teardownOrder = map[string]int{
+	TeardownGoodbye:    0,
+	TeardownClosed:     1,
+	TeardownUnobserved: 2,
+}
+
+// AnalyzeShutdown classifies the teardown of every session open at start. Unless include is
+// set, it tags the sessions that closed and the goodbyes sent during shutdown, so the churn,
+// goodbye and duration statistics leave the run's own teardown out.
+func AnalyzeShutdown(peers map[string]*Stats, start, end time.Time, completed, include bool) *ShutdownTeardown {
+	shutdown := &ShutdownTeardown{
+		Start:     start,
+		End:       end,
+		Seconds:   end.Sub(start).Seconds(),
+		Completed: completed,
+		Included:  include,
+		Codes:     make([]TeardownCode, 0),
+		Teardowns: make([]PeerTeardown, 0),
+	}
+
+	codes := make(map[uint64]*TeardownCode)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			if !openAt(session, start) {
+				continue
+			}
+
+			teardown := classifyTeardown(session, start)
+			teardown.PeerID = peerID
+			teardown.ClientType = stats.ClientType
+
+			if !include {
+				tagTeardown(session, start)
+			}
+
+			shutdown.Peers++
+
+			switch teardown.Outcome {
+			case TeardownGoodbye:
+				shutdown.Goodbye++
+
+				if codes[teardown.Code] == nil {
+					codes[teardown.Code] = &TeardownCode{Code: teardown.Code, Reason: teardown.Reason}
+				}
+
+				codes[teardown.Code].Count++
+			case TeardownClosed:
+				shutdown.Closed++
+			default:
+				shutdown.Unobserved++
+			}
+
+			shutdown.Teardowns = append(shutdown.Teardowns, teardown)
+		}
+	}
+
+	for _, code := range codes {
+		shutdown.Codes = append(shutdown.Codes, *code)
+	}
+
+	sort.Slice(shutdown.Codes, func(i, j int) bool {
+		if shutdown.Codes[i].Count != shutdown.Codes[j].Count {
+			return shutdown.Codes[i].Count > shutdown.Codes[j].Count
+		}
+
+		return shutdown.Codes[i].Code < shutdown.Codes[j].Code
+	})
+
+	sort.Slice(shutdown.Teardowns, func(i, j int) bool {
+		a, b := shutdown.Teardowns[i], shutdown.Teardowns[j]
+
+		if a.Outcome != b.Outcome {
+			return teardownOrder[a.Outcome] < teardownOrder[b.Outcome]
+		}
+
+		if a.AfterSeconds != b.AfterSeconds {
+			return a.AfterSeconds < b.AfterSeconds
+		}
+
+		return a.PeerID < b.PeerID
+	})
+
+	return shutdown
+}
+
+// tagTeardown marks the session as closed in shutdown, when it was, and the goodbyes sent
+// from the start of shutdown as the peer's teardown.
+func tagTeardown(session *ConnectionSession, start time.Time) {
+	session.EndedInShutdown = session.Disconnected
+
+	for i := range session.GoodbyeEvents {
+		if !session.GoodbyeEvents[i].Timestamp.Before(start) {
+			session.GoodbyeEvents[i].InShutdown = true
+		}
+	}
+}
+
+// churned reports whether the session ended while the run was measuring, rather than in the
+// run's own shutdown.
+func churned(session *ConnectionSession) bool {
+	return session.Disconnected && !session.EndedInShutdown
+}
+
+// churnGoodbyes returns the goodbyes the peer sent before the run's shutdown.
+func churnGoodbyes(session *ConnectionSession) []GoodbyeEvent {
+	goodbyes := make([]GoodbyeEvent, 0, len(session.GoodbyeEvents))
+
+	for _, goodbye := range session.GoodbyeEvents {
+		if !goodbye.InShutdown {
+			goodbyes = append(goodbyes, goodbye)
+		}
+	}
+
+	return goodbyes
+}
+
+// openAt reports whether the session was connected at t. Sessions closed at a checkpoint
+// gap were never observed ending.
+func openAt(session *ConnectionSession, t time.Time) bool {
+	if session.ConnectedAt == nil || session.ConnectedAt.After(t) || session.EndedByGap {
+		return false
+	}
+
+	return !session.Disconnected || (session.DisconnectedAt != nil && session.DisconnectedAt.After(t))
+}
+
+// classifyTeardown finds the first goodbye the peer sent after shutdown began, falling back
+// to the disconnect when it sent none.
+func classifyTeardown(session *ConnectionSession, start time.Time) PeerTeardown {
+	for _, goodbye := range session.GoodbyeEvents {
+		if goodbye.Timestamp.Before(start) {
+			continue
+		}
+
+		return PeerTeardown{
+			Outcome:      TeardownGoodbye,
+			Code:         goodbye.Code,
+			Reason:       goodbye.Reason,
+			AfterSeconds: goodbye.Timestamp.Sub(start).Seconds(),
+		}
+	}
+
+	if session.Disconnected && session.DisconnectedAt != nil {
+		return PeerTeardown{
+			Outcome:      TeardownClosed,
+			AfterSeconds: session.DisconnectedAt.Sub(start).Seconds(),
+		}
+	}
+
+	return PeerTeardown{Outcome: TeardownUnobserved}
+}
@@ -0,0 +1,153 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeShutdown(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	closed := func(from, to int) ConnectionSession {
+		return ConnectionSession{ConnectedAt: at(from), DisconnectedAt: at(to), Disconnected: true}
+	}
+
+	goodbye := closed(-50, 2)
+	goodbye.GoodbyeEvents = []GoodbyeEvent{{Timestamp: *at(1), Code: 1, Reason: "client shutdown"}}
+
+	earlier := closed(-40, 3)
+	earlier.GoodbyeEvents = []GoodbyeEvent{{Timestamp: *at(-60), Code: 129}}
+
+	// Shutdown runs from 0s to 5s
+	peers := map[string]*Stats{
+		"goodbye":    {ClientType: "lighthouse", ConnectionSessions: []ConnectionSession{goodbye}},
+		"closed":     {ClientType: "prysm", ConnectionSessions: []ConnectionSession{closed(-90, -80), earlier}},
+		"unobserved": {ClientType: "teku", ConnectionSessions: []ConnectionSession{{ConnectedAt: at(-30)}}},
+		"gone":       {ConnectionSessions: []ConnectionSession{closed(-20, -10)}},
+		"gap":        {ConnectionSessions: []ConnectionSession{{ConnectedAt: at(-20), DisconnectedAt: at(1), Disconnected: true, EndedByGap: true}}},
+		"late":       {ConnectionSessions: []ConnectionSession{closed(1, 2)}},
+	}
+
+	shutdown := AnalyzeShutdown(peers, start, start.Add(5*time.Second), true, false)
+
+	counts := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "peers", got: shutdown.Peers, want: 3},
+		{name: "goodbye", got: shutdown.Goodbye, want: 1},
+		{name: "closed", got: shutdown.Closed, want: 1},
+		{name: "unobserved", got: shutdown.Unobserved, want: 1},
+		{name: "codes", got: len(shutdown.Codes), want: 1},
+	}
+
+	for _, tt := range counts {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %d, want %d", tt.got, tt.want)
+			}
+		})
+	}
+
+	if shutdown.Seconds != 5 || !shutdown.Completed {
+		t.Errorf("Expected a completed 5s shutdown, got %.1fs completed=%v", shutdown.Seconds, shutdown.Completed)
+	}
+
+	want := []PeerTeardown{
+		{PeerID: "goodbye", ClientType: "lighthouse", Outcome: TeardownGoodbye, Code: 1, Reason: "client shutdown", AfterSeconds: 1},
+		{PeerID: "closed", ClientType: "prysm", Outcome: TeardownClosed, AfterSeconds: 3},
+		{PeerID: "unobserved", ClientType: "teku", Outcome: TeardownUnobserved},
+	}
+
+	if len(shutdown.Teardowns) != len(want) {
+		t.Fatalf("Expected %d teardowns, got %+v", len(want), shutdown.Teardowns)
+	}
+
+	for i := range want {
+		if shutdown.Teardowns[i] != want[i] {
+			t.Errorf("Teardown %d: got %+v, want %+v", i, shutdown.Teardowns[i], want[i])
+		}
+	}
+
+	tagged := []struct {
+		peerID  string
+		session int
+		want    bool
+	}{
+		{peerID: "goodbye", session: 0, want: true},
+		{peerID: "closed", session: 0, want: false},
+		{peerID: "closed", session: 1, want: true},
+		{peerID: "unobserved", session: 0, want: false},
+		{peerID: "gap", session: 0, want: false},
+	}
+
+	for _, tt := range tagged {
+		if got := peers[tt.peerID].ConnectionSessions[tt.session].EndedInShutdown; got != tt.want {
+			t.Errorf("%s session %d: expected ended in shutdown %v, got %v", tt.peerID, tt.session, tt.want, got)
+		}
+	}
+
+	if !peers["goodbye"].ConnectionSessions[0].GoodbyeEvents[0].InShutdown {
+		t.Error("Expected the goodbye sent during shutdown to be tagged")
+	}
+
+	if peers["closed"].ConnectionSessions[1].GoodbyeEvents[0].InShutdown {
+		t.Error("Expected the goodbye sent before shutdown to be untagged")
+	}
+}
+
+func TestAnalyzeShutdownIncluded(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	connected, goodbyeAt, disconnected := start.Add(-time.Minute), start.Add(time.Second), start.Add(2*time.Second)
+
+	session := func() ConnectionSession {
+		return ConnectionSession{
+			ConnectedAt:    &connected,
+			DisconnectedAt: &disconnected,
+			Disconnected:   true,
+			GoodbyeEvents:  []GoodbyeEvent{{Timestamp: goodbyeAt, Code: 1, Reason: "client shutdown"}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		include     bool
+		disconnects int
+		goodbyes    int
+	}{
+		{name: "excluded by default", include: false, disconnects: 0, goodbyes: 0},
+		{name: "included", include: true, disconnects: 1, goodbyes: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peers := map[string]*Stats{"peer": {ClientType: "lighthouse", ConnectionSessions: []ConnectionSession{session()}}}
+
+			shutdown := AnalyzeShutdown(peers, start, start.Add(5*time.Second), true, tt.include)
+			if shutdown.Included != tt.include || shutdown.Goodbye != 1 {
+				t.Fatalf("Expected the goodbye classified with included %v, got %+v", tt.include, shutdown)
+			}
+
+			clients := SummarizeClients(peers)
+			if clients[0].Disconnects != tt.disconnects || clients[0].GoodbyeEvents != tt.goodbyes {
+				t.Errorf("Expected %d disconnects and %d goodbyes, got %+v", tt.disconnects, tt.goodbyes, clients[0])
+			}
+
+			if reasons := TopGoodbyeReasons(peers, 5); len(reasons) != tt.goodbyes {
+				t.Errorf("Expected %d goodbye reasons, got %+v", tt.goodbyes, reasons)
+			}
+
+			slices := CalculateTimeSlices(peers, start.Add(-time.Hour), start.Add(time.Minute), 10*time.Minute)
+			if last := slices[len(slices)-1]; last.Disconnects != tt.disconnects || last.Goodbyes != tt.goodbyes {
+				t.Errorf("Expected %d disconnects and %d goodbyes in the last slice, got %+v", tt.disconnects, tt.goodbyes, last)
+			}
+		})
+	}
+}
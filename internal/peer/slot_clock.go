@@ -0,0 +1,86 @@
+package peer
+
+import "time"
+
+// SlotClock converts wall-clock times into beacon chain slots and epochs.
+type SlotClock struct {
+	genesisTime    time.Time
+	secondsPerSlot time.Duration
+	slotsPerEpoch  uint64
+}
+
+// NewSlotClock creates a slot clock for a chain with the given genesis time and timing parameters.
+func NewSlotClock(genesisTime time.Time, secondsPerSlot time.Duration, slotsPerEpoch uint64) *SlotClock {
+	return &SlotClock{
+		genesisTime:    genesisTime,
+		secondsPerSlot: secondsPerSlot,
+		slotsPerEpoch:  slotsPerEpoch,
+	}
+}
+
+// GenesisTime returns the genesis time the clock is anchored to.
+func (c *SlotClock) GenesisTime() time.Time {
+	return c.genesisTime
+}
+
+// EpochStart returns the time the given epoch began.
+func (c *SlotClock) EpochStart(epoch uint64) time.Time {
+	return c.genesisTime.Add(time.Duration(epoch*c.slotsPerEpoch) * c.secondsPerSlot) //nolint:gosec // ok.
+}
+
+// SlotAt returns the slot that was current at the given time.
+// Times before genesis map to slot 0.
+func (c *SlotClock) SlotAt(t time.Time) uint64 {
+	if c.secondsPerSlot <= 0 || !t.After(c.genesisTime) {
+		return 0
+	}
+
+	return uint64(t.Sub(c.genesisTime) / c.secondsPerSlot)
+}
+
+// EpochAt returns the epoch that was current at the given time.
+func (c *SlotClock) EpochAt(t time.Time) uint64 {
+	if c.slotsPerEpoch == 0 {
+		return 0
+	}
+
+	return c.SlotAt(t) / c.slotsPerEpoch
+}
+
+// AnnotatePeers fills in the slot and epoch of every session boundary and
+// recorded event, derived from their timestamps.
+func (c *SlotClock) AnnotatePeers(peers map[string]*Stats) {
+	for _, stats := range peers {
+		for i := range stats.ConnectionSessions {
+			c.annotateSession(&stats.ConnectionSessions[i])
+		}
+	}
+}
+
+// annotateSession fills in slot and epoch information for a single session.
+func (c *SlotClock) annotateSession(session *ConnectionSession) {
+	if session.ConnectedAt != nil {
+		session.ConnectedSlot = c.SlotAt(*session.ConnectedAt)
+		session.ConnectedEpoch = c.EpochAt(*session.ConnectedAt)
+	}
+
+	if session.DisconnectedAt != nil {
+		session.DisconnectedSlot = c.SlotAt(*session.DisconnectedAt)
+		session.DisconnectedEpoch = c.EpochAt(*session.DisconnectedAt)
+	}
+
+	for i := range session.PeerScores {
+		session.PeerScores[i].Slot = c.SlotAt(session.PeerScores[i].Timestamp)
+		session.PeerScores[i].Epoch = c.EpochAt(session.PeerScores[i].Timestamp)
+	}
+
+	for i := range session.GoodbyeEvents {
+		session.GoodbyeEvents[i].Slot = c.SlotAt(session.GoodbyeEvents[i].Timestamp)
+		session.GoodbyeEvents[i].Epoch = c.EpochAt(session.GoodbyeEvents[i].Timestamp)
+	}
+
+	for i := range session.MeshEvents {
+		session.MeshEvents[i].Slot = c.SlotAt(session.MeshEvents[i].Timestamp)
+		session.MeshEvents[i].Epoch = c.EpochAt(session.MeshEvents[i].Timestamp)
+	}
+}
@@ -0,0 +1,97 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlotClock(t *testing.T) {
+	genesis := time.Unix(1606824023, 0)
+	clock := NewSlotClock(genesis, 12*time.Second, 32)
+
+	tests := []struct {
+		name          string
+		at            time.Time
+		expectedSlot  uint64
+		expectedEpoch uint64
+	}{
+		{
+			name:          "before genesis",
+			at:            genesis.Add(-time.Hour),
+			expectedSlot:  0,
+			expectedEpoch: 0,
+		},
+		{
+			name:          "mid first slot",
+			at:            genesis.Add(6 * time.Second),
+			expectedSlot:  0,
+			expectedEpoch: 0,
+		},
+		{
+			name:          "last slot of first epoch",
+			at:            genesis.Add(31*12*time.Second + time.Second),
+			expectedSlot:  31,
+			expectedEpoch: 0,
+		},
+		{
+			name:          "epoch boundary",
+			at:            genesis.Add(32 * 12 * time.Second),
+			expectedSlot:  32,
+			expectedEpoch: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if slot := clock.SlotAt(tt.at); slot != tt.expectedSlot {
+				t.Errorf("Expected slot %d, got %d", tt.expectedSlot, slot)
+			}
+
+			if epoch := clock.EpochAt(tt.at); epoch != tt.expectedEpoch {
+				t.Errorf("Expected epoch %d, got %d", tt.expectedEpoch, epoch)
+			}
+		})
+	}
+}
+
+func TestSlotClockAnnotatePeers(t *testing.T) {
+	genesis := time.Unix(0, 0)
+	clock := NewSlotClock(genesis, 12*time.Second, 32)
+
+	connectedAt := genesis.Add(100 * 12 * time.Second)
+	disconnectedAt := genesis.Add(200 * 12 * time.Second)
+
+	peers := map[string]*Stats{
+		"peer1": {
+			PeerID: "peer1",
+			ConnectionSessions: []ConnectionSession{
+				{
+					ConnectedAt:    &connectedAt,
+					DisconnectedAt: &disconnectedAt,
+					PeerScores:     []PeerScoreSnapshot{{Timestamp: connectedAt}},
+					GoodbyeEvents:  []GoodbyeEvent{{Timestamp: disconnectedAt}},
+					MeshEvents:     []MeshEvent{{Timestamp: connectedAt}},
+				},
+			},
+		},
+	}
+
+	clock.AnnotatePeers(peers)
+
+	session := peers["peer1"].ConnectionSessions[0]
+	if session.ConnectedSlot != 100 || session.ConnectedEpoch != 3 {
+		t.Errorf("Unexpected connected slot/epoch: %d/%d", session.ConnectedSlot, session.ConnectedEpoch)
+	}
+
+	if session.DisconnectedSlot != 200 || session.DisconnectedEpoch != 6 {
+		t.Errorf("Unexpected disconnected slot/epoch: %d/%d", session.DisconnectedSlot, session.DisconnectedEpoch)
+	}
+
+	if session.PeerScores[0].Slot != 100 || session.MeshEvents[0].Slot != 100 {
+		t.Errorf("Expected score and mesh events at slot 100")
+	}
+
+	if session.GoodbyeEvents[0].Epoch != 6 {
+		t.Errorf("Expected goodbye event in epoch 6, got %d", session.GoodbyeEvents[0].Epoch)
+	}
+}
@@ -0,0 +1,56 @@
+package peer
+
+// SlotDrift compares the head slots peers reported in their statuses with our current slot
+// when each status arrived. Synced peers' heads sit at or just behind the current slot, so a
+// median drift well away from that says our clock is off rather than theirs.
+type SlotDrift struct {
+	Peers           int     `json:"peers"`             // Peers with at least one status
+	Statuses        int     `json:"statuses"`          // Statuses compared
+	AheadOfUs       int     `json:"ahead_of_us"`       // Statuses with a head slot past our current slot
+	MedianSlotDrift float64 `json:"median_slot_drift"` // Median over peers of head slot minus our current slot
+	Skewed          bool    `json:"skewed"`
+}
+
+// AnalyzeSlotDrift measures the peers' head slot drift from our slot clock. Each peer counts
+// once, with the median drift of its statuses. With at least minPeers peers, the drift counts
+// as skewed at ahead slots or more in our future, or behind slots or more in our past.
+func AnalyzeSlotDrift(peers map[string]*Stats, clock *SlotClock, minPeers int, ahead, behind float64) *SlotDrift {
+	result := &SlotDrift{}
+	peerDrifts := make([]float64, 0, len(peers))
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		drifts := make([]float64, 0)
+
+		for _, session := range stats.ConnectionSessions {
+			for _, update := range session.StatusUpdates {
+				if update.Error != "" || update.HeadSlot == 0 {
+					continue
+				}
+
+				drift := float64(update.HeadSlot) - float64(clock.SlotAt(update.Timestamp))
+				if drift > 0 {
+					result.AheadOfUs++
+				}
+
+				drifts = append(drifts, drift)
+			}
+		}
+
+		if len(drifts) == 0 {
+			continue
+		}
+
+		result.Peers++
+		result.Statuses += len(drifts)
+		peerDrifts = append(peerDrifts, median(drifts))
+	}
+
+	result.MedianSlotDrift = median(peerDrifts)
+	result.Skewed = result.Peers >= minPeers && (result.MedianSlotDrift >= ahead || result.MedianSlotDrift <= -behind)
+
+	return result
+}
@@ -0,0 +1,95 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeSlotDrift(t *testing.T) {
+	genesis := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewSlotClock(genesis, 12*time.Second, 32)
+
+	// Statuses received during slot 100 by our clock
+	at := genesis.Add(100*12*time.Second + 3*time.Second)
+
+	withHeads := func(heads ...uint64) *Stats {
+		updates := make([]StatusUpdate, 0, len(heads))
+		for _, head := range heads {
+			updates = append(updates, StatusUpdate{Timestamp: at, HeadSlot: head})
+		}
+
+		return &Stats{ConnectionSessions: []ConnectionSession{{StatusUpdates: updates}}}
+	}
+
+	tests := []struct {
+		name       string
+		peers      map[string]*Stats
+		wantPeers  int
+		wantAhead  int
+		wantMedian float64
+		wantSkewed bool
+	}{
+		{
+			name: "synced peers at or just behind our slot",
+			peers: map[string]*Stats{
+				"a":       withHeads(100, 100),
+				"b":       withHeads(99),
+				"c":       withHeads(100),
+				"stalled": withHeads(40),
+			},
+			wantPeers:  4,
+			wantMedian: -0.5,
+		},
+		{
+			name: "peers ahead of our clock",
+			peers: map[string]*Stats{
+				"a": withHeads(102),
+				"b": withHeads(101, 103),
+				"c": withHeads(100),
+			},
+			wantPeers:  3,
+			wantAhead:  3,
+			wantMedian: 2,
+			wantSkewed: true,
+		},
+		{
+			name: "peers well behind our clock",
+			peers: map[string]*Stats{
+				"a": withHeads(97),
+				"b": withHeads(96),
+				"c": withHeads(98),
+			},
+			wantPeers:  3,
+			wantMedian: -3,
+			wantSkewed: true,
+		},
+		{
+			name: "too few peers to judge",
+			peers: map[string]*Stats{
+				"a":      withHeads(105),
+				"failed": {ConnectionSessions: []ConnectionSession{{StatusUpdates: []StatusUpdate{{Timestamp: at, Error: "stream reset"}}}}},
+			},
+			wantPeers:  1,
+			wantAhead:  1,
+			wantMedian: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AnalyzeSlotDrift(tt.peers, clock, 3, 1, 2)
+
+			if result.Peers != tt.wantPeers || result.AheadOfUs != tt.wantAhead {
+				t.Errorf("peers = %d, ahead = %d, want %d and %d", result.Peers, result.AheadOfUs, tt.wantPeers, tt.wantAhead)
+			}
+
+			if result.MedianSlotDrift != tt.wantMedian {
+				t.Errorf("MedianSlotDrift = %v, want %v", result.MedianSlotDrift, tt.wantMedian)
+			}
+
+			if result.Skewed != tt.wantSkewed {
+				t.Errorf("Skewed = %v, want %v", result.Skewed, tt.wantSkewed)
+			}
+		})
+	}
+}
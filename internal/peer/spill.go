@@ -0,0 +1,270 @@
+package peer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Spiller keeps the detailed events of completed sessions in a temporary file on disk, so busy
+// runs on small instances do not hold every score snapshot and mesh event in memory. Spilled
+// events are read back when the report or a checkpoint is written.
+type Spiller struct {
+	dir string
+
+	mu     sync.Mutex
+	file   *os.File
+	offset int64
+	stats  SpillStats
+}
+
+// SpillStats summarises what a run spilled to disk.
+type SpillStats struct {
+	Spills   int   `json:"spills"`
+	Sessions int   `json:"sessions"`
+	Bytes    int64 `json:"bytes"` // Compressed bytes written to the spill file
+}
+
+// spilledEvents locates a session's spilled events in the spill file.
+type spilledEvents struct {
+	offset int64
+	length int
+}
+
+// sessionEvents are the detailed events of a session that are spilled.
+type sessionEvents struct {
+	PeerScores    []PeerScoreSnapshot `json:"peer_scores,omitempty"`
+	GoodbyeEvents []GoodbyeEvent      `json:"goodbye_events,omitempty"`
+	MeshEvents    []MeshEvent         `json:"mesh_events,omitempty"`
+	StatusUpdates []StatusUpdate      `json:"status_updates,omitempty"`
+}
+
+// NewSpiller creates a spiller writing to a temporary file in dir, the system temporary
+// directory when empty. The file is created on the first spill.
+func NewSpiller(dir string) *Spiller {
+	return &Spiller{dir: dir}
+}
+
+// Stats returns what was spilled so far.
+func (s *Spiller) Stats() SpillStats {
+	if s == nil {
+		return SpillStats{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats
+}
+
+// write appends a session's events to the spill file, compressed with zstd.
+func (s *Spiller) write(events *sessionEvents) (*spilledEvents, error) {
+	encoded, err := json.Marshal(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session events: %w", err)
+	}
+
+	compressed := topicEncoder.EncodeAll(encoded, nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		file, err := os.CreateTemp(s.dir, constants.SpillFilePattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spill file: %w", err)
+		}
+
+		s.file = file
+	}
+
+	if _, err := s.file.WriteAt(compressed, s.offset); err != nil {
+		return nil, fmt.Errorf("failed to write spill file: %w", err)
+	}
+
+	spilled := &spilledEvents{offset: s.offset, length: len(compressed)}
+	s.offset += int64(len(compressed))
+	s.stats.Sessions++
+	s.stats.Bytes += int64(len(compressed))
+
+	return spilled, nil
+}
+
+// read reads a session's events back from the spill file.
+func (s *Spiller) read(spilled *spilledEvents) (*sessionEvents, error) {
+	s.mu.Lock()
+	file := s.file
+	s.mu.Unlock()
+
+	if file == nil {
+		return nil, fmt.Errorf("spill file is closed")
+	}
+
+	compressed := make([]byte, spilled.length)
+	if _, err := file.ReadAt(compressed, spilled.offset); err != nil {
+		return nil, fmt.Errorf("failed to read spill file: %w", err)
+	}
+
+	encoded, err := topicDecoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress spilled events: %w", err)
+	}
+
+	var events sessionEvents
+	if err := json.Unmarshal(encoded, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode spilled events: %w", err)
+	}
+
+	return &events, nil
+}
+
+// Load reads the spilled events of peers copied from the repository back into their sessions,
+// ahead of the events recorded since the session was spilled. A nil spiller loads nothing.
+func (s *Spiller) Load(peers map[string]*Stats) error {
+	if s == nil {
+		return nil
+	}
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			if session.spilled == nil {
+				continue
+			}
+
+			events, err := s.read(session.spilled)
+			if err != nil {
+				return fmt.Errorf("failed to load spilled events of peer %s: %w", formatShortPeerID(stats.PeerID), err)
+			}
+
+			// Sessions without events of a kind keep their empty slices, as reports expect
+			if len(events.PeerScores) > 0 {
+				session.PeerScores = append(events.PeerScores, session.PeerScores...)
+			}
+
+			if len(events.GoodbyeEvents) > 0 {
+				session.GoodbyeEvents = append(events.GoodbyeEvents, session.GoodbyeEvents...)
+			}
+
+			if len(events.MeshEvents) > 0 {
+				session.MeshEvents = append(events.MeshEvents, session.MeshEvents...)
+			}
+
+			if len(events.StatusUpdates) > 0 {
+				session.StatusUpdates = append(events.StatusUpdates, session.StatusUpdates...)
+			}
+
+			session.spilled = nil
+		}
+	}
+
+	return nil
+}
+
+// Close removes the spill file, after which spilled events can no longer be loaded.
+func (s *Spiller) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	name := s.file.Name()
+	closeErr := s.file.Close()
+	s.file = nil
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove spill file: %w", err)
+	}
+
+	return closeErr
+}
+
+// SetSpiller sets where Spill moves completed sessions' events, nil disables spilling.
+func (r *InMemoryRepository) SetSpiller(spiller *Spiller) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spiller = spiller
+}
+
+// Spill moves the detailed events of the older half of the sessions that ended before cutoff
+// to the spill file, returning how many sessions it spilled. Sessions are spilled once, events
+// assigned to them afterwards stay in memory. Peers read from the repository carry spilled
+// sessions without their events until the spiller loads them.
+func (r *InMemoryRepository) Spill(cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.spiller == nil {
+		return 0, nil
+	}
+
+	candidates := make([]*ConnectionSession, 0)
+
+	for _, stats := range r.peers {
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+
+			if session.spilled != nil || session.DisconnectedAt == nil || !session.DisconnectedAt.Before(cutoff) {
+				continue
+			}
+
+			if len(session.PeerScores)+len(session.GoodbyeEvents)+len(session.MeshEvents)+len(session.StatusUpdates) == 0 {
+				continue
+			}
+
+			candidates = append(candidates, session)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].DisconnectedAt.Before(*candidates[j].DisconnectedAt)
+	})
+
+	// Spilling the older half leaves room for the next check to spill more if it was not enough
+	candidates = candidates[:(len(candidates)+1)/2]
+
+	for spilled, session := range candidates {
+		ref, err := r.spiller.write(&sessionEvents{
+			PeerScores:    session.PeerScores,
+			GoodbyeEvents: session.GoodbyeEvents,
+			MeshEvents:    session.MeshEvents,
+			StatusUpdates: session.StatusUpdates,
+		})
+		if err != nil {
+			return spilled, err
+		}
+
+		session.spilled = ref
+		session.PeerScores = nil
+		session.GoodbyeEvents = nil
+		session.MeshEvents = nil
+		session.StatusUpdates = nil
+		session.packedScores = 0
+	}
+
+	r.spiller.mu.Lock()
+	r.spiller.stats.Spills++
+	r.spiller.mu.Unlock()
+
+	return len(candidates), nil
+}
@@ -0,0 +1,101 @@
+package peer
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSpill(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := NewInMemoryRepository(logrus.New())
+	repo.SetSpiller(NewSpiller(dir))
+
+	// Three completed sessions ending a minute apart and one still open
+	for i, peerID := range []string{"16Uiu2HAmOldest", "16Uiu2HAmMiddle", "16Uiu2HAmNewest", "16Uiu2HAmOpen"} {
+		connected := start.Add(time.Duration(i) * time.Minute)
+
+		repo.UpdateOrCreatePeer(peerID, func(stats *Stats) {
+			session := ConnectionSession{
+				ConnectedAt:   &connected,
+				PeerScores:    []PeerScoreSnapshot{{Timestamp: connected, Score: float64(i), Topics: subnetTopics(i)}},
+				GoodbyeEvents: []GoodbyeEvent{},
+				MeshEvents:    []MeshEvent{{Timestamp: connected, Type: "GRAFT", Topic: "beacon_block"}},
+			}
+
+			if peerID != "16Uiu2HAmOpen" {
+				disconnected := connected.Add(30 * time.Second)
+				session.DisconnectedAt = &disconnected
+				session.Disconnected = true
+			}
+
+			stats.ConnectionSessions = append(stats.ConnectionSessions, session)
+		})
+	}
+
+	before := repo.GetAllPeers()
+
+	// The newest session ended after the cutoff, of the other two the older half is spilled
+	spilled, err := repo.Spill(start.Add(2 * time.Minute))
+	if err != nil || spilled != 1 {
+		t.Fatalf("Expected one session spilled, got %d, %v", spilled, err)
+	}
+
+	oldest, _ := repo.GetPeer("16Uiu2HAmOldest")
+	if len(oldest.ConnectionSessions[0].PeerScores) != 0 || len(oldest.ConnectionSessions[0].MeshEvents) != 0 {
+		t.Errorf("Expected the oldest session's events spilled, got %+v", oldest.ConnectionSessions[0])
+	}
+
+	if middle, _ := repo.GetPeer("16Uiu2HAmMiddle"); len(middle.ConnectionSessions[0].PeerScores) != 1 {
+		t.Error("Expected the middle session kept in memory")
+	}
+
+	// Spilled sessions are not spilled again, the middle one follows on the next spill
+	if spilled, _ := repo.Spill(start.Add(2 * time.Minute)); spilled != 1 {
+		t.Errorf("Expected the middle session spilled next, got %d", spilled)
+	}
+
+	// A late event assigned after the spill stays in memory and is loaded after the spilled ones
+	repo.UpdatePeer("16Uiu2HAmOldest", func(stats *Stats) {
+		stats.ConnectionSessions[0].GoodbyeEvents = append(stats.ConnectionSessions[0].GoodbyeEvents, GoodbyeEvent{Code: 3, Reason: "too many peers"})
+	})
+
+	spiller := repo.spiller
+	after := repo.GetAllPeers()
+
+	if err := spiller.Load(after); err != nil {
+		t.Fatalf("Expected spilled events loaded, got %v", err)
+	}
+
+	if goodbyes := after["16Uiu2HAmOldest"].ConnectionSessions[0].GoodbyeEvents; len(goodbyes) != 1 || goodbyes[0].Code != 3 {
+		t.Errorf("Expected the late goodbye kept, got %+v", goodbyes)
+	}
+
+	// Otherwise the peers read back match the peers before the spill
+	after["16Uiu2HAmOldest"].ConnectionSessions[0].GoodbyeEvents = nil
+	before["16Uiu2HAmOldest"].ConnectionSessions[0].GoodbyeEvents = nil
+
+	beforeJSON, _ := json.Marshal(before)
+	afterJSON, _ := json.Marshal(after)
+
+	if string(beforeJSON) != string(afterJSON) {
+		t.Errorf("Expected the loaded peers to match the peers before the spill\nbefore: %s\nafter:  %s", beforeJSON, afterJSON)
+	}
+
+	if stats := spiller.Stats(); stats.Spills != 2 || stats.Sessions != 2 || stats.Bytes == 0 {
+		t.Errorf("Unexpected spill stats %+v", stats)
+	}
+
+	if err := spiller.Close(); err != nil {
+		t.Fatalf("Expected the spill file removed, got %v", err)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no spill file left, got %d", len(entries))
+	}
+}
@@ -1,11 +1,61 @@
 package peer
 
 import (
+	"sort"
 	"time"
 
 	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/glossary"
 )
 
+// ConnectionStatsDefinitions define the ConnectionStats metrics, as reports show them.
+var ConnectionStatsDefinitions = []glossary.Definition{
+	{
+		Name:    "Total Connections",
+		Field:   "total_connections",
+		Formula: "Sessions with a connect time",
+		Caveats: []string{
+			"Counts sessions, not peers: a peer reconnecting five times counts five times",
+			"Only general peers, leaving out static, bootnode and canary peers, and only sessions connected in the measurement window when the run has phases",
+		},
+	},
+	{
+		Name:    "Successful Handshakes",
+		Field:   "successful_handshakes",
+		Formula: "Sessions with a connect time that were identified",
+		Caveats: []string{"Counted over the same sessions as Total Connections"},
+	},
+	{
+		Name:    "Failed Handshakes",
+		Field:   "failed_handshakes",
+		Formula: "Sessions with a connect time that were never identified",
+		Caveats: []string{
+			"Not a protocol failure: a session dropped before identify, for instance because either side was at its peer limit, counts as failed",
+			"A retry that then succeeds still leaves the first session failed; Reconciled Handshakes count the episode once",
+		},
+	},
+}
+
+// ReconciledHandshakeDefinitions define the ReconciledHandshakeStats metrics.
+var ReconciledHandshakeDefinitions = []glossary.Definition{
+	{
+		Name:    "Episodes",
+		Field:   "episodes",
+		Formula: "Sessions of a peer, with each failed session followed by a reconnect within the retry window merged into the next",
+	},
+	{
+		Name:    "Reconciled Success Rate",
+		Field:   "success_rate",
+		Formula: "Successful episodes / episodes × 100, an episode taking the outcome of its final session",
+		Caveats: []string{"A percentage, where the headline handshake success rate is a fraction"},
+	},
+	{
+		Name:    "Recovered Episodes",
+		Field:   "recovered_episodes",
+		Formula: "Episodes whose first session failed and a retry identified",
+	},
+}
+
 // DefaultStatsCalculator implements the StatsCalculator interface.
 type DefaultStatsCalculator struct{}
 
@@ -50,6 +100,95 @@ func (sc *DefaultStatsCalculator) CalculateConnectionStats(peers map[string]*Sta
 	return stats
 }
 
+// CalculateConnectionStatsInWindow calculates aggregate connection statistics counting only
+// sessions that connected inside the run's measurement window.
+func (sc *DefaultStatsCalculator) CalculateConnectionStatsInWindow(peers map[string]*Stats, phases *RunPhases) ConnectionStats {
+	return sc.CalculateConnectionStats(phases.MeasuredPeers(peers))
+}
+
+// CalculateReconciledHandshakes calculates handshake outcomes per connection episode, so a
+// failed handshake followed by a successful reconnect within retryWindow counts once, as a success.
+func (sc *DefaultStatsCalculator) CalculateReconciledHandshakes(peers map[string]*Stats, retryWindow time.Duration) ReconciledHandshakeStats {
+	stats := ReconciledHandshakeStats{
+		RetryWindowSeconds: retryWindow.Seconds(),
+	}
+
+	for _, peer := range peers {
+		sessions := make([]ConnectionSession, 0, len(peer.ConnectionSessions))
+
+		for _, session := range peer.ConnectionSessions {
+			if session.ConnectedAt != nil {
+				sessions = append(sessions, session)
+			}
+		}
+
+		sort.SliceStable(sessions, func(i, j int) bool {
+			return sessions[i].ConnectedAt.Before(*sessions[j].ConnectedAt)
+		})
+
+		var (
+			inEpisode   bool
+			identified  bool
+			retried     bool
+			episodeLast ConnectionSession
+		)
+
+		closeEpisode := func() {
+			stats.Episodes++
+
+			if identified {
+				stats.SuccessfulEpisodes++
+
+				if retried {
+					stats.RecoveredEpisodes++
+				}
+			} else {
+				stats.FailedEpisodes++
+			}
+		}
+
+		for _, session := range sessions {
+			// A reconnect only continues the episode if the previous attempt failed
+			if inEpisode && !identified && isRetry(episodeLast, session, retryWindow) {
+				retried = true
+				identified = session.IdentifiedAt != nil
+				episodeLast = session
+
+				continue
+			}
+
+			if inEpisode {
+				closeEpisode()
+			}
+
+			inEpisode = true
+			identified = session.IdentifiedAt != nil
+			retried = false
+			episodeLast = session
+		}
+
+		if inEpisode {
+			closeEpisode()
+		}
+	}
+
+	if stats.Episodes > 0 {
+		stats.SuccessRate = float64(stats.SuccessfulEpisodes) / float64(stats.Episodes) * 100
+	}
+
+	return stats
+}
+
+// isRetry reports whether next reconnects within the retry window after previous ended.
+func isRetry(previous, next ConnectionSession, retryWindow time.Duration) bool {
+	previousEnd := *previous.ConnectedAt
+	if previous.DisconnectedAt != nil {
+		previousEnd = *previous.DisconnectedAt
+	}
+
+	return next.ConnectedAt.Sub(previousEnd) <= retryWindow
+}
+
 // CalculateClientDistribution calculates the distribution of client types.
 func (sc *DefaultStatsCalculator) CalculateClientDistribution(peers map[string]*Stats) map[string]int {
 	distribution := make(map[string]int)
@@ -65,14 +204,23 @@ func (sc *DefaultStatsCalculator) CalculateClientDistribution(peers map[string]*
 	return distribution
 }
 
-// CalculateDurationStats calculates connection duration statistics.
+// CalculateDurationStats calculates connection duration statistics over the sessions the run
+// observed ending. Censored sessions are counted but left out of the durations.
 func (sc *DefaultStatsCalculator) CalculateDurationStats(peers map[string]*Stats) DurationStats {
 	var durations []time.Duration
 
 	var totalDuration time.Duration
 
+	stats := DurationStats{}
+
 	for _, peer := range peers {
 		for _, session := range peer.ConnectionSessions {
+			if session.censored() {
+				stats.CensoredSessions++
+
+				continue
+			}
+
 			if session.Duration != nil && *session.Duration > 0 {
 				durations = append(durations, *session.Duration)
 				totalDuration += *session.Duration
@@ -80,8 +228,6 @@ func (sc *DefaultStatsCalculator) CalculateDurationStats(peers map[string]*Stats
 		}
 	}
 
-	stats := DurationStats{}
-
 	if len(durations) > 0 {
 		stats.AverageDuration = totalDuration / time.Duration(len(durations))
 		stats.MinDuration = durations[0]
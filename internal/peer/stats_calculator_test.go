@@ -0,0 +1,76 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateReconciledHandshakes(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	failed := func(connected, disconnected int) ConnectionSession {
+		return ConnectionSession{ConnectedAt: at(connected), DisconnectedAt: at(disconnected), Disconnected: true}
+	}
+
+	identified := func(connected int) ConnectionSession {
+		return ConnectionSession{ConnectedAt: at(connected), IdentifiedAt: at(connected + 1)}
+	}
+
+	tests := []struct {
+		name     string
+		sessions []ConnectionSession
+		expected ReconciledHandshakeStats
+	}{
+		{
+			name:     "single success",
+			sessions: []ConnectionSession{identified(0)},
+			expected: ReconciledHandshakeStats{Episodes: 1, SuccessfulEpisodes: 1, SuccessRate: 100},
+		},
+		{
+			name:     "failure recovered within window",
+			sessions: []ConnectionSession{failed(0, 5), identified(15)},
+			expected: ReconciledHandshakeStats{Episodes: 1, SuccessfulEpisodes: 1, RecoveredEpisodes: 1, SuccessRate: 100},
+		},
+		{
+			name:     "repeated failures then recovery",
+			sessions: []ConnectionSession{failed(0, 5), failed(10, 12), identified(20)},
+			expected: ReconciledHandshakeStats{Episodes: 1, SuccessfulEpisodes: 1, RecoveredEpisodes: 1, SuccessRate: 100},
+		},
+		{
+			name:     "reconnect outside window",
+			sessions: []ConnectionSession{failed(0, 5), identified(120)},
+			expected: ReconciledHandshakeStats{Episodes: 2, SuccessfulEpisodes: 1, FailedEpisodes: 1, SuccessRate: 50},
+		},
+		{
+			name:     "reconnect after a success starts a new episode",
+			sessions: []ConnectionSession{{ConnectedAt: at(0), IdentifiedAt: at(1), DisconnectedAt: at(5), Disconnected: true}, failed(10, 15)},
+			expected: ReconciledHandshakeStats{Episodes: 2, SuccessfulEpisodes: 1, FailedEpisodes: 1, SuccessRate: 50},
+		},
+		{
+			name:     "sessions out of order",
+			sessions: []ConnectionSession{identified(15), failed(0, 5)},
+			expected: ReconciledHandshakeStats{Episodes: 1, SuccessfulEpisodes: 1, RecoveredEpisodes: 1, SuccessRate: 100},
+		},
+	}
+
+	calculator := NewStatsCalculator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peers := map[string]*Stats{"peer": {ConnectionSessions: tt.sessions}}
+
+			stats := calculator.CalculateReconciledHandshakes(peers, 30*time.Second)
+			tt.expected.RetryWindowSeconds = 30
+
+			if stats != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, stats)
+			}
+		})
+	}
+}
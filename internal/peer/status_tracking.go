@@ -0,0 +1,165 @@
+package peer
+
+import (
+	"sort"
+	"time"
+)
+
+// StalledPeer is a peer whose reported head slot stopped advancing.
+type StalledPeer struct {
+	PeerID         string    `json:"peer_id"`
+	ClientType     string    `json:"client_type"`
+	HeadSlot       uint64    `json:"head_slot"`
+	FinalizedEpoch uint64    `json:"finalized_epoch"`
+	StalledSince   time.Time `json:"stalled_since"`  // First status reporting the head slot
+	LastStatusAt   time.Time `json:"last_status_at"` // Last status still reporting it
+	StalledSeconds float64   `json:"stalled_seconds"`
+	Updates        int       `json:"updates"` // Statuses reporting the stalled head slot
+}
+
+// StatusTracking summarises the statuses peers sent us or answered our requests with over the
+// run. A peer whose head stops advancing is likely a stalled node, which tends to score us
+// poorly and prune us.
+type StatusTracking struct {
+	StallSeconds    float64       `json:"stall_seconds"` // Time without head progress after which a peer counts as stalled
+	Peers           int           `json:"peers"`         // Peers with at least one status
+	Updates         int           `json:"updates"`
+	Inbound         int           `json:"inbound"`  // Statuses the peers sent us
+	Failures        int           `json:"failures"` // Our requests that failed
+	HeadAdvancing   int           `json:"head_advancing"`
+	MedianLatencyMs float64       `json:"median_latency_ms"` // Of the answers to our first request in a session
+	MaxLatencyMs    float64       `json:"max_latency_ms"`
+	Stalled         []StalledPeer `json:"stalled"` // Longest stalled first
+
+	RetriedPeers      int                 `json:"retried_peers"`       // Peers at least one of our requests to failed
+	NeverIdentified   int                 `json:"never_identified"`    // Of them, peers none of our requests to succeeded
+	FailedThenGoodbye int                 `json:"failed_then_goodbye"` // Of them, peers that failed repeatedly in a session and then said goodbye
+	IdentifyRetries   []IdentifyRetryPeer `json:"identify_retries"`    // Most failed attempts first
+}
+
+// AnalyzeStatusUpdates summarises the peers' status updates across their sessions and flags
+// the peers whose head slot did not change for at least stall while they kept reporting it. It
+// also lists the peers some of our requests to failed, with the attempts identifying them took.
+func AnalyzeStatusUpdates(peers map[string]*Stats, stall time.Duration) *StatusTracking {
+	result := &StatusTracking{
+		StallSeconds:    stall.Seconds(),
+		Stalled:         make([]StalledPeer, 0),
+		IdentifyRetries: make([]IdentifyRetryPeer, 0),
+	}
+
+	latencies := make([]float64, 0)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		if attempts := stats.IdentifyAttempts(); attempts.Failed > 0 {
+			result.RetriedPeers++
+
+			if attempts.AttemptsToIdentify == 0 {
+				result.NeverIdentified++
+			}
+
+			if attempts.FailedThenGoodbye {
+				result.FailedThenGoodbye++
+			}
+
+			result.IdentifyRetries = append(result.IdentifyRetries, IdentifyRetryPeer{
+				PeerID:           peerID,
+				ClientType:       stats.ClientType,
+				IdentifyAttempts: attempts,
+			})
+		}
+
+		updates := make([]StatusUpdate, 0)
+
+		for _, session := range stats.ConnectionSessions {
+			for _, update := range session.StatusUpdates {
+				result.Updates++
+
+				if update.Inbound {
+					result.Inbound++
+				}
+
+				// Later requests are answered long after connecting, only the first shows the handshake
+				if update.LatencyMs > 0 && update.Attempt <= 1 {
+					latencies = append(latencies, update.LatencyMs)
+					result.MaxLatencyMs = max(result.MaxLatencyMs, update.LatencyMs)
+				}
+
+				if update.Error != "" {
+					result.Failures++
+
+					continue
+				}
+
+				updates = append(updates, update)
+			}
+		}
+
+		if len(updates) == 0 {
+			continue
+		}
+
+		result.Peers++
+
+		sort.SliceStable(updates, func(i, j int) bool {
+			return updates[i].Timestamp.Before(updates[j].Timestamp)
+		})
+
+		// The head slot counts as stalled from the first status reporting it until it changes
+		stalled := StalledPeer{
+			PeerID:       peerID,
+			ClientType:   stats.ClientType,
+			HeadSlot:     updates[0].HeadSlot,
+			StalledSince: updates[0].Timestamp,
+		}
+		advanced := false
+
+		for _, update := range updates {
+			if update.HeadSlot != stalled.HeadSlot {
+				advanced = advanced || update.HeadSlot > stalled.HeadSlot
+				stalled.HeadSlot = update.HeadSlot
+				stalled.StalledSince = update.Timestamp
+				stalled.Updates = 0
+			}
+
+			stalled.FinalizedEpoch = update.FinalizedEpoch
+			stalled.LastStatusAt = update.Timestamp
+			stalled.Updates++
+		}
+
+		if advanced {
+			result.HeadAdvancing++
+		}
+
+		stalledFor := stalled.LastStatusAt.Sub(stalled.StalledSince)
+		if stalled.Updates < 2 || stalledFor < stall {
+			continue
+		}
+
+		stalled.StalledSeconds = stalledFor.Seconds()
+		result.Stalled = append(result.Stalled, stalled)
+	}
+
+	result.MedianLatencyMs = median(latencies)
+
+	sort.Slice(result.Stalled, func(i, j int) bool {
+		if result.Stalled[i].StalledSeconds != result.Stalled[j].StalledSeconds {
+			return result.Stalled[i].StalledSeconds > result.Stalled[j].StalledSeconds
+		}
+
+		return result.Stalled[i].PeerID < result.Stalled[j].PeerID
+	})
+
+	sort.Slice(result.IdentifyRetries, func(i, j int) bool {
+		if result.IdentifyRetries[i].Failed != result.IdentifyRetries[j].Failed {
+			return result.IdentifyRetries[i].Failed > result.IdentifyRetries[j].Failed
+		}
+
+		return result.IdentifyRetries[i].PeerID < result.IdentifyRetries[j].PeerID
+	})
+
+	return result
+}
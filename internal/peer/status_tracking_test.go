@@ -0,0 +1,70 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeStatusUpdates(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	update := func(minutes int, headSlot uint64) StatusUpdate {
+		return StatusUpdate{Timestamp: start.Add(time.Duration(minutes) * time.Minute), HeadSlot: headSlot, FinalizedEpoch: headSlot / 32}
+	}
+
+	sessions := func(client string, sessions ...[]StatusUpdate) *Stats {
+		stats := &Stats{ClientType: client}
+		for _, updates := range sessions {
+			stats.ConnectionSessions = append(stats.ConnectionSessions, ConnectionSession{StatusUpdates: updates})
+		}
+
+		return stats
+	}
+
+	peers := map[string]*Stats{
+		// Head advances, then stalls for 15 minutes across a reconnect
+		"stalled": sessions("prysm",
+			[]StatusUpdate{{Timestamp: start, HeadSlot: 100, Attempt: 1, LatencyMs: 300}, {Timestamp: start.Add(5 * time.Minute), HeadSlot: 125, Attempt: 2, LatencyMs: 300000}},
+			[]StatusUpdate{update(10, 125), update(20, 125)},
+		),
+		// Head stays put for less than the threshold
+		"recent": sessions("teku", []StatusUpdate{update(0, 200), update(9, 200)}),
+		// Head advances with every status
+		"healthy": sessions("lighthouse", []StatusUpdate{
+			{Timestamp: start, HeadSlot: 10, LatencyMs: 100},
+			{Timestamp: start.Add(5 * time.Minute), HeadSlot: 35, Inbound: true},
+			{Timestamp: start.Add(10 * time.Minute), HeadSlot: 60, Inbound: true},
+		}),
+		// Our requests only ever failed
+		"failing": sessions("nimbus", []StatusUpdate{{Timestamp: start, Error: "stream reset", LatencyMs: 500}}),
+	}
+
+	result := AnalyzeStatusUpdates(peers, 10*time.Minute)
+
+	if result.Peers != 3 || result.Updates != 10 || result.Inbound != 2 || result.Failures != 1 || result.HeadAdvancing != 2 {
+		t.Errorf("Unexpected counts %+v", result)
+	}
+
+	if result.MedianLatencyMs != 300 || result.MaxLatencyMs != 500 {
+		t.Errorf("Expected median latency 300ms and max 500ms, got %.0f and %.0f", result.MedianLatencyMs, result.MaxLatencyMs)
+	}
+
+	want := StalledPeer{
+		PeerID:         "stalled",
+		ClientType:     "prysm",
+		HeadSlot:       125,
+		FinalizedEpoch: 3,
+		StalledSince:   start.Add(5 * time.Minute),
+		LastStatusAt:   start.Add(20 * time.Minute),
+		StalledSeconds: 900,
+		Updates:        3,
+	}
+
+	if len(result.Stalled) != 1 || result.Stalled[0] != want {
+		t.Errorf("Expected only %+v stalled, got %+v", want, result.Stalled)
+	}
+
+	if result.RetriedPeers != 1 || result.NeverIdentified != 1 || len(result.IdentifyRetries) != 1 || result.IdentifyRetries[0].PeerID != "failing" {
+		t.Errorf("Expected only the failing peer retried and never identified, got %+v", result)
+	}
+}
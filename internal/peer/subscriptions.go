@@ -0,0 +1,254 @@
+package peer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Gossip topic subscription event types, as traced by Hermes.
+const (
+	SubscriptionJoin  = "JOIN"
+	SubscriptionLeave = "LEAVE"
+)
+
+// TopicExpectation is a gossip topic the node should subscribe to for the active fork.
+type TopicExpectation struct {
+	Name    string `json:"name"`              // Topic name without the subnet suffix, e.g. beacon_attestation
+	Subnets int    `json:"subnets,omitempty"` // Subnets expected, 0 for topics without subnets
+}
+
+// TopicExpectations is the topic set expected for the fork the run started in.
+type TopicExpectations struct {
+	Fork       string             `json:"fork"`
+	ForkDigest string             `json:"fork_digest"` // Hex without 0x, as used in topic strings
+	Topics     []TopicExpectation `json:"topics"`
+}
+
+// SubscriptionEvent is a single JOIN or LEAVE of a gossip topic by the local node.
+type SubscriptionEvent struct {
+	Type      string    `json:"type"`
+	Topic     string    `json:"topic"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TopicSubscription summarises one topic's subscription over the run.
+type TopicSubscription struct {
+	Topic    string     `json:"topic"`
+	Name     string     `json:"name"`
+	JoinedAt time.Time  `json:"joined_at"`
+	LeftAt   *time.Time `json:"left_at,omitempty"` // Set if the topic was not subscribed at the end of the run
+}
+
+// TopicGroup summarises the subscriptions of one topic name across its subnets.
+type TopicGroup struct {
+	Name        string    `json:"name"`
+	Subscribed  int       `json:"subscribed"` // Topics still subscribed at the end of the run
+	Left        int       `json:"left"`
+	Expected    int       `json:"expected,omitempty"` // 0 when the topic was not expected or no check was made
+	FirstJoinAt time.Time `json:"first_join_at"`
+	LastJoinAt  time.Time `json:"last_join_at"`
+}
+
+// TopicShortfall is an expected topic that was not fully subscribed.
+type TopicShortfall struct {
+	Name       string `json:"name"`
+	Expected   int    `json:"expected"` // Subnets expected, 1 for topics without subnets
+	Subscribed int    `json:"subscribed"`
+}
+
+// SubscriptionReport records the node's gossip subscriptions and checks them against the expected set.
+type SubscriptionReport struct {
+	Fork       string              `json:"fork,omitempty"`
+	ForkDigest string              `json:"fork_digest,omitempty"`
+	Events     []SubscriptionEvent `json:"events"`
+	Topics     []TopicSubscription `json:"topics"`
+	Groups     []TopicGroup        `json:"groups"`
+	Expected   []TopicExpectation  `json:"expected,omitempty"`
+	Missing    []TopicShortfall    `json:"missing,omitempty"`
+	Unexpected []string            `json:"unexpected,omitempty"` // Subscribed topics outside the expected set or fork digest
+	Checked    bool                `json:"checked"`              // False when no expected set was available
+	Valid      bool                `json:"valid"`
+}
+
+// SubscriptionRecorder records the local node's gossip topic subscriptions.
+type SubscriptionRecorder struct {
+	mu     sync.Mutex
+	events []SubscriptionEvent
+}
+
+// NewSubscriptionRecorder creates an empty subscription recorder.
+func NewSubscriptionRecorder() *SubscriptionRecorder {
+	return &SubscriptionRecorder{events: make([]SubscriptionEvent, 0)}
+}
+
+// Record adds a JOIN or LEAVE of a topic.
+func (r *SubscriptionRecorder) Record(eventType, topic string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, SubscriptionEvent{Type: eventType, Topic: topic, Timestamp: at})
+}
+
+// Report builds the subscription timeline and checks it against the expected topics.
+// A nil expectation records the timeline without checking it.
+func (r *SubscriptionRecorder) Report(expected *TopicExpectations) *SubscriptionReport {
+	r.mu.Lock()
+	events := append([]SubscriptionEvent(nil), r.events...)
+	r.mu.Unlock()
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	report := &SubscriptionReport{
+		Events: events,
+		Topics: make([]TopicSubscription, 0),
+	}
+
+	// Fold the events into one entry per topic, keeping the first join and final leave
+	index := make(map[string]int)
+
+	for _, event := range events {
+		i, seen := index[event.Topic]
+
+		switch event.Type {
+		case SubscriptionJoin:
+			if !seen {
+				_, name, _ := ParseGossipTopic(event.Topic)
+				index[event.Topic] = len(report.Topics)
+				report.Topics = append(report.Topics, TopicSubscription{Topic: event.Topic, Name: name, JoinedAt: event.Timestamp})
+
+				continue
+			}
+
+			report.Topics[i].LeftAt = nil
+		case SubscriptionLeave:
+			if seen {
+				left := event.Timestamp
+				report.Topics[i].LeftAt = &left
+			}
+		}
+	}
+
+	report.Groups = groupTopics(report.Topics, expected)
+
+	if expected == nil {
+		return report
+	}
+
+	report.Fork = expected.Fork
+	report.ForkDigest = expected.ForkDigest
+	report.Expected = expected.Topics
+	report.Checked = true
+	report.Missing, report.Unexpected = checkSubscriptions(report.Topics, expected)
+	report.Valid = len(report.Missing) == 0 && len(report.Unexpected) == 0
+
+	return report
+}
+
+// groupTopics summarises the subscriptions by topic name, in order of first join.
+func groupTopics(topics []TopicSubscription, expected *TopicExpectations) []TopicGroup {
+	groups := make([]TopicGroup, 0)
+	index := make(map[string]int)
+
+	for _, topic := range topics {
+		i, seen := index[topic.Name]
+		if !seen {
+			i = len(groups)
+			index[topic.Name] = i
+			groups = append(groups, TopicGroup{Name: topic.Name, FirstJoinAt: topic.JoinedAt})
+		}
+
+		group := &groups[i]
+
+		if topic.LeftAt != nil {
+			group.Left++
+		} else {
+			group.Subscribed++
+		}
+
+		if topic.JoinedAt.Before(group.FirstJoinAt) {
+			group.FirstJoinAt = topic.JoinedAt
+		}
+
+		if topic.JoinedAt.After(group.LastJoinAt) {
+			group.LastJoinAt = topic.JoinedAt
+		}
+	}
+
+	if expected != nil {
+		for _, topic := range expected.Topics {
+			if i, seen := index[topic.Name]; seen {
+				groups[i].Expected = max(topic.Subnets, 1)
+			}
+		}
+	}
+
+	return groups
+}
+
+// checkSubscriptions compares the topics subscribed at the end of the run with the expected set.
+func checkSubscriptions(topics []TopicSubscription, expected *TopicExpectations) ([]TopicShortfall, []string) {
+	expectedNames := make(map[string]bool, len(expected.Topics))
+	for _, topic := range expected.Topics {
+		expectedNames[topic.Name] = true
+	}
+
+	subscribed := make(map[string]int)
+	unexpected := make([]string, 0)
+
+	for _, topic := range topics {
+		if topic.LeftAt != nil {
+			continue
+		}
+
+		digest, name, ok := ParseGossipTopic(topic.Topic)
+		if !ok || digest != expected.ForkDigest || !expectedNames[name] {
+			unexpected = append(unexpected, topic.Topic)
+
+			continue
+		}
+
+		subscribed[name]++
+	}
+
+	missing := make([]TopicShortfall, 0)
+
+	for _, topic := range expected.Topics {
+		want := max(topic.Subnets, 1)
+		if subscribed[topic.Name] < want {
+			missing = append(missing, TopicShortfall{Name: topic.Name, Expected: want, Subscribed: subscribed[topic.Name]})
+		}
+	}
+
+	sort.Strings(unexpected)
+
+	return missing, unexpected
+}
+
+// ParseGossipTopic splits an eth2 gossip topic such as /eth2/<digest>/beacon_attestation_5/ssz_snappy
+// into its fork digest and topic name, with any subnet suffix removed.
+func ParseGossipTopic(topic string) (digest, name string, ok bool) {
+	parts := strings.Split(topic, "/")
+	if len(parts) != 5 || parts[0] != "" || parts[1] != "eth2" {
+		return "", topic, false
+	}
+
+	name = parts[3]
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			name = name[:i]
+		}
+	}
+
+	return parts[2], name, true
+}
+
+// String describes the shortfall, e.g. "blob_sidecar (6 of 9)".
+func (s TopicShortfall) String() string {
+	return fmt.Sprintf("%s (%d of %d)", s.Name, s.Subscribed, s.Expected)
+}
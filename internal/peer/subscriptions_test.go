@@ -0,0 +1,139 @@
+package peer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseGossipTopic(t *testing.T) {
+	tests := []struct {
+		topic      string
+		wantDigest string
+		wantName   string
+		wantOK     bool
+	}{
+		{"/eth2/6a95a1a9/beacon_block/ssz_snappy", "6a95a1a9", "beacon_block", true},
+		{"/eth2/6a95a1a9/beacon_attestation_12/ssz_snappy", "6a95a1a9", "beacon_attestation", true},
+		{"/eth2/6a95a1a9/sync_committee_contribution_and_proof/ssz_snappy", "6a95a1a9", "sync_committee_contribution_and_proof", true},
+		{"/meshsub/1.1.0", "", "/meshsub/1.1.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			digest, name, ok := ParseGossipTopic(tt.topic)
+			if digest != tt.wantDigest || name != tt.wantName || ok != tt.wantOK {
+				t.Errorf("ParseGossipTopic() = %q, %q, %v, want %q, %q, %v", digest, name, ok, tt.wantDigest, tt.wantName, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSubscriptionRecorderReport(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	topic := func(digest, name string) string {
+		return "/eth2/" + digest + "/" + name + "/ssz_snappy"
+	}
+
+	expected := &TopicExpectations{
+		Fork:       "electra",
+		ForkDigest: "aaaa0000",
+		Topics: []TopicExpectation{
+			{Name: "beacon_block"},
+			{Name: "blob_sidecar", Subnets: 3},
+		},
+	}
+
+	type event struct {
+		eventType string
+		topic     string
+		offset    time.Duration
+	}
+
+	tests := []struct {
+		name           string
+		events         []event
+		expected       *TopicExpectations
+		wantValid      bool
+		wantChecked    bool
+		wantMissing    []TopicShortfall
+		wantUnexpected []string
+	}{
+		{
+			name: "all expected topics subscribed",
+			events: []event{
+				{SubscriptionJoin, topic("aaaa0000", "beacon_block"), 0},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_0"), time.Second},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_1"), time.Second},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_2"), time.Second},
+			},
+			expected:    expected,
+			wantValid:   true,
+			wantChecked: true,
+		},
+		{
+			name: "pre-Electra blob subnet count and a stale fork digest",
+			events: []event{
+				{SubscriptionJoin, topic("aaaa0000", "beacon_block"), 0},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_0"), 0},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_1"), 0},
+				{SubscriptionJoin, topic("bbbb1111", "blob_sidecar_2"), 0},
+			},
+			expected:       expected,
+			wantChecked:    true,
+			wantMissing:    []TopicShortfall{{Name: "blob_sidecar", Expected: 3, Subscribed: 2}},
+			wantUnexpected: []string{topic("bbbb1111", "blob_sidecar_2")},
+		},
+		{
+			name: "left topics no longer count",
+			events: []event{
+				{SubscriptionJoin, topic("aaaa0000", "beacon_block"), 0},
+				{SubscriptionLeave, topic("aaaa0000", "beacon_block"), time.Minute},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_0"), 0},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_1"), 0},
+				{SubscriptionJoin, topic("aaaa0000", "blob_sidecar_2"), 0},
+			},
+			expected:    expected,
+			wantChecked: true,
+			wantMissing: []TopicShortfall{{Name: "beacon_block", Expected: 1, Subscribed: 0}},
+		},
+		{
+			name: "no expectation records without checking",
+			events: []event{
+				{SubscriptionJoin, topic("aaaa0000", "beacon_block"), 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := NewSubscriptionRecorder()
+			for _, e := range tt.events {
+				recorder.Record(e.eventType, e.topic, start.Add(e.offset))
+			}
+
+			report := recorder.Report(tt.expected)
+
+			if report.Valid != tt.wantValid || report.Checked != tt.wantChecked {
+				t.Errorf("Valid = %v, Checked = %v, want %v, %v", report.Valid, report.Checked, tt.wantValid, tt.wantChecked)
+			}
+
+			if len(report.Missing) > 0 || len(tt.wantMissing) > 0 {
+				if !reflect.DeepEqual(report.Missing, tt.wantMissing) {
+					t.Errorf("Missing = %+v, want %+v", report.Missing, tt.wantMissing)
+				}
+			}
+
+			if len(report.Unexpected) > 0 || len(tt.wantUnexpected) > 0 {
+				if !reflect.DeepEqual(report.Unexpected, tt.wantUnexpected) {
+					t.Errorf("Unexpected = %v, want %v", report.Unexpected, tt.wantUnexpected)
+				}
+			}
+
+			if len(report.Events) != len(tt.events) {
+				t.Errorf("Events = %d, want %d", len(report.Events), len(tt.events))
+			}
+		})
+	}
+}
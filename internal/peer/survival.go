@@ -0,0 +1,177 @@
+package peer
+
+import (
+	"sort"
+	"time"
+)
+
+// survivalCheckpoints are the session lengths the survival curve is reported at.
+var survivalCheckpoints = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+// SessionSurvival summarises how long sessions last, treating sessions the run itself ended as
+// right-censored: they lasted at least as long as observed, but their true end is unknown.
+// Counting them as ended would bias durations down, dropping them would bias towards short
+// sessions, so the survival curve is a Kaplan-Meier estimate using both.
+type SessionSurvival struct {
+	Sessions         int             `json:"sessions"`
+	Completed        int             `json:"completed"`         // Sessions the run observed ending
+	Censored         int             `json:"censored"`          // Sessions the run ended, by any of the causes below
+	OpenAtEnd        int             `json:"open_at_end"`       // Still open when the report was generated
+	EndedInShutdown  int             `json:"ended_in_shutdown"` // Closed while Hermes shut down after the run
+	EndedByGap       int             `json:"ended_by_gap"`      // Closed at a checkpoint because the collector was down
+	EndedByRestart   int             `json:"ended_by_restart"`  // Closed by a MaxPeers ramp restart
+	NaiveMeanSeconds float64         `json:"naive_mean_seconds"`
+	MeanSeconds      float64         `json:"mean_seconds"`             // Mean of the completed sessions only
+	MedianSeconds    *float64        `json:"median_seconds,omitempty"` // Kaplan-Meier median, unset while over half the sessions survive
+	Curve            []SurvivalPoint `json:"curve"`
+}
+
+// SurvivalPoint is the estimated share of sessions lasting at least Seconds.
+type SurvivalPoint struct {
+	Seconds  float64 `json:"seconds"`
+	Survival float64 `json:"survival"`
+	AtRisk   int     `json:"at_risk"` // Sessions observed for at least Seconds
+}
+
+// observedSession is a session's observed length and whether it was censored.
+type observedSession struct {
+	seconds  float64
+	censored bool
+}
+
+// censored reports whether the run, rather than the peer or the network, ended the session:
+// it was still open at the end, or closed by shutdown, a checkpoint gap or a ramp restart.
+func (s *ConnectionSession) censored() bool {
+	return !s.Disconnected || s.EndedInShutdown || s.EndedByGap || s.EndedByRampRestart
+}
+
+// AnalyzeSessionSurvival marks the sessions the run ended as censored and estimates how long
+// sessions last. Sessions still open are observed up to end. Run it after the shutdown and
+// ramp analyses, which tag the sessions they closed.
+func AnalyzeSessionSurvival(peers map[string]*Stats, end time.Time) *SessionSurvival {
+	survival := &SessionSurvival{Curve: make([]SurvivalPoint, 0, len(survivalCheckpoints))}
+	observed := make([]observedSession, 0)
+
+	var naiveTotal, completedTotal float64
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			if session.ConnectedAt == nil {
+				continue
+			}
+
+			session.Censored = session.censored()
+
+			var length time.Duration
+
+			if session.Disconnected {
+				duration, ok := sessionDuration(*session)
+				if !ok {
+					continue
+				}
+
+				length = duration
+			} else {
+				length = end.Sub(*session.ConnectedAt)
+			}
+
+			seconds := max(length.Seconds(), 0)
+
+			survival.Sessions++
+			naiveTotal += seconds
+
+			observed = append(observed, observedSession{seconds: seconds, censored: session.Censored})
+
+			if !session.Censored {
+				survival.Completed++
+				completedTotal += seconds
+
+				continue
+			}
+
+			survival.Censored++
+
+			switch {
+			case !session.Disconnected:
+				survival.OpenAtEnd++
+			case session.EndedInShutdown:
+				survival.EndedInShutdown++
+			case session.EndedByGap:
+				survival.EndedByGap++
+			default:
+				survival.EndedByRestart++
+			}
+		}
+	}
+
+	if survival.Sessions > 0 {
+		survival.NaiveMeanSeconds = naiveTotal / float64(survival.Sessions)
+	}
+
+	if survival.Completed > 0 {
+		survival.MeanSeconds = completedTotal / float64(survival.Completed)
+	}
+
+	kaplanMeier(survival, observed)
+
+	return survival
+}
+
+// kaplanMeier estimates the survival curve at the checkpoints and the median session length.
+// At each observed end the survival drops by the share of sessions at risk that ended then.
+// Censored sessions count as at risk up to their observed length and never as ended.
+// Checkpoints past the longest session cannot be estimated and are left out.
+func kaplanMeier(survival *SessionSurvival, observed []observedSession) {
+	sort.Slice(observed, func(i, j int) bool {
+		return observed[i].seconds < observed[j].seconds
+	})
+
+	estimate := 1.0
+	atRisk := len(observed)
+	checkpoint := 0
+
+	for i := 0; i < len(observed); {
+		seconds := observed[i].seconds
+
+		for checkpoint < len(survivalCheckpoints) && survivalCheckpoints[checkpoint].Seconds() <= seconds {
+			survival.Curve = append(survival.Curve, SurvivalPoint{
+				Seconds:  survivalCheckpoints[checkpoint].Seconds(),
+				Survival: estimate,
+				AtRisk:   atRisk,
+			})
+			checkpoint++
+		}
+
+		ended, left := 0, 0
+		for ; i < len(observed) && observed[i].seconds == seconds; i++ {
+			if !observed[i].censored {
+				ended++
+			}
+
+			left++
+		}
+
+		if ended > 0 {
+			estimate *= 1 - float64(ended)/float64(atRisk)
+
+			if survival.MedianSeconds == nil && estimate <= 0.5 {
+				median := seconds
+				survival.MedianSeconds = &median
+			}
+		}
+
+		atRisk -= left
+	}
+}
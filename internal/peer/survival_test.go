@@ -0,0 +1,96 @@
+package peer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeSessionSurvival(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	ended := func(seconds int) ConnectionSession {
+		return ConnectionSession{ConnectedAt: at(0), DisconnectedAt: at(seconds), Disconnected: true}
+	}
+
+	shutdown := ended(60)
+	shutdown.EndedInShutdown = true
+
+	peers := map[string]*Stats{
+		"a": {ConnectionSessions: []ConnectionSession{ended(30)}},
+		"b": {ConnectionSessions: []ConnectionSession{ended(120)}},
+		"c": {ConnectionSessions: []ConnectionSession{{ConnectedAt: at(0)}}},
+		"d": {ConnectionSessions: []ConnectionSession{shutdown}},
+		"e": {ConnectionSessions: []ConnectionSession{ended(400)}},
+	}
+
+	survival := AnalyzeSessionSurvival(peers, start.Add(600*time.Second))
+
+	if survival.Sessions != 5 || survival.Completed != 3 || survival.Censored != 2 ||
+		survival.OpenAtEnd != 1 || survival.EndedInShutdown != 1 {
+		t.Errorf("Unexpected session counts %+v", survival)
+	}
+
+	if !peers["c"].ConnectionSessions[0].Censored || !peers["d"].ConnectionSessions[0].Censored || peers["a"].ConnectionSessions[0].Censored {
+		t.Error("Expected only the open and the shutdown session marked censored")
+	}
+
+	if survival.NaiveMeanSeconds != 242 || math.Abs(survival.MeanSeconds-550.0/3) > 1e-9 {
+		t.Errorf("Expected means 242 and 183.3, got %v and %v", survival.NaiveMeanSeconds, survival.MeanSeconds)
+	}
+
+	// Kaplan-Meier: 30s ends 1 of 5, 60s is censored, 120s ends 1 of 3, 400s ends 1 of 2, 600s
+	// is censored. Checkpoints past it cannot be estimated.
+	if survival.MedianSeconds == nil || *survival.MedianSeconds != 400 {
+		t.Errorf("Expected a median of 400s, got %v", survival.MedianSeconds)
+	}
+
+	want := []SurvivalPoint{
+		{Seconds: 60, Survival: 0.8, AtRisk: 4},
+		{Seconds: 300, Survival: 0.8 * 2 / 3, AtRisk: 2},
+	}
+
+	if len(survival.Curve) != len(want) {
+		t.Fatalf("Expected %d curve points up to the longest session, got %+v", len(want), survival.Curve)
+	}
+
+	for i, point := range want {
+		got := survival.Curve[i]
+		if got.Seconds != point.Seconds || math.Abs(got.Survival-point.Survival) > 1e-9 || got.AtRisk != point.AtRisk {
+			t.Errorf("Expected curve point %+v, got %+v", point, got)
+		}
+	}
+
+	// Censored sessions stay out of the completed durations
+	if stats := NewStatsCalculator().CalculateDurationStats(peers); stats.CensoredSessions != 2 {
+		t.Errorf("Expected 2 censored sessions in the duration statistics, got %d", stats.CensoredSessions)
+	}
+
+	if _, ok := completedDuration(peers["d"].ConnectionSessions[0]); ok {
+		t.Error("Expected no completed duration for a session closed in shutdown")
+	}
+}
+
+func TestAnalyzeSessionSurvivalAllOpen(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	peers := map[string]*Stats{
+		"a": {ConnectionSessions: []ConnectionSession{{ConnectedAt: &start}}},
+	}
+
+	survival := AnalyzeSessionSurvival(peers, start.Add(2*time.Hour))
+
+	if survival.MedianSeconds != nil || survival.Completed != 0 || survival.Censored != 1 {
+		t.Errorf("Expected no median while every session outlasted the run, got %+v", survival)
+	}
+
+	if len(survival.Curve) != 4 || survival.Curve[3].Seconds != 3600 || survival.Curve[3].Survival != 1 {
+		t.Errorf("Expected full survival up to an hour, got %+v", survival.Curve)
+	}
+}
@@ -0,0 +1,154 @@
+package peer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Swimlane marker kinds.
+const (
+	MarkerGoodbye   = "goodbye"
+	MarkerPrune     = "prune"
+	MarkerScoreDrop = "score_drop"
+)
+
+// SwimlaneMarker is a point event on a peer's swimlane.
+type SwimlaneMarker struct {
+	At     float64 `json:"t"` // Seconds from the run start
+	Kind   string  `json:"k"`
+	Detail string  `json:"d,omitempty"`
+}
+
+// Swimlane is one peer's row in the swimlane view: when it was connected, and the events
+// worth marking along the way. Field names are short, as the view embeds every lane.
+type Swimlane struct {
+	PeerID     string           `json:"p"`
+	ClientType string           `json:"c,omitempty"`
+	Sessions   int              `json:"n"`
+	Spans      [][2]float64     `json:"s"` // Connected periods as [from, to] seconds from the run start
+	Markers    []SwimlaneMarker `json:"m"`
+}
+
+// Swimlanes is the compact timeline of the most churning peers in a run.
+type Swimlanes struct {
+	Start   time.Time  `json:"start"`
+	Seconds float64    `json:"seconds"` // Length of the run, the extent of the time axis
+	Peers   int        `json:"peers"`   // Peers with at least one session, of which Lanes shows the top
+	Lanes   []Swimlane `json:"lanes"`
+}
+
+// CalculateSwimlanes builds the swimlanes of the limit peers with the most sessions, ties
+// broken by the number of markers. Sessions still open at the end of the run extend to end.
+func CalculateSwimlanes(peers map[string]*Stats, start, end time.Time, limit int) *Swimlanes {
+	swimlanes := &Swimlanes{
+		Start:   start,
+		Seconds: math.Max(end.Sub(start).Seconds(), 0),
+		Lanes:   make([]Swimlane, 0),
+	}
+
+	offset := func(at time.Time) float64 {
+		return math.Round(math.Min(math.Max(at.Sub(start).Seconds(), 0), swimlanes.Seconds)*10) / 10
+	}
+
+	for peerID, stats := range peers {
+		if stats == nil || len(stats.ConnectionSessions) == 0 {
+			continue
+		}
+
+		swimlanes.Peers++
+
+		lane := Swimlane{
+			PeerID:     peerID,
+			ClientType: stats.ClientType,
+			Sessions:   len(stats.ConnectionSessions),
+			Spans:      make([][2]float64, 0, len(stats.ConnectionSessions)),
+			Markers:    make([]SwimlaneMarker, 0),
+		}
+
+		for _, session := range stats.ConnectionSessions {
+			if session.ConnectedAt != nil {
+				to := end
+				if session.Disconnected && session.DisconnectedAt != nil {
+					to = *session.DisconnectedAt
+				}
+
+				lane.Spans = append(lane.Spans, [2]float64{offset(*session.ConnectedAt), offset(to)})
+			}
+
+			for _, goodbye := range session.GoodbyeEvents {
+				lane.Markers = append(lane.Markers, SwimlaneMarker{
+					At:     offset(goodbye.Timestamp),
+					Kind:   MarkerGoodbye,
+					Detail: fmt.Sprintf("%s (code %d)", goodbye.Reason, goodbye.Code),
+				})
+			}
+
+			for _, event := range session.MeshEvents {
+				if event.Type == "PRUNE" {
+					lane.Markers = append(lane.Markers, SwimlaneMarker{At: offset(event.Timestamp), Kind: MarkerPrune, Detail: event.Topic})
+				}
+			}
+
+			lane.Markers = append(lane.Markers, scoreDrops(session.PeerScores, offset)...)
+		}
+
+		sort.Slice(lane.Markers, func(i, j int) bool {
+			return lane.Markers[i].At < lane.Markers[j].At
+		})
+
+		swimlanes.Lanes = append(swimlanes.Lanes, lane)
+	}
+
+	sort.Slice(swimlanes.Lanes, func(i, j int) bool {
+		a, b := swimlanes.Lanes[i], swimlanes.Lanes[j]
+		if a.Sessions != b.Sessions {
+			return a.Sessions > b.Sessions
+		}
+
+		if len(a.Markers) != len(b.Markers) {
+			return len(a.Markers) > len(b.Markers)
+		}
+
+		return a.PeerID < b.PeerID
+	})
+
+	if len(swimlanes.Lanes) > limit {
+		swimlanes.Lanes = swimlanes.Lanes[:limit]
+	}
+
+	return swimlanes
+}
+
+// SwimlanesFromInterface builds swimlanes from report peer data.
+func SwimlanesFromInterface(peers map[string]interface{}, start, end time.Time, limit int) *Swimlanes {
+	return CalculateSwimlanes(statsFromInterface(peers), start, end, limit)
+}
+
+// scoreDrops marks the snapshots where a peer's score fell by at least the configured drop
+// since the previous snapshot.
+func scoreDrops(snapshots []PeerScoreSnapshot, offset func(time.Time) float64) []SwimlaneMarker {
+	ordered := make([]PeerScoreSnapshot, len(snapshots))
+	copy(ordered, snapshots)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Timestamp.Before(ordered[j].Timestamp)
+	})
+
+	markers := make([]SwimlaneMarker, 0)
+
+	for i := 1; i < len(ordered); i++ {
+		if ordered[i-1].Score-ordered[i].Score >= constants.SwimlaneScoreDrop {
+			markers = append(markers, SwimlaneMarker{
+				At:     offset(ordered[i].Timestamp),
+				Kind:   MarkerScoreDrop,
+				Detail: fmt.Sprintf("%.2f to %.2f", ordered[i-1].Score, ordered[i].Score),
+			})
+		}
+	}
+
+	return markers
+}
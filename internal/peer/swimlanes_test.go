@@ -0,0 +1,85 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateSwimlanes(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Minute)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	peers := map[string]*Stats{
+		"flapping": {
+			ClientType: "lighthouse",
+			ConnectionSessions: []ConnectionSession{
+				{
+					ConnectedAt: at(10), DisconnectedAt: at(70), Disconnected: true,
+					GoodbyeEvents: []GoodbyeEvent{{Timestamp: *at(69), Code: 129, Reason: "too many peers"}},
+					MeshEvents:    []MeshEvent{{Timestamp: *at(30), Type: "GRAFT"}, {Timestamp: *at(40), Type: "PRUNE", Topic: "beacon_block"}},
+					PeerScores: []PeerScoreSnapshot{
+						{Timestamp: *at(50), Score: 5},
+						{Timestamp: *at(20), Score: 20},
+						{Timestamp: *at(60), Score: -10},
+					},
+				},
+				{ConnectedAt: at(100)},
+			},
+		},
+		"quiet":    {ConnectionSessions: []ConnectionSession{{ConnectedAt: at(0)}}},
+		"marked":   {ConnectionSessions: []ConnectionSession{{ConnectedAt: at(0), GoodbyeEvents: []GoodbyeEvent{{Timestamp: *at(5)}}}}},
+		"early":    {ConnectionSessions: []ConnectionSession{{ConnectedAt: at(-30), DisconnectedAt: at(-10), Disconnected: true}}},
+		"no-peers": {},
+	}
+
+	swimlanes := CalculateSwimlanes(peers, start, end, 3)
+
+	if swimlanes.Peers != 4 || swimlanes.Seconds != 600 {
+		t.Fatalf("expected 4 peers over 600s, got %d over %f", swimlanes.Peers, swimlanes.Seconds)
+	}
+
+	if len(swimlanes.Lanes) != 3 {
+		t.Fatalf("expected the lanes to be limited to 3, got %d", len(swimlanes.Lanes))
+	}
+
+	// Most sessions first, then most markers, then peer ID
+	for i, want := range []string{"flapping", "marked", "early"} {
+		if swimlanes.Lanes[i].PeerID != want {
+			t.Errorf("expected lane %d to be %s, got %s", i, want, swimlanes.Lanes[i].PeerID)
+		}
+	}
+
+	flapping := swimlanes.Lanes[0]
+
+	if len(flapping.Spans) != 2 || flapping.Spans[0] != [2]float64{10, 70} || flapping.Spans[1] != [2]float64{100, 600} {
+		t.Errorf("expected an ended and an open span, got %v", flapping.Spans)
+	}
+
+	wantMarkers := []SwimlaneMarker{
+		{At: 40, Kind: MarkerPrune, Detail: "beacon_block"},
+		{At: 50, Kind: MarkerScoreDrop, Detail: "20.00 to 5.00"},
+		{At: 60, Kind: MarkerScoreDrop, Detail: "5.00 to -10.00"},
+		{At: 69, Kind: MarkerGoodbye, Detail: "too many peers (code 129)"},
+	}
+
+	if len(flapping.Markers) != len(wantMarkers) {
+		t.Fatalf("expected %d markers, got %+v", len(wantMarkers), flapping.Markers)
+	}
+
+	for i, want := range wantMarkers {
+		if flapping.Markers[i] != want {
+			t.Errorf("marker %d = %+v, want %+v", i, flapping.Markers[i], want)
+		}
+	}
+
+	// Sessions before the run start are clamped to it
+	if early := swimlanes.Lanes[2]; early.Spans[0] != [2]float64{0, 0} {
+		t.Errorf("expected the early span to be clamped, got %v", early.Spans)
+	}
+}
@@ -0,0 +1,132 @@
+package peer
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kinds of the request streams Hermes resets. Hermes never sends a goodbye, so a failed
+// request handler is the only termination it initiates besides ending whole sessions.
+const (
+	TerminationRateLimited = "rate_limited"      // The request was refused for its rate
+	TerminationMalformed   = "malformed_request" // Hermes could not read the request
+	TerminationTimeout     = "timeout"           // Hermes gave up waiting on the stream
+	TerminationError       = "handler_error"     // Handling the request failed otherwise
+)
+
+// Reasons of the sessions we ended, in the disconnect reasons.
+const (
+	ReasonLocalLimit    = "closed at our peer limit"
+	ReasonResourceLimit = "closed by our resource manager"
+)
+
+// DisconnectReasonCount counts the terminations one side initiated with one code and reason.
+// Our terminations carry no goodbye code.
+type DisconnectReasonCount struct {
+	Side   string `json:"side"` // One of the Initiator constants
+	Code   uint64 `json:"code"`
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// ClassifyLocalTermination classifies the error Hermes traced for a request it failed to
+// handle. It reports false when the peer reset or closed the stream first, which is not our
+// termination.
+func ClassifyLocalTermination(reason string) (string, bool) {
+	lower := strings.ToLower(reason)
+
+	if strings.Contains(lower, "stream reset") || strings.Contains(lower, "closed") || strings.Contains(lower, "eof") {
+		return "", false
+	}
+
+	if kind, ok := ClassifyReqRespError(reason); ok {
+		if kind == ReqRespAbuseRateLimit {
+			return TerminationRateLimited, true
+		}
+
+		return TerminationMalformed, true
+	}
+
+	switch {
+	case lower == "":
+		return "", false
+	case strings.Contains(lower, "deadline"), strings.Contains(lower, "timeout"):
+		return TerminationTimeout, true
+	default:
+		return TerminationError, true
+	}
+}
+
+// disconnectReasonKey identifies a disconnect reason by its side, code and reason.
+type disconnectReasonKey struct {
+	side   string
+	code   uint64
+	reason string
+}
+
+// TopDisconnectReasons returns the most frequent reasons either side ended a session or a
+// request for, at most limit: the goodbyes the peers sent us before the run's shutdown, the
+// sessions our limits ended and the request streams Hermes reset.
+func TopDisconnectReasons(peers map[string]*Stats, limit int) []DisconnectReasonCount {
+	counts := make(map[disconnectReasonKey]int)
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+
+			for _, goodbye := range churnGoodbyes(session) {
+				counts[disconnectReasonKey{side: InitiatorRemote, code: goodbye.Code, reason: strings.TrimSpace(goodbye.Reason)}]++
+			}
+
+			switch {
+			case !churned(session):
+			case session.EndedByLocalLimit:
+				counts[disconnectReasonKey{side: InitiatorLocal, reason: ReasonLocalLimit}]++
+			case session.EndedByResourceLimit:
+				counts[disconnectReasonKey{side: InitiatorLocal, reason: ReasonResourceLimit}]++
+			}
+
+			for _, termination := range session.LocalTerminations {
+				reason := "reset " + termination.Protocol + " stream: " + termination.Kind
+				counts[disconnectReasonKey{side: InitiatorLocal, reason: reason}]++
+			}
+		}
+	}
+
+	reasons := make([]DisconnectReasonCount, 0, len(counts))
+	for key, count := range counts {
+		reasons = append(reasons, DisconnectReasonCount{Side: key.side, Code: key.code, Reason: key.reason, Count: count})
+	}
+
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+
+		if reasons[i].Side != reasons[j].Side {
+			return reasons[i].Side > reasons[j].Side
+		}
+
+		if reasons[i].Code != reasons[j].Code {
+			return reasons[i].Code < reasons[j].Code
+		}
+
+		return reasons[i].Reason < reasons[j].Reason
+	})
+
+	if len(reasons) > limit {
+		reasons = reasons[:limit]
+	}
+
+	return reasons
+}
+
+// TopDisconnectReasonsFromInterface returns the most frequent disconnect reasons in generic
+// peer data.
+func TopDisconnectReasonsFromInterface(peers map[string]interface{}, limit int) []DisconnectReasonCount {
+	return TopDisconnectReasons(statsFromInterface(peers), limit)
+}
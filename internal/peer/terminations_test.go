@@ -0,0 +1,63 @@
+package peer
+
+import "testing"
+
+func TestClassifyLocalTermination(t *testing.T) {
+	tests := []struct {
+		reason string
+		kind   string
+		local  bool
+	}{
+		{reason: ""},
+		{reason: "rate limited", kind: TerminationRateLimited, local: true},
+		{reason: "read request data *pb.Status: snappy: corrupt input", kind: TerminationMalformed, local: true},
+		{reason: "read request data *pb.Status: i/o deadline reached", kind: TerminationTimeout, local: true},
+		{reason: "failed to forward status to the beacon node", kind: TerminationError, local: true},
+		{reason: "stream reset"},
+		{reason: "read request data *pb.Status: EOF"},
+	}
+
+	for _, tt := range tests {
+		if kind, local := ClassifyLocalTermination(tt.reason); kind != tt.kind || local != tt.local {
+			t.Errorf("ClassifyLocalTermination(%q) = %q, %t, want %q, %t", tt.reason, kind, local, tt.kind, tt.local)
+		}
+	}
+}
+
+func TestTopDisconnectReasons(t *testing.T) {
+	peers := map[string]*Stats{
+		"a": {ConnectionSessions: []ConnectionSession{
+			{Disconnected: true, GoodbyeEvents: []GoodbyeEvent{{Code: 129, Reason: "too many peers "}}},
+			{Disconnected: true, GoodbyeEvents: []GoodbyeEvent{{Code: 129, Reason: "too many peers"}}},
+			{Disconnected: true, EndedByLocalLimit: true},
+		}},
+		"b": {ConnectionSessions: []ConnectionSession{
+			{Disconnected: true, EndedByResourceLimit: true, LocalTerminations: []LocalTermination{{Kind: TerminationTimeout, Protocol: "HANDLE_PING"}}},
+			{Disconnected: true, EndedInShutdown: true, EndedByLocalLimit: true, GoodbyeEvents: []GoodbyeEvent{{Code: 1, InShutdown: true}}},
+		}},
+		"c": nil,
+	}
+
+	reasons := TopDisconnectReasons(peers, 10)
+
+	want := []DisconnectReasonCount{
+		{Side: InitiatorRemote, Code: 129, Reason: "too many peers", Count: 2},
+		{Side: InitiatorLocal, Reason: ReasonLocalLimit, Count: 1},
+		{Side: InitiatorLocal, Reason: ReasonResourceLimit, Count: 1},
+		{Side: InitiatorLocal, Reason: "reset HANDLE_PING stream: timeout", Count: 1},
+	}
+
+	if len(reasons) != len(want) {
+		t.Fatalf("Expected %d reasons, got %+v", len(want), reasons)
+	}
+
+	for i := range want {
+		if reasons[i] != want[i] {
+			t.Errorf("Reason %d = %+v, want %+v", i, reasons[i], want[i])
+		}
+	}
+
+	if limited := TopDisconnectReasons(peers, 1); len(limited) != 1 || limited[0].Side != InitiatorRemote {
+		t.Errorf("Expected the limit to keep the most frequent reason, got %+v", limited)
+	}
+}
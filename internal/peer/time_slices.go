@@ -0,0 +1,142 @@
+package peer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// TimeSlice holds the headline statistics of one time slice of the run, so a degradation
+// partway through shows rather than being averaged away over the whole run.
+type TimeSlice struct {
+	Start                time.Time            `json:"start"`
+	End                  time.Time            `json:"end"`
+	Connections          int                  `json:"connections"` // Sessions that connected in the slice
+	SuccessfulHandshakes int                  `json:"successful_handshakes"`
+	FailedHandshakes     int                  `json:"failed_handshakes"`
+	HandshakeSuccessRate float64              `json:"handshake_success_rate"`
+	Disconnects          int                  `json:"disconnects"` // Sessions that ended in the slice
+	Goodbyes             int                  `json:"goodbyes"`
+	GoodbyeReasons       []GoodbyeReasonCount `json:"goodbye_reasons"` // Most frequent first
+	ScoredPeers          int                  `json:"scored_peers"`
+	MeanScore            float64              `json:"mean_score"` // Of each scored peer's mean score in the slice, by sampling weight
+}
+
+// CalculateTimeSlices computes headline statistics per slice of width from start to end. Handshakes
+// count in the slice their session connected in, as in the run's headline statistics.
+func CalculateTimeSlices(peers map[string]*Stats, start, end time.Time, width time.Duration) []TimeSlice {
+	if width <= 0 || !end.After(start) {
+		return nil
+	}
+
+	count := int((end.Sub(start) + width - 1) / width)
+	bounds := make([]time.Time, count+1)
+
+	for i := 0; i < count; i++ {
+		bounds[i] = start.Add(time.Duration(i) * width)
+	}
+
+	bounds[count] = end
+
+	return calculateWindows(peers, bounds)
+}
+
+// calculateWindows computes headline statistics per window between consecutive bounds, which
+// must be ascending. Sessions closed by a MaxPeers ramp restart are not counted as disconnects.
+func calculateWindows(peers map[string]*Stats, bounds []time.Time) []TimeSlice {
+	if len(bounds) < 2 {
+		return nil
+	}
+
+	count := len(bounds) - 1
+	slices := make([]TimeSlice, count)
+	reasons := make([]map[goodbyeReasonKey]*GoodbyeReasonCount, count)
+	scoreSums := make([]float64, count)
+	weightSums := make([]float64, count)
+
+	for i := range slices {
+		slices[i].Start = bounds[i]
+		slices[i].End = bounds[i+1]
+		reasons[i] = make(map[goodbyeReasonKey]*GoodbyeReasonCount)
+	}
+
+	// sliceOf returns the slice a time falls in, or -1 outside the bounds
+	sliceOf := func(t time.Time) int {
+		if t.Before(bounds[0]) || !t.Before(bounds[count]) {
+			return -1
+		}
+
+		return sort.Search(count, func(i int) bool { return t.Before(bounds[i+1]) })
+	}
+
+	for _, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		peerScores := make(map[int]*scoreAccumulator)
+
+		for _, session := range stats.ConnectionSessions {
+			if session.ConnectedAt != nil {
+				if i := sliceOf(*session.ConnectedAt); i >= 0 {
+					slices[i].Connections++
+
+					if session.IdentifiedAt != nil {
+						slices[i].SuccessfulHandshakes++
+					} else {
+						slices[i].FailedHandshakes++
+					}
+				}
+			}
+
+			if churned(&session) && session.DisconnectedAt != nil && !session.EndedByRampRestart {
+				if i := sliceOf(*session.DisconnectedAt); i >= 0 {
+					slices[i].Disconnects++
+				}
+			}
+
+			for _, goodbye := range churnGoodbyes(&session) {
+				if i := sliceOf(goodbye.Timestamp); i >= 0 {
+					slices[i].Goodbyes++
+					countGoodbyeReason(reasons[i], goodbye)
+				}
+			}
+
+			for _, snapshot := range session.PeerScores {
+				i := sliceOf(snapshot.Timestamp)
+				if i < 0 {
+					continue
+				}
+
+				if peerScores[i] == nil {
+					peerScores[i] = &scoreAccumulator{}
+				}
+
+				peerScores[i].add(snapshot.Score)
+			}
+		}
+
+		weight := stats.DetailWeight()
+
+		for i, acc := range peerScores {
+			slices[i].ScoredPeers++
+			scoreSums[i] += weight * acc.sum / float64(acc.count)
+			weightSums[i] += weight
+		}
+	}
+
+	for i := range slices {
+		if handshakes := slices[i].SuccessfulHandshakes + slices[i].FailedHandshakes; handshakes > 0 {
+			slices[i].HandshakeSuccessRate = float64(slices[i].SuccessfulHandshakes) / float64(handshakes)
+		}
+
+		if weightSums[i] > 0 {
+			slices[i].MeanScore = scoreSums[i] / weightSums[i]
+		}
+
+		slices[i].GoodbyeReasons = rankGoodbyeReasons(reasons[i], constants.TimeSliceReasonLimit)
+	}
+
+	return slices
+}
@@ -0,0 +1,78 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateTimeSlices(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(25 * time.Minute)
+
+	at := func(minutes int) *time.Time {
+		ts := start.Add(time.Duration(minutes) * time.Minute)
+
+		return &ts
+	}
+
+	score := func(minutes int, value float64) PeerScoreSnapshot {
+		return PeerScoreSnapshot{Timestamp: *at(minutes), Score: value}
+	}
+
+	peers := map[string]*Stats{
+		"steady": {ConnectionSessions: []ConnectionSession{{
+			ConnectedAt:  at(1),
+			IdentifiedAt: at(1),
+			PeerScores:   []PeerScoreSnapshot{score(2, 10), score(4, 20), score(12, 10), score(22, -40)},
+		}}},
+		"churner": {ConnectionSessions: []ConnectionSession{
+			{ConnectedAt: at(2), IdentifiedAt: at(2), DisconnectedAt: at(11), Disconnected: true,
+				GoodbyeEvents: []GoodbyeEvent{{Timestamp: *at(11), Code: 3, Reason: "client error"}}},
+			{ConnectedAt: at(21), DisconnectedAt: at(22), Disconnected: true,
+				GoodbyeEvents: []GoodbyeEvent{{Timestamp: *at(22), Code: 129, Reason: "client shutdown"}}},
+			{ConnectedAt: at(23), DisconnectedAt: at(24), Disconnected: true,
+				GoodbyeEvents: []GoodbyeEvent{{Timestamp: *at(24), Code: 129, Reason: " client shutdown"}}},
+		}},
+		"late": {ConnectionSessions: []ConnectionSession{{ConnectedAt: at(30)}}},
+	}
+
+	slices := CalculateTimeSlices(peers, start, end, 10*time.Minute)
+	if len(slices) != 3 {
+		t.Fatalf("got %d slices, want 3", len(slices))
+	}
+
+	if !slices[2].Start.Equal(*at(20)) || !slices[2].End.Equal(end) {
+		t.Errorf("last slice = %s to %s, want it clamped to the end of the run", slices[2].Start, slices[2].End)
+	}
+
+	first := slices[0]
+	if first.Connections != 2 || first.SuccessfulHandshakes != 2 || first.HandshakeSuccessRate != 1 || first.Disconnects != 0 {
+		t.Errorf("first slice = %+v, want 2 successful connections and no disconnects", first)
+	}
+
+	if first.ScoredPeers != 1 || first.MeanScore != 15 {
+		t.Errorf("first slice scores = %d peers, mean %.1f, want 1 peer, mean 15", first.ScoredPeers, first.MeanScore)
+	}
+
+	second := slices[1]
+	if second.Connections != 0 || second.Disconnects != 1 || second.Goodbyes != 1 || second.MeanScore != 10 {
+		t.Errorf("second slice = %+v, want 1 disconnect with a goodbye and mean score 10", second)
+	}
+
+	third := slices[2]
+	if third.Connections != 2 || third.FailedHandshakes != 2 || third.HandshakeSuccessRate != 0 || third.Disconnects != 2 {
+		t.Errorf("third slice = %+v, want 2 failed handshakes and 2 disconnects", third)
+	}
+
+	if len(third.GoodbyeReasons) != 1 || third.GoodbyeReasons[0].Reason != "client shutdown" || third.GoodbyeReasons[0].Count != 2 {
+		t.Errorf("third slice goodbye reasons = %+v, want client shutdown twice", third.GoodbyeReasons)
+	}
+
+	if third.MeanScore != -40 {
+		t.Errorf("third slice mean score = %.1f, want -40", third.MeanScore)
+	}
+
+	if slices := CalculateTimeSlices(peers, end, start, 10*time.Minute); slices != nil {
+		t.Errorf("got %d slices for a run ending before it started, want none", len(slices))
+	}
+}
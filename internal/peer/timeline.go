@@ -0,0 +1,226 @@
+package peer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventTimeline holds per-peer event counts in fixed-width time buckets, so bursts such as
+// hundreds of GRAFT/PRUNE flaps in one minute stand out from the totals.
+type EventTimeline struct {
+	Start          time.Time                   `json:"start"`
+	BucketSeconds  float64                     `json:"bucket_seconds"`
+	BurstThreshold int                         `json:"burst_threshold"`        // Events of one type in one bucket that count as a burst
+	Peers          map[string]map[string][]int `json:"peers"`                  // Peer ID -> event type -> count per bucket
+	Buckets        int                         `json:"buckets"`                // Buckets covered by the run
+	OutOfRange     int                         `json:"out_of_range,omitempty"` // Events stamped after the run window, left out of the buckets
+}
+
+// EventBurst is a bucket in which a peer sent at least the burst threshold of one event type.
+type EventBurst struct {
+	PeerID      string    `json:"peer_id"`
+	EventType   string    `json:"event_type"`
+	BucketStart time.Time `json:"bucket_start"`
+	Count       int       `json:"count"`
+}
+
+// Bursts returns the buckets at or above the burst threshold, largest first.
+func (tl *EventTimeline) Bursts(limit int) []EventBurst {
+	bursts := make([]EventBurst, 0)
+
+	if tl == nil || tl.BurstThreshold <= 0 {
+		return bursts
+	}
+
+	width := time.Duration(tl.BucketSeconds * float64(time.Second))
+
+	for peerID, types := range tl.Peers {
+		for eventType, counts := range types {
+			for i, count := range counts {
+				if count >= tl.BurstThreshold {
+					bursts = append(bursts, EventBurst{
+						PeerID:      peerID,
+						EventType:   eventType,
+						BucketStart: tl.Start.Add(time.Duration(i) * width),
+						Count:       count,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(bursts, func(i, j int) bool {
+		if bursts[i].Count != bursts[j].Count {
+			return bursts[i].Count > bursts[j].Count
+		}
+
+		if !bursts[i].BucketStart.Equal(bursts[j].BucketStart) {
+			return bursts[i].BucketStart.Before(bursts[j].BucketStart)
+		}
+
+		if bursts[i].PeerID != bursts[j].PeerID {
+			return bursts[i].PeerID < bursts[j].PeerID
+		}
+
+		return bursts[i].EventType < bursts[j].EventType
+	})
+
+	if limit > 0 && len(bursts) > limit {
+		bursts = bursts[:limit]
+	}
+
+	return bursts
+}
+
+// Retain drops the timelines of peers keep rejects, except for peers that burst, whose
+// buckets are needed to show the burst.
+func (tl *EventTimeline) Retain(keep func(peerID string) bool) {
+	if tl == nil {
+		return
+	}
+
+	for peerID, types := range tl.Peers {
+		if keep(peerID) || tl.bursts(types) {
+			continue
+		}
+
+		delete(tl.Peers, peerID)
+	}
+}
+
+// bursts reports whether any bucket of a peer's timeline reaches the burst threshold.
+func (tl *EventTimeline) bursts(types map[string][]int) bool {
+	if tl.BurstThreshold <= 0 {
+		return false
+	}
+
+	for _, counts := range types {
+		for _, count := range counts {
+			if count >= tl.BurstThreshold {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// TimelineRecorder buckets events by peer and type as they are processed.
+type TimelineRecorder struct {
+	mu             sync.Mutex
+	start          time.Time
+	end            time.Time
+	width          time.Duration
+	burstThreshold int
+	peers          map[string]map[string][]int
+	buckets        int
+	outOfRange     int
+}
+
+// NewTimelineRecorder creates a recorder with buckets of the given width, aligned to start,
+// covering the run window up to end.
+func NewTimelineRecorder(start, end time.Time, width time.Duration, burstThreshold int) *TimelineRecorder {
+	return &TimelineRecorder{
+		start:          start.Truncate(width),
+		end:            end,
+		width:          width,
+		burstThreshold: burstThreshold,
+		peers:          make(map[string]map[string][]int),
+	}
+}
+
+// SetEnd moves the end of the run window, once the run's phases are known.
+func (r *TimelineRecorder) SetEnd(end time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.end = end
+}
+
+// Record counts an event in the bucket its timestamp falls into. Events stamped before
+// the first bucket are counted in it. Events stamped at or after the end of the run window,
+// such as a far-future trace timestamp, are only counted as out of range, so they cannot
+// grow the buckets without bound.
+func (r *TimelineRecorder) Record(peerID, eventType string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !at.Before(r.end) {
+		r.outOfRange++
+		return
+	}
+
+	index := 0
+	if at.After(r.start) {
+		index = int(at.Sub(r.start) / r.width)
+	}
+
+	types, exists := r.peers[peerID]
+	if !exists {
+		types = make(map[string][]int)
+		r.peers[peerID] = types
+	}
+
+	counts := types[eventType]
+	for len(counts) <= index {
+		counts = append(counts, 0)
+	}
+
+	counts[index]++
+	types[eventType] = counts
+
+	r.buckets = max(r.buckets, index+1)
+}
+
+// Restore replaces the recorded buckets with a timeline restored from a checkpoint, keeping
+// its start and bucket width so counts recorded after resuming line up.
+func (r *TimelineRecorder) Restore(timeline *EventTimeline) {
+	if timeline == nil || timeline.BucketSeconds <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.start = timeline.Start
+	r.width = time.Duration(timeline.BucketSeconds * float64(time.Second))
+	r.buckets = timeline.Buckets
+	r.outOfRange = timeline.OutOfRange
+	r.peers = make(map[string]map[string][]int, len(timeline.Peers))
+
+	for peerID, types := range timeline.Peers {
+		copied := make(map[string][]int, len(types))
+		for eventType, counts := range types {
+			copied[eventType] = append([]int(nil), counts...)
+		}
+
+		r.peers[peerID] = copied
+	}
+}
+
+// Snapshot returns a copy of the recorded timeline.
+func (r *TimelineRecorder) Snapshot() *EventTimeline {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timeline := &EventTimeline{
+		Start:          r.start,
+		BucketSeconds:  r.width.Seconds(),
+		BurstThreshold: r.burstThreshold,
+		Peers:          make(map[string]map[string][]int, len(r.peers)),
+		Buckets:        r.buckets,
+		OutOfRange:     r.outOfRange,
+	}
+
+	for peerID, types := range r.peers {
+		copied := make(map[string][]int, len(types))
+		for eventType, counts := range types {
+			copied[eventType] = append([]int(nil), counts...)
+		}
+
+		timeline.Peers[peerID] = copied
+	}
+
+	return timeline
+}
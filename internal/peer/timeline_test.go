@@ -0,0 +1,178 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimelineRecorder(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC)
+
+	type event struct {
+		peerID    string
+		eventType string
+		offset    time.Duration
+	}
+
+	tests := []struct {
+		name           string
+		events         []event
+		wantBuckets    int
+		wantOutOfRange int
+		wantCounts     map[string]map[string][]int
+	}{
+		{
+			name:        "no events",
+			wantBuckets: 0,
+			wantCounts:  map[string]map[string][]int{},
+		},
+		{
+			name: "buckets align to the width",
+			events: []event{
+				{"a", "GRAFT", 0},
+				{"a", "GRAFT", 29 * time.Second},
+				{"a", "GRAFT", 30 * time.Second},
+				{"a", "PRUNE", 95 * time.Second},
+			},
+			wantBuckets: 3,
+			wantCounts: map[string]map[string][]int{
+				"a": {"GRAFT": {2, 1}, "PRUNE": {0, 0, 1}},
+			},
+		},
+		{
+			name: "events before the first bucket count in it",
+			events: []event{
+				{"a", "CONNECTED", -5 * time.Minute},
+				{"b", "CONNECTED", 0},
+			},
+			wantBuckets: 1,
+			wantCounts: map[string]map[string][]int{
+				"a": {"CONNECTED": {1}},
+				"b": {"CONNECTED": {1}},
+			},
+		},
+		{
+			name: "events after the run window are counted out of range",
+			events: []event{
+				{"a", "GRAFT", 0},
+				{"a", "GRAFT", time.Hour},
+				{"a", "PRUNE", 100 * 365 * 24 * time.Hour},
+			},
+			wantBuckets:    1,
+			wantOutOfRange: 2,
+			wantCounts: map[string]map[string][]int{
+				"a": {"GRAFT": {1}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := NewTimelineRecorder(start, start.Add(time.Hour), time.Minute, 10)
+
+			for _, e := range tt.events {
+				recorder.Record(e.peerID, e.eventType, start.Add(e.offset))
+			}
+
+			timeline := recorder.Snapshot()
+
+			if !timeline.Start.Equal(start.Truncate(time.Minute)) {
+				t.Errorf("expected start %v, got %v", start.Truncate(time.Minute), timeline.Start)
+			}
+
+			if timeline.Buckets != tt.wantBuckets {
+				t.Errorf("expected %d buckets, got %d", tt.wantBuckets, timeline.Buckets)
+			}
+
+			if timeline.OutOfRange != tt.wantOutOfRange {
+				t.Errorf("expected %d events out of range, got %d", tt.wantOutOfRange, timeline.OutOfRange)
+			}
+
+			if len(timeline.Peers) != len(tt.wantCounts) {
+				t.Fatalf("expected %d peers, got %d", len(tt.wantCounts), len(timeline.Peers))
+			}
+
+			for peerID, types := range tt.wantCounts {
+				for eventType, want := range types {
+					got := timeline.Peers[peerID][eventType]
+					if len(got) != len(want) {
+						t.Fatalf("expected %s %s counts %v, got %v", peerID, eventType, want, got)
+					}
+
+					for i := range want {
+						if got[i] != want[i] {
+							t.Errorf("expected %s %s counts %v, got %v", peerID, eventType, want, got)
+
+							break
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEventTimelineBursts(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	timeline := &EventTimeline{
+		Start:          start,
+		BucketSeconds:  60,
+		BurstThreshold: 100,
+		Peers: map[string]map[string][]int{
+			"a": {"GRAFT": {5, 250, 100}, "PRUNE": {0, 240}},
+			"b": {"PEERSCORE": {99, 99}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		timeline  *EventTimeline
+		limit     int
+		wantCount []int
+	}{
+		{name: "all bursts largest first", timeline: timeline, wantCount: []int{250, 240, 100}},
+		{name: "limited", timeline: timeline, limit: 1, wantCount: []int{250}},
+		{name: "disabled threshold", timeline: &EventTimeline{Peers: timeline.Peers}, wantCount: []int{}},
+		{name: "nil timeline", timeline: nil, wantCount: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bursts := tt.timeline.Bursts(tt.limit)
+			if len(bursts) != len(tt.wantCount) {
+				t.Fatalf("expected %d bursts, got %d", len(tt.wantCount), len(bursts))
+			}
+
+			for i, want := range tt.wantCount {
+				if bursts[i].Count != want {
+					t.Errorf("burst %d: expected count %d, got %d", i, want, bursts[i].Count)
+				}
+			}
+		})
+	}
+
+	bursts := timeline.Bursts(0)
+	if bursts[0].PeerID != "a" || bursts[0].EventType != "GRAFT" || !bursts[0].BucketStart.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected the largest burst to be peer a GRAFT at %v, got %+v", start.Add(time.Minute), bursts[0])
+	}
+}
+
+func TestEventTimelineRetain(t *testing.T) {
+	timeline := &EventTimeline{
+		BurstThreshold: 100,
+		Peers: map[string]map[string][]int{
+			"captured": {"PEERSCORE": {3}},
+			"bursting": {"GRAFT": {150}},
+			"dropped":  {"PEERSCORE": {99}},
+		},
+	}
+
+	timeline.Retain(func(peerID string) bool {
+		return peerID == "captured"
+	})
+
+	if len(timeline.Peers) != 2 || timeline.Peers["dropped"] != nil {
+		t.Errorf("expected captured and bursting peers to be kept, got %v", timeline.Peers)
+	}
+}
@@ -0,0 +1,289 @@
+package peer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Qualitative health of a gossip topic, see AnalyzeTopicHealth.
+const (
+	TopicHealthy   = "healthy"
+	TopicDegraded  = "degraded"
+	TopicUnhealthy = "unhealthy"
+)
+
+// TopicHealth aggregates a gossip topic across peers: how well the peers deliver its messages
+// to us, according to our gossipsub scores of them, and how our mesh for it held up. Operators
+// asking whether a topic is healthy need this rather than individual peers' snapshots.
+type TopicHealth struct {
+	Topic   string   `json:"topic"`
+	Health  string   `json:"health"`            // healthy, degraded or unhealthy
+	Reasons []string `json:"reasons,omitempty"` // Why the topic is not healthy
+
+	ScoredPeers         int     `json:"scored_peers"`          // Peers with a score snapshot for the topic
+	MeshScoredPeers     int     `json:"mesh_scored_peers"`     // Of those, peers scored while in our mesh
+	MeanFirstDeliveries float64 `json:"mean_first_deliveries"` // Weighted mean of each peer's mean first message deliveries counter
+	MeanMeshDeliveries  float64 `json:"mean_mesh_deliveries"`  // The same for mesh message deliveries, over snapshots in our mesh
+	InvalidDeliveries   float64 `json:"invalid_deliveries"`    // Weighted sum of each peer's highest invalid message deliveries counter
+	InvalidPeers        int     `json:"invalid_peers"`         // Peers with any invalid message deliveries, weighted and rounded
+
+	MeshSampled      bool    `json:"mesh_sampled"` // Our router's mesh for the topic was sampled, the mesh fields are unset otherwise
+	MinMesh          int     `json:"min_mesh"`     // Since the topic was first grafted
+	MeanMesh         float64 `json:"mean_mesh"`
+	FinalMesh        int     `json:"final_mesh"`
+	Mesh             []int   `json:"mesh,omitempty"` // Fewest mesh peers in each period of MeshPointSeconds, from the start of the run
+	MeshPointSeconds float64 `json:"mesh_point_seconds,omitempty"`
+}
+
+// TopicHealthSummary is the health of every topic peers were scored on or our mesh was sampled for.
+type TopicHealthSummary struct {
+	Dlo       int           `json:"dlo"` // Mesh low watermark the mean mesh size is judged against
+	Healthy   int           `json:"healthy"`
+	Degraded  int           `json:"degraded"`
+	Unhealthy int           `json:"unhealthy"`
+	Topics    []TopicHealth `json:"topics"` // Unhealthy first, then degraded, then healthy
+}
+
+// sparkBlocks draw a series from its lowest to its highest value.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// MeshSparkline draws the mesh size over the run as a line of block characters scaled from
+// zero to the largest mesh, empty when the mesh was not sampled.
+func (h TopicHealth) MeshSparkline() string {
+	highest := 0
+	for _, size := range h.Mesh {
+		highest = max(highest, size)
+	}
+
+	line := make([]rune, 0, len(h.Mesh))
+
+	for _, size := range h.Mesh {
+		block := 0
+		if highest > 0 {
+			block = size * (len(sparkBlocks) - 1) / highest
+		}
+
+		line = append(line, sparkBlocks[block])
+	}
+
+	return string(line)
+}
+
+// topicDeliveries accumulates one peer's score snapshots for one topic.
+type topicDeliveries struct {
+	weight        float64 // The peer's sampling weight
+	snapshots     int
+	meshSnapshots int
+	first         float64
+	mesh          float64
+	maxInvalid    float64
+}
+
+// AnalyzeTopicHealth aggregates the peers' topic scores and our router's mesh sizes per topic,
+// and flags each topic. A topic is unhealthy when its mesh was empty at the end, its mean mesh
+// was below half of dlo or several peers delivered invalid messages on it (minInvalidPeers),
+// and degraded when its mean mesh was below dlo, its mesh emptied at some point or any peer
+// delivered invalid messages. Mesh sizes are only judged when router metrics recorded grafts.
+// Only score snapshots of peers whose detail is captured are counted, each peer weighted by its
+// sampling weight so the means and invalid counts stand for all peers.
+func AnalyzeTopicHealth(peers map[string]*Stats, router *RouterMetrics, dlo, minInvalidPeers, maxPoints int) *TopicHealthSummary {
+	byTopic := make(map[string]map[string]*topicDeliveries)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for _, session := range stats.ConnectionSessions {
+			for _, snapshot := range session.PeerScores {
+				topics, err := snapshot.TopicScores()
+				if err != nil {
+					continue
+				}
+
+				for _, topic := range topics {
+					if byTopic[topic.Topic] == nil {
+						byTopic[topic.Topic] = make(map[string]*topicDeliveries)
+					}
+
+					deliveries := byTopic[topic.Topic][peerID]
+					if deliveries == nil {
+						deliveries = &topicDeliveries{weight: stats.DetailWeight()}
+						byTopic[topic.Topic][peerID] = deliveries
+					}
+
+					deliveries.snapshots++
+					deliveries.first += topic.FirstMessageDeliveries
+					deliveries.maxInvalid = max(deliveries.maxInvalid, topic.InvalidMessageDeliveries)
+
+					if topic.TimeInMesh > 0 {
+						deliveries.meshSnapshots++
+						deliveries.mesh += topic.MeshMessageDeliveries
+					}
+				}
+			}
+		}
+	}
+
+	topics := make(map[string]*TopicHealth, len(byTopic))
+
+	for name, byPeer := range byTopic {
+		health := &TopicHealth{Topic: name, ScoredPeers: len(byPeer)}
+
+		var scoredWeight, meshWeight, invalidWeight float64
+
+		for _, deliveries := range byPeer {
+			scoredWeight += deliveries.weight
+			health.MeanFirstDeliveries += deliveries.weight * deliveries.first / float64(deliveries.snapshots)
+
+			if deliveries.meshSnapshots > 0 {
+				health.MeshScoredPeers++
+				meshWeight += deliveries.weight
+				health.MeanMeshDeliveries += deliveries.weight * deliveries.mesh / float64(deliveries.meshSnapshots)
+			}
+
+			if deliveries.maxInvalid > 0 {
+				invalidWeight += deliveries.weight
+				health.InvalidDeliveries += deliveries.weight * deliveries.maxInvalid
+			}
+		}
+
+		if scoredWeight > 0 {
+			health.MeanFirstDeliveries /= scoredWeight
+		}
+
+		if meshWeight > 0 {
+			health.MeanMeshDeliveries /= meshWeight
+		}
+
+		health.InvalidPeers = int(math.Round(invalidWeight))
+
+		topics[name] = health
+	}
+
+	if router != nil {
+		addTopicMesh(topics, router, maxPoints)
+	}
+
+	summary := &TopicHealthSummary{Dlo: dlo, Topics: make([]TopicHealth, 0, len(topics))}
+
+	// Without any grafts traced, the router's mesh sizes say nothing
+	meshSampled := router != nil && len(router.Topics) > 0
+
+	for _, health := range topics {
+		judgeTopic(health, meshSampled, dlo, minInvalidPeers)
+
+		switch health.Health {
+		case TopicUnhealthy:
+			summary.Unhealthy++
+		case TopicDegraded:
+			summary.Degraded++
+		default:
+			summary.Healthy++
+		}
+
+		summary.Topics = append(summary.Topics, *health)
+	}
+
+	rank := map[string]int{TopicUnhealthy: 0, TopicDegraded: 1, TopicHealthy: 2}
+
+	sort.Slice(summary.Topics, func(i, j int) bool {
+		if rank[summary.Topics[i].Health] != rank[summary.Topics[j].Health] {
+			return rank[summary.Topics[i].Health] < rank[summary.Topics[j].Health]
+		}
+
+		return summary.Topics[i].Topic < summary.Topics[j].Topic
+	})
+
+	return summary
+}
+
+// addTopicMesh adds our router's mesh sizes to the topics, adding the topics only the router
+// knows. The mesh over the run is reduced to at most maxPoints points, each the fewest mesh
+// peers in its period, since a mesh running dry matters more than one briefly full.
+func addTopicMesh(topics map[string]*TopicHealth, router *RouterMetrics, maxPoints int) {
+	perPoint := 1
+	if maxPoints > 0 && len(router.Samples) > maxPoints {
+		perPoint = (len(router.Samples) + maxPoints - 1) / maxPoints
+	}
+
+	for _, summary := range router.Topics {
+		health := topics[summary.Topic]
+		if health == nil {
+			health = &TopicHealth{Topic: summary.Topic}
+			topics[summary.Topic] = health
+		}
+
+		health.MeshSampled = true
+		health.MinMesh = summary.MinMesh
+		health.MeanMesh = summary.MeanMesh
+		health.FinalMesh = summary.FinalMesh
+		health.MeshPointSeconds = float64(perPoint) * router.BucketSeconds
+		health.Mesh = make([]int, 0, (len(router.Samples)+perPoint-1)/perPoint)
+
+		for i, sample := range router.Samples {
+			size := sample.MeshSizes[summary.Topic]
+
+			if i%perPoint == 0 {
+				health.Mesh = append(health.Mesh, size)
+			} else {
+				health.Mesh[len(health.Mesh)-1] = min(health.Mesh[len(health.Mesh)-1], size)
+			}
+		}
+	}
+}
+
+// judgeTopic sets the topic's health and the reasons it is not healthy. Without router
+// metrics, meshSampled is false and only invalid deliveries are judged. A topic the router
+// never saw grafted counts as meshless only if no peer was scored in our mesh for it either.
+func judgeTopic(health *TopicHealth, meshSampled bool, dlo, minInvalidPeers int) {
+	unhealthy := make([]string, 0)
+	degraded := make([]string, 0)
+
+	if meshSampled {
+		switch {
+		case !health.MeshSampled && health.MeshScoredPeers == 0:
+			unhealthy = append(unhealthy, "never grafted into our mesh")
+		case !health.MeshSampled:
+			// Scored in our mesh, so grafts were missed rather than absent
+		case health.FinalMesh == 0:
+			unhealthy = append(unhealthy, "no mesh peers at the end")
+		case health.MeanMesh < float64(dlo)/2:
+			unhealthy = append(unhealthy, fmt.Sprintf("mean mesh %.1f below half of Dlo %d", health.MeanMesh, dlo))
+		case health.MeanMesh < float64(dlo):
+			degraded = append(degraded, fmt.Sprintf("mean mesh %.1f below Dlo %d", health.MeanMesh, dlo))
+		}
+
+		if health.MeshSampled && health.MinMesh == 0 && health.FinalMesh > 0 {
+			degraded = append(degraded, "mesh emptied during the run")
+		}
+	}
+
+	if health.InvalidPeers > 0 {
+		reason := fmt.Sprintf("invalid deliveries from %d peers", health.InvalidPeers)
+		if health.InvalidPeers == 1 {
+			reason = "invalid deliveries from 1 peer"
+		}
+
+		if health.InvalidPeers >= minInvalidPeers {
+			unhealthy = append(unhealthy, reason)
+		} else {
+			degraded = append(degraded, reason)
+		}
+	}
+
+	switch {
+	case len(unhealthy) > 0:
+		health.Health = TopicUnhealthy
+	case len(degraded) > 0:
+		health.Health = TopicDegraded
+	default:
+		health.Health = TopicHealthy
+	}
+
+	health.Reasons = append(unhealthy, degraded...)
+	if len(health.Reasons) == 0 {
+		health.Reasons = nil
+	}
+}
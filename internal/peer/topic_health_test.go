@@ -0,0 +1,90 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeTopicHealth(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	snapshot := func(topics ...TopicScore) PeerScoreSnapshot {
+		return PeerScoreSnapshot{Timestamp: start, Topics: topics}
+	}
+
+	peers := map[string]*Stats{
+		"a": {ConnectionSessions: []ConnectionSession{{PeerScores: []PeerScoreSnapshot{
+			snapshot(TopicScore{Topic: "block", TimeInMesh: time.Minute, FirstMessageDeliveries: 4, MeshMessageDeliveries: 6}),
+			snapshot(TopicScore{Topic: "block", FirstMessageDeliveries: 2},
+				TopicScore{Topic: "aggregate", TimeInMesh: time.Minute}),
+		}}}},
+		"b": {ConnectionSessions: []ConnectionSession{{PeerScores: []PeerScoreSnapshot{
+			snapshot(TopicScore{Topic: "block", FirstMessageDeliveries: 1},
+				TopicScore{Topic: "exit", InvalidMessageDeliveries: 2}),
+		}}}},
+		"c": {ConnectionSessions: []ConnectionSession{{PeerScores: []PeerScoreSnapshot{
+			snapshot(TopicScore{Topic: "exit", InvalidMessageDeliveries: 1}),
+		}}}},
+	}
+
+	sample := func(minute int, sizes map[string]int) RouterSample {
+		return RouterSample{BucketStart: start.Add(time.Duration(minute) * time.Minute), MeshSizes: sizes}
+	}
+
+	router := &RouterMetrics{
+		BucketSeconds: 60,
+		Topics: []RouterTopic{
+			{Topic: "block", MinMesh: 6, MeanMesh: 7, FinalMesh: 8},
+			{Topic: "exit", MinMesh: 4, MeanMesh: 5, FinalMesh: 5},
+			{Topic: "sync", MinMesh: 0, MeanMesh: 3, FinalMesh: 0},
+		},
+		Samples: []RouterSample{
+			sample(0, map[string]int{"block": 6, "sync": 3}),
+			sample(1, map[string]int{"block": 8, "exit": 4, "sync": 6}),
+			sample(2, map[string]int{"block": 7, "exit": 6, "sync": 0}),
+		},
+	}
+
+	summary := AnalyzeTopicHealth(peers, router, 6, 2, 2)
+
+	// The aggregate topic's grafts were missed, its peer was scored in our mesh
+	if summary.Healthy != 2 || summary.Degraded != 0 || summary.Unhealthy != 2 {
+		t.Fatalf("Expected 2 healthy and 2 unhealthy topics, got %+v", summary)
+	}
+
+	order := []string{"exit", "sync", "aggregate", "block"}
+	for i, topic := range order {
+		if summary.Topics[i].Topic != topic {
+			t.Fatalf("Expected topics %v, got %+v", order, summary.Topics)
+		}
+	}
+
+	block := summary.Topics[3]
+	if block.ScoredPeers != 2 || block.MeshScoredPeers != 1 || block.MeanFirstDeliveries != 2 || block.MeanMeshDeliveries != 6 {
+		t.Errorf("Unexpected block deliveries %+v", block)
+	}
+
+	// Three buckets in at most two points, the fewest mesh peers of each
+	if len(block.Mesh) != 2 || block.Mesh[0] != 6 || block.Mesh[1] != 7 || block.MeshPointSeconds != 120 {
+		t.Errorf("Expected mesh points [6 7] 120s apart, got %v %vs", block.Mesh, block.MeshPointSeconds)
+	}
+
+	if sparkline := block.MeshSparkline(); sparkline != "▇█" {
+		t.Errorf("Expected the mesh drawn as ▇█, got %q", sparkline)
+	}
+
+	exit := summary.Topics[0]
+	if exit.InvalidPeers != 2 || exit.InvalidDeliveries != 3 || len(exit.Reasons) != 2 {
+		t.Errorf("Expected exit unhealthy from invalid deliveries and degraded by its mesh, got %+v", exit)
+	}
+
+	if sync := summary.Topics[1]; sync.ScoredPeers != 0 || sync.Reasons[0] != "no mesh peers at the end" {
+		t.Errorf("Expected sync flagged for its empty mesh, got %+v", sync)
+	}
+
+	// Without router metrics only the deliveries are judged
+	unsampled := AnalyzeTopicHealth(peers, nil, 6, 3, 24)
+	if unsampled.Degraded != 1 || unsampled.Healthy != 2 || unsampled.Topics[0].MeshSampled {
+		t.Errorf("Expected exit degraded and the rest healthy without router metrics, got %+v", unsampled)
+	}
+}
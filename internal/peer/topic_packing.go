@@ -0,0 +1,156 @@
+package peer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Topic score slices are the bulk of a peer's memory on subnet-heavy configurations, dozens
+// of entries per snapshot every few seconds. The repository packs them into a compact binary
+// encoding compressed with zstd, and they are unpacked on demand while reports are generated.
+var (
+	topicEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+	topicDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+)
+
+// TopicScores returns the snapshot's topic scores, unpacking them if the repository packed them.
+// Analyses leave out snapshots whose packed topic scores cannot be unpacked, counted by
+// CountCorruptTopicScores, while writing the snapshot as JSON fails on them.
+func (s *PeerScoreSnapshot) TopicScores() ([]TopicScore, error) {
+	if s.packedTopics == nil {
+		return s.Topics, nil
+	}
+
+	topics, err := unpackTopics(s.packedTopics)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt packed topic scores: %w", err)
+	}
+
+	return topics, nil
+}
+
+// CountCorruptTopicScores adds the score snapshots whose packed topic scores cannot be unpacked
+// to the data quality statistics. The topic analyses leave these snapshots out.
+func CountCorruptTopicScores(peers map[string]*Stats, stats *DataQualityStats) {
+	for _, peerStats := range peers {
+		if peerStats == nil {
+			continue
+		}
+
+		for _, session := range peerStats.ConnectionSessions {
+			for i := range session.PeerScores {
+				if _, err := session.PeerScores[i].TopicScores(); err != nil {
+					stats.CorruptTopicScores++
+				}
+			}
+		}
+	}
+}
+
+// MarshalJSON writes the snapshot with its topic scores unpacked.
+func (s PeerScoreSnapshot) MarshalJSON() ([]byte, error) {
+	type snapshot PeerScoreSnapshot
+
+	topics, err := s.TopicScores()
+	if err != nil {
+		return nil, err
+	}
+
+	unpacked := snapshot(s)
+	unpacked.Topics = topics
+
+	if unpacked.Topics == nil {
+		unpacked.Topics = []TopicScore{}
+	}
+
+	return json.Marshal(unpacked)
+}
+
+// packTopics replaces the snapshot's topic scores with their packed form. Snapshots without
+// topic scores or already packed are left as they are.
+func (s *PeerScoreSnapshot) packTopics() {
+	if s.packedTopics != nil || len(s.Topics) == 0 {
+		return
+	}
+
+	s.packedTopics = packTopics(s.Topics)
+	s.Topics = nil
+}
+
+// packTopics encodes topic scores as length-prefixed names followed by their fixed-width
+// fields, compressed with zstd.
+func packTopics(topics []TopicScore) []byte {
+	size := binary.MaxVarintLen64
+	for _, topic := range topics {
+		size += binary.MaxVarintLen64 + len(topic.Topic) + 4*8
+	}
+
+	encoded := make([]byte, 0, size)
+	encoded = binary.AppendUvarint(encoded, uint64(len(topics)))
+
+	for _, topic := range topics {
+		encoded = binary.AppendUvarint(encoded, uint64(len(topic.Topic)))
+		encoded = append(encoded, topic.Topic...)
+		encoded = binary.LittleEndian.AppendUint64(encoded, uint64(topic.TimeInMesh)) //nolint:gosec // round trips negative durations.
+		encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(topic.FirstMessageDeliveries))
+		encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(topic.MeshMessageDeliveries))
+		encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(topic.InvalidMessageDeliveries))
+	}
+
+	return topicEncoder.EncodeAll(encoded, nil)
+}
+
+// unpackTopics decodes topic scores packed by packTopics.
+func unpackTopics(packed []byte) ([]TopicScore, error) {
+	encoded, err := topicDecoder.DecodeAll(packed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress topic scores: %w", err)
+	}
+
+	count, n := binary.Uvarint(encoded)
+	if n <= 0 || count > uint64(len(encoded)) {
+		return nil, fmt.Errorf("invalid topic score count")
+	}
+
+	encoded = encoded[n:]
+	topics := make([]TopicScore, 0, count)
+
+	for range count {
+		length, n := binary.Uvarint(encoded)
+		if n <= 0 || uint64(len(encoded)-n) < length+4*8 {
+			return nil, fmt.Errorf("truncated topic score %d", len(topics))
+		}
+
+		encoded = encoded[n:]
+		fields := encoded[length:]
+
+		topics = append(topics, TopicScore{
+			Topic:                    string(encoded[:length]),
+			TimeInMesh:               time.Duration(binary.LittleEndian.Uint64(fields)), //nolint:gosec // round trips negative durations.
+			FirstMessageDeliveries:   math.Float64frombits(binary.LittleEndian.Uint64(fields[8:])),
+			MeshMessageDeliveries:    math.Float64frombits(binary.LittleEndian.Uint64(fields[16:])),
+			InvalidMessageDeliveries: math.Float64frombits(binary.LittleEndian.Uint64(fields[24:])),
+		})
+
+		encoded = fields[4*8:]
+	}
+
+	return topics, nil
+}
+
+// packScores packs the topic scores of the snapshots added to each session since the last
+// call. Snapshots are only ever appended.
+func packScores(stats *Stats) {
+	for i := range stats.ConnectionSessions {
+		session := &stats.ConnectionSessions[i]
+
+		for ; session.packedScores < len(session.PeerScores); session.packedScores++ {
+			session.PeerScores[session.packedScores].packTopics()
+		}
+	}
+}
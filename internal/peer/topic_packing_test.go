@@ -0,0 +1,199 @@
+package peer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// subnetTopics returns topic scores shaped like a peer subscribed to every attestation and
+// sync committee subnet.
+func subnetTopics(offset int) []TopicScore {
+	topics := make([]TopicScore, 0, 70)
+
+	for i := range 64 {
+		topics = append(topics, TopicScore{
+			Topic:                  fmt.Sprintf("/eth2/4a26c58b/beacon_attestation_%d/ssz_snappy", i),
+			TimeInMesh:             time.Duration(offset+i) * time.Second,
+			FirstMessageDeliveries: float64(i % 7),
+			MeshMessageDeliveries:  float64(offset) / 3,
+		})
+	}
+
+	for i := range 4 {
+		topics = append(topics, TopicScore{
+			Topic:                    fmt.Sprintf("/eth2/4a26c58b/sync_committee_%d/ssz_snappy", i),
+			InvalidMessageDeliveries: -1.5,
+		})
+	}
+
+	return topics
+}
+
+func TestPackTopics(t *testing.T) {
+	tests := []struct {
+		name   string
+		topics []TopicScore
+	}{
+		{name: "single topic", topics: []TopicScore{{Topic: "/eth2/4a26c58b/beacon_block/ssz_snappy", TimeInMesh: -time.Second, MeshMessageDeliveries: 0.25}}},
+		{name: "empty topic name", topics: []TopicScore{{FirstMessageDeliveries: 3}}},
+		{name: "subnets", topics: subnetTopics(5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unpackTopics(packTopics(tt.topics))
+			if err != nil {
+				t.Fatalf("Expected no error unpacking, got %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.topics) {
+				t.Errorf("Round trip: got %+v, want %+v", got, tt.topics)
+			}
+		})
+	}
+
+	if _, err := unpackTopics([]byte("not zstd")); err == nil {
+		t.Error("Expected an error unpacking garbage")
+	}
+}
+
+func TestCorruptPackedTopics(t *testing.T) {
+	truncated := packTopics(subnetTopics(3))
+
+	tests := []struct {
+		name   string
+		packed []byte
+	}{
+		{name: "not zstd", packed: []byte("not zstd")},
+		{name: "truncated", packed: truncated[:len(truncated)/2]},
+		{name: "bad count", packed: topicEncoder.EncodeAll([]byte{0xff}, nil)},
+		{name: "short topic", packed: topicEncoder.EncodeAll([]byte{1, 40, 'a'}, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := PeerScoreSnapshot{Score: 1, packedTopics: tt.packed}
+
+			if topics, err := snapshot.TopicScores(); err == nil {
+				t.Errorf("Expected an error unpacking corrupt topics, got %d topics", len(topics))
+			}
+
+			if _, err := json.Marshal(snapshot); err == nil {
+				t.Error("Expected an error marshalling corrupt topics")
+			}
+
+			peers := map[string]*Stats{
+				"peer-1": {ConnectionSessions: []ConnectionSession{{PeerScores: []PeerScoreSnapshot{snapshot}}}},
+			}
+
+			if invalid := DetectInvalidDeliveries(peers, 1); len(invalid.Anomalies) != 0 {
+				t.Errorf("Expected the corrupt snapshot left out, got %+v", invalid.Anomalies)
+			}
+
+			var quality DataQualityStats
+			CountCorruptTopicScores(peers, &quality)
+
+			if quality.CorruptTopicScores != 1 {
+				t.Errorf("Expected the corrupt snapshot counted in data quality, got %d", quality.CorruptTopicScores)
+			}
+		})
+	}
+}
+
+func TestPackedTopicsSize(t *testing.T) {
+	topics := subnetTopics(12)
+
+	var unpacked int
+	for _, topic := range topics {
+		unpacked += int(reflect.TypeOf(topic).Size()) + len(topic.Topic)
+	}
+
+	if packed := len(packTopics(topics)); packed*4 > unpacked {
+		t.Errorf("Expected packing to shrink %d bytes at least fourfold, got %d", unpacked, packed)
+	}
+}
+
+func TestRepositoryPacksTopics(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	repo := NewInMemoryRepository(logger)
+	repo.CreatePeer("peer-1")
+
+	topics := subnetTopics(1)
+	now := time.Now()
+
+	for i := range 3 {
+		repo.UpdatePeer("peer-1", func(p *Stats) {
+			if len(p.ConnectionSessions) == 0 {
+				p.ConnectionSessions = append(p.ConnectionSessions, ConnectionSession{ConnectedAt: &now})
+			}
+
+			session := &p.ConnectionSessions[0]
+			session.PeerScores = append(session.PeerScores, PeerScoreSnapshot{
+				Timestamp: now.Add(time.Duration(i) * time.Second),
+				Score:     float64(i),
+				Topics:    append([]TopicScore(nil), topics...),
+			})
+		})
+	}
+
+	// An empty snapshot stays as it is
+	repo.UpdatePeer("peer-1", func(p *Stats) {
+		p.ConnectionSessions[0].PeerScores = append(p.ConnectionSessions[0].PeerScores, PeerScoreSnapshot{Timestamp: now})
+	})
+
+	peers := repo.GetAllPeers()
+	scores := peers["peer-1"].ConnectionSessions[0].PeerScores
+
+	if len(scores) != 4 {
+		t.Fatalf("Expected 4 snapshots, got %d", len(scores))
+	}
+
+	for i, score := range scores[:3] {
+		if score.Topics != nil || score.packedTopics == nil {
+			t.Errorf("Expected snapshot %d to be packed", i)
+		}
+
+		if got, err := score.TopicScores(); err != nil || !reflect.DeepEqual(got, topics) {
+			t.Errorf("Snapshot %d: got %d topics back, want %d", i, len(got), len(topics))
+		}
+	}
+
+	if got, _ := scores[3].TopicScores(); scores[3].packedTopics != nil || len(got) != 0 {
+		t.Errorf("Expected the empty snapshot to stay unpacked, got %+v", scores[3])
+	}
+
+	encoded, err := json.Marshal(scores[0])
+	if err != nil {
+		t.Fatalf("Expected no error marshalling, got %v", err)
+	}
+
+	var decoded PeerScoreSnapshot
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Expected no error unmarshalling, got %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Topics, topics) || decoded.Score != scores[0].Score {
+		t.Errorf("Expected JSON to carry the unpacked topics, got %s", encoded)
+	}
+
+	empty, err := json.Marshal(scores[3])
+	if err != nil {
+		t.Fatalf("Expected no error marshalling, got %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(empty, &fields); err != nil {
+		t.Fatalf("Expected no error unmarshalling, got %v", err)
+	}
+
+	if topics, ok := fields["topics"].([]interface{}); !ok || len(topics) != 0 {
+		t.Errorf("Expected an empty topics array, got %s", empty)
+	}
+}
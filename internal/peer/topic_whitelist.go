@@ -0,0 +1,32 @@
+package peer
+
+import "strings"
+
+// TopicWhitelist records the gossip topics collection was restricted to and the events
+// discarded for topics outside it.
+type TopicWhitelist struct {
+	Topics             []string       `json:"topics"`
+	DroppedEvents      int            `json:"dropped_events"`
+	DroppedByType      map[string]int `json:"dropped_by_type,omitempty"`
+	DroppedTopicScores int            `json:"dropped_topic_scores"` // Per-topic entries removed from peer scores
+}
+
+// TopicWhitelisted reports whether a gossip topic is in the whitelist. Entries match an eth2
+// topic's name with or without its subnet suffix, so beacon_attestation matches every
+// attestation subnet and beacon_attestation_5 only that one. Other topics must match in full.
+func TopicWhitelisted(whitelist []string, topic string) bool {
+	_, name, ok := ParseGossipTopic(topic)
+
+	subnetName := name
+	if ok {
+		subnetName = strings.Split(topic, "/")[3]
+	}
+
+	for _, entry := range whitelist {
+		if entry == name || entry == subnetName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,26 @@
+package peer
+
+import "testing"
+
+func TestTopicWhitelisted(t *testing.T) {
+	whitelist := []string{"beacon_block", "beacon_attestation_5", "sync_committee"}
+
+	tests := []struct {
+		topic string
+		want  bool
+	}{
+		{topic: "/eth2/4a26c58b/beacon_block/ssz_snappy", want: true},
+		{topic: "/eth2/4a26c58b/beacon_aggregate_and_proof/ssz_snappy", want: false},
+		{topic: "/eth2/4a26c58b/beacon_attestation_5/ssz_snappy", want: true},
+		{topic: "/eth2/4a26c58b/beacon_attestation_6/ssz_snappy", want: false},
+		{topic: "/eth2/4a26c58b/sync_committee_2/ssz_snappy", want: true},
+		{topic: "beacon_block", want: true},
+		{topic: "/meshsub/1.1.0", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := TopicWhitelisted(whitelist, tt.topic); got != tt.want {
+			t.Errorf("TopicWhitelisted(%q) = %v, want %v", tt.topic, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package peer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// notReported labels sessions whose muxer or security protocol Hermes did not report.
+const notReported = "not reported"
+
+// transportRanks orders the multiaddr protocols that identify a transport. Layered
+// transports rank above the ones they run over, so WebSocket over TCP is websocket and
+// WebTransport over QUIC is webtransport.
+var transportRanks = map[string]struct {
+	transport string
+	rank      int
+}{
+	"tcp":           {TransportTCP, 1},
+	"quic":          {TransportQUIC, 2},
+	"quic-v1":       {TransportQUIC, 2},
+	"ws":            {TransportWebSocket, 3},
+	"wss":           {TransportWebSocket, 3},
+	"webtransport":  {TransportWebTransport, 3},
+	"webrtc":        {TransportWebRTC, 3},
+	"webrtc-direct": {TransportWebRTC, 3},
+}
+
+// ClassifyTransport returns the transport of a libp2p multiaddr such as
+// /ip4/1.2.3.4/udp/9000/quic-v1, or TransportUnknown when none is recognised.
+func ClassifyTransport(multiaddr string) string {
+	transport, rank := TransportUnknown, 0
+
+	for _, protocol := range strings.Split(multiaddr, "/") {
+		if known, ok := transportRanks[protocol]; ok && known.rank > rank {
+			transport, rank = known.transport, known.rank
+		}
+	}
+
+	return transport
+}
+
+// ImpliedProtocols returns the muxer and security protocol built into a transport. TCP and
+// WebSocket negotiate theirs, so nothing is implied for them.
+func ImpliedProtocols(transport string) (muxer, security string) {
+	switch transport {
+	case TransportQUIC, TransportWebTransport:
+		return "quic", "tls"
+	case TransportWebRTC:
+		return "sctp", "dtls"
+	default:
+		return "", ""
+	}
+}
+
+// TransportBreakdown summarises session stability per transport, most used first.
+// Sessions recorded before transports were tracked count as TransportUnknown.
+func TransportBreakdown(peers map[string]*Stats) []TransportStats {
+	byTransport := make(map[string]*TransportStats)
+	durations := make(map[string][]float64)
+
+	for _, peer := range peers {
+		seen := make(map[string]bool)
+
+		for _, session := range peer.ConnectionSessions {
+			transport := session.Transport
+			if transport == "" {
+				transport = TransportUnknown
+			}
+
+			stats, ok := byTransport[transport]
+			if !ok {
+				stats = &TransportStats{
+					Transport: transport,
+					Muxers:    make(map[string]int),
+					Security:  make(map[string]int),
+				}
+				byTransport[transport] = stats
+			}
+
+			if !seen[transport] {
+				seen[transport] = true
+				stats.Peers++
+			}
+
+			stats.Sessions++
+			stats.Muxers[orNotReported(session.Muxer)]++
+			stats.Security[orNotReported(session.Security)]++
+
+			if len(churnGoodbyes(&session)) > 0 {
+				stats.WithGoodbye++
+			}
+
+			if !churned(&session) {
+				continue
+			}
+
+			stats.Disconnected++
+
+			if duration, ok := completedDuration(session); ok {
+				durations[transport] = append(durations[transport], duration.Seconds())
+
+				if duration < constants.ShortSessionDuration {
+					stats.ShortLived++
+				}
+			}
+		}
+	}
+
+	breakdown := make([]TransportStats, 0, len(byTransport))
+
+	for transport, stats := range byTransport {
+		stats.MedianDurationSeconds = median(durations[transport])
+		breakdown = append(breakdown, *stats)
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Sessions != breakdown[j].Sessions {
+			return breakdown[i].Sessions > breakdown[j].Sessions
+		}
+
+		return breakdown[i].Transport < breakdown[j].Transport
+	})
+
+	return breakdown
+}
+
+// TransportBreakdownFromInterface summarises session stability per transport on generic
+// peer data, as loaded from a JSON report in HTML-only mode.
+func TransportBreakdownFromInterface(peers map[string]interface{}) []TransportStats {
+	return TransportBreakdown(statsFromInterface(peers))
+}
+
+// sessionDuration returns how long a disconnected session lasted.
+func sessionDuration(session ConnectionSession) (time.Duration, bool) {
+	if session.Duration != nil {
+		return *session.Duration, true
+	}
+
+	if session.ConnectedAt != nil && session.DisconnectedAt != nil {
+		return session.DisconnectedAt.Sub(*session.ConnectedAt), true
+	}
+
+	return 0, false
+}
+
+// completedDuration returns how long a session the run observed ending lasted. Sessions the
+// run ended are censored, their length is only a lower bound and would bias durations down.
+func completedDuration(session ConnectionSession) (time.Duration, bool) {
+	if session.censored() {
+		return 0, false
+	}
+
+	return sessionDuration(session)
+}
+
+// orNotReported labels an empty protocol as not reported.
+func orNotReported(protocol string) string {
+	if protocol == "" {
+		return notReported
+	}
+
+	return protocol
+}
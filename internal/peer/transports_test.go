@@ -0,0 +1,89 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyTransport(t *testing.T) {
+	tests := []struct {
+		multiaddr string
+		want      string
+	}{
+		{multiaddr: "/ip4/1.2.3.4/tcp/9000", want: TransportTCP},
+		{multiaddr: "/ip6/::1/udp/9001/quic-v1", want: TransportQUIC},
+		{multiaddr: "/ip4/1.2.3.4/udp/9001/quic", want: TransportQUIC},
+		{multiaddr: "/dns4/node.example/tcp/443/wss", want: TransportWebSocket},
+		{multiaddr: "/ip4/1.2.3.4/udp/9001/quic-v1/webtransport", want: TransportWebTransport},
+		{multiaddr: "/ip4/1.2.3.4/udp/9001/webrtc-direct", want: TransportWebRTC},
+		{multiaddr: "", want: TransportUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.multiaddr, func(t *testing.T) {
+			if got := ClassifyTransport(tt.multiaddr); got != tt.want {
+				t.Errorf("ClassifyTransport(%q) = %q, want %q", tt.multiaddr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransportBreakdown(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	peers := map[string]*Stats{
+		"tcp-flaky": {
+			ConnectionSessions: []ConnectionSession{
+				{Transport: TransportTCP, ConnectedAt: at(0), DisconnectedAt: at(10), Disconnected: true, GoodbyeEvents: []GoodbyeEvent{{Code: 129}}},
+				{Transport: TransportTCP, ConnectedAt: at(20), DisconnectedAt: at(320), Disconnected: true},
+				{Transport: TransportTCP, ConnectedAt: at(400)},
+			},
+		},
+		"mixed": {
+			ConnectionSessions: []ConnectionSession{
+				{Transport: TransportTCP, ConnectedAt: at(0), DisconnectedAt: at(20), Disconnected: true},
+				{Transport: TransportQUIC, Muxer: "quic", Security: "tls", ConnectedAt: at(30)},
+			},
+		},
+		"legacy": {
+			ConnectionSessions: []ConnectionSession{
+				{ConnectedAt: at(0)},
+			},
+		},
+	}
+
+	breakdown := TransportBreakdown(peers)
+
+	if len(breakdown) != 3 || breakdown[0].Transport != TransportTCP {
+		t.Fatalf("expected tcp, quic and unknown with tcp first, got %+v", breakdown)
+	}
+
+	tcp := breakdown[0]
+	if tcp.Peers != 2 || tcp.Sessions != 4 || tcp.Disconnected != 3 || tcp.ShortLived != 2 || tcp.WithGoodbye != 1 {
+		t.Errorf("unexpected tcp counts: %+v", tcp)
+	}
+
+	if tcp.MedianDurationSeconds != 20 {
+		t.Errorf("expected median tcp duration of 20s, got %f", tcp.MedianDurationSeconds)
+	}
+
+	if tcp.Muxers[notReported] != 4 {
+		t.Errorf("expected unreported tcp muxers, got %v", tcp.Muxers)
+	}
+
+	for _, stats := range breakdown[1:] {
+		if stats.Sessions != 1 || stats.Disconnected != 0 {
+			t.Errorf("unexpected %s counts: %+v", stats.Transport, stats)
+		}
+	}
+
+	if breakdown[1].Transport != TransportQUIC || breakdown[1].Security["tls"] != 1 {
+		t.Errorf("expected quic sessions over tls, got %+v", breakdown[1])
+	}
+}
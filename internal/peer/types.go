@@ -7,6 +7,7 @@ type Stats struct {
 	PeerID               string              `json:"peer_id"`
 	ClientType           string              `json:"client_type"`
 	ClientAgent          string              `json:"client_agent"`
+	Origin               string              `json:"origin,omitempty"` // One of the Origin constants, tagged when the report is generated
 	ConnectionSessions   []ConnectionSession `json:"connection_sessions"`
 	TotalConnections     int                 `json:"total_connections"`
 	TotalMessageCount    int                 `json:"total_message_count"`
@@ -14,29 +15,82 @@ type Stats struct {
 	FailedHandshakes     int                 `json:"failed_handshakes"`
 	FirstSeenAt          *time.Time          `json:"first_seen_at"`
 	LastSeenAt           *time.Time          `json:"last_seen_at"`
+	DecodeErrors         *DecodeErrorStats   `json:"decode_errors,omitempty"`
+	ReqRespAbuse         *ReqRespAbuseStats  `json:"reqresp_abuse,omitempty"`
+	ControlPlane         *ControlPlaneStats  `json:"control_plane,omitempty"`         // Nil until a gossipsub RPC was exchanged
+	Sample               *DetailSample       `json:"sample,omitempty"`                // Nil when every peer's detail is captured
+	DroppedLateEvents    map[string]int      `json:"dropped_late_events,omitempty"`   // Events by type that arrived too long after a disconnect
+	DuplicateConnections map[string]int      `json:"duplicate_connections,omitempty"` // CONNECTED events for a connection already recorded, by how they matched
+	Consensus            *ConsensusScore     `json:"consensus,omitempty"`             // Other vantage points' scores, set when the report is generated
 }
 
+// Connection directions recorded on sessions.
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+// Connection transports recorded on sessions, classified from the remote multiaddr.
+const (
+	TransportTCP          = "tcp"
+	TransportQUIC         = "quic"
+	TransportWebSocket    = "websocket"
+	TransportWebTransport = "webtransport"
+	TransportWebRTC       = "webrtc"
+	TransportUnknown      = "unknown"
+)
+
 // ConnectionSession represents a single connection timeline for a peer.
 type ConnectionSession struct {
-	ConnectedAt    *time.Time          `json:"connected_at"`
-	IdentifiedAt   *time.Time          `json:"identified_at"`
-	DisconnectedAt *time.Time          `json:"disconnected_at"`
-	MessageCount   int                 `json:"message_count"`
-	Duration       *time.Duration      `json:"duration"`
-	Disconnected   bool                `json:"disconnected"`
-	PeerScores     []PeerScoreSnapshot `json:"peer_scores"`
-	GoodbyeEvents  []GoodbyeEvent      `json:"goodbye_events"`
-	MeshEvents     []MeshEvent         `json:"mesh_events"`
+	ConnectedAt          *time.Time           `json:"connected_at"`
+	Direction            string               `json:"direction,omitempty"` // inbound or outbound, as libp2p saw the connection
+	Transport            string               `json:"transport,omitempty"` // One of the Transport constants
+	Muxer                string               `json:"muxer,omitempty"`     // Stream multiplexer, empty when Hermes did not report it
+	Security             string               `json:"security,omitempty"`  // Security protocol, empty when Hermes did not report it
+	IdentifiedAt         *time.Time           `json:"identified_at"`
+	DisconnectedAt       *time.Time           `json:"disconnected_at"`
+	ConnectedSlot        uint64               `json:"connected_slot"`
+	ConnectedEpoch       uint64               `json:"connected_epoch"`
+	DisconnectedSlot     uint64               `json:"disconnected_slot,omitempty"`
+	DisconnectedEpoch    uint64               `json:"disconnected_epoch,omitempty"`
+	MessageCount         int                  `json:"message_count"`
+	Duration             *time.Duration       `json:"duration"`
+	Disconnected         bool                 `json:"disconnected"`
+	EndedByGap           bool                 `json:"ended_by_gap,omitempty"`            // Closed at a checkpoint because the collector was down
+	EndedByLocalLimit    bool                 `json:"ended_by_local_limit,omitempty"`    // Closed without a goodbye while we were at MaxPeers
+	EndedByResourceLimit bool                 `json:"ended_by_resource_limit,omitempty"` // Closed without a goodbye while our resource manager was refusing resources
+	EndedInShutdown      bool                 `json:"ended_in_shutdown,omitempty"`       // Closed while Hermes shut down after the run
+	EndedByRampRestart   bool                 `json:"ended_by_ramp_restart,omitempty"`   // Closed when Hermes restarted into the next MaxPeers ramp step
+	Censored             bool                 `json:"censored,omitempty"`                // The run ended the session, so its length is a lower bound. Added when the report is generated
+	RampStep             int                  `json:"ramp_step,omitempty"`               // MaxPeers ramp step the session connected in, from 1
+	ConnectionKey        string               `json:"connection_key,omitempty"`          // Identifies the underlying connection, to drop duplicate CONNECTED events
+	LateEvents           int                  `json:"late_events,omitempty"`             // Events assigned after the disconnect, within the grace window
+	PeerScores           []PeerScoreSnapshot  `json:"peer_scores"`
+	ScoreSummary         *SessionScoreSummary `json:"score_summary,omitempty"` // Added when the report is generated
+	GoodbyeEvents        []GoodbyeEvent       `json:"goodbye_events"`
+	LocalTerminations    []LocalTermination   `json:"local_terminations,omitempty"` // Request streams Hermes reset, nil while it reset none
+	MeshEvents           []MeshEvent          `json:"mesh_events"`
+	StatusUpdates        []StatusUpdate       `json:"status_updates,omitempty"`
+	EventSamples         EventSampleCounts    `json:"event_samples,omitempty"` // Events seen and recorded by sampled type, nil when none is sampled
+
+	packedScores int            // Leading PeerScores whose topic scores the repository packed
+	spilled      *spilledEvents // Events moved to the spill file, loaded back by the Spiller
 }
 
 // PeerScoreSnapshot represents a snapshot of a peer's score at a specific time.
 type PeerScoreSnapshot struct {
 	Timestamp          time.Time    `json:"timestamp"`
+	Slot               uint64       `json:"slot"`
+	Epoch              uint64       `json:"epoch"`
 	Score              float64      `json:"score"`
 	AppSpecificScore   float64      `json:"app_specific_score"`
 	IPColocationFactor float64      `json:"ip_colocation_factor"`
 	BehaviourPenalty   float64      `json:"behaviour_penalty"`
-	Topics             []TopicScore `json:"topics"`
+	Topics             []TopicScore `json:"topics"`                    // Nil once packed, read them with TopicScores
+	PostDisconnect     bool         `json:"post_disconnect,omitempty"` // Arrived after the session's disconnect
+	BeaconDegraded     bool         `json:"beacon_degraded,omitempty"` // Taken while delegated validation's beacon node was degraded
+
+	packedTopics []byte // Topics packed by the repository, see TopicScores
 }
 
 // TopicScore represents the peer score for a specific topic.
@@ -50,9 +104,34 @@ type TopicScore struct {
 
 // GoodbyeEvent represents a goodbye message received from a peer.
 type GoodbyeEvent struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Slot           uint64    `json:"slot"`
+	Epoch          uint64    `json:"epoch"`
+	Code           uint64    `json:"code"`
+	Reason         string    `json:"reason"`
+	PostDisconnect bool      `json:"post_disconnect,omitempty"` // Arrived after the session's disconnect
+	InShutdown     bool      `json:"in_shutdown,omitempty"`     // Sent while Hermes shut down after the run
+}
+
+// LocalTermination is a request stream of the peer's that Hermes reset after failing to handle
+// the request, the termination counterpart of a goodbye the peer sends us.
+type LocalTermination struct {
 	Timestamp time.Time `json:"timestamp"`
-	Code      uint64    `json:"code"`
-	Reason    string    `json:"reason"`
+	Kind      string    `json:"kind"`     // One of the Termination constants
+	Protocol  string    `json:"protocol"` // Event type of the request, e.g. HANDLE_STATUS
+	Reason    string    `json:"reason"`   // The error Hermes traced
+}
+
+// StatusUpdate is a beacon status the peer sent us or answered our status request with.
+type StatusUpdate struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Inbound        bool      `json:"inbound,omitempty"` // Sent by the peer rather than answering our request
+	HeadSlot       uint64    `json:"head_slot"`
+	FinalizedEpoch uint64    `json:"finalized_epoch"`
+	Attempt        int       `json:"attempt,omitempty"`         // Our request's number in the session, from 1
+	LatencyMs      float64   `json:"latency_ms,omitempty"`      // From connecting to the answer of our request
+	LocalHeadSlot  uint64    `json:"local_head_slot,omitempty"` // Ours, as we answered the peer's status or as estimated for the report
+	Error          string    `json:"error,omitempty"`           // Our request failed, the status fields are unset
 }
 
 // GoodbyeReasonStats tracks statistics for a specific goodbye reason.
@@ -72,13 +151,82 @@ type GoodbyeEventsSummary struct {
 	CodeFrequency map[uint64]int        `json:"code_frequency"` // Code occurrence count
 }
 
+// Session fates used in the unknown client diagnosis.
+const (
+	FateIdentifiedConnected    = "identified_still_connected"
+	FateIdentifiedDisconnected = "identified_then_disconnected"
+	FateIdentifyTimeout        = "disconnected_before_identify"
+	FateUnidentifiedConnected  = "unidentified_still_connected"
+)
+
+// UnknownClientDiagnosis describes the peers whose client type could not be determined,
+// so the client normalizer can be taught new patterns from real data.
+type UnknownClientDiagnosis struct {
+	Peers          int                `json:"peers"`
+	Sessions       int                `json:"sessions"`
+	DistinctAgents int                `json:"distinct_agents"`
+	AgentStrings   []AgentStringCount `json:"agent_strings"`   // Sorted by peer count, most common first
+	Identify       IdentifyStats      `json:"identify"`        // Identify timing of unknown peer sessions
+	SessionFates   map[string]int     `json:"session_fates"`   // Session count per fate
+	GoodbyeReasons map[string]int     `json:"goodbye_reasons"` // Goodbye count per reason
+}
+
+// TransportStats summarises how stable the sessions over one transport were.
+type TransportStats struct {
+	Transport             string         `json:"transport"`
+	Peers                 int            `json:"peers"`
+	Sessions              int            `json:"sessions"`
+	Disconnected          int            `json:"disconnected"`
+	ShortLived            int            `json:"short_lived"`             // Disconnected within the short session threshold
+	WithGoodbye           int            `json:"with_goodbye"`            // Sessions the peer sent a goodbye in
+	MedianDurationSeconds float64        `json:"median_duration_seconds"` // Of disconnected sessions
+	Muxers                map[string]int `json:"muxers"`                  // Session count per stream multiplexer
+	Security              map[string]int `json:"security"`                // Session count per security protocol
+}
+
+// AgentStringCount counts the peers advertising a raw agent string.
+type AgentStringCount struct {
+	Agent string `json:"agent"`
+	Peers int    `json:"peers"`
+}
+
+// IdentifyStats summarises how long sessions took to identify, or lived without identifying.
+type IdentifyStats struct {
+	Identified             int     `json:"identified"`
+	NeverIdentified        int     `json:"never_identified"`
+	MedianIdentifySeconds  float64 `json:"median_identify_seconds"`
+	MaxIdentifySeconds     float64 `json:"max_identify_seconds"`
+	MedianUnidentifiedLife float64 `json:"median_unidentified_life_seconds"` // Lifetime of sessions that disconnected before identifying
+}
+
 // MeshEvent represents a GRAFT/PRUNE event for mesh participation tracking.
 type MeshEvent struct {
-	Timestamp time.Time `json:"timestamp"`
-	Type      string    `json:"type"`
-	Direction string    `json:"direction"`
-	Topic     string    `json:"topic"`
-	Reason    string    `json:"reason"`
+	Timestamp      time.Time `json:"timestamp"`
+	Slot           uint64    `json:"slot"`
+	Epoch          uint64    `json:"epoch"`
+	Type           string    `json:"type"`
+	Direction      string    `json:"direction"`
+	Topic          string    `json:"topic"`
+	Reason         string    `json:"reason"`
+	PostDisconnect bool      `json:"post_disconnect,omitempty"` // Arrived after the session's disconnect
+}
+
+// DecodeErrorStats counts gossip messages from a peer that were rejected as invalid or badly signed.
+type DecodeErrorStats struct {
+	Total      int            `json:"total"`
+	ByKind     map[string]int `json:"by_kind"`
+	ByTopic    map[string]int `json:"by_topic"`
+	LastReason string         `json:"last_reason"`
+	LastSeenAt *time.Time     `json:"last_seen_at"`
+}
+
+// ReqRespAbuseStats counts req/resp requests from a peer that broke the rate limits or were malformed.
+type ReqRespAbuseStats struct {
+	Total      int            `json:"total"`
+	ByKind     map[string]int `json:"by_kind"`
+	ByProtocol map[string]int `json:"by_protocol"` // Keyed by the Hermes HANDLE_ event type
+	LastReason string         `json:"last_reason"`
+	LastSeenAt *time.Time     `json:"last_seen_at"`
 }
 
 // ConnectionStats holds aggregate connection statistics.
@@ -89,9 +237,80 @@ type ConnectionStats struct {
 	ConnectedPeers       int `json:"connected_peers"`
 }
 
+// ReconciledHandshakeStats holds handshake outcomes per connection episode. A session that
+// fails to identify and is followed by a reconnect within the retry window belongs to the
+// same episode, and the episode takes the outcome of its final session.
+type ReconciledHandshakeStats struct {
+	RetryWindowSeconds float64 `json:"retry_window_seconds"`
+	Episodes           int     `json:"episodes"`
+	SuccessfulEpisodes int     `json:"successful_episodes"`
+	FailedEpisodes     int     `json:"failed_episodes"`
+	RecoveredEpisodes  int     `json:"recovered_episodes"` // Failed at first, identified on a retry
+	SuccessRate        float64 `json:"success_rate"`       // Percentage of successful episodes
+}
+
+// DataQualityStats describes how faithfully trace events were processed. Events for a peer
+// should be processed in trace timestamp order; an event stamped earlier than one already
+// processed for the same peer skews session durations and is flagged as out of order.
+type DataQualityStats struct {
+	EventsChecked     int            `json:"events_checked"`
+	MissingTimestamps int            `json:"missing_timestamps"` // Events stamped with processing time instead
+	OutOfOrderEvents  int            `json:"out_of_order_events"`
+	OutOfOrderByType  map[string]int `json:"out_of_order_by_type,omitempty"`
+	MaxLagSeconds     float64        `json:"max_lag_seconds"` // Largest gap behind the latest processed event
+
+	// Trace events no handler parses, so new Hermes event types get noticed
+	UnhandledEvents int                  `json:"unhandled_events"`
+	UnhandledTypes  []UnhandledEventType `json:"unhandled_types,omitempty"`  // Sorted by count, most common first
+	UntrackedEvents int                  `json:"untracked_events,omitempty"` // Unhandled events of types beyond the tracking limit
+
+	// Events that arrived after their peer disconnected
+	LateEventGraceSeconds   float64        `json:"late_event_grace_seconds"`
+	LateEventsAssigned      int            `json:"late_events_assigned"` // Assigned to the session that just ended, flagged as post-disconnect
+	LateEventsDropped       int            `json:"late_events_dropped"`  // Arrived beyond the grace window
+	LateEventsDroppedByType map[string]int `json:"late_events_dropped_by_type,omitempty"`
+
+	// Custom handlers attached to the tool, in registration order
+	EventHooks []EventHookStats `json:"event_hooks,omitempty"`
+
+	// CONNECTED events for a connection already recorded, not counted as connections
+	DuplicateConnections        int            `json:"duplicate_connections"`
+	DuplicateConnectionsByMatch map[string]int `json:"duplicate_connections_by_match,omitempty"`
+
+	// Payloads whose peer ID was found by reflection, their types lack a typed adapter
+	PeerIDReflectionFallbacks       int            `json:"peer_id_reflection_fallbacks,omitempty"`
+	PeerIDReflectionFallbacksByType map[string]int `json:"peer_id_reflection_fallbacks_by_type,omitempty"`
+
+	// Score snapshots whose topic scores could not be unpacked, left out of the topic analyses
+	CorruptTopicScores int `json:"corrupt_topic_scores,omitempty"`
+}
+
+// EventHookStats counts the calls of a custom event handler and how they went. A hook that
+// fails or panics is isolated, the event is still processed by the built-in handlers.
+type EventHookStats struct {
+	Name      string  `json:"name"`
+	EventType string  `json:"event_type"` // "*" for every event
+	Calls     int     `json:"calls"`
+	Errors    int     `json:"errors"`
+	Panics    int     `json:"panics"`
+	MeanMs    float64 `json:"mean_ms"`
+	MaxMs     float64 `json:"max_ms"`
+	LastError string  `json:"last_error,omitempty"`
+}
+
+// UnhandledEventType counts the trace events of one type no handler parses, keeping the
+// first few payloads as samples.
+type UnhandledEventType struct {
+	Type        string    `json:"type"`
+	Count       int       `json:"count"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	Samples     []string  `json:"samples,omitempty"` // JSON encoded payloads, truncated
+}
+
 // DurationStats holds aggregate duration statistics.
 type DurationStats struct {
-	AverageDuration time.Duration `json:"average_duration"`
-	MaxDuration     time.Duration `json:"max_duration"`
-	MinDuration     time.Duration `json:"min_duration"`
+	AverageDuration  time.Duration `json:"average_duration"`
+	MaxDuration      time.Duration `json:"max_duration"`
+	MinDuration      time.Duration `json:"min_duration"`
+	CensoredSessions int           `json:"censored_sessions"` // Sessions the run ended, left out of the durations
 }
@@ -0,0 +1,163 @@
+package peer
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// noAgent labels unknown peers that never advertised an agent string.
+const noAgent = "(no agent string)"
+
+// DiagnoseUnknownClients analyses the peers whose client type could not be determined:
+// their raw agent strings, how their sessions identified and how those sessions ended.
+func DiagnoseUnknownClients(peers map[string]*Stats, agentLimit int) UnknownClientDiagnosis {
+	diagnosis := UnknownClientDiagnosis{
+		AgentStrings:   make([]AgentStringCount, 0),
+		SessionFates:   make(map[string]int),
+		GoodbyeReasons: make(map[string]int),
+	}
+
+	agents := make(map[string]int)
+	identifyDurations := make([]float64, 0)
+	unidentifiedLifetimes := make([]float64, 0)
+
+	for _, peer := range peers {
+		if peer.ClientType != "" && peer.ClientType != constants.Unknown {
+			continue
+		}
+
+		diagnosis.Peers++
+
+		agent := strings.TrimSpace(peer.ClientAgent)
+		if agent == "" {
+			agent = noAgent
+		}
+
+		agents[agent]++
+
+		for _, session := range peer.ConnectionSessions {
+			diagnosis.Sessions++
+
+			for _, goodbye := range session.GoodbyeEvents {
+				reason := goodbye.Reason
+				if reason == "" {
+					reason = "no reason provided"
+				}
+
+				diagnosis.GoodbyeReasons[reason]++
+			}
+
+			if session.IdentifiedAt != nil {
+				diagnosis.Identify.Identified++
+
+				if session.ConnectedAt != nil {
+					identifyDurations = append(identifyDurations, session.IdentifiedAt.Sub(*session.ConnectedAt).Seconds())
+				}
+
+				if session.Disconnected {
+					diagnosis.SessionFates[FateIdentifiedDisconnected]++
+				} else {
+					diagnosis.SessionFates[FateIdentifiedConnected]++
+				}
+
+				continue
+			}
+
+			diagnosis.Identify.NeverIdentified++
+
+			if !session.Disconnected {
+				diagnosis.SessionFates[FateUnidentifiedConnected]++
+
+				continue
+			}
+
+			diagnosis.SessionFates[FateIdentifyTimeout]++
+
+			if session.ConnectedAt != nil && session.DisconnectedAt != nil {
+				unidentifiedLifetimes = append(unidentifiedLifetimes, session.DisconnectedAt.Sub(*session.ConnectedAt).Seconds())
+			}
+		}
+	}
+
+	diagnosis.DistinctAgents = len(agents)
+
+	for agent, count := range agents {
+		diagnosis.AgentStrings = append(diagnosis.AgentStrings, AgentStringCount{Agent: agent, Peers: count})
+	}
+
+	sort.Slice(diagnosis.AgentStrings, func(i, j int) bool {
+		if diagnosis.AgentStrings[i].Peers != diagnosis.AgentStrings[j].Peers {
+			return diagnosis.AgentStrings[i].Peers > diagnosis.AgentStrings[j].Peers
+		}
+
+		return diagnosis.AgentStrings[i].Agent < diagnosis.AgentStrings[j].Agent
+	})
+
+	if agentLimit > 0 && len(diagnosis.AgentStrings) > agentLimit {
+		diagnosis.AgentStrings = diagnosis.AgentStrings[:agentLimit]
+	}
+
+	diagnosis.Identify.MedianIdentifySeconds = median(identifyDurations)
+	diagnosis.Identify.MedianUnidentifiedLife = median(unidentifiedLifetimes)
+
+	for _, seconds := range identifyDurations {
+		if seconds > diagnosis.Identify.MaxIdentifySeconds {
+			diagnosis.Identify.MaxIdentifySeconds = seconds
+		}
+	}
+
+	return diagnosis
+}
+
+// DiagnoseUnknownClientsFromInterface runs the unknown client diagnosis on generic peer data,
+// as loaded from a JSON report in HTML-only mode.
+func DiagnoseUnknownClientsFromInterface(peers map[string]interface{}, agentLimit int) UnknownClientDiagnosis {
+	return DiagnoseUnknownClients(statsFromInterface(peers), agentLimit)
+}
+
+// statsFromInterface converts generic peer data back to peer statistics, skipping peers
+// that do not decode.
+func statsFromInterface(peers map[string]interface{}) map[string]*Stats {
+	stats := make(map[string]*Stats, len(peers))
+
+	for peerID, peerData := range peers {
+		switch peer := peerData.(type) {
+		case *Stats:
+			stats[peerID] = peer
+		case map[string]interface{}:
+			// Round-trip through JSON, the map mirrors the Stats JSON layout
+			data, err := json.Marshal(peer)
+			if err != nil {
+				continue
+			}
+
+			var decoded Stats
+			if err := json.Unmarshal(data, &decoded); err == nil {
+				stats[peerID] = &decoded
+			}
+		}
+	}
+
+	return stats
+}
+
+// median returns the median of the values, or zero when there are none.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
@@ -0,0 +1,86 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiagnoseUnknownClients(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+
+		return &ts
+	}
+
+	peers := map[string]*Stats{
+		"known": {
+			ClientType:  "lighthouse",
+			ClientAgent: "Lighthouse/v7.0.0",
+			ConnectionSessions: []ConnectionSession{
+				{ConnectedAt: at(0), IdentifiedAt: at(1)},
+			},
+		},
+		"custom-a": {
+			ClientType:  "unknown",
+			ClientAgent: "erigon/caplin",
+			ConnectionSessions: []ConnectionSession{
+				{ConnectedAt: at(0), IdentifiedAt: at(2), DisconnectedAt: at(30), Disconnected: true},
+				{ConnectedAt: at(40), IdentifiedAt: at(44)},
+			},
+		},
+		"custom-b": {
+			ClientType:  "unknown",
+			ClientAgent: "erigon/caplin",
+			ConnectionSessions: []ConnectionSession{
+				{
+					ConnectedAt:    at(0),
+					DisconnectedAt: at(10),
+					Disconnected:   true,
+					GoodbyeEvents:  []GoodbyeEvent{{Code: 3, Reason: "client shutdown"}},
+				},
+			},
+		},
+		"silent": {
+			ConnectionSessions: []ConnectionSession{
+				{ConnectedAt: at(0)},
+			},
+		},
+	}
+
+	diagnosis := DiagnoseUnknownClients(peers, 10)
+
+	if diagnosis.Peers != 3 || diagnosis.Sessions != 4 {
+		t.Fatalf("expected 3 peers with 4 sessions, got %d peers with %d sessions", diagnosis.Peers, diagnosis.Sessions)
+	}
+
+	if diagnosis.DistinctAgents != 2 || diagnosis.AgentStrings[0].Agent != "erigon/caplin" || diagnosis.AgentStrings[0].Peers != 2 {
+		t.Errorf("unexpected agent strings: %+v", diagnosis.AgentStrings)
+	}
+
+	expectedFates := map[string]int{
+		FateIdentifiedDisconnected: 1,
+		FateIdentifiedConnected:    1,
+		FateIdentifyTimeout:        1,
+		FateUnidentifiedConnected:  1,
+	}
+
+	for fate, count := range expectedFates {
+		if diagnosis.SessionFates[fate] != count {
+			t.Errorf("expected %d sessions with fate %s, got %d", count, fate, diagnosis.SessionFates[fate])
+		}
+	}
+
+	if diagnosis.Identify.MedianIdentifySeconds != 3 || diagnosis.Identify.MaxIdentifySeconds != 4 {
+		t.Errorf("unexpected identify timing: %+v", diagnosis.Identify)
+	}
+
+	if diagnosis.Identify.MedianUnidentifiedLife != 10 {
+		t.Errorf("expected unidentified life of 10s, got %f", diagnosis.Identify.MedianUnidentifiedLife)
+	}
+
+	if diagnosis.GoodbyeReasons["client shutdown"] != 1 {
+		t.Errorf("unexpected goodbye reasons: %v", diagnosis.GoodbyeReasons)
+	}
+}
@@ -0,0 +1,244 @@
+// Package peerexport extracts one peer's history from a saved report into a standalone
+// mini-report, a JSON and HTML pair small enough to attach to an issue about that peer's
+// operator or client.
+package peerexport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports/templates"
+)
+
+// peerTemplate is the embedded template the mini-report is rendered with.
+const peerTemplate = "peer"
+
+// maxAmbiguous caps the matching peer IDs listed when a query matches several.
+const maxAmbiguous = 5
+
+// savedReport is the part of a JSON report the export needs.
+type savedReport struct {
+	Config struct {
+		Network string `json:"network"`
+	} `json:"config"`
+	ValidationMode   string `json:"validation_mode"`
+	ValidationConfig struct {
+		HermesVersion string `json:"HermesVersion"`
+	} `json:"validation_config"`
+	AgentVersion    string                     `json:"agent_version"`
+	StartTime       time.Time                  `json:"start_time"`
+	EndTime         time.Time                  `json:"end_time"`
+	Peers           map[string]json.RawMessage `json:"peers"`
+	PeerEventCounts map[string]map[string]int  `json:"peer_event_counts"`
+}
+
+// Load extracts a peer from a saved report: a JSON report, or the shard directory of a split
+// report. The peer is given by its ID or any part of it that only one peer's ID contains, such
+// as the short ID the HTML report shows.
+func Load(path, query string, now time.Time) (*Export, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	export := &Export{Source: filepath.Base(filepath.Clean(path)), ExportedAt: now}
+
+	var (
+		peers  map[string]json.RawMessage
+		counts map[string]map[string]int
+	)
+
+	if info.IsDir() {
+		if peers, err = reports.ReadDetailShards(path); err != nil {
+			return nil, err
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report: %w", err)
+		}
+
+		var report savedReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("failed to parse report: %w", err)
+		}
+
+		peers, counts = report.Peers, report.PeerEventCounts
+		export.Run = &Run{
+			ValidationMode: report.ValidationMode,
+			Network:        report.Config.Network,
+			HermesVersion:  report.ValidationConfig.HermesVersion,
+			AgentVersion:   report.AgentVersion,
+			StartTime:      report.StartTime,
+			EndTime:        report.EndTime,
+		}
+	}
+
+	if len(peers) == 0 {
+		return nil, errors.New("report holds no peers")
+	}
+
+	peerID, err := findPeer(peers, query)
+	if err != nil {
+		return nil, err
+	}
+
+	export.PeerID = peerID
+	export.Peer = peers[peerID]
+	export.EventCounts = counts[peerID]
+
+	return export, nil
+}
+
+// findPeer returns the ID of the one peer the query names.
+func findPeer(peers map[string]json.RawMessage, query string) (string, error) {
+	if query == "" {
+		return "", errors.New("no peer ID given")
+	}
+
+	if _, ok := peers[query]; ok {
+		return query, nil
+	}
+
+	matches := make([]string, 0)
+
+	for peerID := range peers {
+		if strings.Contains(peerID, query) {
+			matches = append(matches, peerID)
+		}
+	}
+
+	sort.Strings(matches)
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) == 0:
+		return "", fmt.Errorf("no peer in the report matches %s", query)
+	default:
+		listed := matches[:min(len(matches), maxAmbiguous)]
+
+		return "", fmt.Errorf("%s matches %d peers, give more of the ID: %s", query, len(matches), strings.Join(listed, ", "))
+	}
+}
+
+// Write saves the export as base.json and base.html, the HTML rendered with the embedded peer
+// template or its override in templateDir. It returns the paths written.
+func Write(export *Export, base, templateDir string, logger logrus.FieldLogger) ([]string, error) {
+	view, err := newView(export)
+	if err != nil {
+		return nil, err
+	}
+
+	tm := templates.NewManager(logger)
+	if err := tm.LoadTemplates(); err != nil {
+		return nil, err
+	}
+
+	if templateDir != "" {
+		if err := tm.LoadOverrides(templateDir); err != nil {
+			return nil, err
+		}
+	}
+
+	html, err := tm.RenderTemplate(peerTemplate, view)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode peer export: %w", err)
+	}
+
+	jsonFile, htmlFile := base+".json", base+".html"
+
+	if err := os.WriteFile(jsonFile, append(data, '\n'), constants.DefaultFilePermissions); err != nil {
+		return nil, fmt.Errorf("failed to write peer export: %w", err)
+	}
+
+	if err := os.WriteFile(htmlFile, []byte(html), constants.DefaultFilePermissions); err != nil {
+		return nil, fmt.Errorf("failed to write peer export: %w", err)
+	}
+
+	return []string{jsonFile, htmlFile}, nil
+}
+
+// DefaultBase names the export files after the peer, next to where the command runs.
+func DefaultBase(peerID string) string {
+	return "peer-" + peerID
+}
+
+// newView decodes the peer for the template, with what each session adds up to.
+func newView(export *Export) (*view, error) {
+	var stats peer.Stats
+	if err := json.Unmarshal(export.Peer, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse peer %s: %w", export.PeerID, err)
+	}
+
+	v := &view{Export: export, Stats: &stats, Sessions: make([]sessionView, 0, len(stats.ConnectionSessions))}
+
+	for i := range stats.ConnectionSessions {
+		session := &stats.ConnectionSessions[i]
+		sv := sessionView{Number: i + 1, ConnectionSession: session}
+
+		if session.Duration != nil {
+			sv.Seconds = session.Duration.Seconds()
+		}
+
+		for j := range session.PeerScores {
+			if sv.Lowest == nil || session.PeerScores[j].Score < sv.Lowest.Score {
+				sv.Lowest = &session.PeerScores[j]
+			}
+		}
+
+		v.Sessions = append(v.Sessions, sv)
+	}
+
+	for eventType, count := range export.EventCounts {
+		v.Events = append(v.Events, eventCount{Type: eventType, Count: count})
+	}
+
+	sort.Slice(v.Events, func(i, j int) bool {
+		if v.Events[i].Count != v.Events[j].Count {
+			return v.Events[i].Count > v.Events[j].Count
+		}
+
+		return v.Events[i].Type < v.Events[j].Type
+	})
+
+	return v, nil
+}
+
+// view is the data the peer template renders.
+type view struct {
+	*Export
+	Stats    *peer.Stats
+	Sessions []sessionView
+	Events   []eventCount // Most frequent first
+}
+
+// sessionView is a session with its number and what its snapshots add up to.
+type sessionView struct {
+	*peer.ConnectionSession
+	Number  int
+	Seconds float64                 // Zero while the session was open at the end
+	Lowest  *peer.PeerScoreSnapshot // Nil without score snapshots
+}
+
+// eventCount is the number of trace events of one type.
+type eventCount struct {
+	Type  string
+	Count int
+}
@@ -0,0 +1,105 @@
+package peerexport
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testReport = `{
+  "config": {"network": "hoodi"},
+  "validation_mode": "delegated",
+  "validation_config": {"HermesVersion": "v0.0.4"},
+  "start_time": "2025-06-01T12:00:00Z",
+  "end_time": "2025-06-01T12:15:00Z",
+  "peers": {
+    "16Uiu2HAmAAAA1111": {
+      "peer_id": "16Uiu2HAmAAAA1111",
+      "client_type": "teku",
+      "client_agent": "teku/v25.6.0",
+      "composite_score": -12.5,
+      "connection_sessions": [{
+        "connected_at": "2025-06-01T12:01:00Z",
+        "disconnected_at": "2025-06-01T12:03:00Z",
+        "duration": 120000000000,
+        "disconnected": true,
+        "direction": "inbound",
+        "peer_scores": [
+          {"timestamp": "2025-06-01T12:02:00Z", "score": 1.5, "topics": null},
+          {"timestamp": "2025-06-01T12:02:30Z", "score": -42.25, "topics": null}
+        ],
+        "goodbye_events": [{"timestamp": "2025-06-01T12:03:00Z", "code": 129, "reason": "client has too many peers"}],
+        "mesh_events": null
+      }]
+    },
+    "16Uiu2HAmBBBB2222": {"peer_id": "16Uiu2HAmBBBB2222", "client_type": "lighthouse", "connection_sessions": []}
+  },
+  "peer_event_counts": {"16Uiu2HAmAAAA1111": {"PEERSCORE": 2, "HANDLE_GOODBYE": 1}}
+}`
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	reportFile := filepath.Join(dir, "peer-score-report.json")
+
+	if err := os.WriteFile(reportFile, []byte(testReport), 0o600); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	if _, err := Load(reportFile, "16Uiu2HAm", time.Now()); err == nil || !strings.Contains(err.Error(), "matches 2 peers") {
+		t.Errorf("Expected an ambiguous peer to fail, got %v", err)
+	}
+
+	if _, err := Load(reportFile, "CCCC", time.Now()); err == nil {
+		t.Error("Expected an unknown peer to fail")
+	}
+
+	export, err := Load(reportFile, "AAAA", time.Now())
+	if err != nil {
+		t.Fatalf("Expected the peer to load, got %v", err)
+	}
+
+	if export.PeerID != "16Uiu2HAmAAAA1111" || export.Run == nil || export.Run.Network != "hoodi" || export.EventCounts["PEERSCORE"] != 2 {
+		t.Fatalf("Unexpected export %+v", export)
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	files, err := Write(export, filepath.Join(dir, DefaultBase(export.PeerID)), "", logger)
+	if err != nil {
+		t.Fatalf("Expected the export to be written, got %v", err)
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read JSON export: %v", err)
+	}
+
+	var written struct {
+		Peer map[string]interface{} `json:"peer"`
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("failed to parse JSON export: %v", err)
+	}
+
+	// Fields derived when the report was generated stay in the record
+	if written.Peer["composite_score"] != -12.5 {
+		t.Errorf("Expected the peer's record as the report wrote it, got %v", written.Peer)
+	}
+
+	html, err := os.ReadFile(files[1])
+	if err != nil {
+		t.Fatalf("failed to read HTML export: %v", err)
+	}
+
+	for _, want := range []string{"16Uiu2HAmAAAA1111", "teku/v25.6.0", "Network: hoodi", "client has too many peers", "Lowest score -42.25", "HANDLE_GOODBYE"} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("Expected the HTML export to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package peerexport
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Run describes the run a peer was exported from, as far as its report records it.
+type Run struct {
+	ValidationMode string    `json:"validation_mode"`
+	Network        string    `json:"network,omitempty"`
+	HermesVersion  string    `json:"hermes_version,omitempty"`
+	AgentVersion   string    `json:"agent_version,omitempty"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+}
+
+// Export is one peer's full history taken out of a saved report. The peer's record is kept as
+// the report wrote it, fields derived when the report was generated included.
+type Export struct {
+	PeerID      string          `json:"peer_id"`
+	Source      string          `json:"source"` // Base name of the report or shard directory
+	ExportedAt  time.Time       `json:"exported_at"`
+	Run         *Run            `json:"run,omitempty"`          // Nil when exported from a shard directory, which holds peers only
+	EventCounts map[string]int  `json:"event_counts,omitempty"` // Trace events of the peer by type, from the report's event counts
+	Peer        json.RawMessage `json:"peer"`
+}
@@ -0,0 +1,336 @@
+// Package peertrace records everything the run sees of one peer in a file of its own: every
+// trace event with its full payload, every log line about the peer whatever the log level,
+// and every change of its score. Investigating a single misbehaving operator then needs no
+// debug-level capture of the whole run.
+package peertrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+	"github.com/ethpandaops/hermes-peer-score/internal/events/parsers"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+)
+
+// Kinds of trace records.
+const (
+	KindEvent = "event"
+	KindLog   = "log"
+	KindScore = "score"
+)
+
+// fieldPeerID is the log field log lines name their peer in, by its short form.
+const fieldPeerID = "peer_id"
+
+// Record is one line of a peer trace.
+type Record struct {
+	Time      time.Time         `json:"time"`
+	Kind      string            `json:"kind"`                 // One of the Kind constants
+	EventType string            `json:"event_type,omitempty"` // Of an event or a score change
+	Payload   any               `json:"payload,omitempty"`    // An event's payload as Hermes traced it
+	Level     string            `json:"level,omitempty"`      // Of a log line
+	Message   string            `json:"message,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"` // A log line's other fields
+	Score     *ScoreChange      `json:"score,omitempty"`
+}
+
+// ScoreChange is a peer score differing from the one traced before it.
+type ScoreChange struct {
+	Score              float64  `json:"score"`
+	Previous           *float64 `json:"previous,omitempty"` // Nil for the first score traced
+	Delta              float64  `json:"delta"`
+	AppSpecificScore   float64  `json:"app_specific_score"`
+	IPColocationFactor float64  `json:"ip_colocation_factor"`
+	BehaviourPenalty   float64  `json:"behaviour_penalty"`
+	Topics             int      `json:"topics"`
+}
+
+// Trace writes the records of one peer as JSON lines. It is a logrus hook for every level, so
+// the log lines naming the peer are kept even when the run logs at a lower verbosity. It is
+// safe for concurrent use.
+type Trace struct {
+	mu      sync.Mutex
+	peerID  string
+	short   string
+	out     io.Writer
+	closer  io.Closer
+	path    string
+	records int
+	score   *float64
+	parser  parsers.DefaultParser
+	policy  *prune.Policy // Fields dropped or hashed in every record, nil writes them as they are
+	err     error         // First write error, after which records are dropped
+}
+
+// New creates a trace of peerID writing to out.
+func New(out io.Writer, peerID string) *Trace {
+	return &Trace{peerID: peerID, short: common.FormatShortPeerID(peerID), out: out}
+}
+
+// Open creates a trace of peerID writing to a file, appending to it when resuming a run and
+// truncating it otherwise.
+func Open(path, peerID string, resume bool) (*Trace, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if resume {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open peer trace: %w", err)
+	}
+
+	trace := New(file, peerID)
+	trace.closer = file
+	trace.path = path
+
+	return trace, nil
+}
+
+// SetPrunePolicy sets the fields dropped or hashed in every record written from now on, as in
+// the reports. Paths match the record's JSON: an event's payload sits under "payload" with the
+// keys Hermes traced it with, such as AgentVersion or RemoteMaddrs, and log fields under "fields".
+func (t *Trace) SetPrunePolicy(policy *prune.Policy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.policy = policy
+}
+
+// PeerID returns the traced peer.
+func (t *Trace) PeerID() string {
+	return t.peerID
+}
+
+// Path returns the file the trace writes to, empty when it writes elsewhere.
+func (t *Trace) Path() string {
+	return t.path
+}
+
+// Records returns how many records were written so far.
+func (t *Trace) Records() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.records
+}
+
+// Event records a trace event of the traced peer, and the change of its score when the event
+// is a score. The caller matches the event's peer, which it has extracted already.
+func (t *Trace) Event(event *host.TraceEvent) {
+	at := common.GetEventTime(event)
+
+	t.write(Record{Time: at.UTC(), Kind: KindEvent, EventType: event.Type, Payload: event.Payload})
+
+	payload, ok := event.Payload.(map[string]any)
+	if !ok || event.Type != "PEERSCORE" {
+		return
+	}
+
+	score, err := t.parser.ParsePeerScoreFromMap(payload, at)
+	if err != nil {
+		return
+	}
+
+	t.scoreChanged(event.Type, score)
+}
+
+// scoreChanged records a score that differs from the previous one.
+func (t *Trace) scoreChanged(eventType string, score *parsers.PeerScoreData) {
+	t.mu.Lock()
+	previous := t.score
+
+	if previous != nil && *previous == score.Score {
+		t.mu.Unlock()
+
+		return
+	}
+
+	current := score.Score
+	t.score = &current
+	t.mu.Unlock()
+
+	change := &ScoreChange{
+		Score:              score.Score,
+		Previous:           previous,
+		AppSpecificScore:   score.AppSpecificScore,
+		IPColocationFactor: score.IPColocationFactor,
+		BehaviourPenalty:   score.BehaviourPenalty,
+		Topics:             len(score.Topics),
+	}
+
+	if previous != nil {
+		change.Delta = score.Score - *previous
+	}
+
+	t.write(Record{Time: score.Timestamp.UTC(), Kind: KindScore, EventType: eventType, Score: change})
+}
+
+// Levels returns every level, the trace keeps the peer's log lines whatever their level.
+func (t *Trace) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire records a log line naming the traced peer, by its full or short ID. A trace that cannot
+// be written must not break logging, so write errors are kept for Close instead of returned.
+func (t *Trace) Fire(entry *logrus.Entry) error {
+	value, ok := entry.Data[fieldPeerID]
+	if !ok {
+		return nil
+	}
+
+	if id := fmt.Sprint(value); id != t.peerID && id != t.short {
+		return nil
+	}
+
+	record := Record{Time: entry.Time.UTC(), Kind: KindLog, Level: entry.Level.String(), Message: entry.Message}
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := entry.Data[key]
+
+		switch key {
+		case fieldPeerID:
+		case logrus.ErrorKey:
+			if err, ok := value.(error); ok {
+				record.Error = err.Error()
+			} else {
+				record.Error = fmt.Sprint(value)
+			}
+		default:
+			if record.Fields == nil {
+				record.Fields = make(map[string]string)
+			}
+
+			record.Fields[key] = fmt.Sprint(value)
+		}
+	}
+
+	t.write(record)
+
+	return nil
+}
+
+// write writes a record as one JSON line. A payload that cannot be encoded is written in its
+// printed form instead.
+func (t *Trace) write(record Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.out == nil || t.err != nil {
+		return
+	}
+
+	line, err := t.marshal(record)
+	if err != nil && record.Payload != nil {
+		record.Payload = fmt.Sprintf("%+v", record.Payload)
+		line, err = t.marshal(record)
+	}
+
+	if err != nil {
+		t.err = fmt.Errorf("failed to marshal peer trace record: %w", err)
+
+		return
+	}
+
+	if _, err := t.out.Write(append(line, '\n')); err != nil {
+		t.err = fmt.Errorf("failed to write peer trace record: %w", err)
+
+		return
+	}
+
+	t.records++
+}
+
+// marshal encodes a record as JSON, pruned when a policy is set.
+func (t *Trace) marshal(record Record) ([]byte, error) {
+	line, err := json.Marshal(record)
+	if err != nil || t.policy == nil {
+		return line, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(line, &decoded); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(t.policy.Value(decoded))
+}
+
+// Close closes the trace's file, if it opened one, and returns the first write error. Records
+// written after Close are dropped.
+func (t *Trace) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.out = nil
+
+	if t.closer != nil {
+		if err := t.closer.Close(); err != nil && t.err == nil {
+			t.err = fmt.Errorf("failed to close peer trace: %w", err)
+		}
+
+		t.closer = nil
+	}
+
+	return t.err
+}
+
+// Verbose raises the logger to trace level so the trace sees every log line, while the lines
+// below the logger's current level are still left out of its output. The rest of the run
+// keeps the verbosity it was given.
+func Verbose(logger *logrus.Logger) {
+	level := logger.GetLevel()
+	if level >= logrus.TraceLevel {
+		return
+	}
+
+	logger.SetFormatter(&levelFormatter{Formatter: logger.Formatter, level: level})
+	logger.SetOutput(skipEmpty{logger.Out})
+	logger.SetLevel(logrus.TraceLevel)
+}
+
+// skipEmpty leaves out the empty writes of dropped log lines.
+type skipEmpty struct {
+	io.Writer
+}
+
+// Write writes p unless it is empty.
+func (w skipEmpty) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	return w.Writer.Write(p)
+}
+
+// levelFormatter formats the log lines at or above a level and drops the rest.
+type levelFormatter struct {
+	logrus.Formatter
+	level logrus.Level
+}
+
+// Format formats the entry, or nothing when it is below the level.
+func (f *levelFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level > f.level {
+		return nil, nil
+	}
+
+	return f.Formatter.Format(entry)
+}
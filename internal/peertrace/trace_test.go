@@ -0,0 +1,170 @@
+package peertrace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/host"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+)
+
+const tracedPeer = "16Uiu2HAm7Ux3Sq5zW1ofz5fjsuYgYkvy7U5vhSLbETzm9JQ8L5J6"
+
+func scoreEvent(at time.Time, score float64) *host.TraceEvent {
+	return &host.TraceEvent{
+		Type:      "PEERSCORE",
+		Timestamp: at,
+		Payload: map[string]any{
+			"PeerID":           tracedPeer,
+			"Score":            score,
+			"BehaviourPenalty": 0.0,
+			"Topics":           []any{},
+		},
+	}
+}
+
+func readRecords(t *testing.T, out *bytes.Buffer) []Record {
+	t.Helper()
+
+	records := make([]Record, 0)
+
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Expected a JSON object per line, got %q: %v", scanner.Text(), err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestTraceEvents(t *testing.T) {
+	var out bytes.Buffer
+
+	trace := New(&out, tracedPeer)
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	trace.Event(scoreEvent(at, 2))
+	trace.Event(scoreEvent(at.Add(10*time.Second), 2))
+	trace.Event(scoreEvent(at.Add(20*time.Second), -1.5))
+	trace.Event(&host.TraceEvent{Type: "HANDLE_STATUS", Timestamp: at.Add(30 * time.Second), Payload: map[string]any{"PeerID": tracedPeer}})
+
+	// Three scores and a status, and two score changes, the repeated score is no change
+	records := readRecords(t, &out)
+	if len(records) != 6 || trace.Records() != 6 {
+		t.Fatalf("Expected 6 records, got %d (%d counted)", len(records), trace.Records())
+	}
+
+	first, second := records[1], records[4]
+	if first.Kind != KindScore || first.Score.Score != 2 || first.Score.Previous != nil {
+		t.Errorf("Expected the first score as a change, got %+v", first)
+	}
+
+	if second.Kind != KindScore || second.Score.Previous == nil || *second.Score.Previous != 2 || second.Score.Delta != -3.5 {
+		t.Errorf("Expected the drop to -1.5 as a change, got %+v", second.Score)
+	}
+
+	if status := records[5]; status.Kind != KindEvent || status.EventType != "HANDLE_STATUS" || !status.Time.Equal(at.Add(30*time.Second)) {
+		t.Errorf("Expected the status event in full, got %+v", status)
+	}
+}
+
+func TestTraceLogs(t *testing.T) {
+	var trace, console bytes.Buffer
+
+	logger := logrus.New()
+	logger.SetOutput(&console)
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	logger.SetLevel(logrus.InfoLevel)
+
+	tracer := New(&trace, tracedPeer)
+
+	Verbose(logger)
+	logger.AddHook(tracer)
+
+	logger.WithField("peer_id", tracedPeer[:12]).Debug("Added peer score snapshot")
+	logger.WithField("peer_id", tracedPeer).WithError(errors.New("stream reset")).Warn("Request failed")
+	logger.WithField("peer_id", "16Uiu2HAmOther").Debug("Not traced")
+	logger.Info("Run started")
+
+	records := readRecords(t, &trace)
+	if len(records) != 2 {
+		t.Fatalf("Expected the traced peer's 2 log lines, got %+v", records)
+	}
+
+	if records[0].Level != "debug" || records[1].Error != "stream reset" || records[1].Fields != nil {
+		t.Errorf("Unexpected log records %+v", records)
+	}
+
+	// The console keeps the level it was given
+	if got := console.String(); got != "level=warning msg=\"Request failed\" error=\"stream reset\" peer_id="+tracedPeer+"\nlevel=info msg=\"Run started\"\n" {
+		t.Errorf("Expected debug lines left out of the console, got %q", got)
+	}
+
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("Expected the trace to close, got %v", err)
+	}
+
+	logger.SetOutput(io.Discard)
+	logger.WithField("peer_id", tracedPeer).Info("After close")
+
+	if tracer.Records() != 2 {
+		t.Errorf("Expected records after close dropped, got %d", tracer.Records())
+	}
+}
+
+func TestTracePrunePolicy(t *testing.T) {
+	var out bytes.Buffer
+
+	policy, err := prune.New([]string{"payload.AgentVersion", "fields.remote_addr"}, []string{"RemoteMaddrs"}, "")
+	if err != nil {
+		t.Fatalf("Expected a valid policy, got %v", err)
+	}
+
+	trace := New(&out, tracedPeer)
+	trace.SetPrunePolicy(policy)
+
+	trace.Event(&host.TraceEvent{Type: "CONNECTED", Timestamp: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), Payload: map[string]any{
+		"RemotePeer":   tracedPeer,
+		"RemoteMaddrs": "/ip4/203.0.113.10/tcp/9000",
+		"AgentVersion": "Lighthouse/v7.0.1-e42406d/x86_64-linux",
+	}})
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(trace)
+	logger.WithFields(logrus.Fields{"peer_id": tracedPeer, "remote_addr": "203.0.113.10", "topic": "beacon_block"}).Info("Connected")
+
+	line := out.String()
+	for _, leaked := range []string{"Lighthouse", "203.0.113.10"} {
+		if strings.Contains(line, leaked) {
+			t.Errorf("Expected %q pruned from the trace, got %s", leaked, line)
+		}
+	}
+
+	records := readRecords(t, &out)
+	if len(records) != 2 {
+		t.Fatalf("Expected an event and a log record, got %+v", records)
+	}
+
+	payload, ok := records[0].Payload.(map[string]any)
+	if !ok || payload["RemotePeer"] != tracedPeer || !strings.HasPrefix(fmt.Sprint(payload["RemoteMaddrs"]), prune.HashPrefix) {
+		t.Errorf("Expected the peer kept and its address hashed, got %+v", records[0].Payload)
+	}
+
+	if records[1].Fields["topic"] != "beacon_block" {
+		t.Errorf("Expected the other log fields kept, got %+v", records[1].Fields)
+	}
+}
@@ -0,0 +1,200 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress targets that name the standard streams, and the prefix of an inherited file descriptor.
+const (
+	TargetStdout   = "stdout"
+	TargetStderr   = "stderr"
+	TargetFDPrefix = "fd:"
+)
+
+// Kinds of progress events.
+const (
+	EventStarted   = "started"   // The run started, before Hermes is up
+	EventPhase     = "phase"     // The run entered a phase
+	EventStatus    = "status"    // Periodic peer and handshake counts
+	EventReport    = "report"    // A report generation stage finished
+	EventCompleted = "completed" // The run and its reports finished
+	EventFailed    = "failed"    // The run ended with an error
+)
+
+// Peers counts the peers seen so far and those with an open connection.
+type Peers struct {
+	Known     int `json:"known"`
+	Connected int `json:"connected"`
+}
+
+// Handshakes counts the connection sessions whose handshake succeeded or failed so far.
+type Handshakes struct {
+	Successful int `json:"successful"`
+	Failed     int `json:"failed"`
+}
+
+// Event is one progress line. The time and elapsed seconds are filled in by the emitter, the
+// other fields are left out when they do not apply to the event.
+type Event struct {
+	Time           time.Time   `json:"time"`
+	Event          string      `json:"event"`
+	ElapsedSeconds int64       `json:"elapsed_seconds"`       // Since the emitter was opened
+	Phase          string      `json:"phase,omitempty"`       // Run phase the event happened in
+	ETASeconds     *int64      `json:"eta_seconds,omitempty"` // Until the planned end of the run
+	Peers          *Peers      `json:"peers,omitempty"`
+	Handshakes     *Handshakes `json:"handshakes,omitempty"`
+	Stage          string      `json:"stage,omitempty"` // Report generation stage
+	Error          string      `json:"error,omitempty"`
+}
+
+// Emitter writes progress events as JSON lines for wrapper automation. A nil emitter discards
+// events, so callers need not check whether progress output is enabled. It is safe for
+// concurrent use.
+type Emitter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer // Nil for the standard streams, which are not ours to close
+	stdout bool
+	clock  func() time.Time
+	start  time.Time
+	err    error // First write error, after which events are dropped
+}
+
+// NewEmitter creates an emitter writing to out, reading the current time from clock.
+func NewEmitter(out io.Writer, clock func() time.Time) *Emitter {
+	return &Emitter{out: out, clock: clock, start: clock()}
+}
+
+// ValidateTarget checks a progress target is stdout, stderr, fd:N or a file path, without opening it.
+func ValidateTarget(target string) error {
+	if target == "" {
+		return fmt.Errorf("progress target must not be empty")
+	}
+
+	if fd, ok := strings.CutPrefix(target, TargetFDPrefix); ok {
+		if n, err := strconv.Atoi(fd); err != nil || n < 0 {
+			return fmt.Errorf("progress target %q must name a file descriptor number", target)
+		}
+	}
+
+	return nil
+}
+
+// Open creates an emitter for a target: stdout, stderr, an inherited file descriptor as fd:N,
+// or a file path, which is appended to so a named pipe works as well.
+func Open(target string) (*Emitter, error) {
+	if err := ValidateTarget(target); err != nil {
+		return nil, err
+	}
+
+	emitter := NewEmitter(nil, time.Now)
+
+	switch target {
+	case TargetStdout:
+		emitter.out = os.Stdout
+		emitter.stdout = true
+	case TargetStderr:
+		emitter.out = os.Stderr
+	default:
+		file, err := openTarget(target)
+		if err != nil {
+			return nil, err
+		}
+
+		emitter.out = file
+		emitter.closer = file
+	}
+
+	return emitter, nil
+}
+
+// openTarget opens an inherited file descriptor or a file path for writing.
+func openTarget(target string) (*os.File, error) {
+	if fd, ok := strings.CutPrefix(target, TargetFDPrefix); ok {
+		n, _ := strconv.Atoi(fd)
+
+		file := os.NewFile(uintptr(n), "progress-"+fd)
+		if _, err := file.Stat(); err != nil {
+			return nil, fmt.Errorf("progress file descriptor %d is not open: %w", n, err)
+		}
+
+		return file, nil
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open progress file: %w", err)
+	}
+
+	return file, nil
+}
+
+// WritesToStdout reports whether events go to standard output, which then must carry nothing else.
+func (e *Emitter) WritesToStdout() bool {
+	return e != nil && e.stdout
+}
+
+// Emit writes an event as one JSON line. Progress output never fails the run, so a write error
+// is kept for Close and later events are dropped.
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.err != nil {
+		return
+	}
+
+	now := e.clock()
+	event.Time = now.UTC()
+	event.ElapsedSeconds = int64(now.Sub(e.start).Seconds())
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		e.err = fmt.Errorf("failed to marshal progress event: %w", err)
+
+		return
+	}
+
+	// One write per line, so a reader never sees a partial event from interleaved writers
+	if _, err := e.out.Write(append(line, '\n')); err != nil {
+		e.err = fmt.Errorf("failed to write progress event: %w", err)
+	}
+}
+
+// Close closes the target if the emitter opened it, returning the first write error, if any.
+func (e *Emitter) Close() error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closer != nil {
+		if err := e.closer.Close(); err != nil && e.err == nil {
+			e.err = fmt.Errorf("failed to close progress target: %w", err)
+		}
+
+		e.closer = nil
+	}
+
+	return e.err
+}
+
+// Seconds returns a duration as whole seconds for an event's ETA, clamped at zero.
+func Seconds(d time.Duration) *int64 {
+	seconds := int64(max(d, 0).Seconds())
+
+	return &seconds
+}
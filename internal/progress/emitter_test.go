@@ -0,0 +1,133 @@
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEmitterWritesJSONLines(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	var out bytes.Buffer
+
+	emitter := NewEmitter(&out, func() time.Time { return now })
+
+	emitter.Emit(Event{Event: EventStarted})
+
+	now = start.Add(90 * time.Second)
+	emitter.Emit(Event{
+		Event:      EventStatus,
+		Phase:      "measure",
+		ETASeconds: Seconds(10 * time.Minute),
+		Peers:      &Peers{Known: 52, Connected: 0},
+		Handshakes: &Handshakes{Successful: 60, Failed: 5},
+	})
+
+	lines := make([]map[string]any, 0, 2)
+
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Expected a JSON object per line, got %q: %v", scanner.Text(), err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	if _, ok := lines[0]["phase"]; ok {
+		t.Errorf("Expected fields that do not apply to be left out, got %v", lines[0])
+	}
+
+	status := lines[1]
+	if status["event"] != EventStatus || status["elapsed_seconds"] != float64(90) || status["eta_seconds"] != float64(600) {
+		t.Errorf("Unexpected status event %v", status)
+	}
+
+	// Zero counts are reported, not left out
+	if peers, ok := status["peers"].(map[string]any); !ok || peers["connected"] != float64(0) {
+		t.Errorf("Expected the connected peer count, got %v", status["peers"])
+	}
+
+	if status["time"] != "2025-06-01T12:01:30Z" {
+		t.Errorf("Unexpected event time %v", status["time"])
+	}
+}
+
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	w.writes++
+
+	return 0, os.ErrClosed
+}
+
+func TestEmitterKeepsFirstWriteError(t *testing.T) {
+	writer := &failingWriter{}
+	emitter := NewEmitter(writer, time.Now)
+
+	emitter.Emit(Event{Event: EventStarted})
+	emitter.Emit(Event{Event: EventCompleted})
+
+	if writer.writes != 1 {
+		t.Errorf("Expected events to be dropped after a write error, got %d writes", writer.writes)
+	}
+
+	if err := emitter.Close(); err == nil {
+		t.Error("Expected Close to return the write error")
+	}
+
+	// A nil emitter discards events
+	var disabled *Emitter
+	disabled.Emit(Event{Event: EventStarted})
+
+	if disabled.WritesToStdout() || disabled.Close() != nil {
+		t.Error("Expected a nil emitter to do nothing")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.jsonl")
+
+	emitter, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error opening a file target, got %v", err)
+	}
+
+	emitter.Emit(Event{Event: EventCompleted})
+
+	if err := emitter.Close(); err != nil {
+		t.Fatalf("Expected no error closing, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Contains(data, []byte(`"event":"completed"`)) {
+		t.Errorf("Expected the event in the file, got %q (%v)", data, err)
+	}
+
+	stdout, err := Open(TargetStdout)
+	if err != nil || !stdout.WritesToStdout() {
+		t.Errorf("Expected a stdout emitter, got %v", err)
+	}
+
+	for _, target := range []string{"", "fd:", "fd:x", "fd:-1"} {
+		if _, err := Open(target); err == nil {
+			t.Errorf("Expected an error for target %q", target)
+		}
+	}
+
+	// A descriptor number that is not open is refused up front
+	if _, err := Open("fd:987654"); err == nil {
+		t.Error("Expected an error for a file descriptor that is not open")
+	}
+}
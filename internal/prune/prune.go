@@ -0,0 +1,211 @@
+// Package prune drops or hashes the report fields some deployments must not store, such as
+// full agent strings or addresses, before any artifact is written.
+package prune
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// HashPrefix marks a hashed value.
+const HashPrefix = "sha256:"
+
+// hashLength is the number of hex digits of the digest kept, enough to join values across
+// artifacts without making hashed values longer than most originals.
+const hashLength = 16
+
+// wildcard matches any single key or array element in a path.
+const wildcard = "*"
+
+// Policy lists the JSON paths dropped from or hashed in every artifact. A path is a dot
+// separated list of keys, "*" matching any key or array element, and matches a field when it
+// matches the end of the field's path. "client_agent" so matches the field at any depth, while
+// "peers.*.client_agent" only matches it on a peer.
+type Policy struct {
+	Drop   []string `json:"drop,omitempty"`
+	Hash   []string `json:"hash,omitempty"`
+	Salted bool     `json:"salted"` // Hashes are keyed with a secret salt, so they cannot be looked up
+
+	drop [][]string
+	hash [][]string
+	salt []byte
+}
+
+// New creates a policy dropping the drop paths and hashing the hash paths, keyed with salt
+// when it is set. It returns nil when there is nothing to prune.
+func New(drop, hash []string, salt string) (*Policy, error) {
+	if len(drop) == 0 && len(hash) == 0 {
+		return nil, nil
+	}
+
+	policy := &Policy{
+		Drop:   drop,
+		Hash:   hash,
+		Salted: salt != "",
+		salt:   []byte(salt),
+	}
+
+	for _, path := range drop {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.drop = append(policy.drop, segments)
+	}
+
+	for _, path := range hash {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.hash = append(policy.hash, segments)
+	}
+
+	return policy, nil
+}
+
+// ParsePaths splits a comma separated list of paths, checking each.
+func ParsePaths(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	paths := make([]string, 0)
+
+	for _, entry := range strings.Split(spec, ",") {
+		path := strings.TrimSpace(entry)
+
+		if _, err := parsePath(path); err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// parsePath splits a path into its keys.
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty prune path")
+	}
+
+	segments := strings.Split(path, ".")
+
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid prune path %q: empty key", path)
+		}
+	}
+
+	if len(segments) == 1 && segments[0] == wildcard {
+		return nil, fmt.Errorf("invalid prune path %q: it would match every field", path)
+	}
+
+	return segments, nil
+}
+
+// Value returns a pruned copy of a decoded JSON value. Dropped fields are removed from their
+// object and dropped array elements left out. Hashed strings are replaced by their digest,
+// strings nested under a hashed path too, while other values under it are dropped. Map keys
+// are never rewritten. A nil policy returns the value unchanged.
+func (p *Policy) Value(value interface{}) interface{} {
+	if p == nil {
+		return value
+	}
+
+	pruned, _ := p.walk(value, make([]string, 0, 8), false)
+
+	return pruned
+}
+
+// walk prunes a value found at path, returning false when it is dropped. hashed is set under
+// a hashed path.
+func (p *Policy) walk(value interface{}, path []string, hashed bool) (interface{}, bool) {
+	if len(path) > 0 {
+		if matchesAny(p.drop, path) {
+			return nil, false
+		}
+
+		hashed = hashed || matchesAny(p.hash, path)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		pruned := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			if kept, ok := p.walk(item, append(path, key), hashed); ok {
+				pruned[key] = kept
+			}
+		}
+
+		return pruned, true
+	case []interface{}:
+		pruned := make([]interface{}, 0, len(v))
+
+		for _, item := range v {
+			if kept, ok := p.walk(item, append(path, wildcard), hashed); ok {
+				pruned = append(pruned, kept)
+			}
+		}
+
+		return pruned, true
+	case string:
+		if hashed {
+			return p.hashString(v), true
+		}
+
+		return v, true
+	default:
+		return value, !hashed
+	}
+}
+
+// hashString digests a value, keyed with the salt when the policy has one. Empty values stay
+// empty, so an unset field does not look set.
+func (p *Policy) hashString(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write([]byte(value))
+
+	return HashPrefix + hex.EncodeToString(mac.Sum(nil))[:hashLength]
+}
+
+// matchesAny reports whether any of the rules matches the end of path.
+func matchesAny(rules [][]string, path []string) bool {
+	for _, rule := range rules {
+		if matches(rule, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches reports whether a rule matches the end of path. Array elements are walked as "*",
+// so only a wildcard in the rule matches them.
+func matches(rule, path []string) bool {
+	if len(rule) > len(path) {
+		return false
+	}
+
+	tail := path[len(path)-len(rule):]
+
+	for i, segment := range rule {
+		if segment != wildcard && segment != tail[i] {
+			return false
+		}
+	}
+
+	return true
+}
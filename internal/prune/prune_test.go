@@ -0,0 +1,105 @@
+package prune
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPolicyValue(t *testing.T) {
+	var report interface{}
+
+	if err := json.Unmarshal([]byte(`{
+		"client_agent": "run-level",
+		"peers": {
+			"16Uiu2HAmPeer": {
+				"client_type": "lighthouse",
+				"client_agent": "Lighthouse/v7.0.1-e42406d/x86_64-linux",
+				"address": "203.0.113.7",
+				"examples": ["203.0.113.7", "198.51.100.2"],
+				"score": 12.5
+			}
+		},
+		"bootstrap_nodes": [{"address": "192.0.2.1", "peer_id": "16Uiu2HAmBoot"}],
+		"hosts": {"address": {"port": 9000}}
+	}`), &report); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	policy, err := New([]string{"peers.*.client_agent", "bootstrap_nodes.*.address"}, []string{"address", "examples"}, "pepper")
+	if err != nil {
+		t.Fatalf("Expected a valid policy, got %v", err)
+	}
+
+	pruned := policy.Value(report).(map[string]interface{})
+
+	if pruned["client_agent"] != "run-level" {
+		t.Errorf("Expected the run-level agent kept, the rule is anchored under peers, got %v", pruned["client_agent"])
+	}
+
+	peer := pruned["peers"].(map[string]interface{})["16Uiu2HAmPeer"].(map[string]interface{})
+
+	if _, ok := peer["client_agent"]; ok {
+		t.Errorf("Expected the peer's agent dropped, got %v", peer["client_agent"])
+	}
+
+	if peer["client_type"] != "lighthouse" || peer["score"] != 12.5 {
+		t.Errorf("Expected other fields kept, got %v", peer)
+	}
+
+	address, _ := peer["address"].(string)
+	if !strings.HasPrefix(address, HashPrefix) || len(address) != len(HashPrefix)+hashLength {
+		t.Errorf("Expected the address hashed, got %q", address)
+	}
+
+	examples := peer["examples"].([]interface{})
+	if len(examples) != 2 || examples[0] != address || examples[1] == address {
+		t.Errorf("Expected each example hashed like the address, got %v", examples)
+	}
+
+	if nodes := pruned["bootstrap_nodes"].([]interface{}); !reflect.DeepEqual(nodes[0], map[string]interface{}{"peer_id": "16Uiu2HAmBoot"}) {
+		t.Errorf("Expected the boot node address dropped, got %v", nodes[0])
+	}
+
+	// Numbers cannot be hashed into the same type, they are dropped under a hashed path
+	if hosts := pruned["hosts"].(map[string]interface{})["address"].(map[string]interface{}); len(hosts) != 0 {
+		t.Errorf("Expected the number under a hashed path dropped, got %v", hosts)
+	}
+
+	// The original is left alone
+	original := report.(map[string]interface{})["peers"].(map[string]interface{})["16Uiu2HAmPeer"].(map[string]interface{})
+	if original["client_agent"] == nil || original["address"] != "203.0.113.7" {
+		t.Errorf("Expected the original value unchanged, got %v", original)
+	}
+
+	unsalted, _ := New(nil, []string{"address"}, "")
+	if unsalted.hashString("203.0.113.7") == address {
+		t.Error("Expected the salt to change the hash")
+	}
+
+	if unsalted.Salted || !policy.Salted {
+		t.Error("Expected only the salted policy marked salted")
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	if policy, err := New(nil, nil, "salt"); policy != nil || err != nil {
+		t.Errorf("Expected no policy without paths, got %v, %v", policy, err)
+	}
+
+	for _, path := range []string{"", "peers..client_agent", "*"} {
+		if _, err := New([]string{path}, nil, ""); err == nil {
+			t.Errorf("Expected path %q rejected", path)
+		}
+	}
+
+	paths, err := ParsePaths(" client_agent , peers.*.address")
+	if err != nil || !reflect.DeepEqual(paths, []string{"client_agent", "peers.*.address"}) {
+		t.Errorf("Unexpected paths %v, %v", paths, err)
+	}
+
+	if _, err := ParsePaths("client_agent,,address"); err == nil {
+		t.Error("Expected an empty path in the list rejected")
+	}
+}
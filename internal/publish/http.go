@@ -0,0 +1,74 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+// HTTPPublisher POSTs summary events as JSON to a Vector HTTP source or any compatible ingest endpoint.
+type HTTPPublisher struct {
+	endpoint   string
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+}
+
+// NewHTTPPublisher creates a publisher for the given endpoint.
+// Basic auth credentials may be embedded in the endpoint URL.
+func NewHTTPPublisher(endpoint string, timeout time.Duration, logger logrus.FieldLogger) *HTTPPublisher {
+	return &HTTPPublisher{
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger: logger.WithField("component", "http_publisher"),
+	}
+}
+
+// Publish sends a summary event to the configured endpoint.
+func (p *HTTPPublisher) Publish(ctx context.Context, event *SummaryEvent) error {
+	// Vector's HTTP source accepts newline-delimited JSON, a single event is one line
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create publish request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish summary event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("publish endpoint returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"endpoint": RedactEndpoint(p.endpoint),
+		"event":    event.Event.Name,
+	}).Info("Published summary metrics")
+
+	return nil
+}
+
+// RedactEndpoint hides credentials embedded in an endpoint URL for logging.
+func RedactEndpoint(endpoint string) string {
+	return redact.URL(endpoint)
+}
@@ -0,0 +1,8 @@
+package publish
+
+import "context"
+
+// Publisher defines the interface for shipping run summary metrics to an external pipeline.
+type Publisher interface {
+	Publish(ctx context.Context, event *SummaryEvent) error
+}
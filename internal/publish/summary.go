@@ -0,0 +1,148 @@
+package publish
+
+import (
+	"math"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+// clientName identifies this tool in published events.
+const clientName = "hermes-peer-score"
+
+// BuildSummaryEvent maps the final peer data of a run to a summary event. The run totals and
+// handshake success rates are the headline's, so the event never disagrees with the reports.
+func BuildSummaryEvent(run RunInfo, headline *reports.RunHeadline, peers map[string]*peer.Stats) *SummaryEvent {
+	metrics := SummaryMetrics{
+		StartTime:            run.StartTime,
+		EndTime:              run.EndTime,
+		DurationSeconds:      run.EndTime.Sub(run.StartTime).Seconds(),
+		UniquePeers:          headline.UniquePeers,
+		TotalConnections:     headline.TotalConnections,
+		SuccessfulHandshakes: headline.SuccessfulHandshakes,
+		FailedHandshakes:     headline.FailedHandshakes,
+		HandshakeSuccessRate: headline.HandshakeSuccessRate,
+		Clients:              make(map[string]*ClientMetrics),
+		Goodbyes: GoodbyeMix{
+			Reasons: make(map[string]int),
+			Codes:   make(map[uint64]int),
+		},
+	}
+
+	scores := make([]float64, 0, len(peers))
+	weights := make([]float64, 0, len(peers))
+
+	for _, stats := range peers {
+		clientType := stats.ClientType
+		if clientType == "" {
+			clientType = "unknown"
+		}
+
+		client, ok := metrics.Clients[clientType]
+		if !ok {
+			client = &ClientMetrics{}
+			metrics.Clients[clientType] = client
+		}
+
+		client.Peers++
+		client.Connections += stats.TotalConnections
+		client.SuccessfulHandshakes += stats.SuccessfulHandshakes
+		client.FailedHandshakes += stats.FailedHandshakes
+
+		var latest *peer.PeerScoreSnapshot
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+
+			for _, goodbye := range session.GoodbyeEvents {
+				// The peers' teardown while Hermes shut down is not their view of us
+				if goodbye.InShutdown {
+					continue
+				}
+
+				reason := goodbye.Reason
+				if reason == "" {
+					reason = "no reason provided"
+				}
+
+				client.GoodbyeEvents++
+				metrics.Goodbyes.Total++
+				metrics.Goodbyes.Reasons[reason]++
+				metrics.Goodbyes.Codes[goodbye.Code]++
+			}
+
+			for j := range session.PeerScores {
+				if latest == nil || session.PeerScores[j].Timestamp.After(latest.Timestamp) {
+					latest = &session.PeerScores[j]
+				}
+			}
+		}
+
+		if latest != nil {
+			scores = append(scores, latest.Score)
+			weights = append(weights, stats.DetailWeight())
+		}
+	}
+
+	for _, summary := range headline.Clients {
+		if client, ok := metrics.Clients[summary.Client]; ok {
+			client.HandshakeSuccessRate = summary.HandshakeSuccessRate
+		}
+	}
+
+	metrics.Scores = calculateScoreStats(scores, weights)
+
+	return &SummaryEvent{
+		Event: EventInfo{
+			Name:     EventName,
+			DateTime: time.Now().UTC(),
+		},
+		Meta: Meta{
+			Client: ClientMeta{
+				Name:           clientName,
+				Network:        run.Network,
+				ValidationMode: run.ValidationMode,
+				HermesVersion:  run.HermesVersion,
+				AgentVersion:   run.AgentVersion,
+			},
+		},
+		Data: metrics,
+	}
+}
+
+// calculateScoreStats computes distribution statistics for a set of scores. Scores exist only
+// for peers whose detail is captured, so each is weighted by its peer's sampling weight to
+// stand for the peers not sampled; the weights are all 1 without sampling.
+func calculateScoreStats(scores, weights []float64) ScoreStats {
+	stats := ScoreStats{ScoredPeers: len(scores)}
+	if len(scores) == 0 {
+		return stats
+	}
+
+	stats.Min = scores[0]
+	stats.Max = scores[0]
+
+	sum, total, negative := 0.0, 0.0, 0.0
+
+	for i, score := range scores {
+		stats.Min = min(stats.Min, score)
+		stats.Max = max(stats.Max, score)
+
+		sum += score * weights[i]
+		total += weights[i]
+
+		if score < 0 {
+			negative += weights[i]
+		}
+	}
+
+	if total > 0 {
+		stats.Mean = sum / total
+	}
+
+	stats.Median = peer.WeightedMedian(scores, weights)
+	stats.Negative = int(math.Round(negative))
+
+	return stats
+}
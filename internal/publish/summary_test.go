@@ -0,0 +1,164 @@
+package publish
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+func TestBuildSummaryEvent(t *testing.T) {
+	now := time.Now()
+	peers := map[string]*peer.Stats{
+		"peer1": {
+			ClientType:           "lighthouse",
+			TotalConnections:     2,
+			SuccessfulHandshakes: 1,
+			FailedHandshakes:     1,
+			ConnectionSessions: []peer.ConnectionSession{
+				{
+					PeerScores: []peer.PeerScoreSnapshot{
+						{Timestamp: now, Score: -4},
+						{Timestamp: now.Add(time.Second), Score: 2},
+					},
+					GoodbyeEvents: []peer.GoodbyeEvent{{Code: 3, Reason: "too many peers"}},
+				},
+			},
+		},
+		"peer2": {
+			ClientType:           "prysm",
+			TotalConnections:     1,
+			SuccessfulHandshakes: 1,
+			ConnectionSessions: []peer.ConnectionSession{
+				{PeerScores: []peer.PeerScoreSnapshot{{Timestamp: now, Score: -1}}},
+			},
+		},
+		"peer3": {
+			TotalConnections: 1,
+			FailedHandshakes: 1,
+		},
+	}
+
+	report := &reports.Report{
+		TotalConnections:     4,
+		SuccessfulHandshakes: 2,
+		FailedHandshakes:     2,
+		Peers:                make(map[string]interface{}, len(peers)),
+	}
+
+	for peerID, stats := range peers {
+		report.Peers[peerID] = stats
+	}
+
+	event := BuildSummaryEvent(RunInfo{
+		Network:        "mainnet",
+		ValidationMode: "delegated",
+		StartTime:      now.Add(-time.Minute),
+		EndTime:        now,
+	}, reports.CalculateHeadline(report), peers)
+
+	if event.Event.Name != EventName {
+		t.Errorf("Expected event name %s, got %s", EventName, event.Event.Name)
+	}
+
+	if event.Meta.Client.Network != "mainnet" {
+		t.Errorf("Expected network mainnet, got %s", event.Meta.Client.Network)
+	}
+
+	data := event.Data
+
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{name: "unique peers", got: float64(data.UniquePeers), expected: 3},
+		{name: "total connections", got: float64(data.TotalConnections), expected: 4},
+		{name: "handshake success rate", got: data.HandshakeSuccessRate, expected: 0.5},
+		{name: "lighthouse success rate", got: data.Clients["lighthouse"].HandshakeSuccessRate, expected: 0.5},
+		{name: "prysm success rate", got: data.Clients["prysm"].HandshakeSuccessRate, expected: 1},
+		{name: "unknown client peers", got: float64(data.Clients["unknown"].Peers), expected: 1},
+		{name: "goodbye total", got: float64(data.Goodbyes.Total), expected: 1},
+		{name: "goodbye code 3", got: float64(data.Goodbyes.Codes[3]), expected: 1},
+		{name: "scored peers", got: float64(data.Scores.ScoredPeers), expected: 2},
+		{name: "latest score used as max", got: data.Scores.Max, expected: 2},
+		{name: "min score", got: data.Scores.Min, expected: -1},
+		{name: "median score", got: data.Scores.Median, expected: 0.5},
+		{name: "negative scores", got: float64(data.Scores.Negative), expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.got)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreStatsWeighted(t *testing.T) {
+	// The sampled peer scoring -2 stands for three peers
+	stats := calculateScoreStats([]float64{-2, 4}, []float64{3, 1})
+
+	want := ScoreStats{ScoredPeers: 2, Min: -2, Max: 4, Mean: -0.5, Median: -2, Negative: 3}
+	if stats != want {
+		t.Errorf("Expected %+v, got %+v", want, stats)
+	}
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	var received SummaryEvent
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "secret" {
+			t.Error("Expected basic auth credentials from endpoint URL")
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("Expected valid JSON body, got %v", err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	endpoint := "http://user:secret@" + server.Listener.Addr().String()
+	publisher := NewHTTPPublisher(endpoint, time.Second, logger)
+
+	event := BuildSummaryEvent(RunInfo{Network: "hoodi"}, &reports.RunHeadline{}, map[string]*peer.Stats{})
+	if err := publisher.Publish(t.Context(), event); err != nil {
+		t.Fatalf("Expected no error publishing, got %v", err)
+	}
+
+	if received.Meta.Client.Network != "hoodi" {
+		t.Errorf("Expected published network hoodi, got %s", received.Meta.Client.Network)
+	}
+
+	if redacted := RedactEndpoint(endpoint); redacted == endpoint {
+		t.Error("Expected credentials to be redacted from endpoint")
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	if err := NewHTTPPublisher(failing.URL, time.Second, logger).Publish(t.Context(), event); err == nil {
+		t.Error("Expected error for non-2xx response")
+	}
+}
@@ -0,0 +1,87 @@
+package publish
+
+import "time"
+
+// EventName is the name summary events are published under.
+const EventName = "HERMES_PEER_SCORE_SUMMARY"
+
+// SummaryEvent is a run summary mapped to the standard ethPandaOps event schema
+// of event metadata, client metadata and event data.
+type SummaryEvent struct {
+	Event EventInfo      `json:"event"`
+	Meta  Meta           `json:"meta"`
+	Data  SummaryMetrics `json:"data"`
+}
+
+// EventInfo identifies the event.
+type EventInfo struct {
+	Name     string    `json:"name"`
+	DateTime time.Time `json:"date_time"`
+}
+
+// Meta describes the run that produced the event.
+type Meta struct {
+	Client ClientMeta `json:"client"`
+}
+
+// ClientMeta describes the tool instance and the run configuration.
+type ClientMeta struct {
+	Name           string `json:"name"`
+	Network        string `json:"network"`
+	ValidationMode string `json:"validation_mode"`
+	HermesVersion  string `json:"hermes_version"`
+	AgentVersion   string `json:"agent_version"`
+}
+
+// SummaryMetrics holds the final summary metrics of a run.
+type SummaryMetrics struct {
+	StartTime            time.Time                 `json:"start_time"`
+	EndTime              time.Time                 `json:"end_time"`
+	DurationSeconds      float64                   `json:"duration_seconds"`
+	UniquePeers          int                       `json:"unique_peers"`
+	TotalConnections     int                       `json:"total_connections"`
+	SuccessfulHandshakes int                       `json:"successful_handshakes"`
+	FailedHandshakes     int                       `json:"failed_handshakes"`
+	HandshakeSuccessRate float64                   `json:"handshake_success_rate"`
+	Clients              map[string]*ClientMetrics `json:"clients"`
+	Goodbyes             GoodbyeMix                `json:"goodbyes"`
+	Scores               ScoreStats                `json:"scores"`
+}
+
+// ClientMetrics holds per-client handshake metrics.
+type ClientMetrics struct {
+	Peers                int     `json:"peers"`
+	Connections          int     `json:"connections"`
+	SuccessfulHandshakes int     `json:"successful_handshakes"`
+	FailedHandshakes     int     `json:"failed_handshakes"`
+	HandshakeSuccessRate float64 `json:"handshake_success_rate"`
+	GoodbyeEvents        int     `json:"goodbye_events"`
+}
+
+// GoodbyeMix describes the distribution of goodbye events.
+type GoodbyeMix struct {
+	Total   int            `json:"total"`
+	Reasons map[string]int `json:"reasons"`
+	Codes   map[uint64]int `json:"codes"`
+}
+
+// ScoreStats summarises the latest gossipsub score of every scored peer. When detail is
+// sampled, the mean, median and negative count are weighted estimates over all peers.
+type ScoreStats struct {
+	ScoredPeers int     `json:"scored_peers"` // Peers with a score snapshot, not weighted
+	Min         float64 `json:"min"`
+	Max         float64 `json:"max"`
+	Mean        float64 `json:"mean"`
+	Median      float64 `json:"median"`
+	Negative    int     `json:"negative"`
+}
+
+// RunInfo carries the run-level details that are not derivable from peer data.
+type RunInfo struct {
+	Network        string
+	ValidationMode string
+	HermesVersion  string
+	AgentVersion   string
+	StartTime      time.Time
+	EndTime        time.Time
+}
@@ -0,0 +1,69 @@
+package quickstart
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// identityKeyBytes is the size of a secp256k1 libp2p private key.
+const identityKeyBytes = 32
+
+// CheckIdentityFile checks that a file holds a hex-encoded libp2p private key, the format
+// --private-key-file reads.
+func CheckIdentityFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read identity file: %w", err)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fmt.Errorf("identity file %s is not hex-encoded: %w", path, err)
+	}
+
+	if len(key) != identityKeyBytes {
+		return fmt.Errorf("identity file %s holds %d bytes, expected a %d byte private key", path, len(key), identityKeyBytes)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat identity file: %w", err)
+	}
+
+	// Windows has no permission bits, access is governed by ACLs Go does not report
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("identity file %s is readable by other users (mode %s), restrict it with chmod 600", path, info.Mode().Perm())
+	}
+
+	return nil
+}
+
+// GenerateIdentityFile writes a new hex-encoded libp2p private key readable only by the
+// current user. An existing file is never overwritten.
+func GenerateIdentityFile(path string) error {
+	key := make([]byte, identityKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate libp2p identity: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("identity file %s already exists", path)
+		}
+
+		return fmt.Errorf("failed to create identity file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		return fmt.Errorf("failed to write identity file: %w", err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,159 @@
+package quickstart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/common"
+)
+
+// Beacon API endpoints the probe reads.
+const (
+	versionPath = "/eth/v1/node/version"
+	specPath    = "/eth/v1/config/spec"
+)
+
+// Prober checks a beacon node and the local machine for init.
+type Prober struct {
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// NewProber creates a prober whose requests and dials give up after timeout.
+func NewProber(timeout time.Duration) *Prober {
+	return &Prober{
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		timeout: timeout,
+	}
+}
+
+// ProbeBeacon reads the beacon node's version and network from its beacon API, trying TLS
+// first. The host may carry user:password@ credentials.
+func (p *Prober) ProbeBeacon(ctx context.Context, host string, port int) (*BeaconInfo, error) {
+	var errs []string
+
+	for _, secure := range []bool{true, false} {
+		info, err := p.probeBeacon(ctx, common.ParseBeaconEndpoint(host, port, secure))
+		if err == nil {
+			info.Secure = secure
+
+			return info, nil
+		}
+
+		errs = append(errs, err.Error())
+	}
+
+	return nil, fmt.Errorf("beacon API not reachable: %s", strings.Join(errs, "; "))
+}
+
+// probeBeacon reads the version and network from one beacon API endpoint.
+func (p *Prober) probeBeacon(ctx context.Context, endpoint *url.URL) (*BeaconInfo, error) {
+	var version struct {
+		Data struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+
+	if err := p.get(ctx, endpoint.JoinPath(versionPath), &version); err != nil {
+		return nil, err
+	}
+
+	var spec struct {
+		Data map[string]interface{} `json:"data"`
+	}
+
+	if err := p.get(ctx, endpoint.JoinPath(specPath), &spec); err != nil {
+		return nil, err
+	}
+
+	configName, _ := spec.Data["CONFIG_NAME"].(string)
+
+	return &BeaconInfo{
+		Version:    version.Data.Version,
+		ConfigName: configName,
+	}, nil
+}
+
+// get decodes the JSON body of a beacon API endpoint into out.
+func (p *Prober) get(ctx context.Context, endpoint *url.URL, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", endpoint.Scheme, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("%s %s returned status %d: %s", endpoint.Scheme, endpoint.Path, resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", endpoint.Path, err)
+	}
+
+	return nil
+}
+
+// PortReachable reports whether a TCP connection to the host's port succeeds.
+func (p *Prober) PortReachable(ctx context.Context, host string, port int) bool {
+	dialer := &net.Dialer{Timeout: p.timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", common.ParseBeaconEndpoint(host, port, false).Host)
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+
+	return true
+}
+
+// FreePort returns the first port from start that is free for TCP and UDP on this machine.
+func FreePort(start, attempts int) (int, error) {
+	for port := start; port < start+attempts; port++ {
+		if portFree(port) {
+			return port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free port in %d-%d", start, start+attempts-1)
+}
+
+// portFree reports whether both the TCP and UDP port can be bound, libp2p listens on both.
+func portFree(port int) bool {
+	addr := ":" + strconv.Itoa(port)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return false
+	}
+
+	_ = listener.Close()
+
+	packetConn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return false
+	}
+
+	_ = packetConn.Close()
+
+	return true
+}
@@ -0,0 +1,49 @@
+package quickstart
+
+// Networks Hermes ships configurations for, anything else needs devnet config files.
+var knownNetworks = map[string]bool{
+	"mainnet": true,
+	"sepolia": true,
+	"holesky": true,
+	"hoodi":   true,
+}
+
+// DevnetNetwork is the network used for beacon nodes on networks Hermes has no built in
+// configuration for.
+const DevnetNetwork = "devnet"
+
+// Options are the init settings given as flags. Unset settings are probed, suggested and,
+// unless NonInteractive is set, confirmed with the operator.
+type Options struct {
+	PrysmHost      string
+	PrysmHTTPPort  int
+	PrysmGRPCPort  int
+	Network        string
+	Libp2pPort     int
+	IdentityFile   string
+	NonInteractive bool
+}
+
+// BeaconInfo is what the probe learned about the beacon node.
+type BeaconInfo struct {
+	Secure     bool   // The beacon API answered over TLS
+	Version    string // Client version from /eth/v1/node/version
+	ConfigName string // CONFIG_NAME from /eth/v1/config/spec
+}
+
+// Network returns the network to run against, devnet when Hermes has no configuration
+// for the beacon node's network.
+func (b *BeaconInfo) Network() string {
+	if knownNetworks[b.ConfigName] {
+		return b.ConfigName
+	}
+
+	return DevnetNetwork
+}
+
+// Setting is one config file entry, keyed by flag name.
+type Setting struct {
+	Key     string
+	Value   interface{}
+	Comment string
+}
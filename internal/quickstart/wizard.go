@@ -0,0 +1,301 @@
+package quickstart
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+// Wizard probes the environment and asks the operator for what it cannot detect, producing
+// the settings of a ready-to-run config file.
+type Wizard struct {
+	in     *bufio.Reader
+	out    io.Writer
+	prober *Prober
+	opts   Options
+}
+
+// NewWizard creates a wizard that prompts on in and reports to out.
+func NewWizard(in io.Reader, out io.Writer, prober *Prober, opts Options) *Wizard {
+	return &Wizard{
+		in:     bufio.NewReader(in),
+		out:    out,
+		prober: prober,
+		opts:   opts,
+	}
+}
+
+// Run walks through the setup and returns the config file settings.
+func (w *Wizard) Run(ctx context.Context) ([]Setting, error) {
+	host, err := w.ask("Prysm host (user:password@host for basic auth)", w.opts.PrysmHost)
+	if err != nil {
+		return nil, err
+	}
+
+	if host == "" {
+		return nil, errors.New("a Prysm host is required, set it with --prysm-host")
+	}
+
+	httpPort, err := w.askInt("Prysm HTTP port", w.opts.PrysmHTTPPort)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := []Setting{
+		{Key: "validation-mode", Value: "delegated", Comment: "delegated lets Prysm validate gossip, independent validates in Hermes"},
+		{Key: "duration", Value: constants.DefaultTestDuration.String()},
+		{Key: "prysm-host", Value: host, Comment: "Beacon node Hermes connects to"},
+		{Key: "prysm-http-port", Value: httpPort},
+	}
+
+	// The beacon API tells us whether TLS is used and which network the node is on
+	secure := httpPort == 443
+	network := w.opts.Network
+
+	info, err := w.prober.ProbeBeacon(ctx, host, httpPort)
+	if err != nil {
+		w.report(false, "Beacon API on port %d: %v", httpPort, redact.New().String(err.Error()))
+	} else {
+		secure = info.Secure
+		w.report(true, "Beacon API on port %d answers over %s, running %s on %s", httpPort, scheme(secure), info.Version, info.ConfigName)
+
+		if network == "" {
+			network = info.Network()
+		} else if network != info.Network() {
+			w.report(false, "Network %s given, but the beacon node is on %s", network, info.ConfigName)
+		}
+	}
+
+	if network == "" {
+		network = "mainnet"
+	}
+
+	if network, err = w.ask("Network", network); err != nil {
+		return nil, err
+	}
+
+	settings = append(settings,
+		Setting{Key: "secure-prysm", Value: secure},
+		Setting{Key: "network", Value: network},
+	)
+
+	if network == DevnetNetwork {
+		apacheURL, aerr := w.ask("Devnet Apache URL serving network-configs/", "")
+		if aerr != nil {
+			return nil, aerr
+		}
+
+		if apacheURL == "" {
+			w.report(false, "Devnets need --devnet-apache-url, set devnet-apache-url in the config before running")
+		}
+
+		settings = append(settings, Setting{Key: "devnet-apache-url", Value: apacheURL})
+	}
+
+	grpcPort, err := w.askInt("Prysm gRPC port", w.opts.PrysmGRPCPort)
+	if err != nil {
+		return nil, err
+	}
+
+	w.report(w.prober.PortReachable(ctx, host, grpcPort), "Prysm gRPC port %d reachable", grpcPort)
+
+	settings = append(settings, Setting{Key: "prysm-grpc-port", Value: grpcPort})
+
+	libp2pSettings, err := w.libp2pPort()
+	if err != nil {
+		return nil, err
+	}
+
+	settings = append(settings, libp2pSettings...)
+
+	identitySettings, err := w.identity()
+	if err != nil {
+		return nil, err
+	}
+
+	settings = append(settings, identitySettings...)
+
+	if os.Getenv("OPENROUTER_API_KEY") == "" {
+		w.report(false, "OPENROUTER_API_KEY is not set, AI analysis is skipped")
+
+		settings = append(settings, Setting{Key: "skip-ai", Value: true})
+	}
+
+	return settings, nil
+}
+
+// libp2pPort suggests a free fixed libp2p port, so peers and reachability checks can dial in.
+func (w *Wizard) libp2pPort() ([]Setting, error) {
+	port := w.opts.Libp2pPort
+	if port == 0 {
+		free, err := FreePort(constants.QuickstartLibp2pPort, constants.QuickstartPortAttempts)
+		if err != nil {
+			w.report(false, "No free libp2p port found: %v", err)
+
+			return nil, nil
+		}
+
+		port = free
+	}
+
+	port, err := w.askInt("libp2p listen port (0 picks a random port)", port)
+	if err != nil {
+		return nil, err
+	}
+
+	if port != 0 {
+		w.report(portFree(port), "libp2p port %d free on this machine", port)
+	}
+
+	return []Setting{{Key: "libp2p-port", Value: port, Comment: "Open this port for inbound peers"}}, nil
+}
+
+// identity checks or creates the file holding the libp2p identity, so runs share a peer ID.
+func (w *Wizard) identity() ([]Setting, error) {
+	path, err := w.ask("libp2p identity file (empty uses a new identity every run)", w.opts.IdentityFile)
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to check identity file: %w", err)
+		}
+
+		generate, aerr := w.ask("Identity file does not exist, generate it? (y/n)", "y")
+		if aerr != nil {
+			return nil, aerr
+		}
+
+		if !strings.HasPrefix(strings.ToLower(generate), "y") {
+			return nil, fmt.Errorf("identity file %s does not exist", path)
+		}
+
+		if err := GenerateIdentityFile(path); err != nil {
+			return nil, err
+		}
+
+		w.report(true, "Generated libp2p identity in %s", path)
+	} else {
+		if err := CheckIdentityFile(path); err != nil {
+			return nil, err
+		}
+
+		w.report(true, "Identity file %s holds a valid private key", path)
+	}
+
+	return []Setting{{Key: "private-key-file", Value: path, Comment: "Hex-encoded libp2p private key, keeps the peer ID stable across runs"}}, nil
+}
+
+// ask prompts for a value, returning the suggestion when the operator enters nothing or the
+// wizard is not interactive.
+func (w *Wizard) ask(question, suggestion string) (string, error) {
+	if w.opts.NonInteractive {
+		return suggestion, nil
+	}
+
+	if suggestion != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, redact.ConnectionString(suggestion))
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	answer, err := w.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer, nil
+	}
+
+	return suggestion, nil
+}
+
+// askInt prompts for a number.
+func (w *Wizard) askInt(question string, suggestion int) (int, error) {
+	answer, err := w.ask(question, strconv.Itoa(suggestion))
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(answer)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number: %w", strings.ToLower(question[:1])+question[1:], err)
+	}
+
+	return value, nil
+}
+
+// report prints the outcome of a check.
+func (w *Wizard) report(ok bool, format string, args ...interface{}) {
+	mark := "ok"
+	if !ok {
+		mark = "!!"
+	}
+
+	fmt.Fprintf(w.out, "[%s] %s\n", mark, fmt.Sprintf(format, args...))
+}
+
+// WriteConfig writes the settings as a YAML config file readable only by the current user,
+// as the Prysm host may carry credentials. An existing file is only replaced when force is set.
+func WriteConfig(path string, settings []Setting, force bool) error {
+	doc := &yaml.Node{
+		Kind:        yaml.MappingNode,
+		HeadComment: "Generated by peer-score-tool init, run with: peer-score-tool --config " + path + "\nFlags given on the command line override these settings.",
+	}
+
+	for _, setting := range settings {
+		value := &yaml.Node{}
+		if err := value.Encode(setting.Value); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", setting.Key, err)
+		}
+
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: setting.Key, HeadComment: setting.Comment}, value)
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+
+	file, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("config file %s already exists, use --force to replace it", path)
+		}
+
+		return fmt.Errorf("failed to create config file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// scheme names the beacon API transport.
+func scheme(secure bool) string {
+	if secure {
+		return "https"
+	}
+
+	return "http"
+}
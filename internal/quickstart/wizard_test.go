@@ -0,0 +1,171 @@
+package quickstart
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+)
+
+func TestWizard(t *testing.T) {
+	tests := []struct {
+		name        string
+		configName  string
+		network     string
+		answers     string
+		interactive bool
+		want        map[string]string
+	}{
+		{
+			name:       "detects network",
+			configName: "hoodi",
+			want:       map[string]string{"network": "hoodi", "secure-prysm": "false", "libp2p-port": "9100"},
+		},
+		{
+			name:       "unknown network runs as devnet",
+			configName: "my-devnet-3",
+			want:       map[string]string{"network": "devnet", "devnet-apache-url": ""},
+		},
+		{
+			name:       "network flag wins over detection",
+			configName: "hoodi",
+			network:    "sepolia",
+			want:       map[string]string{"network": "sepolia"},
+		},
+		{
+			name:        "interactive answers override suggestions",
+			configName:  "hoodi",
+			interactive: true,
+			answers:     "\n\nmainnet\n\n9200\n\n",
+			want:        map[string]string{"network": "mainnet", "libp2p-port": "9200"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case versionPath:
+					_, _ = w.Write([]byte(`{"data":{"version":"Prysm/v6.0.3"}}`))
+				case specPath:
+					_, _ = w.Write([]byte(`{"data":{"CONFIG_NAME":"` + tt.configName + `","SECONDS_PER_SLOT":"12"}}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			serverURL, err := url.Parse(server.URL)
+			if err != nil {
+				t.Fatalf("failed to parse server URL: %v", err)
+			}
+
+			port, err := strconv.Atoi(serverURL.Port())
+			if err != nil {
+				t.Fatalf("failed to parse server port: %v", err)
+			}
+
+			dir := t.TempDir()
+			out := &bytes.Buffer{}
+
+			wizard := NewWizard(strings.NewReader(tt.answers), out, NewProber(time.Second), Options{
+				PrysmHost:      serverURL.Hostname(),
+				PrysmHTTPPort:  port,
+				PrysmGRPCPort:  port,
+				Network:        tt.network,
+				Libp2pPort:     9100,
+				IdentityFile:   filepath.Join(dir, "identity.key"),
+				NonInteractive: !tt.interactive,
+			})
+
+			settings, err := wizard.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v, output:\n%s", err, out.String())
+			}
+
+			if err := CheckIdentityFile(filepath.Join(dir, "identity.key")); err != nil {
+				t.Errorf("expected a valid generated identity: %v", err)
+			}
+
+			path := filepath.Join(dir, "config.yaml")
+			if err := WriteConfig(path, settings, false); err != nil {
+				t.Fatalf("WriteConfig() error = %v", err)
+			}
+
+			if err := WriteConfig(path, settings, false); err == nil {
+				t.Error("expected an existing config file not to be replaced without force")
+			}
+
+			// The written file must load into the tool's flags
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			for _, setting := range settings {
+				flags.String(setting.Key, "", "")
+			}
+
+			if err := config.ApplyFile(path, flags); err != nil {
+				t.Fatalf("ApplyFile() error = %v", err)
+			}
+
+			for key, want := range tt.want {
+				f := flags.Lookup(key)
+				if f == nil {
+					t.Errorf("expected setting %s in config", key)
+
+					continue
+				}
+
+				if f.Value.String() != want {
+					t.Errorf("%s = %q, want %q", key, f.Value.String(), want)
+				}
+			}
+
+			if got := flags.Lookup("prysm-http-port").Value.String(); got != serverURL.Port() {
+				t.Errorf("prysm-http-port = %q, want %q", got, serverURL.Port())
+			}
+		})
+	}
+}
+
+func TestCheckIdentityFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		mode    os.FileMode
+		wantErr string
+	}{
+		{name: "valid", content: strings.Repeat("ab", 32) + "\n", mode: 0o600},
+		{name: "not hex", content: "not a key", mode: 0o600, wantErr: "not hex-encoded"},
+		{name: "wrong length", content: "abcd", mode: 0o600, wantErr: "holds 2 bytes"},
+		{name: "readable by others", content: strings.Repeat("ab", 32), mode: 0o644, wantErr: "readable by other users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-"))
+			if err := os.WriteFile(path, []byte(tt.content), tt.mode); err != nil {
+				t.Fatalf("failed to write identity file: %v", err)
+			}
+
+			err := CheckIdentityFile(path)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("CheckIdentityFile() error = %v", err)
+			}
+
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("CheckIdentityFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
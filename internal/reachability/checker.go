@@ -0,0 +1,135 @@
+package reachability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+// Checker asks an external dial-back vantage whether our libp2p port is reachable.
+type Checker struct {
+	endpoint   string
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+}
+
+// NewChecker creates a checker for the given vantage endpoint.
+func NewChecker(endpoint string, timeout time.Duration, logger logrus.FieldLogger) *Checker {
+	return &Checker{
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger: logger.WithField("component", "reachability_checker"),
+	}
+}
+
+// Check asks the vantage to dial the given port back. A failed check is recorded with an
+// unknown status rather than returned, so the run carries on without reachability data.
+func (c *Checker) Check(ctx context.Context, port int) *Result {
+	result := &Result{
+		Status:    StatusUnknown,
+		Vantage:   redact.URL(c.endpoint),
+		Port:      port,
+		CheckedAt: time.Now(),
+	}
+
+	resp, err := c.dialBack(ctx, port)
+	if err != nil {
+		result.Error = err.Error()
+		c.logger.WithError(err).Warn("Reachability check failed")
+
+		return result
+	}
+
+	result.ObservedAddress = resp.Address
+	result.Error = resp.Error
+	result.BehindNAT = isBehindNAT(resp.Address)
+
+	if resp.Reachable {
+		result.Status = StatusReachable
+	} else {
+		result.Status = StatusUnreachable
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"status":           result.Status,
+		"observed_address": result.ObservedAddress,
+		"behind_nat":       result.BehindNAT,
+	}).Info("Reachability check complete")
+
+	return result
+}
+
+// dialBack requests a dial-back of the given port from the vantage.
+func (c *Checker) dialBack(ctx context.Context, port int) (*DialBackResponse, error) {
+	checkURL, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reachability endpoint: %w", err)
+	}
+
+	query := checkURL.Query()
+	query.Set("port", strconv.Itoa(port))
+	checkURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reachability request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach vantage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return nil, fmt.Errorf("vantage returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var dialBack DialBackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dialBack); err != nil {
+		return nil, fmt.Errorf("failed to decode vantage response: %w", err)
+	}
+
+	return &dialBack, nil
+}
+
+// isBehindNAT reports whether the address the vantage dialed is not one of ours, which
+// means traffic reaches us through address translation.
+func isBehindNAT(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+
+	observed := net.ParseIP(host)
+	if observed == nil {
+		return false
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(observed) {
+			return false
+		}
+	}
+
+	return true
+}
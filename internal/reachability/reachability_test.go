@@ -0,0 +1,165 @@
+package reachability
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// listenPort opens a local TCP listener and returns its port.
+func listenPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// closedPort returns a local port with nothing listening on it.
+func closedPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	return port
+}
+
+func TestServerDialBack(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	server := httptest.NewServer(NewServer(time.Second, logger))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		port          string
+		wantStatus    int
+		wantReachable bool
+	}{
+		{name: "open port", port: strconv.Itoa(listenPort(t)), wantStatus: http.StatusOK, wantReachable: true},
+		{name: "closed port", port: strconv.Itoa(closedPort(t)), wantStatus: http.StatusOK, wantReachable: false},
+		{name: "missing port", port: "", wantStatus: http.StatusBadRequest},
+		{name: "out of range port", port: "70000", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "?port=" + tt.port)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var dialBack DialBackResponse
+			if err := json.NewDecoder(resp.Body).Decode(&dialBack); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if dialBack.Reachable != tt.wantReachable {
+				t.Errorf("expected reachable %v, got %v (error %q)", tt.wantReachable, dialBack.Reachable, dialBack.Error)
+			}
+
+			if want := net.JoinHostPort("127.0.0.1", tt.port); dialBack.Address != want {
+				t.Errorf("expected dial-back address %s, got %s", want, dialBack.Address)
+			}
+		})
+	}
+}
+
+func TestCheckerCheck(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		wantStatus  string
+		wantNAT     bool
+		wantErrText bool
+	}{
+		{
+			name: "reachable from a local address",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(DialBackResponse{Reachable: true, Address: "127.0.0.1:" + r.URL.Query().Get("port")})
+			},
+			wantStatus: StatusReachable,
+		},
+		{
+			name: "unreachable through translated address",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(DialBackResponse{Address: "203.0.113.7:9000", Error: "i/o timeout"})
+			},
+			wantStatus:  StatusUnreachable,
+			wantNAT:     true,
+			wantErrText: true,
+		},
+		{
+			name: "vantage error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			},
+			wantStatus:  StatusUnknown,
+			wantErrText: true,
+		},
+		{
+			name: "malformed response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("not json"))
+			},
+			wantStatus:  StatusUnknown,
+			wantErrText: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			result := NewChecker(server.URL, time.Second, logger).Check(context.Background(), 9000)
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, result.Status)
+			}
+
+			if result.BehindNAT != tt.wantNAT {
+				t.Errorf("expected behind NAT %v, got %v", tt.wantNAT, result.BehindNAT)
+			}
+
+			if (result.Error != "") != tt.wantErrText {
+				t.Errorf("expected error recorded %v, got %q", tt.wantErrText, result.Error)
+			}
+
+			if result.Port != 9000 {
+				t.Errorf("expected port 9000, got %d", result.Port)
+			}
+		})
+	}
+}
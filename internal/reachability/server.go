@@ -0,0 +1,66 @@
+package reachability
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Server is a dial-back vantage. It only dials the requester's own address, so it cannot
+// be used to probe third parties.
+type Server struct {
+	dialTimeout time.Duration
+	logger      logrus.FieldLogger
+}
+
+// NewServer creates a dial-back vantage.
+func NewServer(dialTimeout time.Duration, logger logrus.FieldLogger) *Server {
+	return &Server{
+		dialTimeout: dialTimeout,
+		logger:      logger.WithField("component", "reachability_server"),
+	}
+}
+
+// ServeHTTP dials the requester back on the port given in the query string.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	port, err := strconv.Atoi(r.URL.Query().Get("port"))
+	if err != nil || port <= 0 || port > 65535 {
+		http.Error(w, "port must be between 1 and 65535", http.StatusBadRequest)
+
+		return
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		http.Error(w, "unable to determine remote address", http.StatusBadRequest)
+
+		return
+	}
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	resp := DialBackResponse{Address: address}
+
+	conn, err := net.DialTimeout("tcp", address, s.dialTimeout)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Reachable = true
+
+		conn.Close()
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"address":   address,
+		"reachable": resp.Reachable,
+	}).Info("Dial-back complete")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.WithError(err).Warn("Failed to write dial-back response")
+	}
+}
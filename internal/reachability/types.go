@@ -0,0 +1,28 @@
+package reachability
+
+import "time"
+
+// Reachability statuses recorded in the report.
+const (
+	StatusReachable   = "reachable"   // The vantage dialed our libp2p port back
+	StatusUnreachable = "unreachable" // The vantage could not dial our libp2p port
+	StatusUnknown     = "unknown"     // The check itself failed
+)
+
+// Result is the outcome of a reachability self-test.
+type Result struct {
+	Status          string    `json:"status"`
+	Vantage         string    `json:"vantage"` // Check endpoint, with credentials redacted
+	Port            int       `json:"port"`
+	ObservedAddress string    `json:"observed_address,omitempty"` // Address the vantage dialed, as seen from outside
+	BehindNAT       bool      `json:"behind_nat"`                 // Observed IP is not assigned to a local interface
+	CheckedAt       time.Time `json:"checked_at"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// DialBackResponse is returned by a dial-back vantage.
+type DialBackResponse struct {
+	Reachable bool   `json:"reachable"`
+	Address   string `json:"address"` // Address the vantage dialed
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,373 @@
+// Package recommend derives a recommended configuration for the next run from the run's
+// measurements, so the report says what to change rather than only what happened.
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/probe-lab/hermes/eth"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// Recommend suggests MaxPeers, subnet subscriptions, dial concurrency and the score snapshot
+// interval for the next run. Every setting gets a recommendation, kept at its current value
+// when the measurements do not call for a change.
+func Recommend(in Inputs) *Profile {
+	profile := &Profile{
+		Recommendations: []Recommendation{
+			maxPeers(in),
+			subnets(in),
+			dialConcurrency(in),
+			scoreSnapshotInterval(in),
+		},
+	}
+
+	for i := range profile.Recommendations {
+		recommendation := &profile.Recommendations[i]
+		recommendation.Change = recommendation.Suggested != recommendation.Current
+
+		if recommendation.Change && recommendation.Flag != "" {
+			profile.Flags = append(profile.Flags, recommendation.Flag+"="+recommendation.Suggested)
+		}
+	}
+
+	return profile
+}
+
+// maxPeers prefers the best scored step of a MaxPeers ramp, then backs off when our resource
+// manager refused resources, and makes room when our limit distorted the churn statistics.
+func maxPeers(in Inputs) Recommendation {
+	current := strconv.Itoa(in.MaxPeers)
+	if len(in.MaxPeersRamp) > 0 {
+		current = "ramp " + joinInts(in.MaxPeersRamp)
+	}
+
+	recommendation := Recommendation{Setting: SettingMaxPeers, Flag: "--max-peers", Current: current, Suggested: current}
+
+	if best := bestRampStep(in.Ramp); best != nil {
+		recommendation.Suggested = strconv.Itoa(best.MaxPeers)
+		recommendation.Reason = fmt.Sprintf("Step %d of the ramp (MaxPeers %d) scored us highest, a mean of %.2f at %.1f disconnects per hour",
+			best.Step, best.MaxPeers, best.MeanScore, best.ChurnPerHour)
+
+		return recommendation
+	}
+
+	if len(in.MaxPeersRamp) > 0 {
+		recommendation.Reason = "No ramp step scored enough peers to compare the steps"
+
+		return recommendation
+	}
+
+	switch pressure := in.Pressure; {
+	case in.Resources != nil && in.Resources.LimitedSessions > 0:
+		recommendation.Suggested = strconv.Itoa(max(1, in.MaxPeers*3/4))
+		recommendation.Reason = fmt.Sprintf("The libp2p resource manager refused %d resources and %d sessions ended without a goodbye while it did, fewer peers keep Hermes within its limits",
+			in.Resources.TotalBlocked, in.Resources.LimitedSessions)
+	case pressure == nil:
+		recommendation.Reason = "The peer count was not measured"
+	case pressure.Distorted:
+		recommendation.Suggested = strconv.Itoa(int(math.Ceil(float64(in.MaxPeers) * 1.5)))
+		recommendation.Reason = fmt.Sprintf("Our limit ended %d of %d sessions and the node was at capacity for %s, more room leaves the churn statistics to the peers",
+			pressure.EndedByLocalLimit, pressure.Disconnects, formatSeconds(pressure.SecondsAtCapacity))
+	case pressure.FirstAtCapacity == nil:
+		recommendation.Reason = fmt.Sprintf("The peer count peaked at %d without reaching capacity (%d), the limit did not hold it back",
+			pressure.PeakPeers, pressure.Capacity)
+	default:
+		recommendation.Reason = fmt.Sprintf("The node was at capacity for %s, but our limit ended too few sessions to distort the churn statistics",
+			formatSeconds(pressure.SecondsAtCapacity))
+	}
+
+	return recommendation
+}
+
+// bestRampStep returns the ramp step with the highest mean score, the one with less churn on
+// a tie. Nil unless at least two steps scored peers.
+func bestRampStep(ramp *peer.MaxPeersRamp) *peer.RampStep {
+	if ramp == nil {
+		return nil
+	}
+
+	var best *peer.RampStep
+
+	scored := 0
+
+	for i := range ramp.Steps {
+		step := &ramp.Steps[i]
+		if step.ScoredPeers == 0 {
+			continue
+		}
+
+		scored++
+
+		if best == nil || step.MeanScore > best.MeanScore ||
+			(step.MeanScore == best.MeanScore && step.ChurnPerHour < best.ChurnPerHour) {
+			best = step
+		}
+	}
+
+	if scored < 2 {
+		return nil
+	}
+
+	return best
+}
+
+// subnets suggests subscribing a subnet topic to its healthy subnets only when most of its
+// subnets had an unhealthy mesh, so the peers we have are spread over fewer meshes.
+func subnets(in Inputs) Recommendation {
+	current := describeSubnets(in.Subnets)
+	recommendation := Recommendation{Setting: SettingSubnets, Current: current, Suggested: current}
+
+	if in.Topics == nil {
+		recommendation.Reason = "Topic health was not measured"
+
+		return recommendation
+	}
+
+	type subnetHealth struct {
+		total     int
+		unhealthy int
+		healthy   []int
+	}
+
+	byName := make(map[string]*subnetHealth)
+
+	for _, topic := range in.Topics.Topics {
+		name, subnet, ok := subnetTopic(topic.Topic)
+		if !ok || !topic.MeshSampled {
+			continue
+		}
+
+		health, ok := byName[name]
+		if !ok {
+			health = &subnetHealth{}
+			byName[name] = health
+		}
+
+		health.total++
+
+		if topic.Health == peer.TopicUnhealthy {
+			health.unhealthy++
+		} else {
+			health.healthy = append(health.healthy, subnet)
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	suggested := make(map[string]*eth.SubnetConfig, len(in.Subnets)+len(names))
+	for name, config := range in.Subnets {
+		suggested[name] = config
+	}
+
+	reasons := make([]string, 0)
+
+	for _, name := range names {
+		health := byName[name]
+		if len(health.healthy) == 0 || float64(health.unhealthy) < constants.RecommendUnhealthySubnets*float64(health.total) {
+			continue
+		}
+
+		sort.Ints(health.healthy)
+
+		static := make([]uint64, 0, len(health.healthy))
+		for _, subnet := range health.healthy {
+			static = append(static, uint64(subnet))
+		}
+
+		suggested[name] = &eth.SubnetConfig{Type: eth.SubnetStatic, Subnets: static}
+		reasons = append(reasons, fmt.Sprintf("%d of %d %s subnets had an unhealthy mesh", health.unhealthy, health.total, name))
+	}
+
+	if len(reasons) == 0 {
+		recommendation.Reason = "No subnet topic had an unhealthy mesh on most of its subnets"
+
+		return recommendation
+	}
+
+	recommendation.Suggested = describeSubnets(suggested)
+	recommendation.Reason = strings.Join(reasons, ", ") + ", subscribing to the healthy ones only concentrates our peers on meshes that work"
+
+	return recommendation
+}
+
+// subnetTopic returns the topic name and subnet of a subnet gossip topic such as
+// /eth2/<digest>/beacon_attestation_5/ssz_snappy.
+func subnetTopic(topic string) (string, int, bool) {
+	_, name, ok := peer.ParseGossipTopic(topic)
+	if !ok {
+		return "", 0, false
+	}
+
+	parts := strings.Split(topic, "/")
+
+	subnet, err := strconv.Atoi(strings.TrimPrefix(parts[3], name+"_"))
+	if err != nil || parts[3] == name {
+		return "", 0, false
+	}
+
+	return name, subnet, true
+}
+
+// describeSubnets describes subnet selections by topic name, e.g.
+// "beacon_attestation: static 1,4; sync_committee: random 2". "all" subscribes to every subnet.
+func describeSubnets(configs map[string]*eth.SubnetConfig) string {
+	names := make([]string, 0, len(configs))
+
+	for name, config := range configs {
+		if config != nil && config.Type != eth.SubnetAll {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "all"
+	}
+
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+
+	for _, name := range names {
+		config := configs[name]
+
+		var selection string
+
+		switch config.Type {
+		case eth.SubnetStatic:
+			subnets := make([]int, 0, len(config.Subnets))
+			for _, subnet := range config.Subnets {
+				subnets = append(subnets, int(subnet))
+			}
+
+			selection = "static " + joinInts(subnets)
+		case eth.SubnetRandom:
+			selection = fmt.Sprintf("random %d", config.Count)
+		case eth.SubnetStaticRange:
+			selection = fmt.Sprintf("static range %d-%d", config.Start, config.End)
+		default:
+			selection = string(config.Type)
+		}
+
+		parts = append(parts, name+": "+selection)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// dialConcurrency suggests dialing more peers at once when the node took long to reach
+// capacity or never did.
+func dialConcurrency(in Inputs) Recommendation {
+	current := strconv.Itoa(in.DialConcurrency)
+	recommendation := Recommendation{Setting: SettingDialConcurrency, Current: current, Suggested: current}
+
+	pressure := in.Pressure
+	if pressure == nil {
+		recommendation.Reason = "The peer count was not measured"
+
+		return recommendation
+	}
+
+	var slow string
+
+	switch {
+	case pressure.FirstAtCapacity == nil && in.End.Sub(in.Start) >= constants.RecommendCapacityDelay:
+		slow = fmt.Sprintf("The node never reached capacity (%d peers, it peaked at %d)", pressure.Capacity, pressure.PeakPeers)
+	case pressure.FirstAtCapacity != nil && pressure.FirstAtCapacity.Sub(in.Start) > constants.RecommendCapacityDelay:
+		slow = "Reaching capacity took " + formatSeconds(pressure.FirstAtCapacity.Sub(in.Start).Seconds())
+	case pressure.FirstAtCapacity == nil:
+		recommendation.Reason = "The run was too short to judge how fast the node fills up"
+
+		return recommendation
+	default:
+		recommendation.Reason = "The node reached capacity after " + formatSeconds(pressure.FirstAtCapacity.Sub(in.Start).Seconds())
+
+		return recommendation
+	}
+
+	if in.DialConcurrency >= constants.RecommendMaxDialConcurrency {
+		recommendation.Reason = slow + ", but dial concurrency is already at its suggested maximum"
+
+		return recommendation
+	}
+
+	recommendation.Suggested = strconv.Itoa(min(in.DialConcurrency*2, constants.RecommendMaxDialConcurrency))
+	recommendation.Reason = slow + ", dialing more peers at once fills it sooner"
+
+	return recommendation
+}
+
+// scoreSnapshotInterval suggests snapshotting peer scores more often when many sessions ended
+// before their score was snapshotted twice, too few to follow it.
+func scoreSnapshotInterval(in Inputs) Recommendation {
+	current := in.ScoreSnapshotInterval.String()
+	recommendation := Recommendation{Setting: SettingScoreSnapshotInterval, Current: current, Suggested: current}
+
+	completed, sparse := 0, 0
+
+	for _, stats := range in.Peers {
+		if stats == nil {
+			continue
+		}
+
+		for _, session := range stats.ConnectionSessions {
+			if !session.Disconnected || session.EndedByGap || session.EndedInShutdown || session.EndedByRampRestart {
+				continue
+			}
+
+			completed++
+
+			if len(session.PeerScores) < 2 {
+				sparse++
+			}
+		}
+	}
+
+	if completed == 0 {
+		recommendation.Reason = "No session ended during the run"
+
+		return recommendation
+	}
+
+	share := float64(sparse) / float64(completed)
+	interval := max(in.ScoreSnapshotInterval/2, constants.RecommendMinScoreInterval)
+
+	if share < constants.RecommendSparseScoreShare || interval >= in.ScoreSnapshotInterval {
+		recommendation.Reason = fmt.Sprintf("%d of %d completed sessions (%.0f%%) had fewer than two score snapshots", sparse, completed, share*100)
+
+		return recommendation
+	}
+
+	recommendation.Suggested = interval.String()
+	recommendation.Reason = fmt.Sprintf("%d of %d completed sessions (%.0f%%) had fewer than two score snapshots, too few to follow their score",
+		sparse, completed, share*100)
+
+	return recommendation
+}
+
+// joinInts lists numbers separated by commas.
+func joinInts(values []int) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, strconv.Itoa(value))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// formatSeconds rounds a duration in seconds for a reason, e.g. 12m30s.
+func formatSeconds(seconds float64) string {
+	return (time.Duration(seconds) * time.Second).Round(time.Second).String()
+}
@@ -0,0 +1,182 @@
+package recommend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/probe-lab/hermes/eth"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/resources"
+)
+
+func sessionsWithSnapshots(counts ...int) map[string]*peer.Stats {
+	stats := &peer.Stats{}
+
+	for _, count := range counts {
+		stats.ConnectionSessions = append(stats.ConnectionSessions, peer.ConnectionSession{
+			Disconnected: true,
+			PeerScores:   make([]peer.PeerScoreSnapshot, count),
+		})
+	}
+
+	return map[string]*peer.Stats{"16Uiu2A": stats}
+}
+
+func TestRecommend(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	late := start.Add(20 * time.Minute)
+	early := start.Add(2 * time.Minute)
+
+	base := Inputs{
+		MaxPeers:              80,
+		DialConcurrency:       16,
+		ScoreSnapshotInterval: 30 * time.Second,
+		Start:                 start,
+		End:                   start.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		inputs  func(in Inputs) Inputs
+		setting string
+		want    string
+	}{
+		{
+			name:    "nothing measured keeps max peers",
+			inputs:  func(in Inputs) Inputs { return in },
+			setting: SettingMaxPeers,
+			want:    "80",
+		},
+		{
+			name: "distorted churn makes room",
+			inputs: func(in Inputs) Inputs {
+				in.Pressure = &peer.PeerPressure{Distorted: true, FirstAtCapacity: &early, EndedByLocalLimit: 4, Disconnects: 10}
+
+				return in
+			},
+			setting: SettingMaxPeers,
+			want:    "120",
+		},
+		{
+			name: "resource limits back off first",
+			inputs: func(in Inputs) Inputs {
+				in.Pressure = &peer.PeerPressure{Distorted: true, FirstAtCapacity: &early}
+				in.Resources = &resources.Summary{TotalBlocked: 9, LimitedSessions: 3}
+
+				return in
+			},
+			setting: SettingMaxPeers,
+			want:    "60",
+		},
+		{
+			name: "best scored ramp step",
+			inputs: func(in Inputs) Inputs {
+				in.MaxPeersRamp = []int{40, 80, 120}
+				in.Ramp = &peer.MaxPeersRamp{Steps: []peer.RampStep{
+					{Step: 1, MaxPeers: 40, TimeSlice: peer.TimeSlice{ScoredPeers: 30, MeanScore: 1.5}},
+					{Step: 2, MaxPeers: 80, TimeSlice: peer.TimeSlice{ScoredPeers: 60, MeanScore: 2.5}},
+					{Step: 3, MaxPeers: 120, TimeSlice: peer.TimeSlice{ScoredPeers: 90, MeanScore: 2.5}, ChurnPerHour: 40},
+				}}
+
+				return in
+			},
+			setting: SettingMaxPeers,
+			want:    "80",
+		},
+		{
+			name: "slow to fill dials more",
+			inputs: func(in Inputs) Inputs {
+				in.Pressure = &peer.PeerPressure{FirstAtCapacity: &late}
+
+				return in
+			},
+			setting: SettingDialConcurrency,
+			want:    "32",
+		},
+		{
+			name: "never full dials more",
+			inputs: func(in Inputs) Inputs {
+				in.Pressure = &peer.PeerPressure{Capacity: 76, PeakPeers: 30}
+
+				return in
+			},
+			setting: SettingDialConcurrency,
+			want:    "32",
+		},
+		{
+			name: "quick to fill keeps dial concurrency",
+			inputs: func(in Inputs) Inputs {
+				in.Pressure = &peer.PeerPressure{FirstAtCapacity: &early}
+
+				return in
+			},
+			setting: SettingDialConcurrency,
+			want:    "16",
+		},
+		{
+			name: "sparse score snapshots halve the interval",
+			inputs: func(in Inputs) Inputs {
+				in.Peers = sessionsWithSnapshots(0, 1, 5, 8)
+
+				return in
+			},
+			setting: SettingScoreSnapshotInterval,
+			want:    "15s",
+		},
+		{
+			name: "enough score snapshots keep the interval",
+			inputs: func(in Inputs) Inputs {
+				in.Peers = sessionsWithSnapshots(1, 5, 8, 9, 12)
+
+				return in
+			},
+			setting: SettingScoreSnapshotInterval,
+			want:    "30s",
+		},
+		{
+			name: "mostly unhealthy subnets subscribe to the healthy ones",
+			inputs: func(in Inputs) Inputs {
+				in.Subnets = map[string]*eth.SubnetConfig{"sync_committee": {Type: eth.SubnetRandom, Count: 2}}
+				in.Topics = &peer.TopicHealthSummary{Topics: []peer.TopicHealth{
+					{Topic: "/eth2/4a26c58b/beacon_attestation_0/ssz_snappy", Health: peer.TopicUnhealthy, MeshSampled: true},
+					{Topic: "/eth2/4a26c58b/beacon_attestation_1/ssz_snappy", Health: peer.TopicUnhealthy, MeshSampled: true},
+					{Topic: "/eth2/4a26c58b/beacon_attestation_7/ssz_snappy", Health: peer.TopicDegraded, MeshSampled: true},
+					{Topic: "/eth2/4a26c58b/beacon_attestation_3/ssz_snappy", Health: peer.TopicHealthy, MeshSampled: true},
+					{Topic: "/eth2/4a26c58b/beacon_block/ssz_snappy", Health: peer.TopicUnhealthy, MeshSampled: true},
+				}}
+
+				return in
+			},
+			setting: SettingSubnets,
+			want:    "beacon_attestation: static 3,7; sync_committee: random 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := Recommend(tt.inputs(base))
+
+			for _, recommendation := range profile.Recommendations {
+				if recommendation.Setting != tt.setting {
+					continue
+				}
+
+				if recommendation.Suggested != tt.want || recommendation.Change != (recommendation.Suggested != recommendation.Current) || recommendation.Reason == "" {
+					t.Errorf("Expected %s to be %s, got %+v", tt.setting, tt.want, recommendation)
+				}
+
+				return
+			}
+
+			t.Fatalf("No recommendation for %s", tt.setting)
+		})
+	}
+
+	distorted := base
+	distorted.Pressure = &peer.PeerPressure{Distorted: true, FirstAtCapacity: &early}
+
+	if profile := Recommend(distorted); len(profile.Flags) != 1 || profile.Flags[0] != "--max-peers=120" {
+		t.Errorf("Expected the MaxPeers change as a flag, got %v", profile.Flags)
+	}
+}
@@ -0,0 +1,52 @@
+package recommend
+
+import (
+	"time"
+
+	"github.com/probe-lab/hermes/eth"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/resources"
+)
+
+// Settings the recommendations cover, named as in the Hermes node configuration.
+const (
+	SettingMaxPeers              = "max_peers"
+	SettingSubnets               = "subnets"
+	SettingDialConcurrency       = "dial_concurrency"
+	SettingScoreSnapshotInterval = "peerscore_snapshot_frequency"
+)
+
+// Recommendation is a suggested value for one setting and the measurement behind it.
+type Recommendation struct {
+	Setting   string `json:"setting"`
+	Flag      string `json:"flag,omitempty"` // Command line flag setting it, empty when only the code can
+	Current   string `json:"current"`
+	Suggested string `json:"suggested"`
+	Change    bool   `json:"change"` // Suggested differs from the current value
+	Reason    string `json:"reason"`
+}
+
+// Profile is the configuration recommended for the next run.
+type Profile struct {
+	Recommendations []Recommendation `json:"recommendations"`
+	Flags           []string         `json:"flags,omitempty"` // Command line flags applying the changes that have one
+}
+
+// Inputs are the settings the run used and the measurements the recommendations derive from.
+// Unset measurements leave their recommendation at the current value.
+type Inputs struct {
+	MaxPeers              int
+	MaxPeersRamp          []int // Steps of a MaxPeers ramp, empty without one
+	DialConcurrency       int
+	ScoreSnapshotInterval time.Duration
+	Subnets               map[string]*eth.SubnetConfig // By topic name, topics not listed subscribe to all subnets
+
+	Start     time.Time
+	End       time.Time
+	Peers     map[string]*peer.Stats
+	Pressure  *peer.PeerPressure
+	Ramp      *peer.MaxPeersRamp
+	Resources *resources.Summary
+	Topics    *peer.TopicHealthSummary
+}
@@ -0,0 +1,181 @@
+package redact
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Mask replaces redacted secrets.
+const Mask = "****"
+
+// minSecretLength avoids scrubbing short values that would match ordinary text.
+const minSecretLength = 6
+
+// sensitiveKeys are map key fragments whose values are always redacted.
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "private_key", "privatekey", "credential", "authorization"}
+
+// sensitiveParams are URL query parameters whose values are redacted.
+var sensitiveParams = []string{"token", "key", "secret", "password", "auth", "signature", "sig"}
+
+var (
+	// urlCredentials matches user:password@ in URLs embedded in free text.
+	urlCredentials = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:)[^\s@/]+@`)
+
+	// bearerTokens matches bearer and token authorization values in free text.
+	bearerTokens = regexp.MustCompile(`(?i)\b(bearer|token)\s+[A-Za-z0-9._~+/=-]{8,}`)
+)
+
+// URL redacts the password and sensitive query parameters of a URL. Values that do not
+// parse as absolute URLs are treated as connection strings.
+func URL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ConnectionString(raw)
+	}
+
+	if _, hasPassword := parsed.User.Password(); hasPassword {
+		parsed.User = url.UserPassword(parsed.User.Username(), Mask)
+	}
+
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+
+		for param := range query {
+			if isSensitiveParam(param) {
+				query.Set(param, Mask)
+			}
+		}
+
+		parsed.RawQuery = query.Encode()
+	}
+
+	// Keep the mask readable rather than percent-encoded
+	return strings.ReplaceAll(parsed.String(), url.QueryEscape(Mask), Mask)
+}
+
+// ConnectionString redacts the password of a user:password@host connection string.
+func ConnectionString(connStr string) string {
+	at := strings.LastIndex(connStr, "@")
+	if at <= 0 {
+		return connStr
+	}
+
+	userInfo := connStr[:at]
+
+	colon := strings.Index(userInfo, ":")
+	if colon < 0 || strings.ContainsAny(userInfo, "/ \t\n") {
+		return connStr
+	}
+
+	return userInfo[:colon] + ":" + Mask + connStr[at:]
+}
+
+// Token redacts an API key or token, keeping only whether one was set.
+func Token(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	return Mask
+}
+
+// IsSensitiveKey reports whether a map key names a secret.
+func IsSensitiveKey(key string) bool {
+	normalized := strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(normalized, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSensitiveParam reports whether a URL query parameter carries a secret.
+func isSensitiveParam(param string) bool {
+	normalized := strings.ToLower(param)
+
+	for _, fragment := range sensitiveParams {
+		if strings.Contains(normalized, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redactor scrubs known secret values and credential patterns from values before they
+// are written to reports, data files or AI prompts.
+type Redactor struct {
+	secrets []string
+}
+
+// New creates a redactor for the given secret values. Empty and very short values are ignored.
+func New(secrets ...string) *Redactor {
+	known := make([]string, 0, len(secrets))
+
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			known = append(known, secret)
+		}
+	}
+
+	// Replace longer secrets first, so one containing another is fully masked
+	sort.Slice(known, func(i, j int) bool {
+		return len(known[i]) > len(known[j])
+	})
+
+	return &Redactor{secrets: known}
+}
+
+// String scrubs known secrets, URL credentials and bearer tokens from free text. A nil
+// redactor knows no secrets but still scrubs credential patterns.
+func (r *Redactor) String(text string) string {
+	if r != nil {
+		for _, secret := range r.secrets {
+			text = strings.ReplaceAll(text, secret, Mask)
+		}
+	}
+
+	text = urlCredentials.ReplaceAllString(text, "${1}"+Mask+"@")
+	text = bearerTokens.ReplaceAllString(text, "${1} "+Mask)
+
+	return text
+}
+
+// Value returns a redacted copy of a decoded JSON-like value. Maps and slices are walked,
+// values under sensitive keys are masked, and strings have URL and connection string
+// credentials redacted before being scrubbed.
+func (r *Redactor) Value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			if s, ok := item.(string); ok && IsSensitiveKey(key) {
+				redacted[key] = Token(s)
+
+				continue
+			}
+
+			redacted[key] = r.Value(item)
+		}
+
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+
+		for i, item := range v {
+			redacted[i] = r.Value(item)
+		}
+
+		return redacted
+	case string:
+		return r.String(URL(v))
+	default:
+		return value
+	}
+}
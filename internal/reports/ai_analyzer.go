@@ -11,12 +11,19 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/glossary"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
 )
 
 // DefaultAIAnalyzer implements the AIAnalyzer interface.
 type DefaultAIAnalyzer struct {
 	logger     logrus.FieldLogger
 	httpClient *http.Client
+	endpoint   string // Chat completions URL
+	redactor   *redact.Redactor
 }
 
 // NewDefaultAIAnalyzer creates a new AI analyzer.
@@ -24,11 +31,18 @@ func NewDefaultAIAnalyzer(logger logrus.FieldLogger) *DefaultAIAnalyzer {
 	return &DefaultAIAnalyzer{
 		logger: logger.WithField("component", "ai_analyzer"),
 		httpClient: &http.Client{
-			Timeout: 300 * time.Second, // Increased timeout for DeepSeek
+			Timeout: constants.DefaultAIRequestTimeout,
 		},
+		endpoint: constants.DefaultAIBaseURL + "/chat/completions",
+		redactor: redact.New(),
 	}
 }
 
+// SetRedactor sets the redactor applied to prompts before they are sent.
+func (ai *DefaultAIAnalyzer) SetRedactor(redactor *redact.Redactor) {
+	ai.redactor = redactor
+}
+
 // AnalyzeReport generates AI analysis for the given report.
 func (ai *DefaultAIAnalyzer) AnalyzeReport(report *Report, apiKey string) (string, error) {
 	if apiKey == "" {
@@ -64,8 +78,23 @@ func (ai *DefaultAIAnalyzer) GenerateInsights(data interface{}) (string, error)
 	return insights, nil
 }
 
+// connectionMetricsDefinitions define the connection metrics the AI analysis is given.
+var connectionMetricsDefinitions = []glossary.Definition{
+	{
+		Name:    "Reconnection Rate",
+		Field:   "reconnection_rate",
+		Formula: "Peers with more than one session / peers seen × 100",
+		Caveats: []string{
+			"A percentage of peers, not of connections: a peer reconnecting ten times counts once",
+			"Every peer of any origin over the whole run, so static peers that are redialled raise it",
+		},
+	},
+}
+
 // prepareAnalysisData prepares the report data for AI analysis (enhanced like old implementation).
 func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]interface{} {
+	refs := newAIReferences(report)
+
 	summary := map[string]interface{}{
 		"overview": map[string]interface{}{
 			"test_duration":         report.Duration.String(),
@@ -91,6 +120,292 @@ func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]inte
 		summary["overview"].(map[string]interface{})["success_rate"] = float64(report.SuccessfulHandshakes) / float64(report.TotalConnections) * 100
 	}
 
+	// Retried handshakes reconciled into one outcome per connection episode
+	if report.ReconciledHandshakes != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["reconciled_handshakes"] = report.ReconciledHandshakes
+	}
+
+	// Unreachable nodes only hold outbound connections, which lowers peer retention
+	if report.Reachability != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["reachability"] = report.Reachability
+	}
+
+	// Score percentiles say at a glance whether Hermes is well scored or near the gossipsub thresholds
+	if bands := peer.ScoreBandsFromInterface(report.Peers, report.StartTime, peer.ScoreBandWidth(report.Duration)); bands.Peers > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["score_percentiles"] = map[string]interface{}{
+			"scored_peers":   bands.Peers,
+			"lowest_score":   bands.Min,
+			"mean_score":     bands.Mean,
+			"below_gossip":   bands.BelowGossip,
+			"below_publish":  bands.BelowPublish,
+			"below_graylist": bands.BelowGraylist,
+		}
+	}
+
+	// Per slice statistics show whether the run degraded partway through
+	if len(report.TimeSlices) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["time_slices"] = report.TimeSlices
+	}
+
+	// The same per beacon epoch, as incidents on the network are reported
+	if len(report.Epochs) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["epochs"] = report.Epochs
+	}
+
+	// Sessions our own peer limit ended are not churn caused by the network
+	if report.PeerPressure != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["peer_pressure"] = report.PeerPressure
+	}
+
+	// Disconnects our own resource manager caused by refusing connections, streams or memory
+	if report.Resources != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["resources"] = report.Resources
+	}
+
+	// How scores and churn responded to each step of a MaxPeers ramp, to find the limit that suits us
+	if report.MaxPeersRamp != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["max_peers_ramp"] = report.MaxPeersRamp
+	}
+
+	// Per-topic health across peers, the healthy topics and the mesh series are left out
+	if health := report.TopicHealth; health != nil && len(health.Topics) > 0 {
+		flagged := make([]peer.TopicHealth, 0, health.Degraded+health.Unhealthy)
+
+		for _, topic := range health.Topics {
+			if topic.Health != peer.TopicHealthy {
+				topic.Mesh = nil
+				flagged = append(flagged, topic)
+			}
+		}
+
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["topic_health"] = map[string]interface{}{
+			"dlo":       health.Dlo,
+			"healthy":   health.Healthy,
+			"degraded":  health.Degraded,
+			"unhealthy": health.Unhealthy,
+			"flagged":   flagged,
+		}
+	}
+
+	// Invalid deliveries across many peers usually point at a Hermes bug, not at the peers
+	if report.InvalidDeliveries != nil && len(report.InvalidDeliveries.Anomalies) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["invalid_deliveries"] = report.InvalidDeliveries
+	}
+
+	// Our own router behaviour explains part of how peers score us, the per-bucket samples are left out
+	if router := report.RouterMetrics; router != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["local_router"] = map[string]interface{}{
+			"totals":     router.Totals,
+			"topics":     router.Topics,
+			"publishing": router.Publishing,
+		}
+	}
+
+	// A skewed clock makes timeliness-sensitive scores unreliable, whatever the peers did
+	if report.ClockSkew != nil && report.ClockSkew.Skewed {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["clock_skew"] = report.ClockSkew
+	}
+
+	// Nothing was observed while the node was starved, low counts then say nothing about the peers
+	if len(report.Starvation) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["event_starvation"] = report.Starvation
+	}
+
+	// Events of other topics were discarded, so scores and mesh events cover the whitelisted topics only
+	if report.TopicWhitelist != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["topic_whitelist"] = report.TopicWhitelist
+	}
+
+	// Stalled nodes explain low scores and prunes that are not our fault
+	if report.StatusTracking != nil && (report.StatusTracking.Peers > 0 || report.StatusTracking.RetriedPeers > 0) {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["status_tracking"] = report.StatusTracking
+	}
+
+	// Peers trailing our head explain churn their goodbyes do not
+	if report.HeadDivergence != nil && report.HeadDivergence.Statuses > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["head_divergence"] = report.HeadDivergence
+	}
+
+	// Sessions closed while Hermes shut down show how peers react to our teardown, not churn
+	if report.Shutdown != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["shutdown"] = report.Shutdown
+	}
+
+	// Session lengths treat sessions the run ended as censored, their lengths are lower bounds
+	if report.SessionSurvival != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["session_survival"] = report.SessionSurvival
+	}
+
+	// Scores and mesh events cover a weighted sample of peers only, not every peer
+	if report.Sampling != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["detail_sampling"] = report.Sampling
+	}
+
+	// Mesh events and score snapshots of sampled types hold one in N events only
+	if report.EventSampling != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["event_sampling"] = report.EventSampling
+	}
+
+	// Events processed out of trace timestamp order
+	if report.DataQuality != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["data_quality"] = report.DataQuality
+	}
+
+	// A topic set that does not match the fork skews every peer score, the full event list is left out
+	if subs := report.Subscriptions; subs != nil && subs.Checked {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["topic_subscriptions"] = map[string]interface{}{
+			"fork":       subs.Fork,
+			"valid":      subs.Valid,
+			"missing":    subs.Missing,
+			"unexpected": subs.Unexpected,
+		}
+	}
+
+	// How many peers Hermes and the beacon node share, both being independent hosts on the network
+	if beacon := report.BeaconPeers; beacon != nil && beacon.Error == "" {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["beacon_peer_cross_check"] = map[string]interface{}{
+			"overlap":     beacon.Overlap,
+			"beacon_only": beacon.BeaconOnly,
+			"hermes_only": beacon.HermesOnly,
+		}
+	}
+
+	// Slow or failing beacon data fetches leave independent validation on stale state
+	if fetches := report.BeaconFetches; fetches != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["beacon_data_fetches"] = map[string]interface{}{
+			"health":   fetches.Health,
+			"reasons":  fetches.Reasons,
+			"requests": fetches.Requests,
+			"failures": fetches.Failures,
+			"slow":     fetches.Slow,
+		}
+	}
+
+	// A degraded beacon node behind delegated validation fails requests and misjudges gossip for us
+	if beaconSync := report.BeaconSync; beaconSync != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["beacon_sync"] = map[string]interface{}{
+			"health":           beaconSync.Health,
+			"failures":         beaconSync.Failures,
+			"windows":          len(beaconSync.Windows),
+			"degraded_seconds": beaconSync.DegradedSeconds,
+			"paused_snapshots": beaconSync.PausedSnapshots,
+		}
+	}
+
+	// Canaries misjudged by our own node mean the measurement pipeline cannot be trusted
+	if canaries := report.Canary; canaries != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["canary"] = map[string]interface{}{
+			"status":   canaries.Status,
+			"canaries": len(canaries.Canaries),
+			"failed":   canaries.Failed,
+		}
+	}
+
+	// Peers only we score badly are hostile toward us, peers everyone scores badly are bad for the network
+	if consensus := report.ScoreConsensus; consensus != nil && consensus.Error == "" {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["score_consensus"] = map[string]interface{}{
+			"other_vantage_points": len(consensus.Instances),
+			"shared_peers":         consensus.SharedPeers,
+			"verdicts":             consensus.Verdicts,
+		}
+	}
+
+	// QUIC and TCP sessions may differ in stability, compare them per transport
+	if transports := peer.TransportBreakdownFromInterface(report.Peers); len(transports) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["transport_stability"] = transports
+	}
+
+	// Boot nodes and static peers behave unlike the network at large and are not in the headline statistics
+	if origins := peer.OriginBreakdownFromInterface(report.Peers); len(origins) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["peer_origins"] = origins
+	}
+
+	// Boot nodes and static peers that were never reached point at a misconfigured network
+	if len(report.BootstrapNodes) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["bootstrap_nodes"] = report.BootstrapNodes
+	}
+
+	// Findings of the custom analyzers, failed ones have nothing to add
+	if len(report.Analyses) > 0 {
+		analyses := make(map[string]json.RawMessage, len(report.Analyses))
+		for _, section := range report.Analyses {
+			if section.Error == "" && len(section.Data) > 0 {
+				analyses[section.Name] = section.Data
+			}
+		}
+
+		if len(analyses) > 0 {
+			//nolint:errcheck // ok.
+			summary["overview"].(map[string]interface{})["custom_analyses"] = analyses
+		}
+	}
+
+	// The configuration the measurements suggest, for the analysis to confirm or argue with
+	if report.Recommendations != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["recommendations"] = report.Recommendations
+	}
+
+	// Connections that failed during negotiation, hostility the peer statistics cannot show
+	if report.NegotiationFailures != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["negotiation_failures"] = report.NegotiationFailures
+	}
+
+	// Whether churn is the same peers cycling or the network turning over, against earlier runs
+	if report.PeerOverlap != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["peer_overlap"] = report.PeerOverlap
+	}
+
+	// Rate limit breaches and malformed frames per client, peers are cited through their refs
+	if abuse := peer.ReqRespAbuseByClientFromInterface(report.Peers); len(abuse) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["reqresp_abuse_by_client"] = abuse
+	}
+
+	// Peers taking messages through gossip without delivering any, per client
+	if leeches := peer.GossipLeechesByClientFromInterface(report.Peers); len(leeches) > 0 {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["gossip_leeches_by_client"] = leeches
+	}
+
+	// Reconnects after a goodbye tell load shedding apart from peers rejecting us for good
+	if reconnects := peer.GoodbyeReconnectsFromInterface(report.Peers, report.EndTime); reconnects != nil {
+		//nolint:errcheck // ok.
+		summary["overview"].(map[string]interface{})["goodbye_reconnects"] = reconnects
+	}
+
 	// Analyze connection metrics and peer behavior
 	var (
 		connectionDurations    []time.Duration
@@ -101,6 +416,7 @@ func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]inte
 		mostActivePeerMsgCount int
 		disconnectReasons      = make(map[string]int)
 		reconnections          int
+		citablePeers           = make([]citablePeer, 0, len(report.Peers))
 	)
 
 	for peerID, peerData := range report.Peers {
@@ -112,9 +428,15 @@ func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]inte
 			}
 
 			// Get message count for this peer
-			var peerMsgCount int
+			var (
+				peerMsgCount     int
+				peerSessionCount int
+				peerReasons      = make([]string, 0)
+			)
 
 			if sessions, ok := peer["connection_sessions"].([]interface{}); ok {
+				peerSessionCount = len(sessions)
+
 				if len(sessions) > 1 {
 					reconnections++
 				}
@@ -146,6 +468,7 @@ func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]inte
 								if goodbye, ok := goodbyeData.(map[string]interface{}); ok {
 									if reason, ok := goodbye["reason"].(string); ok {
 										disconnectReasons[reason]++
+										peerReasons = append(peerReasons, reason)
 									}
 								}
 							}
@@ -156,6 +479,15 @@ func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]inte
 
 			totalMessages += peerMsgCount
 
+			clientType, _ := peer["client_type"].(string)
+			citablePeers = append(citablePeers, citablePeer{
+				Ref:            refs.Ref(peerID),
+				ClientType:     clientType,
+				Sessions:       peerSessionCount,
+				Messages:       peerMsgCount,
+				GoodbyeReasons: peerReasons,
+			})
+
 			if peerMsgCount > mostActivePeerMsgCount {
 				mostActivePeerMsgCount = peerMsgCount
 				mostActivePeerID = peerID
@@ -204,7 +536,7 @@ func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]inte
 	summary["peer_behavior_summary"] = map[string]interface{}{
 		"peers_with_scores":          peersWithScores,
 		"peers_with_mesh_events":     peersWithMeshEvents,
-		"most_active_peer_id":        mostActivePeerID,
+		"most_active_peer_ref":       refs.Ref(mostActivePeerID),
 		"most_active_peer_msg_count": mostActivePeerMsgCount,
 		"avg_messages_per_peer":      float64(0),
 	}
@@ -245,6 +577,24 @@ func (ai *DefaultAIAnalyzer) prepareAnalysisData(report *Report) map[string]inte
 
 	summary["top_disconnect_reasons"] = topReasons
 
+	// Peers that reconnect most are the most telling, the model may only cite what it is given
+	sort.Slice(citablePeers, func(i, j int) bool {
+		if citablePeers[i].Sessions != citablePeers[j].Sessions {
+			return citablePeers[i].Sessions > citablePeers[j].Sessions
+		}
+
+		if citablePeers[i].Messages != citablePeers[j].Messages {
+			return citablePeers[i].Messages > citablePeers[j].Messages
+		}
+
+		return citablePeers[i].Ref < citablePeers[j].Ref
+	})
+
+	summary["references"] = map[string]interface{}{
+		"sections": refs.Sections(),
+		"peers":    citablePeers[:min(len(citablePeers), constants.AIReferencePeerLimit)],
+	}
+
 	return summary
 }
 
@@ -258,6 +608,8 @@ func (ai *DefaultAIAnalyzer) callOpenRouterAPI(data map[string]interface{}, apiK
 
 	// Prepare the prompt for analysis
 	systemPrompt, userPrompt := ai.buildAnalysisPrompts(data)
+	systemPrompt = ai.redactor.String(systemPrompt)
+	userPrompt = ai.redactor.String(userPrompt)
 
 	// Prepare the API request
 	requestBody := map[string]interface{}{
@@ -282,7 +634,7 @@ func (ai *DefaultAIAnalyzer) callOpenRouterAPI(data map[string]interface{}, apiK
 	}
 
 	// Create HTTP request
-	req, err := http.NewRequest("POST", "https://openrouter.ai/api/v1/chat/completions", bytes.NewBuffer(requestJSON))
+	req, err := http.NewRequest("POST", ai.endpoint, bytes.NewBuffer(requestJSON))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -364,6 +716,13 @@ IMPORTANT: Provide your response as clean HTML using Tailwind CSS classes. Use t
 - Code/metrics: span with "bg-gray-100 px-1 py-0.5 rounded text-sm font-mono"
 - This HTML will be embedded via Javascript, so to avoid any issues, ensure basic, clean HTML is used only
 
+CITATIONS: Every finding must cite the evidence it rests on, so readers can verify it in the report:
+
+- Cite a peer as [peer:REF], using a "ref" from references.peers or most_active_peer_ref, e.g. [peer:16Uiu2HAmAbCd]
+- Cite a report section as [section:ANCHOR], using an "anchor" from references.sections, e.g. [section:data-quality]
+- Write citations exactly in this form, outside of HTML attributes, and only with identifiers present in the data; never invent or shorten them
+- Prefer specific peers and sections over general statements; a finding you cannot cite should be presented as a hypothesis
+
 Do not include any markdown formatting - return only HTML.`
 
 	userPrompt := fmt.Sprintf(`Analyze this Hermes network monitoring data to understand why peers are disconnecting from our monitoring tool:
@@ -378,7 +737,7 @@ Please provide HTML sections for:
 4. **Network Integration Issues** - Is Hermes participating effectively in gossipsub without being too resource-intensive for other peers?
 5. **Monitoring Optimization** - How can Hermes become a better network participant to maintain stable monitoring connections?
 
-Focus on improving Hermes as a passive network monitoring tool that other peers want to stay connected to. Use proper HTML structure with the specified Tailwind classes.`, string(dataJSON))
+Focus on improving Hermes as a passive network monitoring tool that other peers want to stay connected to. Use proper HTML structure with the specified Tailwind classes, and cite the peers and sections behind each finding.`, string(dataJSON))
 
 	return systemPrompt, userPrompt
 }
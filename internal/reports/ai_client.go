@@ -0,0 +1,92 @@
+package reports
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// AIClientOptions configures how the AI analysis reaches its API.
+type AIClientOptions struct {
+	BaseURL  string        // OpenAI-compatible API the chat completion is posted under
+	ProxyURL string        // HTTP(S) proxy, empty to use HTTPS_PROXY and HTTP_PROXY from the environment
+	CABundle string        // PEM file of CAs trusted on top of the system's, e.g. a TLS-intercepting proxy's
+	Timeout  time.Duration // Of the whole request, response included
+}
+
+// NewAIHTTPClient creates the HTTP client AI requests are sent with. It builds its own
+// transport rather than sharing the default one, so the proxy and CAs apply to AI requests only.
+func NewAIHTTPClient(options AIClientOptions) (*http.Client, error) {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	if options.ProxyURL != "" {
+		proxy, err := url.Parse(options.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid AI proxy URL")
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if options.CABundle != "" {
+		pem, err := os.ReadFile(options.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read AI CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("AI CA bundle %s holds no PEM certificates", options.CABundle)
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &http.Client{Transport: transport, Timeout: options.Timeout}, nil
+}
+
+// completionsURL returns the chat completions endpoint under an API base URL. Query
+// parameters of the base URL, such as a gateway's API version, are kept.
+func completionsURL(baseURL string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return "", fmt.Errorf("AI base URL must be an absolute http or https URL")
+	}
+
+	return base.JoinPath("chat", "completions").String(), nil
+}
+
+// SetClient points the analyzer at the API and proxy in the options.
+func (ai *DefaultAIAnalyzer) SetClient(options AIClientOptions) error {
+	endpoint, err := completionsURL(options.BaseURL)
+	if err != nil {
+		return err
+	}
+
+	client, err := NewAIHTTPClient(options)
+	if err != nil {
+		return err
+	}
+
+	ai.endpoint = endpoint
+	ai.httpClient = client
+
+	return nil
+}
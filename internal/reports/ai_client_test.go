@@ -0,0 +1,62 @@
+package reports
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAIClientGateway(t *testing.T) {
+	var path, query string
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"analysis"}}]}`))
+	}))
+	defer server.Close()
+
+	// Trust the gateway's self-signed certificate through the CA bundle
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	if err := os.WriteFile(bundle, certificate, 0o600); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	analyzer := NewDefaultAIAnalyzer(logger)
+
+	if err := analyzer.SetClient(AIClientOptions{BaseURL: server.URL + "/openai/v1?api-version=2024-06-01", CABundle: bundle, Timeout: time.Minute}); err != nil {
+		t.Fatalf("Expected the client to be configured, got %v", err)
+	}
+
+	analysis, err := analyzer.callOpenRouterAPI(map[string]interface{}{"test": "data"}, "key")
+	if err != nil {
+		t.Fatalf("Expected the gateway to answer, got %v", err)
+	}
+
+	if analysis != "analysis" || path != "/openai/v1/chat/completions" || query != "api-version=2024-06-01" {
+		t.Errorf("Expected the completion from /openai/v1/chat/completions with the API version, got %q from %s?%s", analysis, path, query)
+	}
+
+	invalid := []AIClientOptions{
+		{BaseURL: "openrouter.ai/api/v1", Timeout: time.Minute},
+		{BaseURL: server.URL, ProxyURL: "://proxy", Timeout: time.Minute},
+		{BaseURL: server.URL, CABundle: filepath.Join(t.TempDir(), "missing.pem"), Timeout: time.Minute},
+	}
+
+	for _, options := range invalid {
+		if err := analyzer.SetClient(options); err == nil {
+			t.Errorf("Expected an error for %+v", options)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package reports
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AI analysis export formats, the suffix each adds to the HTML report's filename.
+const (
+	AIExportMarkdown = "-ai-analysis.md"
+	AIExportText     = "-ai-analysis.txt"
+	AIExportHTML     = "-ai-analysis.html"
+)
+
+// blankLines matches runs of blank lines, collapsed to one in the exports.
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// exportAIAnalysis writes the AI analysis next to the HTML report as standalone markdown,
+// plain text and HTML, so it can be pasted into GitHub issues and Slack. Citations are
+// resolved to full peer IDs and section titles. It returns the files written.
+func (g *DefaultGenerator) exportAIAnalysis(report *Report, aiAnalysis, htmlFilename string) ([]string, error) {
+	resolved := newAIReferences(report).Resolve(aiAnalysis)
+
+	nodes, err := html.ParseFragment(strings.NewReader(resolved), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AI analysis: %w", err)
+	}
+
+	exports := []struct {
+		suffix  string
+		kind    string
+		content string
+	}{
+		{suffix: AIExportMarkdown, kind: ArtifactAIMarkdown, content: renderAIText(nodes, true)},
+		{suffix: AIExportText, kind: ArtifactAIText, content: renderAIText(nodes, false)},
+		{suffix: AIExportHTML, kind: ArtifactAIHTML, content: resolved},
+	}
+
+	written := make([]string, 0, len(exports))
+
+	for _, export := range exports {
+		filename := aiExportFilename(htmlFilename, export.suffix)
+
+		if err := g.fileManager.SaveHTML(filename, g.redactor.String(export.content)); err != nil {
+			return written, fmt.Errorf("failed to save AI analysis export: %w", err)
+		}
+
+		written = append(written, filename)
+		g.recordArtifact(export.kind, filename)
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"markdown": written[0],
+		"text":     written[1],
+		"html":     written[2],
+	}).Info("AI analysis exported")
+
+	return written, nil
+}
+
+// aiExportFilename returns the filename of an AI analysis export of the HTML report.
+func aiExportFilename(htmlFilename, suffix string) string {
+	return strings.TrimSuffix(htmlFilename, filepath.Ext(htmlFilename)) + suffix
+}
+
+// renderAIText renders parsed AI analysis HTML as markdown, or as plain text without markup.
+func renderAIText(nodes []*html.Node, markdown bool) string {
+	w := &aiTextWriter{markdown: markdown}
+
+	for _, node := range nodes {
+		w.node(node, "")
+	}
+
+	text := blankLines.ReplaceAllString(w.b.String(), "\n\n")
+
+	return strings.TrimSpace(text) + "\n"
+}
+
+// aiTextWriter renders AI analysis HTML as text. Whitespace in text is collapsed, and a space
+// between words is only written once the next word follows on the same line.
+type aiTextWriter struct {
+	b         strings.Builder
+	markdown  bool
+	space     bool // A space is due before the next word
+	lineStart bool // Nothing but a line break or prefix was written since the last word
+}
+
+// node writes a node and its children. Block elements are separated by blank lines, list
+// items are prefixed with a dash, and in markdown headings, emphasis and code keep their markup.
+func (w *aiTextWriter) node(node *html.Node, indent string) {
+	switch node.Type {
+	case html.TextNode:
+		w.text(node.Data)
+
+		return
+	case html.ElementNode:
+	default:
+		w.children(node, indent)
+
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		w.block("\n\n")
+
+		if w.markdown {
+			w.block(strings.Repeat("#", int(node.Data[1]-'0')) + " ")
+		}
+
+		w.children(node, indent)
+		w.block("\n\n")
+	case atom.P, atom.Div:
+		w.block("\n\n")
+		w.children(node, indent)
+		w.block("\n\n")
+	case atom.Ul, atom.Ol:
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.DataAtom == atom.Li {
+				w.block("\n" + indent + "- ")
+				w.children(child, indent+"  ")
+			}
+		}
+
+		// Nested lists continue their parent's items
+		if indent == "" {
+			w.block("\n\n")
+		}
+	case atom.Br:
+		w.block("\n" + indent)
+	case atom.Strong, atom.B:
+		w.inline(node, indent, "**")
+	case atom.Em, atom.I:
+		w.inline(node, indent, "*")
+	case atom.Code:
+		w.inline(node, indent, "`")
+	case atom.Span:
+		marker := ""
+		if strings.Contains(attr(node, "class"), "font-mono") {
+			marker = "`"
+		}
+
+		w.inline(node, indent, marker)
+	case atom.Script, atom.Style:
+	default:
+		w.children(node, indent)
+	}
+}
+
+// children writes the children of a node.
+func (w *aiTextWriter) children(node *html.Node, indent string) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		w.node(child, indent)
+	}
+}
+
+// inline writes an inline element, wrapped in marker in markdown.
+func (w *aiTextWriter) inline(node *html.Node, indent, marker string) {
+	if !w.markdown {
+		marker = ""
+	}
+
+	w.word(marker)
+	w.children(node, indent)
+	w.b.WriteString(marker)
+}
+
+// text writes text with its whitespace collapsed.
+func (w *aiTextWriter) text(text string) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		w.space = w.space || text != ""
+
+		return
+	}
+
+	if strings.TrimLeft(text, " \t\r\n") != text {
+		w.space = true
+	}
+
+	w.word(strings.Join(words, " "))
+	w.space = strings.TrimRight(text, " \t\r\n") != text
+}
+
+// word writes s, preceded by a space if one is due.
+func (w *aiTextWriter) word(s string) {
+	if s == "" {
+		return
+	}
+
+	if w.space && !w.lineStart {
+		w.b.WriteString(" ")
+	}
+
+	w.b.WriteString(s)
+	w.space = false
+	w.lineStart = false
+}
+
+// block writes a line break or line prefix, dropping any space due.
+func (w *aiTextWriter) block(s string) {
+	w.b.WriteString(s)
+	w.space = false
+	w.lineStart = true
+}
+
+// attr returns the value of a node's attribute, or an empty string.
+func attr(node *html.Node, name string) string {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,60 @@
+package reports
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestRenderAIText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		markdown string
+		text     string
+	}{
+		{
+			name:     "headings and paragraphs",
+			content:  "<h2>Summary</h2>\n<p>Peers  stayed\n connected.</p><p>Scores were <strong>stable</strong>.</p>",
+			markdown: "## Summary\n\nPeers stayed connected.\n\nScores were **stable**.\n",
+			text:     "Summary\n\nPeers stayed connected.\n\nScores were stable.\n",
+		},
+		{
+			name:     "nested lists",
+			content:  "<ul><li>Lighthouse <em>dropped</em> us<ul><li>twice</li></ul></li><li>Teku stayed</li></ul>",
+			markdown: "- Lighthouse *dropped* us\n  - twice\n- Teku stayed\n",
+			text:     "- Lighthouse dropped us\n  - twice\n- Teku stayed\n",
+		},
+		{
+			name:     "peer IDs and line breaks",
+			content:  `<p>Peer <span class="font-mono">16Uiu2HAmBBBBqrs</span> sent<br>invalid <code>beacon_block</code> messages.</p>`,
+			markdown: "Peer `16Uiu2HAmBBBBqrs` sent\ninvalid `beacon_block` messages.\n",
+			text:     "Peer 16Uiu2HAmBBBBqrs sent\ninvalid beacon_block messages.\n",
+		},
+		{
+			name:     "scripts are dropped",
+			content:  "<p>Fine.</p><script>alert(1)</script>",
+			markdown: "Fine.\n",
+			text:     "Fine.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := html.ParseFragment(strings.NewReader(tt.content), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+			if err != nil {
+				t.Fatalf("Expected no error parsing %q, got %v", tt.content, err)
+			}
+
+			if got := renderAIText(nodes, true); got != tt.markdown {
+				t.Errorf("Markdown: got %q, want %q", got, tt.markdown)
+			}
+
+			if got := renderAIText(nodes, false); got != tt.text {
+				t.Errorf("Text: got %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
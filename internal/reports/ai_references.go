@@ -0,0 +1,220 @@
+package reports
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"sort"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// Reference kinds the AI analysis cites, written as [peer:<ref>] and [section:<anchor>].
+const (
+	RefPeer    = "peer"
+	RefSection = "section"
+)
+
+// referencePattern matches a citation in the AI analysis.
+var referencePattern = regexp.MustCompile(`\[(peer|section):([A-Za-z0-9-]+)\]`)
+
+// reportSection is a report section the AI analysis may cite. The report template gives
+// each section the element id "section-<anchor>".
+type reportSection struct {
+	Anchor  string `json:"anchor"`
+	Title   string `json:"title"`
+	present func(report *Report) bool
+}
+
+// reportSections lists the citable sections in the order they appear in the report.
+var reportSections = []reportSection{
+	{Anchor: "summary", Title: "Summary", present: func(*Report) bool { return true }},
+	{Anchor: "score-bands", Title: "Peer Score Bands", present: func(r *Report) bool {
+		return peer.ScoreBandsFromInterface(r.Peers, r.StartTime, peer.ScoreBandWidth(r.Duration)).Peers > 0
+	}},
+	{Anchor: "time-slices", Title: "Time Slices", present: func(r *Report) bool { return len(r.TimeSlices) > 0 }},
+	{Anchor: "epochs", Title: "Epochs", present: func(r *Report) bool { return len(r.Epochs) > 0 }},
+	{Anchor: "host-comparison", Title: "Host Comparison", present: func(r *Report) bool { return len(r.Hosts) > 0 }},
+	{Anchor: "sampling", Title: "Detail Sampling", present: func(r *Report) bool { return r.Sampling != nil }},
+	{Anchor: "event-sampling", Title: "Event Sampling", present: func(r *Report) bool { return r.EventSampling != nil }},
+	{Anchor: "data-quality", Title: "Data Quality", present: func(r *Report) bool { return r.DataQuality != nil }},
+	{Anchor: "peer-pressure", Title: "Peer Capacity", present: func(r *Report) bool { return r.PeerPressure != nil }},
+	{Anchor: "resources", Title: "Resource Manager", present: func(r *Report) bool { return r.Resources != nil }},
+	{Anchor: "max-peers-ramp", Title: "MaxPeers Ramp", present: func(r *Report) bool { return r.MaxPeersRamp != nil }},
+	{Anchor: "shutdown", Title: "Shutdown Teardown", present: func(r *Report) bool { return r.Shutdown != nil }},
+	{Anchor: "session-survival", Title: "Session Survival", present: func(r *Report) bool { return r.SessionSurvival != nil }},
+	{Anchor: "topic-subscriptions", Title: "Gossip Topic Subscriptions", present: func(r *Report) bool { return r.Subscriptions != nil }},
+	{Anchor: "topic-health", Title: "Topic Health", present: func(r *Report) bool {
+		return r.TopicHealth != nil && len(r.TopicHealth.Topics) > 0
+	}},
+	{Anchor: "invalid-deliveries", Title: "Invalid Message Deliveries", present: func(r *Report) bool {
+		return r.InvalidDeliveries != nil && len(r.InvalidDeliveries.Anomalies) > 0
+	}},
+	{Anchor: "router-metrics", Title: "Local Gossipsub Router", present: func(r *Report) bool { return r.RouterMetrics != nil }},
+	{Anchor: "status-tracking", Title: "Peer Status Updates", present: func(r *Report) bool {
+		return r.StatusTracking != nil && (r.StatusTracking.Peers > 0 || r.StatusTracking.RetriedPeers > 0)
+	}},
+	{Anchor: "head-divergence", Title: "Head Slot Divergence", present: func(r *Report) bool {
+		return r.HeadDivergence != nil && r.HeadDivergence.Statuses > 0
+	}},
+	{Anchor: "beacon-peers", Title: "Beacon Node Peer Cross-Check", present: func(r *Report) bool { return r.BeaconPeers != nil }},
+	{Anchor: "beacon-fetches", Title: "Beacon Data Fetches", present: func(r *Report) bool { return r.BeaconFetches != nil }},
+	{Anchor: "beacon-sync", Title: "Delegated Beacon Node Health", present: func(r *Report) bool { return r.BeaconSync != nil }},
+	{Anchor: "canary", Title: "Canary Self-Test", present: func(r *Report) bool { return r.Canary != nil }},
+	{Anchor: "score-consensus", Title: "Score Consensus", present: func(r *Report) bool { return r.ScoreConsensus != nil }},
+	{Anchor: "clock-skew", Title: "Clock Skew", present: func(r *Report) bool { return r.ClockSkew != nil }},
+	{Anchor: "transports", Title: "Transports", present: func(r *Report) bool { return len(r.Peers) > 0 }},
+	{Anchor: "negotiation-failures", Title: "Negotiation Failures", present: func(r *Report) bool { return r.NegotiationFailures != nil }},
+	{Anchor: "peer-origins", Title: "Peer Origins", present: func(r *Report) bool {
+		return len(peer.OriginBreakdownFromInterface(r.Peers)) > 0
+	}},
+	{Anchor: "bootstrap-nodes", Title: "Bootstrap Nodes", present: func(r *Report) bool { return len(r.BootstrapNodes) > 0 }},
+	{Anchor: "peer-overlap", Title: "Returning Peers", present: func(r *Report) bool { return r.PeerOverlap != nil }},
+	{Anchor: "reqresp-abuse", Title: "Req/Resp Abuse", present: func(r *Report) bool {
+		return len(peer.TopReqRespAbusersFromInterface(r.Peers, 1)) > 0
+	}},
+	{Anchor: "gossip-leeches", Title: "Gossip Leeches", present: func(r *Report) bool {
+		return len(peer.GossipLeechesFromInterface(r.Peers, 1)) > 0
+	}},
+	{Anchor: "goodbye-reconnects", Title: "Reconnects After Goodbye", present: func(r *Report) bool {
+		return peer.GoodbyeReconnectsFromInterface(r.Peers, r.EndTime) != nil
+	}},
+	{Anchor: "custom-analyses", Title: "Custom Analyses", present: func(r *Report) bool { return len(r.Analyses) > 0 }},
+	{Anchor: "recommendations", Title: "Recommended Configuration", present: func(r *Report) bool { return r.Recommendations != nil }},
+	{Anchor: "peer-analysis", Title: "Peer Analysis", present: func(*Report) bool { return true }},
+	{Anchor: "metric-definitions", Title: "Metric Definitions", present: func(*Report) bool { return true }},
+}
+
+// citablePeer is a peer listed in the AI analysis data under the ref it is cited by.
+type citablePeer struct {
+	Ref            string   `json:"ref"`
+	ClientType     string   `json:"client_type"`
+	Sessions       int      `json:"sessions"`
+	Messages       int      `json:"messages"`
+	GoodbyeReasons []string `json:"goodbye_reasons"`
+}
+
+// aiReferences maps the identifiers the AI analysis cites back to the report.
+type aiReferences struct {
+	peers    map[string]string // Ref to full peer ID
+	refs     map[string]string // Full peer ID to ref
+	sections map[string]reportSection
+	order    []reportSection
+}
+
+// newAIReferences assigns each peer in the report a stable ref and collects the sections
+// present in it. A ref is the short peer ID, extended until no other peer shares it, so
+// the same peer set always yields the same refs.
+func newAIReferences(report *Report) *aiReferences {
+	refs := &aiReferences{
+		peers:    make(map[string]string, len(report.Peers)),
+		refs:     make(map[string]string, len(report.Peers)),
+		sections: make(map[string]reportSection),
+	}
+
+	peerIDs := make([]string, 0, len(report.Peers))
+	for peerID := range report.Peers {
+		peerIDs = append(peerIDs, peerID)
+	}
+
+	sort.Strings(peerIDs)
+
+	for i, peerID := range peerIDs {
+		length := constants.ShortPeerIDLength
+
+		// Sorted IDs share their longest prefixes with their neighbours
+		for _, neighbour := range []int{i - 1, i + 1} {
+			if neighbour >= 0 && neighbour < len(peerIDs) {
+				length = max(length, commonPrefix(peerID, peerIDs[neighbour])+1)
+			}
+		}
+
+		ref := peerID[:min(length, len(peerID))]
+		refs.peers[ref] = peerID
+		refs.refs[peerID] = ref
+	}
+
+	for _, section := range reportSections {
+		if section.present(report) {
+			refs.sections[section.Anchor] = section
+			refs.order = append(refs.order, section)
+		}
+	}
+
+	return refs
+}
+
+// Ref returns the ref of a peer, or the peer ID itself when the peer is not in the report.
+func (r *aiReferences) Ref(peerID string) string {
+	if ref, ok := r.refs[peerID]; ok {
+		return ref
+	}
+
+	return peerID
+}
+
+// Sections returns the citable sections in report order.
+func (r *aiReferences) Sections() []reportSection {
+	return r.order
+}
+
+// Link replaces the citations in the cleaned AI analysis with links to the peer details and
+// report sections they refer to. Citations of identifiers not in the report are kept as
+// plain text marked as unverified.
+func (r *aiReferences) Link(content template.HTML) template.HTML {
+	linked := referencePattern.ReplaceAllStringFunc(string(content), func(match string) string {
+		parts := referencePattern.FindStringSubmatch(match)
+		kind, id := parts[1], parts[2]
+
+		switch kind {
+		case RefPeer:
+			if peerID, ok := r.peers[id]; ok {
+				return `<a href="#section-peer-analysis" class="ai-ref font-mono text-blue-600 hover:underline" title="` + html.EscapeString(peerID) +
+					`" onclick="openAIReference('peer', '` + html.EscapeString(template.JSEscapeString(peerID)) + `'); return false;">` + id + `</a>`
+			}
+		case RefSection:
+			if section, ok := r.sections[id]; ok {
+				return `<a href="#section-` + id + `" class="ai-ref text-blue-600 hover:underline"` +
+					` onclick="openAIReference('section', '` + id + `'); return false;">` + section.Title + `</a>`
+			}
+		}
+
+		return `<span class="ai-ref-unverified font-mono text-gray-500" title="Not found in this report">` + id + `</span>`
+	})
+
+	return template.HTML(linked) //nolint:gosec // Citations carry matched alphanumeric identifiers and escaped peer IDs
+}
+
+// Resolve replaces the citations in the AI analysis with the full peer IDs and section titles
+// they refer to, for exports read outside the HTML report. Citations of identifiers not in
+// the report are kept as the bare identifier.
+func (r *aiReferences) Resolve(content string) string {
+	return referencePattern.ReplaceAllStringFunc(content, func(match string) string {
+		parts := referencePattern.FindStringSubmatch(match)
+		kind, id := parts[1], parts[2]
+
+		switch kind {
+		case RefPeer:
+			if peerID, ok := r.peers[id]; ok {
+				return peerID
+			}
+		case RefSection:
+			if section, ok := r.sections[id]; ok {
+				return section.Title
+			}
+		}
+
+		return id
+	})
+}
+
+// commonPrefix returns the length of the prefix a and b share.
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+
+	return n
+}
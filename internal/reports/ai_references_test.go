@@ -0,0 +1,84 @@
+package reports
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestAIReferences(t *testing.T) {
+	report := &Report{
+		Peers: map[string]interface{}{
+			"16Uiu2HAmAAAAxyz1": map[string]interface{}{},
+			"16Uiu2HAmAAAAxyz2": map[string]interface{}{},
+			"16Uiu2HAmBBBBqrs":  map[string]interface{}{},
+		},
+		DataQuality: &peer.DataQualityStats{},
+	}
+
+	refs := newAIReferences(report)
+
+	if got := refs.Ref("16Uiu2HAmAAAAxyz1"); got != "16Uiu2HAmAAAAxyz1" {
+		t.Errorf("expected clashing short IDs to be extended, got %q", got)
+	}
+
+	if got := refs.Ref("16Uiu2HAmBBBBqrs"); got != "16Uiu2HAmBBB" {
+		t.Errorf("expected the short peer ID as ref, got %q", got)
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "peer",
+			content:  "Dropped by [peer:16Uiu2HAmBBB] twice.",
+			contains: []string{`openAIReference('peer', '16Uiu2HAmBBBBqrs')`, `>16Uiu2HAmBBB</a> twice.`},
+			excludes: []string{"[peer:"},
+		},
+		{
+			name:     "section present in the report",
+			content:  "See [section:data-quality].",
+			contains: []string{`href="#section-data-quality"`, `>Data Quality</a>.`},
+		},
+		{
+			name:     "section missing from the report",
+			content:  "See [section:beacon-peers].",
+			contains: []string{`ai-ref-unverified`, `>beacon-peers</span>`},
+			excludes: []string{"openAIReference"},
+		},
+		{
+			name:     "unknown peer",
+			content:  "[peer:16Uiu2HAmZZZZ] misbehaved.",
+			contains: []string{`ai-ref-unverified`},
+			excludes: []string{"openAIReference"},
+		},
+		{
+			name:     "plain brackets",
+			content:  "Scores [min, max] stayed flat.",
+			contains: []string{"Scores [min, max] stayed flat."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(refs.Link(template.HTML(tt.content))) //nolint:gosec // test input
+
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in %q", want, got)
+				}
+			}
+
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("unexpected %q in %q", unwanted, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package reports
+
+import (
+	"context"
+	"html/template"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/analyzers"
+)
+
+// analysisView is a custom analyzer's section as the HTML report shows it.
+type analysisView struct {
+	Name       string
+	Source     string
+	Error      string
+	HTML       template.HTML // Inserted as is, analyzers are code the operator chose to run
+	HasData    bool
+	DurationMs float64
+}
+
+// SetAnalyzers sets the custom analyzers run on the report, nil runs none.
+func (g *DefaultGenerator) SetAnalyzers(runner *analyzers.Runner) {
+	g.analyzers = runner
+}
+
+// RunAnalyzers runs the custom analyzers on the report as the JSON report will hold it, with
+// secrets redacted, and adds their sections to the report. Each failed analyzer is counted in
+// the error budget, the report is written either way.
+func (g *DefaultGenerator) RunAnalyzers(ctx context.Context, report *Report) error {
+	if g.analyzers == nil || g.analyzers.Len() == 0 {
+		return nil
+	}
+
+	reportJSON, err := g.marshalReport(report)
+	if err != nil {
+		return err
+	}
+
+	report.Analyses = g.analyzers.Run(ctx, reportJSON)
+
+	for _, section := range report.Analyses {
+		if section.Error != "" {
+			g.errors.Record(ErrorCategoryAnalyzer)
+		}
+	}
+
+	return nil
+}
+
+// analysisViews converts the custom analyzers' sections for the HTML report.
+func analysisViews(sections []analyzers.Section) []analysisView {
+	if len(sections) == 0 {
+		return nil
+	}
+
+	views := make([]analysisView, 0, len(sections))
+
+	for _, section := range sections {
+		views = append(views, analysisView{
+			Name:       section.Name,
+			Source:     section.Source,
+			Error:      section.Error,
+			HTML:       template.HTML(section.HTML), //nolint:gosec // Fragments come from analyzers the operator configured
+			HasData:    len(section.Data) > 0,
+			DurationMs: section.DurationMs,
+		})
+	}
+
+	return views
+}
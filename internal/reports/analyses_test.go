@@ -0,0 +1,107 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/analyzers"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+// stubAnalyzer returns a fixed output or error.
+type stubAnalyzer struct {
+	name   string
+	output *analyzers.Output
+	err    error
+	seen   []byte
+}
+
+func (s *stubAnalyzer) Name() string { return s.name }
+
+func (s *stubAnalyzer) Analyze(_ context.Context, report []byte) (*analyzers.Output, error) {
+	s.seen = report
+
+	return s.output, s.err
+}
+
+func TestRunAnalyzers(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	generator, err := NewGenerator(logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating generator, got %v", err)
+	}
+
+	generator.SetRedactor(redact.New("sk-or-v1-0123456789abcdef"))
+
+	relays := &stubAnalyzer{name: "mev-relays", output: &analyzers.Output{
+		Data: json.RawMessage(`{"relays":2}`),
+		HTML: `<p class="relays">2 relay peers</p>`,
+	}}
+	failing := &stubAnalyzer{name: "client-versions", err: errors.New("version list unavailable")}
+
+	runner := analyzers.NewRunner(logger)
+	for _, analyzer := range []analyzers.Analyzer{relays, failing} {
+		if err := runner.Add(analyzer, analyzers.SourceBuiltin); err != nil {
+			t.Fatalf("Expected no error adding analyzer, got %v", err)
+		}
+	}
+
+	generator.SetAnalyzers(runner)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &Report{
+		Config:           map[string]interface{}{"openrouter_api_key": "sk-or-v1-0123456789abcdef"},
+		ValidationMode:   "delegated",
+		ValidationConfig: map[string]interface{}{"HermesVersion": "test"},
+		StartTime:        start,
+		EndTime:          start.Add(time.Hour),
+		Duration:         time.Hour,
+		Peers:            map[string]interface{}{},
+	}
+
+	if err := generator.RunAnalyzers(context.Background(), report); err != nil {
+		t.Fatalf("Expected no error running analyzers, got %v", err)
+	}
+
+	if strings.Contains(string(relays.seen), "sk-or-v1") || !strings.Contains(string(relays.seen), `"validation_mode": "delegated"`) {
+		t.Errorf("Expected analyzers to see the redacted JSON report, got %s", relays.seen)
+	}
+
+	if len(report.Analyses) != 2 || string(report.Analyses[0].Data) != `{"relays":2}` || report.Analyses[1].Error != "version list unavailable" {
+		t.Fatalf("Unexpected sections %+v", report.Analyses)
+	}
+
+	if counts := generator.errors.Counts(); counts[ErrorCategoryAnalyzer] != 1 {
+		t.Errorf("Expected the failed analyzer in the error budget, got %v", counts)
+	}
+
+	templateData, err := NewDefaultDataProcessor(logger).FormatForTemplate(report)
+	if err != nil {
+		t.Fatalf("Expected no error formatting for template, got %v", err)
+	}
+
+	html, err := generator.templateManager.RenderReport(templateData)
+	if err != nil {
+		t.Fatalf("Expected no error rendering report, got %v", err)
+	}
+
+	expected := []string{
+		`id="section-custom-analyses"`,
+		`<p class="relays">2 relay peers</p>`,
+		"Analyzer failed: version list unavailable",
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected rendered report to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package reports
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// dataFileWriter streams the report data file as a JavaScript assignment, encoding each
+// top-level field and each peer separately so the whole dataset is never held encoded.
+type dataFileWriter struct {
+	ctx      context.Context
+	w        *bufio.Writer
+	redact   func(string) string
+	pretty   bool
+	budget   int // Bytes of encoded peers held in memory at once
+	written  int // Bytes written so far
+	progress *progressLogger
+	err      error
+}
+
+// writeString writes s unless an earlier write failed.
+func (dw *dataFileWriter) writeString(s string) {
+	if dw.err == nil {
+		var n int
+
+		n, dw.err = dw.w.WriteString(s)
+		dw.written += n
+	}
+}
+
+// encode marshals a value nested depth levels deep into the data file.
+func (dw *dataFileWriter) encode(value interface{}, depth int) ([]byte, error) {
+	if !dw.pretty {
+		return json.Marshal(value)
+	}
+
+	return json.MarshalIndent(value, strings.Repeat("  ", depth), "  ")
+}
+
+// separator returns the text between a container's opening bracket or a previous item and
+// the next item, depth levels deep.
+func (dw *dataFileWriter) separator(first bool, depth int) string {
+	sep := ","
+	if first {
+		sep = ""
+	}
+
+	if !dw.pretty {
+		return sep
+	}
+
+	return sep + "\n" + strings.Repeat("  ", depth)
+}
+
+// closing returns the text before a container's closing bracket, depth levels deep.
+func (dw *dataFileWriter) closing(depth int) string {
+	if !dw.pretty {
+		return ""
+	}
+
+	return "\n" + strings.Repeat("  ", depth)
+}
+
+// writeObject writes the fields as a JSON object in key order. The peers field is
+// streamed one peer at a time when it holds processed peer maps.
+func (dw *dataFileWriter) writeObject(fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	dw.writeString("{")
+
+	for i, key := range keys {
+		name, _ := json.Marshal(key)
+
+		dw.writeString(dw.separator(i == 0, 1))
+		dw.writeString(string(name) + ":")
+
+		if dw.pretty {
+			dw.writeString(" ")
+		}
+
+		if peers, ok := fields[key].([]map[string]interface{}); ok && key == "peers" {
+			dw.writePeers(peers)
+
+			continue
+		}
+
+		data, err := dw.encode(fields[key], 1)
+		if err != nil {
+			dw.err = fmt.Errorf("failed to marshal %s: %w", key, err)
+
+			return
+		}
+
+		dw.writeString(dw.redact(string(data)))
+	}
+
+	dw.writeString(dw.closing(0) + "}")
+}
+
+// writePeers streams the peers as a JSON array. Peers are encoded by a pool of workers in
+// batches sized from the encoded size seen so far, so the bytes held in memory stay near
+// the budget.
+func (dw *dataFileWriter) writePeers(peers []map[string]interface{}) {
+	workers := runtime.GOMAXPROCS(0)
+	batchSize := workers
+
+	dw.writeString("[")
+
+	for start := 0; start < len(peers) && dw.err == nil; {
+		if err := checkCancelled(dw.ctx, "data file"); err != nil {
+			dw.err = err
+
+			return
+		}
+
+		end := min(start+batchSize, len(peers))
+
+		chunks, err := dw.encodeBatch(peers[start:end], workers)
+		if err != nil {
+			dw.err = err
+
+			return
+		}
+
+		encoded := 0
+
+		for i, chunk := range chunks {
+			dw.writeString(dw.separator(start+i == 0, 2))
+			dw.writeString(dw.redact(string(chunk)))
+			encoded += len(chunk)
+		}
+
+		// Size the next batch to the budget from the average peer seen in this one
+		batchSize = max(dw.budget/max(encoded/len(chunks), 1), 1)
+		start = end
+
+		dw.progress.update(start, len(peers), dw.written)
+	}
+
+	if len(peers) > 0 {
+		dw.writeString(dw.closing(1))
+	}
+
+	dw.writeString("]")
+}
+
+// encodeBatch encodes a batch of peers in parallel, keeping their order.
+func (dw *dataFileWriter) encodeBatch(peers []map[string]interface{}, workers int) ([][]byte, error) {
+	chunks := make([][]byte, len(peers))
+	errs := make([]error, len(peers))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < min(workers, len(peers)); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				chunks[i], errs[i] = dw.encode(peers[i], 2)
+			}
+		}()
+	}
+
+	for i := range peers {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal peer %d: %w", i, err)
+		}
+	}
+
+	return chunks, nil
+}
+
+// writeDataFile streams the report data to filename as window.reportData, stopping with an
+// error when ctx is cancelled.
+func (g *DefaultGenerator) writeDataFile(ctx context.Context, filename string, fields map[string]interface{}) error {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, constants.DefaultFilePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to create data file: %w", err)
+	}
+	defer file.Close()
+
+	dw := &dataFileWriter{
+		ctx:      ctx,
+		w:        bufio.NewWriter(file),
+		redact:   g.redactor.String,
+		pretty:   g.prettyDataFile,
+		budget:   g.dataFileBudget,
+		progress: newProgressLogger(g.logger, filename),
+	}
+
+	dw.writeString("window.reportData = ")
+	dw.writeObject(fields)
+	dw.writeString(";")
+
+	if dw.err != nil {
+		return fmt.Errorf("failed to write data file: %w", dw.err)
+	}
+
+	if err := dw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"filename":      filename,
+		"bytes_written": dw.written,
+	}).Info("Report data file written")
+
+	return file.Close()
+}
@@ -0,0 +1,95 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+func TestWriteDataFile(t *testing.T) {
+	peers := make([]map[string]interface{}, 0, 25)
+	for i := 0; i < 25; i++ {
+		peers = append(peers, map[string]interface{}{"peer_id": strings.Repeat("p", i+1), "score": float64(i)})
+	}
+
+	fields := map[string]interface{}{
+		"metadata": map[string]interface{}{"format_version": "1.0", "note": "token s3cr3t-value"},
+		"peers":    peers,
+		"summary":  map[string]interface{}{"TotalConnections": 25},
+	}
+
+	tests := []struct {
+		name   string
+		pretty bool
+		budget int
+		peers  []map[string]interface{}
+	}{
+		{name: "compact", budget: 1 << 20, peers: peers},
+		{name: "pretty", pretty: true, budget: 1 << 20, peers: peers},
+		{name: "budget smaller than a peer", budget: 1, peers: peers},
+		{name: "no peers", pretty: true, budget: 1 << 20, peers: []map[string]interface{}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &DefaultGenerator{
+				logger:         logrus.New(),
+				redactor:       redact.New("s3cr3t-value"),
+				prettyDataFile: tt.pretty,
+				dataFileBudget: tt.budget,
+			}
+
+			data := make(map[string]interface{}, len(fields))
+			for key, value := range fields {
+				data[key] = value
+			}
+
+			data["peers"] = tt.peers
+
+			filename := filepath.Join(t.TempDir(), "data.js")
+			if err := g.writeDataFile(context.Background(), filename, data); err != nil {
+				t.Fatalf("writeDataFile() error = %v", err)
+			}
+
+			content, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("failed to read data file: %v", err)
+			}
+
+			js := string(content)
+			if !strings.HasPrefix(js, "window.reportData = ") || !strings.HasSuffix(js, ";") {
+				t.Fatalf("unexpected data file wrapper: %.40q", js)
+			}
+
+			if strings.Contains(js, "s3cr3t-value") {
+				t.Errorf("expected secrets to be redacted")
+			}
+
+			if strings.Contains(js, "\n") != tt.pretty {
+				t.Errorf("expected pretty = %v output", tt.pretty)
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(js, "window.reportData = "), ";")), &got); err != nil {
+				t.Fatalf("data file is not valid JSON: %v", err)
+			}
+
+			want := make([]interface{}, 0, len(tt.peers))
+			for _, p := range tt.peers {
+				want = append(want, map[string]interface{}{"peer_id": p["peer_id"], "score": p["score"]})
+			}
+
+			if !reflect.DeepEqual(got["peers"], want) {
+				t.Errorf("peers = %v, want %v", got["peers"], want)
+			}
+		})
+	}
+}
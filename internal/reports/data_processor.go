@@ -1,6 +1,7 @@
 package reports
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"sort"
@@ -10,18 +11,22 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/clientmeta"
+	"github.com/ethpandaops/hermes-peer-score/internal/glossary"
 	"github.com/ethpandaops/hermes-peer-score/internal/peer"
 )
 
 // DefaultDataProcessor implements the DataProcessor interface.
 type DefaultDataProcessor struct {
 	logger logrus.FieldLogger
+	clock  func() time.Time
 }
 
 // NewDefaultDataProcessor creates a new data processor.
 func NewDefaultDataProcessor(logger logrus.FieldLogger) *DefaultDataProcessor {
 	return &DefaultDataProcessor{
 		logger: logger.WithField("component", "data_processor"),
+		clock:  time.Now,
 	}
 }
 
@@ -51,7 +56,7 @@ func (dp *DefaultDataProcessor) ProcessPeerDataWithEventCounts(peers map[string]
 		"peers": processedPeers,
 		"metadata": map[string]interface{}{
 			"total_peers":    len(processedPeers),
-			"processed_at":   time.Now(),
+			"processed_at":   dp.clock(),
 			"format_version": "1.0",
 		},
 	}, nil
@@ -67,6 +72,8 @@ func (dp *DefaultDataProcessor) CalculateSummaryStats(report *Report) (interface
 		"SuccessfulHandshakes": report.SuccessfulHandshakes,
 		"FailedHandshakes":     report.FailedHandshakes,
 		"UniquePeers":          len(report.Peers),
+		"ReconciledHandshakes": report.ReconciledHandshakes,
+		"DataQuality":          report.DataQuality,
 	}
 
 	// Calculate goodbye events summary.
@@ -79,6 +86,14 @@ func (dp *DefaultDataProcessor) CalculateSummaryStats(report *Report) (interface
 
 	for peerID, peerData := range report.Peers {
 		peerSummary := dp.createPeerSummary(peerID, peerData)
+
+		// Use the correct event count from PeerEventCounts
+		totalEventCount := 0
+		for _, count := range report.PeerEventCounts[peerID] {
+			totalEventCount += count
+		}
+
+		peerSummary["event_count"] = totalEventCount
 		peerSummaries = append(peerSummaries, peerSummary)
 
 		// Count client types
@@ -97,10 +112,130 @@ func (dp *DefaultDataProcessor) CalculateSummaryStats(report *Report) (interface
 
 	summary["client_distribution"] = clientDistribution
 	summary["peer_summaries"] = peerSummaries
+	summary["decode_error_offenders"] = dp.decodeErrorOffenders(report.Peers)
+	summary["reqresp_abusers"] = peer.TopReqRespAbusersFromInterface(report.Peers, constants.ReqRespAbuserLimit)
+	summary["reqresp_abuse_by_client"] = peer.ReqRespAbuseByClientFromInterface(report.Peers)
+	summary["gossip_leeches"] = peer.GossipLeechesFromInterface(report.Peers, constants.GossipLeechLimit)
+	summary["gossip_leeches_by_client"] = peer.GossipLeechesByClientFromInterface(report.Peers)
+	summary["unknown_clients"] = peer.DiagnoseUnknownClientsFromInterface(report.Peers, constants.UnknownAgentStringLimit)
+	summary["event_bursts"] = report.EventTimeline.Bursts(constants.EventBurstLimit)
+	summary["transports"] = peer.TransportBreakdownFromInterface(report.Peers)
+	summary["peer_origins"] = peer.OriginBreakdownFromInterface(report.Peers)
+	summary["goodbye_reconnects"] = peer.GoodbyeReconnectsFromInterface(report.Peers, report.EndTime)
+
+	headline := CalculateHeadline(report)
+	summary["headline"] = headline
+	summary["score_bands"] = headline.ScoreBands
+	summary["score_band_chart"] = newScoreBandChart(headline.ScoreBands)
+	summary["gossip_threshold"] = constants.GossipScoreThreshold
+	summary["publish_threshold"] = constants.PublishScoreThreshold
+	summary["graylist_threshold"] = constants.GraylistScoreThreshold
 
 	return summary, nil
 }
 
+// RunHeadline holds the numbers a run's report artifacts lead with. The HTML report, the lite
+// report and the markdown summary all take them from CalculateHeadline, so they never disagree.
+type RunHeadline struct {
+	UniquePeers          int                          `json:"unique_peers"`
+	TotalConnections     int                          `json:"total_connections"`
+	SuccessfulHandshakes int                          `json:"successful_handshakes"`
+	FailedHandshakes     int                          `json:"failed_handshakes"`
+	HandshakeSuccessRate float64                      `json:"handshake_success_rate"`
+	Sessions             int                          `json:"sessions"`
+	Disconnects          int                          `json:"disconnects"`
+	GoodbyeEvents        int                          `json:"goodbye_events"`
+	Clients              []peer.ClientSummary         `json:"clients"`            // Largest client first
+	DisconnectReasons    []peer.DisconnectReasonCount `json:"disconnect_reasons"` // Most frequent goodbye codes and reasons, and our own terminations
+	ScoreBands           *peer.ScoreBands             `json:"score_bands"`
+	WorstScored          []peer.ScoredPeer            `json:"worst_scored"` // Largest score area below zero first
+}
+
+// headlineDefinitions define the RunHeadline metrics not taken from ConnectionStats.
+var headlineDefinitions = []glossary.Definition{
+	{
+		Name:    "Unique Peers",
+		Field:   "unique_peers",
+		Formula: "Peers seen during the run",
+		Caveats: []string{"Every peer of any origin over the whole run, unlike the connection counts, which cover general peers in the measurement window"},
+	},
+	{
+		Name:    "Handshake Success Rate",
+		Field:   "handshake_success_rate",
+		Formula: "Successful handshakes / (successful + failed handshakes)",
+		Caveats: []string{"A fraction between 0 and 1, shown as a percentage", "Inherits the caveats of Failed Handshakes"},
+	},
+	{
+		Name:    "Sessions",
+		Field:   "sessions",
+		Formula: "Sessions summed over the client summaries",
+		Caveats: []string{"Every peer over the whole run, so it can exceed Total Connections"},
+	},
+	{
+		Name:    "Disconnects",
+		Field:   "disconnects",
+		Formula: "Disconnects summed over the client summaries",
+	},
+	{
+		Name:    "Goodbye Events",
+		Field:   "goodbye_events",
+		Formula: "Goodbye events summed over the client summaries",
+	},
+}
+
+// CalculateHeadline computes the headline numbers of a run from its report.
+func CalculateHeadline(report *Report) *RunHeadline {
+	headline := &RunHeadline{
+		UniquePeers:          len(report.Peers),
+		TotalConnections:     report.TotalConnections,
+		SuccessfulHandshakes: report.SuccessfulHandshakes,
+		FailedHandshakes:     report.FailedHandshakes,
+		Clients:              peer.SummarizeClientsFromInterface(report.Peers),
+		DisconnectReasons:    peer.TopDisconnectReasonsFromInterface(report.Peers, constants.LiteReportReasonLimit),
+		ScoreBands:           peer.ScoreBandsFromInterface(report.Peers, report.StartTime, peer.ScoreBandWidth(report.Duration)),
+		WorstScored:          peer.WorstScoredPeersFromInterface(report.Peers, constants.WorstScoredPeerLimit),
+	}
+
+	if handshakes := report.SuccessfulHandshakes + report.FailedHandshakes; handshakes > 0 {
+		headline.HandshakeSuccessRate = float64(report.SuccessfulHandshakes) / float64(handshakes)
+	}
+
+	for _, client := range headline.Clients {
+		headline.Sessions += client.Sessions
+		headline.Disconnects += client.Disconnects
+		headline.GoodbyeEvents += client.GoodbyeEvents
+	}
+
+	return headline
+}
+
+// attachEventBuckets adds each peer's bucketed event counts to its processed record.
+func attachEventBuckets(peers []map[string]interface{}, timeline *peer.EventTimeline) {
+	if timeline == nil {
+		return
+	}
+
+	for _, p := range peers {
+		peerID, _ := p["peer_id"].(string)
+		if buckets, exists := timeline.Peers[peerID]; exists {
+			p["event_buckets"] = buckets
+		}
+	}
+}
+
+// decodeErrorOffenders returns the peers with the most gossip decode errors.
+func (dp *DefaultDataProcessor) decodeErrorOffenders(peers map[string]interface{}) []peer.DecodeErrorOffender {
+	stats := make(map[string]*peer.Stats, len(peers))
+
+	for peerID, peerData := range peers {
+		if peerStats, ok := peerData.(*peer.Stats); ok {
+			stats[peerID] = peerStats
+		}
+	}
+
+	return peer.TopDecodeErrorOffenders(stats, constants.DecodeErrorOffenderLimit)
+}
+
 // FormatForTemplate formats the report data for template rendering.
 func (dp *DefaultDataProcessor) FormatForTemplate(report *Report) (interface{}, error) {
 	summaryStats, err := dp.CalculateSummaryStats(report)
@@ -113,19 +248,73 @@ func (dp *DefaultDataProcessor) FormatForTemplate(report *Report) (interface{},
 		return nil, fmt.Errorf("invalid summary stats format")
 	}
 
+	clients := dp.clients()
+
 	templateData := map[string]interface{}{
-		"GeneratedAt":      time.Now(),
-		"Summary":          summary,
-		"ValidationMode":   report.ValidationMode,
-		"ValidationConfig": report.ValidationConfig,
-		"DataFile":         "",                // Will be set by generator
-		"AIAnalysis":       "",                // Will be set by generator if available
-		"AIAnalysisHTML":   template.HTML(""), // Safe HTML version
+		"GeneratedAt":         dp.clock(),
+		"Summary":             summary,
+		"ValidationMode":      report.ValidationMode,
+		"ValidationConfig":    report.ValidationConfig,
+		"AgentVersion":        report.AgentVersion,
+		"MeshDegree":          report.MeshDegree,
+		"Phases":              report.Phases,
+		"Hosts":               report.Hosts,
+		"Reachability":        report.Reachability,
+		"Subscriptions":       report.Subscriptions,
+		"BeaconPeers":         report.BeaconPeers,
+		"BeaconFetches":       report.BeaconFetches,
+		"BeaconSync":          report.BeaconSync,
+		"Canary":              report.Canary,
+		"ScoreConsensus":      report.ScoreConsensus,
+		"ClockSkew":           report.ClockSkew,
+		"Sampling":            report.Sampling,
+		"EventSampling":       report.EventSampling,
+		"MetricDefinitions":   MetricDefinitions(),
+		"PeerPressure":        report.PeerPressure,
+		"Resources":           report.Resources,
+		"MaxPeersRamp":        report.MaxPeersRamp,
+		"Shutdown":            report.Shutdown,
+		"SessionSurvival":     report.SessionSurvival,
+		"TopicHealth":         report.TopicHealth,
+		"InvalidDeliveries":   report.InvalidDeliveries,
+		"RouterMetrics":       report.RouterMetrics,
+		"StatusTracking":      report.StatusTracking,
+		"HeadDivergence":      report.HeadDivergence,
+		"Gaps":                report.Gaps,
+		"Starvation":          report.Starvation,
+		"TimeSlices":          report.TimeSlices,
+		"Epochs":              report.Epochs,
+		"TopicWhitelist":      report.TopicWhitelist,
+		"PeerOverlap":         report.PeerOverlap,
+		"NegotiationFailures": report.NegotiationFailures,
+		"BootstrapNodes":      report.BootstrapNodes,
+		"PrunePolicy":         report.PrunePolicy,
+		"Analyses":            analysisViews(report.Analyses),
+		"Recommendations":     report.Recommendations,
+		"Clients":             clients.Clients,
+		"ClientLogoFallback":  clients.LogoFallback(),
+		"DataFile":            "",                // Will be set by generator
+		"SwimlanesFile":       "",                // Will be set by generator when the swimlane view is written
+		"AIAnalysis":          "",                // Will be set by generator if available
+		"AIAnalysisHTML":      template.HTML(""), // Safe HTML version
 	}
 
 	return templateData, nil
 }
 
+// clients returns the bundled client metadata. Reports still render without it, just
+// without client logos.
+func (dp *DefaultDataProcessor) clients() *clientmeta.Metadata {
+	metadata, err := clientmeta.Load()
+	if err != nil {
+		dp.logger.WithError(err).Warn("Failed to load bundled client metadata")
+
+		return &clientmeta.Metadata{Clients: map[string]clientmeta.Client{}}
+	}
+
+	return metadata
+}
+
 // processSinglePeerWithEventCounts processes a single peer's data with event counts.
 func (dp *DefaultDataProcessor) processSinglePeerWithEventCounts(peerID string, peerData interface{}, eventCounts map[string]map[string]int) map[string]interface{} {
 	processed := map[string]interface{}{
@@ -177,10 +366,41 @@ func (dp *DefaultDataProcessor) extractFromPeerStatsWithEventCounts(peerStats *p
 	target["first_seen_at"] = peerStats.FirstSeenAt
 	target["last_seen_at"] = peerStats.LastSeenAt
 
+	if peerStats.Origin != "" {
+		target["origin"] = peerStats.Origin
+	}
+
+	if peerStats.Consensus != nil {
+		target["consensus"] = peerStats.Consensus
+	}
+
+	// Decode errors are tracked per peer rather than per session
+	decodeErrorCount := 0
+	if peerStats.DecodeErrors != nil {
+		decodeErrorCount = peerStats.DecodeErrors.Total
+		target["decode_errors"] = peerStats.DecodeErrors
+	}
+
+	target["decode_error_count"] = decodeErrorCount
+
+	// Req/resp abuse is tracked per peer like decode errors
+	reqRespAbuseCount := 0
+	if peerStats.ReqRespAbuse != nil {
+		reqRespAbuseCount = peerStats.ReqRespAbuse.Total
+		target["reqresp_abuse"] = peerStats.ReqRespAbuse
+	}
+
+	target["reqresp_abuse_count"] = reqRespAbuseCount
+
+	if peerStats.Sample != nil {
+		target["sample"] = peerStats.Sample
+	}
+
 	// Process sessions
 	sessionCount := len(peerStats.ConnectionSessions)
 	target["session_count"] = sessionCount
 	target["connection_sessions"] = peerStats.ConnectionSessions
+	target["session_attribution"] = peer.AttributeSessions(peerStats.ConnectionSessions)
 
 	// Calculate session statistics
 	goodbyeCount := 0
@@ -193,7 +413,8 @@ func (dp *DefaultDataProcessor) extractFromPeerStatsWithEventCounts(peerStats *p
 
 	for _, session := range peerStats.ConnectionSessions {
 		goodbyeCount += len(session.GoodbyeEvents)
-		meshCount += len(session.MeshEvents)
+		grafts, prunes := session.MeshCounts()
+		meshCount += grafts + prunes
 
 		if len(session.PeerScores) > 0 {
 			for _, score := range session.PeerScores {
@@ -229,6 +450,9 @@ func (dp *DefaultDataProcessor) extractFromPeerStatsWithEventCounts(peerStats *p
 	target["min_peer_score"] = minScore
 	target["max_peer_score"] = maxScore
 	target["last_session_status"] = lastSessionStatus
+
+	dp.setScoreTotals(peerStats, target)
+	dp.setIdentifyAttempts(peerStats, target)
 }
 
 // extractFromMap extracts data from a map-based peer structure.
@@ -249,6 +473,10 @@ func (dp *DefaultDataProcessor) extractFromMapWithEventCounts(source, target map
 		target["client_agent"] = clientAgent
 	}
 
+	if consensus, ok := source["consensus"].(map[string]interface{}); ok {
+		target["consensus"] = consensus
+	}
+
 	// Process sessions
 	sessionCount := 0
 	if sessions, ok := source["connection_sessions"].([]interface{}); ok {
@@ -277,6 +505,14 @@ func (dp *DefaultDataProcessor) extractFromMapWithEventCounts(source, target map
 		target["has_scores"] = false
 	}
 
+	if _, ok := target["decode_error_count"]; !ok {
+		target["decode_error_count"] = 0
+	}
+
+	if _, ok := target["reqresp_abuse_count"]; !ok {
+		target["reqresp_abuse_count"] = 0
+	}
+
 	if _, ok := target["last_session_status"]; !ok {
 		target["last_session_status"] = constants.Unknown
 	}
@@ -286,6 +522,38 @@ func (dp *DefaultDataProcessor) extractFromMapWithEventCounts(source, target map
 	}
 }
 
+// sessionMeshCount counts the mesh events of generic session data, as
+// peer.ConnectionSession.MeshCounts does: the recorded events of a sampled type are replaced
+// by the events seen.
+func sessionMeshCount(session map[string]interface{}) int {
+	counts := make(map[string]int)
+
+	if meshEvents, ok := session["mesh_events"].([]interface{}); ok {
+		for _, eventData := range meshEvents {
+			if event, ok := eventData.(map[string]interface{}); ok {
+				eventType, _ := event["type"].(string)
+				counts[eventType]++
+			}
+		}
+	}
+
+	samples, _ := session["event_samples"].(map[string]interface{})
+
+	total := 0
+
+	for _, eventType := range []string{peer.MeshGraft, peer.MeshPrune} {
+		if sample, ok := samples[eventType].(map[string]interface{}); ok {
+			if seen, ok := sample["seen"].(float64); ok {
+				counts[eventType] = int(seen)
+			}
+		}
+
+		total += counts[eventType]
+	}
+
+	return total
+}
+
 // processSessionData extracts information from session data.
 func (dp *DefaultDataProcessor) processSessionData(sessions []interface{}, target map[string]interface{}) {
 	goodbyeCount := 0
@@ -302,10 +570,7 @@ func (dp *DefaultDataProcessor) processSessionData(sessions []interface{}, targe
 				goodbyeCount += len(goodbyes)
 			}
 
-			// Count mesh events
-			if meshEvents, ok := session["mesh_events"].([]interface{}); ok {
-				meshCount += len(meshEvents)
-			}
+			meshCount += sessionMeshCount(session)
 
 			// Process peer scores
 			if scores, ok := session["peer_scores"].([]interface{}); ok && len(scores) > 0 {
@@ -354,28 +619,84 @@ func (dp *DefaultDataProcessor) processSessionData(sessions []interface{}, targe
 	target["max_peer_score"] = maxScore
 	target["last_session_status"] = lastSessionStatus
 	target["last_session_time"] = lastSessionTime
+
+	// Score summaries, identify attempts and the session attribution are read through the typed
+	// sessions, the maps mirror their JSON layout
+	if data, err := json.Marshal(sessions); err == nil {
+		var typed []peer.ConnectionSession
+		if err := json.Unmarshal(data, &typed); err == nil {
+			stats := &peer.Stats{ConnectionSessions: typed}
+			dp.setScoreTotals(stats, target)
+			dp.setIdentifyAttempts(stats, target)
+			target["session_attribution"] = peer.AttributeSessions(typed)
+		}
+	}
+}
+
+// setScoreTotals adds a peer's score totals across its session score summaries, used to sort
+// peers by how badly they scored over time rather than by a single snapshot.
+func (dp *DefaultDataProcessor) setScoreTotals(peerStats *peer.Stats, target map[string]interface{}) {
+	mean, areaBelowZero, secondsBelowPublish, ok := peerStats.ScoreTotals()
+	if !ok {
+		return
+	}
+
+	target["time_weighted_score"] = mean
+	target["score_area_below_zero"] = areaBelowZero
+	target["seconds_below_publish"] = secondsBelowPublish
+}
+
+// setIdentifyAttempts adds how many of our status requests it took to identify a peer and how
+// many failed, which a plain handshake count hides.
+func (dp *DefaultDataProcessor) setIdentifyAttempts(peerStats *peer.Stats, target map[string]interface{}) {
+	attempts := peerStats.IdentifyAttempts()
+
+	target["identify_attempts"] = attempts.Attempts
+	target["failed_identify_attempts"] = attempts.Failed
+	target["attempts_to_identify"] = attempts.AttemptsToIdentify
 }
 
 // createPeerSummary creates a summary for a single peer.
 func (dp *DefaultDataProcessor) createPeerSummary(peerID string, peerData interface{}) map[string]interface{} {
 	summary := map[string]interface{}{
-		"peer_id":             peerID,
-		"short_peer_id":       dp.formatShortPeerID(peerID),
-		"client_type":         constants.Unknown,
-		"client_agent":        "",
-		"session_count":       0,
-		"event_count":         0,
-		"goodbye_count":       0,
-		"mesh_count":          0,
-		"min_peer_score":      0.0,
-		"max_peer_score":      0.0,
-		"has_scores":          false,
-		"last_session_status": constants.Unknown,
-		"last_session_time":   "",
-	}
-
-	if peer, ok := peerData.(map[string]interface{}); ok {
-		dp.extractFromMap(peer, summary)
+		"peer_id":                  peerID,
+		"short_peer_id":            dp.formatShortPeerID(peerID),
+		"client_type":              constants.Unknown,
+		"client_agent":             "",
+		"session_count":            0,
+		"event_count":              0,
+		"goodbye_count":            0,
+		"mesh_count":               0,
+		"min_peer_score":           0.0,
+		"max_peer_score":           0.0,
+		"has_scores":               false,
+		"time_weighted_score":      0.0,
+		"score_area_below_zero":    0.0,
+		"seconds_below_publish":    0.0,
+		"identify_attempts":        0,
+		"failed_identify_attempts": 0,
+		"attempts_to_identify":     0,
+		"decode_error_count":       0,
+		"reqresp_abuse_count":      0,
+		"last_session_status":      constants.Unknown,
+		"last_session_time":        "",
+	}
+
+	switch peerObj := peerData.(type) {
+	case map[string]interface{}:
+		dp.extractFromMap(peerObj, summary)
+	case *peer.Stats:
+		dp.extractFromPeerStatsWithEventCounts(peerObj, summary, 0)
+
+		// Summaries are rendered inline in the HTML, keep them lightweight
+		delete(summary, "connection_sessions")
+		delete(summary, "session_attribution")
+
+		if sessionCount := len(peerObj.ConnectionSessions); sessionCount > 0 {
+			if connectedAt := peerObj.ConnectionSessions[sessionCount-1].ConnectedAt; connectedAt != nil {
+				summary["last_session_time"] = connectedAt.Format(time.RFC3339)
+			}
+		}
 	}
 
 	return summary
@@ -1,7 +1,9 @@
 package reports
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,7 +13,13 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/aiqueue"
+	"github.com/ethpandaops/hermes-peer-score/internal/analyzers"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
 	"github.com/ethpandaops/hermes-peer-score/internal/reports/templates"
+	"github.com/ethpandaops/hermes-peer-score/internal/signing"
 )
 
 // DefaultGenerator implements the Generator interface.
@@ -20,7 +28,40 @@ type DefaultGenerator struct {
 	fileManager     FileManager
 	dataProcessor   DataProcessor
 	aiAnalyzer      AIAnalyzer
+	redactor        *redact.Redactor
 	logger          logrus.FieldLogger
+
+	// Split report settings
+	splitReport bool
+	shardSize   int
+
+	// Data file settings
+	prettyDataFile bool
+	dataFileBudget int // Bytes of encoded peers held in memory while writing the data file
+
+	swimlanePeers int // Peers drawn in the swimlane view, 0 disables it
+
+	clock func() time.Time // Generation time stamped into the reports
+
+	artifacts []ManifestArtifact // Files written so far, for the run manifest
+
+	// Errors recovered from and the AI analysis outcome, for the run manifest's health
+	errors   *ErrorBudget
+	aiStatus string
+
+	signer signing.Signer // Signs the JSON report and manifest, nil leaves them unsigned
+
+	analyzers *analyzers.Runner // Custom analyzers run on the report, nil runs none
+
+	// Queue AI requests wait in with concurrent runs, nil calls the AI API straight away
+	aiQueue        *aiqueue.Queue
+	aiQueueTimeout time.Duration
+
+	// Fields dropped or hashed in every artifact, nil writes the report as it is. The last
+	// pruned report is kept, a run writes all its artifacts from the same one.
+	prunePolicy *prune.Policy
+	prunedFrom  *Report
+	pruned      *Report
 }
 
 // NewGenerator creates a new report generator.
@@ -37,15 +78,26 @@ func NewGenerator(logger logrus.FieldLogger) (*DefaultGenerator, error) {
 		fileManager:     NewDefaultFileManager(logger),
 		dataProcessor:   NewDefaultDataProcessor(logger),
 		aiAnalyzer:      NewDefaultAIAnalyzer(logger),
+		redactor:        redact.New(),
 		logger:          logger.WithField("component", "report_generator"),
+		shardSize:       constants.DefaultShardSize,
+		dataFileBudget:  constants.DefaultDataFileBudgetMB << 20,
+		swimlanePeers:   constants.DefaultSwimlanePeers,
+		clock:           time.Now,
+		errors:          NewErrorBudget(),
 	}, nil
 }
 
 // GenerateJSON generates a JSON report and saves it to a file.
 func (g *DefaultGenerator) GenerateJSON(report *Report) (string, error) {
-	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	report, err := g.pruneReport(report)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal report: %w", err)
+		return "", err
+	}
+
+	reportJSON, err := g.marshalReport(report)
+	if err != nil {
+		return "", err
 	}
 
 	// Generate timestamped filename
@@ -55,81 +107,258 @@ func (g *DefaultGenerator) GenerateJSON(report *Report) (string, error) {
 		return "", fmt.Errorf("failed to save JSON report: %w", err)
 	}
 
+	g.recordArtifact(ArtifactJSON, filename)
 	g.logger.WithField("filename", filename).Info("JSON report generated successfully")
 
+	if err := g.sign(filename); err != nil {
+		return "", fmt.Errorf("failed to sign JSON report: %w", err)
+	}
+
 	return filename, nil
 }
 
+// marshalReport encodes the report as written to the JSON report, with secrets redacted.
+func (g *DefaultGenerator) marshalReport(report *Report) ([]byte, error) {
+	redacted := *report
+	redacted.Config = g.redactConfig(report.Config)
+
+	reportJSON, err := json.MarshalIndent(&redacted, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	return []byte(g.redactor.String(string(reportJSON))), nil
+}
+
+// sign signs an artifact when signing is configured, listing the signature in the manifest.
+func (g *DefaultGenerator) sign(filename string) error {
+	if g.signer == nil {
+		return nil
+	}
+
+	signatureFile, err := g.signer.Sign(filename)
+	if err != nil {
+		return err
+	}
+
+	g.recordArtifact(ArtifactSignature, signatureFile)
+	g.logger.WithField("filename", signatureFile).Info("Artifact signed")
+
+	return nil
+}
+
 // GenerateHTML generates an HTML report and saves it to a file.
-func (g *DefaultGenerator) GenerateHTML(report *Report) (string, error) {
-	return g.generateHTMLReport(report, "")
+func (g *DefaultGenerator) GenerateHTML(ctx context.Context, report *Report) (string, error) {
+	report, err := g.pruneReport(report)
+	if err != nil {
+		return "", err
+	}
+
+	return g.generateHTMLReport(ctx, report, "")
+}
+
+// GenerateHTMLWithAI generates an HTML report with AI analysis. The AI analysis sees the
+// report as pruned, the fields pruned from the artifacts are not sent either.
+func (g *DefaultGenerator) GenerateHTMLWithAI(ctx context.Context, report *Report, apiKey string) (string, error) {
+	report, err := g.pruneReport(report)
+	if err != nil {
+		return "", err
+	}
+
+	return g.generateHTMLReport(ctx, report, g.analyzeWithAI(ctx, report, apiKey))
 }
 
-// GenerateHTMLWithAI generates an HTML report with AI analysis.
-func (g *DefaultGenerator) GenerateHTMLWithAI(report *Report, apiKey string) (string, error) {
-	// Generate AI analysis first
+// analyzeWithAI generates the AI analysis, waiting for a slot in the AI queue first when one
+// is set. A failed analysis, or one deferred because no slot freed up in time, is left out.
+func (g *DefaultGenerator) analyzeWithAI(ctx context.Context, report *Report, apiKey string) string {
+	if g.aiQueue != nil {
+		started := time.Now()
+
+		slot, err := g.aiQueue.Acquire(ctx, g.aiQueueTimeout)
+		if err != nil {
+			if errors.Is(err, aiqueue.ErrTimeout) {
+				g.logger.WithField("waited", g.aiQueueTimeout).Warn("No AI queue slot freed up in time, deferring the AI analysis")
+
+				g.aiStatus = AIStatusDeferred
+
+				return ""
+			}
+
+			g.logger.WithError(err).Warn("Failed to queue for AI analysis, proceeding without it")
+			g.errors.Record(ErrorCategoryAI)
+
+			g.aiStatus = AIStatusFailed
+
+			return ""
+		}
+
+		defer func() {
+			if err := slot.Release(); err != nil {
+				g.logger.WithError(err).Warn("Failed to release AI queue slot")
+			}
+		}()
+
+		g.logger.WithField("waited", time.Since(started).Round(time.Millisecond)).Info("Acquired AI queue slot")
+	}
+
 	aiAnalysis, err := g.aiAnalyzer.AnalyzeReport(report, apiKey)
 	if err != nil {
 		g.logger.WithError(err).Warn("Failed to generate AI analysis, proceeding without it")
+		g.errors.Record(ErrorCategoryAI)
 
-		aiAnalysis = ""
+		g.aiStatus = AIStatusFailed
+
+		return ""
 	}
 
-	return g.generateHTMLReport(report, aiAnalysis)
+	g.aiStatus = AIStatusOK
+
+	return aiAnalysis
 }
 
 // generateHTMLReport is the common HTML generation logic.
-func (g *DefaultGenerator) generateHTMLReport(report *Report, aiAnalysis string) (string, error) {
+func (g *DefaultGenerator) generateHTMLReport(ctx context.Context, report *Report, aiAnalysis string) (string, error) {
+	htmlFilename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultHTMLReportFile, report.Timestamp)
+	dataFilename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultDataJSFile, report.Timestamp)
+
+	if err := g.writeHTML(ctx, report, aiAnalysis, htmlFilename, dataFilename); err != nil {
+		return "", err
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"html_file": htmlFilename,
+		"data_file": dataFilename,
+	}).Info("HTML report generated successfully")
+
+	return htmlFilename, nil
+}
+
+// writeHTML renders the HTML report with its swimlane view and data file. When ctx is
+// cancelled part way, the files written so far are marked partial.
+func (g *DefaultGenerator) writeHTML(ctx context.Context, report *Report, aiAnalysis, htmlFilename, dataFilename string) error {
+	written, err := g.renderHTML(ctx, report, aiAnalysis, htmlFilename, dataFilename)
+	if err != nil && ctx.Err() != nil {
+		g.markPartial(written...)
+	}
+
+	return err
+}
+
+// renderHTML writes the HTML report files stage by stage, checking for cancellation between
+// stages, and returns the paths it wrote or started writing.
+func (g *DefaultGenerator) renderHTML(ctx context.Context, report *Report, aiAnalysis, htmlFilename, dataFilename string) ([]string, error) {
+	written := make([]string, 0, 4)
+
+	if err := checkCancelled(ctx, "template data"); err != nil {
+		return written, err
+	}
+
+	started := time.Now()
+
 	// Process data for template
 	templateData, err := g.dataProcessor.FormatForTemplate(report)
 	if err != nil {
-		return "", fmt.Errorf("failed to format data for template: %w", err)
+		return written, fmt.Errorf("failed to format data for template: %w", err)
 	}
 
-	// Generate filename first to use in template
-	htmlFilename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultHTMLReportFile, report.Timestamp)
-	dataFilename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultDataJSFile, report.Timestamp)
+	g.logStage("template data", started, logrus.Fields{"peers": len(report.Peers)})
+
+	if err := checkCancelled(ctx, "swimlanes"); err != nil {
+		return written, err
+	}
+
+	swimlanesFilename, err := g.generateSwimlanes(report, htmlFilename)
+	if err != nil {
+		g.logger.WithError(err).Warn("Failed to generate swimlane view")
+		g.errors.Record(ErrorCategoryReport)
+	}
+
+	if swimlanesFilename != "" {
+		written = append(written, swimlanesFilename)
+		g.recordArtifact(ArtifactSwimlanes, swimlanesFilename)
+	}
 
 	// Add AI analysis and data file if provided
 	if reportData, ok := templateData.(map[string]interface{}); ok {
 		reportData["AIAnalysis"] = aiAnalysis
+		reportData["AIDeferred"] = g.aiStatus == AIStatusDeferred
 		reportData["DataFile"] = dataFilename
+		reportData["SwimlanesFile"] = swimlanesFilename
 
 		// Convert AI analysis to safe HTML
 		if aiAnalysis != "" {
 			if processor, ok := g.dataProcessor.(*DefaultDataProcessor); ok {
-				reportData["AIAnalysisHTML"] = processor.CleanAIHTML(aiAnalysis)
+				reportData["AIAnalysisHTML"] = newAIReferences(report).Link(processor.CleanAIHTML(aiAnalysis))
 			}
+
+			reportData["AIMarkdownFile"] = aiExportFilename(htmlFilename, AIExportMarkdown)
+			reportData["AITextFile"] = aiExportFilename(htmlFilename, AIExportText)
 		}
 	}
 
+	if err := checkCancelled(ctx, "HTML rendering"); err != nil {
+		return written, err
+	}
+
+	started = time.Now()
+
 	// Render template
 	htmlContent, err := g.templateManager.RenderReport(templateData)
 	if err != nil {
-		return "", fmt.Errorf("failed to render HTML template: %w", err)
+		return written, fmt.Errorf("failed to render HTML template: %w", err)
 	}
 
-	// HTML filename was already generated above
-
 	if err := g.fileManager.SaveHTML(htmlFilename, htmlContent); err != nil {
-		return "", fmt.Errorf("failed to save HTML report: %w", err)
+		return written, fmt.Errorf("failed to save HTML report: %w", err)
 	}
 
-	// Generate data file for JavaScript (filename was already generated above)
-	if err := g.generateDataFile(report, dataFilename); err != nil {
+	written = append(written, htmlFilename)
+	g.recordArtifact(ArtifactHTML, htmlFilename)
+
+	g.logStage("HTML", started, logrus.Fields{"filename": htmlFilename, "bytes_written": len(htmlContent)})
+
+	// The data file and its shards are incomplete if generation is cancelled while they are written
+	written = append(written, dataFilename, shardDirFor(dataFilename))
+
+	if err := g.generateDataFile(ctx, report, dataFilename); err != nil {
+		if ctx.Err() != nil {
+			return written, err
+		}
+
 		g.logger.WithError(err).Warn("Failed to generate data file")
+		g.errors.Record(ErrorCategoryReport)
+	} else {
+		g.recordArtifact(ArtifactData, dataFilename)
+
+		if g.splitReport {
+			g.recordArtifact(ArtifactShards, shardDirFor(dataFilename))
+		}
 	}
 
-	g.logger.WithFields(logrus.Fields{
-		"html_file": htmlFilename,
-		"data_file": dataFilename,
-	}).Info("HTML report generated successfully")
+	if aiAnalysis == "" {
+		return written, nil
+	}
 
-	return htmlFilename, nil
+	if err := checkCancelled(ctx, "AI analysis export"); err != nil {
+		return written, err
+	}
+
+	exported, err := g.exportAIAnalysis(report, aiAnalysis, htmlFilename)
+	written = append(written, exported...)
+
+	if err != nil {
+		g.logger.WithError(err).Warn("Failed to export AI analysis")
+		g.errors.Record(ErrorCategoryReport)
+	}
+
+	return written, nil
 }
 
-// generateDataFile creates a JavaScript data file for the HTML report.
-func (g *DefaultGenerator) generateDataFile(report *Report, filename string) error {
+// generateDataFile creates a JavaScript data file for the HTML report, stopping with an
+// error when ctx is cancelled.
+func (g *DefaultGenerator) generateDataFile(ctx context.Context, report *Report, filename string) error {
+	started := time.Now()
+
 	// Process the full report data for JavaScript consumption with event counts
 	var processedData interface{}
 
@@ -146,6 +375,12 @@ func (g *DefaultGenerator) generateDataFile(report *Report, filename string) err
 		return fmt.Errorf("failed to process peer data: %w", err)
 	}
 
+	g.logStage("peer data", started, logrus.Fields{"peers": len(report.Peers)})
+
+	if err := checkCancelled(ctx, "peer data"); err != nil {
+		return err
+	}
+
 	// Extract the peers array from the processed data
 	var peersArray interface{}
 
@@ -159,6 +394,11 @@ func (g *DefaultGenerator) generateDataFile(report *Report, filename string) err
 		peersArray = processedData // Use as-is if not a map
 	}
 
+	// Bucketed event counts drive the sparklines in the peer detail view
+	if peers, ok := peersArray.([]map[string]interface{}); ok {
+		attachEventBuckets(peers, report.EventTimeline)
+	}
+
 	// Calculate summary statistics including goodbye events
 	summaryStats, err := g.dataProcessor.CalculateSummaryStats(report)
 	if err != nil {
@@ -171,34 +411,53 @@ func (g *DefaultGenerator) generateDataFile(report *Report, filename string) err
 			"format_version": "1.0",
 			"processed_at":   report.Timestamp.Format(time.RFC3339),
 			"total_peers":    len(report.Peers),
+			"agent_version":  report.AgentVersion,
+			"phases":         report.Phases,
+			"timeline":       timelineMetadata(report.EventTimeline),
 		},
 		"peers":           peersArray,
 		"peerEventCounts": report.PeerEventCounts,
 		"summary":         summaryStats,
 	}
 
-	dataJSON, err := json.MarshalIndent(jsData, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
-	}
+	// In split mode peers are served from shards, so the data file only carries the manifest
+	if g.splitReport {
+		peers, ok := peersArray.([]map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected processed peer data format: %T", peersArray)
+		}
 
-	// Wrap in JavaScript variable
-	jsContent := fmt.Sprintf("window.reportData = %s;", string(dataJSON))
+		manifest, serr := g.writeShards(ctx, peers, filename)
+		if serr != nil {
+			return fmt.Errorf("failed to write report shards: %w", serr)
+		}
+
+		jsData["peers"] = []interface{}{}
+		jsData["split"] = manifest
+
+		if summary, ok := summaryStats.(map[string]interface{}); ok {
+			trimmed := make(map[string]interface{}, len(summary))
+			for key, value := range summary {
+				if key != "peer_summaries" {
+					trimmed[key] = value
+				}
+			}
 
-	if err := os.WriteFile(filename, []byte(jsContent), constants.DefaultFilePermissions); err != nil {
-		return fmt.Errorf("failed to write data file: %w", err)
+			jsData["summary"] = trimmed
+		}
 	}
 
-	return nil
+	// Stream the data file rather than marshalling it whole, which doubled peak memory
+	return g.writeDataFile(ctx, filename, jsData)
 }
 
 // GenerateHTMLFromJSON generates HTML report from existing JSON file.
-func (g *DefaultGenerator) GenerateHTMLFromJSON(jsonFile, outputFile string) error {
-	return g.GenerateHTMLFromJSONWithAI(jsonFile, outputFile, "")
+func (g *DefaultGenerator) GenerateHTMLFromJSON(ctx context.Context, jsonFile, outputFile string) error {
+	return g.GenerateHTMLFromJSONWithAI(ctx, jsonFile, outputFile, "")
 }
 
 // GenerateHTMLFromJSONWithAI generates HTML report from existing JSON file with optional AI analysis.
-func (g *DefaultGenerator) GenerateHTMLFromJSONWithAI(jsonFile, outputFile, apiKey string) error {
+func (g *DefaultGenerator) GenerateHTMLFromJSONWithAI(ctx context.Context, jsonFile, outputFile, apiKey string) error {
 	// Check if input file exists
 	if !g.fileManager.FileExists(jsonFile) {
 		return fmt.Errorf("input JSON file does not exist: %s", jsonFile)
@@ -210,59 +469,28 @@ func (g *DefaultGenerator) GenerateHTMLFromJSONWithAI(jsonFile, outputFile, apiK
 		return fmt.Errorf("failed to read JSON file: %w", err)
 	}
 
-	var report Report
-	if jerr := json.Unmarshal(jsonData, &report); jerr != nil {
+	var parsed Report
+	if jerr := json.Unmarshal(jsonData, &parsed); jerr != nil {
 		return fmt.Errorf("failed to parse JSON report: %w", jerr)
 	}
 
+	report, err := g.pruneReport(&parsed)
+	if err != nil {
+		return err
+	}
+
 	// Generate AI analysis if API key provided
 	var aiAnalysis string
 
 	if apiKey != "" {
-		analysis, aerr := g.aiAnalyzer.AnalyzeReport(&report, apiKey)
-		if aerr != nil {
-			g.logger.WithError(aerr).Warn("Failed to generate AI analysis")
-		} else {
-			aiAnalysis = analysis
-		}
-	}
-
-	// Process data for template
-	templateData, err := g.dataProcessor.FormatForTemplate(&report)
-	if err != nil {
-		return fmt.Errorf("failed to format data for template: %w", err)
+		aiAnalysis = g.analyzeWithAI(ctx, report, apiKey)
 	}
 
 	// Generate data filename
 	dataFilename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultDataJSFile, report.Timestamp)
 
-	// Add AI analysis and data file to template data
-	if reportData, ok := templateData.(map[string]interface{}); ok {
-		reportData["AIAnalysis"] = aiAnalysis
-		reportData["DataFile"] = dataFilename
-
-		// Convert AI analysis to safe HTML
-		if aiAnalysis != "" {
-			if processor, ok := g.dataProcessor.(*DefaultDataProcessor); ok {
-				reportData["AIAnalysisHTML"] = processor.CleanAIHTML(aiAnalysis)
-			}
-		}
-	}
-
-	// Render template
-	htmlContent, err := g.templateManager.RenderReport(templateData)
-	if err != nil {
-		return fmt.Errorf("failed to render HTML template: %w", err)
-	}
-
-	// Save HTML file
-	if err := g.fileManager.SaveHTML(outputFile, htmlContent); err != nil {
-		return fmt.Errorf("failed to save HTML file: %w", err)
-	}
-
-	// Generate data file for JavaScript
-	if err := g.generateDataFile(&report, dataFilename); err != nil {
-		g.logger.WithError(err).Warn("Failed to generate data file")
+	if err := g.writeHTML(ctx, report, aiAnalysis, outputFile, dataFilename); err != nil {
+		return err
 	}
 
 	g.logger.WithFields(logrus.Fields{
@@ -288,6 +516,15 @@ func (g *DefaultGenerator) SetTemplateManager(tm *templates.Manager) {
 	g.templateManager = tm
 }
 
+// SetTemplateDir replaces the embedded templates with the ones in dir, empty keeps them all.
+func (g *DefaultGenerator) SetTemplateDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	return g.templateManager.LoadOverrides(dir)
+}
+
 // SetFileManager allows injecting a different file manager (for testing).
 func (g *DefaultGenerator) SetFileManager(fm FileManager) {
 	g.fileManager = fm
@@ -298,6 +535,150 @@ func (g *DefaultGenerator) SetDataProcessor(dp DataProcessor) {
 	g.dataProcessor = dp
 }
 
+// SetClock sets the source of the generation time stamped into the reports, time.Now by default.
+func (g *DefaultGenerator) SetClock(clock func() time.Time) {
+	g.clock = clock
+
+	if processor, ok := g.dataProcessor.(*DefaultDataProcessor); ok {
+		processor.clock = clock
+	}
+}
+
+// SetErrorBudget sets the error budget the generator counts the errors it recovers from in,
+// shared with the rest of the run so the manifest grades all of them.
+func (g *DefaultGenerator) SetErrorBudget(budget *ErrorBudget) {
+	g.errors = budget
+}
+
+// SetSigner sets the signer of the JSON report and manifest, nil leaves them unsigned.
+func (g *DefaultGenerator) SetSigner(signer signing.Signer) {
+	g.signer = signer
+}
+
+// SetAIQueue sets the queue AI requests wait in with concurrent runs, and how long they wait
+// before the analysis is deferred. A nil queue calls the AI API straight away.
+func (g *DefaultGenerator) SetAIQueue(queue *aiqueue.Queue, timeout time.Duration) {
+	g.aiQueue = queue
+	g.aiQueueTimeout = timeout
+}
+
+// SetSplitReport configures whether peer data is split into index shards, and the number of peers per shard.
+func (g *DefaultGenerator) SetSplitReport(enabled bool, shardSize int) {
+	g.splitReport = enabled
+	g.shardSize = shardSize
+}
+
+// SetDataFile configures whether the data file is indented, and the memory budget in bytes
+// for peers encoded at once while it is written.
+func (g *DefaultGenerator) SetDataFile(pretty bool, budget int) {
+	g.prettyDataFile = pretty
+	g.dataFileBudget = budget
+}
+
+// timelineMetadata describes the event buckets without the per-peer counts.
+func timelineMetadata(timeline *peer.EventTimeline) map[string]interface{} {
+	if timeline == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"start":           timeline.Start,
+		"bucket_seconds":  timeline.BucketSeconds,
+		"buckets":         timeline.Buckets,
+		"burst_threshold": timeline.BurstThreshold,
+		"out_of_range":    timeline.OutOfRange,
+	}
+}
+
+// SetRedactor sets the redactor applied to everything the generator writes or sends for AI analysis.
+func (g *DefaultGenerator) SetRedactor(redactor *redact.Redactor) {
+	g.redactor = redactor
+
+	if analyzer, ok := g.aiAnalyzer.(*DefaultAIAnalyzer); ok {
+		analyzer.SetRedactor(redactor)
+	}
+}
+
+// SetAIClient sets the API and proxy AI analysis is requested through.
+func (g *DefaultGenerator) SetAIClient(options AIClientOptions) error {
+	if analyzer, ok := g.aiAnalyzer.(*DefaultAIAnalyzer); ok {
+		return analyzer.SetClient(options)
+	}
+
+	return nil
+}
+
+// SetPrunePolicy sets the fields dropped or hashed in everything the generator writes or sends
+// for AI analysis, nil to write reports as they are.
+func (g *DefaultGenerator) SetPrunePolicy(policy *prune.Policy) {
+	g.prunePolicy = policy
+	g.prunedFrom, g.pruned = nil, nil
+}
+
+// pruneReport returns a copy of the report with the pruning policy applied, the report itself
+// without a policy. The policy works on the report's JSON, so the copy is decoded from it and
+// records the policy. A report read back that was pruned when written is not pruned again,
+// hashing its hashes.
+func (g *DefaultGenerator) pruneReport(report *Report) (*Report, error) {
+	if g.prunePolicy == nil || report.PrunePolicy != nil {
+		return report, nil
+	}
+
+	if report == g.prunedFrom {
+		return g.pruned, nil
+	}
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report for pruning: %w", err)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode report for pruning: %w", err)
+	}
+
+	raw, err = json.Marshal(g.prunePolicy.Value(decoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pruned report: %w", err)
+	}
+
+	var pruned Report
+	if err := json.Unmarshal(raw, &pruned); err != nil {
+		return nil, fmt.Errorf("failed to decode pruned report: %w", err)
+	}
+
+	pruned.PrunePolicy = g.prunePolicy
+	g.prunedFrom, g.pruned = report, &pruned
+
+	return &pruned, nil
+}
+
+// redactConfig returns the report config with credentials redacted. Configs loaded from
+// older JSON reports are walked the same way, so re-rendering them cannot leak secrets.
+func (g *DefaultGenerator) redactConfig(cfg interface{}) interface{} {
+	if cfg == nil {
+		return nil
+	}
+
+	if _, ok := cfg.(map[string]interface{}); !ok {
+		// Normalize typed configs into decoded JSON values so they can be walked
+		raw, err := json.Marshal(cfg)
+		if err != nil {
+			return nil
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil
+		}
+
+		cfg = decoded
+	}
+
+	return g.redactor.Value(cfg)
+}
+
 // SetAIAnalyzer allows injecting a different AI analyzer (for testing).
 func (g *DefaultGenerator) SetAIAnalyzer(ai AIAnalyzer) {
 	g.aiAnalyzer = ai
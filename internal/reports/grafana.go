@@ -0,0 +1,144 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// GrafanaSchemaVersion is the version of the Grafana dashboard file layout. Fields are only
+// ever added within a version, a change of meaning or a removal bumps it.
+const GrafanaSchemaVersion = 1
+
+// GrafanaReport is a run's key metrics shaped for the Grafana Infinity and JSON datasources.
+// Every field but the two tables is a scalar at the top level, so a panel reads the run as one
+// row without a parser. The tables are arrays of flat rows, selected with their key as the
+// root. Its numbers come from the lite report, so the two never disagree.
+type GrafanaReport struct {
+	SchemaVersion   int     `json:"schema_version"`
+	ValidationMode  string  `json:"validation_mode"`
+	Network         string  `json:"network"`
+	HermesVersion   string  `json:"hermes_version"`
+	AgentVersion    string  `json:"agent_version"`
+	StartTime       string  `json:"start_time"` // RFC 3339
+	EndTime         string  `json:"end_time"`   // RFC 3339
+	StartTimeMs     int64   `json:"start_time_ms"`
+	EndTimeMs       int64   `json:"end_time_ms"`
+	DurationSeconds float64 `json:"duration_seconds"`
+
+	UniquePeers           int     `json:"unique_peers"`
+	TotalConnections      int     `json:"total_connections"`
+	SuccessfulHandshakes  int     `json:"successful_handshakes"`
+	FailedHandshakes      int     `json:"failed_handshakes"`
+	HandshakeSuccessRate  float64 `json:"handshake_success_rate"`
+	Sessions              int     `json:"sessions"`
+	Disconnects           int     `json:"disconnects"`
+	GoodbyeEvents         int     `json:"goodbye_events"`
+	InvalidDeliveryTopics int     `json:"invalid_delivery_topics"`
+	DegradedTopics        int     `json:"degraded_topics"`
+	UnhealthyTopics       int     `json:"unhealthy_topics"`
+	ClockSkewed           bool    `json:"clock_skewed"`
+	BeaconFetchHealth     string  `json:"beacon_fetch_health"` // Empty in delegated mode
+	StarvedSeconds        float64 `json:"starved_seconds"`
+
+	EventsChecked      int     `json:"events_checked"`
+	MissingTimestamps  int     `json:"missing_timestamps"`
+	OutOfOrderEvents   int     `json:"out_of_order_events"`
+	MaxLagSeconds      float64 `json:"max_lag_seconds"`
+	UnhandledEvents    int     `json:"unhandled_events"`
+	LateEventsAssigned int     `json:"late_events_assigned"`
+	LateEventsDropped  int     `json:"late_events_dropped"`
+
+	Pruned bool `json:"pruned"` // Fields were dropped or hashed before the report was written
+
+	Clients           []peer.ClientSummary         `json:"clients"`            // Largest client first
+	DisconnectReasons []peer.DisconnectReasonCount `json:"disconnect_reasons"` // Most frequent goodbye codes and reasons, and our own terminations
+}
+
+// BuildGrafanaReport flattens the lite report of a run for Grafana.
+func BuildGrafanaReport(report *Report) *GrafanaReport {
+	lite := BuildLiteReport(report)
+
+	grafana := &GrafanaReport{
+		SchemaVersion:   GrafanaSchemaVersion,
+		ValidationMode:  lite.ValidationMode,
+		Network:         lite.Network,
+		HermesVersion:   lite.HermesVersion,
+		AgentVersion:    lite.AgentVersion,
+		StartTime:       lite.StartTime.UTC().Format(time.RFC3339),
+		EndTime:         lite.EndTime.UTC().Format(time.RFC3339),
+		StartTimeMs:     lite.StartTime.UnixMilli(),
+		EndTimeMs:       lite.EndTime.UnixMilli(),
+		DurationSeconds: lite.DurationSeconds,
+
+		UniquePeers:           lite.Summary.UniquePeers,
+		TotalConnections:      lite.Summary.TotalConnections,
+		SuccessfulHandshakes:  lite.Summary.SuccessfulHandshakes,
+		FailedHandshakes:      lite.Summary.FailedHandshakes,
+		HandshakeSuccessRate:  lite.Summary.HandshakeSuccessRate,
+		Sessions:              lite.Summary.Sessions,
+		Disconnects:           lite.Summary.Disconnects,
+		GoodbyeEvents:         lite.Summary.GoodbyeEvents,
+		InvalidDeliveryTopics: lite.Summary.InvalidDeliveryTopics,
+		DegradedTopics:        lite.Summary.DegradedTopics,
+		UnhealthyTopics:       lite.Summary.UnhealthyTopics,
+		ClockSkewed:           lite.Summary.ClockSkewed,
+		BeaconFetchHealth:     lite.Summary.BeaconFetchHealth,
+		StarvedSeconds:        lite.Summary.StarvedSeconds,
+
+		Pruned:            lite.PrunePolicy != nil,
+		Clients:           lite.Clients,
+		DisconnectReasons: lite.DisconnectReasons,
+	}
+
+	if quality := lite.DataQuality; quality != nil {
+		grafana.EventsChecked = quality.EventsChecked
+		grafana.MissingTimestamps = quality.MissingTimestamps
+		grafana.OutOfOrderEvents = quality.OutOfOrderEvents
+		grafana.MaxLagSeconds = quality.MaxLagSeconds
+		grafana.UnhandledEvents = quality.UnhandledEvents
+		grafana.LateEventsAssigned = quality.LateEventsAssigned
+		grafana.LateEventsDropped = quality.LateEventsDropped
+	}
+
+	// Empty tables are written as arrays, so dashboards see no rows rather than a null
+	if grafana.Clients == nil {
+		grafana.Clients = make([]peer.ClientSummary, 0)
+	}
+
+	if grafana.DisconnectReasons == nil {
+		grafana.DisconnectReasons = make([]peer.DisconnectReasonCount, 0)
+	}
+
+	return grafana
+}
+
+// GenerateGrafanaJSON writes the Grafana dashboard file next to the JSON reports. Its name
+// carries no timestamp, so CI can publish the latest run's at a fixed artifact URL.
+func (g *DefaultGenerator) GenerateGrafanaJSON(report *Report) (string, error) {
+	report, err := g.pruneReport(report)
+	if err != nil {
+		return "", err
+	}
+
+	grafanaJSON, err := json.MarshalIndent(BuildGrafanaReport(report), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Grafana report: %w", err)
+	}
+
+	grafanaJSON = []byte(g.redactor.String(string(grafanaJSON)))
+
+	filename := constants.DefaultGrafanaFile
+
+	if err := g.fileManager.SaveJSON(filename, grafanaJSON); err != nil {
+		return "", fmt.Errorf("failed to save Grafana report: %w", err)
+	}
+
+	g.recordArtifact(ArtifactGrafana, filename)
+	g.logger.WithField("filename", filename).Info("Grafana JSON report generated successfully")
+
+	return filename, nil
+}
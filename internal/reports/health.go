@@ -0,0 +1,237 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Run health grades. A degraded run recovered from errors or has data quality warnings, a
+// failed run did not write all of its reports or collected nothing to report on.
+const (
+	HealthOK       = "OK"
+	HealthDegraded = "DEGRADED"
+	HealthFailed   = "FAILED"
+)
+
+// Error categories counted in the run's error budget.
+const (
+	ErrorCategoryEvents     = "events"     // Trace events a handler failed on
+	ErrorCategoryHermes     = "hermes"     // Hermes nodes that failed to stop or restart
+	ErrorCategoryCheckpoint = "checkpoint" // Checkpoints that could not be written or removed
+	ErrorCategoryReport     = "report"     // Optional report artifacts that could not be written
+	ErrorCategoryAI         = "ai"         // AI analyses that failed
+	ErrorCategoryPublish    = "publish"    // Summary metrics that could not be published
+	ErrorCategoryRegression = "regression" // Baseline comparisons that could not be made
+	ErrorCategoryAnalyzer   = "analyzer"   // Custom analyzers that failed
+	ErrorCategorySpill      = "spill"      // Session events that could not be spilled to disk
+	ErrorCategoryScoreFeed  = "score_feed" // Score feeds that could not be published to or read
+)
+
+// AI analysis statuses.
+const (
+	AIStatusSkipped = "skipped" // No API key, or AI analysis was turned off
+	AIStatusOK      = "ok"
+	AIStatusFailed  = "failed"
+
+	// No AI queue slot freed up in time, the analysis can be added later from the JSON report
+	AIStatusDeferred = "deferred"
+)
+
+// RunHealth grades a run for automation, so it can act on a run without parsing its logs.
+type RunHealth struct {
+	Grade               string         `json:"grade"`
+	Reasons             []string       `json:"reasons,omitempty"` // Why the run is not OK
+	ErrorsByCategory    map[string]int `json:"errors_by_category,omitempty"`
+	DataQualityWarnings []string       `json:"data_quality_warnings,omitempty"`
+	AIStatus            string         `json:"ai_status"`
+}
+
+// ErrorBudget counts the errors a run recovered from, by category. It is safe for concurrent use.
+type ErrorBudget struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewErrorBudget creates an empty error budget.
+func NewErrorBudget() *ErrorBudget {
+	return &ErrorBudget{counts: make(map[string]int)}
+}
+
+// Record counts an error of the given category.
+func (b *ErrorBudget) Record(category string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.counts[category]++
+}
+
+// Counts returns the errors counted so far by category, or nil when there were none.
+func (b *ErrorBudget) Counts() map[string]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.counts) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int, len(b.counts))
+	for category, count := range b.counts {
+		counts[category] = count
+	}
+
+	return counts
+}
+
+// gradeRun grades a run from its report, the errors it recovered from and the error that
+// stopped report generation, if any.
+func gradeRun(report *Report, errors map[string]int, aiStatus string, runErr error) RunHealth {
+	health := RunHealth{
+		Grade:               HealthOK,
+		ErrorsByCategory:    errors,
+		DataQualityWarnings: dataQualityWarnings(report),
+		AIStatus:            aiStatus,
+	}
+
+	if health.AIStatus == "" {
+		health.AIStatus = AIStatusSkipped
+	}
+
+	if runErr != nil {
+		health.Reasons = append(health.Reasons, "report generation failed: "+runErr.Error())
+	}
+
+	if report.TotalConnections == 0 {
+		health.Reasons = append(health.Reasons, "no peers connected")
+	}
+
+	if len(health.Reasons) > 0 {
+		health.Grade = HealthFailed
+	}
+
+	for _, category := range sortedCategories(errors) {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("%d %s errors", errors[category], category))
+	}
+
+	if len(health.DataQualityWarnings) > 0 {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("%d data quality warnings", len(health.DataQualityWarnings)))
+	}
+
+	if health.Grade == HealthOK && len(health.Reasons) > 0 {
+		health.Grade = HealthDegraded
+	}
+
+	return health
+}
+
+// dataQualityWarnings lists what makes the run's numbers less trustworthy.
+func dataQualityWarnings(report *Report) []string {
+	var warnings []string
+
+	if report.Phases != nil && report.Phases.Interrupted {
+		warnings = append(warnings, "the run was interrupted before its cooldown ended")
+	}
+
+	if len(report.Gaps) > 0 {
+		warnings = append(warnings, fmt.Sprintf("the collector was down %d times", len(report.Gaps)))
+	}
+
+	if len(report.Starvation) > 0 {
+		var seconds float64
+		for _, window := range report.Starvation {
+			seconds += window.Seconds
+		}
+
+		warnings = append(warnings, fmt.Sprintf("no events arrived for %.0fs in %d windows", seconds, len(report.Starvation)))
+	}
+
+	if report.ClockSkew != nil && report.ClockSkew.Skewed {
+		warnings = append(warnings, "clock skew against the beacon node or peers")
+	}
+
+	if report.EventTimeline != nil && report.EventTimeline.OutOfRange > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d events stamped after the run window, left out of the event timeline", report.EventTimeline.OutOfRange))
+	}
+
+	quality := report.DataQuality
+	if quality == nil {
+		return warnings
+	}
+
+	if quality.EventsChecked > 0 {
+		if ratio := float64(quality.OutOfOrderEvents) / float64(quality.EventsChecked); ratio > constants.HealthOutOfOrderRatio {
+			warnings = append(warnings, fmt.Sprintf("%.1f%% of events out of order", ratio*100))
+		}
+	}
+
+	if quality.MissingTimestamps > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d events without a trace timestamp", quality.MissingTimestamps))
+	}
+
+	if quality.UnhandledEvents > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d events of %d unhandled types", quality.UnhandledEvents, len(quality.UnhandledTypes)))
+	}
+
+	if quality.PeerIDReflectionFallbacks > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d peer IDs found by reflection", quality.PeerIDReflectionFallbacks))
+	}
+
+	if quality.CorruptTopicScores > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d score snapshots with unreadable topic scores", quality.CorruptTopicScores))
+	}
+
+	return warnings
+}
+
+// sortedCategories returns the error categories in name order.
+func sortedCategories(errors map[string]int) []string {
+	categories := make([]string, 0, len(errors))
+	for category := range errors {
+		categories = append(categories, category)
+	}
+
+	sort.Strings(categories)
+
+	return categories
+}
+
+// WriteRunSummary prints the run's health and the artifacts it wrote, for the end of a run.
+func WriteRunSummary(w io.Writer, manifest *Manifest, manifestFile string) error {
+	health := manifest.Health
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Run health: %s\n", health.Grade)
+
+	if len(health.ErrorsByCategory) > 0 {
+		counts := make([]string, 0, len(health.ErrorsByCategory))
+		for _, category := range sortedCategories(health.ErrorsByCategory) {
+			counts = append(counts, fmt.Sprintf("%s %d", category, health.ErrorsByCategory[category]))
+		}
+
+		fmt.Fprintf(&b, "  Errors: %s\n", strings.Join(counts, ", "))
+	}
+
+	for _, warning := range health.DataQualityWarnings {
+		fmt.Fprintf(&b, "  Data quality: %s\n", warning)
+	}
+
+	fmt.Fprintf(&b, "  AI analysis: %s\n", health.AIStatus)
+	fmt.Fprintf(&b, "  Artifacts:\n")
+
+	for _, artifact := range manifest.Artifacts {
+		fmt.Fprintf(&b, "    %-17s %s\n", artifact.Kind, artifact.Path)
+	}
+
+	if manifestFile != "" {
+		fmt.Fprintf(&b, "    %-17s %s\n", "manifest", manifestFile)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
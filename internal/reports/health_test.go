@@ -0,0 +1,105 @@
+package reports
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
+)
+
+func TestGradeRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   *Report
+		errors   map[string]int
+		aiStatus string
+		runErr   error
+		grade    string
+		warnings int
+	}{
+		{
+			name:   "clean run",
+			report: &Report{TotalConnections: 10, DataQuality: &peer.DataQualityStats{EventsChecked: 1000, OutOfOrderEvents: 5}},
+			grade:  HealthOK,
+		},
+		{
+			name:     "recovered errors",
+			report:   &Report{TotalConnections: 10},
+			errors:   map[string]int{ErrorCategoryPublish: 1},
+			aiStatus: AIStatusFailed,
+			grade:    HealthDegraded,
+		},
+		{
+			name: "data quality warnings",
+			report: &Report{
+				TotalConnections: 10,
+				DataQuality:      &peer.DataQualityStats{EventsChecked: 100, OutOfOrderEvents: 5, UnhandledEvents: 2},
+				Starvation:       []watchdog.Window{{Seconds: 300}},
+			},
+			grade:    HealthDegraded,
+			warnings: 3,
+		},
+		{
+			name:   "no peers connected",
+			report: &Report{},
+			grade:  HealthFailed,
+		},
+		{
+			name:   "report generation failed",
+			report: &Report{TotalConnections: 10},
+			errors: map[string]int{ErrorCategoryReport: 1},
+			runErr: errors.New("failed to save HTML report: disk full"),
+			grade:  HealthFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := gradeRun(tt.report, tt.errors, tt.aiStatus, tt.runErr)
+
+			if health.Grade != tt.grade {
+				t.Errorf("Expected grade %s, got %s (reasons %v)", tt.grade, health.Grade, health.Reasons)
+			}
+
+			if len(health.DataQualityWarnings) != tt.warnings {
+				t.Errorf("Expected %d data quality warnings, got %v", tt.warnings, health.DataQualityWarnings)
+			}
+
+			if health.Grade != HealthOK && len(health.Reasons) == 0 {
+				t.Error("Expected the reasons for a run that is not OK")
+			}
+		})
+	}
+}
+
+func TestWriteRunSummary(t *testing.T) {
+	manifest := &Manifest{
+		Health: RunHealth{
+			Grade:               HealthDegraded,
+			ErrorsByCategory:    map[string]int{ErrorCategoryPublish: 1, ErrorCategoryCheckpoint: 2},
+			DataQualityWarnings: []string{"5.0% of events out of order"},
+			AIStatus:            AIStatusSkipped,
+		},
+		Artifacts: []ManifestArtifact{{Kind: ArtifactJSON, Path: "report.json"}},
+	}
+
+	var out strings.Builder
+	if err := WriteRunSummary(&out, manifest, "manifest.json"); err != nil {
+		t.Fatalf("Expected no error writing the summary, got %v", err)
+	}
+
+	for _, want := range []string{
+		"Run health: DEGRADED\n",
+		"  Errors: checkpoint 2, publish 1\n",
+		"  Data quality: 5.0% of events out of order\n",
+		"  AI analysis: skipped\n",
+		"report.json\n",
+		"manifest.json\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package reports
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/alerting"
+)
+
+// hermesRegressionTemplate is the template rendered for runs compared across Hermes versions.
+const hermesRegressionTemplate = "hermes_regression"
+
+// regressionRow is a metric compared between the baseline and current run, formatted for display.
+type regressionRow struct {
+	Metric    string
+	Label     string
+	Baseline  string
+	Current   string
+	Change    string
+	Threshold string
+	Regressed bool
+}
+
+// GenerateHermesRegression renders the Hermes regression report for a comparison across
+// Hermes versions and saves it next to the run's other reports.
+func (g *DefaultGenerator) GenerateHermesRegression(comparison *alerting.Comparison, baselineReport string, timestamp time.Time) (string, error) {
+	content, err := g.templateManager.RenderTemplate(hermesRegressionTemplate, FormatHermesRegression(comparison, baselineReport, timestamp))
+	if err != nil {
+		return "", fmt.Errorf("failed to render Hermes regression template: %w", err)
+	}
+
+	filename := g.generateTimestampedFilename(comparison.Current.ValidationMode, constants.DefaultHermesRegressionFile, timestamp)
+
+	if err := g.fileManager.SaveHTML(filename, content); err != nil {
+		return "", fmt.Errorf("failed to save Hermes regression report: %w", err)
+	}
+
+	g.recordArtifact(ArtifactHermesRegression, filename)
+	g.logger.WithField("filename", filename).Info("Hermes regression report generated successfully")
+
+	return filename, nil
+}
+
+// FormatHermesRegression prepares a comparison across Hermes versions for the regression template.
+func FormatHermesRegression(comparison *alerting.Comparison, baselineReport string, generatedAt time.Time) map[string]interface{} {
+	hermesMetrics := make([]regressionRow, 0, len(comparison.HermesDeltas))
+
+	for _, delta := range comparison.HermesDeltas {
+		change := "-"
+		if delta.Baseline > 0 {
+			change = alerting.FormatChange(delta.Metric, delta.Change)
+		}
+
+		hermesMetrics = append(hermesMetrics, regressionRow{
+			Metric:    delta.Metric,
+			Baseline:  alerting.FormatMetric(delta.Metric, delta.Baseline),
+			Current:   alerting.FormatMetric(delta.Metric, delta.Current),
+			Change:    change,
+			Regressed: delta.Regressed,
+		})
+	}
+
+	regressions := make([]regressionRow, 0, len(comparison.Regressions))
+
+	for _, regression := range comparison.Regressions {
+		regressions = append(regressions, regressionRow{
+			Metric:    regression.Metric,
+			Baseline:  alerting.FormatMetric(regression.Metric, regression.Baseline),
+			Current:   alerting.FormatMetric(regression.Metric, regression.Current),
+			Change:    alerting.FormatChange(regression.Metric, regression.Drop),
+			Threshold: fmt.Sprintf("%.1f%%", regression.Threshold*100),
+			Regressed: true,
+		})
+	}
+
+	baseline, current := comparison.Baseline, comparison.Current
+
+	summary := []regressionRow{
+		{Label: "Peers with sessions", Baseline: fmt.Sprint(baseline.Hermes.Peers), Current: fmt.Sprint(current.Hermes.Peers)},
+		{Label: "Peers grafted", Baseline: fmt.Sprint(baseline.Hermes.MeshPeers), Current: fmt.Sprint(current.Hermes.MeshPeers)},
+		{Label: "Scored sessions", Baseline: fmt.Sprint(baseline.Hermes.ScoredSessions), Current: fmt.Sprint(current.Hermes.ScoredSessions)},
+		{Label: "GRAFT / PRUNE events", Baseline: fmt.Sprintf("%d / %d", baseline.Hermes.Grafts, baseline.Hermes.Prunes), Current: fmt.Sprintf("%d / %d", current.Hermes.Grafts, current.Hermes.Prunes)},
+		{Label: "Total connections", Baseline: fmt.Sprint(baseline.TotalConnections), Current: fmt.Sprint(current.TotalConnections)},
+		{Label: "Handshake success rate", Baseline: alerting.FormatMetric(alerting.MetricHandshakeSuccessRate, baseline.HandshakeSuccessRate), Current: alerting.FormatMetric(alerting.MetricHandshakeSuccessRate, current.HandshakeSuccessRate)},
+		{Label: "Unique peers", Baseline: fmt.Sprint(baseline.UniquePeers), Current: fmt.Sprint(current.UniquePeers)},
+	}
+
+	return map[string]interface{}{
+		"GeneratedAt":     generatedAt,
+		"ValidationMode":  current.ValidationMode,
+		"BaselineVersion": baseline.HermesVersion,
+		"CurrentVersion":  current.HermesVersion,
+		"BaselineReport":  filepath.Base(baselineReport),
+		"HermesMetrics":   hermesMetrics,
+		"Summary":         summary,
+		"Regressions":     regressions,
+		"Regressed":       comparison.HasRegressions(),
+	}
+}
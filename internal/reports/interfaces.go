@@ -1,16 +1,36 @@
 package reports
 
 import (
+	"context"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/analyzers"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconfetch"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconpeers"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
+	"github.com/ethpandaops/hermes-peer-score/internal/canary"
+	"github.com/ethpandaops/hermes-peer-score/internal/clockskew"
+	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+	"github.com/ethpandaops/hermes-peer-score/internal/reachability"
+	"github.com/ethpandaops/hermes-peer-score/internal/recommend"
+	"github.com/ethpandaops/hermes-peer-score/internal/resources"
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
 )
 
 // Generator defines the interface for report generation.
 type Generator interface {
 	GenerateJSON(report *Report) (string, error)
-	GenerateHTML(report *Report) (string, error)
-	GenerateHTMLWithAI(report *Report, apiKey string) (string, error)
+	GenerateLiteJSON(report *Report) (string, error)
+	GenerateMarkdownSummary(report *Report) (string, error)
+	GenerateGrafanaJSON(report *Report) (string, error)
+	GenerateOpenMetrics(report *Report) (string, error)
+	GenerateHTML(ctx context.Context, report *Report) (string, error)
+	GenerateHTMLWithAI(ctx context.Context, report *Report, apiKey string) (string, error)
+	GenerateManifest(report *Report, runErr error) (*Manifest, string, error)
 }
 
 // TemplateManager defines the interface for template management.
@@ -30,18 +50,56 @@ type FileManager interface {
 
 // Report represents the comprehensive analysis results from a peer scoring test.
 type Report struct {
-	Config               interface{}               `json:"config"`
-	ValidationMode       string                    `json:"validation_mode"`
-	ValidationConfig     interface{}               `json:"validation_config"`
-	Timestamp            time.Time                 `json:"timestamp"`
-	StartTime            time.Time                 `json:"start_time"`
-	EndTime              time.Time                 `json:"end_time"`
-	Duration             time.Duration             `json:"duration"`
-	TotalConnections     int                       `json:"total_connections"`
-	SuccessfulHandshakes int                       `json:"successful_handshakes"`
-	FailedHandshakes     int                       `json:"failed_handshakes"`
-	Peers                map[string]interface{}    `json:"peers"`
-	PeerEventCounts      map[string]map[string]int `json:"peer_event_counts"`
+	Config               interface{}                    `json:"config"`
+	ValidationMode       string                         `json:"validation_mode"`
+	ValidationConfig     interface{}                    `json:"validation_config"`
+	AgentVersion         string                         `json:"agent_version,omitempty"`
+	MeshDegree           *config.MeshDegree             `json:"mesh_degree,omitempty"` // Unset in reports written before it was recorded
+	Timestamp            time.Time                      `json:"timestamp"`
+	StartTime            time.Time                      `json:"start_time"`
+	EndTime              time.Time                      `json:"end_time"`
+	Duration             time.Duration                  `json:"duration"`
+	TotalConnections     int                            `json:"total_connections"`
+	SuccessfulHandshakes int                            `json:"successful_handshakes"`
+	FailedHandshakes     int                            `json:"failed_handshakes"`
+	ReconciledHandshakes *peer.ReconciledHandshakeStats `json:"reconciled_handshakes,omitempty"`
+	DataQuality          *peer.DataQualityStats         `json:"data_quality,omitempty"`
+	Reachability         *reachability.Result           `json:"reachability,omitempty"`
+	Subscriptions        *peer.SubscriptionReport       `json:"subscriptions,omitempty"`
+	BeaconPeers          *beaconpeers.Result            `json:"beacon_peers,omitempty"`
+	BeaconFetches        *beaconfetch.Summary           `json:"beacon_fetches,omitempty"`
+	BeaconSync           *beaconsync.Summary            `json:"beacon_sync,omitempty"`
+	Canary               *canary.Result                 `json:"canary,omitempty"`
+	ScoreConsensus       *peer.ConsensusView            `json:"score_consensus,omitempty"`
+	ClockSkew            *clockskew.Result              `json:"clock_skew,omitempty"`
+	Sampling             *peer.SamplingSummary          `json:"sampling,omitempty"`
+	EventSampling        *peer.EventSampling            `json:"event_sampling,omitempty"`
+	PeerPressure         *peer.PeerPressure             `json:"peer_pressure,omitempty"`
+	Resources            *resources.Summary             `json:"resources,omitempty"`
+	Shutdown             *peer.ShutdownTeardown         `json:"shutdown,omitempty"`
+	SessionSurvival      *peer.SessionSurvival          `json:"session_survival,omitempty"`
+	TopicHealth          *peer.TopicHealthSummary       `json:"topic_health,omitempty"`
+	InvalidDeliveries    *peer.InvalidDeliveries        `json:"invalid_deliveries,omitempty"`
+	RouterMetrics        *peer.RouterMetrics            `json:"router_metrics,omitempty"`
+	StatusTracking       *peer.StatusTracking           `json:"status_tracking,omitempty"`
+	HeadDivergence       *peer.HeadDivergence           `json:"head_divergence,omitempty"`
+	Peers                map[string]interface{}         `json:"peers"`
+	PeerEventCounts      map[string]map[string]int      `json:"peer_event_counts"`
+	EventTimeline        *peer.EventTimeline            `json:"event_timeline,omitempty"`
+	Phases               *peer.RunPhases                `json:"phases,omitempty"`
+	Gaps                 []peer.RunGap                  `json:"gaps,omitempty"`
+	Starvation           []watchdog.Window              `json:"starvation,omitempty"`
+	TimeSlices           []peer.TimeSlice               `json:"time_slices,omitempty"`
+	Epochs               []peer.EpochSlice              `json:"epochs,omitempty"`
+	TopicWhitelist       *peer.TopicWhitelist           `json:"topic_whitelist,omitempty"`
+	PeerOverlap          *peer.PeerOverlap              `json:"peer_overlap,omitempty"`
+	NegotiationFailures  *peer.NegotiationFailures      `json:"negotiation_failures,omitempty"`
+	BootstrapNodes       []peer.BootstrapNodeStatus     `json:"bootstrap_nodes,omitempty"`
+	MaxPeersRamp         *peer.MaxPeersRamp             `json:"max_peers_ramp,omitempty"`
+	Analyses             []analyzers.Section            `json:"analyses,omitempty"` // Custom analyzers' sections, by analyzer name
+	Hosts                []peer.HostSummary             `json:"hosts,omitempty"`
+	Recommendations      *recommend.Profile             `json:"recommendations,omitempty"` // Configuration suggested for the next run
+	PrunePolicy          *prune.Policy                  `json:"prune_policy,omitempty"`    // Fields dropped or hashed before the report was written
 }
 
 // AIAnalyzer defines the interface for AI-powered analysis.
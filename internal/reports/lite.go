@@ -0,0 +1,197 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+)
+
+// LiteSchemaVersion is the version of the lite report layout. Fields are only ever added
+// within a version, a change of meaning or a removal bumps it.
+const LiteSchemaVersion = 1
+
+// LiteReport is a small, schema-stable summary of a run for Slack bots, CI comments and the
+// trends database, so they need not parse the full report.
+type LiteReport struct {
+	SchemaVersion     int                          `json:"schema_version"`
+	ValidationMode    string                       `json:"validation_mode"`
+	Network           string                       `json:"network,omitempty"`
+	HermesVersion     string                       `json:"hermes_version,omitempty"`
+	AgentVersion      string                       `json:"agent_version,omitempty"`
+	StartTime         time.Time                    `json:"start_time"`
+	EndTime           time.Time                    `json:"end_time"`
+	DurationSeconds   float64                      `json:"duration_seconds"`
+	Summary           LiteSummary                  `json:"summary"`
+	Clients           []peer.ClientSummary         `json:"clients"`            // Largest client first
+	DisconnectReasons []peer.DisconnectReasonCount `json:"disconnect_reasons"` // Most frequent goodbye codes and reasons, and our own terminations
+	DataQuality       *LiteDataQuality             `json:"data_quality,omitempty"`
+	PrunePolicy       *prune.Policy                `json:"prune_policy,omitempty"`   // Fields dropped or hashed before the report was written
+	EventSampling     *peer.EventSampling          `json:"event_sampling,omitempty"` // Event types recorded one in N, with the factors their counts scale by
+}
+
+// LiteSummary holds the headline numbers of a run. Connections and handshakes count the
+// measurement window only, as in the full report.
+type LiteSummary struct {
+	UniquePeers          int     `json:"unique_peers"`
+	TotalConnections     int     `json:"total_connections"`
+	SuccessfulHandshakes int     `json:"successful_handshakes"`
+	FailedHandshakes     int     `json:"failed_handshakes"`
+	HandshakeSuccessRate float64 `json:"handshake_success_rate"`
+	Sessions             int     `json:"sessions"`
+	Disconnects          int     `json:"disconnects"`
+	GoodbyeEvents        int     `json:"goodbye_events"`
+
+	// Topics with invalid message deliveries across several peers, a likely Hermes bug
+	InvalidDeliveryTopics int `json:"invalid_delivery_topics"`
+
+	// Gossip topics flagged degraded or unhealthy across peers
+	DegradedTopics  int `json:"degraded_topics"`
+	UnhealthyTopics int `json:"unhealthy_topics"`
+
+	// Our clock was skewed from the beacon node's or the peers', timeliness-sensitive scores are unreliable
+	ClockSkewed bool `json:"clock_skewed"`
+
+	// Health of independent validation's beacon data fetches, empty in delegated mode
+	BeaconFetchHealth string `json:"beacon_fetch_health,omitempty"`
+
+	// Health of delegated validation's beacon node, empty in independent mode
+	BeaconSyncHealth string `json:"beacon_sync_health,omitempty"`
+
+	// Outcome of the canary self-test, empty when no canaries ran
+	CanaryStatus string `json:"canary_status,omitempty"`
+
+	// Total time no events arrived while the run was active, the node was probably wedged
+	StarvedSeconds float64 `json:"starved_seconds"`
+}
+
+// LiteDataQuality holds the data quality counters, without the per-type breakdowns.
+type LiteDataQuality struct {
+	EventsChecked      int     `json:"events_checked"`
+	MissingTimestamps  int     `json:"missing_timestamps"`
+	OutOfOrderEvents   int     `json:"out_of_order_events"`
+	MaxLagSeconds      float64 `json:"max_lag_seconds"`
+	UnhandledEvents    int     `json:"unhandled_events"`
+	LateEventsAssigned int     `json:"late_events_assigned"`
+	LateEventsDropped  int     `json:"late_events_dropped"`
+}
+
+// BuildLiteReport summarises the report into its lite form.
+func BuildLiteReport(report *Report) *LiteReport {
+	headline := CalculateHeadline(report)
+
+	lite := &LiteReport{
+		SchemaVersion:     LiteSchemaVersion,
+		ValidationMode:    report.ValidationMode,
+		AgentVersion:      report.AgentVersion,
+		StartTime:         report.StartTime,
+		EndTime:           report.EndTime,
+		DurationSeconds:   report.Duration.Seconds(),
+		Clients:           headline.Clients,
+		DisconnectReasons: headline.DisconnectReasons,
+		PrunePolicy:       report.PrunePolicy,
+		EventSampling:     report.EventSampling,
+		Summary: LiteSummary{
+			UniquePeers:          headline.UniquePeers,
+			TotalConnections:     headline.TotalConnections,
+			SuccessfulHandshakes: headline.SuccessfulHandshakes,
+			FailedHandshakes:     headline.FailedHandshakes,
+			HandshakeSuccessRate: headline.HandshakeSuccessRate,
+			Sessions:             headline.Sessions,
+			Disconnects:          headline.Disconnects,
+			GoodbyeEvents:        headline.GoodbyeEvents,
+		},
+	}
+
+	lite.Network, lite.HermesVersion = runVersions(report)
+
+	if report.InvalidDeliveries != nil {
+		lite.Summary.InvalidDeliveryTopics = len(report.InvalidDeliveries.Anomalies)
+	}
+
+	if report.TopicHealth != nil {
+		lite.Summary.DegradedTopics = report.TopicHealth.Degraded
+		lite.Summary.UnhealthyTopics = report.TopicHealth.Unhealthy
+	}
+
+	if report.ClockSkew != nil {
+		lite.Summary.ClockSkewed = report.ClockSkew.Skewed
+	}
+
+	if report.BeaconFetches != nil {
+		lite.Summary.BeaconFetchHealth = report.BeaconFetches.Health
+	}
+
+	if report.BeaconSync != nil {
+		lite.Summary.BeaconSyncHealth = report.BeaconSync.Health
+	}
+
+	if report.Canary != nil {
+		lite.Summary.CanaryStatus = report.Canary.Status
+	}
+
+	for _, window := range report.Starvation {
+		lite.Summary.StarvedSeconds += window.Seconds
+	}
+
+	if quality := report.DataQuality; quality != nil {
+		lite.DataQuality = &LiteDataQuality{
+			EventsChecked:      quality.EventsChecked,
+			MissingTimestamps:  quality.MissingTimestamps,
+			OutOfOrderEvents:   quality.OutOfOrderEvents,
+			MaxLagSeconds:      quality.MaxLagSeconds,
+			UnhandledEvents:    quality.UnhandledEvents,
+			LateEventsAssigned: quality.LateEventsAssigned,
+			LateEventsDropped:  quality.LateEventsDropped,
+		}
+	}
+
+	return lite
+}
+
+// runVersions returns the network and Hermes version a report was run with, empty when unknown.
+func runVersions(report *Report) (network, hermesVersion string) {
+	if cfg, ok := report.Config.(map[string]interface{}); ok {
+		network, _ = cfg["network"].(string)
+	}
+
+	if validationConfig, ok := report.ValidationConfig.(map[string]interface{}); ok {
+		hermesVersion, _ = validationConfig["HermesVersion"].(string)
+	}
+
+	return network, hermesVersion
+}
+
+// GenerateLiteJSON writes the lite report next to the full JSON report. It fails rather
+// than write a file over the size consumers rely on.
+func (g *DefaultGenerator) GenerateLiteJSON(report *Report) (string, error) {
+	report, err := g.pruneReport(report)
+	if err != nil {
+		return "", err
+	}
+
+	liteJSON, err := json.MarshalIndent(BuildLiteReport(report), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal lite report: %w", err)
+	}
+
+	if len(liteJSON) > constants.LiteReportMaxBytes {
+		return "", fmt.Errorf("lite report is %d bytes, over the %d byte limit", len(liteJSON), constants.LiteReportMaxBytes)
+	}
+
+	liteJSON = []byte(g.redactor.String(string(liteJSON)))
+
+	filename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultLiteReportFile, report.Timestamp)
+
+	if err := g.fileManager.SaveJSON(filename, liteJSON); err != nil {
+		return "", fmt.Errorf("failed to save lite report: %w", err)
+	}
+
+	g.recordArtifact(ArtifactLite, filename)
+	g.logger.WithField("filename", filename).Info("Lite JSON report generated successfully")
+
+	return filename, nil
+}
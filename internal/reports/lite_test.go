@@ -0,0 +1,225 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/clockskew"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
+)
+
+func TestBuildLiteReport(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	connectedAt := start.Add(time.Minute)
+	disconnectedAt := start.Add(2 * time.Minute)
+
+	report := &Report{
+		Config:               map[string]interface{}{"network": "hoodi"},
+		ValidationMode:       "delegated",
+		ValidationConfig:     map[string]interface{}{"HermesVersion": "v0.0.4"},
+		StartTime:            start,
+		EndTime:              start.Add(10 * time.Minute),
+		Duration:             10 * time.Minute,
+		SuccessfulHandshakes: 3,
+		FailedHandshakes:     1,
+		DataQuality:          &peer.DataQualityStats{EventsChecked: 100, OutOfOrderEvents: 2, LateEventsDroppedByType: map[string]int{"GOODBYE": 1}},
+		Peers: map[string]interface{}{
+			"a": &peer.Stats{ClientType: constants.Lighthouse, ConnectionSessions: []peer.ConnectionSession{{
+				ConnectedAt: &connectedAt, DisconnectedAt: &disconnectedAt, Disconnected: true,
+				GoodbyeEvents: []peer.GoodbyeEvent{{Code: 129, Reason: "too many peers"}},
+			}}},
+			"b": &peer.Stats{ClientType: constants.Lighthouse, ConnectionSessions: []peer.ConnectionSession{{
+				ConnectedAt:       &connectedAt,
+				LocalTerminations: []peer.LocalTermination{{Kind: peer.TerminationMalformed, Protocol: "HANDLE_STATUS", Reason: "read request data: invalid snappy"}},
+			}}},
+			"c": map[string]interface{}{"client_type": constants.Teku, "connection_sessions": []interface{}{map[string]interface{}{}}},
+		},
+	}
+
+	lite := BuildLiteReport(report)
+
+	if lite.SchemaVersion != LiteSchemaVersion || lite.Network != "hoodi" || lite.HermesVersion != "v0.0.4" {
+		t.Errorf("Unexpected run details %+v", lite)
+	}
+
+	want := LiteSummary{UniquePeers: 3, SuccessfulHandshakes: 3, FailedHandshakes: 1, HandshakeSuccessRate: 0.75, Sessions: 3, Disconnects: 1, GoodbyeEvents: 1}
+	if lite.Summary != want {
+		t.Errorf("Summary = %+v, want %+v", lite.Summary, want)
+	}
+
+	if len(lite.Clients) != 2 || lite.Clients[0].Client != constants.Lighthouse || lite.Clients[0].Peers != 2 {
+		t.Errorf("Unexpected clients %+v", lite.Clients)
+	}
+
+	if len(lite.DisconnectReasons) != 2 || lite.DisconnectReasons[0] != (peer.DisconnectReasonCount{Side: peer.InitiatorRemote, Code: 129, Reason: "too many peers", Count: 1}) ||
+		lite.DisconnectReasons[1] != (peer.DisconnectReasonCount{Side: peer.InitiatorLocal, Reason: "reset HANDLE_STATUS stream: malformed_request", Count: 1}) {
+		t.Errorf("Unexpected disconnect reasons %+v", lite.DisconnectReasons)
+	}
+
+	if lite.DataQuality == nil || lite.DataQuality.EventsChecked != 100 || lite.DataQuality.OutOfOrderEvents != 2 {
+		t.Errorf("Unexpected data quality %+v", lite.DataQuality)
+	}
+}
+
+func TestBuildLiteReportRunHealth(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	abuser := &peer.Stats{PeerID: "16Uiu2HAmAbuser", ClientType: constants.Prysm}
+	abuser.RecordReqRespAbuse(peer.ReqRespAbuseRateLimit, "HANDLE_PING", "exceeded HANDLE_PING quota", start)
+	abuser.RecordReqRespAbuse(peer.ReqRespAbuseMalformed, "HANDLE_STATUS", "read request data *pb.Status: snappy: corrupt input", start)
+
+	report := &Report{
+		ValidationMode: "delegated",
+		StartTime:      start,
+		EndTime:        start.Add(time.Hour),
+		Duration:       time.Hour,
+		ClockSkew:      &clockskew.Result{ThresholdMs: 500, MaxOffsetMs: 2350, Skewed: true},
+		Starvation: []watchdog.Window{
+			{From: start.Add(10 * time.Minute), To: start.Add(20 * time.Minute), Seconds: 600, Resolved: true},
+			{From: start.Add(50 * time.Minute), To: start.Add(time.Hour), Seconds: 600},
+		},
+		Peers: map[string]interface{}{
+			abuser.PeerID:       abuser,
+			"16Uiu2HAmWellKept": &peer.Stats{PeerID: "16Uiu2HAmWellKept", ClientType: constants.Teku},
+		},
+	}
+
+	lite := BuildLiteReport(report)
+
+	if !lite.Summary.ClockSkewed {
+		t.Error("Expected the lite report to flag the clock skew")
+	}
+
+	if lite.Summary.StarvedSeconds != 1200 {
+		t.Errorf("Expected 1200 starved seconds, got %.0f", lite.Summary.StarvedSeconds)
+	}
+
+	for _, client := range lite.Clients {
+		want := 0
+		if client.Client == constants.Prysm {
+			want = 2
+		}
+
+		if client.ReqRespAbuse != want {
+			t.Errorf("Expected %d req/resp abuse occurrences for %s, got %d", want, client.Client, client.ReqRespAbuse)
+		}
+	}
+}
+
+func TestGenerateLiteJSON(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clients := []string{constants.Lighthouse, constants.Prysm, constants.Teku, constants.Nimbus, constants.Lodestar, constants.Grandine, constants.Unknown}
+
+	// A large run with many distinct goodbye reasons still yields a small lite report
+	peers := make(map[string]interface{})
+
+	for i := 0; i < 5000; i++ {
+		at := start.Add(time.Duration(i) * time.Second)
+		peers[fmt.Sprintf("peer-%d", i)] = &peer.Stats{
+			ClientType: clients[i%len(clients)],
+			ConnectionSessions: []peer.ConnectionSession{{
+				ConnectedAt:   &at,
+				GoodbyeEvents: []peer.GoodbyeEvent{{Code: uint64(i % 300), Reason: fmt.Sprintf("reason %d", i%300)}},
+			}},
+		}
+	}
+
+	report := &Report{ValidationMode: "delegated", Timestamp: start, StartTime: start, EndTime: start.Add(time.Hour), Peers: peers}
+
+	g, err := NewGenerator(logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating generator, got %v", err)
+	}
+
+	fm := NewMockFileManager()
+	g.SetFileManager(fm)
+
+	filename, err := g.GenerateLiteJSON(report)
+	if err != nil {
+		t.Fatalf("Expected no error generating lite report, got %v", err)
+	}
+
+	if filename != "peer-score-report-lite-delegated-2025-06-01_12-00-00.json" {
+		t.Errorf("Unexpected lite report filename %q", filename)
+	}
+
+	data := fm.files[filename]
+	if len(data) == 0 || len(data) > constants.LiteReportMaxBytes {
+		t.Fatalf("Expected a lite report under %d bytes, got %d", constants.LiteReportMaxBytes, len(data))
+	}
+
+	var decoded LiteReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	if decoded.Summary.UniquePeers != 5000 || len(decoded.Clients) != len(clients) || len(decoded.DisconnectReasons) != constants.LiteReportReasonLimit {
+		t.Errorf("Unexpected lite report contents: %d peers, %d clients, %d reasons",
+			decoded.Summary.UniquePeers, len(decoded.Clients), len(decoded.DisconnectReasons))
+	}
+}
+
+func TestBuildGrafanaReport(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	connectedAt := start.Add(time.Minute)
+
+	report := &Report{
+		Config:           map[string]interface{}{"network": "hoodi"},
+		ValidationMode:   "independent",
+		ValidationConfig: map[string]interface{}{"HermesVersion": "v0.0.4"},
+		StartTime:        start,
+		EndTime:          start.Add(10 * time.Minute),
+		Duration:         10 * time.Minute,
+		DataQuality:      &peer.DataQualityStats{EventsChecked: 100, OutOfOrderEvents: 2},
+		Peers: map[string]interface{}{
+			"a": &peer.Stats{ClientType: constants.Lighthouse, ConnectionSessions: []peer.ConnectionSession{{ConnectedAt: &connectedAt}}},
+		},
+	}
+
+	grafana := BuildGrafanaReport(report)
+
+	if grafana.SchemaVersion != GrafanaSchemaVersion || grafana.Network != "hoodi" || grafana.StartTime != "2025-06-01T12:00:00Z" ||
+		grafana.EndTimeMs != start.Add(10*time.Minute).UnixMilli() {
+		t.Errorf("Unexpected run details %+v", grafana)
+	}
+
+	lite := BuildLiteReport(report)
+	if grafana.UniquePeers != lite.Summary.UniquePeers || grafana.Sessions != 1 || grafana.EventsChecked != 100 || grafana.OutOfOrderEvents != 2 {
+		t.Errorf("Expected the lite report's numbers, got %+v", grafana)
+	}
+
+	if len(grafana.Clients) != 1 || grafana.DisconnectReasons == nil {
+		t.Errorf("Expected the client table and an empty reasons table, got %+v and %+v", grafana.Clients, grafana.DisconnectReasons)
+	}
+
+	// Panels read every field but the tables as one row, so nothing else may nest
+	data, err := json.Marshal(grafana)
+	if err != nil {
+		t.Fatalf("Expected the Grafana report to marshal, got %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Expected a JSON object, got %v", err)
+	}
+
+	for key, value := range fields {
+		switch value.(type) {
+		case map[string]interface{}:
+			t.Errorf("Expected a flat report, %s is an object", key)
+		case []interface{}:
+			if key != "clients" && key != "disconnect_reasons" {
+				t.Errorf("Expected only the tables as arrays, %s is one", key)
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package reports
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+)
+
+// ManifestSchemaVersion is the version of the run manifest layout.
+const ManifestSchemaVersion = 1
+
+// Artifact kinds listed in the run manifest.
+const (
+	ArtifactJSON             = "json"
+	ArtifactLite             = "lite_json"
+	ArtifactMarkdownSummary  = "markdown_summary"
+	ArtifactGrafana          = "grafana"
+	ArtifactOpenMetrics      = "openmetrics"
+	ArtifactHTML             = "html"
+	ArtifactData             = "data"
+	ArtifactShards           = "shards"
+	ArtifactSwimlanes        = "swimlanes"
+	ArtifactAIMarkdown       = "ai_markdown"
+	ArtifactAIText           = "ai_text"
+	ArtifactAIHTML           = "ai_html"
+	ArtifactHermesRegression = "hermes_regression"
+	ArtifactSignature        = "signature"
+	ArtifactErrors           = "errors"
+	ArtifactPeerTrace        = "peer_trace"
+)
+
+// Manifest lists the files a run wrote, so tooling can pick up its artifacts without
+// guessing filenames.
+type Manifest struct {
+	SchemaVersion  int                `json:"schema_version"`
+	ValidationMode string             `json:"validation_mode"`
+	Timestamp      time.Time          `json:"timestamp"`
+	GeneratedAt    time.Time          `json:"generated_at"`
+	Health         RunHealth          `json:"health"`
+	Artifacts      []ManifestArtifact `json:"artifacts"`              // In the order they were written
+	PrunePolicy    *prune.Policy      `json:"prune_policy,omitempty"` // Fields dropped or hashed in every artifact
+}
+
+// ManifestArtifact is one file or directory written by a run.
+type ManifestArtifact struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`   // Slash separated
+	Bytes  int64  `json:"bytes"`  // Summed over the files of a directory
+	SHA256 string `json:"sha256"` // Hex, of a directory's file paths and digests for a directory
+}
+
+// recordArtifact notes a file or directory written for the run manifest.
+func (g *DefaultGenerator) recordArtifact(kind, path string) {
+	g.artifacts = append(g.artifacts, ManifestArtifact{Kind: kind, Path: path})
+}
+
+// AddArtifact lists a file the run wrote outside the generator in the manifest.
+func (g *DefaultGenerator) AddArtifact(kind, path string) {
+	g.recordArtifact(kind, path)
+}
+
+// GenerateManifest writes the manifest of the artifacts generated so far next to the reports,
+// graded by the run's health. runErr is the error that stopped report generation, if any.
+// Artifacts no longer on disk, such as a data file renamed partial, are left out. With signing
+// configured the manifest is signed last, its own signature is not listed in it.
+func (g *DefaultGenerator) GenerateManifest(report *Report, runErr error) (*Manifest, string, error) {
+	manifest := &Manifest{
+		SchemaVersion:  ManifestSchemaVersion,
+		ValidationMode: report.ValidationMode,
+		Timestamp:      report.Timestamp,
+		GeneratedAt:    g.clock(),
+		Health:         gradeRun(report, g.errors.Counts(), g.aiStatus, runErr),
+		Artifacts:      make([]ManifestArtifact, 0, len(g.artifacts)),
+		PrunePolicy:    g.prunePolicy,
+	}
+
+	for _, artifact := range g.artifacts {
+		size, digest, err := artifactDigest(artifact.Path)
+		if err != nil {
+			g.logger.WithError(err).WithField("path", artifact.Path).Debug("Leaving missing artifact out of manifest")
+
+			continue
+		}
+
+		// Slash separated, so a manifest written on Windows reads the same elsewhere
+		artifact.Path = filepath.ToSlash(artifact.Path)
+		artifact.Bytes = size
+		artifact.SHA256 = digest
+		manifest.Artifacts = append(manifest.Artifacts, artifact)
+	}
+
+	filename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultManifestFile, report.Timestamp)
+
+	if err := g.fileManager.SaveJSON(filename, manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to save manifest: %w", err)
+	}
+
+	if g.signer != nil {
+		if _, err := g.signer.Sign(filename); err != nil {
+			return nil, "", fmt.Errorf("failed to sign manifest: %w", err)
+		}
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"filename":  filename,
+		"artifacts": len(manifest.Artifacts),
+		"health":    manifest.Health.Grade,
+	}).Info("Run manifest generated successfully")
+
+	return manifest, filename, nil
+}
+
+// artifactDigest returns the size and hex SHA-256 digest of a file. A directory's size is
+// summed over its files, and its digest is taken over the slash separated path below it and
+// digest of each file in lexical order, so a file added, removed, renamed or changed shows.
+func artifactDigest(path string) (int64, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if !info.IsDir() {
+		digest, err := fileDigest(path)
+
+		return info.Size(), digest, err
+	}
+
+	var size int64
+
+	tree := sha256.New()
+
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		digest, err := fileDigest(file)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+		fmt.Fprintf(tree, "%s %s\n", filepath.ToSlash(rel), digest)
+
+		return nil
+	})
+
+	return size, hex.EncodeToString(tree.Sum(nil)), err
+}
+
+// fileDigest returns the hex SHA-256 digest of a file's content.
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// IsManifestFile reports whether path names a run manifest, rather than its signature.
+func IsManifestFile(path string) bool {
+	ext := filepath.Ext(constants.DefaultManifestFile)
+	base := filepath.Base(path)
+
+	return strings.HasPrefix(base, strings.TrimSuffix(constants.DefaultManifestFile, ext)+"-") &&
+		strings.HasSuffix(base, ext) && !strings.HasSuffix(base, constants.SigstoreBundleSuffix)
+}
+
+// VerifyManifestArtifacts hashes every artifact a manifest lists again, resolved against the
+// manifest's directory where the run wrote them, and returns how many matched. The error names
+// each artifact that is missing, changed or has no digest, as in manifests written before
+// digests were recorded.
+func VerifyManifestArtifacts(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return 0, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	verified := 0
+	problems := make([]string, 0)
+
+	for _, artifact := range manifest.Artifacts {
+		if artifact.SHA256 == "" {
+			problems = append(problems, artifact.Path+" has no digest")
+
+			continue
+		}
+
+		_, digest, err := artifactDigest(filepath.Join(filepath.Dir(path), filepath.FromSlash(artifact.Path)))
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s cannot be read: %v", artifact.Path, err))
+		case digest != artifact.SHA256:
+			problems = append(problems, artifact.Path+" changed since the manifest was written")
+		default:
+			verified++
+		}
+	}
+
+	if len(problems) > 0 {
+		return verified, fmt.Errorf("%d of %d artifacts failed: %s", len(problems), len(manifest.Artifacts), strings.Join(problems, "; "))
+	}
+
+	return verified, nil
+}
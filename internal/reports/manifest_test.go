@@ -0,0 +1,225 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestGenerateManifest(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	g, err := NewGenerator(logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating generator, got %v", err)
+	}
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &Report{
+		ValidationMode:   "delegated",
+		ValidationConfig: map[string]interface{}{"HermesVersion": "test"},
+		Timestamp:        start,
+		StartTime:        start,
+		EndTime:          start.Add(time.Minute),
+		Duration:         time.Minute,
+		TotalConnections: 1,
+		Peers:            map[string]interface{}{"16Uiu2HAmBBBBqrs": map[string]interface{}{}},
+		DataQuality:      &peer.DataQualityStats{},
+	}
+
+	if _, err := g.GenerateJSON(report); err != nil {
+		t.Fatalf("Expected no error generating JSON, got %v", err)
+	}
+
+	analysis := "<h2>Summary</h2><p>[peer:16Uiu2HAmBBB] was dropped, see [section:data-quality].</p>"
+
+	htmlFile, err := g.generateHTMLReport(context.Background(), report, analysis)
+	if err != nil {
+		t.Fatalf("Expected no error generating HTML, got %v", err)
+	}
+
+	markdown, err := os.ReadFile(aiExportFilename(htmlFile, AIExportMarkdown))
+	if err != nil {
+		t.Fatalf("Expected a markdown export, got %v", err)
+	}
+
+	if want := "## Summary\n\n16Uiu2HAmBBBBqrs was dropped, see Data Quality.\n"; string(markdown) != want {
+		t.Errorf("Markdown export: got %q, want %q", markdown, want)
+	}
+
+	_, filename, err := g.GenerateManifest(report, nil)
+	if err != nil {
+		t.Fatalf("Expected no error generating manifest, got %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Expected the manifest to be written, got %v", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("Expected a valid manifest, got %v", err)
+	}
+
+	if manifest.SchemaVersion != ManifestSchemaVersion || manifest.ValidationMode != "delegated" {
+		t.Errorf("Unexpected manifest header %+v", manifest)
+	}
+
+	if manifest.Health.Grade != HealthOK || manifest.Health.AIStatus != AIStatusSkipped {
+		t.Errorf("Expected an OK run without AI analysis, got %+v", manifest.Health)
+	}
+
+	kinds := make([]string, 0, len(manifest.Artifacts))
+
+	for _, artifact := range manifest.Artifacts {
+		kinds = append(kinds, artifact.Kind)
+
+		if artifact.Bytes <= 0 {
+			t.Errorf("Expected %s to have a size, got %d", artifact.Path, artifact.Bytes)
+		}
+
+		if len(artifact.SHA256) != 64 {
+			t.Errorf("Expected %s to have a SHA-256 digest, got %q", artifact.Path, artifact.SHA256)
+		}
+	}
+
+	want := []string{ArtifactJSON, ArtifactHTML, ArtifactData, ArtifactAIMarkdown, ArtifactAIText, ArtifactAIHTML}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Errorf("Artifacts: got %v, want %v", kinds, want)
+	}
+}
+
+func TestVerifyManifestArtifacts(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	g, err := NewGenerator(logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating generator, got %v", err)
+	}
+
+	files := map[string]string{
+		"report.html":          "<html></html>",
+		"shards/index.json":    `{"shards":1}`,
+		"shards/shard-0.json":  `{"peers":{}}`,
+		"shards/nested/a.json": `{}`,
+	}
+
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("Expected no error creating %s, got %v", filepath.Dir(name), err)
+		}
+
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatalf("Expected no error writing %s, got %v", name, err)
+		}
+	}
+
+	g.AddArtifact(ArtifactHTML, "report.html")
+	g.AddArtifact(ArtifactShards, "shards")
+
+	_, filename, err := g.GenerateManifest(&Report{ValidationMode: "delegated"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error generating manifest, got %v", err)
+	}
+
+	if !IsManifestFile(filename) || IsManifestFile(filename+".sig") || IsManifestFile("report.html") {
+		t.Errorf("Expected only %s to be taken for a manifest", filename)
+	}
+
+	if verified, err := VerifyManifestArtifacts(filename); err != nil || verified != 2 {
+		t.Fatalf("Expected both artifacts to match, got %d and %v", verified, err)
+	}
+
+	// A changed file, inside a directory artifact or not, no longer matches
+	for _, name := range []string{"report.html", "shards/nested/a.json"} {
+		if err := os.WriteFile(name, []byte("tampered"), 0o644); err != nil {
+			t.Fatalf("Expected no error changing %s, got %v", name, err)
+		}
+	}
+
+	verified, err := VerifyManifestArtifacts(filename)
+	if err == nil || verified != 0 || !strings.Contains(err.Error(), "report.html changed") || !strings.Contains(err.Error(), "shards changed") {
+		t.Errorf("Expected both changed artifacts to fail, got %d and %v", verified, err)
+	}
+
+	// Restored, they match again, until a file is added to the directory
+	for _, name := range []string{"report.html", "shards/nested/a.json"} {
+		if err := os.WriteFile(name, []byte(files[name]), 0o644); err != nil {
+			t.Fatalf("Expected no error restoring %s, got %v", name, err)
+		}
+	}
+
+	if verified, err := VerifyManifestArtifacts(filename); err != nil || verified != 2 {
+		t.Fatalf("Expected the restored artifacts to match, got %d and %v", verified, err)
+	}
+
+	if err := os.WriteFile("shards/shard-1.json", []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("Expected no error adding a shard, got %v", err)
+	}
+
+	if verified, err := VerifyManifestArtifacts(filename); err == nil || verified != 1 || !strings.Contains(err.Error(), "shards changed") {
+		t.Errorf("Expected the shards to fail after a file was added, got %d and %v", verified, err)
+	}
+}
+
+// recordingSigner records the files it is asked to sign.
+type recordingSigner struct {
+	signed []string
+}
+
+func (s *recordingSigner) Sign(path string) (string, error) {
+	s.signed = append(s.signed, path)
+
+	return path + ".sig", os.WriteFile(path+".sig", []byte("signature"), 0o600)
+}
+
+func TestSignedArtifacts(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	g, err := NewGenerator(logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating generator, got %v", err)
+	}
+
+	signer := &recordingSigner{}
+	g.SetSigner(signer)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &Report{ValidationMode: "delegated", Timestamp: start, TotalConnections: 1, Peers: map[string]interface{}{}}
+
+	jsonFile, err := g.GenerateJSON(report)
+	if err != nil {
+		t.Fatalf("Expected no error generating JSON, got %v", err)
+	}
+
+	manifest, manifestFile, err := g.GenerateManifest(report, nil)
+	if err != nil {
+		t.Fatalf("Expected no error generating manifest, got %v", err)
+	}
+
+	if len(signer.signed) != 2 || signer.signed[0] != jsonFile || signer.signed[1] != manifestFile {
+		t.Errorf("Expected the JSON report and then the manifest to be signed, got %v", signer.signed)
+	}
+
+	if len(manifest.Artifacts) != 2 || manifest.Artifacts[1].Kind != ArtifactSignature || manifest.Artifacts[1].Path != jsonFile+".sig" {
+		t.Errorf("Expected the report signature to be listed in the manifest, got %+v", manifest.Artifacts)
+	}
+}
@@ -0,0 +1,228 @@
+package reports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// RenderMarkdownSummary renders a concise run summary as markdown, short enough for a commit
+// or pull request comment. Its numbers come from CalculateHeadline, as the other reports' do.
+func RenderMarkdownSummary(report *Report) string {
+	headline := CalculateHeadline(report)
+	network, hermesVersion := runVersions(report)
+
+	var b strings.Builder
+
+	b.WriteString("## Peer score run summary\n\n")
+
+	fmt.Fprintf(&b, "Validation mode `%s`", report.ValidationMode)
+
+	if network != "" {
+		fmt.Fprintf(&b, " on `%s`", network)
+	}
+
+	if hermesVersion != "" {
+		fmt.Fprintf(&b, " with Hermes `%s`", hermesVersion)
+	}
+
+	fmt.Fprintf(&b, ", %s from %s.\n\n", report.Duration.Round(time.Second), report.StartTime.UTC().Format(time.RFC3339))
+
+	b.WriteString("| Metric | Value |\n")
+	b.WriteString("| --- | --- |\n")
+	fmt.Fprintf(&b, "| Unique peers | %d |\n", headline.UniquePeers)
+	fmt.Fprintf(&b, "| Total connections | %d |\n", headline.TotalConnections)
+	fmt.Fprintf(&b, "| Successful handshakes | %d |\n", headline.SuccessfulHandshakes)
+	fmt.Fprintf(&b, "| Failed handshakes | %d |\n", headline.FailedHandshakes)
+	fmt.Fprintf(&b, "| Handshake success rate | %.1f%% |\n", headline.HandshakeSuccessRate*100)
+	fmt.Fprintf(&b, "| Sessions | %d |\n", headline.Sessions)
+
+	if survival := report.SessionSurvival; survival != nil {
+		fmt.Fprintf(&b, "| Censored sessions | %d |\n", survival.Censored)
+	}
+
+	fmt.Fprintf(&b, "| Disconnects | %d |\n", headline.Disconnects)
+	fmt.Fprintf(&b, "| Goodbye events | %d |\n", headline.GoodbyeEvents)
+
+	if len(headline.Clients) > 0 {
+		b.WriteString("\n### Clients\n\n")
+		b.WriteString("| Client | Peers | Sessions | Disconnects | Handshakes | Median score |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+
+		for i, client := range headline.Clients {
+			if i == constants.MarkdownSummaryClientLimit {
+				fmt.Fprintf(&b, "| %d more | | | | | |\n", len(headline.Clients)-i)
+
+				break
+			}
+
+			median := "-"
+			if client.ScoredPeers > 0 {
+				median = fmt.Sprintf("%.2f", client.MedianScore)
+			}
+
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %d ok, %d failed | %s |\n",
+				markdownCell(client.Client), client.Peers, client.Sessions, client.Disconnects,
+				client.SuccessfulHandshakes, client.FailedHandshakes, median)
+		}
+	}
+
+	if bands := headline.ScoreBands; bands != nil && bands.Peers > 0 {
+		b.WriteString("\n### Peer scores\n\n")
+		fmt.Fprintf(&b, "%d peers scored from %d snapshots. Median of each peer's mean score %.2f, of its lowest score %.2f. ",
+			bands.Peers, bands.Snapshots, bands.Mean.P50, bands.Min.P50)
+		fmt.Fprintf(&b, "Fell below the gossip threshold: %d, the publish threshold: %d, the graylist threshold: %d.\n",
+			bands.BelowGossip, bands.BelowPublish, bands.BelowGraylist)
+	}
+
+	if len(headline.WorstScored) > 0 {
+		b.WriteString("\n### Worst scored peers\n\n")
+		b.WriteString("| Peer | Client | Mean score over time | Area below zero | Below publish threshold |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+		for _, scored := range headline.WorstScored {
+			fmt.Fprintf(&b, "| `%s` | %s | %.2f | %.1f | %.0fs |\n",
+				scored.PeerID, markdownCell(scored.ClientType), scored.TimeWeightedMean, scored.AreaBelowZero, scored.SecondsBelowPublish)
+		}
+	}
+
+	if health := report.TopicHealth; health != nil && len(health.Topics) > 0 {
+		b.WriteString("\n### Topic health\n\n")
+		fmt.Fprintf(&b, "%d healthy, %d degraded, %d unhealthy.\n\n", health.Healthy, health.Degraded, health.Unhealthy)
+		b.WriteString("| Topic | Health | Mean mesh | Mesh over the run | Mean first deliveries | Mean mesh deliveries | Invalid deliveries |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+
+		for i, topic := range health.Topics {
+			if i == constants.MarkdownSummaryTopicLimit {
+				fmt.Fprintf(&b, "| %d more | | | | | | |\n", len(health.Topics)-i)
+
+				break
+			}
+
+			status := topic.Health
+			if len(topic.Reasons) > 0 {
+				status += ": " + strings.Join(topic.Reasons, ", ")
+			}
+
+			mesh, sparkline := "-", "-"
+			if topic.MeshSampled {
+				mesh = fmt.Sprintf("%.1f", topic.MeanMesh)
+				sparkline = topic.MeshSparkline()
+			}
+
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %.2f | %.2f | %.1f |\n",
+				markdownTopic(topic.Topic), markdownCell(status), mesh, sparkline,
+				topic.MeanFirstDeliveries, topic.MeanMeshDeliveries, topic.InvalidDeliveries)
+		}
+	}
+
+	if len(headline.DisconnectReasons) > 0 {
+		b.WriteString("\n### Disconnect reasons\n\n")
+		b.WriteString("| Initiated by | Code | Reason | Count |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+
+		for _, reason := range headline.DisconnectReasons {
+			side, code := "peer", strconv.FormatUint(reason.Code, 10)
+			if reason.Side == peer.InitiatorLocal {
+				side, code = "us", "-"
+			}
+
+			fmt.Fprintf(&b, "| %s | %s | %s | %d |\n", side, code, markdownCell(reason.Reason), reason.Count)
+		}
+	}
+
+	if policy := report.PrunePolicy; policy != nil {
+		b.WriteString("\n### Field pruning\n\n")
+		fmt.Fprintf(&b, "Dropped: %s. Hashed: %s.\n", markdownPaths(policy.Drop), markdownPaths(policy.Hash))
+	}
+
+	if sampling := report.EventSampling; sampling != nil {
+		b.WriteString("\n### Event sampling\n\n")
+		b.WriteString("| Event | Rate | Seen | Recorded | Scale by |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+		for _, sampled := range sampling.Types {
+			fmt.Fprintf(&b, "| %s | 1 in %d | %d | %d | %.2f |\n", sampled.EventType, sampled.Rate, sampled.Seen, sampled.Recorded, sampled.Factor)
+		}
+	}
+
+	if quality := report.DataQuality; quality != nil {
+		b.WriteString("\n### Data quality\n\n")
+		fmt.Fprintf(&b, "%d events checked, %d out of order, %d without a timestamp, %d unhandled. %d late events assigned, %d dropped.\n",
+			quality.EventsChecked, quality.OutOfOrderEvents, quality.MissingTimestamps, quality.UnhandledEvents,
+			quality.LateEventsAssigned, quality.LateEventsDropped)
+	}
+
+	if profile := report.Recommendations; profile != nil {
+		b.WriteString("\n### Recommended configuration\n\n")
+		b.WriteString("| Setting | Current | Suggested | Why |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+
+		for _, recommendation := range profile.Recommendations {
+			suggested := "keep"
+			if recommendation.Change {
+				suggested = "**" + markdownCell(recommendation.Suggested) + "**"
+			}
+
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", recommendation.Setting, markdownCell(recommendation.Current),
+				suggested, markdownCell(recommendation.Reason))
+		}
+
+		if len(profile.Flags) > 0 {
+			fmt.Fprintf(&b, "\nFlags for the next run: `%s`\n", strings.Join(profile.Flags, " "))
+		}
+	}
+
+	return b.String()
+}
+
+// markdownPaths lists pruned field paths as code, "none" when there are none.
+func markdownPaths(paths []string) string {
+	if len(paths) == 0 {
+		return "none"
+	}
+
+	return "`" + strings.Join(paths, "`, `") + "`"
+}
+
+// markdownTopic shortens an eth2 gossip topic to its name, e.g. beacon_attestation_5.
+func markdownTopic(topic string) string {
+	parts := strings.Split(topic, "/")
+	if len(parts) == 5 && parts[1] == "eth2" {
+		return parts[3]
+	}
+
+	return topic
+}
+
+// markdownCell escapes a value for a markdown table cell.
+func markdownCell(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
+
+// GenerateMarkdownSummary writes the markdown run summary next to the JSON reports.
+func (g *DefaultGenerator) GenerateMarkdownSummary(report *Report) (string, error) {
+	report, err := g.pruneReport(report)
+	if err != nil {
+		return "", err
+	}
+
+	filename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultMarkdownSummaryFile, report.Timestamp)
+
+	if err := g.fileManager.SaveHTML(filename, g.redactor.String(RenderMarkdownSummary(report))); err != nil {
+		return "", fmt.Errorf("failed to save markdown summary: %w", err)
+	}
+
+	g.recordArtifact(ArtifactMarkdownSummary, filename)
+	g.logger.WithField("filename", filename).Info("Markdown summary generated successfully")
+
+	return filename, nil
+}
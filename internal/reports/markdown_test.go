@@ -0,0 +1,121 @@
+package reports
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/recommend"
+)
+
+func TestRenderMarkdownSummary(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	connectedAt := start.Add(time.Minute)
+	disconnectedAt := start.Add(2 * time.Minute)
+
+	report := &Report{
+		Config:               map[string]interface{}{"network": "hoodi"},
+		ValidationMode:       "delegated",
+		ValidationConfig:     map[string]interface{}{"HermesVersion": "v0.0.4"},
+		Timestamp:            start,
+		StartTime:            start,
+		EndTime:              start.Add(10 * time.Minute),
+		Duration:             10 * time.Minute,
+		SuccessfulHandshakes: 3,
+		FailedHandshakes:     1,
+		DataQuality:          &peer.DataQualityStats{EventsChecked: 100, OutOfOrderEvents: 2},
+		EventSampling: &peer.EventSampling{Types: []peer.EventTypeSampling{
+			{EventType: "PRUNE", Rate: 10, Seen: 30, Recorded: 4, Factor: 7.5},
+		}},
+		TopicHealth: &peer.TopicHealthSummary{Dlo: 6, Healthy: 1, Unhealthy: 1, Topics: []peer.TopicHealth{
+			{Topic: "/eth2/4a26c58b/sync_committee_1/ssz_snappy", Health: peer.TopicUnhealthy, Reasons: []string{"no mesh peers at the end"}},
+			{Topic: "/eth2/4a26c58b/beacon_block/ssz_snappy", Health: peer.TopicHealthy, MeshSampled: true, MeanMesh: 7.5, Mesh: []int{4, 8},
+				MeanFirstDeliveries: 1.5, MeanMeshDeliveries: 2.25},
+		}},
+		Peers: map[string]interface{}{
+			"16Uiu2HAmWorst": &peer.Stats{ClientType: constants.Lighthouse, ConnectionSessions: []peer.ConnectionSession{{
+				ConnectedAt: &connectedAt, DisconnectedAt: &disconnectedAt, Disconnected: true,
+				GoodbyeEvents: []peer.GoodbyeEvent{{Code: 129, Reason: "too | many peers"}},
+				PeerScores:    []peer.PeerScoreSnapshot{{Timestamp: connectedAt, Score: -20}},
+				ScoreSummary:  &peer.SessionScoreSummary{Snapshots: 1, ScoredSeconds: 60, TimeWeightedMean: -20, AreaBelowZero: -1200},
+			}}},
+			"16Uiu2HAmFine": &peer.Stats{ClientType: constants.Teku, ConnectionSessions: []peer.ConnectionSession{{ConnectedAt: &connectedAt}}},
+		},
+		Recommendations: &recommend.Profile{
+			Recommendations: []recommend.Recommendation{
+				{Setting: recommend.SettingMaxPeers, Flag: "--max-peers", Current: "80", Suggested: "120", Change: true, Reason: "Our limit ended 4 of 10 sessions"},
+				{Setting: recommend.SettingDialConcurrency, Current: "16", Suggested: "16", Reason: "The node reached capacity after 2m0s"},
+			},
+			Flags: []string{"--max-peers=120"},
+		},
+	}
+
+	summary := RenderMarkdownSummary(report)
+	lite := BuildLiteReport(report)
+
+	expected := []string{
+		"Validation mode `delegated` on `hoodi` with Hermes `v0.0.4`, 10m0s from 2025-06-01T12:00:00Z.",
+		"| Unique peers | 2 |",
+		"| Handshake success rate | 75.0% |",
+		"| Sessions | 2 |",
+		"| lighthouse | 1 | 1 | 1 | 0 ok, 0 failed | -20.00 |",
+		"| `16Uiu2HAmWorst` | lighthouse | -20.00 | -1200.0 | 0s |",
+		`| 129 | too \| many peers | 1 |`,
+		"100 events checked, 2 out of order",
+		"| PRUNE | 1 in 10 | 30 | 4 | 7.50 |",
+		"1 healthy, 0 degraded, 1 unhealthy.",
+		"| `sync_committee_1` | unhealthy: no mesh peers at the end | - | - | 0.00 | 0.00 | 0.0 |",
+		"| `beacon_block` | healthy | 7.5 | ▄█ | 1.50 | 2.25 | 0.0 |",
+		"| `max_peers` | 80 | **120** | Our limit ended 4 of 10 sessions |",
+		"| `dial_concurrency` | 16 | keep | The node reached capacity after 2m0s |",
+		"Flags for the next run: `--max-peers=120`",
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected markdown summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+
+	// The lite report reads the same headline, so the numbers agree
+	if lite.Summary.Sessions != 2 || lite.Summary.HandshakeSuccessRate != 0.75 || lite.Summary.UnhealthyTopics != 1 {
+		t.Errorf("Unexpected lite summary %+v", lite.Summary)
+	}
+
+	if lite.EventSampling == nil || lite.EventSampling.Types[0].Factor != 7.5 {
+		t.Errorf("Expected the lite report to carry the event sampling factors, got %+v", lite.EventSampling)
+	}
+}
+
+func TestGenerateMarkdownSummary(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &Report{ValidationMode: "delegated", Timestamp: start, StartTime: start, EndTime: start.Add(time.Hour), Peers: map[string]interface{}{}}
+
+	g, err := NewGenerator(logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating generator, got %v", err)
+	}
+
+	fm := NewMockFileManager()
+	g.SetFileManager(fm)
+
+	filename, err := g.GenerateMarkdownSummary(report)
+	if err != nil {
+		t.Fatalf("Expected no error generating markdown summary, got %v", err)
+	}
+
+	if filename != "peer-score-summary-delegated-2025-06-01_12-00-00.md" {
+		t.Errorf("Unexpected markdown summary filename %q", filename)
+	}
+
+	if !strings.HasPrefix(string(fm.files[filename]), "## Peer score run summary") {
+		t.Errorf("Unexpected markdown summary contents %q", fm.files[filename])
+	}
+}
@@ -0,0 +1,48 @@
+package reports
+
+import (
+	"github.com/ethpandaops/hermes-peer-score/internal/glossary"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// MetricDefinitions returns the definitions of the metrics reports show, grouped by the
+// struct carrying them. The definitions live next to the code computing each metric.
+func MetricDefinitions() []glossary.Group {
+	return []glossary.Group{
+		{
+			Title:       "Connections",
+			Source:      "Report summary and headline",
+			Definitions: peer.ConnectionStatsDefinitions,
+		},
+		{
+			Title:       "Reconciled Handshakes",
+			Source:      "Report summary",
+			Definitions: peer.ReconciledHandshakeDefinitions,
+		},
+		{
+			Title:       "Headline",
+			Source:      "HTML report, lite report and markdown summary",
+			Definitions: headlineDefinitions,
+		},
+		{
+			Title:       "Clients",
+			Source:      "Client summaries",
+			Definitions: peer.ClientSummaryDefinitions,
+		},
+		{
+			Title:       "Session Scores",
+			Source:      "Each session's score summary",
+			Definitions: peer.SessionScoreDefinitions,
+		},
+		{
+			Title:       "Hermes Metrics",
+			Source:      "Hermes regression report and alerting",
+			Definitions: peer.HermesMetricsDefinitions,
+		},
+		{
+			Title:       "AI Analysis",
+			Source:      "Connection metrics given to the AI analysis",
+			Definitions: connectionMetricsDefinitions,
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/glossary"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestMetricDefinitionsMatchFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions []glossary.Definition
+		v           interface{}
+	}{
+		{"connection stats", peer.ConnectionStatsDefinitions, peer.ConnectionStats{}},
+		{"reconciled handshakes", peer.ReconciledHandshakeDefinitions, peer.ReconciledHandshakeStats{}},
+		{"headline", headlineDefinitions, RunHeadline{}},
+		{"client summary", peer.ClientSummaryDefinitions, peer.ClientSummary{}},
+		{"session scores", peer.SessionScoreDefinitions, peer.SessionScoreSummary{}},
+		{"hermes metrics", peer.HermesMetricsDefinitions, peer.HermesMetrics{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if unknown := glossary.Unknown(tt.definitions, tt.v); len(unknown) > 0 {
+				t.Errorf("Expected every definition to name a field, got unknown %v", unknown)
+			}
+		})
+	}
+
+	// The AI analysis metrics are map keys, defined as prepareAnalysisData writes them
+	data := NewDefaultAIAnalyzer(logrus.New()).prepareAnalysisData(&Report{Peers: map[string]interface{}{
+		"peer1": map[string]interface{}{
+			"connection_sessions": []interface{}{map[string]interface{}{
+				"connection_duration": float64(5 * time.Minute),
+			}},
+		},
+	}})
+
+	metrics, ok := data["connection_metrics"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected connection metrics in the analysis data")
+	}
+
+	for _, definition := range connectionMetricsDefinitions {
+		if _, ok := metrics[definition.Field]; !ok {
+			t.Errorf("Expected connection metric %s in the analysis data", definition.Field)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// openMetricsNamespace prefixes every metric of the snapshot.
+const openMetricsNamespace = "hermes_peer_score"
+
+// openMetricsSnapshot registers the gauges of one snapshot. Every gauge carries the run's
+// validation mode and network, so snapshots of several runs pushed together stay apart.
+type openMetricsSnapshot struct {
+	registry *prometheus.Registry
+	labels   prometheus.Labels
+}
+
+// vec registers a gauge with the given variable labels.
+func (s *openMetricsSnapshot) vec(name, help string, labels ...string) *prometheus.GaugeVec {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   openMetricsNamespace,
+		Name:        name,
+		Help:        help,
+		ConstLabels: s.labels,
+	}, labels)
+
+	s.registry.MustRegister(gauge)
+
+	return gauge
+}
+
+// set registers a gauge without variable labels and sets it.
+func (s *openMetricsSnapshot) set(name, help string, value float64) {
+	s.vec(name, help).WithLabelValues().Set(value)
+}
+
+// BuildOpenMetrics encodes a run's final aggregate metrics in the OpenMetrics text format, for
+// a Pushgateway or a textfile collector. Every metric is a gauge without a timestamp, so the
+// file is also valid Prometheus text format and the Pushgateway accepts it as is. Its numbers
+// come from the lite report, so the two never disagree.
+func BuildOpenMetrics(report *Report) ([]byte, error) {
+	lite := BuildLiteReport(report)
+	headline := CalculateHeadline(report)
+
+	snapshot := &openMetricsSnapshot{
+		registry: prometheus.NewRegistry(),
+		labels:   prometheus.Labels{"validation_mode": lite.ValidationMode, "network": lite.Network},
+	}
+
+	snapshot.vec("run_info", "Versions the run was made with, always 1.", "hermes_version", "agent_version").
+		WithLabelValues(lite.HermesVersion, lite.AgentVersion).Set(1)
+	snapshot.set("run_start_time_seconds", "Start of the run's measurement window, in Unix seconds.", float64(lite.StartTime.Unix()))
+	snapshot.set("run_end_time_seconds", "End of the run's measurement window, in Unix seconds.", float64(lite.EndTime.Unix()))
+	snapshot.set("run_duration_seconds", "Length of the run's measurement window.", lite.DurationSeconds)
+	snapshot.set("run_pruned", "1 when fields were dropped or hashed before the report was written.", boolGauge(lite.PrunePolicy != nil))
+
+	summary := lite.Summary
+
+	snapshot.set("unique_peers", "Peers seen during the run.", float64(summary.UniquePeers))
+	snapshot.set("connections", "Connections made in the measurement window.", float64(summary.TotalConnections))
+
+	handshakes := snapshot.vec("handshakes", "Status handshakes in the measurement window, by result.", "result")
+	handshakes.WithLabelValues("successful").Set(float64(summary.SuccessfulHandshakes))
+	handshakes.WithLabelValues("failed").Set(float64(summary.FailedHandshakes))
+
+	snapshot.set("handshake_success_ratio", "Share of the handshakes that succeeded.", summary.HandshakeSuccessRate)
+	snapshot.set("sessions", "Connection sessions across all peers.", float64(summary.Sessions))
+	snapshot.set("disconnects", "Disconnects before the run's shutdown.", float64(summary.Disconnects))
+	snapshot.set("goodbye_events", "Goodbyes received before the run's shutdown.", float64(summary.GoodbyeEvents))
+	snapshot.set("invalid_delivery_topics", "Topics with invalid message deliveries across several peers.", float64(summary.InvalidDeliveryTopics))
+
+	topics := snapshot.vec("flagged_topics", "Gossip topics flagged across peers, by health.", "health")
+	topics.WithLabelValues("degraded").Set(float64(summary.DegradedTopics))
+	topics.WithLabelValues("unhealthy").Set(float64(summary.UnhealthyTopics))
+
+	snapshot.set("clock_skewed", "1 when our clock was skewed from the beacon node's or the peers'.", boolGauge(summary.ClockSkewed))
+	snapshot.set("starved_seconds", "Time no events arrived while the run was active.", summary.StarvedSeconds)
+
+	if quality := lite.DataQuality; quality != nil {
+		snapshot.set("data_quality_events_checked", "Events checked for data quality.", float64(quality.EventsChecked))
+		snapshot.set("data_quality_missing_timestamps", "Events without a timestamp.", float64(quality.MissingTimestamps))
+		snapshot.set("data_quality_out_of_order_events", "Events older than the peer's previous event.", float64(quality.OutOfOrderEvents))
+		snapshot.set("data_quality_max_lag_seconds", "Largest delay between an event and its processing.", quality.MaxLagSeconds)
+		snapshot.set("data_quality_unhandled_events", "Events of a type no handler processes.", float64(quality.UnhandledEvents))
+
+		late := snapshot.vec("data_quality_late_events", "Events arriving after their session closed, by outcome.", "outcome")
+		late.WithLabelValues("assigned").Set(float64(quality.LateEventsAssigned))
+		late.WithLabelValues("dropped").Set(float64(quality.LateEventsDropped))
+	}
+
+	clientPeers := snapshot.vec("client_peers", "Peers by client.", "client")
+	clientSessions := snapshot.vec("client_sessions", "Connection sessions by client.", "client")
+	clientDisconnects := snapshot.vec("client_disconnects", "Disconnects before the run's shutdown by client.", "client")
+	clientGoodbyes := snapshot.vec("client_goodbye_events", "Goodbyes received before the run's shutdown by client.", "client")
+	clientHandshakes := snapshot.vec("client_handshakes", "Status handshakes by client and result.", "client", "result")
+	clientDuration := snapshot.vec("client_median_session_duration_seconds", "Median length of the client's disconnected sessions.", "client")
+	clientScored := snapshot.vec("client_scored_peers", "Peers with a score by client.", "client")
+	clientScore := snapshot.vec("client_median_score", "Median of the latest score of the client's scored peers.", "client")
+	clientAbuse := snapshot.vec("client_reqresp_abuse", "Rate limited or malformed requests the client's peers sent us.", "client")
+
+	for _, client := range lite.Clients {
+		clientPeers.WithLabelValues(client.Client).Set(float64(client.Peers))
+		clientSessions.WithLabelValues(client.Client).Set(float64(client.Sessions))
+		clientDisconnects.WithLabelValues(client.Client).Set(float64(client.Disconnects))
+		clientGoodbyes.WithLabelValues(client.Client).Set(float64(client.GoodbyeEvents))
+		clientHandshakes.WithLabelValues(client.Client, "successful").Set(float64(client.SuccessfulHandshakes))
+		clientHandshakes.WithLabelValues(client.Client, "failed").Set(float64(client.FailedHandshakes))
+		clientDuration.WithLabelValues(client.Client).Set(client.MedianDurationSeconds)
+		clientScored.WithLabelValues(client.Client).Set(float64(client.ScoredPeers))
+		clientAbuse.WithLabelValues(client.Client).Set(float64(client.ReqRespAbuse))
+
+		// A client without scored peers has no median score, rather than one of zero
+		if client.ScoredPeers > 0 {
+			clientScore.WithLabelValues(client.Client).Set(client.MedianScore)
+		}
+	}
+
+	reasons := snapshot.vec("disconnect_reasons", "Most frequent goodbye codes and reasons, and our own terminations.", "side", "code", "reason")
+
+	for _, reason := range lite.DisconnectReasons {
+		reasons.WithLabelValues(reason.Side, strconv.FormatUint(reason.Code, 10), reason.Reason).Set(float64(reason.Count))
+	}
+
+	if bands := headline.ScoreBands; bands != nil {
+		snapshot.set("scored_peers", "Peers with at least one score snapshot.", float64(bands.Peers))
+		snapshot.set("score_snapshots", "Score snapshots across all peers.", float64(bands.Snapshots))
+
+		minimum := snapshot.vec("peer_min_score", "Percentiles of each peer's lowest score over the run.", "percentile")
+		mean := snapshot.vec("peer_mean_score", "Percentiles of each peer's mean score over the run.", "percentile")
+
+		for percentile, values := range map[string][2]float64{
+			"10": {bands.Min.P10, bands.Mean.P10},
+			"50": {bands.Min.P50, bands.Mean.P50},
+			"90": {bands.Min.P90, bands.Mean.P90},
+		} {
+			minimum.WithLabelValues(percentile).Set(values[0])
+			mean.WithLabelValues(percentile).Set(values[1])
+		}
+
+		below := snapshot.vec("peers_below_threshold", "Peers whose lowest score fell below a gossipsub threshold.", "threshold")
+		below.WithLabelValues("gossip").Set(float64(bands.BelowGossip))
+		below.WithLabelValues("publish").Set(float64(bands.BelowPublish))
+		below.WithLabelValues("graylist").Set(float64(bands.BelowGraylist))
+	}
+
+	families, err := snapshot.registry.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather OpenMetrics snapshot: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToOpenMetrics(&out, family); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", family.GetName(), err)
+		}
+	}
+
+	if _, err := expfmt.FinalizeOpenMetrics(&out); err != nil {
+		return nil, fmt.Errorf("failed to finalize OpenMetrics snapshot: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// boolGauge converts a flag to a gauge value.
+func boolGauge(flag bool) float64 {
+	if flag {
+		return 1
+	}
+
+	return 0
+}
+
+// GenerateOpenMetrics writes the run's OpenMetrics snapshot next to the JSON reports, so its
+// final metrics can be pushed or ingested without the live metrics endpoint.
+func (g *DefaultGenerator) GenerateOpenMetrics(report *Report) (string, error) {
+	report, err := g.pruneReport(report)
+	if err != nil {
+		return "", err
+	}
+
+	metrics, err := BuildOpenMetrics(report)
+	if err != nil {
+		return "", err
+	}
+
+	filename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultOpenMetricsFile, report.Timestamp)
+
+	if err := g.fileManager.SaveHTML(filename, g.redactor.String(string(metrics))); err != nil {
+		return "", fmt.Errorf("failed to save OpenMetrics snapshot: %w", err)
+	}
+
+	g.recordArtifact(ArtifactOpenMetrics, filename)
+	g.logger.WithField("filename", filename).Info("OpenMetrics snapshot generated successfully")
+
+	return filename, nil
+}
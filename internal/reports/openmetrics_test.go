@@ -0,0 +1,78 @@
+package reports
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestBuildOpenMetrics(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	connectedAt := start.Add(time.Minute)
+	disconnectedAt := start.Add(2 * time.Minute)
+
+	report := &Report{
+		Config:               map[string]interface{}{"network": "hoodi"},
+		ValidationMode:       "delegated",
+		ValidationConfig:     map[string]interface{}{"HermesVersion": "v0.0.4"},
+		StartTime:            start,
+		EndTime:              start.Add(10 * time.Minute),
+		Duration:             10 * time.Minute,
+		SuccessfulHandshakes: 3,
+		FailedHandshakes:     1,
+		DataQuality:          &peer.DataQualityStats{EventsChecked: 100, OutOfOrderEvents: 2},
+		Peers: map[string]interface{}{
+			"a": &peer.Stats{ClientType: constants.Lighthouse, ConnectionSessions: []peer.ConnectionSession{{
+				ConnectedAt: &connectedAt, DisconnectedAt: &disconnectedAt, Disconnected: true,
+				GoodbyeEvents: []peer.GoodbyeEvent{{Code: 129, Reason: `too "many" peers`}},
+			}}},
+			"b": &peer.Stats{ClientType: constants.Teku, ConnectionSessions: []peer.ConnectionSession{{ConnectedAt: &connectedAt}}},
+		},
+	}
+
+	metrics, err := BuildOpenMetrics(report)
+	if err != nil {
+		t.Fatalf("Expected the snapshot to encode, got %v", err)
+	}
+
+	text := string(metrics)
+
+	if !strings.HasSuffix(text, "# EOF\n") {
+		t.Errorf("Expected the snapshot to end with # EOF, got %q", text)
+	}
+
+	for _, line := range []string{
+		`hermes_peer_score_run_info{agent_version="",hermes_version="v0.0.4",network="hoodi",validation_mode="delegated"} 1.0`,
+		`hermes_peer_score_handshakes{network="hoodi",result="failed",validation_mode="delegated"} 1.0`,
+		`hermes_peer_score_client_peers{client="` + constants.Teku + `",network="hoodi",validation_mode="delegated"} 1.0`,
+		`hermes_peer_score_disconnect_reasons{code="129",network="hoodi",reason="too \"many\" peers",side="remote",validation_mode="delegated"} 1.0`,
+		`hermes_peer_score_data_quality_out_of_order_events{network="hoodi",validation_mode="delegated"} 2.0`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("Expected the line %s in %s", line, text)
+		}
+	}
+
+	// Unscored peers have no median score rather than one of zero
+	if strings.Contains(text, "hermes_peer_score_client_median_score{") {
+		t.Errorf("Expected no median score without scored peers, got %s", text)
+	}
+
+	// The Pushgateway reads the Prometheus text format, which the snapshot must stay valid in
+	var parser expfmt.TextParser
+
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(metrics))
+	if err != nil {
+		t.Fatalf("Expected the snapshot to parse as Prometheus text, got %v", err)
+	}
+
+	if family := families["hermes_peer_score_unique_peers"]; family == nil || family.GetMetric()[0].GetGauge().GetValue() != 2 {
+		t.Errorf("Expected 2 unique peers, got %v", family)
+	}
+}
@@ -0,0 +1,83 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// checkCancelled returns an error naming the stage if report generation was cancelled.
+func checkCancelled(ctx context.Context, stage string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("report generation cancelled during %s: %w", stage, err)
+	}
+
+	return nil
+}
+
+// logStage logs that a report generation stage completed.
+func (g *DefaultGenerator) logStage(stage string, started time.Time, fields logrus.Fields) {
+	g.logger.WithFields(fields).WithFields(logrus.Fields{
+		"stage":   stage,
+		"elapsed": time.Since(started).Round(time.Millisecond),
+	}).Info("Report generation progress")
+}
+
+// markPartial renames the files and directories of a cancelled report generation with the
+// partial suffix, so an incomplete report is never mistaken for a complete one. Paths that
+// were not written yet are skipped.
+func (g *DefaultGenerator) markPartial(paths ...string) {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		if err := os.Rename(path, path+constants.PartialReportSuffix); err != nil {
+			g.logger.WithError(err).WithField("path", path).Warn("Failed to mark partial report file")
+
+			continue
+		}
+
+		g.logger.WithField("path", path+constants.PartialReportSuffix).Warn("Marked partial report file")
+	}
+}
+
+// progressLogger logs the progress of a long running write at most once per interval.
+type progressLogger struct {
+	logger   logrus.FieldLogger
+	interval time.Duration
+	last     time.Time
+}
+
+// newProgressLogger creates a progress logger for the named file.
+func newProgressLogger(logger logrus.FieldLogger, filename string) *progressLogger {
+	return &progressLogger{
+		logger:   logger.WithField("filename", filename),
+		interval: constants.ReportProgressInterval,
+		last:     time.Now(),
+	}
+}
+
+// update logs the peers and bytes written so far if the interval has passed since the last log.
+func (p *progressLogger) update(peers, total, bytes int) {
+	if p == nil || time.Since(p.last) < p.interval {
+		return
+	}
+
+	p.last = time.Now()
+
+	p.logger.WithFields(logrus.Fields{
+		"peers_written": peers,
+		"peers_total":   total,
+		"bytes_written": bytes,
+	}).Info("Writing report data")
+}
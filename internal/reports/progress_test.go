@@ -0,0 +1,155 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/aiqueue"
+)
+
+// cancellingFileManager saves files like the default file manager and cancels generation
+// once the HTML report is saved.
+type cancellingFileManager struct {
+	*DefaultFileManager
+	cancel context.CancelFunc
+}
+
+func (fm *cancellingFileManager) SaveHTML(filename string, content string) error {
+	err := fm.DefaultFileManager.SaveHTML(filename, content)
+	if strings.HasPrefix(filename, "peer-score-report") {
+		fm.cancel()
+	}
+
+	return err
+}
+
+func TestGenerateHTMLCancelled(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	htmlFile := "peer-score-report-delegated-2025-06-01_12-00-00.html"
+	dataFile := "peer-score-report-data-delegated-2025-06-01_12-00-00.js"
+
+	tests := []struct {
+		name    string
+		before  bool     // Cancel before generation starts rather than once the HTML is saved
+		partial []string // Files expected to be marked partial
+	}{
+		{name: "before generation", before: true},
+		{name: "after the HTML report", partial: []string{htmlFile}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tt.before {
+				cancel()
+			}
+
+			g, err := NewGenerator(logger)
+			if err != nil {
+				t.Fatalf("Expected no error creating generator, got %v", err)
+			}
+
+			g.SetFileManager(&cancellingFileManager{DefaultFileManager: NewDefaultFileManager(logger), cancel: cancel})
+
+			report := &Report{
+				ValidationMode:   "delegated",
+				ValidationConfig: map[string]interface{}{"HermesVersion": "test"},
+				Timestamp:        start,
+				StartTime:        start,
+				EndTime:          start.Add(time.Minute),
+				Duration:         time.Minute,
+				Peers:            map[string]interface{}{},
+			}
+
+			if _, err := g.GenerateHTML(ctx, report); !errors.Is(err, context.Canceled) {
+				t.Fatalf("Expected a cancelled error, got %v", err)
+			}
+
+			for _, file := range []string{htmlFile, dataFile} {
+				if _, err := os.Stat(file); err == nil {
+					t.Errorf("Expected %s not to be left unmarked", file)
+				}
+			}
+
+			for _, file := range tt.partial {
+				if _, err := os.Stat(file + constants.PartialReportSuffix); err != nil {
+					t.Errorf("Expected %s to be marked partial: %v", file, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateHTMLWithAIDeferred(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	t.Chdir(t.TempDir())
+
+	queue, err := aiqueue.New("ai-queue", 1)
+	if err != nil {
+		t.Fatalf("Expected no error creating AI queue, got %v", err)
+	}
+
+	// Another run holds the only slot for longer than this one waits
+	held, err := queue.Acquire(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("Expected a slot, got %v", err)
+	}
+	defer held.Release()
+
+	g, err := NewGenerator(logger)
+	if err != nil {
+		t.Fatalf("Expected no error creating generator, got %v", err)
+	}
+
+	g.SetAIAnalyzer(&MockAIAnalyzer{})
+	g.SetAIQueue(queue, 0)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &Report{
+		ValidationMode:   "delegated",
+		ValidationConfig: map[string]interface{}{"HermesVersion": "test"},
+		Timestamp:        start,
+		StartTime:        start,
+		EndTime:          start.Add(time.Minute),
+		Duration:         time.Minute,
+		Peers:            map[string]interface{}{},
+	}
+
+	htmlFile, err := g.GenerateHTMLWithAI(context.Background(), report, "key")
+	if err != nil {
+		t.Fatalf("Expected the report without AI analysis, got %v", err)
+	}
+
+	if g.aiStatus != AIStatusDeferred {
+		t.Errorf("Expected AI status %q, got %q", AIStatusDeferred, g.aiStatus)
+	}
+
+	content, err := os.ReadFile(htmlFile)
+	if err != nil {
+		t.Fatalf("Expected the HTML report, got %v", err)
+	}
+
+	if !strings.Contains(string(content), `id="ai-deferred"`) || strings.Contains(string(content), "Mock AI analysis result") {
+		t.Error("Expected the report to mark the AI analysis deferred and leave it out")
+	}
+
+	if g.errors.Counts() != nil {
+		t.Errorf("Expected a deferred analysis not to count as an error, got %v", g.errors.Counts())
+	}
+}
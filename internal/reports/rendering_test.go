@@ -0,0 +1,821 @@
+package reports
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconfetch"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconpeers"
+	"github.com/ethpandaops/hermes-peer-score/internal/beaconsync"
+	"github.com/ethpandaops/hermes-peer-score/internal/canary"
+	"github.com/ethpandaops/hermes-peer-score/internal/clientmeta"
+	"github.com/ethpandaops/hermes-peer-score/internal/clockskew"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/recommend"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports/templates"
+	"github.com/ethpandaops/hermes-peer-score/internal/resources"
+	"github.com/ethpandaops/hermes-peer-score/internal/watchdog"
+)
+
+// renderReport formats report for the template and renders the HTML report from it.
+func renderReport(t *testing.T, report *Report) string {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	templateData, err := NewDefaultDataProcessor(logger).FormatForTemplate(report)
+	if err != nil {
+		t.Fatalf("Expected no error formatting for template, got %v", err)
+	}
+
+	tm := templates.NewManager(logger)
+	if err := tm.LoadTemplates(); err != nil {
+		t.Fatalf("Expected no error loading templates, got %v", err)
+	}
+
+	html, err := tm.RenderReport(templateData)
+	if err != nil {
+		t.Fatalf("Expected no error rendering report, got %v", err)
+	}
+
+	return html
+}
+
+// TestReportRendering renders one section of the HTML report per case, starting from an hour
+// long delegated run without peers. What each section shows is computed, and tested, by the
+// package that owns it; these cases only check the template renders it.
+func TestReportRendering(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(d time.Duration) *time.Time {
+		ts := start.Add(d)
+
+		return &ts
+	}
+
+	metadata, err := clientmeta.Load()
+	if err != nil {
+		t.Fatalf("Expected bundled client metadata to load, got %v", err)
+	}
+
+	// The CDN is only consulted for the logos the bundle lacks
+	logosFetched, logosBundled := []string{}, []string{"cartographoor"}
+	if metadata.LogoFallback() != "" {
+		logosFetched, logosBundled = logosBundled, logosFetched
+	}
+
+	samplingPeers := map[string]*peer.Stats{
+		"16Uiu2HAmBaseline": {PeerID: "16Uiu2HAmBaseline", Sample: &peer.DetailSample{Captured: true, Reason: peer.SampleBaseline, Weight: 10}},
+		"16Uiu2HAmGoodbye":  {PeerID: "16Uiu2HAmGoodbye", Sample: &peer.DetailSample{Captured: true, Reason: peer.SampleGoodbye, Weight: 1}},
+		"16Uiu2HAmSkipped":  {PeerID: "16Uiu2HAmSkipped", Sample: &peer.DetailSample{}},
+	}
+
+	subscriptions := peer.NewSubscriptionRecorder()
+	subscriptions.Record(peer.SubscriptionJoin, "/eth2/aaaa0000/beacon_block/ssz_snappy", start)
+	subscriptions.Record(peer.SubscriptionJoin, "/eth2/aaaa0000/blob_sidecar_0/ssz_snappy", start)
+
+	abuser := &peer.Stats{PeerID: "16Uiu2HAmAbuser", ClientType: "prysm"}
+	abuser.RecordReqRespAbuse(peer.ReqRespAbuseRateLimit, "HANDLE_PING", "exceeded HANDLE_PING quota", start)
+	abuser.RecordReqRespAbuse(peer.ReqRespAbuseMalformed, "HANDLE_STATUS", "read request data *pb.Status: snappy: corrupt input", start)
+
+	leech := &peer.Stats{PeerID: "16Uiu2HAmLeech", ClientType: "teku"}
+	leech.RecordControlRPC(true, 40, 0, 0, 3)
+	leech.RecordControlRPC(false, 0, 25, 0, 0)
+
+	median := 600.0
+
+	tests := []struct {
+		name     string
+		setup    func(report *Report)
+		expected []string
+		absent   []string
+	}{
+		{
+			name: "static fallback",
+			setup: func(report *Report) {
+				report.ValidationMode = "independent"
+				report.TotalConnections = 1
+				report.SuccessfulHandshakes = 1
+				report.Peers["16Uiu2HAmStaticFallbackPeer"] = &peer.Stats{
+					PeerID:      "16Uiu2HAmStaticFallbackPeer",
+					ClientType:  constants.Lighthouse,
+					ClientAgent: "Lighthouse/v7.0.0",
+					ConnectionSessions: []peer.ConnectionSession{{
+						ConnectedAt: at(0),
+						PeerScores:  []peer.PeerScoreSnapshot{{Timestamp: start, Score: -1.5}},
+					}},
+				}
+				report.PeerEventCounts = map[string]map[string]int{"16Uiu2HAmStaticFallbackPeer": {"CONNECTED": 1, "PEERSCORE": 4}}
+			},
+			expected: append([]string{
+				`id="staticFallback"`, "16Uiu2HAmSta", constants.Lighthouse, "<td class=\"px-3 py-2\">5</td>", "-1.500 to -1.500", ".bg-white{",
+			}, logosFetched...),
+			absent: append([]string{"cdn.tailwindcss.com"}, logosBundled...),
+		},
+		{
+			name: "unknown client diagnosis",
+			setup: func(report *Report) {
+				report.Peers["16Uiu2HAmUnknownClientPeer"] = &peer.Stats{
+					PeerID:             "16Uiu2HAmUnknownClientPeer",
+					ClientType:         constants.Unknown,
+					ClientAgent:        "erigon/caplin",
+					ConnectionSessions: []peer.ConnectionSession{{ConnectedAt: at(0)}},
+				}
+			},
+			expected: []string{"Unknown Client Diagnosis", "erigon/caplin", "unidentified still connected"},
+		},
+		{
+			name: "transports",
+			setup: func(report *Report) {
+				report.Peers["16Uiu2HAmQuicPeer"] = &peer.Stats{
+					PeerID: "16Uiu2HAmQuicPeer",
+					ConnectionSessions: []peer.ConnectionSession{
+						{ConnectedAt: at(0), DisconnectedAt: at(10 * time.Second), Disconnected: true, Transport: peer.TransportQUIC, Muxer: "quic", Security: "tls"},
+						{ConnectedAt: at(10 * time.Second), Transport: peer.TransportTCP},
+					},
+				}
+			},
+			expected: []string{`id="section-transports"`, ">quic</td>", ">tcp</td>", "tls: 1", "not reported: 1", "10.0s"},
+		},
+		{
+			name: "sampling",
+			setup: func(report *Report) {
+				for peerID, stats := range samplingPeers {
+					report.Peers[peerID] = stats
+				}
+
+				report.Sampling = peer.SummarizeSampling(samplingPeers, 0.1, 7)
+			},
+			expected: []string{`id="section-sampling"`, "captured for 2 of 3 peers", "rate 0.1 with seed 7", ">goodbye</td>", ">11</td>"},
+		},
+		{
+			name: "score bands",
+			setup: func(report *Report) {
+				report.EndTime = start.Add(10 * time.Minute)
+				report.Duration = 10 * time.Minute
+				report.Peers["16Uiu2HAmHealthy"] = &peer.Stats{PeerID: "16Uiu2HAmHealthy", ConnectionSessions: []peer.ConnectionSession{{
+					PeerScores: []peer.PeerScoreSnapshot{{Timestamp: start.Add(time.Minute), Score: 12}, {Timestamp: start.Add(5 * time.Minute), Score: 14}},
+				}}}
+				report.Peers["16Uiu2HAmGraylisted"] = &peer.Stats{PeerID: "16Uiu2HAmGraylisted", ConnectionSessions: []peer.ConnectionSession{{
+					PeerScores: []peer.PeerScoreSnapshot{{Timestamp: start.Add(2 * time.Minute), Score: -20000}},
+				}}}
+			},
+			expected: []string{`id="section-score-bands"`, "Percentiles across the 2 scored peers, from 3 score snapshots", "<polygon points=", "graylist threshold -16000.00"},
+		},
+		{
+			name: "peer pressure distorted",
+			setup: func(report *Report) {
+				report.PeerPressure = &peer.PeerPressure{MaxPeers: 80, CapacityRatio: 0.95, Capacity: 76, PeakPeers: 80, Disconnects: 10, EndedByLocalLimit: 4, Rejected: 3, Pruned: 1, Distorted: true}
+			},
+			expected: []string{`id="section-peer-pressure"`, "Our own peer limit ended 4 of 10 sessions (40.0%)", "From 76 peers"},
+		},
+		{
+			name: "peer pressure within limits",
+			setup: func(report *Report) {
+				report.PeerPressure = &peer.PeerPressure{MaxPeers: 80, CapacityRatio: 0.95, Capacity: 76, PeakPeers: 40, Disconnects: 10, EndedByPeer: 6}
+			},
+			expected: []string{`id="section-peer-pressure"`, "<td class=\"px-3 py-2\">Never</td>"},
+			absent:   []string{"Our own peer limit ended"},
+		},
+		{
+			name: "session survival median reached",
+			setup: func(report *Report) {
+				report.SessionSurvival = &peer.SessionSurvival{
+					Sessions: 10, Completed: 7, Censored: 3, OpenAtEnd: 2, EndedInShutdown: 1, MedianSeconds: &median, MeanSeconds: 540,
+					Curve: []peer.SurvivalPoint{{Seconds: 60, Survival: 0.9, AtRisk: 9}},
+				}
+			},
+			expected: []string{`id="section-session-survival"`, "3 sessions (30.0%) were ended by the run", "2 open at the end, 1 in shutdown", "10.0m", "0.90"},
+			absent:   []string{"outlasted the run"},
+		},
+		{
+			name: "session survival every session open",
+			setup: func(report *Report) {
+				report.SessionSurvival = &peer.SessionSurvival{Sessions: 2, Censored: 2, OpenAtEnd: 2, Curve: []peer.SurvivalPoint{}}
+			},
+			expected: []string{`id="section-session-survival"`, "Over half the sessions outlasted the run"},
+		},
+		{
+			name: "topic health",
+			setup: func(report *Report) {
+				report.TopicHealth = &peer.TopicHealthSummary{Dlo: 6, Healthy: 1, Degraded: 1, Topics: []peer.TopicHealth{
+					{Topic: "/eth2/4a26c58b/voluntary_exit/ssz_snappy", Health: peer.TopicDegraded, Reasons: []string{"invalid deliveries from 1 peer"},
+						ScoredPeers: 3, InvalidDeliveries: 2, InvalidPeers: 1},
+					{Topic: "/eth2/4a26c58b/beacon_block/ssz_snappy", Health: peer.TopicHealthy, MeshSampled: true, MeanMesh: 7.5, FinalMesh: 8,
+						Mesh: []int{0, 8}, MeshPointSeconds: 120, ScoredPeers: 5, MeanFirstDeliveries: 1.25},
+				}}
+			},
+			expected: []string{
+				`id="section-topic-health"`, "1 healthy, 1 degraded and 0 unhealthy", "below half of Dlo (6)", "invalid deliveries from 1 peer",
+				"2.0 from 1 peer<", "Not sampled", "▁█", "1.25",
+			},
+		},
+		{
+			name: "beacon peers",
+			setup: func(report *Report) {
+				checkedAt := start.Add(30 * time.Minute)
+				report.BeaconPeers = &beaconpeers.Result{
+					CheckedAt: checkedAt, BeaconPeers: 2, HermesPeers: 1, Overlap: 1, BeaconOnly: 1, HermesOnly: 3,
+					Reconciliation: []beaconpeers.StateCount{
+						{State: beaconpeers.StateConnected, Both: 1, BeaconOnly: 1},
+						{State: beaconpeers.StateDisconnected, HermesOnly: 3},
+					},
+					OneSided: []beaconpeers.OneSidedPeer{
+						{PeerID: "16Uiu2HAmBeaconOnlyPeer", Side: beaconpeers.SideBeacon, LastSeenP2PAddress: "/ip4/5.6.7.8/tcp/9000"},
+					},
+					Snapshot: []beaconpeers.BeaconPeer{
+						{PeerID: "16Uiu2HAmBeaconOnlyPeer", State: beaconpeers.StateConnected, Direction: "inbound", LastSeenP2PAddress: "/ip4/5.6.7.8/tcp/9000"},
+					},
+					Periodic: []beaconpeers.Summary{
+						{CheckedAt: checkedAt.Add(-10 * time.Minute), BeaconPeers: 4, HermesPeers: 2, Overlap: 2, BeaconOnly: 2, HermesOnly: 7},
+						{CheckedAt: checkedAt.Add(-20 * time.Minute), Error: "connection refused"},
+					},
+				}
+			},
+			expected: []string{
+				`id="section-beacon-peers"`, "Reconciliation by state", "Connected on one side only", "Beacon node peer snapshot",
+				"/ip4/5.6.7.8/tcp/9000", "Checks during the run", "12:20:00", "connection refused",
+			},
+		},
+		{
+			name: "beacon fetches",
+			setup: func(report *Report) {
+				report.ValidationMode = "independent"
+				report.BeaconFetches = &beaconfetch.Summary{
+					Endpoint: "http://localhost:3500", Observed: true, Health: beaconfetch.HealthDegraded,
+					Reasons: []string{"1 of 4 fetches failed"}, Requests: 4, Failures: 1, SlowThresholdSeconds: 30, CacheSize: 10000,
+					Endpoints: []beaconfetch.EndpointStats{
+						{Kind: beaconfetch.KindBeaconState, Requests: 2, MeanMillis: 1834.4, P95Millis: 2100, MaxMillis: 2100},
+						{Kind: beaconfetch.KindSpec, Requests: 2, Failures: 1, MeanMillis: 12, P95Millis: 15, MaxMillis: 15, LastError: "HTTP 503"},
+					},
+				}
+			},
+			expected: []string{
+				`id="section-beacon-fetches"`, "http://localhost:3500", "1 of 4 fetches failed", "10000 entries, hit rate not exposed by Hermes",
+				"beacon_state", "1834 ms", "HTTP 503",
+			},
+		},
+		{
+			name: "beacon sync",
+			setup: func(report *Report) {
+				report.BeaconSync = &beaconsync.Summary{
+					Health: beaconsync.HealthDegraded, Failures: 3, SyncChecks: 20, NotSynced: 1, MaxSyncDistance: 40,
+					GraceSeconds: 60, DegradedSeconds: 90, PausedSnapshots: 7,
+					Windows: []beaconsync.Window{{
+						From: start.Add(time.Minute), To: start.Add(150 * time.Second), Seconds: 90, Failures: 3, Resolved: true,
+						Kinds: []string{beaconsync.FailureMissingHistory, beaconsync.FailureNotSynced},
+					}},
+					ByKind: []beaconsync.FailureCount{
+						{Kind: beaconsync.FailureMissingHistory, Protocol: "HANDLE_BLOB_SIDECARS_BY_RANGE", Count: 2, LastError: "resource unavailable"},
+						{Kind: beaconsync.FailureNotSynced, Protocol: beaconsync.ProtocolSyncCheck, Count: 1, LastError: "behind"},
+					},
+				}
+			},
+			expected: []string{
+				`id="section-beacon-sync"`, "20, 1 not synced, max distance 40 slots", "2025-06-01 12:01:00", "missing_history, not_synced",
+				"HANDLE_BLOB_SIDECARS_BY_RANGE", "resource unavailable",
+			},
+		},
+		{
+			name: "canary",
+			setup: func(report *Report) {
+				report.ValidationMode = "independent"
+				report.Canary = &canary.Result{
+					Status: canary.StatusFailed,
+					Topic:  "/eth2/4a26c58b/beacon_block/ssz_snappy",
+					Failed: 1,
+					Canaries: []canary.Outcome{
+						{
+							Staged:    canary.Staged{Behaviour: canary.BehaviourHonest, PeerID: "16Uiu2HAmHonestCanary"},
+							Snapshots: 100, LowestScore: 0, FinalScore: 1.5, Passed: true,
+							Checks: []canary.Check{{Name: "score not negative", Expected: ">= 0", Observed: "lowest 0.00", Passed: true}},
+						},
+						{
+							Staged:    canary.Staged{Behaviour: canary.BehaviourInvalid, PeerID: "16Uiu2HAmInvalidCanary", Published: 40},
+							Snapshots: 100, LowestScore: 0, FinalScore: 0,
+							Checks: []canary.Check{{Name: "invalid deliveries counted", Expected: "> 0", Observed: "0.00"}},
+						},
+					},
+				}
+			},
+			expected: []string{
+				`id="section-canary"`, "/eth2/4a26c58b/beacon_block/ssz_snappy", "1 of 2 canaries were not scored as expected",
+				"passed: score not negative, expected &gt;= 0, observed lowest 0.00",
+				"failed: invalid deliveries counted, expected &gt; 0, observed 0.00",
+			},
+		},
+		{
+			name: "shutdown completed",
+			setup: func(report *Report) {
+				report.Shutdown = &peer.ShutdownTeardown{
+					Start: report.EndTime, End: report.EndTime.Add(3 * time.Second), Seconds: 3, Completed: true, Peers: 2, Goodbye: 1, Closed: 1,
+					Codes: []peer.TeardownCode{{Code: 1, Reason: "client shutdown", Count: 1}},
+					Teardowns: []peer.PeerTeardown{
+						{PeerID: "16Uiu2HAmGoodbye", ClientType: "lighthouse", Outcome: peer.TeardownGoodbye, Code: 1, Reason: "client shutdown", AfterSeconds: 1},
+						{PeerID: "16Uiu2HAmClosed", ClientType: "prysm", Outcome: peer.TeardownClosed, AfterSeconds: 2},
+					},
+				}
+			},
+			expected: []string{`id="section-shutdown"`, "How the 2 peers still connected", "Goodbye 1 (client shutdown)", "Closed without a goodbye"},
+			absent:   []string{"before the shutdown timeout ran out"},
+		},
+		{
+			name: "shutdown timed out",
+			setup: func(report *Report) {
+				report.Shutdown = &peer.ShutdownTeardown{
+					Start: report.EndTime, End: report.EndTime.Add(10 * time.Second), Seconds: 10, Peers: 1, Unobserved: 1,
+					Teardowns: []peer.PeerTeardown{{PeerID: "16Uiu2HAmStuck", Outcome: peer.TeardownUnobserved}},
+				}
+			},
+			expected: []string{`id="section-shutdown"`, "before the shutdown timeout ran out"},
+			absent:   []string{"Goodbye Code"},
+		},
+		{
+			name: "invalid deliveries anomaly",
+			setup: func(report *Report) {
+				report.InvalidDeliveries = &peer.InvalidDeliveries{MinPeers: 2, AffectedTopics: 3, Anomalies: []peer.InvalidDeliveryAnomaly{{
+					Topic: "/eth2/aaaa0000/beacon_block/ssz_snappy", FirstSeenAt: start, LastSeenAt: start.Add(time.Minute), MaxDeliveries: 4, EstimatedPeers: 2,
+					Peers: []peer.InvalidDeliveryPeer{
+						{PeerID: "16Uiu2HAmInvalidOne", ClientType: "lighthouse", MaxDeliveries: 4, FirstSeenAt: start, LastSeenAt: start},
+						{PeerID: "16Uiu2HAmInvalidTwo", ClientType: "prysm", MaxDeliveries: 1, FirstSeenAt: start, LastSeenAt: start.Add(time.Minute)},
+					},
+				}}}
+			},
+			expected: []string{
+				`id="section-invalid-deliveries"`, "1 topic shows invalid message deliveries from 2 or more peers", "/eth2/aaaa0000/beacon_block/ssz_snappy",
+				"2 peers, up to 4.0 invalid deliveries, 12:00:00 to 12:01:00", "out of 3 topics with any",
+			},
+		},
+		{
+			name: "invalid deliveries none",
+			setup: func(report *Report) {
+				report.InvalidDeliveries = &peer.InvalidDeliveries{MinPeers: 2, AffectedTopics: 1, Anomalies: []peer.InvalidDeliveryAnomaly{}}
+			},
+			absent: []string{`id="section-invalid-deliveries"`, "invalid message deliveries from 2 or more peers"},
+		},
+		{
+			name: "subscriptions",
+			setup: func(report *Report) {
+				report.Subscriptions = subscriptions.Report(&peer.TopicExpectations{
+					Fork:       "electra",
+					ForkDigest: "aaaa0000",
+					Topics:     []peer.TopicExpectation{{Name: "beacon_block"}, {Name: "blob_sidecar", Subnets: 9}},
+				})
+			},
+			expected: []string{"Gossip Topic Subscriptions", "do not match the set expected for electra", "Missing Topic", "1 / 9"},
+		},
+		{
+			name: "router metrics",
+			setup: func(report *Report) {
+				report.RouterMetrics = &peer.RouterMetrics{
+					Start:         start,
+					BucketSeconds: 60,
+					Totals:        peer.RouterCounts{Delivered: 30, Duplicates: 10, IHaveReceived: 40, IWantSent: 8, IWantRatio: 0.2, IHaveSent: 5, IWantReceived: 2},
+					Topics:        []peer.RouterTopic{{Topic: "/eth2/aaaa0000/beacon_block/ssz_snappy", MinMesh: 4, MaxMesh: 8, MeanMesh: 6.5, FinalMesh: 7}},
+					Samples: []peer.RouterSample{{
+						BucketStart:  start,
+						MeshPeers:    7,
+						RouterCounts: peer.RouterCounts{Delivered: 30, Duplicates: 10, IHaveReceived: 40, IWantSent: 8, Published: 4, PublishRejected: 1},
+					}},
+					Publishing: &peer.PublishActivity{
+						Published:     4,
+						Rejected:      1,
+						PeakPerBucket: 4,
+						Topics: []peer.PublishTopic{{
+							Topic:         "/eth2/aaaa0000/beacon_attestation_3/ssz_snappy",
+							Published:     4,
+							Rejected:      1,
+							RejectReasons: map[string]int{"validation failed": 1},
+						}},
+					},
+				}
+			},
+			expected: []string{
+				`id="section-router-metrics"`,
+				"25.0% of 40 received messages were duplicates",
+				"we requested 8 of the 40 message IDs announced to us (IWANT/IHAVE 0.20)",
+				"/eth2/aaaa0000/beacon_block/ssz_snappy",
+				"10 (25.0%)",
+				`4 <span class="text-red-600">(1 rejected)</span>`,
+				"We published 4 messages, at most 4 in one bucket.",
+				"Our own validator rejected 1 of them (25.0%).",
+				"validation failed: 1",
+			},
+		},
+		{
+			name: "status tracking",
+			setup: func(report *Report) {
+				report.StatusTracking = &peer.StatusTracking{
+					StallSeconds:    600,
+					Peers:           3,
+					Updates:         12,
+					Inbound:         7,
+					Failures:        1,
+					HeadAdvancing:   2,
+					MedianLatencyMs: 180,
+					MaxLatencyMs:    2400,
+					Stalled: []peer.StalledPeer{{
+						PeerID:         "16Uiu2HAmStalledPeer",
+						ClientType:     "prysm",
+						HeadSlot:       123456,
+						FinalizedEpoch: 3856,
+						StalledSince:   start.Add(5 * time.Minute),
+						LastStatusAt:   start.Add(20 * time.Minute),
+						StalledSeconds: 900,
+						Updates:        4,
+					}},
+					RetriedPeers:      1,
+					FailedThenGoodbye: 1,
+					IdentifyRetries: []peer.IdentifyRetryPeer{{
+						PeerID:     "16Uiu2HAmRetriedPeer",
+						ClientType: "nimbus",
+						IdentifyAttempts: peer.IdentifyAttempts{
+							Attempts:           3,
+							Failed:             2,
+							AttemptsToIdentify: 3,
+							LatenciesMs:        []float64{250, 1250, 4100},
+							FailedThenGoodbye:  true,
+						},
+					}},
+				}
+			},
+			expected: []string{
+				`id="section-status-tracking"`,
+				"12 statuses from 3 peers, 7 sent by the peers",
+				"of which 1 failed",
+				"180ms after connecting at the median, 2400ms at most",
+				"123456",
+				"12:05:00",
+				`id="identify-retries"`,
+				"Our status requests failed for 1 peer, 0 of which never answered one",
+				"3 attempts",
+				"250ms, 1250ms, 4100ms",
+			},
+		},
+		{
+			name: "event sampling",
+			setup: func(report *Report) {
+				report.EventSampling = &peer.EventSampling{Types: []peer.EventTypeSampling{
+					{EventType: "PEERSCORE", Rate: 5, Seen: 1200, Recorded: 250, Factor: 4.8},
+					{EventType: "PRUNE", Rate: 10, Seen: 30, Recorded: 4, Factor: 7.5},
+				}}
+			},
+			expected: []string{`id="section-event-sampling"`, "PEERSCORE", "1 in 5", "1200", "4.80", "1 in 10", "7.50"},
+		},
+		{
+			name: "head divergence",
+			setup: func(report *Report) {
+				report.HeadDivergence = &peer.HeadDivergence{
+					ThresholdSlots:       32,
+					FollowSeconds:        300,
+					Peers:                40,
+					Statuses:             120,
+					Estimated:            15,
+					DivergingPeers:       3,
+					Episodes:             4,
+					WithScoreDrop:        2,
+					WithGoodbye:          3,
+					WithDisconnect:       3,
+					DivergingSessions:    4,
+					DivergingGoodbyes:    3,
+					InSyncSessions:       50,
+					InSyncGoodbyes:       5,
+					DivergingGoodbyeRate: 0.75,
+					InSyncGoodbyeRate:    0.1,
+					Top: []peer.DivergenceEpisode{{
+						PeerID:       "16Uiu2HAmLaggingPeer",
+						ClientType:   "lodestar",
+						Session:      2,
+						Start:        start.Add(10 * time.Minute),
+						End:          start.Add(14 * time.Minute),
+						Statuses:     3,
+						MaxBehind:    96,
+						ScoreDrop:    24.5,
+						ScoreDropped: true,
+						Goodbye:      true,
+						Disconnected: true,
+					}},
+				}
+			},
+			expected: []string{
+				`id="section-head-divergence"`, "120 statuses from 40 peers", "15 against our head estimated",
+				"3 peers trailed us by more than 32 slots in 4 episodes", "75.0%", "3 of 4", "10.0%", "lodestar", "12:10:00", "96 slots", "24.500",
+			},
+		},
+		{
+			name: "clock skew",
+			setup: func(report *Report) {
+				report.ClockSkew = &clockskew.Result{
+					ThresholdMs: 500,
+					MaxOffsetMs: 2350,
+					Skewed:      true,
+					Beacon: []clockskew.BeaconSample{
+						{CheckedAt: start.Add(time.Minute), OffsetMs: 2350, UncertaintyMs: 520, RoundTripMs: 40, HeadSlot: 1001, LocalSlot: 1000, Skewed: true},
+						{CheckedAt: start.Add(time.Hour), Error: "beacon API returned status 503"},
+					},
+					Peers: &peer.SlotDrift{Peers: 12, Statuses: 30, AheadOfUs: 9, MedianSlotDrift: 0.5},
+				}
+			},
+			expected: []string{
+				"The local clock was skewed during this run, up to &#43;2350 ms from the beacon node.",
+				"skew beyond 500 ms",
+				`id="section-clock-skew"`,
+				"&#43;2350 &plusmn; 520 ms",
+				"1001 / 1000",
+				"beacon API returned status 503",
+				"&#43;0.5 slots",
+			},
+		},
+		{
+			name: "score consensus",
+			setup: func(report *Report) {
+				report.ScoreConsensus = &peer.ConsensusView{
+					Instances:   []string{"eu-west", "us-east"},
+					SharedPeers: 40,
+					Verdicts:    map[string]int{peer.ConsensusHostileToUs: 3, peer.ConsensusGenerallyBad: 2, peer.ConsensusAgreed: 35},
+					Peers: []peer.ConsensusPeer{{
+						PeerID:         "16Uiu2HAmHostilePeer",
+						ClientType:     "lighthouse",
+						OurScore:       -12.5,
+						ConsensusScore: peer.ConsensusScore{Vantages: 2, OtherMedian: 4.25, OtherMin: 3, OtherMax: 5.5, Verdict: peer.ConsensusHostileToUs},
+					}},
+				}
+			},
+			expected: []string{
+				`id="section-score-consensus"`,
+				"(eu-west, us-east)",
+				"Peers scored by both</th><td class=\"px-3 py-2\">40",
+				"median 4.250 from 2 vantage points",
+				"Hostile to us</td>",
+			},
+		},
+		{
+			name: "score consensus feed failed",
+			setup: func(report *Report) {
+				report.ScoreConsensus = &peer.ConsensusView{Error: "score feed returned status 502"}
+			},
+			expected: []string{"Score feed failed: <span class=\"font-mono\">score feed returned status 502"},
+		},
+		{
+			name: "time slices",
+			setup: func(report *Report) {
+				report.TimeSlices = []peer.TimeSlice{
+					{Start: start, End: start.Add(10 * time.Minute), Connections: 40, SuccessfulHandshakes: 36, FailedHandshakes: 4, HandshakeSuccessRate: 0.9, ScoredPeers: 30, MeanScore: 12.5},
+					{Start: start.Add(10 * time.Minute), End: start.Add(20 * time.Minute), Connections: 20, SuccessfulHandshakes: 5, FailedHandshakes: 15, HandshakeSuccessRate: 0.25,
+						Disconnects: 18, Goodbyes: 9, GoodbyeReasons: []peer.GoodbyeReasonCount{{Code: 129, Reason: "client shutdown", Count: 9}}},
+				}
+			},
+			expected: []string{`id="section-time-slices"`, "12:00 - 12:10", "36 (90.0%)", "12:10 - 12:20", "5 (25.0%)", "129 client shutdown: 9"},
+		},
+		{
+			name: "epochs",
+			setup: func(report *Report) {
+				report.Epochs = []peer.EpochSlice{
+					{Epoch: 368748, TimeSlice: peer.TimeSlice{Start: start, End: start.Add(384 * time.Second), Connections: 10, SuccessfulHandshakes: 8, FailedHandshakes: 2,
+						HandshakeSuccessRate: 0.8, ScoredPeers: 6, MeanScore: 4.25}, Prunes: 3},
+					{Epoch: 368749, TimeSlice: peer.TimeSlice{Start: start.Add(384 * time.Second), End: start.Add(10 * time.Minute), Disconnects: 4, Goodbyes: 2,
+						GoodbyeReasons: []peer.GoodbyeReasonCount{{Code: 3, Reason: "client error", Count: 2}}}, Prunes: 11},
+				}
+			},
+			expected: []string{`id="section-epochs"`, ">368748<", "12:00:00 - 12:06:24", "8 (80.0%)", ">368749<", "3 client error: 2", ">11<"},
+		},
+		{
+			name: "starvation",
+			setup: func(report *Report) {
+				report.Starvation = []watchdog.Window{
+					{From: start.Add(10 * time.Minute), To: start.Add(20 * time.Minute), Seconds: 600, Resolved: true, Goroutines: 812, KnownPeers: 40, ConnectedPeers: 12, Restarted: true},
+					{From: start.Add(50 * time.Minute), To: start.Add(time.Hour), Seconds: 600, Goroutines: 815, KnownPeers: 44, RestartError: "failed to start Hermes: address already in use"},
+				}
+			},
+			expected: []string{
+				"No events arrived from Hermes during the windows below",
+				"2025-06-01 12:10:00 to 12:20:00 (600s), 12 of 40 peers connected, 812 goroutines, Hermes restarted",
+				"2025-06-01 12:50:00 to 13:00:00 (600s, until the end of the run), 0 of 44 peers connected, 815 goroutines, restart failed: failed to start Hermes: address already in use",
+			},
+		},
+		{
+			name: "req/resp abuse",
+			setup: func(report *Report) {
+				report.Peers[abuser.PeerID] = abuser
+				report.Peers["16Uiu2HAmWellKept"] = &peer.Stats{PeerID: "16Uiu2HAmWellKept", ClientType: "teku"}
+			},
+			expected: []string{`id="section-reqresp-abuse"`, "prysm (malformed: 1 rate_limited: 1 )", "HANDLE_PING: 1", "HANDLE_STATUS: 1"},
+		},
+		{
+			name: "peer id reflection fallback",
+			setup: func(report *Report) {
+				report.DataQuality = &peer.DataQualityStats{
+					EventsChecked:                   100,
+					PeerIDReflectionFallbacks:       3,
+					PeerIDReflectionFallbacksByType: map[string]int{"eth.TraceEventFuluBlock": 3},
+				}
+			},
+			expected: []string{"Peer IDs found by reflection", "eth.TraceEventFuluBlock: 3"},
+		},
+		{
+			name: "topic whitelist",
+			setup: func(report *Report) {
+				report.TopicWhitelist = &peer.TopicWhitelist{
+					Topics:             []string{"beacon_block", "beacon_aggregate_and_proof"},
+					DroppedEvents:      120,
+					DroppedByType:      map[string]int{"GRAFT": 20, "HANDLE_MESSAGE": 100},
+					DroppedTopicScores: 40,
+				}
+			},
+			expected: []string{
+				`Collection was restricted to the gossip topics <code class="font-mono">beacon_block</code>, <code class="font-mono">beacon_aggregate_and_proof</code>.`,
+				"120 events of other topics were discarded",
+				"HANDLE_MESSAGE: 100",
+				"40 topic scores were removed from peer scores",
+			},
+		},
+		{
+			name: "goodbye reconnects",
+			setup: func(report *Report) {
+				report.Peers["16Uiu2HAmShed"] = &peer.Stats{PeerID: "16Uiu2HAmShed", ClientType: "lighthouse", ConnectionSessions: []peer.ConnectionSession{
+					{ConnectedAt: at(0), DisconnectedAt: at(time.Minute), Disconnected: true,
+						GoodbyeEvents: []peer.GoodbyeEvent{{Code: 129, Reason: "too many peers"}}},
+					{ConnectedAt: at(90 * time.Second)},
+				}}
+			},
+			expected: []string{`id="section-goodbye-reconnects"`, `129 <span class="text-gray-500">(too many peers)</span>`, "1 (100.0%)", "1 of 1"},
+		},
+		{
+			name: "peer overlap",
+			setup: func(report *Report) {
+				report.PeerOverlap = &peer.PeerOverlap{
+					Peers:     4,
+					Returning: 3,
+					New:       1,
+					Runs: []peer.RunOverlap{
+						{File: "reports/yesterday.json", StartTime: start.Add(-24 * time.Hour), PreviousPeers: 5, Returning: 3, New: 1, Gone: 2, Jaccard: 0.5},
+					},
+					Clients: []peer.ClientRetention{
+						{Client: "lighthouse", Peers: 4, Returning: 3, New: 1, PreviousPeers: 5, RetentionRate: 0.6},
+					},
+				}
+			},
+			expected: []string{`id="section-peer-overlap"`, "3 of this run's 4 peers (75.0%)", "reports/yesterday.json", "0.50", "3 of 5 (60.0%)"},
+		},
+		{
+			name: "negotiation failures",
+			setup: func(report *Report) {
+				report.NegotiationFailures = &peer.NegotiationFailures{
+					Inbound: &peer.NegotiationCounts{
+						Direction:   peer.NegotiationInbound,
+						Failures:    4,
+						Unsupported: 1,
+						ByStage:     map[string]int{peer.NegotiationSecurity: 3, peer.NegotiationMuxer: 1},
+						Examples:    map[string]string{peer.NegotiationSecurity: "failed to negotiate security protocol: EOF"},
+						Remotes:     2,
+						TopRemotes:  []peer.NegotiationRemote{{Remote: "/ip4/1.2.3.4", Failures: 3}},
+					},
+				}
+			},
+			expected: []string{`id="section-negotiation-failures"`, "1 (25.0%)", "security: 3", "/ip4/1.2.3.4"},
+		},
+		{
+			name: "bootstrap nodes",
+			setup: func(report *Report) {
+				report.BootstrapNodes = []peer.BootstrapNodeStatus{
+					{
+						BootstrapNode:        peer.BootstrapNode{PeerID: "16Uiu2HAmBoot", Address: "10.0.0.1:9000", Origin: peer.OriginBootnode},
+						ClientType:           "lighthouse",
+						Contacted:            true,
+						Responsive:           true,
+						Sessions:             2,
+						SuccessfulHandshakes: 2,
+						FirstConnectedAt:     at(45 * time.Second),
+						SecondsToConnect:     45,
+					},
+					{BootstrapNode: peer.BootstrapNode{PeerID: "16Uiu2HAmSilent", Origin: peer.OriginStatic}},
+				}
+			},
+			expected: []string{`id="section-bootstrap-nodes"`, "10.0.0.1:9000", "responsive", "not contacted", "16Uiu2HAmSilent"},
+		},
+		{
+			name: "max peers ramp",
+			setup: func(report *Report) {
+				report.MaxPeersRamp = &peer.MaxPeersRamp{
+					StepSeconds: 1800,
+					Steps: []peer.RampStep{
+						{
+							Step:      1,
+							MaxPeers:  50,
+							TimeSlice: peer.TimeSlice{Start: start, End: start.Add(30 * time.Minute), Connections: 40, SuccessfulHandshakes: 30},
+							MeanPeers: 45,
+							PeakPeers: 50,
+							Fill:      0.9,
+						},
+						{
+							Step:         2,
+							MaxPeers:     100,
+							Restart:      &peer.RampRestart{Step: 2, MaxPeers: 100, Start: start.Add(30 * time.Minute), End: start.Add(30*time.Minute + 2500*time.Millisecond)},
+							TimeSlice:    peer.TimeSlice{Start: start.Add(30 * time.Minute), End: start.Add(time.Hour), Disconnects: 12},
+							ShortLived:   3,
+							ChurnPerHour: 24,
+						},
+					},
+				}
+			},
+			expected: []string{`id="section-max-peers-ramp"`, "30 (75.0%)", "45.0 / 50", "0.90", "restart 2.5s", "3 (25.0%)", "24.0"},
+		},
+		{
+			name: "gossip leeches",
+			setup: func(report *Report) {
+				report.Peers[leech.PeerID] = leech
+			},
+			expected: []string{`id="section-gossip-leeches"`, "teku (1)", `<td class="px-3 py-2 text-red-600">25</td>`},
+		},
+		{
+			name: "resources",
+			setup: func(report *Report) {
+				report.Resources = &resources.Summary{
+					Limiter: "libp2p default limits",
+					Limits: []resources.Limit{
+						{Scope: resources.ScopeSystem, Conns: 128, ConnsInbound: 64, ConnsOutbound: 128, Streams: 4096, StreamsInbound: 2048, StreamsOutbound: 4096, FD: 256, MemoryBytes: 1 << 30},
+						{Scope: resources.ScopeConn, Conns: 1, ConnsInbound: 1, ConnsOutbound: 1, Streams: -1, StreamsInbound: -1, StreamsOutbound: -1, FD: 1, MemoryBytes: -1},
+					},
+					Blocked:               []resources.Blocked{{Direction: "inbound", Scope: "system", Resource: "connection", Count: 7}},
+					TotalBlocked:          7,
+					SampleIntervalSeconds: 10,
+					Samples:               []resources.Sample{{Start: start, End: start.Add(10 * time.Second), Blocked: 7}},
+					LimitedSessions:       2,
+					Peers:                 []resources.LimitedPeer{{PeerID: "16Uiu2HAmLimited", ClientType: "prysm", Sessions: 2}},
+				}
+			},
+			expected: []string{
+				`id="section-resources"`,
+				"The resource manager refused 7 resources in 1 intervals, and 2 sessions ended without a goodbye while it did.",
+				"128 (64 / 128)",
+				"1024 MiB",
+				"unlimited (unlimited / unlimited)",
+				"16Uiu2HAmLim",
+			},
+		},
+		{
+			name: "recommendations",
+			setup: func(report *Report) {
+				report.Recommendations = &recommend.Profile{
+					Recommendations: []recommend.Recommendation{
+						{Setting: recommend.SettingMaxPeers, Flag: "--max-peers", Current: "80", Suggested: "120", Change: true, Reason: "Our limit ended 4 of 10 sessions"},
+						{Setting: recommend.SettingSubnets, Current: "all", Suggested: "all", Reason: "No subnet topic had an unhealthy mesh on most of its subnets"},
+					},
+					Flags: []string{"--max-peers=120"},
+				}
+			},
+			expected: []string{
+				`id="section-recommendations"`,
+				`<td class="px-3 py-2 text-blue-700 font-medium">120</td>`,
+				`<td class="px-3 py-2">Keep</td>`,
+				`<code class="font-mono">--max-peers=120</code>`,
+			},
+		},
+		{
+			name:  "metric definitions",
+			setup: func(*Report) {},
+			expected: []string{
+				`id="section-metric-definitions"`,
+				"Failed Handshakes",
+				"failed_handshakes",
+				"Sessions with a connect time that were never identified",
+				"Reconnection Rate",
+				"reconnection_rate",
+				"A percentage of peers, not of connections",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &Report{
+				ValidationMode:   "delegated",
+				ValidationConfig: map[string]interface{}{"HermesVersion": "test"},
+				StartTime:        start,
+				EndTime:          start.Add(time.Hour),
+				Duration:         time.Hour,
+				Peers:            map[string]interface{}{},
+			}
+			tt.setup(report)
+
+			html := renderReport(t, report)
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(html, expected) {
+					t.Errorf("Expected rendered report to contain %q", expected)
+				}
+			}
+
+			for _, absent := range tt.absent {
+				if strings.Contains(html, absent) {
+					t.Errorf("Expected rendered report not to contain %q", absent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/alerting"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// NewRunSummary derives the statistics a regression check compares from a report. The
+// headline numbers come from CalculateHeadline, so alerts fire on the numbers the reports show.
+func NewRunSummary(report *Report, hermesVersion string) alerting.RunSummary {
+	headline := CalculateHeadline(report)
+
+	return alerting.RunSummary{
+		ValidationMode:       report.ValidationMode,
+		StartTime:            report.StartTime,
+		TotalConnections:     headline.TotalConnections,
+		SuccessfulHandshakes: headline.SuccessfulHandshakes,
+		FailedHandshakes:     headline.FailedHandshakes,
+		HandshakeSuccessRate: headline.HandshakeSuccessRate,
+		UniquePeers:          headline.UniquePeers,
+		HermesVersion:        hermesVersion,
+		Hermes:               peer.CalculateHermesMetricsFromInterface(report.Peers),
+	}
+}
+
+// LoadRunSummary reads the run summary of a previously saved JSON report.
+func LoadRunSummary(path string) (alerting.RunSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return alerting.RunSummary{}, fmt.Errorf("failed to read baseline report: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return alerting.RunSummary{}, fmt.Errorf("failed to parse baseline report: %w", err)
+	}
+
+	_, hermesVersion := runVersions(&report)
+
+	return NewRunSummary(&report, hermesVersion), nil
+}
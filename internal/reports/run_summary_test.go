@@ -0,0 +1,26 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRunSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	report := `{"validation_mode":"delegated","validation_config":{"HermesVersion":"v1"},"total_connections":10,"successful_handshakes":6,"failed_handshakes":2,"peers":{"a":{},"b":{}}}`
+
+	if err := os.WriteFile(path, []byte(report), 0600); err != nil {
+		t.Fatalf("Failed to write baseline: %v", err)
+	}
+
+	summary, err := LoadRunSummary(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The rate is of the handshakes attempted, as the report headline shows it, not of the connections
+	if summary.UniquePeers != 2 || summary.TotalConnections != 10 || summary.HandshakeSuccessRate != 0.75 || summary.HermesVersion != "v1" || summary.ValidationMode != "delegated" {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
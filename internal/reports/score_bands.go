@@ -0,0 +1,115 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// Score band chart dimensions, in SVG user units.
+const (
+	scoreChartWidth  = 800
+	scoreChartHeight = 200
+)
+
+// scoreBandChart lays the score bands out as SVG shapes, so the report draws them without
+// JavaScript.
+type scoreBandChart struct {
+	Width      int
+	Height     int
+	MeanArea   string // Polygon points between the p10 and p90 mean score
+	MeanLine   string // Polyline points of the p50 mean score
+	MinArea    string // Polygon points between the p10 and p90 lowest score
+	MinLine    string // Polyline points of the p50 lowest score
+	Top        float64
+	Bottom     float64
+	Thresholds []scoreChartLine // Gossipsub thresholds within the plotted range
+	ZeroY      float64
+}
+
+// scoreChartLine is a labelled horizontal line across the chart.
+type scoreChartLine struct {
+	Label string
+	Score float64
+	Y     float64
+}
+
+// newScoreBandChart lays out the score band buckets, nil when there are none to draw.
+func newScoreBandChart(bands *peer.ScoreBands) *scoreBandChart {
+	if bands == nil || len(bands.Buckets) == 0 {
+		return nil
+	}
+
+	buckets := bands.Buckets
+	chart := &scoreBandChart{Width: scoreChartWidth, Height: scoreChartHeight}
+
+	// Zero is always in range, so a run of positive scores still shows how far above it sits
+	for _, bucket := range buckets {
+		chart.Bottom = min(chart.Bottom, bucket.Min.P10, bucket.Mean.P10)
+		chart.Top = max(chart.Top, bucket.Min.P90, bucket.Mean.P90)
+	}
+
+	if chart.Top == chart.Bottom {
+		chart.Top = chart.Bottom + 1
+	}
+
+	first, last := buckets[0].Start, buckets[len(buckets)-1].Start
+	span := last.Sub(first).Seconds()
+
+	x := func(i int) float64 {
+		if span <= 0 {
+			return float64(chart.Width) / 2
+		}
+
+		return buckets[i].Start.Sub(first).Seconds() / span * float64(chart.Width)
+	}
+
+	y := func(score float64) float64 {
+		return (chart.Top - score) / (chart.Top - chart.Bottom) * float64(chart.Height)
+	}
+
+	chart.ZeroY = y(0)
+
+	band := func(lower, middle, upper func(peer.ScoreBandBucket) float64) (area, line string) {
+		upperPoints := make([]string, 0, len(buckets))
+		lowerPoints := make([]string, 0, len(buckets))
+		linePoints := make([]string, 0, len(buckets))
+
+		for i, bucket := range buckets {
+			upperPoints = append(upperPoints, fmt.Sprintf("%.1f,%.1f", x(i), y(upper(bucket))))
+			linePoints = append(linePoints, fmt.Sprintf("%.1f,%.1f", x(i), y(middle(bucket))))
+		}
+
+		for i := len(buckets) - 1; i >= 0; i-- {
+			lowerPoints = append(lowerPoints, fmt.Sprintf("%.1f,%.1f", x(i), y(lower(buckets[i]))))
+		}
+
+		return strings.Join(append(upperPoints, lowerPoints...), " "), strings.Join(linePoints, " ")
+	}
+
+	chart.MeanArea, chart.MeanLine = band(
+		func(b peer.ScoreBandBucket) float64 { return b.Mean.P10 },
+		func(b peer.ScoreBandBucket) float64 { return b.Mean.P50 },
+		func(b peer.ScoreBandBucket) float64 { return b.Mean.P90 },
+	)
+	chart.MinArea, chart.MinLine = band(
+		func(b peer.ScoreBandBucket) float64 { return b.Min.P10 },
+		func(b peer.ScoreBandBucket) float64 { return b.Min.P50 },
+		func(b peer.ScoreBandBucket) float64 { return b.Min.P90 },
+	)
+
+	for _, threshold := range []scoreChartLine{
+		{Label: "gossip", Score: constants.GossipScoreThreshold},
+		{Label: "publish", Score: constants.PublishScoreThreshold},
+		{Label: "graylist", Score: constants.GraylistScoreThreshold},
+	} {
+		if threshold.Score >= chart.Bottom {
+			threshold.Y = y(threshold.Score)
+			chart.Thresholds = append(chart.Thresholds, threshold)
+		}
+	}
+
+	return chart
+}
@@ -0,0 +1,273 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Shard sort orders, matching the sort options offered by the HTML report.
+const (
+	ShardOrderEvents    = "events"
+	ShardOrderMinScore  = "minScore"
+	ShardOrderScoreArea = "scoreArea"
+	ShardOrderClient    = "client"
+)
+
+// shardOrders lists the sort orders index shards are generated for.
+var shardOrders = []string{ShardOrderEvents, ShardOrderMinScore, ShardOrderScoreArea, ShardOrderClient}
+
+// ShardManifest describes the shards written alongside a split report data file.
+type ShardManifest struct {
+	ShardSize  int                 `json:"shard_size"`
+	TotalPeers int                 `json:"total_peers"`
+	Indexes    map[string][]string `json:"indexes"` // Sort order -> index shard files, one per page
+	Details    []string            `json:"details"` // Detail shard files, peers ordered by peer ID
+}
+
+// writeShards splits processed peers into pre-sorted, pre-paginated index shards and
+// per-page detail shards, written to a directory next to the data file. It stops with an
+// error when ctx is cancelled.
+func (g *DefaultGenerator) writeShards(ctx context.Context, peers []map[string]interface{}, dataFilename string) (*ShardManifest, error) {
+	shardSize := g.shardSize
+	if shardSize <= 0 {
+		shardSize = constants.DefaultShardSize
+	}
+
+	shardDir := shardDirFor(dataFilename)
+	if err := os.MkdirAll(shardDir, constants.DefaultDirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create shard directory: %w", err)
+	}
+
+	manifest := &ShardManifest{
+		ShardSize:  shardSize,
+		TotalPeers: len(peers),
+		Indexes:    make(map[string][]string, len(shardOrders)),
+		Details:    make([]string, 0),
+	}
+
+	// Detail shards hold the full peer records, ordered by peer ID
+	byPeerID := make([]map[string]interface{}, len(peers))
+	copy(byPeerID, peers)
+	sort.SliceStable(byPeerID, func(i, j int) bool {
+		return shardString(byPeerID[i], "peer_id") < shardString(byPeerID[j], "peer_id")
+	})
+
+	rows := make([]map[string]interface{}, 0, len(byPeerID))
+
+	for start := 0; start < len(byPeerID); start += shardSize {
+		if err := checkCancelled(ctx, "detail shards"); err != nil {
+			return nil, err
+		}
+
+		end := min(start+shardSize, len(byPeerID))
+		shardIndex := len(manifest.Details)
+		details := make(map[string]interface{}, end-start)
+
+		for _, p := range byPeerID[start:end] {
+			details[shardString(p, "peer_id")] = p
+
+			// Index rows are the peer record without session data or event buckets
+			row := make(map[string]interface{}, len(p))
+			for key, value := range p {
+				if key != "connection_sessions" && key != "session_attribution" && key != "event_buckets" {
+					row[key] = value
+				}
+			}
+
+			row["detail_shard"] = shardIndex
+			rows = append(rows, row)
+		}
+
+		file, err := g.writeShardFile(shardDir, fmt.Sprintf("details-%d", shardIndex), details)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Details = append(manifest.Details, file)
+	}
+
+	// Index shards hold summary rows, one file per page per sort order
+	for _, order := range shardOrders {
+		sorted := make([]map[string]interface{}, len(rows))
+		copy(sorted, rows)
+		sortShardRows(sorted, order)
+
+		files := make([]string, 0)
+
+		for start := 0; start < len(sorted); start += shardSize {
+			if err := checkCancelled(ctx, "index shards"); err != nil {
+				return nil, err
+			}
+
+			end := min(start+shardSize, len(sorted))
+
+			file, err := g.writeShardFile(shardDir, fmt.Sprintf("%s-%d", order, len(files)), sorted[start:end])
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, file)
+		}
+
+		manifest.Indexes[order] = files
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"shard_dir":     shardDir,
+		"shard_size":    shardSize,
+		"detail_shards": len(manifest.Details),
+	}).Info("Report shards generated")
+
+	return manifest, nil
+}
+
+// shardDirFor returns the directory the shards of a data file are written to.
+func shardDirFor(dataFilename string) string {
+	return strings.TrimSuffix(dataFilename, filepath.Ext(dataFilename)) + "-shards"
+}
+
+// writeShardFile writes a single shard as a script registering its data under the given key.
+// Shards are loaded via script tags so reports keep working when opened from the local filesystem.
+func (g *DefaultGenerator) writeShardFile(shardDir, key string, data interface{}) (string, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal shard %s: %w", key, err)
+	}
+
+	jsContent := fmt.Sprintf("window.reportShards = window.reportShards || {};\nwindow.reportShards[%q] = %s;\n", key, g.redactor.String(string(dataJSON)))
+	filename := key + ".js"
+
+	if err := os.WriteFile(filepath.Join(shardDir, filename), []byte(jsContent), constants.DefaultFilePermissions); err != nil {
+		return "", fmt.Errorf("failed to write shard %s: %w", key, err)
+	}
+
+	// Paths are relative to the HTML report, which lives next to the shard directory
+	return filepath.Base(shardDir) + "/" + filename, nil
+}
+
+// ReadDetailShards reads the full peer records back from the detail shards of a split report,
+// keyed by peer ID.
+func ReadDetailShards(shardDir string) (map[string]json.RawMessage, error) {
+	files, err := filepath.Glob(filepath.Join(shardDir, "details-*.js"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list detail shards: %w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no detail shards in %s", shardDir)
+	}
+
+	records := make(map[string]json.RawMessage)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read detail shard: %w", err)
+		}
+
+		// The data follows the assignment to its key, see writeShardFile
+		_, data, found := strings.Cut(string(content), "] = ")
+		if !found {
+			return nil, fmt.Errorf("detail shard %s holds no data", filepath.Base(file))
+		}
+
+		var details map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimSpace(data), ";")), &details); err != nil {
+			return nil, fmt.Errorf("failed to parse detail shard %s: %w", filepath.Base(file), err)
+		}
+
+		for peerID, record := range details {
+			records[peerID] = record
+		}
+	}
+
+	return records, nil
+}
+
+// sortShardRows sorts index rows the same way the HTML report sorts peers client-side.
+func sortShardRows(rows []map[string]interface{}, order string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+
+		switch order {
+		case ShardOrderEvents:
+			if ea, eb := shardInt(a, "event_count"), shardInt(b, "event_count"); ea != eb {
+				return ea > eb
+			}
+		case ShardOrderMinScore:
+			// Peers without scores sort last, lowest scores first
+			hasA, hasB := shardBool(a, "has_scores"), shardBool(b, "has_scores")
+			if hasA != hasB {
+				return hasA
+			}
+
+			if sa, sb := shardFloat(a, "min_peer_score"), shardFloat(b, "min_peer_score"); hasA && sa != sb {
+				return sa < sb
+			}
+		case ShardOrderScoreArea:
+			// Peers without scores sort last, the largest area below zero first
+			hasA, hasB := shardBool(a, "has_scores"), shardBool(b, "has_scores")
+			if hasA != hasB {
+				return hasA
+			}
+
+			if sa, sb := shardFloat(a, "score_area_below_zero"), shardFloat(b, "score_area_below_zero"); hasA && sa != sb {
+				return sa < sb
+			}
+		case ShardOrderClient:
+			if ca, cb := shardString(a, "client_type"), shardString(b, "client_type"); ca != cb {
+				return ca < cb
+			}
+		}
+
+		// Fall back to peer ID for a stable, deterministic order
+		return shardString(a, "peer_id") < shardString(b, "peer_id")
+	})
+}
+
+// shardString reads a string field from a processed peer record.
+func shardString(row map[string]interface{}, key string) string {
+	value, _ := row[key].(string)
+
+	return value
+}
+
+// shardInt reads an integer field from a processed peer record.
+func shardInt(row map[string]interface{}, key string) int {
+	switch value := row[key].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	default:
+		return 0
+	}
+}
+
+// shardFloat reads a float field from a processed peer record.
+func shardFloat(row map[string]interface{}, key string) float64 {
+	switch value := row[key].(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	default:
+		return 0
+	}
+}
+
+// shardBool reads a boolean field from a processed peer record.
+func shardBool(row map[string]interface{}, key string) bool {
+	value, _ := row[key].(bool)
+
+	return value
+}
@@ -0,0 +1,108 @@
+package reports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSortShardRows(t *testing.T) {
+	rows := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			{"peer_id": "a", "event_count": 5, "has_scores": false, "min_peer_score": 0.0, "client_type": "prysm"},
+			{"peer_id": "b", "event_count": 9, "has_scores": true, "min_peer_score": -2.0, "score_area_below_zero": -50.0, "client_type": "teku"},
+			{"peer_id": "c", "event_count": 1, "has_scores": true, "min_peer_score": 3.0, "score_area_below_zero": 0.0, "client_type": "lighthouse"},
+			{"peer_id": "d", "event_count": 9, "has_scores": true, "min_peer_score": -2.0, "score_area_below_zero": -300.0, "client_type": "lighthouse"},
+		}
+	}
+
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{order: ShardOrderEvents, expected: []string{"b", "d", "a", "c"}},
+		{order: ShardOrderMinScore, expected: []string{"b", "d", "c", "a"}},
+		{order: ShardOrderScoreArea, expected: []string{"d", "b", "c", "a"}},
+		{order: ShardOrderClient, expected: []string{"c", "d", "a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			sorted := rows()
+			sortShardRows(sorted, tt.order)
+
+			for i, expectedID := range tt.expected {
+				if got := shardString(sorted[i], "peer_id"); got != expectedID {
+					t.Errorf("Position %d: expected peer %s, got %s", i, expectedID, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteShards(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	g := &DefaultGenerator{
+		logger:      logger,
+		splitReport: true,
+		shardSize:   2,
+	}
+
+	peers := []map[string]interface{}{
+		{"peer_id": "a", "event_count": 1, "connection_sessions": []interface{}{}},
+		{"peer_id": "b", "event_count": 2, "connection_sessions": []interface{}{}},
+		{"peer_id": "c", "event_count": 3, "connection_sessions": []interface{}{}},
+	}
+
+	dataFile := filepath.Join(t.TempDir(), "report-data.js")
+
+	manifest, err := g.writeShards(context.Background(), peers, dataFile)
+	if err != nil {
+		t.Fatalf("Expected no error writing shards, got %v", err)
+	}
+
+	if manifest.TotalPeers != 3 || len(manifest.Details) != 2 {
+		t.Errorf("Expected 3 peers in 2 detail shards, got %d peers in %d shards", manifest.TotalPeers, len(manifest.Details))
+	}
+
+	for _, order := range shardOrders {
+		if len(manifest.Indexes[order]) != 2 {
+			t.Errorf("Expected 2 index shards for %s, got %d", order, len(manifest.Indexes[order]))
+		}
+	}
+
+	// The first events page holds the two busiest peers without session data
+	content, err := os.ReadFile(filepath.Join(filepath.Dir(dataFile), manifest.Indexes[ShardOrderEvents][0]))
+	if err != nil {
+		t.Fatalf("Expected events shard to be readable, got %v", err)
+	}
+
+	shard := string(content)
+	if !strings.Contains(shard, `window.reportShards["events-0"]`) {
+		t.Error("Expected events shard to register under its key")
+	}
+
+	if !strings.HasPrefix(strings.SplitN(shard, `"peer_id":"`, 2)[1], "c") {
+		t.Error("Expected busiest peer first in events shard")
+	}
+
+	if strings.Contains(shard, "connection_sessions") {
+		t.Error("Expected index shards to omit session data")
+	}
+
+	// The detail shards read back as every peer's full record
+	records, err := ReadDetailShards(shardDirFor(dataFile))
+	if err != nil {
+		t.Fatalf("Expected detail shards to read back, got %v", err)
+	}
+
+	if len(records) != 3 || !strings.Contains(string(records["b"]), "connection_sessions") {
+		t.Errorf("Expected 3 full peer records, got %d", len(records))
+	}
+}
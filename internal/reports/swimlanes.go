@@ -0,0 +1,63 @@
+package reports
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// swimlanesTemplate is the template rendered for the swimlane view.
+const swimlanesTemplate = "swimlanes"
+
+// SetSwimlanes sets the number of most churning peers drawn in the swimlane view, 0 disables it.
+func (g *DefaultGenerator) SetSwimlanes(peers int) {
+	g.swimlanePeers = peers
+}
+
+// BuildSwimlanes builds the compact timeline drawn in the swimlane view from the limit most
+// churning peers.
+func (dp *DefaultDataProcessor) BuildSwimlanes(report *Report, limit int) *peer.Swimlanes {
+	return peer.SwimlanesFromInterface(report.Peers, report.StartTime, report.EndTime, limit)
+}
+
+// generateSwimlanes renders the swimlane view of a report and saves it next to the report,
+// returning its filename. Nothing is written when the view is disabled or no peer connected.
+func (g *DefaultGenerator) generateSwimlanes(report *Report, reportFilename string) (string, error) {
+	processor, ok := g.dataProcessor.(*DefaultDataProcessor)
+	if !ok || g.swimlanePeers <= 0 {
+		return "", nil
+	}
+
+	swimlanes := processor.BuildSwimlanes(report, g.swimlanePeers)
+	if len(swimlanes.Lanes) == 0 {
+		return "", nil
+	}
+
+	content, err := g.templateManager.RenderTemplate(swimlanesTemplate, map[string]interface{}{
+		"GeneratedAt":    g.clock(),
+		"ValidationMode": report.ValidationMode,
+		"ReportFile":     filepath.Base(reportFilename),
+		"Swimlanes":      swimlanes,
+		"ScoreDrop":      constants.SwimlaneScoreDrop,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render swimlanes template: %w", err)
+	}
+
+	filename := g.generateTimestampedFilename(report.ValidationMode, constants.DefaultSwimlanesFile, report.Timestamp)
+
+	if err := g.fileManager.SaveHTML(filename, g.redactor.String(content)); err != nil {
+		return "", fmt.Errorf("failed to save swimlanes: %w", err)
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"filename": filename,
+		"lanes":    len(swimlanes.Lanes),
+	}).Info("Swimlane view generated successfully")
+
+	return filename, nil
+}
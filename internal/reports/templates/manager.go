@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"html/template"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -14,17 +17,32 @@ import (
 //go:embed *.html *.css
 var templateFS embed.FS
 
-// Manager handles template loading, parsing, and rendering.
+// tailwindCSS is the compiled Tailwind stylesheet inlined into every generated page, so reports
+// render the same offline, under a strict Content Security Policy and whichever Tailwind
+// version a CDN would serve today. Rebuild it with `make tailwind`.
+//
+//go:embed tailwind.css
+var tailwindCSS string
+
+// stylesheetFile names the stylesheet in a template override directory.
+const stylesheetFile = "tailwind.css"
+
+// Manager handles template loading, parsing, and rendering. Everything it renders is embedded
+// in the binary, unless an override directory replaces some of it.
 type Manager struct {
-	templates map[string]*template.Template
-	logger    logrus.FieldLogger
+	templates  map[string]*template.Template
+	overrides  map[string]string // Content of the templates an override directory replaced
+	stylesheet string
+	logger     logrus.FieldLogger
 }
 
 // NewManager creates a new template manager.
 func NewManager(logger logrus.FieldLogger) *Manager {
 	return &Manager{
-		templates: make(map[string]*template.Template),
-		logger:    logger.WithField("component", "template_manager"),
+		templates:  make(map[string]*template.Template),
+		overrides:  make(map[string]string),
+		stylesheet: tailwindCSS,
+		logger:     logger.WithField("component", "template_manager"),
 	}
 }
 
@@ -71,33 +89,56 @@ func (m *Manager) LoadTemplates() error {
 	return nil
 }
 
-// LoadTemplatesFromDir loads templates from a directory (for development/testing).
-func (m *Manager) LoadTemplatesFromDir(dir string) error {
-	m.logger.WithField("directory", dir).Info("Loading templates from directory")
+// LoadOverrides replaces embedded templates with the files of the same name in dir, so
+// reports can be customised without rebuilding. Only the templates present are replaced, a
+// tailwind.css in dir replaces the inlined stylesheet for the classes they add. A file matching
+// no embedded template is an error rather than silently ignored.
+func (m *Manager) LoadOverrides(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read template override directory: %w", err)
+	}
 
-	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
 		}
 
-		if d.IsDir() || !strings.HasSuffix(path, ".html") {
-			return nil
-		}
+		path := filepath.Join(dir, entry.Name())
 
-		// Create template name from filename
-		templateName := strings.TrimSuffix(filepath.Base(path), ".html")
+		switch {
+		case entry.Name() == stylesheetFile:
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read stylesheet override %s: %w", path, err)
+			}
 
-		// Parse template with helper functions
-		tmpl, err := template.New(templateName).Funcs(m.getTemplateFuncs()).ParseFiles(path)
-		if err != nil {
-			return fmt.Errorf("failed to parse template %s: %w", path, err)
-		}
+			m.stylesheet = string(content)
+			m.logger.WithField("file", path).Info("Loaded stylesheet override")
+		case strings.HasSuffix(entry.Name(), ".html"):
+			templateName := strings.TrimSuffix(entry.Name(), ".html")
+			if _, ok := m.templates[templateName]; !ok {
+				return fmt.Errorf("template override %s matches no embedded template, expected one of %s",
+					path, strings.Join(m.GetAvailableTemplates(), ", "))
+			}
 
-		m.templates[templateName] = tmpl
-		m.logger.WithField("template", templateName).Debug("Loaded template from file")
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read template override %s: %w", path, err)
+			}
 
-		return nil
-	})
+			tmpl, err := template.New(templateName).Funcs(m.getTemplateFuncs()).Parse(string(content))
+			if err != nil {
+				return fmt.Errorf("failed to parse template override %s: %w", path, err)
+			}
+
+			m.templates[templateName] = tmpl
+			m.overrides[templateName] = string(content)
+			m.logger.WithField("file", path).Info("Loaded template override")
+		}
+	}
+
+	return nil
 }
 
 // RenderReport renders the main report template with the given data.
@@ -120,8 +161,12 @@ func (m *Manager) RenderTemplate(templateName string, data interface{}) (string,
 	return output.String(), nil
 }
 
-// GetTemplate returns the raw template content for a given name.
+// GetTemplate returns the raw template content for a given name, the override's if replaced.
 func (m *Manager) GetTemplate(templateName string) (string, error) {
+	if content, ok := m.overrides[strings.TrimSuffix(templateName, ".html")]; ok {
+		return content, nil
+	}
+
 	if !strings.HasSuffix(templateName, ".html") {
 		templateName += ".html"
 	}
@@ -141,12 +186,17 @@ func (m *Manager) GetAvailableTemplates() []string {
 		templates = append(templates, name)
 	}
 
+	sort.Strings(templates)
+
 	return templates
 }
 
 // getTemplateFuncs returns template helper functions.
 func (m *Manager) getTemplateFuncs() template.FuncMap {
 	return template.FuncMap{
+		"tailwindCSS": func() template.CSS {
+			return template.CSS(m.stylesheet) //nolint:gosec // ok.
+		},
 		"formatDuration": func(seconds float64) string {
 			if seconds < 60 {
 				return fmt.Sprintf("%.1fs", seconds)
@@ -165,6 +215,20 @@ func (m *Manager) getTemplateFuncs() template.FuncMap {
 
 			return fmt.Sprintf("%.1f%%", percent)
 		},
+		"formatLimit": func(limit int) string {
+			if limit < 0 {
+				return "unlimited"
+			}
+
+			return strconv.Itoa(limit)
+		},
+		"formatMemoryLimit": func(bytes int64) string {
+			if bytes < 0 {
+				return "unlimited"
+			}
+
+			return fmt.Sprintf("%.0f MiB", float64(bytes)/(1<<20))
+		},
 		"formatScore": func(score float64) string {
 			return fmt.Sprintf("%.3f", score)
 		},
@@ -0,0 +1,60 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLoadOverrides(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "swimlanes.html"), []byte(`<style>{{tailwindCSS}}</style>custom {{.Title}}`), 0o600); err != nil {
+		t.Fatalf("failed to write template override: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "tailwind.css"), []byte(".custom{color:red}"), 0o600); err != nil {
+		t.Fatalf("failed to write stylesheet override: %v", err)
+	}
+
+	tm := NewManager(logger)
+	if err := tm.LoadTemplates(); err != nil {
+		t.Fatalf("Expected no error loading templates, got %v", err)
+	}
+
+	if err := tm.LoadOverrides(dir); err != nil {
+		t.Fatalf("Expected no error loading overrides, got %v", err)
+	}
+
+	html, err := tm.RenderTemplate("swimlanes", map[string]string{"Title": "lanes"})
+	if err != nil {
+		t.Fatalf("Expected no error rendering the override, got %v", err)
+	}
+
+	if html != "<style>.custom{color:red}</style>custom lanes" {
+		t.Errorf("Expected the override rendered with its stylesheet, got %q", html)
+	}
+
+	if content, _ := tm.GetTemplate("swimlanes"); !strings.HasPrefix(content, "<style>") {
+		t.Errorf("Expected the override's content, got %q", content)
+	}
+
+	// The templates left alone are still the embedded ones
+	if content, _ := tm.GetTemplate("report"); !strings.Contains(content, `id="section-beacon-sync"`) {
+		t.Error("Expected the embedded report template")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "reprot.html"), []byte("typo"), 0o600); err != nil {
+		t.Fatalf("failed to write template override: %v", err)
+	}
+
+	if err := tm.LoadOverrides(dir); err == nil || !strings.Contains(err.Error(), "matches no embedded template") {
+		t.Errorf("Expected a misnamed override to fail, got %v", err)
+	}
+}
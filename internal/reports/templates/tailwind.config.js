@@ -0,0 +1,17 @@
+// Builds tailwind.css, the stylesheet inlined into every generated page, from the classes the
+// templates, the AI analysis prompt and the GitHub Pages index use. Run `make tailwind` from
+// the repository root after changing any of them.
+module.exports = {
+  content: [
+    './internal/reports/templates/*.html',
+    './internal/reports/ai_analyzer.go',
+    './internal/reports/ai_references.go',
+    './index-template.html',
+    './scripts/generate_index.py',
+  ],
+  // The peer timeline builds its badge colours in JavaScript
+  safelist: [
+    { pattern: /^bg-(green|blue|purple|orange|red)-100$/ },
+    { pattern: /^text-(green|blue|purple|orange|red)-800$/ },
+  ],
+};
@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	classAttribute = regexp.MustCompile(`class="([^"]*)"`)
+	templateAction = regexp.MustCompile(`\{\{.*?\}\}`)
+)
+
+// classEscaper escapes a class name the way Tailwind writes it in a selector.
+var classEscaper = strings.NewReplacer(":", `\:`, "/", `\/`, ".", `\.`, "[", `\[`, "]", `\]`, "%", `\%`, "(", `\(`, ")", `\)`)
+
+// TestTailwindCoversTemplateClasses fails when a template uses a class the inlined stylesheet
+// has no rule for, which means tailwind.css needs rebuilding with `make tailwind`. Classes
+// built by template actions or JavaScript are left to the Tailwind safelist.
+func TestTailwindCoversTemplateClasses(t *testing.T) {
+	styles, err := fs.ReadFile(templateFS, "styles.css")
+	if err != nil {
+		t.Fatalf("Expected no error reading styles.css, got %v", err)
+	}
+
+	stylesheet := tailwindCSS + string(styles)
+
+	names, err := fs.Glob(templateFS, "*.html")
+	if err != nil {
+		t.Fatalf("Expected no error listing templates, got %v", err)
+	}
+
+	for _, name := range names {
+		content, err := fs.ReadFile(templateFS, name)
+		if err != nil {
+			t.Fatalf("Expected no error reading %s, got %v", name, err)
+		}
+
+		checked := make(map[string]bool)
+
+		for _, match := range classAttribute.FindAllStringSubmatch(templateAction.ReplaceAllString(string(content), "\x00"), -1) {
+			if strings.ContainsAny(match[1], "'$") {
+				continue
+			}
+
+			for _, class := range strings.Fields(match[1]) {
+				if checked[class] || strings.Contains(class, "\x00") {
+					continue
+				}
+
+				checked[class] = true
+
+				rule := regexp.MustCompile(`\.` + regexp.QuoteMeta(classEscaper.Replace(class)) + `[\s{:,.>\[)]`)
+				if !rule.MatchString(stylesheet) {
+					t.Errorf("Expected a rule for class %q used in %s", class, name)
+				}
+			}
+		}
+	}
+}
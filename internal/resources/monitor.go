@@ -0,0 +1,276 @@
+// Package resources records the libp2p resource manager limits Hermes runs with and the
+// resources the resource manager refused during a run.
+package resources
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// blockedMetric is the gauge the resource manager's stats reporter counts refusals in.
+const blockedMetric = "libp2p_rcmgr_blocked_resources"
+
+// blockedKey identifies one series of the blocked resources gauge.
+type blockedKey struct {
+	direction string
+	scope     string
+	resource  string
+}
+
+// Monitor samples the resources the resource manager refused. Hermes builds its resource
+// manager with the stats reporter, which counts refusals in package level metrics, so they
+// are read by registering those metrics with a registry of our own. The counts cover every
+// host of the process.
+type Monitor struct {
+	gatherer prometheus.Gatherer
+
+	mu       sync.Mutex
+	baseline map[blockedKey]float64 // Counts when the monitor started, earlier runs of the process included
+	last     float64
+	lastAt   time.Time
+	samples  []Sample
+}
+
+// NewMonitor starts counting refusals from now.
+func NewMonitor(now time.Time) *Monitor {
+	registry := prometheus.NewRegistry()
+	rcmgr.MustRegisterWith(registry)
+
+	return newMonitor(registry, now)
+}
+
+// newMonitor starts counting the refusals gathered from gatherer from now.
+func newMonitor(gatherer prometheus.Gatherer, now time.Time) *Monitor {
+	m := &Monitor{gatherer: gatherer, lastAt: now, samples: make([]Sample, 0)}
+	m.baseline = m.blocked()
+
+	return m
+}
+
+// blocked returns the refusals counted in the process so far.
+func (m *Monitor) blocked() map[blockedKey]float64 {
+	counts := make(map[blockedKey]float64)
+
+	families, err := m.gatherer.Gather()
+	if err != nil {
+		return counts
+	}
+
+	for _, family := range families {
+		if family.GetName() != blockedMetric {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			var key blockedKey
+
+			for _, label := range metric.GetLabel() {
+				switch label.GetName() {
+				case "dir":
+					key.direction = label.GetValue()
+				case "scope":
+					key.scope = label.GetValue()
+				case "resource":
+					key.resource = label.GetValue()
+				}
+			}
+
+			counts[key] += metric.GetGauge().GetValue()
+		}
+	}
+
+	return counts
+}
+
+// counted reports whether a series counts each refusal once. Connections and streams are
+// counted per direction and again as a total, memory only as a total.
+func counted(key blockedKey) bool {
+	return key.direction != "" || key.resource == "memory"
+}
+
+// total returns the refusals since the monitor started.
+func (m *Monitor) total(counts map[blockedKey]float64) float64 {
+	var total float64
+
+	for key, count := range counts {
+		if counted(key) {
+			total += count - m.baseline[key]
+		}
+	}
+
+	return total
+}
+
+// Sample closes the interval since the previous sample, keeping it when resources were
+// refused in it.
+func (m *Monitor) Sample(now time.Time) {
+	total := m.total(m.blocked())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if blocked := int(math.Round(total - m.last)); blocked > 0 {
+		m.samples = append(m.samples, Sample{Start: m.lastAt, End: now, Blocked: blocked})
+	}
+
+	m.last = total
+	m.lastAt = now
+}
+
+// Summary takes a last sample and returns the limits and the refusals of the run.
+func (m *Monitor) Summary(now time.Time) *Summary {
+	m.Sample(now)
+
+	counts := m.blocked()
+
+	summary := &Summary{
+		Limiter:               "libp2p default limits, auto-scaled to the host's memory and file descriptors",
+		Limits:                Limits(),
+		Blocked:               make([]Blocked, 0),
+		SampleIntervalSeconds: constants.ResourceSampleInterval.Seconds(),
+		Peers:                 make([]LimitedPeer, 0),
+		Note:                  "Counts cover every Hermes host of the process",
+	}
+
+	for key, count := range counts {
+		blocked := int(math.Round(count - m.baseline[key]))
+		if !counted(key) || blocked <= 0 {
+			continue
+		}
+
+		summary.Blocked = append(summary.Blocked, Blocked{Direction: key.direction, Scope: key.scope, Resource: key.resource, Count: blocked})
+		summary.TotalBlocked += blocked
+	}
+
+	sort.Slice(summary.Blocked, func(i, j int) bool {
+		a, b := summary.Blocked[i], summary.Blocked[j]
+
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+
+		return a.Direction < b.Direction
+	})
+
+	m.mu.Lock()
+	summary.Samples = append([]Sample(nil), m.samples...)
+	m.mu.Unlock()
+
+	return summary
+}
+
+// Limits returns the limits Hermes configures its resource manager with: libp2p's defaults
+// scaled to the host, as eth.NodeConfig does.
+func Limits() []Limit {
+	config := rcmgr.DefaultLimits.AutoScale().ToPartialLimitConfig()
+
+	return []Limit{
+		limit(ScopeSystem, config.System),
+		limit(ScopeTransient, config.Transient),
+		limit(ScopePeer, config.PeerDefault),
+		limit(ScopeConn, config.Conn),
+		limit(ScopeStream, config.Stream),
+	}
+}
+
+// limit converts the limits of a scope, -1 standing for unlimited.
+func limit(scope string, limits rcmgr.ResourceLimits) Limit {
+	value := func(v rcmgr.LimitVal) int {
+		if n := v.Build(0); n != math.MaxInt {
+			return n
+		}
+
+		return -1
+	}
+
+	memory := limits.Memory.Build(0)
+	if memory == math.MaxInt64 {
+		memory = -1
+	}
+
+	return Limit{
+		Scope:           scope,
+		Conns:           value(limits.Conns),
+		ConnsInbound:    value(limits.ConnsInbound),
+		ConnsOutbound:   value(limits.ConnsOutbound),
+		Streams:         value(limits.Streams),
+		StreamsInbound:  value(limits.StreamsInbound),
+		StreamsOutbound: value(limits.StreamsOutbound),
+		FD:              value(limits.FD),
+		MemoryBytes:     memory,
+	}
+}
+
+// AttributeSessions tags the sessions that ended without a goodbye in an interval in which
+// resources were refused, and lists their peers. Sessions closed at a checkpoint gap, during
+// shutdown or by a MaxPeers ramp restart were ended by the run.
+func AttributeSessions(peers map[string]*peer.Stats, summary *Summary) {
+	limited := make(map[string]int)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+			session.EndedByResourceLimit = false
+
+			if !session.Disconnected || session.DisconnectedAt == nil || len(session.GoodbyeEvents) > 0 ||
+				session.EndedByGap || session.EndedInShutdown || session.EndedByRampRestart {
+				continue
+			}
+
+			if !blockedAt(summary.Samples, *session.DisconnectedAt) {
+				continue
+			}
+
+			session.EndedByResourceLimit = true
+			summary.LimitedSessions++
+			limited[peerID]++
+		}
+	}
+
+	for peerID, sessions := range limited {
+		summary.Peers = append(summary.Peers, LimitedPeer{PeerID: peerID, ClientType: peers[peerID].ClientType, Sessions: sessions})
+	}
+
+	sort.Slice(summary.Peers, func(i, j int) bool {
+		if summary.Peers[i].Sessions != summary.Peers[j].Sessions {
+			return summary.Peers[i].Sessions > summary.Peers[j].Sessions
+		}
+
+		return summary.Peers[i].PeerID < summary.Peers[j].PeerID
+	})
+
+	if len(summary.Peers) > constants.ResourceLimitedPeers {
+		summary.Peers = summary.Peers[:constants.ResourceLimitedPeers]
+	}
+}
+
+// blockedAt reports whether t falls in an interval with refusals.
+func blockedAt(samples []Sample, t time.Time) bool {
+	for _, sample := range samples {
+		if t.After(sample.Start) && !t.After(sample.End) {
+			return true
+		}
+	}
+
+	return false
+}
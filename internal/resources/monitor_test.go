@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestMonitor(t *testing.T) {
+	// The resource manager's stats reporter counts refusals per direction and again as a total
+	blocked := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: blockedMetric}, []string{"dir", "scope", "resource"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(blocked)
+
+	// Refusals before the run started are left out
+	blocked.WithLabelValues("inbound", "system", "connection").Add(5)
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	monitor := newMonitor(registry, start)
+
+	monitor.Sample(start.Add(10 * time.Second))
+
+	blocked.WithLabelValues("inbound", "system", "connection").Add(3)
+	blocked.WithLabelValues("", "system", "connection").Add(3)
+	blocked.WithLabelValues("", "transient", "memory").Add(1)
+	monitor.Sample(start.Add(20 * time.Second))
+
+	summary := monitor.Summary(start.Add(30 * time.Second))
+
+	if summary.TotalBlocked != 4 || len(summary.Blocked) != 2 || summary.Blocked[0].Count != 3 || summary.Blocked[0].Direction != "inbound" {
+		t.Fatalf("Expected 3 inbound connections and 1 memory reservation refused, got %+v", summary.Blocked)
+	}
+
+	if len(summary.Samples) != 1 || !summary.Samples[0].Start.Equal(start.Add(10*time.Second)) || summary.Samples[0].Blocked != 4 {
+		t.Fatalf("Expected one interval with refusals, got %+v", summary.Samples)
+	}
+
+	if len(summary.Limits) != 5 || summary.Limits[0].Scope != ScopeSystem || summary.Limits[0].Conns <= 0 {
+		t.Errorf("Expected the auto-scaled default limits, got %+v", summary.Limits)
+	}
+
+	at := func(offset time.Duration) *time.Time {
+		t := start.Add(offset)
+
+		return &t
+	}
+
+	peers := map[string]*peer.Stats{
+		"16Uiu2A": {ClientType: "lighthouse", ConnectionSessions: []peer.ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(15 * time.Second), Disconnected: true},
+			{ConnectedAt: at(0), DisconnectedAt: at(25 * time.Second), Disconnected: true},
+		}},
+		"16Uiu2B": {ClientType: "teku", ConnectionSessions: []peer.ConnectionSession{
+			{ConnectedAt: at(0), DisconnectedAt: at(15 * time.Second), Disconnected: true, GoodbyeEvents: []peer.GoodbyeEvent{{Code: 129}}},
+			{ConnectedAt: at(0), DisconnectedAt: at(18 * time.Second), Disconnected: true, EndedByRampRestart: true},
+			{ConnectedAt: at(0)},
+		}},
+	}
+
+	AttributeSessions(peers, summary)
+
+	if summary.LimitedSessions != 1 || len(summary.Peers) != 1 || summary.Peers[0].PeerID != "16Uiu2A" {
+		t.Fatalf("Expected only the session closed without a goodbye during the refusals, got %d sessions of %+v", summary.LimitedSessions, summary.Peers)
+	}
+
+	if !peers["16Uiu2A"].ConnectionSessions[0].EndedByResourceLimit || peers["16Uiu2A"].ConnectionSessions[1].EndedByResourceLimit {
+		t.Error("Expected the session ending in the interval with refusals to be tagged")
+	}
+}
@@ -0,0 +1,63 @@
+package resources
+
+import "time"
+
+// Scopes of the resource manager limits recorded.
+const (
+	ScopeSystem    = "system"    // Everything the host holds
+	ScopeTransient = "transient" // Connections and streams not yet attached to a peer or protocol
+	ScopePeer      = "peer"      // Each peer, unless a peer has limits of its own
+	ScopeConn      = "conn"      // Each connection
+	ScopeStream    = "stream"    // Each stream
+)
+
+// Limit is the resource manager's limits in one scope. A limit of -1 is unlimited.
+type Limit struct {
+	Scope           string `json:"scope"`
+	Conns           int    `json:"conns"`
+	ConnsInbound    int    `json:"conns_inbound"`
+	ConnsOutbound   int    `json:"conns_outbound"`
+	Streams         int    `json:"streams"`
+	StreamsInbound  int    `json:"streams_inbound"`
+	StreamsOutbound int    `json:"streams_outbound"`
+	FD              int    `json:"fd"`
+	MemoryBytes     int64  `json:"memory_bytes"`
+}
+
+// Blocked counts the resources the resource manager refused in one scope.
+type Blocked struct {
+	Direction string `json:"direction,omitempty"` // inbound or outbound, empty for memory
+	Scope     string `json:"scope"`               // Scope whose limit was hit, e.g. system or peer
+	Resource  string `json:"resource"`            // connection, stream or memory
+	Count     int    `json:"count"`
+}
+
+// Sample is a sampling interval in which the resource manager blocked resources.
+type Sample struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Blocked int       `json:"blocked"`
+}
+
+// LimitedPeer is a peer whose sessions ended while the resource manager was blocking.
+type LimitedPeer struct {
+	PeerID     string `json:"peer_id"`
+	ClientType string `json:"client_type"`
+	Sessions   int    `json:"sessions"`
+}
+
+// Summary is the libp2p resource manager configuration Hermes ran with and the resources it
+// refused. Hitting our own limits closes connections and resets streams without a goodbye,
+// which reads as peers dropping us. The resource manager does not say which peer it refused,
+// so sessions ending without a goodbye in an interval with blocks are attributed to it.
+type Summary struct {
+	Limiter               string        `json:"limiter"` // How the limits were derived
+	Limits                []Limit       `json:"limits"`
+	Blocked               []Blocked     `json:"blocked"` // Most frequent first
+	TotalBlocked          int           `json:"total_blocked"`
+	SampleIntervalSeconds float64       `json:"sample_interval_seconds"`
+	Samples               []Sample      `json:"samples"` // Only the intervals with blocks
+	LimitedSessions       int           `json:"limited_sessions"`
+	Peers                 []LimitedPeer `json:"peers"` // Most limited sessions first, at most ResourceLimitedPeers
+	Note                  string        `json:"note,omitempty"`
+}
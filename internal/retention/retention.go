@@ -0,0 +1,291 @@
+// Package retention prunes the artifacts of old runs from the output directory. Runs are found
+// by their manifests, so only files a run recorded writing are ever removed.
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+// Validate rejects a policy that would keep no run.
+func (p Policy) Validate() error {
+	if p.KeepLast < 0 || p.KeepFor < 0 {
+		return errors.New("--keep-last and --keep-days cannot be negative")
+	}
+
+	if p.KeepLast == 0 && p.KeepFor == 0 {
+		return errors.New("no retention given, set --keep-last, --keep-days or both")
+	}
+
+	return nil
+}
+
+// FindRuns reads the run manifests in dir. Artifact paths are resolved against dir, where the
+// runs wrote them. Files that are not manifests, and manifests that cannot be read, are skipped.
+func FindRuns(dir string) ([]Run, error) {
+	ext := filepath.Ext(constants.DefaultManifestFile)
+	pattern := filepath.Join(dir, strings.TrimSuffix(constants.DefaultManifestFile, ext)+"-*"+ext)
+
+	manifests, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests: %w", err)
+	}
+
+	runs := make([]Run, 0, len(manifests))
+
+	for _, manifestFile := range manifests {
+		if strings.HasSuffix(manifestFile, constants.SigstoreBundleSuffix) {
+			continue
+		}
+
+		data, err := os.ReadFile(manifestFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+
+		var manifest reports.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil || manifest.SchemaVersion == 0 {
+			continue
+		}
+
+		run := Run{Manifest: manifestFile, Timestamp: manifest.Timestamp}
+
+		candidates := []string{
+			manifestFile,
+			manifestFile + constants.SignatureSuffix,
+			manifestFile + constants.SigstoreBundleSuffix,
+		}
+
+		for _, artifact := range manifest.Artifacts {
+			path := filepath.FromSlash(artifact.Path)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+
+			candidates = append(candidates, path)
+		}
+
+		for _, path := range candidates {
+			size, err := pathSize(path)
+			if err != nil {
+				continue
+			}
+
+			run.Paths = append(run.Paths, path)
+			run.Bytes += size
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// pathSize returns the size of a file, or the total size of the files in a directory.
+func pathSize(path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+// Apply the policy to the runs found in dir.
+func (p Policy) Apply(dir string, runs []Run) *Plan {
+	sorted := append([]Run(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+
+	plan := &Plan{Kept: make([]Run, 0), Removed: make([]Run, 0)}
+
+	for i, run := range sorted {
+		if i < p.KeepLast || (p.KeepFor > 0 && p.Now.Sub(run.Timestamp) < p.KeepFor) {
+			plan.Kept = append(plan.Kept, run)
+		} else {
+			plan.Removed = append(plan.Removed, run)
+		}
+	}
+
+	kept := make(map[string]bool)
+
+	for _, run := range plan.Kept {
+		for _, path := range run.Paths {
+			kept[filepath.Clean(path)] = true
+		}
+	}
+
+	for i := range plan.Removed {
+		run := &plan.Removed[i]
+		paths := make([]string, 0, len(run.Paths))
+
+		for _, path := range run.Paths {
+			switch {
+			case kept[filepath.Clean(path)]:
+				continue
+			case !within(dir, path):
+				plan.Skipped = append(plan.Skipped, path)
+
+				continue
+			}
+
+			paths = append(paths, path)
+		}
+
+		run.Paths = paths
+		run.Bytes = 0
+
+		for _, path := range paths {
+			if size, err := pathSize(path); err == nil {
+				run.Bytes += size
+			}
+		}
+
+		plan.Bytes += run.Bytes
+	}
+
+	return plan
+}
+
+// within reports whether path lies inside dir.
+func within(dir, path string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(absDir, absPath)
+
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Remove deletes the paths of the removed runs, each run's manifest last so a run interrupted
+// part way is found again by the next clean.
+func (p *Plan) Remove() error {
+	for _, run := range p.Removed {
+		for i := len(run.Paths) - 1; i >= 0; i-- {
+			if err := os.RemoveAll(run.Paths[i]); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", run.Paths[i], err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Remote removes the removed runs' artifacts from remote storage too, by running a command for
+// each path relative to dir with the placeholder in its arguments replaced by the path, e.g.
+// "aws s3 rm --recursive s3://bucket/reports/{}". The command is not run through a shell.
+type Remote struct {
+	Command []string
+	run     func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// NewRemote parses a remote removal command, which must contain the placeholder.
+func NewRemote(command string) (*Remote, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 || !strings.Contains(command, constants.RemotePathPlaceholder) {
+		return nil, fmt.Errorf("remote removal command must contain %s for the artifact's path", constants.RemotePathPlaceholder)
+	}
+
+	return &Remote{
+		Command: fields,
+		run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, name, args...).CombinedOutput()
+		},
+	}, nil
+}
+
+// Remove runs the command for every path of the plan's removed runs. It stops at the first
+// failure, before the local files are removed.
+func (r *Remote) Remove(dir string, plan *Plan) error {
+	for _, run := range plan.Removed {
+		for _, path := range run.Paths {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %s: %w", path, err)
+			}
+
+			args := make([]string, 0, len(r.Command)-1)
+			for _, arg := range r.Command[1:] {
+				args = append(args, strings.ReplaceAll(arg, constants.RemotePathPlaceholder, filepath.ToSlash(rel)))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), constants.RemoteRemoveTimeout)
+			output, err := r.run(ctx, r.Command[0], args...)
+
+			cancel()
+
+			if err != nil {
+				return fmt.Errorf("remote removal of %s failed: %w: %s", rel, err, strings.TrimSpace(string(output)))
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteText prints the removed runs, one line each, the paths left alone and a summary.
+func WriteText(w io.Writer, plan *Plan, dryRun bool) error {
+	verb, summary := "removed", "Removed"
+	if dryRun {
+		verb, summary = "would remove", "Would remove"
+	}
+
+	for _, run := range plan.Removed {
+		if _, err := fmt.Fprintf(w, "%s  %s  %s (%d paths, %.1f MB)\n", verb, run.Timestamp.UTC().Format(time.RFC3339),
+			filepath.Base(run.Manifest), len(run.Paths), megabytes(run.Bytes)); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range plan.Skipped {
+		if _, err := fmt.Fprintf(w, "skipped  %s (outside the output directory)\n", path); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%s %d runs (%.1f MB), kept %d runs\n", summary, len(plan.Removed), megabytes(plan.Bytes), len(plan.Kept))
+
+	return err
+}
+
+// megabytes converts a size in bytes.
+func megabytes(bytes int64) float64 {
+	return float64(bytes) / (1 << 20)
+}
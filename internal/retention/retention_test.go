@@ -0,0 +1,146 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+// writeRun writes a run's artifacts and its manifest into dir, as a run started at at would.
+func writeRun(t *testing.T, dir string, at time.Time, outside string) {
+	t.Helper()
+
+	stamp := at.Format("2006-01-02_15-04-05")
+	report := "peer-score-report-delegated-" + stamp + ".json"
+	shards := "peer-score-report-delegated-" + stamp + "-shards"
+
+	files := map[string]string{
+		report:                            "{}",
+		filepath.Join(shards, "index.js"): "window.reportShards = {};",
+		constants.DefaultGrafanaFile:      "{}",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	manifest := reports.Manifest{
+		SchemaVersion: reports.ManifestSchemaVersion,
+		Timestamp:     at,
+		Artifacts: []reports.ManifestArtifact{
+			{Kind: reports.ArtifactJSON, Path: report},
+			{Kind: reports.ArtifactShards, Path: shards},
+			{Kind: reports.ArtifactGrafana, Path: constants.DefaultGrafanaFile},
+			{Kind: reports.ArtifactErrors, Path: outside},
+		},
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+
+	manifestFile := filepath.Join(dir, "peer-score-manifest-delegated-"+stamp+".json")
+	if err := os.WriteFile(manifestFile, data, 0o600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	if err := os.WriteFile(manifestFile+constants.SigstoreBundleSuffix, []byte(`{"mediaType":"bundle"}`), 0o600); err != nil {
+		t.Fatalf("failed to write manifest bundle: %v", err)
+	}
+}
+
+func TestClean(t *testing.T) {
+	dir := t.TempDir()
+
+	// An error journal kept elsewhere is not the output directory's to remove
+	outside := filepath.Join(t.TempDir(), constants.DefaultErrorJournalFile)
+	if err := os.WriteFile(outside, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("failed to write error journal: %v", err)
+	}
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -9)
+
+	writeRun(t, dir, old, outside)
+	writeRun(t, dir, now.AddDate(0, 0, -5), filepath.Join(dir, "missing.ndjson"))
+	writeRun(t, dir, now.AddDate(0, 0, -1), filepath.Join(dir, "missing.ndjson"))
+
+	runs, err := FindRuns(dir)
+	if err != nil || len(runs) != 3 {
+		t.Fatalf("Expected 3 runs, got %d (%v)", len(runs), err)
+	}
+
+	if err := (Policy{}).Validate(); err == nil {
+		t.Error("Expected a policy keeping nothing to be rejected")
+	}
+
+	// The newest run, and those of the last week
+	plan := Policy{KeepLast: 1, KeepFor: 7 * 24 * time.Hour, Now: now}.Apply(dir, runs)
+	if len(plan.Kept) != 2 || len(plan.Removed) != 1 || !plan.Removed[0].Timestamp.Equal(old) {
+		t.Fatalf("Expected only the 9 day old run removed, got %+v", plan)
+	}
+
+	removed := plan.Removed[0].Paths
+	if len(removed) != 4 || len(plan.Skipped) != 1 || plan.Skipped[0] != outside {
+		t.Fatalf("Expected the manifest, its bundle, the report and the shards without the shared Grafana file, got %v (skipped %v)", removed, plan.Skipped)
+	}
+
+	remote := &Remote{Command: []string{"rclone", "purge", "reports:bucket/{}"}}
+
+	var calls []string
+
+	remote.run = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+
+		return nil, nil
+	}
+
+	if err := remote.Remove(dir, plan); err != nil || len(calls) != 4 || !strings.HasPrefix(calls[0], "rclone purge reports:bucket/peer-score-manifest-delegated-") {
+		t.Fatalf("Expected a remote removal per path, got %v (%v)", calls, err)
+	}
+
+	if err := plan.Remove(); err != nil {
+		t.Fatalf("Expected the run to be removed, got %v", err)
+	}
+
+	for _, path := range removed {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s removed, stat returned %v", path, err)
+		}
+	}
+
+	for _, path := range []string{filepath.Join(dir, constants.DefaultGrafanaFile), outside} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s kept, stat returned %v", path, err)
+		}
+	}
+
+	if runs, _ := FindRuns(dir); len(runs) != 2 {
+		t.Errorf("Expected 2 runs left, got %d", len(runs))
+	}
+
+	var out bytes.Buffer
+	if err := WriteText(&out, plan, true); err != nil || !strings.HasSuffix(out.String(), "Would remove 1 runs (0.0 MB), kept 2 runs\n") {
+		t.Errorf("Unexpected output %q (%v)", out.String(), err)
+	}
+
+	if _, err := NewRemote("aws s3 rm s3://bucket/reports"); err == nil {
+		t.Error("Expected a remote command without the placeholder to be rejected")
+	}
+}
@@ -0,0 +1,28 @@
+package retention
+
+import "time"
+
+// Policy decides which runs to keep. A run is kept when it is one of the newest KeepLast runs
+// or is younger than KeepFor, so a policy keeping nothing is rejected.
+type Policy struct {
+	KeepLast int
+	KeepFor  time.Duration
+	Now      time.Time
+}
+
+// Run is a run found in the output directory by its manifest.
+type Run struct {
+	Manifest  string    `json:"manifest"`
+	Timestamp time.Time `json:"timestamp"`
+	Paths     []string  `json:"paths"` // The manifest, its signature and the artifacts it lists that are still on disk
+	Bytes     int64     `json:"bytes"`
+}
+
+// Plan is the runs a policy keeps and removes. Paths a kept run lists too, such as the
+// untimestamped Grafana file, and paths outside the output directory are never removed.
+type Plan struct {
+	Kept    []Run    `json:"kept"`    // Newest first
+	Removed []Run    `json:"removed"` // Newest first, with the paths to remove
+	Skipped []string `json:"skipped,omitempty"`
+	Bytes   int64    `json:"bytes"` // Freed by removing the paths
+}
@@ -0,0 +1,86 @@
+package scorefeed
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+// New creates the feed for a feed URL. HTTP(S) URLs point at a feed server, see Server.
+func New(feedURL string, timeout time.Duration, logger logrus.FieldLogger) (Feed, error) {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid score feed URL: %w", err)
+	}
+
+	// NATS would suit large fleets, until it is supported they share a feed server
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported score feed scheme %q, expected http or https", parsed.Scheme)
+	}
+
+	return NewHTTPFeed(feedURL, timeout, logger), nil
+}
+
+// BuildSnapshot collects the composite scores of the peers that have score summaries.
+func BuildSnapshot(instance, network string, publishedAt time.Time, peers map[string]*peer.Stats) *Snapshot {
+	snapshot := &Snapshot{
+		Instance:    instance,
+		Network:     network,
+		PublishedAt: publishedAt,
+		Peers:       make([]PeerScore, 0, len(peers)),
+	}
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		mean, _, _, ok := stats.ScoreTotals()
+		if !ok {
+			continue
+		}
+
+		snapshot.Peers = append(snapshot.Peers, PeerScore{PeerID: peerID, ClientType: stats.ClientType, Score: mean})
+	}
+
+	sort.Slice(snapshot.Peers, func(i, j int) bool { return snapshot.Peers[i].PeerID < snapshot.Peers[j].PeerID })
+
+	return snapshot
+}
+
+// OtherScores groups the scores of the snapshots other instances published for network since
+// notBefore by peer ID, and returns the instances they came from. An instance that published
+// several snapshots counts with its latest one.
+func OtherScores(snapshots []Snapshot, self, network string, notBefore time.Time) (map[string][]peer.VantageScore, []string) {
+	latest := make(map[string]Snapshot)
+
+	for _, snapshot := range snapshots {
+		if snapshot.Instance == "" || snapshot.Instance == self || snapshot.Network != network || snapshot.PublishedAt.Before(notBefore) {
+			continue
+		}
+
+		if existing, ok := latest[snapshot.Instance]; !ok || snapshot.PublishedAt.After(existing.PublishedAt) {
+			latest[snapshot.Instance] = snapshot
+		}
+	}
+
+	scores := make(map[string][]peer.VantageScore)
+	instances := make([]string, 0, len(latest))
+
+	for instance, snapshot := range latest {
+		instances = append(instances, instance)
+
+		for _, score := range snapshot.Peers {
+			scores[score.PeerID] = append(scores[score.PeerID], peer.VantageScore{Instance: instance, Score: score.Score})
+		}
+	}
+
+	sort.Strings(instances)
+
+	return scores, instances
+}
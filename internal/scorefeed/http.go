@@ -0,0 +1,95 @@
+package scorefeed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/redact"
+)
+
+// HTTPFeed publishes snapshots by POSTing them to a feed server and reads them with a GET of
+// the same URL.
+type HTTPFeed struct {
+	endpoint   string
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+}
+
+// NewHTTPFeed creates a feed for a feed server's URL. Basic auth credentials may be embedded in it.
+func NewHTTPFeed(endpoint string, timeout time.Duration, logger logrus.FieldLogger) *HTTPFeed {
+	return &HTTPFeed{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger.WithField("component", "score_feed"),
+	}
+}
+
+// Publish sends our snapshot to the feed server.
+func (f *HTTPFeed) Publish(ctx context.Context, snapshot *Snapshot) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal score snapshot: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create score feed request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if _, err := f.do(req); err != nil {
+		return fmt.Errorf("failed to publish score snapshot: %w", err)
+	}
+
+	f.logger.WithFields(logrus.Fields{
+		"endpoint": redact.URL(f.endpoint),
+		"peers":    len(snapshot.Peers),
+	}).Info("Published peer scores to the score feed")
+
+	return nil
+}
+
+// Fetch reads every snapshot the feed server holds.
+func (f *HTTPFeed) Fetch(ctx context.Context) ([]Snapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create score feed request: %w", err)
+	}
+
+	body, err := f.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch score snapshots: %w", err)
+	}
+
+	var snapshots []Snapshot
+	if err := json.Unmarshal(body, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to decode score snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// do sends a request and returns the response body of a successful one.
+func (f *HTTPFeed) do(req *http.Request) ([]byte, error) {
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return nil, fmt.Errorf("score feed returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return io.ReadAll(resp.Body)
+}
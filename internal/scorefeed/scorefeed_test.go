@@ -0,0 +1,79 @@
+package scorefeed
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func TestFeedRoundTrip(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	server := NewServer(time.Hour, logger)
+	server.clock = func() time.Time { return now }
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	feed, err := New(httpServer.URL, time.Second, logger)
+	if err != nil {
+		t.Fatalf("Expected an HTTP feed, got %v", err)
+	}
+
+	peers := map[string]*peer.Stats{
+		"16Uiu2HAmScored": {
+			ClientType:         "lighthouse",
+			ConnectionSessions: []peer.ConnectionSession{{ScoreSummary: &peer.SessionScoreSummary{Snapshots: 2, ScoredSeconds: 30, TimeWeightedMean: -3}}},
+		},
+		"16Uiu2HAmUnscored": {ClientType: "prysm"},
+	}
+
+	snapshots := []*Snapshot{
+		BuildSnapshot("us-east", "mainnet", now.Add(-2*time.Hour), peers), // Expired on the server
+		BuildSnapshot("eu-west", "mainnet", now.Add(-time.Minute), peers),
+		BuildSnapshot("eu-west", "mainnet", now, peers), // Replaces the earlier one
+		BuildSnapshot("holesky", "holesky", now, peers),
+		BuildSnapshot("self", "mainnet", now, peers),
+	}
+
+	for _, snapshot := range snapshots {
+		if err := feed.Publish(context.Background(), snapshot); err != nil {
+			t.Fatalf("Expected the snapshot published, got %v", err)
+		}
+	}
+
+	if len(snapshots[1].Peers) != 1 || snapshots[1].Peers[0].Score != -3 {
+		t.Errorf("Expected only the scored peer in the snapshot, got %+v", snapshots[1].Peers)
+	}
+
+	fetched, err := feed.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the snapshots fetched, got %v", err)
+	}
+
+	if len(fetched) != 3 {
+		t.Fatalf("Expected the expired snapshot dropped, got %d snapshots", len(fetched))
+	}
+
+	scores, instances := OtherScores(fetched, "self", "mainnet", now.Add(-time.Hour))
+
+	if len(instances) != 1 || instances[0] != "eu-west" {
+		t.Errorf("Expected only the other mainnet instance, got %v", instances)
+	}
+
+	if got := scores["16Uiu2HAmScored"]; len(got) != 1 || got[0].Instance != "eu-west" || got[0].Score != -3 {
+		t.Errorf("Unexpected other scores %+v", got)
+	}
+
+	if _, err := New("nats://feed.example:4222", time.Second, logger); err == nil {
+		t.Error("Expected a NATS feed URL rejected")
+	}
+}
@@ -0,0 +1,96 @@
+package scorefeed
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Server is a minimal feed server holding each instance's latest snapshot in memory. Snapshots
+// older than the maximum age are no longer served.
+type Server struct {
+	maxAge time.Duration
+	clock  func() time.Time
+	logger logrus.FieldLogger
+
+	mu        sync.Mutex
+	snapshots map[string]Snapshot
+}
+
+// NewServer creates a feed server serving snapshots up to maxAge old.
+func NewServer(maxAge time.Duration, logger logrus.FieldLogger) *Server {
+	return &Server{
+		maxAge:    maxAge,
+		clock:     time.Now,
+		logger:    logger.WithField("component", "score_feed_server"),
+		snapshots: make(map[string]Snapshot),
+	}
+}
+
+// ServeHTTP stores a POSTed snapshot or lists the current snapshots on GET.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var snapshot Snapshot
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, constants.ScoreFeedMaxBodyBytes)).Decode(&snapshot); err != nil {
+			http.Error(w, "invalid score snapshot", http.StatusBadRequest)
+
+			return
+		}
+
+		if snapshot.Instance == "" {
+			http.Error(w, "score snapshot has no instance", http.StatusBadRequest)
+
+			return
+		}
+
+		s.mu.Lock()
+		s.snapshots[snapshot.Instance] = snapshot
+		s.mu.Unlock()
+
+		s.logger.WithFields(logrus.Fields{
+			"instance": snapshot.Instance,
+			"network":  snapshot.Network,
+			"peers":    len(snapshot.Peers),
+		}).Info("Stored score snapshot")
+
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(s.current()); err != nil {
+			s.logger.WithError(err).Warn("Failed to write score snapshots")
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// current returns the snapshots within the maximum age, dropping older ones.
+func (s *Server) current() []Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.clock().Add(-s.maxAge)
+	snapshots := make([]Snapshot, 0, len(s.snapshots))
+
+	for instance, snapshot := range s.snapshots {
+		if snapshot.PublishedAt.Before(cutoff) {
+			delete(s.snapshots, instance)
+
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Instance < snapshots[j].Instance })
+
+	return snapshots
+}
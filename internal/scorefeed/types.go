@@ -0,0 +1,30 @@
+// Package scorefeed shares per-peer composite scores between peer-score instances monitoring
+// the same network from different vantage points, so each can tell peers hostile toward it
+// from peers that are bad for everyone.
+package scorefeed
+
+import (
+	"context"
+	"time"
+)
+
+// PeerScore is a peer's composite score, its time-weighted mean score across its sessions.
+type PeerScore struct {
+	PeerID     string  `json:"peer_id"`
+	ClientType string  `json:"client_type"`
+	Score      float64 `json:"score"`
+}
+
+// Snapshot is the composite scores one instance published at the end of a run.
+type Snapshot struct {
+	Instance    string      `json:"instance"`
+	Network     string      `json:"network"`
+	PublishedAt time.Time   `json:"published_at"`
+	Peers       []PeerScore `json:"peers"`
+}
+
+// Feed is a shared feed instances publish their snapshots to and read the others' from.
+type Feed interface {
+	Publish(ctx context.Context, snapshot *Snapshot) error
+	Fetch(ctx context.Context) ([]Snapshot, error)
+}
@@ -0,0 +1,299 @@
+// Package search finds the peers and sessions of a saved report that match some criteria,
+// such as the peers that sent a given goodbye code, without loading the report into jq.
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+)
+
+// savedReport is the part of a JSON report the search needs.
+type savedReport struct {
+	Peers map[string]*peer.Stats `json:"peers"`
+}
+
+// LoadPeers reads the peers of a saved report: a JSON report, or the shard directory of a
+// split report.
+func LoadPeers(path string) (map[string]*peer.Stats, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report: %w", err)
+		}
+
+		var report savedReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("failed to parse report: %w", err)
+		}
+
+		if report.Peers == nil {
+			return nil, errors.New("report holds no peers")
+		}
+
+		return report.Peers, nil
+	}
+
+	records, err := reports.ReadDetailShards(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Detail records carry the peer's JSON fields, sessions included, next to derived ones
+	peers := make(map[string]*peer.Stats, len(records))
+
+	for peerID, record := range records {
+		var stats peer.Stats
+		if err := json.Unmarshal(record, &stats); err != nil {
+			return nil, fmt.Errorf("failed to parse peer %s: %w", peerID, err)
+		}
+
+		peers[peerID] = &stats
+	}
+
+	return peers, nil
+}
+
+// Search returns the sessions matching the criteria, ordered by peer ID and session.
+func Search(peers map[string]*peer.Stats, criteria Criteria) []Match {
+	matches := make([]Match, 0)
+
+	for peerID, stats := range peers {
+		if stats == nil {
+			continue
+		}
+
+		if criteria.Client != "" && !strings.EqualFold(stats.ClientType, criteria.Client) {
+			continue
+		}
+
+		for i := range stats.ConnectionSessions {
+			session := &stats.ConnectionSessions[i]
+
+			context, ok := matchSession(session, criteria)
+			if !ok {
+				continue
+			}
+
+			matches = append(matches, Match{
+				PeerID:         peerID,
+				ClientType:     stats.ClientType,
+				Session:        i,
+				ConnectedAt:    session.ConnectedAt,
+				DisconnectedAt: session.DisconnectedAt,
+				Context:        context,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].PeerID != matches[j].PeerID {
+			return matches[i].PeerID < matches[j].PeerID
+		}
+
+		return matches[i].Session < matches[j].Session
+	})
+
+	return matches
+}
+
+// matchSession checks the session criteria, returning what matched as context.
+func matchSession(session *peer.ConnectionSession, criteria Criteria) ([]string, bool) {
+	var context []string
+
+	if criteria.GoodbyeCode != nil {
+		found := false
+
+		for _, goodbye := range session.GoodbyeEvents {
+			if goodbye.Code != *criteria.GoodbyeCode {
+				continue
+			}
+
+			found = true
+
+			context = append(context, fmt.Sprintf("goodbye %d %q at %s", goodbye.Code, strings.TrimSpace(goodbye.Reason), timestamp(goodbye.Timestamp)))
+		}
+
+		if !found {
+			return nil, false
+		}
+	}
+
+	if criteria.ScoreBelow != nil {
+		var lowest *peer.PeerScoreSnapshot
+
+		for j := range session.PeerScores {
+			if lowest == nil || session.PeerScores[j].Score < lowest.Score {
+				lowest = &session.PeerScores[j]
+			}
+		}
+
+		if lowest == nil || lowest.Score >= *criteria.ScoreBelow {
+			return nil, false
+		}
+
+		context = append(context, fmt.Sprintf("lowest score %.2f at %s", lowest.Score, timestamp(lowest.Timestamp)))
+	}
+
+	if criteria.Topic != "" {
+		topicContext, ok := matchTopic(session, criteria.Topic)
+		if !ok {
+			return nil, false
+		}
+
+		context = append(context, topicContext...)
+	}
+
+	return context, true
+}
+
+// matchTopic finds the topics containing the search term in the session's mesh events and
+// topic scores. A session with score snapshots whose topic scores cannot be read matches too,
+// as the topic cannot be ruled out, with the unreadable snapshots in its context.
+func matchTopic(session *peer.ConnectionSession, term string) ([]string, bool) {
+	meshEvents := make(map[string]map[string]int)
+	invalid := make(map[string]float64)
+	scored := make(map[string]bool)
+	unreadable := 0
+
+	for _, event := range session.MeshEvents {
+		if !strings.Contains(event.Topic, term) {
+			continue
+		}
+
+		if meshEvents[event.Topic] == nil {
+			meshEvents[event.Topic] = make(map[string]int)
+		}
+
+		meshEvents[event.Topic][event.Type]++
+	}
+
+	for j := range session.PeerScores {
+		topics, err := session.PeerScores[j].TopicScores()
+		if err != nil {
+			unreadable++
+
+			continue
+		}
+
+		for _, topic := range topics {
+			if !strings.Contains(topic.Topic, term) {
+				continue
+			}
+
+			scored[topic.Topic] = true
+			invalid[topic.Topic] = max(invalid[topic.Topic], topic.InvalidMessageDeliveries)
+		}
+	}
+
+	topics := make([]string, 0, len(scored)+len(meshEvents))
+
+	for topic := range scored {
+		topics = append(topics, topic)
+	}
+
+	for topic := range meshEvents {
+		if !scored[topic] {
+			topics = append(topics, topic)
+		}
+	}
+
+	if len(topics) == 0 && unreadable == 0 {
+		return nil, false
+	}
+
+	sort.Strings(topics)
+
+	context := make([]string, 0, len(topics)+1)
+
+	for _, topic := range topics {
+		parts := make([]string, 0, 2)
+
+		if events := meshEvents[topic]; len(events) > 0 {
+			types := make([]string, 0, len(events))
+			for eventType, count := range events {
+				types = append(types, fmt.Sprintf("%s x%d", eventType, count))
+			}
+
+			sort.Strings(types)
+			parts = append(parts, "mesh "+strings.Join(types, ", "))
+		}
+
+		if scored[topic] {
+			parts = append(parts, fmt.Sprintf("scored, invalid deliveries up to %.0f", invalid[topic]))
+		}
+
+		context = append(context, fmt.Sprintf("topic %s: %s", topic, strings.Join(parts, "; ")))
+	}
+
+	if unreadable > 0 {
+		context = append(context, fmt.Sprintf("topic scores unreadable in %d of %d score snapshots", unreadable, len(session.PeerScores)))
+	}
+
+	return context, true
+}
+
+// WriteText prints the matches, one line per session with its context indented below, and
+// a closing count.
+func WriteText(w io.Writer, matches []Match) error {
+	peers := make(map[string]bool)
+
+	for _, match := range matches {
+		peers[match.PeerID] = true
+
+		ended := "open at the end"
+		if match.DisconnectedAt != nil {
+			ended = timestamp(*match.DisconnectedAt)
+		}
+
+		connected := "unknown"
+		if match.ConnectedAt != nil {
+			connected = timestamp(*match.ConnectedAt)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s  %s  session %d  %s - %s\n", match.PeerID, match.ClientType, match.Session, connected, ended); err != nil {
+			return err
+		}
+
+		for _, line := range match.Context {
+			if _, err := fmt.Fprintf(w, "    %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d sessions of %d peers matched\n", len(matches), len(peers))
+
+	return err
+}
+
+// PeerIDs returns the distinct peers of the matches, in order.
+func PeerIDs(matches []Match) []string {
+	peerIDs := make([]string, 0)
+
+	for i, match := range matches {
+		if i == 0 || matches[i-1].PeerID != match.PeerID {
+			peerIDs = append(peerIDs, match.PeerID)
+		}
+	}
+
+	return peerIDs
+}
+
+// timestamp formats an event time for the output.
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
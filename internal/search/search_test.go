@@ -0,0 +1,144 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/internal/peer"
+)
+
+func testPeers() map[string]*peer.Stats {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := at.Add(time.Minute)
+
+	return map[string]*peer.Stats{
+		"16Uiu2A": {
+			ClientType: "lighthouse",
+			ConnectionSessions: []peer.ConnectionSession{
+				{
+					ConnectedAt:    &at,
+					DisconnectedAt: &later,
+					Disconnected:   true,
+					GoodbyeEvents:  []peer.GoodbyeEvent{{Timestamp: later, Code: 129, Reason: "client has too many peers"}},
+					PeerScores:     []peer.PeerScoreSnapshot{{Timestamp: at, Score: 2}},
+				},
+				{
+					ConnectedAt: &later,
+					PeerScores: []peer.PeerScoreSnapshot{{
+						Timestamp: later,
+						Score:     -20,
+						Topics:    []peer.TopicScore{{Topic: "/eth2/abcd/beacon_block/ssz_snappy", InvalidMessageDeliveries: 3}},
+					}},
+				},
+			},
+		},
+		"16Uiu2B": {
+			ClientType: "Teku",
+			ConnectionSessions: []peer.ConnectionSession{{
+				ConnectedAt:   &at,
+				GoodbyeEvents: []peer.GoodbyeEvent{{Timestamp: later, Code: 3, Reason: "fault"}},
+				MeshEvents:    []peer.MeshEvent{{Timestamp: later, Type: "PRUNE", Topic: "/eth2/abcd/beacon_block/ssz_snappy"}},
+			}},
+		},
+	}
+}
+
+func TestSearch(t *testing.T) {
+	code := uint64(129)
+	below := float64(-10)
+
+	tests := []struct {
+		name     string
+		criteria Criteria
+		want     []string // Peer ID and session of each match
+	}{
+		{name: "goodbye code", criteria: Criteria{GoodbyeCode: &code}, want: []string{"16Uiu2A/0"}},
+		{name: "client is case insensitive", criteria: Criteria{Client: "teku"}, want: []string{"16Uiu2B/0"}},
+		{name: "score below", criteria: Criteria{ScoreBelow: &below}, want: []string{"16Uiu2A/1"}},
+		{name: "topic", criteria: Criteria{Topic: "beacon_block"}, want: []string{"16Uiu2A/1", "16Uiu2B/0"}},
+		{name: "criteria combine", criteria: Criteria{Topic: "beacon_block", Client: "lighthouse"}, want: []string{"16Uiu2A/1"}},
+		{name: "no match", criteria: Criteria{GoodbyeCode: &code, Client: "teku"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := Search(testPeers(), tt.criteria)
+
+			got := make([]string, 0, len(matches))
+			for _, match := range matches {
+				got = append(got, match.PeerID+"/"+string(rune('0'+match.Session)))
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	matches := Search(testPeers(), Criteria{GoodbyeCode: &code})
+	if len(matches) != 1 || len(matches[0].Context) != 1 || !strings.Contains(matches[0].Context[0], "client has too many peers") {
+		t.Fatalf("Expected the goodbye as context, got %+v", matches)
+	}
+
+	var out bytes.Buffer
+	if err := WriteText(&out, matches); err != nil {
+		t.Fatalf("Expected the matches to print, got %v", err)
+	}
+
+	if !strings.HasPrefix(out.String(), "16Uiu2A  lighthouse  session 0") || !strings.HasSuffix(out.String(), "1 sessions of 1 peers matched\n") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	if ids := PeerIDs(Search(testPeers(), Criteria{Topic: "beacon_block"})); len(ids) != 2 {
+		t.Errorf("Expected 2 distinct peers, got %v", ids)
+	}
+}
+
+func TestLoadPeers(t *testing.T) {
+	dir := t.TempDir()
+
+	data, err := json.Marshal(map[string]interface{}{"peers": testPeers()})
+	if err != nil {
+		t.Fatalf("failed to marshal report: %v", err)
+	}
+
+	reportFile := filepath.Join(dir, "report.json")
+	if err := os.WriteFile(reportFile, data, 0o600); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	peers, err := LoadPeers(reportFile)
+	if err != nil || len(peers) != 2 {
+		t.Fatalf("Expected 2 peers from the JSON report, got %d (%v)", len(peers), err)
+	}
+
+	// A detail shard as the split report writes it
+	details, err := json.Marshal(map[string]interface{}{"16Uiu2B": testPeers()["16Uiu2B"]})
+	if err != nil {
+		t.Fatalf("failed to marshal shard: %v", err)
+	}
+
+	shardDir := filepath.Join(dir, "report-data-shards")
+	if err := os.Mkdir(shardDir, 0o700); err != nil {
+		t.Fatalf("failed to create shard directory: %v", err)
+	}
+
+	shard := "window.reportShards = window.reportShards || {};\nwindow.reportShards[\"details-0\"] = " + string(details) + ";\n"
+	if err := os.WriteFile(filepath.Join(shardDir, "details-0.js"), []byte(shard), 0o600); err != nil {
+		t.Fatalf("failed to write shard: %v", err)
+	}
+
+	peers, err = LoadPeers(shardDir)
+	if err != nil || len(peers) != 1 || peers["16Uiu2B"].ClientType != "Teku" {
+		t.Fatalf("Expected the shard's peer, got %+v (%v)", peers, err)
+	}
+
+	if _, err := LoadPeers(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected an error for a missing report")
+	}
+}
@@ -0,0 +1,27 @@
+package search
+
+import "time"
+
+// Criteria selects the sessions of a saved report. Every criterion set must hold for a
+// session to match, unset ones match anything.
+type Criteria struct {
+	GoodbyeCode *uint64  // The peer sent a goodbye with this code
+	Client      string   // The peer's client type, case insensitive
+	ScoreBelow  *float64 // A score snapshot fell below this
+	Topic       string   // A topic score or mesh event names a topic containing this
+}
+
+// Empty reports whether no criterion is set.
+func (c Criteria) Empty() bool {
+	return c.GoodbyeCode == nil && c.Client == "" && c.ScoreBelow == nil && c.Topic == ""
+}
+
+// Match is a session that met the criteria.
+type Match struct {
+	PeerID         string     `json:"peer_id"`
+	ClientType     string     `json:"client_type"`
+	Session        int        `json:"session"` // Index in the peer's sessions, from 0
+	ConnectedAt    *time.Time `json:"connected_at"`
+	DisconnectedAt *time.Time `json:"disconnected_at"`   // Nil while the session was open at the end
+	Context        []string   `json:"context,omitempty"` // What in the session matched
+}
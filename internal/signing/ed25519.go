@@ -0,0 +1,186 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Signature is the detached signature file of an ed25519-signed artifact. The signature is made
+// over the SHA-256 digest of the artifact.
+type Signature struct {
+	Scheme    string    `json:"scheme"`
+	Artifact  string    `json:"artifact"`  // Base name of the signed file
+	SHA256    string    `json:"sha256"`    // Hex digest of the signed file
+	KeyID     string    `json:"key_id"`    // Hex SHA-256 of the public key, first 16 characters
+	Signature string    `json:"signature"` // Base64 ed25519 signature over the digest
+	SignedAt  time.Time `json:"signed_at"`
+}
+
+// Ed25519Signer signs artifacts with an ed25519 private key.
+type Ed25519Signer struct {
+	key   ed25519.PrivateKey
+	clock func() time.Time
+}
+
+// NewEd25519Signer creates a signer with the PKCS #8 PEM private key in keyFile, as written by
+// `openssl genpkey -algorithm ed25519`.
+func NewEd25519Signer(keyFile string) (*Ed25519Signer, error) {
+	block, err := readPEM(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key is a %T, not an ed25519 key", parsed)
+	}
+
+	return &Ed25519Signer{key: key, clock: time.Now}, nil
+}
+
+// Sign writes the signature of the file at path to path with the signature suffix.
+func (s *Ed25519Signer) Sign(path string) (string, error) {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return "", err
+	}
+
+	signature := Signature{
+		Scheme:    SchemeEd25519,
+		Artifact:  filepath.Base(path),
+		SHA256:    hex.EncodeToString(digest),
+		KeyID:     KeyID(s.key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, digest)),
+		SignedAt:  s.clock().UTC(),
+	}
+
+	data, err := json.MarshalIndent(signature, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal signature: %w", err)
+	}
+
+	signatureFile := path + constants.SignatureSuffix
+	if err := os.WriteFile(signatureFile, data, constants.DefaultFilePermissions); err != nil {
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+
+	return signatureFile, nil
+}
+
+// VerifyEd25519 checks the ed25519 signature next to the file at path against the trusted
+// public key, and that the file was not changed after signing.
+func VerifyEd25519(path string, publicKey ed25519.PublicKey) (*Signature, error) {
+	data, err := os.ReadFile(path + constants.SignatureSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	var signature Signature
+	if err := json.Unmarshal(data, &signature); err != nil {
+		return nil, fmt.Errorf("failed to parse signature: %w", err)
+	}
+
+	if signature.Scheme != SchemeEd25519 {
+		return nil, fmt.Errorf("signature scheme is %q, not %s", signature.Scheme, SchemeEd25519)
+	}
+
+	if keyID := KeyID(publicKey); signature.KeyID != keyID {
+		return nil, fmt.Errorf("signed with key %s, not the trusted key %s", signature.KeyID, keyID)
+	}
+
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if hex.EncodeToString(digest) != signature.SHA256 {
+		return nil, errors.New("file was changed after it was signed")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	if !ed25519.Verify(publicKey, digest, sig) {
+		return nil, errors.New("signature does not match")
+	}
+
+	return &signature, nil
+}
+
+// LoadPublicKey reads a PKIX PEM ed25519 public key, as written by `openssl pkey -pubout`.
+func LoadPublicKey(keyFile string) (ed25519.PublicKey, error) {
+	block, err := readPEM(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is a %T, not an ed25519 key", parsed)
+	}
+
+	return key, nil
+}
+
+// KeyID identifies a public key by the start of its SHA-256 digest.
+func KeyID(publicKey ed25519.PublicKey) string {
+	digest := sha256.Sum256(publicKey)
+
+	return hex.EncodeToString(digest[:])[:16]
+}
+
+// readPEM reads the first PEM block of a file.
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %s", file)
+	}
+
+	return block, nil
+}
+
+// fileDigest returns the SHA-256 digest of a file.
+func fileDigest(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	return hash.Sum(nil), nil
+}
@@ -0,0 +1,98 @@
+// Package signing signs report artifacts and verifies their signatures, so reports published
+// on GitHub Pages or shared elsewhere can be traced back to the CI run that produced them.
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// Signing schemes.
+const (
+	SchemeEd25519  = "ed25519"  // Detached signature made with a private key we hold
+	SchemeSigstore = "sigstore" // Keyless signature bound to the CI's OIDC identity
+)
+
+// Signer signs an artifact, writing a detached signature next to it.
+type Signer interface {
+	// Sign signs the file at path and returns the signature file it wrote.
+	Sign(path string) (string, error)
+}
+
+// ValidScheme reports whether scheme names a signing scheme. The empty scheme turns signing off.
+func ValidScheme(scheme string) bool {
+	switch scheme {
+	case "", SchemeEd25519, SchemeSigstore:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewSigner creates the signer of a scheme. Ed25519 signs with the PEM private key in keyFile,
+// Sigstore signs keyless through the cosign binary.
+func NewSigner(scheme, keyFile string) (Signer, error) {
+	switch scheme {
+	case SchemeEd25519:
+		return NewEd25519Signer(keyFile)
+	case SchemeSigstore:
+		return NewSigstoreSigner(constants.DefaultCosignBinary), nil
+	default:
+		return nil, fmt.Errorf("unknown signing scheme %q", scheme)
+	}
+}
+
+// Verifier checks the signatures of artifacts. The scheme of each artifact is told by the
+// signature file next to it.
+type Verifier struct {
+	PublicKey    ed25519.PublicKey // Trusted key of ed25519 signatures
+	CosignBinary string
+	Identity     string // Regular expression the Sigstore certificate identity must match
+	Issuer       string // OIDC issuer of the Sigstore certificate
+
+	run commandRunner
+}
+
+// Verify checks the signature of the artifact at path and describes the signature on success.
+func (v *Verifier) Verify(ctx context.Context, path string) (string, error) {
+	if _, err := os.Stat(path + constants.SignatureSuffix); err == nil {
+		if v.PublicKey == nil {
+			return "", errors.New("ed25519 signature found, verifying it requires a public key")
+		}
+
+		signature, err := VerifyEd25519(path, v.PublicKey)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s, key %s, signed %s", SchemeEd25519, signature.KeyID, signature.SignedAt.Format("2006-01-02 15:04:05 MST")), nil
+	}
+
+	if _, err := os.Stat(path + constants.SigstoreBundleSuffix); err == nil {
+		if v.Identity == "" {
+			return "", errors.New("sigstore bundle found, verifying it requires a certificate identity")
+		}
+
+		run := v.run
+		if run == nil {
+			run = runCommand
+		}
+
+		if err := verifySigstore(ctx, run, v.CosignBinary, path, v.Identity, v.Issuer); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s, identity matching %s", SchemeSigstore, v.Identity), nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+
+	return "", errors.New("no signature found")
+}
@@ -0,0 +1,181 @@
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeKeys writes a fresh ed25519 key pair as PEM files and returns their paths.
+func writeKeys(t *testing.T, dir string) (privateFile, publicFile string) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("Failed to marshal private key: %v", err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+
+	privateFile = filepath.Join(dir, "signing.pem")
+	publicFile = filepath.Join(dir, "signing.pub.pem")
+
+	for file, block := range map[string]*pem.Block{
+		privateFile: {Type: "PRIVATE KEY", Bytes: privateDER},
+		publicFile:  {Type: "PUBLIC KEY", Bytes: publicDER},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatalf("Failed to write key: %v", err)
+		}
+	}
+
+	return privateFile, publicFile
+}
+
+func TestEd25519SignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	privateFile, publicFile := writeKeys(t, dir)
+
+	artifact := filepath.Join(dir, "peer-score-report.json")
+	if err := os.WriteFile(artifact, []byte(`{"total_connections": 10}`), 0o600); err != nil {
+		t.Fatalf("Failed to write artifact: %v", err)
+	}
+
+	signer, err := NewSigner(SchemeEd25519, privateFile)
+	if err != nil {
+		t.Fatalf("Expected no error creating the signer, got %v", err)
+	}
+
+	signatureFile, err := signer.Sign(artifact)
+	if err != nil {
+		t.Fatalf("Expected no error signing, got %v", err)
+	}
+
+	if signatureFile != artifact+".sig" {
+		t.Errorf("Expected the signature next to the artifact, got %s", signatureFile)
+	}
+
+	publicKey, err := LoadPublicKey(publicFile)
+	if err != nil {
+		t.Fatalf("Expected no error loading the public key, got %v", err)
+	}
+
+	verifier := &Verifier{PublicKey: publicKey}
+
+	description, err := verifier.Verify(context.Background(), artifact)
+	if err != nil {
+		t.Fatalf("Expected the signature to verify, got %v", err)
+	}
+
+	if !strings.HasPrefix(description, "ed25519, key "+KeyID(publicKey)) {
+		t.Errorf("Expected the description to name the key, got %s", description)
+	}
+
+	// Another key must not verify
+	_, otherPublicFile := writeKeys(t, t.TempDir())
+
+	otherKey, err := LoadPublicKey(otherPublicFile)
+	if err != nil {
+		t.Fatalf("Expected no error loading the other public key, got %v", err)
+	}
+
+	if _, err := (&Verifier{PublicKey: otherKey}).Verify(context.Background(), artifact); err == nil {
+		t.Error("Expected verification against another key to fail")
+	}
+
+	// Neither must a changed file
+	if err := os.WriteFile(artifact, []byte(`{"total_connections": 1000}`), 0o600); err != nil {
+		t.Fatalf("Failed to change artifact: %v", err)
+	}
+
+	if _, err := verifier.Verify(context.Background(), artifact); err == nil || !strings.Contains(err.Error(), "changed after it was signed") {
+		t.Errorf("Expected a changed file to fail verification, got %v", err)
+	}
+}
+
+func TestVerifyWithoutSignature(t *testing.T) {
+	artifact := filepath.Join(t.TempDir(), "peer-score-manifest.json")
+	if err := os.WriteFile(artifact, []byte(`{}`), 0o600); err != nil {
+		t.Fatalf("Failed to write artifact: %v", err)
+	}
+
+	if _, err := (&Verifier{}).Verify(context.Background(), artifact); err == nil || err.Error() != "no signature found" {
+		t.Errorf("Expected no signature to be found, got %v", err)
+	}
+}
+
+func TestSigstore(t *testing.T) {
+	artifact := filepath.Join(t.TempDir(), "peer-score-report.json")
+
+	var calls [][]string
+
+	run := func(_ context.Context, name string, args ...string) ([]byte, error) {
+		calls = append(calls, append([]string{name}, args...))
+
+		// Stand in for cosign writing the bundle
+		if args[0] == "sign-blob" {
+			return nil, os.WriteFile(args[3], []byte(`{}`), 0o600)
+		}
+
+		return []byte("Verified OK"), nil
+	}
+
+	signer := &SigstoreSigner{binary: "cosign", run: run}
+
+	bundle, err := signer.Sign(artifact)
+	if err != nil {
+		t.Fatalf("Expected no error signing, got %v", err)
+	}
+
+	verifier := &Verifier{Identity: "^https://github.com/ethpandaops/hermes-peer-score/", run: run}
+	if _, err := verifier.Verify(context.Background(), artifact); err != nil {
+		t.Fatalf("Expected the bundle to verify, got %v", err)
+	}
+
+	want := [][]string{
+		{"cosign", "sign-blob", "--yes", "--bundle", bundle, artifact},
+		{"cosign", "verify-blob", "--bundle", bundle,
+			"--certificate-identity-regexp", "^https://github.com/ethpandaops/hermes-peer-score/",
+			"--certificate-oidc-issuer", "https://token.actions.githubusercontent.com", artifact},
+	}
+
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected cosign calls %v, got %v", want, calls)
+	}
+
+	failing := &SigstoreSigner{binary: "cosign", run: func(context.Context, string, ...string) ([]byte, error) {
+		return []byte("no OIDC token"), errors.New("exit status 1")
+	}}
+
+	if _, err := failing.Sign(artifact); err == nil || !strings.Contains(err.Error(), "no OIDC token") {
+		t.Errorf("Expected the cosign output in the error, got %v", err)
+	}
+}
+
+func TestValidScheme(t *testing.T) {
+	for scheme, valid := range map[string]bool{"": true, SchemeEd25519: true, SchemeSigstore: true, "gpg": false} {
+		if ValidScheme(scheme) != valid {
+			t.Errorf("Expected ValidScheme(%q) to be %v", scheme, valid)
+		}
+	}
+
+	if _, err := NewSigner(SchemeEd25519, filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("Expected a missing key file to fail")
+	}
+}
@@ -0,0 +1,68 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ethpandaops/hermes-peer-score/constants"
+)
+
+// commandRunner runs a command and returns its combined output.
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// runCommand runs a command with os/exec.
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// SigstoreSigner signs artifacts keyless with cosign. Cosign exchanges the CI's OIDC token for
+// a short-lived certificate and records the signature in the Rekor transparency log, so no key
+// has to be kept. The bundle it writes holds everything needed to verify offline.
+type SigstoreSigner struct {
+	binary string
+	run    commandRunner
+}
+
+// NewSigstoreSigner creates a keyless signer running the given cosign binary.
+func NewSigstoreSigner(binary string) *SigstoreSigner {
+	return &SigstoreSigner{binary: binary, run: runCommand}
+}
+
+// Sign writes the Sigstore bundle of the file at path to path with the bundle suffix.
+func (s *SigstoreSigner) Sign(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultSigningTimeout)
+	defer cancel()
+
+	bundle := path + constants.SigstoreBundleSuffix
+
+	if output, err := s.run(ctx, s.binary, "sign-blob", "--yes", "--bundle", bundle, path); err != nil {
+		return "", fmt.Errorf("cosign sign-blob failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return bundle, nil
+}
+
+// verifySigstore checks the Sigstore bundle next to the file at path with cosign. The signing
+// certificate must have been issued by issuer to an identity matching the identity expression.
+func verifySigstore(ctx context.Context, run commandRunner, binary, path, identity, issuer string) error {
+	if binary == "" {
+		binary = constants.DefaultCosignBinary
+	}
+
+	if issuer == "" {
+		issuer = constants.GitHubActionsOIDCIssuer
+	}
+
+	output, err := run(ctx, binary, "verify-blob",
+		"--bundle", path+constants.SigstoreBundleSuffix,
+		"--certificate-identity-regexp", identity,
+		"--certificate-oidc-issuer", issuer,
+		path)
+	if err != nil {
+		return fmt.Errorf("cosign verify-blob failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
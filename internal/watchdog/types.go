@@ -0,0 +1,24 @@
+package watchdog
+
+import "time"
+
+// Window is a period no events of any type arrived while the run was active. A wedged node
+// produces an empty dataset rather than an error, so the window is recorded in the report.
+type Window struct {
+	From           time.Time `json:"from"` // Last event before the starvation, or the start of the run
+	To             time.Time `json:"to"`   // First event after it, or the end of the run
+	Seconds        float64   `json:"seconds"`
+	Resolved       bool      `json:"resolved"`        // Events arrived again before the run ended
+	DetectedAt     time.Time `json:"detected_at"`     // When the watchdog noticed
+	Goroutines     int       `json:"goroutines"`      // At detection, a leak or deadlock shows here
+	KnownPeers     int       `json:"known_peers"`     // At detection
+	ConnectedPeers int       `json:"connected_peers"` // Peers with an open session at detection
+	Restarted      bool      `json:"restarted,omitempty"`
+	RestartError   string    `json:"restart_error,omitempty"`
+}
+
+// Diagnostics are the connection counts logged and recorded when starvation is detected.
+type Diagnostics struct {
+	KnownPeers     int
+	ConnectedPeers int
+}
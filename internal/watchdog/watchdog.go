@@ -0,0 +1,178 @@
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Watchdog detects event starvation, a run going without any event for longer than the
+// timeout. It logs diagnostics when it does, optionally restarts the node, and records each
+// starvation window until events arrive again.
+type Watchdog struct {
+	timeout  time.Duration
+	clock    func() time.Time
+	diagnose func() Diagnostics
+	restart  func() error // Nil leaves the node running
+	logger   logrus.FieldLogger
+
+	mu        sync.Mutex
+	lastEvent time.Time
+	windows   []Window
+	open      int // Index of the window events have not yet ended, -1 when none is
+}
+
+// New creates a watchdog for a run starting at start. The run counts as starved once no event
+// has arrived for timeout, diagnose reports the connection counts at that moment.
+func New(timeout time.Duration, start time.Time, clock func() time.Time, diagnose func() Diagnostics, logger logrus.FieldLogger) *Watchdog {
+	return &Watchdog{
+		timeout:   timeout,
+		clock:     clock,
+		diagnose:  diagnose,
+		logger:    logger.WithField("component", "watchdog"),
+		lastEvent: start,
+		open:      -1,
+	}
+}
+
+// SetRestart sets how the node is restarted when starvation is detected.
+func (w *Watchdog) SetRestart(restart func() error) {
+	w.restart = restart
+}
+
+// Touch records that an event arrived, ending the open starvation window if there is one.
+func (w *Watchdog) Touch() {
+	now := w.clock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastEvent = now
+
+	if w.open < 0 {
+		return
+	}
+
+	window := &w.windows[w.open]
+	window.To = now
+	window.Seconds = now.Sub(window.From).Seconds()
+	window.Resolved = true
+	w.open = -1
+
+	w.logger.WithFields(logrus.Fields{
+		"starved_for": now.Sub(window.From).Round(time.Second),
+		"restarted":   window.Restarted,
+	}).Info("Events are arriving again after starvation")
+}
+
+// Run checks for starvation several times per timeout until the context is cancelled.
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.timeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Check opens a starvation window when no event has arrived for the timeout, and reports
+// whether it did. A window stays open until the next event, so it is only opened once.
+func (w *Watchdog) Check() bool {
+	now := w.clock()
+
+	w.mu.Lock()
+
+	if w.open >= 0 || now.Sub(w.lastEvent) < w.timeout {
+		w.mu.Unlock()
+
+		return false
+	}
+
+	diagnostics := w.diagnose()
+	window := Window{
+		From:           w.lastEvent,
+		To:             now,
+		Seconds:        now.Sub(w.lastEvent).Seconds(),
+		DetectedAt:     now,
+		Goroutines:     runtime.NumGoroutine(),
+		KnownPeers:     diagnostics.KnownPeers,
+		ConnectedPeers: diagnostics.ConnectedPeers,
+	}
+
+	w.windows = append(w.windows, window)
+	index := len(w.windows) - 1
+	w.open = index
+
+	w.mu.Unlock()
+
+	w.logger.WithFields(logrus.Fields{
+		"last_event":      window.From,
+		"timeout":         w.timeout,
+		"goroutines":      window.Goroutines,
+		"known_peers":     window.KnownPeers,
+		"connected_peers": window.ConnectedPeers,
+	}).Warn("No events arrived within the starvation timeout, the node is probably wedged")
+
+	w.logger.Warn("Goroutine dump at starvation:\n" + goroutineDump())
+
+	// Restarting takes a while, events that arrive meanwhile still end the window
+	if w.restart != nil {
+		w.logger.Warn("Restarting Hermes after event starvation")
+
+		err := w.restart()
+		if err != nil {
+			w.logger.WithError(err).Error("Failed to restart Hermes after event starvation")
+		}
+
+		w.mu.Lock()
+
+		if err != nil {
+			w.windows[index].RestartError = err.Error()
+		} else {
+			w.windows[index].Restarted = true
+		}
+
+		w.mu.Unlock()
+	}
+
+	return true
+}
+
+// Finish ends the open starvation window at the end of the run and returns every window
+// recorded, in the order they started.
+func (w *Watchdog) Finish() []Window {
+	now := w.clock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.open >= 0 {
+		window := &w.windows[w.open]
+		window.To = now
+		window.Seconds = now.Sub(window.From).Seconds()
+		w.open = -1
+	}
+
+	return append([]Window(nil), w.windows...)
+}
+
+// goroutineDump returns the stacks of all goroutines, grouped by identical stacks.
+func goroutineDump() string {
+	var buf bytes.Buffer
+
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return "unavailable: " + err.Error()
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,90 @@
+package watchdog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestWatchdog(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+	diagnose := func() Diagnostics { return Diagnostics{KnownPeers: 40, ConnectedPeers: 12} }
+
+	restarts := 0
+	w := New(5*time.Minute, start, clock, diagnose, logger)
+	w.SetRestart(func() error {
+		restarts++
+
+		return nil
+	})
+
+	// Events keep arriving within the timeout
+	now = start.Add(4 * time.Minute)
+	w.Touch()
+
+	now = start.Add(8 * time.Minute)
+	if w.Check() {
+		t.Fatal("Check() reported starvation 4 minutes after the last event")
+	}
+
+	// Nothing for the timeout opens a window, once
+	now = start.Add(9 * time.Minute)
+	if !w.Check() {
+		t.Fatal("Check() did not report starvation 5 minutes after the last event")
+	}
+
+	now = start.Add(12 * time.Minute)
+	if w.Check() {
+		t.Error("Check() opened a second window while the first was still open")
+	}
+
+	if restarts != 1 {
+		t.Errorf("restarts = %d, want 1", restarts)
+	}
+
+	// The next event ends it
+	now = start.Add(14 * time.Minute)
+	w.Touch()
+
+	// A second starvation lasts until the end of the run, and its restart fails
+	w.SetRestart(func() error { return errors.New("address already in use") })
+
+	now = start.Add(20 * time.Minute)
+	w.Check()
+
+	now = start.Add(25 * time.Minute)
+	windows := w.Finish()
+
+	if len(windows) != 2 {
+		t.Fatalf("got %d windows, want 2", len(windows))
+	}
+
+	first := windows[0]
+	if !first.From.Equal(start.Add(4*time.Minute)) || !first.To.Equal(start.Add(14*time.Minute)) || first.Seconds != 600 {
+		t.Errorf("first window = %s to %s (%.0fs), want 12:04 to 12:14 (600s)", first.From, first.To, first.Seconds)
+	}
+
+	if !first.Resolved || !first.Restarted || first.RestartError != "" {
+		t.Errorf("first window resolved=%v restarted=%v error=%q, want resolved and restarted", first.Resolved, first.Restarted, first.RestartError)
+	}
+
+	if first.KnownPeers != 40 || first.ConnectedPeers != 12 || first.Goroutines == 0 {
+		t.Errorf("first window diagnostics = %d known, %d connected, %d goroutines", first.KnownPeers, first.ConnectedPeers, first.Goroutines)
+	}
+
+	second := windows[1]
+	if second.Resolved || second.Restarted || second.RestartError != "address already in use" {
+		t.Errorf("second window resolved=%v restarted=%v error=%q, want unresolved with the restart error", second.Resolved, second.Restarted, second.RestartError)
+	}
+
+	if !second.To.Equal(start.Add(25*time.Minute)) || second.Seconds != 660 {
+		t.Errorf("second window ends %s (%.0fs), want the end of the run (660s)", second.To, second.Seconds)
+	}
+}
@@ -1,36 +1,173 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/hermes-peer-score/constants"
 	"github.com/ethpandaops/hermes-peer-score/internal/cli"
 	"github.com/ethpandaops/hermes-peer-score/internal/config"
+	"github.com/ethpandaops/hermes-peer-score/internal/peerexport"
+	"github.com/ethpandaops/hermes-peer-score/internal/prune"
+	"github.com/ethpandaops/hermes-peer-score/internal/quickstart"
+	"github.com/ethpandaops/hermes-peer-score/internal/reports"
+	"github.com/ethpandaops/hermes-peer-score/internal/retention"
+	"github.com/ethpandaops/hermes-peer-score/internal/search"
+	"github.com/ethpandaops/hermes-peer-score/internal/signing"
 )
 
 // Command-line flags.
 var (
+	configFile      = flag.String("config", "", "YAML config file of flag settings, as written by the init command (flags given on the command line take precedence)")
+	privateKeyFile  = flag.String("private-key-file", "", "File holding a hex-encoded libp2p private key, overrides "+constants.PrivateKeyEnv)
 	duration        = flag.Duration("duration", constants.DefaultTestDuration, "Test duration for peer scoring")
+	smoke           = flag.Bool("smoke", false, "Smoke test for PR-level CI: a short run without AI analysis, writing only the lite artifacts and failing unless a peer, an event and the report were seen")
+	warmup          = flag.Duration("warmup", 0, "Warmup period before the measurement window, excluded from headline statistics")
+	cooldown        = flag.Duration("cooldown", 0, "Cooldown period after the measurement window, new sessions are not counted")
 	prysmHost       = flag.String("prysm-host", "", "Prysm host connection string (required for both validation modes)")
 	prysmHTTPPort   = flag.Int("prysm-http-port", constants.DefaultPrysmHTTPPort, "Prysm HTTP port")
 	prysmGRPCPort   = flag.Int("prysm-grpc-port", constants.DefaultPrysmGRPCPort, "Prysm gRPC port")
 	securePrysm     = flag.Bool("secure-prysm", false, "Use HTTPS/TLS for Prysm connections")
 	network         = flag.String("network", "mainnet", "Ethereum network (mainnet, sepolia, holesky, devnet, etc.)")
+	maxPeers        = flag.Int("max-peers", constants.DefaultMaxPeers, "Maximum number of peers Hermes connects to")
+	maxPeersRamp    = flag.String("max-peers-ramp", "", "Step the primary host's MaxPeers through these values during the run, restarting Hermes at each step, as value,... (e.g. 50,100,200; overrides --max-peers)")
+	maxPeersStep    = flag.Duration("max-peers-ramp-step", constants.DefaultMaxPeersRampStep, "Duration of each --max-peers-ramp step, the last step lasts until the run ends")
+	capacityRatio   = flag.Float64("capacity-ratio", constants.DefaultCapacityRatio, "Share of --max-peers at which the node counts as at capacity, sessions ending without a goodbye from then on are attributed to our own limit")
+	agentVersion    = flag.String("agent-version", constants.DefaultAgentVersion, "Agent version string advertised to peers and recorded in the report, the pinned Hermes version only supports \"hermes\"")
 	devnetApacheURL = flag.String("devnet-apache-url", "", "Apache URL for devnet configuration files (required when network=devnet)")
 	validationMode  = flag.String("validation-mode", string(config.ValidationModeDelegated), "Validation mode: 'delegated' (delegates validation to Prysm) or 'independent' (uses Prysm for beacon data, validates internally)")
 	htmlOnly        = flag.Bool("html-only", false, "Generate HTML report from existing JSON file without running peer score test")
 	inputJSON       = flag.String("input-json", constants.DefaultJSONReportFile, "Input JSON file for HTML-only mode")
 	claudeAPIKey    = flag.String("openrouter-api-key", "", "OpenRouter API key for AI analysis (can also be set via OPENROUTER_API_KEY env var)")
 	skipAI          = flag.Bool("skip-ai", false, "Skip AI analysis even if API key is available")
+	aiQueueDir      = flag.String("ai-queue-dir", "", "Lock directory shared by concurrent runs to queue their AI requests, e.g. on a CI runner (empty disables the queue)")
+	aiConcurrency   = flag.Int("ai-concurrency", constants.DefaultAIConcurrency, "Runs sharing --ai-queue-dir that may call the AI API at once")
+	aiQueueTimeout  = flag.Duration("ai-queue-timeout", constants.DefaultAIQueueTimeout, "Longest a run waits in the AI queue before it skips the analysis and marks it deferred")
+	aiBaseURL       = flag.String("ai-base-url", constants.DefaultAIBaseURL, "OpenAI-compatible API base URL AI analysis is requested from, e.g. a gateway's")
+	aiProxy         = flag.String("ai-proxy", "", "HTTP(S) proxy AI requests go through (empty uses HTTPS_PROXY and HTTP_PROXY)")
+	aiCABundle      = flag.String("ai-ca-bundle", "", "PEM file of CAs trusted for AI requests on top of the system's, e.g. a TLS-intercepting proxy's")
+	templateDir     = flag.String("template-dir", "", "Directory of report templates (report.html, swimlanes.html, hermes_regression.html, peer.html, tailwind.css) replacing the embedded ones")
+	aiTimeout       = flag.Duration("ai-timeout", constants.DefaultAIRequestTimeout, "Longest an AI request may take, response included")
 	updateGoMod     = flag.Bool("update-go-mod", false, "Update go.mod for the specified validation mode and exit")
 	validateGoMod   = flag.Bool("validate-go-mod", false, "Validate go.mod configuration for the specified validation mode and exit")
+	pruneFields     = flag.String("prune-fields", "", "Comma-separated JSON paths dropped from every report artifact, e.g. client_agent or peers.*.address (\"*\" matches any key)")
+	hashFields      = flag.String("hash-fields", "", "Comma-separated JSON paths whose values are hashed in every report artifact, keyed with $"+constants.PruneHashSaltEnv+" when set")
+	splitReport     = flag.Bool("split-report", false, "Split HTML report data into pre-sorted, pre-paginated index shards (recommended for very large runs)")
+	publishURL      = flag.String("publish-url", "", "Vector/HTTP ingest endpoint to POST summary metrics to after the run (can also be set via PUBLISH_URL env var)")
+	retryWindow     = flag.Duration("handshake-retry-window", constants.DefaultHandshakeRetryWindow, "Reconnects within this window after a failed handshake count as retries of the same connection episode")
+	lateEventGrace  = flag.Duration("late-event-grace", constants.DefaultLateEventGrace, "Events arriving within this window after a disconnect are assigned to the session that ended, later ones are dropped")
+	shutdownTimeout = flag.Duration("shutdown-timeout", constants.DefaultShutdownTimeout, "How long to wait for Hermes to stop after the run while recording how connected peers react to the teardown (0 skips the shutdown phase)")
+	inclTeardown    = flag.Bool("include-teardown", false, "Count the disconnects and goodbyes of the shutdown phase in the churn, goodbye and duration statistics, they are left out by default")
+	eventBucket     = flag.Duration("event-bucket", constants.DefaultEventBucketWidth, "Width of the time buckets peer event counts are recorded in")
+	burstThreshold  = flag.Int("event-burst-threshold", constants.DefaultEventBurstThreshold, "Events of one type from one peer in one bucket that count as a burst (0 disables burst detection)")
+	sampleRate      = flag.Float64("detail-sample-rate", constants.DefaultDetailSampleRate, "Share of peers whose scores, mesh events and timeline are captured as a random baseline, interesting peers are always captured (1 captures every peer)")
+	sampleSeed      = flag.Int64("detail-sample-seed", 0, "Seed the detail sample is drawn with, the same seed picks the same peers")
+	eventRates      = flag.String("event-sample-rates", "", "Comma-separated event types recorded one in N, as PRUNE=10,GRAFT=10,PEERSCORE=5, with exact counts kept of every event")
+	checkpointFile  = flag.String("checkpoint-file", constants.DefaultCheckpointFile, "File collector state is periodically checkpointed to")
+	checkpointEvery = flag.Duration("checkpoint-interval", constants.DefaultCheckpointInterval, "How often collector state is checkpointed (0 disables checkpoints)")
+	resume          = flag.Bool("resume", false, "Resume an interrupted run from its checkpoint, recording the downtime as a gap")
+	staticPeers     = flag.String("static-peers", "", "Comma-separated ENRs of peers to point the node at, tagged as static in the report and kept out of churn statistics")
+	bootnodes       = flag.String("bootnodes", "", "Comma-separated boot node ENRs replacing the network's, for devnets and private networks (empty keeps the network's boot nodes)")
+	topics          = flag.String("topics", "", "Comma-separated gossip topic names to restrict collection to, e.g. beacon_block,beacon_aggregate_and_proof (subnet topics match by name without the subnet, empty keeps all topics)")
+	hosts           = flag.String("hosts", "", "Run several Hermes hosts in parallel for comparison, as label[:libp2p-port[:devp2p-port]][;name=value...],... (first host is the primary)")
+	baselineJSON    = flag.String("baseline-json", "", "Previous JSON report to compare this run against for regressions")
+	previousRuns    = flag.String("previous-reports", "", "Comma-separated earlier JSON reports or glob patterns, e.g. reports/*.json, whose peer sets are compared with this run's (returning vs new peers)")
+	analyzerSpecs   = flag.String("analyzers", "", "Comma-separated custom analyzers run on the final report, as plugin:<file.so> (Go plugin) or exec:<program> (reads the JSON report on stdin)")
+	regression      = flag.Float64("regression-threshold", constants.DefaultHandshakeRegressionThreshold, "Relative drop in handshake success rate versus the baseline that counts as a regression")
+	alertRepo       = flag.String("alert-github-repo", "", "Open a GitHub issue in this owner/name repository on regressions (token from GITHUB_TOKEN)")
+	artifactURL     = flag.String("artifact-base-url", "", "Public URL the reports are published under, linked from regression issues")
+	libp2pPort      = flag.Int("libp2p-port", 0, "libp2p listen port of the primary host (0 picks a random port)")
+	reachability    = flag.String("reachability-check-url", "", "Dial-back vantage that checks our libp2p port is reachable from the internet (requires a fixed libp2p port)")
+	scoreFeedURL    = flag.String("score-feed-url", "", "Score feed server to share per-peer composite scores with other monitoring nodes through, for the consensus view")
+	scoreFeedName   = flag.String("score-feed-instance", "", "Name this instance publishes its scores to the score feed under (default the host name)")
+	scoreFeedAt     = flag.String("score-feed-serve", "", "Serve a score feed for other instances on this address (e.g. :9401) instead of running a test")
+	reachabilityAt  = flag.String("reachability-serve", "", "Serve as a dial-back vantage for other instances on this address (e.g. :9400) instead of running a test")
+	clockSkew       = flag.Duration("clock-skew-threshold", constants.DefaultClockSkewThreshold, "Offset from the Prysm beacon node's clock, checked at the start and end of the run, that is flagged as clock skew (0 disables the check)")
+	starvation      = flag.Duration("starvation-timeout", constants.DefaultStarvationTimeout, "Log diagnostics and record a starvation window when no events arrive for this long while the run is active (0 disables the watchdog)")
+	restartStarved  = flag.Bool("restart-on-starvation", false, "Restart Hermes when its events stop arriving for --starvation-timeout")
+	spillRSS        = flag.Int("spill-rss-mb", 0, "Resident memory in MiB above which the oldest completed sessions' events are spilled to disk until the report is generated (0 disables spilling)")
+	spillDir        = flag.String("spill-dir", "", "Directory spilled session events are written to (default the system temporary directory)")
+	beaconPeers     = flag.Bool("check-beacon-peers", false, "Cross-check Hermes' peers against the Prysm beacon node's /eth/v1/node/peers at the end of the run")
+	beaconPeersInt  = flag.Duration("beacon-peers-interval", constants.DefaultBeaconPeersInterval, "How often the beacon node's peers are also snapshotted during the run with --check-beacon-peers (0 checks at the end only)")
+	beaconSyncInt   = flag.Duration("beacon-sync-interval", constants.DefaultBeaconSyncInterval, "How often the Prysm beacon node is checked for being synced in delegated mode, score snapshots are not attributed to peers while it is not (0 disables the checks)")
+	canary          = flag.Bool("canary", false, "Connect canary peers with known good and bad gossip behaviour to the primary host and check it scores them as expected (requires a fixed libp2p port)")
+	negotiationLogs = flag.Bool("negotiation-logs", false, "Count connections that fail negotiation from the libp2p upgrader and Hermes dialer logs, taking over the process-wide slog default and go-log output for the run")
+	shardSize       = flag.Int("shard-size", constants.DefaultShardSize, "Number of peers per shard when --split-report is enabled")
+	prettyData      = flag.Bool("pretty-data-file", false, "Indent the HTML report data file for reading (larger file)")
+	dataBudget      = flag.Int("data-file-budget-mb", constants.DefaultDataFileBudgetMB, "Memory budget in MiB for peers encoded at once while writing the HTML report data file")
+	swimlanePeers   = flag.Int("swimlane-peers", constants.DefaultSwimlanePeers, "Number of most churning peers drawn in the swimlane view (0 disables it)")
+	headDivergence  = flag.Uint64("head-divergence-slots", constants.DefaultHeadDivergenceSlots, "Slots a peer's head may trail ours in its status before the peer is reported as diverging")
+	experiment      = flag.Int("validation-experiment", 0, "Alternate validation modes over this many sequential sub-runs and compare per-peer scores and goodbyes (0 disables)")
+	experimentPhase = flag.Duration("experiment-phase", constants.DefaultExperimentPhase, "Duration of each validation experiment sub-run")
+	experimentBins  = flag.String("experiment-binaries", "", "Peer score binary built for each validation mode, as delegated=path,independent=path")
+	experimentDir   = flag.String("experiment-dir", constants.DefaultExperimentDir, "Directory validation experiment sub-runs write their reports to")
+	gossipD         = flag.Int("gossip-d", constants.DefaultGossipD, "Gossipsub mesh degree D, the number of mesh peers kept per topic")
+	gossipDlo       = flag.Int("gossip-dlo", constants.DefaultGossipDlo, "Gossipsub mesh low watermark Dlo, below which peers are grafted")
+	gossipDhi       = flag.Int("gossip-dhi", constants.DefaultGossipDhi, "Gossipsub mesh high watermark Dhi, above which peers are pruned")
+	paramSweep      = flag.String("param-sweep", "", "Run sequential sub-tests of --duration each over values of one mesh degree parameter, as param=value,... (e.g. d=6,8,10)")
+	sweepDir        = flag.String("sweep-dir", constants.DefaultSweepDir, "Directory parameter sweep sub-tests write their reports to")
+	signScheme      = flag.String("sign", "", "Sign the JSON report and run manifest: 'ed25519' with --signing-key, or 'sigstore' for keyless signing with cosign in CI (empty leaves them unsigned)")
+	signingKey      = flag.String("signing-key", "", "PKCS #8 PEM ed25519 private key file reports are signed with when --sign=ed25519")
+	errorJournal    = flag.String("error-journal", constants.DefaultErrorJournalFile, "File the run's warnings and errors are journaled to as JSON lines, whatever the log level (empty disables)")
+	tracePeer       = flag.String("trace-peer", "", "Peer ID whose every event, log line and score change is recorded at full detail into --trace-peer-file, the rest of the run keeps its detail (empty disables)")
+	tracePeerFile   = flag.String("trace-peer-file", constants.DefaultPeerTraceFile, "File the --trace-peer records are written to as JSON lines")
+	statusInterval  = flag.Duration("status-interval", constants.DefaultStatusReportInterval, "How often the run's status is logged and emitted as a progress event, the log line backs off while nothing changes (0 disables status reports)")
+	logSample       = flag.Int("log-sample", constants.DefaultLogSample, "Log one in this many per-peer connection, disconnection and identification events at info level, the rest at debug level (0 logs them all at debug level)")
+	quiet           = flag.Bool("quiet", false, "Log warnings and errors only, the reports, progress events and error journal are unaffected")
+	progressJSON    = flag.String("progress-json", "", "Write progress events as JSON lines for wrapper automation to 'stdout', 'stderr', an inherited file descriptor as 'fd:N', or a file path (empty disables)")
 )
 
+// experimentFlags are not passed on to validation experiment and parameter sweep sub-runs,
+// which get their own validation mode and duration.
+var experimentFlags = map[string]bool{
+	"validation-experiment": true,
+	"experiment-phase":      true,
+	"experiment-binaries":   true,
+	"experiment-dir":        true,
+	"param-sweep":           true,
+	"sweep-dir":             true,
+	"validation-mode":       true,
+	"duration":              true,
+	"resume":                true,
+	"progress-json":         true,
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		if err := runInit(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "init: %v\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		if err := runVerify(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "verify: %v\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		if err := runReport(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "report: %v\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	flag.Parse()
 
 	// Initialize logger
@@ -39,6 +176,21 @@ func main() {
 		FullTimestamp: true,
 	})
 
+	// Flags win over the environment, which wins over the config file
+	if err := config.ApplyEnv(flag.CommandLine); err != nil {
+		logger.Fatalf("Configuration error: %v", err)
+	}
+
+	if *configFile != "" {
+		if err := config.ApplyFile(*configFile, flag.CommandLine); err != nil {
+			logger.Fatalf("Configuration error: %v", err)
+		}
+	}
+
+	if *quiet {
+		logger.SetLevel(logrus.WarnLevel)
+	}
+
 	// Create configuration from flags
 	cfg, err := createConfigFromFlags(logger)
 	if err != nil {
@@ -67,17 +219,197 @@ func createConfigFromFlags(logger logrus.FieldLogger) (*config.DefaultConfig, er
 	// Set configuration values from flags
 	cfg.SetValidationMode(validationModeValue)
 	cfg.SetTestDuration(*duration)
+	cfg.SetSmoke(*smoke)
+
+	// A smoke test is short unless a duration was given explicitly
+	if *smoke && !flagSet("duration") {
+		cfg.SetTestDuration(constants.SmokeTestDuration)
+	}
+
+	cfg.SetWarmupDuration(*warmup)
+	cfg.SetCooldownDuration(*cooldown)
+	cfg.SetHandshakeRetryWindow(*retryWindow)
+	cfg.SetLateEventGrace(*lateEventGrace)
+	cfg.SetShutdownTimeout(*shutdownTimeout)
+	cfg.SetIncludeTeardown(*inclTeardown)
+	cfg.SetEventBucketWidth(*eventBucket)
+	cfg.SetEventBurstThreshold(*burstThreshold)
+	cfg.SetDetailSampleRate(*sampleRate)
+	cfg.SetDetailSampleSeed(*sampleSeed)
+	cfg.SetCheckpointFile(*checkpointFile)
+	cfg.SetCheckpointInterval(*checkpointEvery)
+	cfg.SetResume(*resume)
 	cfg.SetPrysmHost(*prysmHost)
 	cfg.SetPrysmHTTPPort(*prysmHTTPPort)
 	cfg.SetPrysmGRPCPort(*prysmGRPCPort)
 	cfg.SetUseTLS(*securePrysm)
 	cfg.SetNetwork(*network)
 	cfg.SetDevnetApacheURL(*devnetApacheURL)
+	cfg.SetMaxPeers(*maxPeers)
+	cfg.SetCapacityRatio(*capacityRatio)
+
+	ramp, err := config.ParseMaxPeersRamp(*maxPeersRamp)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetMaxPeersRamp(ramp)
+	cfg.SetMaxPeersRampStep(*maxPeersStep)
+	cfg.SetAgentVersion(*agentVersion)
+	cfg.SetMeshDegree(config.MeshDegree{D: *gossipD, Dlo: *gossipDlo, Dhi: *gossipDhi})
+
+	hostSpecs, err := config.ParseHostSpecs(*hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetHosts(hostSpecs)
+
+	statics, err := config.ParseStaticPeers(*staticPeers)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetStaticPeers(statics)
+
+	bootnodeRecords, err := config.ParseBootnodes(*bootnodes)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetBootnodes(bootnodeRecords)
+
+	whitelist, err := config.ParseTopicWhitelist(*topics)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetTopicWhitelist(whitelist)
+
+	previousReports, err := config.ParsePreviousReports(*previousRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetPreviousReports(previousReports)
+
+	eventSampleRates, err := config.ParseEventSampleRates(*eventRates)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetEventSampleRates(eventSampleRates)
+
+	analyzerList, err := config.ParseAnalyzerSpecs(*analyzerSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetAnalyzers(analyzerList)
+
+	dropPaths, err := prune.ParsePaths(*pruneFields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --prune-fields: %w", err)
+	}
+
+	hashPaths, err := prune.ParsePaths(*hashFields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --hash-fields: %w", err)
+	}
+
+	cfg.SetPruneFields(dropPaths)
+	cfg.SetHashFields(hashPaths)
+	cfg.SetPruneHashSalt(os.Getenv(constants.PruneHashSaltEnv))
 	cfg.SetHTMLOnly(*htmlOnly)
 	cfg.SetInputJSON(*inputJSON)
-	cfg.SetSkipAI(*skipAI)
+	cfg.SetSkipAI(*skipAI || *smoke)
+	cfg.SetAIQueueDir(*aiQueueDir)
+	cfg.SetAIConcurrency(*aiConcurrency)
+	cfg.SetAIQueueTimeout(*aiQueueTimeout)
+	cfg.SetAIBaseURL(*aiBaseURL)
+	cfg.SetAIProxy(*aiProxy)
+	cfg.SetAICABundle(*aiCABundle)
+	cfg.SetAITimeout(*aiTimeout)
+	cfg.SetTemplateDir(*templateDir)
 	cfg.SetUpdateGoMod(*updateGoMod)
 	cfg.SetValidateGoMod(*validateGoMod)
+	cfg.SetSplitReport(*splitReport)
+	cfg.SetShardSize(*shardSize)
+	cfg.SetPrettyDataFile(*prettyData)
+	cfg.SetDataFileBudgetMB(*dataBudget)
+	cfg.SetSwimlanePeers(*swimlanePeers)
+	cfg.SetHeadDivergenceSlots(*headDivergence)
+	cfg.SetBaselineJSON(*baselineJSON)
+	cfg.SetRegressionThreshold(*regression)
+	cfg.SetAlertGitHubRepo(*alertRepo)
+	cfg.SetArtifactBaseURL(*artifactURL)
+	cfg.SetLibp2pPort(*libp2pPort)
+	cfg.SetReachabilityCheckURL(*reachability)
+	cfg.SetReachabilityListenAddr(*reachabilityAt)
+	cfg.SetScoreFeedURL(*scoreFeedURL)
+	cfg.SetScoreFeedInstance(*scoreFeedName)
+	cfg.SetScoreFeedListenAddr(*scoreFeedAt)
+	cfg.SetCheckBeaconPeers(*beaconPeers)
+	cfg.SetBeaconPeersInterval(*beaconPeersInt)
+	cfg.SetBeaconSyncInterval(*beaconSyncInt)
+	cfg.SetCanary(*canary)
+	cfg.SetNegotiationLogs(*negotiationLogs)
+	cfg.SetClockSkewThreshold(*clockSkew)
+	cfg.SetStarvationTimeout(*starvation)
+	cfg.SetRestartOnStarvation(*restartStarved)
+	cfg.SetSpillRSSMB(*spillRSS)
+	cfg.SetSpillDir(*spillDir)
+	cfg.SetSignScheme(*signScheme)
+	cfg.SetSigningKeyFile(*signingKey)
+	cfg.SetProgressJSON(*progressJSON)
+	cfg.SetErrorJournal(*errorJournal)
+	cfg.SetTracePeer(*tracePeer)
+	cfg.SetTracePeerFile(*tracePeerFile)
+	cfg.SetStatusInterval(*statusInterval)
+	cfg.SetLogSample(*logSample)
+	cfg.SetQuiet(*quiet)
+
+	// A key file given as a flag wins over the environment, so experiment sub-runs read the same one
+	privateKey := os.Getenv(constants.PrivateKeyEnv)
+	if *privateKeyFile != "" {
+		data, err := os.ReadFile(*privateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key file: %w", err)
+		}
+
+		privateKey = strings.TrimSpace(string(data))
+	}
+
+	cfg.SetPrivateKeyStr(privateKey)
+
+	experimentBinaries, err := config.ParseExperimentBinaries(*experimentBins)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetExperimentPhases(*experiment)
+	cfg.SetExperimentPhaseDuration(*experimentPhase)
+	cfg.SetExperimentBinaries(experimentBinaries)
+	cfg.SetExperimentDir(*experimentDir)
+
+	sweep, err := config.ParseParamSweep(*paramSweep)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SetParamSweep(sweep)
+	cfg.SetSweepDir(*sweepDir)
+
+	// Sub-runs get every other flag that was set explicitly
+	experimentArgs := make([]string, 0)
+
+	flag.Visit(func(f *flag.Flag) {
+		if !experimentFlags[f.Name] {
+			experimentArgs = append(experimentArgs, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+
+	cfg.SetExperimentArgs(experimentArgs)
 
 	// Get API key from flag or environment
 	apiKey := *claudeAPIKey
@@ -87,16 +419,330 @@ func createConfigFromFlags(logger logrus.FieldLogger) (*config.DefaultConfig, er
 
 	cfg.SetClaudeAPIKey(apiKey)
 
+	// Get publish endpoint from flag or environment
+	publishEndpoint := *publishURL
+	if publishEndpoint == "" {
+		publishEndpoint = os.Getenv("PUBLISH_URL")
+	}
+
+	cfg.SetPublishURL(publishEndpoint)
+
 	logger.WithFields(logrus.Fields{
 		"validation_mode": cfg.GetValidationMode(),
 		"test_duration":   cfg.GetTestDuration(),
+		"warmup":          cfg.GetWarmupDuration(),
+		"cooldown":        cfg.GetCooldownDuration(),
 		"html_only":       cfg.IsHTMLOnly(),
+		"agent_version":   cfg.GetAgentVersion(),
 		"prysm_host":      cfg.HostWithRedactedSecrets(),
 	}).Info("Configuration loaded")
 
 	return cfg, nil
 }
 
+// runInit runs the init command, which probes the beacon node and this machine and writes a
+// ready-to-run config file.
+func runInit(args []string) error {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+
+	host := flags.String("prysm-host", "", "Prysm host connection string to probe")
+	httpPort := flags.Int("prysm-http-port", constants.DefaultPrysmHTTPPort, "Prysm HTTP port")
+	grpcPort := flags.Int("prysm-grpc-port", constants.DefaultPrysmGRPCPort, "Prysm gRPC port")
+	networkName := flags.String("network", "", "Ethereum network (detected from the beacon node when empty)")
+	port := flags.Int("libp2p-port", 0, "libp2p listen port (a free port is suggested when 0)")
+	identity := flags.String("private-key-file", "", "File holding the libp2p identity, generated when missing")
+	output := flags.String("output", constants.DefaultConfigFile, "Config file to write")
+	force := flags.Bool("force", false, "Replace an existing config file")
+	yes := flags.Bool("yes", false, "Accept detected and suggested values without prompting")
+	timeout := flags.Duration("probe-timeout", constants.DefaultProbeTimeout, "Timeout of each beacon node probe")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Fail before probing or generating an identity if the config would not be written
+	if _, err := os.Stat(*output); err == nil && !*force {
+		return fmt.Errorf("config file %s already exists, use --force to replace it", *output)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check config file: %w", err)
+	}
+
+	wizard := quickstart.NewWizard(os.Stdin, os.Stdout, quickstart.NewProber(*timeout), quickstart.Options{
+		PrysmHost:      *host,
+		PrysmHTTPPort:  *httpPort,
+		PrysmGRPCPort:  *grpcPort,
+		Network:        *networkName,
+		Libp2pPort:     *port,
+		IdentityFile:   *identity,
+		NonInteractive: *yes,
+	})
+
+	settings, err := wizard.Run(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if err := quickstart.WriteConfig(*output, settings, *force); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %s, start a run with: %s --config %s\n", *output, os.Args[0], *output)
+
+	return nil
+}
+
+// runVerify runs the verify command, which checks the signatures of report artifacts. The
+// scheme of each artifact is told by the signature file next to it.
+func runVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+
+	publicKey := flags.String("public-key", "", "PKIX PEM ed25519 public key ed25519 signatures must verify against")
+	identity := flags.String("certificate-identity-regexp", "", "Regular expression the Sigstore signing identity must match, e.g. the CI workflow URL")
+	issuer := flags.String("certificate-oidc-issuer", constants.GitHubActionsOIDCIssuer, "OIDC issuer of the Sigstore signing certificate")
+	cosign := flags.String("cosign", constants.DefaultCosignBinary, "cosign binary Sigstore bundles are verified with")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return errors.New("no files to verify, usage: verify [flags] file...")
+	}
+
+	verifier := &signing.Verifier{CosignBinary: *cosign, Identity: *identity, Issuer: *issuer}
+
+	if *publicKey != "" {
+		key, err := signing.LoadPublicKey(*publicKey)
+		if err != nil {
+			return err
+		}
+
+		verifier.PublicKey = key
+	}
+
+	failed := 0
+
+	for _, file := range flags.Args() {
+		description, err := verifier.Verify(context.Background(), file)
+		if err != nil {
+			failed++
+
+			fmt.Printf("FAILED    %s: %v\n", file, err)
+
+			continue
+		}
+
+		// A signed manifest vouches for the artifacts it lists only if they still match it
+		if reports.IsManifestFile(file) {
+			artifacts, err := reports.VerifyManifestArtifacts(file)
+			if err != nil {
+				failed++
+
+				fmt.Printf("FAILED    %s: %v\n", file, err)
+
+				continue
+			}
+
+			description = fmt.Sprintf("%s, %d artifacts match", description, artifacts)
+		}
+
+		fmt.Printf("VERIFIED  %s (%s)\n", file, description)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed verification", failed, flags.NArg())
+	}
+
+	return nil
+}
+
+// runReport runs the report commands, which work on the reports of earlier runs.
+func runReport(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "grep":
+			return runReportGrep(args[1:])
+		case "clean":
+			return runReportClean(args[1:])
+		case "peer":
+			return runReportPeer(args[1:])
+		}
+	}
+
+	return errors.New("unknown command, usage: report grep [flags] report.json|shard-dir, report clean [flags] [dir], " +
+		"or report peer [flags] peer-id report.json|shard-dir")
+}
+
+// runReportPeer runs the report peer command, which exports one peer's history from a saved
+// report as a standalone JSON and HTML pair.
+func runReportPeer(args []string) error {
+	flags := flag.NewFlagSet("report peer", flag.ContinueOnError)
+
+	output := flags.String("output", "", "Path of the exported files without their extension (default peer-<peer ID>)")
+	templateDir := flags.String("template-dir", "", "Directory with a peer.html replacing the embedded template")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return errors.New("expected a peer and a report, usage: report peer [flags] peer-id report.json|shard-dir")
+	}
+
+	export, err := peerexport.Load(flags.Arg(1), flags.Arg(0), time.Now())
+	if err != nil {
+		return err
+	}
+
+	base := *output
+	if base == "" {
+		base = peerexport.DefaultBase(export.PeerID)
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	files, err := peerexport.Write(export, base, *templateDir, logger)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		fmt.Printf("Wrote %s\n", file)
+	}
+
+	return nil
+}
+
+// runReportGrep runs the report grep command, which lists the peers and sessions of a saved
+// report matching the given criteria.
+func runReportGrep(args []string) error {
+	flags := flag.NewFlagSet("report grep", flag.ContinueOnError)
+
+	goodbyeCode := flags.Uint64("goodbye-code", 0, "Match sessions in which the peer sent a goodbye with this code")
+	client := flags.String("client", "", "Match peers of this client type, e.g. lighthouse")
+	scoreBelow := flags.Float64("score-below", 0, "Match sessions with a peer score snapshot below this")
+	topic := flags.String("topic", "", "Match sessions with a topic score or mesh event on a topic containing this, e.g. beacon_block")
+	asJSON := flags.Bool("json", false, "Print the matches as JSON")
+	idsOnly := flags.Bool("ids", false, "Print only the matching peer IDs, one per line")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("expected one report, usage: report grep [flags] report.json|shard-dir")
+	}
+
+	criteria := search.Criteria{Client: *client, Topic: *topic}
+
+	// Zero is a valid code and score, so only the flags given are criteria
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "goodbye-code":
+			criteria.GoodbyeCode = goodbyeCode
+		case "score-below":
+			criteria.ScoreBelow = scoreBelow
+		}
+	})
+
+	if criteria.Empty() {
+		return errors.New("no criteria, give at least one of --goodbye-code, --client, --score-below or --topic")
+	}
+
+	peers, err := search.LoadPeers(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	matches := search.Search(peers, criteria)
+
+	switch {
+	case *asJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(matches)
+	case *idsOnly:
+		for _, peerID := range search.PeerIDs(matches) {
+			fmt.Println(peerID)
+		}
+
+		return nil
+	default:
+		return search.WriteText(os.Stdout, matches)
+	}
+}
+
+// runReportClean runs the report clean command, which removes the artifacts of old runs from
+// an output directory, by their manifests.
+func runReportClean(args []string) error {
+	flags := flag.NewFlagSet("report clean", flag.ContinueOnError)
+
+	keepLast := flags.Int("keep-last", 0, "Keep the newest N runs")
+	keepDays := flags.Int("keep-days", 0, "Keep the runs of the last D days")
+	dryRun := flags.Bool("dry-run", false, "List what would be removed without removing it")
+	remoteRm := flags.String("remote-rm", "", "Command removing an artifact from remote storage too, with {} for its path relative to the directory, e.g. 'aws s3 rm --recursive s3://bucket/reports/{}'")
+	asJSON := flags.Bool("json", false, "Print the kept and removed runs as JSON")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 1 {
+		return errors.New("expected at most one directory, usage: report clean [flags] [dir]")
+	}
+
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	policy := retention.Policy{KeepLast: *keepLast, KeepFor: time.Duration(*keepDays) * 24 * time.Hour, Now: time.Now()}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	var remote *retention.Remote
+
+	if *remoteRm != "" {
+		var err error
+
+		if remote, err = retention.NewRemote(*remoteRm); err != nil {
+			return err
+		}
+	}
+
+	runs, err := retention.FindRuns(dir)
+	if err != nil {
+		return err
+	}
+
+	plan := policy.Apply(dir, runs)
+
+	if !*dryRun {
+		// Remote copies go first, so a failure leaves the local files to retry from
+		if remote != nil {
+			if err := remote.Remove(dir, plan); err != nil {
+				return err
+			}
+		}
+
+		if err := plan.Remove(); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(plan)
+	}
+
+	return retention.WriteText(os.Stdout, plan, *dryRun)
+}
+
 // parseValidationMode parses and validates the validation mode string.
 func parseValidationMode(mode string) (config.ValidationMode, error) {
 	switch mode {
@@ -108,3 +754,14 @@ func parseValidationMode(mode string) (config.ValidationMode, error) {
 		return "", fmt.Errorf(constants.ErrInvalidValidationMode)
 	}
 }
+
+// flagSet reports whether a flag was set, on the command line, in the environment or in the config file.
+func flagSet(name string) bool {
+	set := false
+
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+
+	return set
+}
@@ -0,0 +1,112 @@
+#!/usr/bin/env python3
+"""
+Compare `go test -bench` output against the benchmark thresholds and fail on a regression.
+
+Usage:
+    bench_gate.py THRESHOLDS BENCH_OUTPUT            check, exit 1 on a regression
+    bench_gate.py THRESHOLDS BENCH_OUTPUT --update   record the output as the new baselines
+
+Unlike the report scripts this one is meant to fail the build. A benchmark run several times
+(-count) is judged by its fastest run, which is the least disturbed by a noisy machine.
+"""
+import json
+import re
+import sys
+
+# Matches a result line such as
+# BenchmarkHandleEvent/peers=1000-8   9   159069012 ns/op   14457 ns/event   38214670 B/op   448314 allocs/op
+RESULT = re.compile(r'^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+(.*)$')
+
+# Metrics that are compared, others such as ns/event are informational.
+GATED = ('ns/op', 'B/op', 'allocs/op')
+
+
+def parse_results(path):
+    """Return the lowest value of every gated metric per benchmark."""
+    results = {}
+
+    with open(path, 'r') as f:
+        for line in f:
+            match = RESULT.match(line.strip())
+            if not match:
+                continue
+
+            fields = match.group(2).split()
+            metrics = results.setdefault(match.group(1), {})
+
+            for value, unit in zip(fields[::2], fields[1::2]):
+                if unit not in GATED:
+                    continue
+
+                value = float(value)
+                metrics[unit] = min(value, metrics.get(unit, value))
+
+    return results
+
+
+def check(thresholds, results):
+    """Print every gated metric against its baseline and return the number of regressions."""
+    tolerance = thresholds.get('tolerance', {})
+    baselines = thresholds.get('benchmarks', {})
+    regressions = 0
+
+    for name in sorted(baselines):
+        if name not in results:
+            print(f'MISSING     {name}: in the thresholds but not in the benchmark output')
+            regressions += 1
+            continue
+
+        for unit, baseline in sorted(baselines[name].items()):
+            current = results[name].get(unit)
+            if current is None:
+                continue
+
+            limit = baseline * (1 + tolerance.get(unit, 0))
+            change = (current / baseline - 1) * 100 if baseline else 0
+            status = 'REGRESSION' if current > limit else 'ok'
+
+            if current > limit:
+                regressions += 1
+
+            print(f'{status:<11} {name} {unit}: {current:.0f} against {baseline:.0f} ({change:+.1f}%, limit {limit:.0f})')
+
+    for name in sorted(set(results) - set(baselines)):
+        print(f'UNTRACKED   {name}: no threshold, run make bench-update to record one')
+
+    return regressions
+
+
+def main():
+    if len(sys.argv) < 3:
+        print(__doc__.strip())
+        sys.exit(2)
+
+    thresholds_file, output_file = sys.argv[1], sys.argv[2]
+
+    with open(thresholds_file, 'r') as f:
+        thresholds = json.load(f)
+
+    results = parse_results(output_file)
+    if not results:
+        print(f'No benchmark results in {output_file}')
+        sys.exit(1)
+
+    if '--update' in sys.argv[3:]:
+        thresholds['benchmarks'] = {name: {unit: round(value) for unit, value in sorted(metrics.items())}
+                                    for name, metrics in sorted(results.items())}
+
+        with open(thresholds_file, 'w') as f:
+            json.dump(thresholds, f, indent=2)
+            f.write('\n')
+
+        print(f'Recorded {len(results)} benchmarks in {thresholds_file}')
+        return
+
+    regressions = check(thresholds, results)
+    if regressions:
+        print(f'{regressions} benchmark metrics regressed beyond their tolerance')
+        sys.exit(1)
+
+
+if __name__ == '__main__':
+    main()
@@ -374,8 +374,13 @@ def generate_index():
     with open('index-template.html', 'r') as f:
         template = f.read()
 
+    # Inline the Tailwind CSS the reports embed, so the index needs no CDN either
+    with open('internal/reports/templates/tailwind.css', 'r') as f:
+        tailwind_css = f.read()
+
     # Simple template replacement
-    html = template.replace('{{.TotalReports}}', str(template_data['total_reports']))
+    html = template.replace('{{.TailwindCSS}}', tailwind_css)
+    html = html.replace('{{.TotalReports}}', str(template_data['total_reports']))
     html = html.replace('{{.LastUpdated}}', template_data['last_updated'])
 
     # Handle latest report section